@@ -0,0 +1,324 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"ApiEscuela/repositories"
+	"ApiEscuela/services"
+	"ApiEscuela/storage"
+
+	"gorm.io/gorm"
+)
+
+// Subcomandos de administración. Usan los mismos repositorios y servicios que el servidor HTTP.
+
+var comandosUsuario = map[string]func(db *gorm.DB, args []string) error{
+	"create-admin":   crearAdmin,
+	"reset-password": restablecerClave,
+	"unlock":         desbloquearUsuario,
+}
+
+var comandosCodigos = map[string]func(db *gorm.DB, args []string) error{
+	"purge-expired": purgarCodigos,
+}
+
+var comandosArchivos = map[string]func(db *gorm.DB, args []string) error{
+	"gc":       recolectarArchivos,
+	"index":    indexarArchivos,
+	"migrate":  migrarArchivos,
+	"variants": generarVariantes,
+}
+
+var comandosComunicados = map[string]func(db *gorm.DB, args []string) error{
+	"resend": reenviarComunicado,
+}
+
+var comandosEstadisticas = map[string]func(db *gorm.DB, args []string) error{
+	"dump": volcarEstadisticas,
+}
+
+// nuevoAdminService arma el servicio de administración sobre la base de datos
+func nuevoAdminService(db *gorm.DB) services.AdminService {
+	usuarioRepo := repositories.NewUsuarioRepository(db)
+	personaRepo := repositories.NewPersonaRepository(db)
+	codigoUsuarioRepo := repositories.NewCodigoUsuarioRepository(db)
+	authService := services.NewAuthService(usuarioRepo, personaRepo, codigoUsuarioRepo)
+	return services.NewAdminService(usuarioRepo, personaRepo, repositories.NewTipoUsuarioRepository(db), codigoUsuarioRepo, authService)
+}
+
+// argumentoUnico parsea las opciones y exige exactamente un argumento posicional
+func argumentoUnico(fs *flag.FlagSet, args []string, nombre string) (string, error) {
+	// Permitir el argumento antes de las opciones: `unlock juan --flag`
+	if len(args) > 0 && len(args[0]) > 0 && args[0][0] != '-' {
+		if err := fs.Parse(args[1:]); err != nil {
+			return "", err
+		}
+		if fs.NArg() > 0 {
+			return "", fmt.Errorf("argumentos de más: %v", fs.Args())
+		}
+		return args[0], nil
+	}
+	if err := fs.Parse(args); err != nil {
+		return "", err
+	}
+	if fs.NArg() != 1 {
+		return "", fmt.Errorf("uso: %s <%s> [opciones]", fs.Name(), nombre)
+	}
+	return fs.Arg(0), nil
+}
+
+// crearAdmin crea un usuario Administrador
+func crearAdmin(db *gorm.DB, args []string) error {
+	fs := flag.NewFlagSet("user create-admin", flag.ContinueOnError)
+	var datos services.NuevoAdmin
+	fs.StringVar(&datos.Usuario, "usuario", "", "nombre de usuario (requerido)")
+	fs.StringVar(&datos.Cedula, "cedula", "", "cédula de la persona (requerida; se reutiliza si ya existe)")
+	fs.StringVar(&datos.Nombre, "nombre", "", "nombre de la persona si hay que crearla")
+	fs.StringVar(&datos.Correo, "correo", "", "correo de la persona si hay que crearla")
+	fs.StringVar(&datos.Clave, "clave", "", "contraseña (se genera una temporal si está vacía)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	usuario, generada, err := nuevoAdminService(db).CreateAdmin(datos)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Administrador %q creado (ID %d)\n", usuario.Usuario, usuario.ID)
+	if generada != "" {
+		fmt.Printf("Contraseña temporal: %s\n", generada)
+	}
+	return nil
+}
+
+// restablecerClave asigna una contraseña nueva que el usuario debe cambiar al ingresar
+func restablecerClave(db *gorm.DB, args []string) error {
+	fs := flag.NewFlagSet("user reset-password", flag.ContinueOnError)
+	clave := fs.String("clave", "", "contraseña nueva (se genera una temporal si está vacía)")
+	username, err := argumentoUnico(fs, args, "usuario")
+	if err != nil {
+		return err
+	}
+
+	generada, err := nuevoAdminService(db).ResetPassword(username, *clave)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Contraseña de %q restablecida; se pedirá cambiarla en el próximo login\n", username)
+	if generada != "" {
+		fmt.Printf("Contraseña temporal: %s\n", generada)
+	}
+	return nil
+}
+
+// desbloquearUsuario restaura un usuario eliminado e invalida sus códigos de recuperación vigentes
+func desbloquearUsuario(db *gorm.DB, args []string) error {
+	fs := flag.NewFlagSet("user unlock", flag.ContinueOnError)
+	username, err := argumentoUnico(fs, args, "usuario")
+	if err != nil {
+		return err
+	}
+
+	result, err := nuevoAdminService(db).UnlockUsuario(username)
+	if err != nil {
+		return err
+	}
+	if result.Restaurado {
+		fmt.Printf("Usuario %q restaurado\n", username)
+	}
+	fmt.Printf("Usuario %q desbloqueado (%d códigos de recuperación invalidados)\n", username, result.CodigosExpirados)
+	return nil
+}
+
+// purgarCodigos elimina los códigos de recuperación que ya no sirven
+func purgarCodigos(db *gorm.DB, args []string) error {
+	fs := flag.NewFlagSet("codes purge-expired", flag.ContinueOnError)
+	antiguedad := fs.Duration("older-than", 24*time.Hour, "conservar los códigos vencidos o usados más recientes que esta duración")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	eliminados, err := nuevoAdminService(db).PurgeExpiredCodes(time.Now().Add(-*antiguedad))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d códigos eliminados\n", eliminados)
+	return nil
+}
+
+// recolectarArchivos lista (o elimina con --delete) los archivos subidos que nadie referencia
+func recolectarArchivos(db *gorm.DB, args []string) error {
+	fs := flag.NewFlagSet("files gc", flag.ContinueOnError)
+	antiguedad := fs.Duration("older-than", 24*time.Hour, "ignorar archivos modificados más recientemente")
+	eliminar := fs.Bool("delete", false, "eliminar los archivos; sin esta opción solo se listan")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	archivos, err := storage.DesdeEntorno()
+	if err != nil {
+		return err
+	}
+	mediaService := services.NewMediaService(repositories.NewMediaRepository(db), archivos)
+	archivoService := services.NewArchivoService(repositories.NewNoticiaRepository(db), repositories.NewComunicadoRepository(db), archivos, mediaService)
+	huerfanos, err := archivoService.FindOrphanFiles(time.Now().Add(-*antiguedad))
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	for _, archivo := range huerfanos {
+		total += archivo.Tamano
+		fmt.Printf("%s\t%d bytes\t%s\n", archivo.Clave, archivo.Tamano, archivo.Modificado.Format(time.RFC3339))
+	}
+	if !*eliminar {
+		fmt.Printf("%d archivos sin referencias (%d bytes). Use --delete para eliminarlos\n", len(huerfanos), total)
+		return nil
+	}
+	eliminados, err := archivoService.RemoveOrphanFiles(huerfanos)
+	fmt.Printf("%d archivos eliminados (%d bytes)\n", eliminados, total)
+	return err
+}
+
+// reenviarComunicado vuelve a enviar por correo un comunicado
+func reenviarComunicado(db *gorm.DB, args []string) error {
+	fs := flag.NewFlagSet("comunicados resend", flag.ContinueOnError)
+	idStr, err := argumentoUnico(fs, args, "id")
+	if err != nil {
+		return err
+	}
+	id, err := strconv.ParseUint(idStr, 10, 32)
+	if err != nil {
+		return fmt.Errorf("ID de comunicado inválido: %s", idStr)
+	}
+
+	archivos, err := storage.DesdeEntorno()
+	if err != nil {
+		return err
+	}
+	comunicadoService := services.NewComunicadoService(
+		repositories.NewComunicadoRepository(db),
+		repositories.NewEstudianteRepository(db),
+		repositories.NewInstitucionRepository(db),
+		archivos,
+		services.NewMediaService(repositories.NewMediaRepository(db), archivos),
+	)
+	result, err := comunicadoService.ResendComunicado(uint(id))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Comunicado %d: %d de %d correos enviados\n", id, result.Enviados, result.Total)
+	if len(result.Errores) > 0 {
+		for _, e := range result.Errores {
+			fmt.Fprintln(os.Stderr, e)
+		}
+		return errors.New("el reenvío tuvo errores")
+	}
+	return nil
+}
+
+// volcarEstadisticas imprime en JSON los totales por tabla y las estadísticas de los repositorios
+func volcarEstadisticas(db *gorm.DB, args []string) error {
+	fs := flag.NewFlagSet("stats dump", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	totales := map[string]int{}
+	contar := func(nombre string, n int, err error) error {
+		if err != nil {
+			return fmt.Errorf("error al contar %s: %v", nombre, err)
+		}
+		totales[nombre] = n
+		return nil
+	}
+	usuarioRepo := repositories.NewUsuarioRepository(db)
+	usuarios, err := usuarioRepo.GetAllUsuarios()
+	if err := contar("usuarios", len(usuarios), err); err != nil {
+		return err
+	}
+	eliminados, err := usuarioRepo.GetDeletedUsuarios()
+	if err := contar("usuarios_eliminados", len(eliminados), err); err != nil {
+		return err
+	}
+	conteos := []struct {
+		nombre string
+		fn     func() (int, error)
+	}{
+		{"provincias", contarCon(repositories.NewProvinciaRepository(db).GetAllProvincias)},
+		{"ciudades", contarCon(repositories.NewCiudadRepository(db).GetAllCiudades)},
+		{"personas", contarCon(repositories.NewPersonaRepository(db).GetAllPersonas)},
+		{"instituciones", contarCon(repositories.NewInstitucionRepository(db).GetAllInstituciones)},
+		{"estudiantes", contarCon(repositories.NewEstudianteRepository(db).GetAllEstudiantes)},
+		{"estudiantes_universitarios", contarCon(repositories.NewEstudianteUniversitarioRepository(db).GetAllEstudiantesUniversitarios)},
+		{"autoridades_uteq", contarCon(repositories.NewAutoridadUTEQRepository(db).GetAllAutoridadesUTEQ)},
+		{"tematicas", contarCon(repositories.NewTematicaRepository(db).GetAllTematicas)},
+		{"actividades", contarCon(repositories.NewActividadRepository(db).GetAllActividades)},
+		{"programas_visita", contarCon(repositories.NewProgramaVisitaRepository(db).GetAllProgramasVisita)},
+		{"dudas", contarCon(repositories.NewDudasRepository(db).GetAllDudas)},
+		{"noticias", contarCon(repositories.NewNoticiaRepository(db).GetAllNoticias)},
+		{"comunicados", contarCon(repositories.NewComunicadoRepository(db).GetAllComunicados)},
+	}
+	for _, c := range conteos {
+		n, err := c.fn()
+		if err := contar(c.nombre, n, err); err != nil {
+			return err
+		}
+	}
+
+	// Usuarios activos por tipo de usuario
+	tipos, err := repositories.NewTipoUsuarioRepository(db).GetAllTiposUsuario()
+	if err != nil {
+		return fmt.Errorf("error al obtener los tipos de usuario: %v", err)
+	}
+	nombresTipo := map[uint]string{}
+	for _, t := range tipos {
+		nombresTipo[t.ID] = t.Nombre
+	}
+	porTipo := map[string]int{}
+	for _, u := range usuarios {
+		nombre, ok := nombresTipo[u.TipoUsuarioID]
+		if !ok {
+			nombre = fmt.Sprintf("tipo_%d", u.TipoUsuarioID)
+		}
+		porTipo[nombre]++
+	}
+
+	asignacion, err := repositories.NewDetalleAutoridadDetallesVisitaRepository(db).GetEstadisticasAsignacion()
+	if err != nil {
+		return fmt.Errorf("error al obtener las estadísticas de asignación: %v", err)
+	}
+	participacion, err := repositories.NewVisitaDetalleEstudiantesUniversitariosRepository(db).GetEstadisticasParticipacion()
+	if err != nil {
+		return fmt.Errorf("error al obtener las estadísticas de participación: %v", err)
+	}
+	actividades, err := repositories.NewVisitaDetalleRepository(db).GetEstadisticasActividades()
+	if err != nil {
+		return fmt.Errorf("error al obtener las estadísticas de actividades: %v", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(map[string]interface{}{
+		"generado":                    time.Now().Format(time.RFC3339),
+		"totales":                     totales,
+		"usuarios_por_tipo":           porTipo,
+		"asignacion_autoridades":      asignacion,
+		"participacion_universitaria": participacion,
+		"actividades_programadas":     actividades,
+	})
+}
+
+// contarCon adapta un método GetAll de un repositorio a un contador
+func contarCon[T any](getAll func() ([]T, error)) func() (int, error) {
+	return func() (int, error) {
+		rows, err := getAll()
+		return len(rows), err
+	}
+}
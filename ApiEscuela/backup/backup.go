@@ -0,0 +1,128 @@
+// Package backup genera y restaura respaldos lógicos de los datos de la aplicación.
+//
+// Un respaldo es un archivo .tar.gz con:
+//
+//	manifest.json          formato, versión, fecha y filas por tabla
+//	data/<tabla>.ndjson    una fila JSON por línea, incluidas las eliminadas lógicamente
+//	assets/...             los archivos subidos (opcional)
+//
+// La restauración valida la integridad referencial antes de escribir. Sobre una base vacía
+// (o con Reemplazar) conserva los IDs; sobre una base con datos los reasigna y reutiliza los
+// registros que ya existen según sus claves naturales (cédula, usuario, nombre de provincia...).
+package backup
+
+import (
+	"ApiEscuela/database"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+const (
+	// Formato identifica los archivos generados por este paquete
+	Formato = "apiescuela-backup"
+	// Version del formato; Restaurar acepta respaldos de esta versión o anteriores
+	Version = 1
+
+	archivoManifiesto = "manifest.json"
+	carpetaDatos      = "data/"
+	carpetaAssets     = "assets/"
+	extensionDatos    = ".ndjson"
+)
+
+// Manifiesto describe el contenido de un respaldo
+type Manifiesto struct {
+	Formato       string            `json:"formato"`
+	Version       int               `json:"version"`
+	Creado        time.Time         `json:"creado"`
+	Tablas        []TablaManifiesto `json:"tablas"`
+	Archivos      int               `json:"archivos"`
+	BytesArchivos int64             `json:"bytes_archivos"`
+}
+
+// TablaManifiesto es la cantidad de filas respaldadas de una tabla
+type TablaManifiesto struct {
+	Nombre string `json:"nombre"`
+	Filas  int    `json:"filas"`
+}
+
+// tabla describe cómo respaldar y restaurar un modelo
+type tabla struct {
+	schema      *schema.Schema
+	referencias []referencia
+	claves      [][]string // columnas que identifican un registro existente al fusionar
+}
+
+// referencia es una columna que apunta al ID de otro modelo
+type referencia struct {
+	Columna string
+	Modelo  string // nombre del modelo referenciado (schema.Name)
+}
+
+// clavesNaturales identifican registros de catálogo que no tienen restricción UNIQUE.
+// Las columnas con UNIQUE (cédula, correo, usuario) se agregan automáticamente.
+var clavesNaturales = map[string][][]string{
+	"Provincia":   {{"provincia"}},
+	"Ciudad":      {{"provincia_id", "ciudad"}},
+	"TipoUsuario": {{"nombre"}},
+	"Institucion": {{"nombre"}},
+	"Tematica":    {{"nombre"}},
+	// Un archivo se usa una sola vez por noticia o comunicado
+	"MediaReferencia": {{"media_id", "entidad", "entidad_id"}},
+}
+
+// cargarTablas analiza database.Models en orden de migración, que también es el orden de dependencias
+func cargarTablas(namer schema.Namer) ([]*tabla, error) {
+	cache := &sync.Map{}
+	tablas := make([]*tabla, 0, len(database.Models))
+	vistos := map[string]bool{}
+	for _, model := range database.Models {
+		sch, err := schema.Parse(model, cache, namer)
+		if err != nil {
+			return nil, fmt.Errorf("error al analizar el modelo %T: %v", model, err)
+		}
+		t := &tabla{schema: sch, claves: clavesNaturales[sch.Name]}
+		for _, f := range sch.Fields {
+			if f.Unique && f.DBName != "" && !f.PrimaryKey {
+				t.claves = append(t.claves, []string{f.DBName})
+			}
+		}
+		for _, rel := range sch.Relationships.Relations {
+			if rel.Type != schema.BelongsTo {
+				continue
+			}
+			for _, ref := range rel.References {
+				if ref.ForeignKey == nil || ref.ForeignKey.Schema != sch {
+					continue
+				}
+				if !vistos[rel.FieldSchema.Name] {
+					return nil, fmt.Errorf("%s referencia a %s, que se migra después", sch.Name, rel.FieldSchema.Name)
+				}
+				t.referencias = append(t.referencias, referencia{Columna: ref.ForeignKey.DBName, Modelo: rel.FieldSchema.Name})
+			}
+		}
+		sort.Slice(t.referencias, func(i, j int) bool { return t.referencias[i].Columna < t.referencias[j].Columna })
+		vistos[sch.Name] = true
+		tablas = append(tablas, t)
+	}
+	return tablas, nil
+}
+
+// tablasDe devuelve las tablas usando la convención de nombres de la conexión
+func tablasDe(db *gorm.DB) ([]*tabla, error) {
+	return cargarTablas(db.NamingStrategy)
+}
+
+// archivoDatos es el nombre dentro del respaldo del NDJSON de la tabla
+func (t *tabla) archivoDatos() string {
+	return carpetaDatos + t.schema.Table + extensionDatos
+}
+
+// columnaID es la columna de la clave primaria
+func (t *tabla) columnaID() string {
+	return t.schema.PrioritizedPrimaryField.DBName
+}
@@ -0,0 +1,95 @@
+//go:build integration
+
+package backup_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"ApiEscuela/backup"
+	"ApiEscuela/models"
+	"ApiEscuela/storage"
+	"ApiEscuela/testutil"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(testutil.RunMain(m))
+}
+
+func TestExportarYRestaurar(t *testing.T) {
+	db := testutil.PostgresDB(t)
+	repos := testutil.GormRepos(db)
+	f := testutil.NewFixtures(t, repos)
+	e := f.Escenario()
+	estudiante := f.Estudiante(f.Persona(), e.Institucion, e.Ciudad)
+	eliminado := f.Usuario(f.Persona(), e.TipoEstudiante, "secreto1")
+	if err := repos.Usuario.DeleteUsuario(eliminado.ID); err != nil {
+		t.Fatal(err)
+	}
+	f.Comunicado(e.Admin, func(c *models.Comunicado) {
+		c.Destinatarios = `{"tipo":"estudiantes","ids":[` + strconv.Itoa(int(estudiante.ID)) + `]}`
+	})
+
+	assets := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(assets, "images"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(assets, "images", "logo.png"), []byte("png"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	manifiesto, err := backup.Exportar(db, &buf, backup.OpcionesExportacion{Archivos: storage.NewLocal(assets)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifiesto.Archivos != 1 {
+		t.Errorf("archivos = %d, se esperaba 1", manifiesto.Archivos)
+	}
+
+	// Sobre la base con datos los IDs se reasignan y los registros con clave natural se reutilizan
+	simulado, err := backup.Restaurar(db, bytes.NewReader(buf.Bytes()), backup.OpcionesRestauracion{Simular: true, SinArchivos: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if simulado.Modo != backup.ModoRemapearIDs {
+		t.Errorf("modo = %s, se esperaba %s", simulado.Modo, backup.ModoRemapearIDs)
+	}
+	for _, tabla := range simulado.Tablas {
+		if tabla.Tabla == "usuarios" && (tabla.Existentes != 2 || tabla.Insertadas != 0) {
+			t.Errorf("usuarios = %+v, se esperaban 2 existentes", tabla)
+		}
+	}
+	var comunicados int64
+	db.Model(&models.Comunicado{}).Count(&comunicados)
+	if comunicados != 1 {
+		t.Errorf("la simulación no debe escribir: %d comunicados", comunicados)
+	}
+
+	// Reemplazar conserva los IDs, las filas eliminadas y las secuencias
+	destino := storage.NewMemoria()
+	resultado, err := backup.Restaurar(db, bytes.NewReader(buf.Bytes()), backup.OpcionesRestauracion{Reemplazar: true, Archivos: destino})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resultado.Modo != backup.ModoConservarIDs || resultado.ArchivosRestaurados != 1 {
+		t.Errorf("resultado = %+v", resultado)
+	}
+	if _, err := repos.Estudiante.GetEstudianteByID(estudiante.ID); err != nil {
+		t.Errorf("el estudiante debería conservar su ID: %v", err)
+	}
+	if u, err := repos.Usuario.GetUsuarioByUsernameIncludingDeleted(eliminado.Usuario); err != nil || !u.DeletedAt.Valid {
+		t.Errorf("el usuario eliminado debería restaurarse como eliminado: %+v, %v", u, err)
+	}
+	provincia := &models.Provincia{Provincia: "Guayas"}
+	if err := repos.Provincia.CreateProvincia(provincia); err != nil || provincia.ID <= e.Provincia.ID {
+		t.Errorf("nueva provincia ID %d, %v: la secuencia debería continuar", provincia.ID, err)
+	}
+	if _, err := destino.Info(context.Background(), "images/logo.png"); err != nil {
+		t.Errorf("el asset debería restaurarse: %v", err)
+	}
+}
@@ -0,0 +1,245 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm/schema"
+)
+
+func tablasPrueba(t *testing.T) []*tabla {
+	t.Helper()
+	tablas, err := cargarTablas(schema.NamingStrategy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tablas
+}
+
+func buscarTabla(t *testing.T, tablas []*tabla, modelo string) *tabla {
+	t.Helper()
+	for _, tb := range tablas {
+		if tb.schema.Name == modelo {
+			return tb
+		}
+	}
+	t.Fatalf("modelo %s no encontrado", modelo)
+	return nil
+}
+
+func TestCargarTablas(t *testing.T) {
+	tablas := tablasPrueba(t)
+
+	usuario := buscarTabla(t, tablas, "Usuario")
+	want := []referencia{{"persona_id", "Persona"}, {"tipo_usuario_id", "TipoUsuario"}}
+	if fmt.Sprint(usuario.referencias) != fmt.Sprint(want) {
+		t.Errorf("referencias de Usuario = %v, se esperaba %v", usuario.referencias, want)
+	}
+	if fmt.Sprint(usuario.claves) != "[[usuario]]" {
+		t.Errorf("claves de Usuario = %v", usuario.claves)
+	}
+	if codigo := buscarTabla(t, tablas, "CodigoUsuario"); codigo.schema.Table != "codigosusuarios" {
+		t.Errorf("tabla de CodigoUsuario = %s", codigo.schema.Table)
+	}
+	dudas := buscarTabla(t, tablas, "Dudas")
+	if fmt.Sprint(dudas.referencias) != "[{autoridad_uteq_id AutoridadUTEQ} {estudiante_id Estudiante}]" {
+		t.Errorf("referencias de Dudas = %v", dudas.referencias)
+	}
+}
+
+// respaldo arma un contenido con filas escritas como JSON
+func respaldo(t *testing.T, tablas []*tabla, datos map[string][]string) *contenido {
+	t.Helper()
+	c := &contenido{manifiesto: &Manifiesto{Formato: Formato, Version: Version}, filas: map[string][]fila{}}
+	for modelo, lineas := range datos {
+		tb := buscarTabla(t, tablas, modelo)
+		for _, linea := range lineas {
+			var f fila
+			if err := json.Unmarshal([]byte(linea), &f); err != nil {
+				t.Fatal(err)
+			}
+			c.filas[tb.schema.Table] = append(c.filas[tb.schema.Table], f)
+		}
+	}
+	return c
+}
+
+func TestValidar(t *testing.T) {
+	tablas := tablasPrueba(t)
+	c := respaldo(t, tablas, map[string][]string{
+		"Provincia": {`{"id":1,"provincia":"Los Ríos"}`},
+		"Ciudad":    {`{"id":1,"provincia_id":1,"ciudad":"Quevedo"}`, `{"id":2,"provincia_id":9,"ciudad":"Mocache"}`, `{"id":2,"provincia_id":1,"ciudad":"Repetida"}`},
+		"Dudas":     {`{"id":1,"estudiante_id":5,"autoridad_uteq_id":null,"pregunta":"?"}`},
+	})
+	c.filas["desconocida"] = nil
+
+	problemas := strings.Join(validar(tablas, c), "\n")
+	for _, want := range []string{
+		"tabla desconocida: desconocida",
+		"ciudads ID 2: provincia_id=9 no existe en el respaldo",
+		"ciudads: ID 2 repetido",
+		"dudas ID 1: estudiante_id=5 no existe en el respaldo",
+	} {
+		if !strings.Contains(problemas, want) {
+			t.Errorf("falta el problema %q en:\n%s", want, problemas)
+		}
+	}
+	if strings.Contains(problemas, "autoridad_uteq_id") {
+		t.Errorf("las referencias nulas son válidas:\n%s", problemas)
+	}
+
+	c.manifiesto.Version = Version + 1
+	if p := validar(tablas, c); len(p) != 1 || !strings.Contains(p[0], "no soportada") {
+		t.Errorf("problemas = %v, se esperaba versión no soportada", p)
+	}
+}
+
+// destinoMemoria guarda las filas insertadas por modelo
+type destinoMemoria struct {
+	filas map[string][]map[string]interface{}
+}
+
+func (d *destinoMemoria) buscar(t *tabla, valores map[string]interface{}) (uint, bool, error) {
+	for _, f := range d.filas[t.schema.Name] {
+		coincide := true
+		for k, v := range valores {
+			if fmt.Sprint(f[k]) != fmt.Sprint(v) {
+				coincide = false
+			}
+		}
+		if coincide {
+			return f["id"].(uint), true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+func (d *destinoMemoria) insertar(t *tabla, valores map[string]interface{}) (uint, error) {
+	if _, ok := valores["id"]; !ok {
+		valores["id"] = uint(100 + len(d.filas[t.schema.Name]))
+	}
+	d.filas[t.schema.Name] = append(d.filas[t.schema.Name], valores)
+	return valores["id"].(uint), nil
+}
+
+func TestRestaurarFilasRemapeaIDs(t *testing.T) {
+	tablas := tablasPrueba(t)
+	c := respaldo(t, tablas, map[string][]string{
+		"Provincia":   {`{"id":1,"provincia":"Los Ríos"}`, `{"id":2,"provincia":"Guayas"}`},
+		"Ciudad":      {`{"id":7,"provincia_id":2,"ciudad":"Daule"}`},
+		"Institucion": {`{"id":3,"nombre":"Colegio A"}`, `{"id":4,"nombre":"Colegio B"}`},
+		"Persona":     {`{"id":1,"nombre":"Ana","cedula":"0912345678","correo":null}`},
+		"TipoUsuario": {`{"id":1,"nombre":"Administrador"}`},
+		"Usuario":     {`{"id":1,"usuario":"ana","persona_id":1,"tipo_usuario_id":1,"deleted_at":"2026-01-02T03:04:05Z"}`},
+		"Comunicado":  {`{"id":1,"usuario_id":1,"asunto":"Hola","destinatarios":"{\"tipo\":\"instituciones\",\"ids\":[4,99]}"}`},
+	})
+	if p := validar(tablas, c); len(p) > 0 {
+		t.Fatalf("respaldo inválido: %v", p)
+	}
+
+	// La base ya tiene Los Ríos y una persona con otra cédula pero sin correo
+	dst := &destinoMemoria{filas: map[string][]map[string]interface{}{
+		"Provincia": {{"id": uint(50), "provincia": "Los Ríos"}},
+		"Persona":   {{"id": uint(60), "cedula": "0999999999", "correo": nil}},
+	}}
+	resultados, err := restaurarFilas(tablas, c, dst, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	por := map[string]ResultadoTabla{}
+	for _, r := range resultados {
+		por[r.Tabla] = r
+	}
+	if r := por["provincia"]; r.Existentes != 1 || r.Insertadas != 1 {
+		t.Errorf("provincias = %+v, se esperaba 1 existente y 1 insertada", r)
+	}
+	if r := por["personas"]; r.Existentes != 0 || r.Insertadas != 1 {
+		t.Errorf("personas = %+v; un correo nulo no identifica a nadie", r)
+	}
+
+	guayas := dst.filas["Provincia"][1]["id"]
+	if ciudad := dst.filas["Ciudad"][0]; ciudad["provincia_id"] != guayas {
+		t.Errorf("provincia_id = %v, se esperaba %v", ciudad["provincia_id"], guayas)
+	}
+	usuario := dst.filas["Usuario"][0]
+	if usuario["persona_id"] != dst.filas["Persona"][1]["id"] {
+		t.Errorf("persona_id = %v", usuario["persona_id"])
+	}
+	if eliminado := usuario["deleted_at"]; fmt.Sprint(eliminado) == "" || !strings.Contains(fmt.Sprint(eliminado), "2026") {
+		t.Errorf("deleted_at = %v, debe conservarse", eliminado)
+	}
+	colegioB := dst.filas["Institucion"][1]["id"]
+	want := fmt.Sprintf(`{"ids":[%d],"tipo":"instituciones"}`, colegioB)
+	if got := dst.filas["Comunicado"][0]["destinatarios"]; got != want {
+		t.Errorf("destinatarios = %v, se esperaba %s", got, want)
+	}
+}
+
+func TestRestaurarFilasConservaIDs(t *testing.T) {
+	tablas := tablasPrueba(t)
+	c := respaldo(t, tablas, map[string][]string{
+		"Provincia": {`{"id":5,"provincia":"Los Ríos"}`},
+		"Ciudad":    {`{"id":9,"provincia_id":5,"ciudad":"Quevedo"}`},
+	})
+	dst := &destinoMemoria{filas: map[string][]map[string]interface{}{}}
+	if _, err := restaurarFilas(tablas, c, dst, true); err != nil {
+		t.Fatal(err)
+	}
+	if ciudad := dst.filas["Ciudad"][0]; ciudad["id"] != uint(9) || ciudad["provincia_id"] != uint(5) {
+		t.Errorf("ciudad = %v, se esperaban los IDs originales", ciudad)
+	}
+}
+
+func TestLeerRespaldo(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	agregar := func(nombre, texto string) {
+		if err := escribirEntrada(tw, nombre, time.Now(), strings.NewReader(texto), int64(len(texto))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	agregar(archivoManifiesto, `{"formato":"apiescuela-backup","version":1,"tablas":[{"nombre":"provincia","filas":2}]}`)
+	agregar("data/provincia.ndjson", "{\"id\":1}\n\n{\"id\":2}\n")
+	agregar("assets/comunicados_files/1700000000/acta final.pdf", "pdf")
+	tw.Close()
+	gz.Close()
+
+	dir := t.TempDir()
+	c, err := leerRespaldo(bytes.NewReader(buf.Bytes()), dir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.filas["provincia"]) != 2 || c.manifiesto.Tablas[0].Filas != 2 {
+		t.Errorf("filas = %v, manifiesto = %+v", c.filas, c.manifiesto)
+	}
+	clave := "comunicados_files/1700000000/acta final.pdf"
+	if len(c.archivos) != 1 || c.archivos[0] != clave {
+		t.Fatalf("archivos = %v", c.archivos)
+	}
+	if b, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(clave))); err != nil || string(b) != "pdf" {
+		t.Errorf("contenido del asset = %q, %v", b, err)
+	}
+
+	for _, nombre := range []string{"assets/../../etc/passwd", "assets//etc/passwd/../../.."} {
+		buf.Reset()
+		gz = gzip.NewWriter(&buf)
+		tw = tar.NewWriter(gz)
+		agregar(archivoManifiesto, `{"formato":"apiescuela-backup","version":1}`)
+		agregar(nombre, "x")
+		tw.Close()
+		gz.Close()
+		if _, err := leerRespaldo(bytes.NewReader(buf.Bytes()), t.TempDir(), true); err == nil {
+			t.Errorf("se esperaba error para %s", nombre)
+		}
+	}
+}
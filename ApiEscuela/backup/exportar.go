@@ -0,0 +1,184 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	"ApiEscuela/storage"
+
+	"gorm.io/gorm"
+)
+
+// tamanoLote es la cantidad de filas que se leen por consulta al exportar
+const tamanoLote = 500
+
+// OpcionesExportacion configura Exportar
+type OpcionesExportacion struct {
+	SinArchivos bool            // no incluir los archivos subidos
+	Archivos    storage.Storage // almacenamiento de los archivos subidos; por defecto la carpeta "assets"
+}
+
+// Exportar escribe en w un respaldo .tar.gz de todas las tablas y, salvo SinArchivos, de los assets.
+// Las tablas se leen dentro de una misma transacción para obtener una copia consistente.
+func Exportar(db *gorm.DB, w io.Writer, opts OpcionesExportacion) (*Manifiesto, error) {
+	tablas, err := tablasDe(db)
+	if err != nil {
+		return nil, err
+	}
+
+	manifiesto := &Manifiesto{Formato: Formato, Version: Version, Creado: time.Now().UTC()}
+	datos := make([]*bytes.Buffer, len(tablas))
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for i, t := range tablas {
+			buf := &bytes.Buffer{}
+			filas, err := exportarTabla(tx, t, buf)
+			if err != nil {
+				return fmt.Errorf("error al exportar %s: %v", t.schema.Table, err)
+			}
+			datos[i] = buf
+			manifiesto.Tablas = append(manifiesto.Tablas, TablaManifiesto{Nombre: t.schema.Table, Filas: filas})
+		}
+		return nil
+	}, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+
+	var archivos []archivoAsset
+	if !opts.SinArchivos {
+		archivos, err = listarAssets(almacenamiento(opts.Archivos))
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range archivos {
+			manifiesto.Archivos++
+			manifiesto.BytesArchivos += a.tamano
+		}
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	contenido, err := json.MarshalIndent(manifiesto, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := escribirEntrada(tw, archivoManifiesto, manifiesto.Creado, bytes.NewReader(contenido), int64(len(contenido))); err != nil {
+		return nil, err
+	}
+	for i, t := range tablas {
+		if err := escribirEntrada(tw, t.archivoDatos(), manifiesto.Creado, datos[i], int64(datos[i].Len())); err != nil {
+			return nil, err
+		}
+	}
+	for _, a := range archivos {
+		if err := escribirAsset(tw, almacenamiento(opts.Archivos), a); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return manifiesto, nil
+}
+
+// exportarTabla escribe en w todas las filas de la tabla (incluidas las eliminadas) como NDJSON
+func exportarTabla(tx *gorm.DB, t *tabla, w io.Writer) (int, error) {
+	enc := json.NewEncoder(w)
+	ctx := context.Background()
+	total := 0
+	for offset := 0; ; offset += tamanoLote {
+		lote := reflect.New(reflect.SliceOf(t.schema.ModelType))
+		err := tx.Unscoped().Model(reflect.New(t.schema.ModelType).Interface()).
+			Order(t.columnaID()).Limit(tamanoLote).Offset(offset).
+			Find(lote.Interface()).Error
+		if err != nil {
+			return total, err
+		}
+		filas := lote.Elem()
+		for i := 0; i < filas.Len(); i++ {
+			fila := filas.Index(i)
+			registro := make(map[string]interface{}, len(t.schema.DBNames))
+			for _, f := range t.schema.Fields {
+				if f.DBName == "" {
+					continue
+				}
+				registro[f.DBName], _ = f.ValueOf(ctx, fila)
+			}
+			if err := enc.Encode(registro); err != nil {
+				return total, err
+			}
+		}
+		total += filas.Len()
+		if filas.Len() < tamanoLote {
+			return total, nil
+		}
+	}
+}
+
+// archivoAsset es un archivo subido
+type archivoAsset struct {
+	clave      string // clave en el almacenamiento
+	nombre     string // ruta dentro del respaldo (assets/...)
+	tamano     int64
+	modificado time.Time
+}
+
+// almacenamiento devuelve el almacenamiento configurado o la carpeta local predeterminada
+func almacenamiento(s storage.Storage) storage.Storage {
+	if s == nil {
+		return storage.NewLocal("assets")
+	}
+	return s
+}
+
+// listarAssets lista todos los archivos del almacenamiento
+func listarAssets(s storage.Storage) ([]archivoAsset, error) {
+	var archivos []archivoAsset
+	err := s.Listar(context.Background(), "", func(obj storage.Objeto) error {
+		archivos = append(archivos, archivoAsset{
+			clave:      obj.Clave,
+			nombre:     carpetaAssets + obj.Clave,
+			tamano:     obj.Tamano,
+			modificado: obj.Modificado,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error al listar los archivos de %s: %v", s.Nombre(), err)
+	}
+	return archivos, nil
+}
+
+// escribirEntrada agrega un archivo al tar
+func escribirEntrada(tw *tar.Writer, nombre string, modificado time.Time, r io.Reader, tamano int64) error {
+	hdr := &tar.Header{Name: nombre, Mode: 0o644, Size: tamano, ModTime: modificado, Typeflag: tar.TypeReg}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := io.Copy(tw, r)
+	return err
+}
+
+// escribirAsset copia un archivo subido al tar
+func escribirAsset(tw *tar.Writer, s storage.Storage, a archivoAsset) error {
+	r, _, err := s.Abrir(context.Background(), a.clave)
+	if err != nil {
+		return fmt.Errorf("error al respaldar %s: %v", a.clave, err)
+	}
+	defer r.Close()
+	if err := escribirEntrada(tw, a.nombre, a.modificado, io.LimitReader(r, a.tamano), a.tamano); err != nil {
+		return fmt.Errorf("error al respaldar %s: %v", a.clave, err)
+	}
+	return nil
+}
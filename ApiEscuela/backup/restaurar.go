@@ -0,0 +1,618 @@
+package backup
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"ApiEscuela/storage"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxProblemas limita la cantidad de problemas de validación que se informan
+const maxProblemas = 50
+
+// OpcionesRestauracion configura Restaurar
+type OpcionesRestauracion struct {
+	// Reemplazar vacía todas las tablas antes de restaurar y sobrescribe los assets existentes
+	Reemplazar bool
+	// SinArchivos ignora los assets incluidos en el respaldo
+	SinArchivos bool
+	// Simular valida y ejecuta la restauración dentro de una transacción que se revierte
+	Simular bool
+	// Archivos es el almacenamiento de los archivos subidos; por defecto la carpeta "assets"
+	Archivos storage.Storage
+}
+
+// Modos de restauración
+const (
+	ModoConservarIDs = "conservar_ids" // base vacía o Reemplazar: los registros mantienen su ID
+	ModoRemapearIDs  = "remapear_ids"  // base con datos: se asignan IDs nuevos y se actualizan las referencias
+)
+
+// ResultadoTabla resume lo restaurado en una tabla
+type ResultadoTabla struct {
+	Tabla      string `json:"tabla"`
+	Insertadas int    `json:"insertadas"`
+	Existentes int    `json:"existentes"` // filas que coincidieron con un registro existente y no se insertaron
+}
+
+// Resultado resume una restauración
+type Resultado struct {
+	Manifiesto          *Manifiesto      `json:"manifiesto"`
+	Modo                string           `json:"modo"`
+	Simulado            bool             `json:"simulado"`
+	Tablas              []ResultadoTabla `json:"tablas"`
+	ArchivosRestaurados int              `json:"archivos_restaurados"`
+	ArchivosOmitidos    int              `json:"archivos_omitidos"` // ya existían y no se sobrescribieron
+}
+
+// ErrorValidacion indica que el respaldo no es válido; no se escribió nada
+type ErrorValidacion struct {
+	Problemas []string
+}
+
+func (e *ErrorValidacion) Error() string {
+	return fmt.Sprintf("respaldo inválido (%d problemas): %s", len(e.Problemas), strings.Join(e.Problemas, "; "))
+}
+
+// fila es una fila del respaldo con sus columnas sin decodificar
+type fila map[string]json.RawMessage
+
+// contenido es un respaldo leído en memoria; los assets se copian a una carpeta temporal
+type contenido struct {
+	manifiesto *Manifiesto
+	filas      map[string][]fila // por nombre de tabla
+	archivos   []string          // claves de los archivos, copiados en la carpeta temporal
+}
+
+// errSimulacion revierte la transacción de una restauración simulada
+var errSimulacion = errors.New("simulación")
+
+// Restaurar lee un respaldo generado por Exportar y lo carga en la base de datos.
+// Todas las filas se escriben en una transacción; los assets se copian solo si la transacción se confirma.
+func Restaurar(db *gorm.DB, r io.Reader, opts OpcionesRestauracion) (*Resultado, error) {
+	tablas, err := tablasDe(db)
+	if err != nil {
+		return nil, err
+	}
+
+	temporal, err := os.MkdirTemp("", "apiescuela-restore-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(temporal)
+
+	c, err := leerRespaldo(r, temporal, !opts.SinArchivos)
+	if err != nil {
+		return nil, &ErrorValidacion{Problemas: []string{err.Error()}}
+	}
+	if problemas := validar(tablas, c); len(problemas) > 0 {
+		return nil, &ErrorValidacion{Problemas: problemas}
+	}
+
+	resultado := &Resultado{Manifiesto: c.manifiesto, Simulado: opts.Simular}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		conservarIDs := opts.Reemplazar
+		if opts.Reemplazar {
+			if err := vaciarTablas(tx, tablas); err != nil {
+				return fmt.Errorf("error al vaciar las tablas: %v", err)
+			}
+		} else {
+			vacia, err := baseVacia(tx, tablas)
+			if err != nil {
+				return err
+			}
+			conservarIDs = vacia
+		}
+		resultado.Modo = ModoRemapearIDs
+		if conservarIDs {
+			resultado.Modo = ModoConservarIDs
+		}
+
+		resultado.Tablas, err = restaurarFilas(tablas, c, &destinoGorm{tx: tx}, conservarIDs)
+		if err != nil {
+			return err
+		}
+		if conservarIDs {
+			if err := reiniciarSecuencias(tx, tablas); err != nil {
+				return fmt.Errorf("error al reiniciar las secuencias: %v", err)
+			}
+		}
+		if opts.Simular {
+			return errSimulacion
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errSimulacion) {
+		return nil, err
+	}
+	if opts.Simular || opts.SinArchivos {
+		return resultado, nil
+	}
+
+	resultado.ArchivosRestaurados, resultado.ArchivosOmitidos, err = copiarAssets(storage.NewLocal(temporal), almacenamiento(opts.Archivos), c.archivos, opts.Reemplazar)
+	return resultado, err
+}
+
+// leerRespaldo lee el .tar.gz completo; los datos quedan en memoria y los assets en dirTemporal
+func leerRespaldo(r io.Reader, dirTemporal string, conArchivos bool) (*contenido, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("el archivo no es un respaldo .tar.gz: %v", err)
+	}
+	defer gz.Close()
+
+	c := &contenido{filas: map[string][]fila{}}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error al leer el respaldo: %v", err)
+		}
+		if hdr.Typeflag == tar.TypeDir {
+			continue
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			return nil, fmt.Errorf("entrada no soportada en el respaldo: %s", hdr.Name)
+		}
+
+		switch nombre := hdr.Name; {
+		case nombre == archivoManifiesto:
+			c.manifiesto = &Manifiesto{}
+			if err := json.NewDecoder(tr).Decode(c.manifiesto); err != nil {
+				return nil, fmt.Errorf("manifiesto inválido: %v", err)
+			}
+		case strings.HasPrefix(nombre, carpetaDatos) && strings.HasSuffix(nombre, extensionDatos):
+			tabla := strings.TrimSuffix(strings.TrimPrefix(nombre, carpetaDatos), extensionDatos)
+			filas, err := leerFilas(tr)
+			if err != nil {
+				return nil, fmt.Errorf("error en %s: %v", nombre, err)
+			}
+			c.filas[tabla] = filas
+		case strings.HasPrefix(nombre, carpetaAssets):
+			rel, ok := rutaSegura(strings.TrimPrefix(nombre, carpetaAssets))
+			if !ok {
+				return nil, fmt.Errorf("ruta de archivo no permitida en el respaldo: %s", nombre)
+			}
+			if !conArchivos {
+				continue
+			}
+			if err := guardarArchivo(filepath.Join(dirTemporal, rel), tr); err != nil {
+				return nil, err
+			}
+			c.archivos = append(c.archivos, filepath.ToSlash(rel))
+		default:
+			return nil, fmt.Errorf("entrada desconocida en el respaldo: %s", nombre)
+		}
+	}
+	if c.manifiesto == nil {
+		return nil, fmt.Errorf("el respaldo no contiene %s", archivoManifiesto)
+	}
+	return c, nil
+}
+
+// leerFilas decodifica un NDJSON; las líneas vacías se ignoran
+func leerFilas(r io.Reader) ([]fila, error) {
+	var filas []fila
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for n := 1; sc.Scan(); n++ {
+		linea := bytes.TrimSpace(sc.Bytes())
+		if len(linea) == 0 {
+			continue
+		}
+		var f fila
+		if err := json.Unmarshal(linea, &f); err != nil {
+			return nil, fmt.Errorf("línea %d: %v", n, err)
+		}
+		filas = append(filas, f)
+	}
+	return filas, sc.Err()
+}
+
+// rutaSegura normaliza una ruta relativa del respaldo y rechaza las que salen de la carpeta
+func rutaSegura(nombre string) (string, bool) {
+	limpio := path.Clean(nombre)
+	if limpio == "." || path.IsAbs(limpio) || limpio == ".." || strings.HasPrefix(limpio, "../") {
+		return "", false
+	}
+	return filepath.FromSlash(limpio), true
+}
+
+// guardarArchivo escribe el contenido de r en ruta, creando las carpetas necesarias
+func guardarArchivo(ruta string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(ruta), 0o755); err != nil {
+		return err
+	}
+	f, err := os.Create(ruta)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// validar revisa el manifiesto, los IDs y que cada referencia apunte a una fila incluida en el respaldo
+func validar(tablas []*tabla, c *contenido) []string {
+	var problemas []string
+	agregar := func(format string, args ...interface{}) {
+		if len(problemas) < maxProblemas {
+			problemas = append(problemas, fmt.Sprintf(format, args...))
+		}
+	}
+
+	m := c.manifiesto
+	if m.Formato != Formato {
+		agregar("formato %q desconocido", m.Formato)
+		return problemas
+	}
+	if m.Version < 1 || m.Version > Version {
+		agregar("versión %d no soportada (máxima %d)", m.Version, Version)
+		return problemas
+	}
+	esperadas := map[string]int{}
+	for _, t := range m.Tablas {
+		esperadas[t.Nombre] = t.Filas
+	}
+	conocidas := map[string]bool{}
+	for _, t := range tablas {
+		conocidas[t.schema.Table] = true
+	}
+	for nombre := range c.filas {
+		if !conocidas[nombre] {
+			agregar("tabla desconocida: %s", nombre)
+		}
+	}
+
+	ids := map[string]map[uint]bool{} // por modelo
+	for _, t := range tablas {
+		filas := c.filas[t.schema.Table]
+		if n, ok := esperadas[t.schema.Table]; ok && n != len(filas) {
+			agregar("%s: el manifiesto indica %d filas y el respaldo contiene %d", t.schema.Table, n, len(filas))
+		}
+		vistos := map[uint]bool{}
+		for i, f := range filas {
+			id, ok := idDe(f[t.columnaID()])
+			if !ok {
+				agregar("%s fila %d: ID inválido", t.schema.Table, i+1)
+				continue
+			}
+			if vistos[id] {
+				agregar("%s: ID %d repetido", t.schema.Table, id)
+			}
+			vistos[id] = true
+		}
+		ids[t.schema.Name] = vistos
+
+		for _, f := range filas {
+			id, _ := idDe(f[t.columnaID()])
+			for _, ref := range t.referencias {
+				raw, ok := f[ref.Columna]
+				if !ok || string(raw) == "null" {
+					continue
+				}
+				destino, ok := idDe(raw)
+				if !ok {
+					agregar("%s ID %d: %s inválido", t.schema.Table, id, ref.Columna)
+				} else if !ids[ref.Modelo][destino] {
+					agregar("%s ID %d: %s=%d no existe en el respaldo", t.schema.Table, id, ref.Columna, destino)
+				}
+			}
+		}
+	}
+	return problemas
+}
+
+// idDe decodifica un ID positivo
+func idDe(raw json.RawMessage) (uint, bool) {
+	var id uint
+	if len(raw) == 0 || json.Unmarshal(raw, &id) != nil || id == 0 {
+		return 0, false
+	}
+	return id, true
+}
+
+// destino es donde se escriben las filas restauradas
+type destino interface {
+	// buscar devuelve el ID de un registro (incluidos los eliminados) con esos valores
+	buscar(t *tabla, valores map[string]interface{}) (uint, bool, error)
+	// insertar crea el registro y devuelve su ID; si valores no incluye el ID se asigna uno nuevo
+	insertar(t *tabla, valores map[string]interface{}) (uint, error)
+}
+
+// restaurarFilas escribe las filas en orden de dependencias. Con conservarIDs inserta cada fila con su ID;
+// si no, reutiliza los registros que coinciden por clave natural y asigna IDs nuevos al resto.
+func restaurarFilas(tablas []*tabla, c *contenido, dst destino, conservarIDs bool) ([]ResultadoTabla, error) {
+	nuevosIDs := map[string]map[uint]uint{} // por modelo: ID del respaldo → ID en la base
+	traducir := func(modelo string, id uint) (uint, bool) {
+		nuevo, ok := nuevosIDs[modelo][id]
+		return nuevo, ok
+	}
+
+	resultados := make([]ResultadoTabla, 0, len(tablas))
+	for _, t := range tablas {
+		res := ResultadoTabla{Tabla: t.schema.Table}
+		mapa := map[uint]uint{}
+		nuevosIDs[t.schema.Name] = mapa
+		for _, f := range c.filas[t.schema.Table] {
+			valores, err := decodificar(t, f)
+			if err != nil {
+				return nil, err
+			}
+			original, _ := idDe(f[t.columnaID()])
+			for _, ref := range t.referencias {
+				if err := remapearReferencia(valores, ref, traducir); err != nil {
+					return nil, fmt.Errorf("%s ID %d: %v", t.schema.Table, original, err)
+				}
+			}
+
+			if !conservarIDs {
+				if ajustar, ok := ajustes[t.schema.Name]; ok {
+					ajustar(valores, traducir)
+				}
+				existente, ok, err := buscarPorClaves(t, valores, dst)
+				if err != nil {
+					return nil, fmt.Errorf("%s ID %d: %v", t.schema.Table, original, err)
+				}
+				if ok {
+					mapa[original] = existente
+					res.Existentes++
+					continue
+				}
+				delete(valores, t.columnaID())
+			}
+
+			nuevo, err := dst.insertar(t, valores)
+			if err != nil {
+				return nil, fmt.Errorf("error al insertar %s ID %d: %v", t.schema.Table, original, err)
+			}
+			mapa[original] = nuevo
+			res.Insertadas++
+		}
+		resultados = append(resultados, res)
+	}
+	return resultados, nil
+}
+
+// decodificar convierte las columnas del respaldo a los tipos del modelo
+func decodificar(t *tabla, f fila) (map[string]interface{}, error) {
+	valores := make(map[string]interface{}, len(f))
+	for columna, raw := range f {
+		campo := t.schema.LookUpField(columna)
+		if campo == nil || campo.DBName == "" {
+			return nil, fmt.Errorf("%s: columna desconocida %q", t.schema.Table, columna)
+		}
+		v := reflect.New(campo.FieldType)
+		if err := json.Unmarshal(raw, v.Interface()); err != nil {
+			return nil, fmt.Errorf("%s: valor inválido en %s: %v", t.schema.Table, columna, err)
+		}
+		valores[columna] = v.Elem().Interface()
+	}
+	return valores, nil
+}
+
+// remapearReferencia reemplaza el ID referenciado por el asignado al restaurar
+func remapearReferencia(valores map[string]interface{}, ref referencia, traducir func(string, uint) (uint, bool)) error {
+	switch v := valores[ref.Columna].(type) {
+	case uint:
+		nuevo, ok := traducir(ref.Modelo, v)
+		if !ok {
+			return fmt.Errorf("%s=%d no fue restaurado", ref.Columna, v)
+		}
+		valores[ref.Columna] = nuevo
+	case *uint:
+		if v == nil {
+			return nil
+		}
+		nuevo, ok := traducir(ref.Modelo, *v)
+		if !ok {
+			return fmt.Errorf("%s=%d no fue restaurado", ref.Columna, *v)
+		}
+		valores[ref.Columna] = &nuevo
+	}
+	return nil
+}
+
+// buscarPorClaves busca un registro existente por cada clave natural; las claves con valores vacíos se omiten
+func buscarPorClaves(t *tabla, valores map[string]interface{}, dst destino) (uint, bool, error) {
+	for _, clave := range t.claves {
+		condiciones := make(map[string]interface{}, len(clave))
+		for _, columna := range clave {
+			v := valores[columna]
+			if esVacio(v) {
+				condiciones = nil
+				break
+			}
+			condiciones[columna] = v
+		}
+		if condiciones == nil {
+			continue
+		}
+		if id, ok, err := dst.buscar(t, condiciones); err != nil || ok {
+			return id, ok, err
+		}
+	}
+	return 0, false, nil
+}
+
+// esVacio indica si un valor no sirve para identificar un registro
+func esVacio(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return true
+		}
+		rv = rv.Elem()
+	}
+	return rv.IsZero()
+}
+
+// ajustes corrigen al remapear los IDs que no son claves foráneas: los guardados dentro de
+// columnas JSON y los que apuntan a un modelo distinto según otra columna
+var ajustes = map[string]func(valores map[string]interface{}, traducir func(string, uint) (uint, bool)){
+	"Comunicado":      remapearDestinatarios,
+	"MediaReferencia": remapearEntidadReferencia,
+}
+
+// remapearEntidadReferencia actualiza el ID de la noticia o el comunicado que usa un archivo
+func remapearEntidadReferencia(valores map[string]interface{}, traducir func(string, uint) (uint, bool)) {
+	modelo := map[string]string{"noticia": "Noticia", "comunicado": "Comunicado"}[fmt.Sprint(valores["entidad"])]
+	id, ok := valores["entidad_id"].(uint)
+	if modelo == "" || !ok {
+		return
+	}
+	if nuevo, ok := traducir(modelo, id); ok {
+		valores["entidad_id"] = nuevo
+	}
+}
+
+// remapearDestinatarios actualiza los IDs de instituciones o estudiantes del JSON de destinatarios.
+// Los IDs que no estaban en el respaldo se descartan para no apuntar a otro registro.
+func remapearDestinatarios(valores map[string]interface{}, traducir func(string, uint) (uint, bool)) {
+	texto, _ := valores["destinatarios"].(string)
+	var destinatarios map[string]interface{}
+	if texto == "" || json.Unmarshal([]byte(texto), &destinatarios) != nil {
+		return
+	}
+	modelo := map[string]string{"instituciones": "Institucion", "estudiantes": "Estudiante"}[fmt.Sprint(destinatarios["tipo"])]
+	lista, ok := destinatarios["ids"].([]interface{})
+	if modelo == "" || !ok {
+		return
+	}
+	nuevos := make([]uint, 0, len(lista))
+	for _, v := range lista {
+		if n, ok := v.(float64); ok {
+			if id, ok := traducir(modelo, uint(n)); ok {
+				nuevos = append(nuevos, id)
+			}
+		}
+	}
+	destinatarios["ids"] = nuevos
+	if b, err := json.Marshal(destinatarios); err == nil {
+		valores["destinatarios"] = string(b)
+	}
+}
+
+// destinoGorm escribe las filas con GORM dentro de la transacción de la restauración
+type destinoGorm struct {
+	tx *gorm.DB
+}
+
+func (d *destinoGorm) buscar(t *tabla, valores map[string]interface{}) (uint, bool, error) {
+	var ids []uint
+	err := d.tx.Unscoped().Table(t.schema.Table).Where(valores).Limit(1).Pluck(t.columnaID(), &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, false, err
+	}
+	return ids[0], true, nil
+}
+
+func (d *destinoGorm) insertar(t *tabla, valores map[string]interface{}) (uint, error) {
+	ctx := context.Background()
+	registro := reflect.New(t.schema.ModelType)
+	for columna, v := range valores {
+		if err := t.schema.LookUpField(columna).Set(ctx, registro.Elem(), v); err != nil {
+			return 0, fmt.Errorf("%s: %v", columna, err)
+		}
+	}
+	if err := d.tx.Omit(clause.Associations).Create(registro.Interface()).Error; err != nil {
+		return 0, err
+	}
+	id, _ := t.schema.PrioritizedPrimaryField.ValueOf(ctx, registro.Elem())
+	return id.(uint), nil
+}
+
+// baseVacia indica si ninguna tabla tiene filas (incluidas las eliminadas)
+func baseVacia(tx *gorm.DB, tablas []*tabla) (bool, error) {
+	for _, t := range tablas {
+		var n int64
+		if err := tx.Unscoped().Table(t.schema.Table).Count(&n).Error; err != nil {
+			return false, err
+		}
+		if n > 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// vaciarTablas elimina definitivamente todas las filas
+func vaciarTablas(tx *gorm.DB, tablas []*tabla) error {
+	if tx.Dialector.Name() == "postgres" {
+		nombres := make([]string, len(tablas))
+		for i, t := range tablas {
+			nombres[i] = `"` + t.schema.Table + `"`
+		}
+		return tx.Exec("TRUNCATE " + strings.Join(nombres, ", ") + " RESTART IDENTITY CASCADE").Error
+	}
+	for i := len(tablas) - 1; i >= 0; i-- {
+		if err := tx.Exec("DELETE FROM " + tx.Statement.Quote(tablas[i].schema.Table)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reiniciarSecuencias ajusta las secuencias de Postgres después de insertar IDs explícitos
+func reiniciarSecuencias(tx *gorm.DB, tablas []*tabla) error {
+	if tx.Dialector.Name() != "postgres" {
+		return nil
+	}
+	for _, t := range tablas {
+		sql := fmt.Sprintf(`SELECT setval(pg_get_serial_sequence('%[1]s', '%[2]s'), COALESCE(MAX("%[2]s"), 0) + 1, false) FROM "%[1]s"`,
+			t.schema.Table, t.columnaID())
+		if err := tx.Exec(sql).Error; err != nil {
+			return fmt.Errorf("%s: %v", t.schema.Table, err)
+		}
+	}
+	return nil
+}
+
+// copiarAssets copia los archivos del respaldo al almacenamiento.
+// Los archivos existentes solo se sobrescriben con reemplazar.
+func copiarAssets(origen, destino storage.Storage, archivos []string, reemplazar bool) (int, int, error) {
+	ctx := context.Background()
+	copiados, omitidos := 0, 0
+	for _, clave := range archivos {
+		if _, err := destino.Info(ctx, clave); err == nil && !reemplazar {
+			omitidos++
+			continue
+		} else if err != nil && !errors.Is(err, storage.ErrNoEncontrado) {
+			return copiados, omitidos, err
+		}
+		r, obj, err := origen.Abrir(ctx, clave)
+		if err != nil {
+			return copiados, omitidos, err
+		}
+		err = destino.Guardar(ctx, clave, r, obj.Tamano, obj.ContentType)
+		r.Close()
+		if err != nil {
+			return copiados, omitidos, fmt.Errorf("error al restaurar %s: %v", clave, err)
+		}
+		copiados++
+	}
+	return copiados, omitidos, nil
+}
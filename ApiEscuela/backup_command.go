@@ -0,0 +1,116 @@
+package main
+
+import (
+	"ApiEscuela/backup"
+	"ApiEscuela/services"
+	"ApiEscuela/storage"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var comandosBackup = map[string]func(db *gorm.DB, args []string) error{
+	"export":  exportarBackup,
+	"restore": restaurarBackup,
+}
+
+// exportarBackup escribe un respaldo .tar.gz en un archivo o en la salida estándar ("-")
+func exportarBackup(db *gorm.DB, args []string) error {
+	fs := flag.NewFlagSet("backup export", flag.ContinueOnError)
+	salida := fs.String("salida", fmt.Sprintf("apiescuela-backup-%s.tar.gz", time.Now().Format("20060102-150405")), "archivo de destino; - para la salida estándar")
+	sinArchivos := fs.Bool("sin-archivos", false, "no incluir los archivos subidos")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *salida == "-" {
+		// El logger de GORM escribe en stdout y corrompería el respaldo
+		db = db.Session(&gorm.Session{Logger: logger.Discard})
+	} else {
+		f, err := os.Create(*salida)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	archivos, err := storage.DesdeEntorno()
+	if err != nil {
+		return err
+	}
+	manifiesto, err := services.NewBackupService(db, archivos).Exportar(w, backup.OpcionesExportacion{SinArchivos: *sinArchivos})
+	if err != nil {
+		if *salida != "-" {
+			os.Remove(*salida)
+		}
+		return err
+	}
+	// El resumen va a stderr para no mezclarse con el respaldo cuando se usa la salida estándar
+	filas := 0
+	for _, t := range manifiesto.Tablas {
+		filas += t.Filas
+	}
+	fmt.Fprintf(os.Stderr, "Respaldo %s: %d tablas, %d filas, %d archivos (%d bytes)\n",
+		*salida, len(manifiesto.Tablas), filas, manifiesto.Archivos, manifiesto.BytesArchivos)
+	return nil
+}
+
+// restaurarBackup carga un respaldo generado por `backup export`
+func restaurarBackup(db *gorm.DB, args []string) error {
+	fs := flag.NewFlagSet("backup restore", flag.ContinueOnError)
+	var opts backup.OpcionesRestauracion
+	fs.BoolVar(&opts.Reemplazar, "reemplazar", false, "vaciar todas las tablas y conservar los IDs del respaldo; sobrescribe los archivos existentes")
+	fs.BoolVar(&opts.SinArchivos, "sin-archivos", false, "no restaurar los archivos subidos")
+	fs.BoolVar(&opts.Simular, "simular", false, "validar y mostrar el resultado sin guardar cambios")
+	ruta, err := argumentoUnico(fs, args, "archivo.tar.gz")
+	if err != nil {
+		return err
+	}
+
+	var r io.Reader = os.Stdin
+	if ruta != "-" {
+		f, err := os.Open(ruta)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	archivos, err := storage.DesdeEntorno()
+	if err != nil {
+		return err
+	}
+	resultado, err := services.NewBackupService(db, archivos).Restaurar(r, opts)
+	var errValidacion *backup.ErrorValidacion
+	if errors.As(err, &errValidacion) {
+		fmt.Fprintln(os.Stderr, "El respaldo no es válido; no se modificó la base de datos:")
+		for _, p := range errValidacion.Problemas {
+			fmt.Fprintln(os.Stderr, "  -", p)
+		}
+		return errors.New("respaldo inválido")
+	}
+	if err != nil {
+		return err
+	}
+
+	if resultado.Simulado {
+		fmt.Println("Simulación: no se guardaron cambios")
+	}
+	fmt.Printf("Respaldo del %s restaurado (%s)\n", resultado.Manifiesto.Creado.Local().Format("2006-01-02 15:04"), resultado.Modo)
+	for _, t := range resultado.Tablas {
+		fmt.Printf("  %-45s %5d insertadas  %5d existentes\n", t.Tabla, t.Insertadas, t.Existentes)
+	}
+	if !opts.SinArchivos && !opts.Simular {
+		fmt.Printf("Archivos: %d restaurados, %d ya existían\n", resultado.ArchivosRestaurados, resultado.ArchivosOmitidos)
+	}
+	return nil
+}
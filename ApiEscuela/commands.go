@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// comando es un subcomando de administración: `ApiEscuela <nombre> [opciones]`
+type comando struct {
+	Descripcion string
+	Ejecutar    func(db *gorm.DB, args []string) error
+}
+
+// comandos disponibles; sin argumentos se inicia el servidor
+var comandos = map[string]comando{
+	"seed":        {Descripcion: "Carga provincias, cantones, tipos de usuario y el administrador inicial", Ejecutar: ejecutarSeed},
+	"user":        {Descripcion: "create-admin | reset-password <usuario> | unlock <usuario>", Ejecutar: conSubcomandos("user", comandosUsuario)},
+	"codes":       {Descripcion: "purge-expired: elimina códigos de recuperación vencidos o usados", Ejecutar: conSubcomandos("codes", comandosCodigos)},
+	"files":       {Descripcion: "gc | index | migrate | variants: limpia los archivos subidos sin uso, los registra en la biblioteca, los copia al almacenamiento configurado o genera las variantes de las imágenes", Ejecutar: conSubcomandos("files", comandosArchivos)},
+	"comunicados": {Descripcion: "resend <id>: reenvía por correo un comunicado guardado", Ejecutar: conSubcomandos("comunicados", comandosComunicados)},
+	"stats":       {Descripcion: "dump: imprime en JSON los totales y estadísticas del sistema", Ejecutar: conSubcomandos("stats", comandosEstadisticas)},
+	"backup":      {Descripcion: "export | restore <archivo>: respaldo lógico de las tablas y los assets", Ejecutar: conSubcomandos("backup", comandosBackup)},
+}
+
+// conSubcomandos despacha `ApiEscuela <grupo> <subcomando> [opciones]`
+func conSubcomandos(grupo string, subcomandos map[string]func(db *gorm.DB, args []string) error) func(db *gorm.DB, args []string) error {
+	return func(db *gorm.DB, args []string) error {
+		if len(args) > 0 {
+			if ejecutar, ok := subcomandos[args[0]]; ok {
+				return ejecutar(db, args[1:])
+			}
+		}
+		nombres := make([]string, 0, len(subcomandos))
+		for nombre := range subcomandos {
+			nombres = append(nombres, nombre)
+		}
+		sort.Strings(nombres)
+		return fmt.Errorf("uso: ApiEscuela %s <%s>", grupo, strings.Join(nombres, "|"))
+	}
+}
+
+// buscarComando devuelve el subcomando pedido en los argumentos, si hay uno
+func buscarComando(args []string) (string, *comando, error) {
+	if len(args) == 0 {
+		return "", nil, nil
+	}
+	nombre := args[0]
+	if nombre == "help" || nombre == "-h" || nombre == "--help" {
+		imprimirAyuda()
+		os.Exit(0)
+	}
+	cmd, ok := comandos[nombre]
+	if !ok {
+		imprimirAyuda()
+		return nombre, nil, fmt.Errorf("comando desconocido: %s", nombre)
+	}
+	return nombre, &cmd, nil
+}
+
+func imprimirAyuda() {
+	nombres := make([]string, 0, len(comandos))
+	for nombre := range comandos {
+		nombres = append(nombres, nombre)
+	}
+	sort.Strings(nombres)
+
+	fmt.Fprintln(os.Stderr, "Uso: ApiEscuela [comando] [opciones]")
+	fmt.Fprintln(os.Stderr, "Sin comando se inicia el servidor HTTP. Comandos:")
+	for _, nombre := range nombres {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", nombre, comandos[nombre].Descripcion)
+	}
+	fmt.Fprintln(os.Stderr, "Use 'ApiEscuela <comando> -h' para ver las opciones de cada comando.")
+}
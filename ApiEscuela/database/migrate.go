@@ -0,0 +1,195 @@
+// Package database agrupa las migraciones del esquema compartidas por el servidor,
+// los comandos de administración y las pruebas de integración.
+package database
+
+import (
+	"ApiEscuela/models"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Models contiene todos los modelos en el orden en que deben migrarse
+var Models = []interface{}{
+	&models.Provincia{},
+	&models.Ciudad{},
+	&models.Persona{},
+	&models.TipoUsuario{},
+	&models.Usuario{},
+	&models.Institucion{},
+	&models.Estudiante{},
+	&models.EstudianteUniversitario{},
+	&models.AutoridadUTEQ{},
+	&models.Tematica{},
+	&models.Actividad{},
+	&models.ProgramaVisita{},
+	&models.DetalleAutoridadDetallesVisita{},
+	&models.VisitaDetalle{},
+	&models.Dudas{},
+	&models.VisitaDetalleEstudiantesUniversitarios{},
+	&models.CodigoUsuario{},
+	&models.Noticia{},
+	&models.Comunicado{},
+	&models.Media{},
+	&models.MediaReferencia{},
+	&models.EjecucionTarea{},
+	&models.Notificacion{},
+	&models.PreferenciaNotificacion{},
+	&models.Webhook{},
+	&models.EntregaWebhook{},
+}
+
+// AutoMigrate ejecuta la automigración de todos los modelos
+func AutoMigrate(db *gorm.DB) error {
+	return db.AutoMigrate(Models...)
+}
+
+// MigrarColumnaExpiraEn permite valores NULL en la columna expira_en de codigosusuarios
+func MigrarColumnaExpiraEn(db *gorm.DB) error {
+	// Primero, agregar la columna estado si no existe
+	if err := db.Exec("ALTER TABLE codigosusuarios ADD COLUMN IF NOT EXISTS estado VARCHAR(20) DEFAULT 'valido'").Error; err != nil {
+		return err
+	}
+
+	// Crear índice para estado si no existe
+	if err := db.Exec("CREATE INDEX IF NOT EXISTS idx_codigosusuarios_estado ON codigosusuarios(estado)").Error; err != nil {
+		return err
+	}
+
+	// Modificar la columna expira_en para permitir NULL
+	if err := db.Exec("ALTER TABLE codigosusuarios ALTER COLUMN expira_en DROP NOT NULL").Error; err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ConfiguracionBusqueda es la configuración de texto completo en español que ignora las tildes
+const ConfiguracionBusqueda = "es_unaccent"
+
+// columnasBusqueda define, por modelo, el texto indexado en la columna busqueda: la primera
+// expresión recibe el peso A y la segunda, si existe, el peso B
+var columnasBusqueda = []struct {
+	modelo      interface{}
+	expresiones []string
+}{
+	{&models.Noticia{}, []string{"titulo", "descripcion"}},
+	{&models.Dudas{}, []string{"pregunta", "respuesta"}},
+	// El mensaje es HTML: se indexa sin etiquetas
+	{&models.Comunicado{}, []string{"asunto", `regexp_replace(mensaje, '<[^>]*>', ' ', 'g')`}},
+	{&models.Tematica{}, []string{"nombre", "descripcion"}},
+	{&models.Actividad{}, []string{"actividad"}},
+}
+
+// MigrarBusquedaTexto crea la configuración es_unaccent (diccionario español sin tildes) y agrega a
+// noticias, dudas, comunicados, temáticas y actividades la columna generada busqueda (tsvector)
+// con su índice GIN
+func MigrarBusquedaTexto(db *gorm.DB) error {
+	if err := db.Exec("CREATE EXTENSION IF NOT EXISTS unaccent").Error; err != nil {
+		return err
+	}
+	// CREATE TEXT SEARCH CONFIGURATION no admite IF NOT EXISTS
+	if err := db.Exec(fmt.Sprintf(`DO $$
+BEGIN
+	IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = '%[1]s') THEN
+		CREATE TEXT SEARCH CONFIGURATION %[1]s (COPY = spanish);
+		ALTER TEXT SEARCH CONFIGURATION %[1]s
+			ALTER MAPPING FOR hword, hword_part, word WITH unaccent, spanish_stem;
+	END IF;
+END
+$$`, ConfiguracionBusqueda)).Error; err != nil {
+		return err
+	}
+
+	pesos := []string{"A", "B"}
+	for _, c := range columnasBusqueda {
+		partes := make([]string, len(c.expresiones))
+		for i, expr := range c.expresiones {
+			partes[i] = fmt.Sprintf("setweight(to_tsvector('%s', coalesce(%s, '')), '%s')", ConfiguracionBusqueda, expr, pesos[i])
+		}
+		tabla, err := tablaDe(db, c.modelo)
+		if err != nil {
+			return err
+		}
+		sql := fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS busqueda tsvector GENERATED ALWAYS AS (%s) STORED",
+			tabla, strings.Join(partes, " || "))
+		if err := db.Exec(sql).Error; err != nil {
+			return fmt.Errorf("%s: %w", tabla, err)
+		}
+		if err := db.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%[1]s_busqueda ON %[1]s USING GIN (busqueda)", tabla)).Error; err != nil {
+			return fmt.Errorf("%s: %w", tabla, err)
+		}
+	}
+	return nil
+}
+
+// indicesTrigramas son los índices de la búsqueda global y el autocompletado, por modelo: la
+// expresión indexada con pg_trgm, tal como la usan las consultas de BusquedaRepository
+var indicesTrigramas = []struct {
+	modelo    interface{}
+	nombre    string
+	expresion string
+}{
+	{&models.Persona{}, "nombre_trgm", "sin_tildes(nombre)"},
+	{&models.Persona{}, "correo_trgm", "lower(correo)"},
+	{&models.Institucion{}, "nombre_trgm", "sin_tildes(nombre)"},
+}
+
+// MigrarBusquedaTrigramas crea las extensiones pg_trgm y unaccent, la función inmutable sin_tildes
+// (unaccent y minúsculas, que se puede indexar) y los índices de trigramas y de prefijo de cédula
+func MigrarBusquedaTrigramas(db *gorm.DB) error {
+	for _, extension := range []string{"pg_trgm", "unaccent"} {
+		if err := db.Exec("CREATE EXTENSION IF NOT EXISTS " + extension).Error; err != nil {
+			return err
+		}
+	}
+	// unaccent() no es IMMUTABLE porque depende del search_path; con el diccionario explícito sí
+	if err := db.Exec(`CREATE OR REPLACE FUNCTION sin_tildes(texto text) RETURNS text
+LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT
+AS $$ SELECT public.unaccent('public.unaccent'::regdictionary, lower(texto)) $$`).Error; err != nil {
+		return err
+	}
+	for _, i := range indicesTrigramas {
+		tabla, err := tablaDe(db, i.modelo)
+		if err != nil {
+			return err
+		}
+		sql := fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%[1]s_%[2]s ON %[1]s USING GIN (%[3]s gin_trgm_ops)", tabla, i.nombre, i.expresion)
+		if err := db.Exec(sql).Error; err != nil {
+			return fmt.Errorf("%s: %w", tabla, err)
+		}
+	}
+	// Con una collation distinta de C, LIKE 'prefijo%' solo usa un índice B-tree con text_pattern_ops
+	personas, err := tablaDe(db, &models.Persona{})
+	if err != nil {
+		return err
+	}
+	return db.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS idx_%[1]s_cedula_prefijo ON %[1]s (cedula text_pattern_ops)", personas)).Error
+}
+
+// tablaDe devuelve el nombre de la tabla del modelo según la estrategia de nombres de GORM
+func tablaDe(db *gorm.DB, modelo interface{}) (string, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(modelo); err != nil {
+		return "", err
+	}
+	return stmt.Schema.Table, nil
+}
+
+// Migrate ejecuta la automigración y las migraciones manuales
+func Migrate(db *gorm.DB) error {
+	if err := AutoMigrate(db); err != nil {
+		return fmt.Errorf("error en la automigración: %w", err)
+	}
+	if err := MigrarColumnaExpiraEn(db); err != nil {
+		return fmt.Errorf("error al migrar tabla de códigos: %w", err)
+	}
+	if err := MigrarBusquedaTexto(db); err != nil {
+		return fmt.Errorf("error al migrar la búsqueda de texto: %w", err)
+	}
+	if err := MigrarBusquedaTrigramas(db); err != nil {
+		return fmt.Errorf("error al migrar los índices de trigramas: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,555 @@
+package docs
+
+import (
+	"fmt"
+	"strings"
+
+	"ApiEscuela/backup"
+	"ApiEscuela/eventos"
+	"ApiEscuela/handlers"
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+	"ApiEscuela/services"
+	"ApiEscuela/tareas"
+)
+
+// resource describe un grupo de rutas y el modelo que manipula
+type resource struct {
+	Prefix      string
+	Tag         string
+	Description string
+	Model       interface{}
+	// VersionModels reemplaza el modelo en las versiones que redefinen el recurso
+	VersionModels map[string]interface{}
+	// Envelope indica que el handler responde con SendSuccess/SendError ({success, data, ...})
+	Envelope bool
+	Public   bool
+}
+
+// resources es el catálogo de grupos de rutas. El prefijo más largo gana.
+var resources = []resource{
+	{Prefix: "/auth", Tag: "Autenticación", Description: "Login, registro y recuperación de contraseña", Public: true},
+	{Prefix: "/api/auth", Tag: "Autenticación", Description: "Operaciones sobre la sesión del usuario autenticado"},
+	{Prefix: "/api/files", Tag: "Archivos", Description: "Descarga de archivos subidos; los privados requieren token", Public: true},
+	{Prefix: "/api/upload", Tag: "Archivos", Description: "Subida de imágenes, videos y documentos"},
+	{Prefix: "/api/media", Tag: "Archivos", Description: "Biblioteca de archivos subidos: metadatos, referencias y visibilidad", Model: models.Media{}},
+	{Prefix: "/api/estudiantes", Tag: "Estudiantes", Description: "Estudiantes de instituciones educativas", Model: models.Estudiante{}, Envelope: true},
+	{Prefix: "/api/personas", Tag: "Personas", Description: "Información básica de personas", Model: models.Persona{}, Envelope: true},
+	{Prefix: "/api/provincias", Tag: "Provincias", Description: "Provincias del país", Model: models.Provincia{}},
+	{Prefix: "/api/ciudades", Tag: "Ciudades", Description: "Ciudades del país", Model: models.Ciudad{}},
+	{Prefix: "/api/instituciones", Tag: "Instituciones", Description: "Instituciones educativas visitantes", Model: models.Institucion{}},
+	{Prefix: "/api/tipos-usuario", Tag: "Tipos de usuario", Description: "Roles del sistema", Model: models.TipoUsuario{}},
+	{Prefix: "/api/usuarios", Tag: "Usuarios", Description: "Cuentas de acceso", Model: models.Usuario{}},
+	{Prefix: "/api/estudiantes-universitarios", Tag: "Estudiantes universitarios", Description: "Estudiantes de la UTEQ", Model: models.EstudianteUniversitario{}, Envelope: true},
+	{Prefix: "/api/autoridades-uteq", Tag: "Autoridades UTEQ", Description: "Autoridades de la UTEQ", Model: models.AutoridadUTEQ{}, Envelope: true},
+	{Prefix: "/api/tematicas", Tag: "Temáticas", Description: "Temáticas de actividades", Model: models.Tematica{}},
+	{Prefix: "/api/actividades", Tag: "Actividades", Description: "Actividades disponibles en las visitas", Model: models.Actividad{}, Envelope: true},
+	{Prefix: "/api/programas-visita", Tag: "Programas de visita", Description: "Visitas programadas", Model: models.ProgramaVisita{}},
+	{Prefix: "/api/detalle-autoridad-detalles-visita", Tag: "Autoridades por visita", Description: "Autoridades asignadas a programas de visita", Model: models.DetalleAutoridadDetallesVisita{}},
+	{Prefix: "/api/visita-detalles", Tag: "Actividades por visita", Description: "Actividades programadas en cada visita", Model: models.VisitaDetalle{}},
+	{Prefix: "/api/dudas", Tag: "Dudas", Description: "Preguntas de estudiantes y sus respuestas", Model: models.Dudas{}, Envelope: true},
+	{Prefix: "/api/visita-detalle-estudiantes-universitarios", Tag: "Estudiantes por visita", Description: "Estudiantes universitarios asignados a programas de visita", Model: models.VisitaDetalleEstudiantesUniversitarios{}},
+	{Prefix: "/api/noticias", Tag: "Noticias", Description: "Noticias publicadas", Model: models.Noticia{}},
+	{Prefix: "/api/codigos", Tag: "Códigos", Description: "Códigos temporales (OTP) de usuarios", Model: models.CodigoUsuario{}, Envelope: true},
+	{Prefix: "/api/comunicados", Tag: "Comunicados", Description: "Mensajería masiva por correo y WhatsApp", Model: models.Comunicado{},
+		VersionModels: map[string]interface{}{"v2": handlers.ComunicadoV2{}}},
+	{Prefix: "/api/whatsapp", Tag: "WhatsApp", Description: "Proxy hacia el servicio de WhatsApp"},
+	{Prefix: "/api/notificaciones", Tag: "Notificaciones", Description: "Centro de notificaciones del usuario autenticado y sus canales de envío", Model: models.Notificacion{}, Envelope: true},
+	{Prefix: "/api/webhooks", Tag: "Webhooks", Description: "Suscripciones de sistemas externos a eventos de la API, con entregas firmadas (HMAC-SHA256) y reintentos; solo para el tipo de usuario Administrador", Model: models.Webhook{}, Envelope: true},
+	{Prefix: "/api/search", Tag: "Búsqueda", Description: "Búsqueda global en personas, instituciones, programas de visita y noticias, con autocompletado por trigramas", Envelope: true},
+	{Prefix: "/api/exportaciones", Tag: "Exportaciones", Description: "Listas exportables a CSV, XLSX y PDF y sus columnas", Envelope: true},
+	{Prefix: "/api/analitica", Tag: "Analítica", Description: "Agregados del dashboard por rango de fechas, calculados en el servidor y guardados en caché", Envelope: true},
+	{Prefix: "/api/eventos", Tag: "Eventos", Description: "Eventos en tiempo real del usuario autenticado por Server-Sent Events"},
+	{Prefix: "/api/papelera", Tag: "Papelera", Description: "Registros eliminados lógicamente de todas las entidades, para restaurarlos o eliminarlos definitivamente; solo para el tipo de usuario Administrador", Envelope: true},
+	{Prefix: "/api/admin", Tag: "Administración", Description: "Respaldos, restauración y tareas programadas; solo para el tipo de usuario Administrador"},
+	{Prefix: "/", Tag: "Sistema", Description: "Estado del servicio", Public: true},
+}
+
+// singleLookups son los handlers GET que devuelven un único registro en lugar de una lista
+var singleLookups = map[string]bool{
+	"PersonaHandler.GetPersonaByCedula":                                  true,
+	"ProvinciaHandler.GetProvinciaByNombre":                              true,
+	"CiudadHandler.GetCiudadByNombre":                                    true,
+	"TipoUsuarioHandler.GetTipoUsuarioByNombre":                          true,
+	"UsuarioHandler.GetUsuarioByUsername":                                true,
+	"EstudianteUniversitarioHandler.GetEstudianteUniversitarioByPersona": true,
+	"AutoridadUTEQHandler.GetAutoridadUTEQByPersona":                     true,
+}
+
+// operationSpec complementa lo que se infiere de la ruta. Los campos vacíos conservan el valor inferido.
+type operationSpec struct {
+	Summary  string
+	Request  interface{} // Valor de ejemplo o *Schema para application/json
+	Form     *Schema     // Esquema multipart/form-data
+	Response interface{} // Valor de ejemplo o *Schema
+	Status   int
+	List     bool
+	Raw      bool // La respuesta no usa el envoltorio de SendSuccess
+	Query    []Parameter
+	// ContentType de la respuesta cuando no es application/json, p. ej. text/event-stream
+	ContentType string
+}
+
+var (
+	messageSchema = object(map[string]*Schema{"message": str("Mensaje de confirmación")})
+	freeObject    = &Schema{Type: "object", Description: "Objeto JSON devuelto por el servicio"}
+)
+
+var comunicadoForm = object(map[string]*Schema{
+	"asunto":        str(""),
+	"destinatarios": str("JSON serializado con el esquema DestinatarioInfo"),
+	"mensaje":       str("Contenido HTML"),
+	"usuario_id":    integer(""),
+	"canal":         {Type: "string", Enum: []string{"correo", "whatsapp"}},
+	"enviado_a":     integer("Conteo de envíos realizados desde el frontend (solo WhatsApp)"),
+	"adjuntos":      arrayOf(&Schema{Type: "string", Format: "binary"}),
+}, "asunto", "destinatarios", "mensaje", "usuario_id")
+
+// comunicadoCreado es la respuesta de la creación de un comunicado con el esquema de la versión indicada
+func comunicadoCreado(schema string) *Schema {
+	return object(map[string]*Schema{
+		"success": boolean(""), "comunicado": refTo(schema), "enviados": integer(""), "total": integer(""),
+		"errores": arrayOf(str("")),
+	})
+}
+
+// exportacionQuery son los parámetros de las listas exportables; el formato también se puede
+// pedir con el encabezado Accept (text/csv, application/pdf o el tipo de XLSX)
+var exportacionQuery = []Parameter{
+	queryParam("format", "json (por defecto), csv, xlsx o pdf; los archivos se descargan como adjunto", false),
+	queryParam("columnas", "Claves de las columnas a exportar separadas por comas, en orden; por defecto todas (ver /api/exportaciones)", false),
+}
+
+// analiticaQuery son los parámetros comunes de la analítica
+var analiticaQuery = []Parameter{
+	queryParam("desde", "Fecha inicial (YYYY-MM-DD); por defecto el primer día del mes, once meses atrás", false),
+	queryParam("hasta", "Fecha final incluida (YYYY-MM-DD); por defecto hoy", false),
+}
+
+// analiticaAgruparQuery es el período de las series de la analítica
+var analiticaAgruparQuery = queryParam("agrupar", "dia (rango de hasta 366 días), semana, mes (por defecto) o anio", false)
+
+// analiticaLimiteQuery es la cantidad de temáticas o actividades de los rankings
+var analiticaLimiteQuery = queryParam("limite", "Cantidad de temáticas o actividades (1-50, por defecto 10)", false)
+
+// rangoFechaQuery son los parámetros de GET /api/programas-visita/rango-fecha
+var rangoFechaQuery = []Parameter{queryParam("inicio", "Fecha inicial (YYYY-MM-DD)", true), queryParam("fin", "Fecha final (YYYY-MM-DD)", true)}
+
+func queryParam(name, description string, required bool) Parameter {
+	return Parameter{Name: name, In: "query", Required: required, Description: description, Schema: &Schema{Type: "string"}}
+}
+
+// archivoSubido es la respuesta de una subida terminada
+var archivoSubido = object(map[string]*Schema{
+	"message": str(""), "url": str("URL pública del archivo"), "tipo": str("image, video o document"), "tamano": integer("Bytes"),
+	"sha256": str("Hash del archivo subido"), "duplicado": boolean("Ya existía un archivo idéntico y se devolvió ese"),
+	"id": integer("ID en la biblioteca de archivos"), "visibilidad": str("publico o privado"),
+	"ancho": integer("Solo imágenes"), "alto": integer("Solo imágenes"), "blurhash": str("Solo imágenes"),
+})
+
+// eventosStream describe el cuerpo de GET /api/eventos: cada mensaje lleva id, event (el tipo) y
+// data (el evento en JSON)
+var eventosStream = &Schema{
+	Type: "string",
+	Description: fmt.Sprintf("Mensajes Server-Sent Events; event es uno de: %s. data es un JSON con id, tipo, fecha y datos",
+		strings.Join([]string{eventos.WhatsAppEstado, eventos.WhatsAppQR, eventos.WhatsAppCola, eventos.ComunicadoProgreso,
+			eventos.ImportacionProgreso, eventos.NotificacionNueva}, ", ")),
+}
+
+// elementoPapeleraAfectado es la respuesta de restaurar o purgar un registro de la papelera
+var elementoPapeleraAfectado = object(map[string]*Schema{
+	"message": str(""), "entidad": str("Nombre del recurso, p. ej. instituciones"), "id": integer(""),
+})
+
+// responderDuda es la respuesta de una autoridad a una duda
+var responderDuda = operationSpec{
+	Request:  object(map[string]*Schema{"respuesta": str(""), "autoridad_uteq_id": integer("")}, "respuesta", "autoridad_uteq_id"),
+	Response: object(map[string]*Schema{"message": str(""), "duda_id": integer("")}),
+}
+
+// duracionQuery filtran las actividades por duración
+var duracionQuery = []Parameter{
+	queryParam("min", "Duración mínima en minutos", false),
+	queryParam("max", "Duración máxima en minutos", false),
+}
+
+// variantesQuery eligen la variante redimensionada de una imagen
+var variantesQuery = []Parameter{
+	queryParam("size", "Variante: thumb (320 px), medium (800 px) o large (1600 px)", false),
+	queryParam("w", "Ancho mínimo en píxeles; se sirve la variante más pequeña que lo alcanza", false),
+}
+
+// operationSpecs contiene las operaciones que no siguen el patrón CRUD del recurso.
+// La clave es "MÉTODO /ruta"; las rutas de la versión base se escriben sin versión
+// (/api/estudiantes) y las de versiones posteriores con ella (/api/v2/comunicados).
+var operationSpecs = map[string]operationSpec{
+	"GET /": {
+		Summary:  "Página de bienvenida",
+		Response: object(map[string]*Schema{"message": str(""), "version": str(""), "status": str("")}),
+	},
+	"GET /health": {
+		Summary:  "Estado de salud del servicio",
+		Response: object(map[string]*Schema{"status": str(""), "database": str("")}),
+	},
+
+	// Autenticación
+	"POST /auth/login": {Request: services.LoginRequest{}, Response: services.LoginResponse{}, Raw: true},
+	"POST /auth/register": {
+		Request:  services.RegisterRequest{},
+		Status:   201,
+		Response: object(map[string]*Schema{"message": str(""), "usuario": refTo("Usuario")}),
+	},
+	"POST /auth/validate-token": {
+		Request: object(map[string]*Schema{"token": str("JWT a validar")}, "token"),
+		Response: object(map[string]*Schema{
+			"valid": boolean(""), "user_id": integer(""), "username": str(""), "tipo_usuario_id": integer(""),
+		}),
+	},
+	"POST /auth/recover-password": {
+		Request:  object(map[string]*Schema{"cedula": str("Cédula de la persona")}, "cedula"),
+		Response: messageSchema,
+	},
+	"POST /auth/verify-code": {
+		Request: object(map[string]*Schema{"codigo": str("Código OTP de 6 dígitos")}, "codigo"),
+		Response: envelope(object(map[string]*Schema{
+			"estado": str(""), "usuario_id": integer(""), "cedula": str(""), "codigo_id": integer(""), "message": str(""),
+		})),
+		Raw: true,
+	},
+	"POST /auth/reset-password": {
+		Request: object(map[string]*Schema{
+			"codigo_id": integer(""), "usuario_id": integer(""), "clave": str("Nueva contraseña (6-100 caracteres, sin espacios)"),
+		}, "codigo_id", "usuario_id", "clave"),
+		Response: envelope(object(map[string]*Schema{"message": str(""), "usuario_id": integer(""), "codigo_id": integer("")})),
+		Raw:      true,
+	},
+	"GET /api/auth/profile": {
+		Response: object(map[string]*Schema{"user_id": integer(""), "username": str(""), "tipo_usuario_id": integer("")}),
+	},
+	"POST /api/auth/change-password": {
+		Request:  object(map[string]*Schema{"old_password": str(""), "new_password": str("")}, "old_password", "new_password"),
+		Response: messageSchema,
+	},
+	"POST /api/auth/refresh-token": {
+		Response: object(map[string]*Schema{"token": str(""), "message": str("")}),
+	},
+
+	// Archivos
+	"POST /api/upload": {
+		Form: object(map[string]*Schema{
+			"file":        {Type: "string", Format: "binary"},
+			"visibilidad": str("publico (por defecto) o privado: solo usuarios autenticados pueden descargarlo"),
+		}, "file"),
+		Response: archivoSubido,
+	},
+	"POST /api/upload/sesiones": {
+		Summary:  "Inicia una subida por partes (reanudable) para archivos de hasta 50MB",
+		Request:  services.NuevaSubida{},
+		Response: services.UploadSession{},
+		Status:   201,
+		Raw:      true,
+	},
+	"GET /api/upload/sesiones/:id": {
+		Summary:  "Estado de una subida por partes; el encabezado Upload-Offset indica desde dónde continuar",
+		Response: services.UploadSession{},
+		Raw:      true,
+	},
+	"PATCH /api/upload/sesiones/:id": {
+		Summary:  "Envía un fragmento (application/offset+octet-stream, máximo 2MB) en la posición del encabezado Upload-Offset; Upload-Checksum (sha256 en base64) es opcional",
+		Response: services.UploadSession{},
+		Raw:      true,
+	},
+	"POST /api/upload/sesiones/:id/finalizar": {
+		Summary:  "Verifica la subida completa y la guarda en el almacenamiento",
+		Response: archivoSubido,
+		Raw:      true,
+	},
+	"DELETE /api/upload/sesiones/:id": {
+		Summary: "Descarta una subida por partes",
+		Status:  204,
+		Raw:     true,
+	},
+	"GET /api/upload/test": {
+		Summary:  "Verifica que el token permite subir archivos",
+		Response: object(map[string]*Schema{"message": str(""), "user_id": integer(""), "username": str("")}),
+	},
+	"GET /api/files/:tipo/:nombre": {
+		Summary:  "Descarga un archivo; en imágenes, size o w eligen una variante redimensionada (WebP si Accept lo incluye)",
+		Response: &Schema{Type: "string", Format: "binary"},
+		Query:    variantesQuery,
+	},
+	"GET /api/files/:tipo/:subcarpeta/:nombre": {
+		Response: &Schema{Type: "string", Format: "binary"},
+	},
+
+	"GET /api/media": {
+		Summary: "Lista y busca los archivos subidos, del más nuevo al más antiguo",
+		Query: []Parameter{
+			queryParam("q", "Texto a buscar en el nombre original o la ruta", false),
+			queryParam("categoria", "image, video o document", false),
+			queryParam("visibilidad", "publico o privado", false),
+			queryParam("usuario_id", "Usuario que lo subió", false),
+			queryParam("sin_referencias", "true para listar solo los que ninguna noticia o comunicado usa", false),
+		},
+	},
+	"PUT /api/media/:id/visibilidad": {
+		Summary:  "Hace público o privado un archivo (Administrador)",
+		Request:  object(map[string]*Schema{"visibilidad": str("publico o privado")}, "visibilidad"),
+		Response: refTo("Media"),
+	},
+	"DELETE /api/media/:id": {
+		Summary:  "Elimina un archivo y sus variantes (Administrador); responde 409 si está en uso",
+		Query:    []Parameter{queryParam("forzar", "true para eliminarlo aunque lo usen noticias o comunicados", false)},
+		Response: messageSchema,
+	},
+
+	// Estudiantes
+	"POST /api/estudiantes/bulk": {
+		Request: object(map[string]*Schema{"estudiantes": arrayOf(refTo("BulkEstudianteRequest"))}, "estudiantes"),
+		Response: object(map[string]*Schema{
+			"success":        boolean(""),
+			"total":          integer(""),
+			"total_exitosos": integer(""),
+			"total_fallidos": integer(""),
+			"exitosos":       arrayOf(refTo("BulkEstudianteResult")),
+			"fallidos":       arrayOf(refTo("BulkEstudianteResult")),
+		}),
+		Raw: true,
+	},
+
+	// Filtros por query string
+	"GET /api/actividades/duracion":    {Query: duracionQuery},
+	"GET /api/v2/actividades/duracion": {Query: duracionQuery},
+	"GET /api/programas-visita/rango-fecha": {
+		Query: append(append([]Parameter{}, rangoFechaQuery...), exportacionQuery...),
+	},
+
+	// Listas exportables a CSV, XLSX y PDF
+	"GET /api/estudiantes":                                                                   {Query: exportacionQuery},
+	"GET /api/estudiantes/ciudad/:ciudad_id":                                                 {Query: exportacionQuery},
+	"GET /api/estudiantes/institucion/:institucion_id":                                       {Query: exportacionQuery},
+	"GET /api/programas-visita":                                                              {Query: exportacionQuery},
+	"GET /api/programas-visita/institucion/:institucion_id":                                  {Query: exportacionQuery},
+	"GET /api/visita-detalle-estudiantes-universitarios":                                     {Query: exportacionQuery},
+	"GET /api/visita-detalle-estudiantes-universitarios/programa-visita/:programa_visita_id": {Query: exportacionQuery},
+	"GET /api/dudas":                  {Query: exportacionQuery},
+	"GET /api/dudas/sin-responder":    {Query: exportacionQuery},
+	"GET /api/dudas/respondidas":      {Query: exportacionQuery},
+	"GET /api/dudas/sin-asignar":      {Query: exportacionQuery},
+	"GET /api/v2/dudas":               {Query: exportacionQuery},
+	"GET /api/v2/dudas/sin-responder": {Query: exportacionQuery},
+	"GET /api/v2/dudas/respondidas":   {Query: exportacionQuery},
+	"GET /api/v2/dudas/sin-asignar":   {Query: exportacionQuery},
+	"GET /api/comunicados":            {Query: exportacionQuery},
+	"GET /api/v2/comunicados":         {Query: exportacionQuery},
+	"GET /api/exportaciones":          {Response: handlers.ListasExportables{}},
+
+	// Dudas
+	"PUT /api/dudas/:duda_id/responder":    responderDuda,
+	"PUT /api/v2/dudas/:duda_id/responder": responderDuda,
+
+	// Códigos
+	"POST /api/codigos/verify": {
+		Request: object(map[string]*Schema{"codigo": str("")}, "codigo"),
+		Response: object(map[string]*Schema{
+			"message": str(""), "codigo_id": integer(""), "usuario_id": integer(""), "estado": str(""),
+			"expira_en": {Type: "string", Format: "date-time", Nullable: true},
+		}),
+	},
+
+	// Comunicados
+	"POST /api/comunicados": {
+		Form:     comunicadoForm,
+		Status:   201,
+		Response: comunicadoCreado("Comunicado"),
+	},
+	"POST /api/v2/comunicados": {
+		Form:     comunicadoForm,
+		Status:   201,
+		Response: comunicadoCreado("ComunicadoV2"),
+	},
+
+	// Búsqueda de texto completo en v2: cada resultado envuelve el registro con su relevancia y
+	// fragmento. En v1 se infiere la lista de registros, del más relevante al menos relevante.
+	"GET /api/v2/actividades/nombre/:nombre":  {Response: repositories.ResultadoBusqueda[models.Actividad]{}, List: true},
+	"GET /api/v2/tematicas/nombre/:nombre":    {Response: repositories.ResultadoBusqueda[models.Tematica]{}, List: true},
+	"GET /api/v2/dudas/buscar/:termino":       {Response: repositories.ResultadoBusqueda[models.Dudas]{}, List: true},
+	"GET /api/v2/noticias/buscar/:termino":    {Response: repositories.ResultadoBusqueda[models.Noticia]{}, List: true},
+	"GET /api/v2/comunicados/buscar/:termino": {Response: repositories.ResultadoBusqueda[handlers.ComunicadoV2]{}, List: true},
+
+	// Administración
+	"GET /api/admin/backup": {
+		Query:    []Parameter{queryParam("sin_archivos", "true para no incluir la carpeta assets", false)},
+		Response: &Schema{Type: "string", Format: "binary", Description: "Respaldo .tar.gz"},
+	},
+	"POST /api/admin/restore": {
+		Form: object(map[string]*Schema{
+			"archivo":      {Type: "string", Format: "binary", Description: "Respaldo .tar.gz generado por GET /api/admin/backup"},
+			"reemplazar":   boolean("true para vaciar las tablas y conservar los IDs del respaldo"),
+			"sin_archivos": boolean("true para no restaurar los assets"),
+			"simular":      boolean("true para validar sin guardar cambios"),
+		}, "archivo"),
+		Response: envelope(refTo("Resultado")),
+		Raw:      true,
+	},
+	"GET /api/admin/tareas": {
+		Summary: "Tareas programadas con su próxima ejecución, la última y el último error",
+		Response: object(map[string]*Schema{
+			"instancia": str("Host y PID de la instancia que responde"),
+			"lider":     boolean("true si esta instancia ejecuta las tareas programadas"),
+			"tareas":    arrayOf(refTo("Estado")),
+		}),
+	},
+	"GET /api/admin/tareas/:nombre/ejecuciones": {
+		Summary:  "Historial de ejecuciones de una tarea, de la más reciente a la más antigua",
+		Query:    []Parameter{queryParam("limite", "Cantidad máxima de ejecuciones (1-500, por defecto 20)", false)},
+		Response: arrayOf(refTo("EjecucionTarea")),
+	},
+	"POST /api/admin/tareas/:nombre/ejecutar": {
+		Summary:  "Ejecuta ya la tarea en esta instancia; responde 409 si ya se está ejecutando",
+		Status:   202,
+		Response: refTo("EjecucionTarea"),
+	},
+
+	// Eventos en tiempo real
+	"GET /api/eventos": {
+		Summary: "Transmite los eventos del usuario autenticado (text/event-stream) hasta que el cliente cierra la conexión",
+		Query: []Parameter{
+			queryParam("access_token", "JWT para EventSource, que no puede enviar el encabezado Authorization", false),
+			queryParam("tipos", "Tipos de evento separados por comas; por defecto todos", false),
+			queryParam("last_event_id", "ID del último evento recibido, si no se envía el encabezado Last-Event-ID", false),
+			{Name: "Last-Event-ID", In: "header", Description: "ID del último evento recibido; se reenvían los eventos posteriores que sigan en el historial", Schema: &Schema{Type: "string"}},
+		},
+		Response:    eventosStream,
+		ContentType: "text/event-stream",
+	},
+
+	// Papelera
+	"GET /api/papelera": {
+		Response: services.ResumenPapelera{},
+		List:     true,
+		Raw:      true,
+	},
+	"GET /api/papelera/:entidad": {
+		Response: repositories.ElementoPapelera{},
+		List:     true,
+		Raw:      true,
+	},
+	"PUT /api/papelera/:entidad/:id/restore": {Response: elementoPapeleraAfectado},
+	"DELETE /api/papelera/:entidad/:id": {
+		Summary:  "Elimina definitivamente un registro de la papelera; responde 409 si otros registros, incluso eliminados, lo usan",
+		Response: elementoPapeleraAfectado,
+	},
+
+	// Notificaciones
+	"GET /api/notificaciones": {
+		Summary: "Notificaciones del usuario autenticado, de la más reciente a la más antigua",
+		Query: []Parameter{
+			queryParam("no_leidas", "true para listar solo las notificaciones sin leer", false),
+			queryParam("limite", "Cantidad máxima de notificaciones (1-200, por defecto 50)", false),
+		},
+	},
+	"GET /api/notificaciones/no-leidas": {
+		Summary:  "Cantidad de notificaciones sin leer del usuario autenticado",
+		Response: object(map[string]*Schema{"no_leidas": integer("Notificaciones sin leer")}),
+	},
+	"PUT /api/notificaciones/leidas": {
+		Summary: "Marca como leídas todas las notificaciones del usuario autenticado",
+		Response: object(map[string]*Schema{
+			"message":  str(""),
+			"marcadas": integer("Notificaciones que estaban sin leer"),
+		}),
+	},
+	"GET /api/notificaciones/preferencias": {
+		Summary:  "Canales por los que el usuario recibe sus notificaciones; sin preferencias guardadas, solo en la aplicación",
+		Response: refTo("PreferenciaNotificacion"),
+	},
+	"PUT /api/notificaciones/preferencias": {
+		Summary:  "Cambia los canales de notificación; los campos omitidos conservan su valor",
+		Request:  handlers.PreferenciasRequest{},
+		Response: refTo("PreferenciaNotificacion"),
+	},
+
+	// Webhooks
+	"GET /api/webhooks/eventos": {
+		Summary:  "Catálogo de eventos que se pueden suscribir",
+		Response: arrayOf(refTo("EventoWebhook")),
+	},
+	"POST /api/webhooks": {
+		Summary:  "Crea una suscripción; la respuesta es la única que incluye el secreto de la firma",
+		Request:  handlers.WebhookRequest{},
+		Response: services.WebhookCreado{},
+		Status:   201,
+	},
+	"PUT /api/webhooks/:id":    {Request: handlers.WebhookRequest{}},
+	"DELETE /api/webhooks/:id": {Response: messageSchema},
+	"POST /api/webhooks/:id/prueba": {
+		Summary:  "Envía un evento webhook.ping y devuelve el resultado de la entrega",
+		Response: refTo("EntregaWebhook"),
+	},
+	"GET /api/webhooks/:id/entregas": {
+		Summary:  "Registro de entregas de una suscripción, las más recientes primero",
+		Response: arrayOf(refTo("EntregaWebhook")),
+		Query:    []Parameter{queryParam("limite", "Cantidad máxima de entregas (1-500, por defecto 50)", false)},
+	},
+	"POST /api/webhooks/entregas/:id/reenviar": {
+		Summary:  "Reenvía el cuerpo de una entrega como una entrega nueva y devuelve su resultado",
+		Response: refTo("EntregaWebhook"),
+	},
+
+	// Búsqueda global
+	"GET /api/search": {
+		Summary:  "Busca en personas, instituciones, programas de visita y noticias; los resultados se agrupan por tipo",
+		Response: services.ResultadoBusquedaGlobal{},
+		Query: []Parameter{
+			queryParam("q", "Texto a buscar (2-100 caracteres): nombre, inicio de la cédula, correo, teléfono (4 dígitos o más) o fecha de la visita (2006-01-02 o 02/01/2006)", true),
+			queryParam("tipos", "Tipos separados por comas: persona, institucion, programa_visita, noticia; por defecto todos", false),
+			queryParam("limite", "Resultados por tipo (1-50; por defecto 5, o 10 al autocompletar)", false),
+			queryParam("modo", "completa (por defecto) o autocompletar: por nombre y tolerante a errores de escritura", false),
+		},
+	},
+
+	// Analítica
+	"GET /api/analitica/panel": {
+		Response: services.PanelAnalitica{},
+		Query: append(append([]Parameter{}, analiticaQuery...), analiticaAgruparQuery,
+			queryParam("por", "Dimensión de los estudiantes alcanzados: provincia, ciudad, institucion (por defecto) o especialidad", false),
+			analiticaLimiteQuery),
+	},
+	"GET /api/analitica/visitas": {Response: services.SerieVisitas{}, Query: append(append([]Parameter{}, analiticaQuery...), analiticaAgruparQuery)},
+	"GET /api/analitica/estudiantes": {
+		Response: services.EstudiantesAlcanzados{},
+		Query: append(append([]Parameter{}, analiticaQuery...),
+			queryParam("por", "provincia, ciudad, institucion (por defecto) o especialidad", false)),
+	},
+	"GET /api/analitica/tematicas":   {Response: services.RankingAnalitica{}, Query: append(append([]Parameter{}, analiticaQuery...), analiticaLimiteQuery)},
+	"GET /api/analitica/actividades": {Response: services.RankingAnalitica{}, Query: append(append([]Parameter{}, analiticaQuery...), analiticaLimiteQuery)},
+	"GET /api/analitica/autoridades": {Response: services.CargaAutoridades{}, Query: analiticaQuery},
+	"GET /api/analitica/dudas":       {Response: services.TiemposDudas{}, Query: append(append([]Parameter{}, analiticaQuery...), analiticaAgruparQuery)},
+	"GET /api/analitica/comunicados": {Response: services.AlcanceComunicados{}, Query: analiticaQuery},
+
+	// WhatsApp
+	"GET /api/whatsapp/status":        {Response: handlers.StatusResponse{}},
+	"GET /api/whatsapp/qr":            {Response: handlers.QRResponse{}},
+	"POST /api/whatsapp/send-message": {Request: handlers.SendMessageRequest{}, Response: handlers.SendMessageResponse{}},
+	"POST /api/whatsapp/send-media":   {Request: handlers.SendMediaRequest{}, Response: handlers.SendMessageResponse{}},
+	"POST /api/whatsapp/send-bulk":    {Request: handlers.SendBulkRequest{}, Response: freeObject},
+	"GET /api/whatsapp/queue/status":  {Response: freeObject},
+	"POST /api/whatsapp/queue/cancel": {Response: freeObject},
+	"POST /api/whatsapp/logout":       {Response: freeObject},
+}
+
+// extraSchemas son tipos que solo se referencian desde esquemas escritos a mano
+var extraSchemas = []interface{}{
+	handlers.BulkEstudianteRequest{},
+	handlers.BulkEstudianteResult{},
+	services.DestinatarioInfo{},
+	handlers.ComunicadoV2{},
+	backup.Resultado{},
+	tareas.Estado{},
+	models.EjecucionTarea{},
+	models.PreferenciaNotificacion{},
+	models.EntregaWebhook{},
+	services.EventoWebhook{},
+	services.CargaWebhook{},
+}
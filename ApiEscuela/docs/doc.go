@@ -0,0 +1,5 @@
+// Package docs genera la especificación OpenAPI 3 de la API a partir de las rutas
+// registradas en Fiber, los modelos y los comentarios de los handlers, y la sirve en /docs.
+package docs
+
+//go:generate go run ./gen
@@ -0,0 +1,107 @@
+// Command gen extrae los comentarios de documentación de los handlers y genera
+// docs/summaries_gen.go, que el generador OpenAPI usa como resumen de cada operación.
+//
+// Se ejecuta desde el paquete docs con: go generate ./docs
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+func main() {
+	fset := token.NewFileSet()
+	files, err := filepath.Glob(filepath.Join("..", "handlers", "*.go"))
+	if err != nil {
+		log.Fatalf("Error al listar handlers: %v", err)
+	}
+
+	summaries := map[string]string{}
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+		if err != nil {
+			log.Fatalf("Error al analizar %s: %v", path, err)
+		}
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || fn.Doc == nil || !fn.Name.IsExported() {
+				continue
+			}
+			receiver := receiverName(fn.Recv.List[0].Type)
+			if !strings.HasSuffix(receiver, "Handler") {
+				continue
+			}
+			if summary := summaryFromDoc(fn.Name.Name, fn.Doc.Text()); summary != "" {
+				summaries[receiver+"."+fn.Name.Name] = summary
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(summaries))
+	for k := range summaries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by go run ./gen; DO NOT EDIT.\n\n")
+	buf.WriteString("package docs\n\n")
+	buf.WriteString("// handlerSummaries contiene el comentario de documentación de cada método de handler\n")
+	buf.WriteString("var handlerSummaries = map[string]string{\n")
+	for _, k := range keys {
+		fmt.Fprintf(&buf, "\t%q: %q,\n", k, summaries[k])
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("Error al formatear el código generado: %v", err)
+	}
+	if err := os.WriteFile("summaries_gen.go", src, 0644); err != nil {
+		log.Fatalf("Error al escribir summaries_gen.go: %v", err)
+	}
+}
+
+// receiverName obtiene el nombre del tipo receptor (*EstudianteHandler -> EstudianteHandler)
+func receiverName(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// summaryFromDoc toma la primera línea del comentario que describe al método.
+// Algunos métodos tienen comentarios de otro método encima; se usa la línea que empieza con su nombre.
+func summaryFromDoc(name, doc string) string {
+	for _, line := range strings.Split(doc, "\n") {
+		line = strings.TrimSpace(line)
+		if rest, ok := strings.CutPrefix(line, name+" "); ok {
+			return capitalize(strings.TrimSpace(rest))
+		}
+	}
+	return ""
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	runes := []rune(s)
+	runes[0] = []rune(strings.ToUpper(string(runes[0])))[0]
+	return string(runes)
+}
@@ -0,0 +1,456 @@
+package docs
+
+import (
+	"ApiEscuela/handlers"
+	"ApiEscuela/middleware"
+	"ApiEscuela/routers"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Version de la API publicada en el documento
+const Version = "4.2.2"
+
+// excludedPaths son rutas que no forman parte de la API documentada
+var excludedPaths = map[string]bool{
+	"/docs":              true,
+	"/docs/openapi.json": true,
+}
+
+var (
+	handlerNameRe   = regexp.MustCompile(`\.\(\*(\w+)\)\.(\w+)-fm$`)
+	routeParamRe    = regexp.MustCompile(`:(\w+)`)
+	versionPrefixRe = regexp.MustCompile(`^/api/(v\d+)(/|$)`)
+)
+
+// route es una ruta registrada en Fiber normalizada para la documentación
+type route struct {
+	Method  string
+	Path    string // Ruta tal como la registra Fiber, sin barra final
+	Params  []string
+	Handler string // "TipoHandler.Metodo" o "" si es una función anónima
+	Version string // "v1", "v2"... o "" si la ruta no está versionada
+	// Unversioned es la ruta sin el segmento de versión; identifica el recurso en el catálogo
+	Unversioned string
+}
+
+// Key identifica la operación en operationSpecs: las rutas de la versión base se
+// describen sin versión y las de versiones posteriores con su ruta completa
+func (rt route) Key() string {
+	if rt.Version == routers.BaseVersion() {
+		return rt.Method + " " + rt.Unversioned
+	}
+	return rt.Method + " " + rt.Path
+}
+
+// splitVersion separa la versión de una ruta de la API: /api/v1/estudiantes → ("v1", "/api/estudiantes")
+func splitVersion(path string) (string, string) {
+	m := versionPrefixRe.FindStringSubmatch(path)
+	if m == nil {
+		return "", path
+	}
+	return m[1], normalizePath("/api" + strings.TrimPrefix(path, "/api/"+m[1]))
+}
+
+// documentedRoutes devuelve las rutas que forman parte de la especificación. Se omiten el
+// alias /api (idéntico a la versión base) y los recursos que una versión posterior hereda sin cambios.
+func documentedRoutes(app *fiber.App) []route {
+	all := registeredRoutes(app)
+	base := map[string]string{}
+	for _, rt := range all {
+		if rt.Version == routers.BaseVersion() {
+			base[rt.Method+" "+rt.Unversioned] = rt.Handler
+		}
+	}
+	// Recursos que alguna versión posterior redefine: se documentan completos en esa versión
+	changed := map[string]bool{}
+	for _, rt := range all {
+		if rt.Version == "" || rt.Version == routers.BaseVersion() {
+			continue
+		}
+		if h, ok := base[rt.Method+" "+rt.Unversioned]; !ok || h != rt.Handler {
+			if res, _ := findResource(rt.Unversioned); res != nil {
+				changed[rt.Version+" "+res.Prefix] = true
+			}
+		}
+	}
+
+	var routes []route
+	for _, rt := range all {
+		if rt.Version == "" && strings.HasPrefix(rt.Path, "/api/") {
+			continue
+		}
+		if rt.Version != "" && rt.Version != routers.BaseVersion() {
+			res, _ := findResource(rt.Unversioned)
+			if res == nil || !changed[rt.Version+" "+res.Prefix] {
+				continue
+			}
+		}
+		routes = append(routes, rt)
+	}
+	return routes
+}
+
+// registeredRoutes obtiene las rutas de la aplicación omitiendo middlewares y HEAD automáticos
+func registeredRoutes(app *fiber.App) []route {
+	var routes []route
+	seen := map[string]bool{}
+	for _, r := range app.GetRoutes(true) {
+		if r.Method == fiber.MethodHead || r.Method == fiber.MethodConnect || r.Method == fiber.MethodTrace {
+			continue
+		}
+		path := normalizePath(r.Path)
+		if excludedPaths[path] {
+			continue
+		}
+		key := r.Method + " " + path
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		version, unversioned := splitVersion(path)
+		routes = append(routes, route{
+			Method:      r.Method,
+			Path:        path,
+			Params:      r.Params,
+			Handler:     handlerName(r.Handlers),
+			Version:     version,
+			Unversioned: unversioned,
+		})
+	}
+	return routes
+}
+
+func normalizePath(path string) string {
+	if len(path) > 1 {
+		return strings.TrimSuffix(path, "/")
+	}
+	return path
+}
+
+// handlerName devuelve "TipoHandler.Metodo" para el último handler de la ruta
+func handlerName(hs []fiber.Handler) string {
+	if len(hs) == 0 {
+		return ""
+	}
+	fn := runtime.FuncForPC(reflect.ValueOf(hs[len(hs)-1]).Pointer())
+	if fn == nil {
+		return ""
+	}
+	m := handlerNameRe.FindStringSubmatch(fn.Name())
+	if m == nil {
+		return ""
+	}
+	return m[1] + "." + m[2]
+}
+
+// findResource busca el recurso cuyo prefijo coincide con la ruta (el más largo gana)
+func findResource(path string) (*resource, string) {
+	var best *resource
+	for i := range resources {
+		r := &resources[i]
+		if path != r.Prefix && !strings.HasPrefix(path, strings.TrimSuffix(r.Prefix, "/")+"/") {
+			continue
+		}
+		if best == nil || len(r.Prefix) > len(best.Prefix) {
+			best = r
+		}
+	}
+	if best == nil {
+		return nil, ""
+	}
+	return best, strings.TrimPrefix(path, strings.TrimSuffix(best.Prefix, "/"))
+}
+
+// inferSpec deduce la operación a partir del método, la ruta relativa al recurso y el nombre del handler
+func inferSpec(rt route, res *resource, suffix string) operationSpec {
+	spec := operationSpec{}
+	model := res.Model
+	if m, ok := res.VersionModels[rt.Version]; ok {
+		model = m
+	}
+	if model == nil {
+		return spec
+	}
+	switch {
+	case strings.Contains(rt.Handler, "Estadisticas"):
+		spec.Response = freeObject
+	case rt.Method == fiber.MethodPost && suffix == "":
+		spec.Request = model
+		spec.Response = model
+		spec.Status = 201
+	case rt.Method == fiber.MethodGet && suffix == "":
+		spec.Response = model
+		spec.List = true
+	case rt.Method == fiber.MethodGet && suffix == "/:id":
+		spec.Response = model
+	case rt.Method == fiber.MethodPut && suffix == "/:id":
+		spec.Request = model
+		spec.Response = model
+	case rt.Method == fiber.MethodDelete, rt.Method == fiber.MethodPut:
+		spec.Response = messageSchema
+	case rt.Method == fiber.MethodGet:
+		spec.Response = model
+		spec.List = !singleLookups[rt.Handler]
+	}
+	return spec
+}
+
+// merge aplica sobre la especificación inferida los campos definidos explícitamente
+func (s operationSpec) merge(o operationSpec) operationSpec {
+	if o.Summary != "" {
+		s.Summary = o.Summary
+	}
+	if o.Request != nil {
+		s.Request = o.Request
+	}
+	if o.Form != nil {
+		s.Form = o.Form
+	}
+	if o.Response != nil {
+		s.Response = o.Response
+		s.List = o.List
+	}
+	if o.Status != 0 {
+		s.Status = o.Status
+	}
+	if o.Raw {
+		s.Raw = true
+	}
+	if len(o.Query) > 0 {
+		s.Query = o.Query
+	}
+	if o.ContentType != "" {
+		s.ContentType = o.ContentType
+	}
+	return s
+}
+
+// envelope envuelve un esquema con la estructura de SendSuccess
+func envelope(data *Schema) *Schema {
+	return object(map[string]*Schema{
+		"success":     boolean(""),
+		"data":        data,
+		"status_code": integer(""),
+		"timestamp":   {Type: "string", Format: "date-time"},
+		"path":        str(""),
+		"method":      str(""),
+	}, "success", "data")
+}
+
+// Generate construye el documento OpenAPI a partir de las rutas registradas en la aplicación
+func Generate(app *fiber.App) *Document {
+	reg := newSchemaRegistry()
+	// Nombres explícitos para tipos homónimos de distintos paquetes
+	reg.names[reflect.TypeOf(middleware.ErrorResponse{})] = "AuthErrorResponse"
+	reg.schemas["AuthErrorResponse"] = reg.structSchema(reflect.TypeOf(middleware.ErrorResponse{}))
+	reg.schemaOf(handlers.ErrorResponse{})
+	reg.schemaOf(handlers.ValidationResponse{})
+	reg.schemas["SimpleError"] = object(map[string]*Schema{"error": str("Descripción del error")}, "error")
+	for _, v := range extraSchemas {
+		reg.schemaOf(v)
+	}
+
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title: "ApiEscuela",
+			Description: "API del sistema de gestión de visitas educativas de la UTEQ. Las rutas bajo /api requieren un token JWT obtenido en /auth/login.\n\n" +
+				"Las rutas se publican en /api/v1; /api es un alias de /api/v1. Las versiones posteriores (por ejemplo /api/v2) " +
+				"heredan todos los recursos que no redefinen, por lo que solo se documentan los recursos que cambian.",
+			Version: Version,
+		},
+		Paths: map[string]map[string]*Operation{},
+		Components: Components{
+			Schemas: reg.schemas,
+			SecuritySchemes: map[string]*SecurityScheme{
+				"bearerAuth": {
+					Type:         "http",
+					Scheme:       "bearer",
+					BearerFormat: "JWT",
+					Description:  "Token obtenido en POST /auth/login. Header: Authorization: Bearer <token>",
+				},
+			},
+		},
+	}
+	if baseURL := os.Getenv("BASE_URL"); baseURL != "" {
+		doc.Servers = []Server{{URL: baseURL}}
+	}
+
+	usedTags := map[string]string{}
+	for _, rt := range documentedRoutes(app) {
+		res, suffix := findResource(rt.Unversioned)
+		if res == nil {
+			continue
+		}
+		spec := inferSpec(rt, res, suffix)
+		if o, ok := operationSpecs[rt.Key()]; ok {
+			spec = spec.merge(o)
+		}
+		if spec.Summary == "" {
+			spec.Summary = handlerSummaries[rt.Handler]
+		}
+
+		op := buildOperation(reg, rt, res, spec)
+		usedTags[res.Tag] = res.Description
+
+		openapiPath := routeParamRe.ReplaceAllString(rt.Path, "{$1}")
+		if doc.Paths[openapiPath] == nil {
+			doc.Paths[openapiPath] = map[string]*Operation{}
+		}
+		doc.Paths[openapiPath][strings.ToLower(rt.Method)] = op
+	}
+
+	for _, r := range resources {
+		if desc, ok := usedTags[r.Tag]; ok {
+			doc.Tags = append(doc.Tags, Tag{Name: r.Tag, Description: desc})
+			delete(usedTags, r.Tag)
+		}
+	}
+	return doc
+}
+
+func buildOperation(reg *schemaRegistry, rt route, res *resource, spec operationSpec) *Operation {
+	op := &Operation{
+		Tags:        []string{res.Tag},
+		Summary:     spec.Summary,
+		OperationID: operationID(rt),
+		Responses:   map[string]*Response{},
+	}
+	if dep, ok := routers.DeprecationFor(rt.Path); ok {
+		op.Deprecated = true
+		op.Description = fmt.Sprintf("Obsoleto desde el %s; dejará de responder el %s. Use %s.",
+			dep.Since.Format("2006-01-02"), dep.Sunset.Format("2006-01-02"), dep.Successor)
+	}
+
+	for _, p := range rt.Params {
+		schema := &Schema{Type: "string"}
+		if p == "id" || strings.HasSuffix(p, "_id") {
+			schema = &Schema{Type: "integer", Format: "int64"}
+		}
+		op.Parameters = append(op.Parameters, Parameter{Name: p, In: "path", Required: true, Schema: schema})
+	}
+	op.Parameters = append(op.Parameters, spec.Query...)
+
+	if spec.Request != nil {
+		op.RequestBody = &RequestBody{Required: true, Content: jsonContent(reg.schemaOf(spec.Request))}
+	}
+	if spec.Form != nil {
+		op.RequestBody = &RequestBody{Required: true, Content: map[string]MediaType{"multipart/form-data": {Schema: spec.Form}}}
+	}
+
+	status := spec.Status
+	if status == 0 {
+		status = 200
+	}
+	if spec.Response != nil {
+		schema := reg.schemaOf(spec.Response)
+		if spec.List {
+			schema = arrayOf(schema)
+		}
+		if res.Envelope && !spec.Raw {
+			schema = envelope(schema)
+		}
+		content := jsonContent(schema)
+		if schema.Format == "binary" {
+			content = map[string]MediaType{"application/octet-stream": {Schema: schema}}
+		}
+		if spec.ContentType != "" {
+			content = map[string]MediaType{spec.ContentType: {Schema: schema}}
+		}
+		op.Responses[strconv.Itoa(status)] = &Response{Description: http.StatusText(status), Content: content}
+	} else {
+		op.Responses[strconv.Itoa(status)] = &Response{Description: http.StatusText(status)}
+	}
+
+	errorSchema := refTo("SimpleError")
+	if res.Envelope {
+		errorSchema = refTo("ErrorResponse")
+	}
+	if op.RequestBody != nil || len(op.Parameters) > 0 {
+		badRequest := errorSchema
+		if res.Envelope {
+			badRequest = &Schema{Description: "ErrorResponse o ValidationResponse", Ref: "#/components/schemas/ValidationResponse"}
+		}
+		op.Responses["400"] = &Response{Description: "Petición inválida", Content: jsonContent(badRequest)}
+	}
+	if len(rt.Params) > 0 {
+		op.Responses["404"] = &Response{Description: "Recurso no encontrado", Content: jsonContent(errorSchema)}
+	}
+	op.Responses["500"] = &Response{Description: "Error interno", Content: jsonContent(errorSchema)}
+
+	if res.Public {
+		op.Security = &[]map[string][]string{}
+	} else {
+		op.Security = &[]map[string][]string{{"bearerAuth": {}}}
+		op.Responses["401"] = &Response{Description: "Token ausente, inválido o expirado", Content: jsonContent(refTo("AuthErrorResponse"))}
+	}
+	return op
+}
+
+// operationID usa el nombre del método del handler; para funciones anónimas se deriva de la ruta.
+// En versiones posteriores a la base se agrega el sufijo de versión si el handler no lo tiene.
+func operationID(rt route) string {
+	if rt.Handler != "" {
+		_, method, _ := strings.Cut(rt.Handler, ".")
+		if suffix := strings.ToUpper(rt.Version); rt.Version != routers.BaseVersion() && !strings.HasSuffix(method, suffix) {
+			method += suffix
+		}
+		return method
+	}
+	var b strings.Builder
+	b.WriteString(strings.ToLower(rt.Method))
+	for _, part := range strings.FieldsFunc(rt.Path, func(r rune) bool { return r == '/' || r == '-' || r == ':' }) {
+		b.WriteString(capitalize(part))
+	}
+	if rt.Path == "/" {
+		b.WriteString("Root")
+	}
+	return b.String()
+}
+
+// CheckRoutes compara las rutas registradas con el catálogo de documentación y devuelve
+// la lista de inconsistencias. Una lista vacía significa que la especificación está sincronizada.
+func CheckRoutes(app *fiber.App) []string {
+	var problems []string
+	registered := map[string]bool{}
+
+	for _, rt := range documentedRoutes(app) {
+		key := rt.Key()
+		registered[key] = true
+
+		res, _ := findResource(rt.Unversioned)
+		if res == nil {
+			problems = append(problems, fmt.Sprintf("%s: la ruta no pertenece a ningún recurso documentado", key))
+			continue
+		}
+		spec, hasSpec := operationSpecs[key]
+		if res.Model == nil && !hasSpec {
+			problems = append(problems, fmt.Sprintf("%s: el recurso %q no tiene modelo y la ruta no tiene especificación", key, res.Tag))
+		}
+		if rt.Handler == "" {
+			if spec.Summary == "" {
+				problems = append(problems, fmt.Sprintf("%s: handler anónimo sin resumen en operationSpecs", key))
+			}
+		} else if _, ok := handlerSummaries[rt.Handler]; !ok && spec.Summary == "" {
+			problems = append(problems, fmt.Sprintf("%s: %s no tiene comentario de documentación (ejecute go generate ./docs)", key, rt.Handler))
+		}
+	}
+
+	for key := range operationSpecs {
+		if !registered[key] {
+			problems = append(problems, fmt.Sprintf("%s: especificación sin ruta registrada", key))
+		}
+	}
+	sort.Strings(problems)
+	return problems
+}
@@ -0,0 +1,17 @@
+package docs_test
+
+import (
+	"testing"
+
+	"ApiEscuela/docs"
+	"ApiEscuela/testutil"
+)
+
+// TestCheckRoutes falla cuando se registra una ruta sin documentar o queda una especificación
+// sin ruta, para no depender de los avisos que main.go escribe al iniciar
+func TestCheckRoutes(t *testing.T) {
+	app := testutil.NewApp(testutil.MemoryRepos())
+	for _, problema := range docs.CheckRoutes(app) {
+		t.Error(problema)
+	}
+}
@@ -0,0 +1,48 @@
+package docs
+
+import (
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const swaggerUIHTML = `<!DOCTYPE html>
+<html lang="es">
+<head>
+  <meta charset="utf-8">
+  <title>ApiEscuela - Documentación</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({
+      url: "/docs/openapi.json",
+      dom_id: "#swagger-ui",
+      persistAuthorization: true
+    });
+  </script>
+</body>
+</html>`
+
+// UIHandler sirve la interfaz interactiva de Swagger UI
+func UIHandler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Type("html", "utf-8")
+		return c.SendString(swaggerUIHTML)
+	}
+}
+
+// SpecHandler sirve el documento OpenAPI en JSON.
+// El documento se genera en la primera petición, cuando todas las rutas ya están registradas.
+func SpecHandler(app *fiber.App) fiber.Handler {
+	var (
+		once sync.Once
+		doc  *Document
+	)
+	return func(c *fiber.Ctx) error {
+		once.Do(func() { doc = Generate(app) })
+		return c.JSON(doc)
+	}
+}
@@ -0,0 +1,184 @@
+package docs
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// schemaRegistry genera esquemas a partir de tipos Go y los registra en components
+type schemaRegistry struct {
+	schemas map[string]*Schema
+	names   map[reflect.Type]string
+}
+
+func newSchemaRegistry() *schemaRegistry {
+	return &schemaRegistry{
+		schemas: map[string]*Schema{},
+		names:   map[reflect.Type]string{},
+	}
+}
+
+// schemaOf devuelve el esquema de un valor de ejemplo (por ejemplo models.Persona{})
+func (r *schemaRegistry) schemaOf(v interface{}) *Schema {
+	if v == nil {
+		return nil
+	}
+	if s, ok := v.(*Schema); ok {
+		return s
+	}
+	return r.schemaFor(reflect.TypeOf(v))
+}
+
+// schemaFor devuelve el esquema de un tipo, registrando los structs con nombre como componentes
+func (r *schemaRegistry) schemaFor(t reflect.Type) *Schema {
+	if t.Kind() == reflect.Ptr {
+		s := r.schemaFor(t.Elem())
+		if s.Ref != "" {
+			return s
+		}
+		copy := *s
+		copy.Nullable = true
+		return &copy
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t == deletedAtType:
+		return &Schema{Type: "string", Format: "date-time", Nullable: true}
+	case t.Implements(marshalerType) || reflect.PtrTo(t).Implements(marshalerType):
+		// Tipos con serialización propia (datatypes.JSON, etc.) se documentan como JSON libre
+		return &Schema{Description: "JSON libre"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return arrayOf(r.schemaFor(t.Elem()))
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schemaFor(t.Elem())}
+	case reflect.Interface:
+		return &Schema{}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.structSchema(t)
+		}
+		return refTo(r.register(t))
+	}
+	return &Schema{}
+}
+
+// register agrega el struct a components y devuelve su nombre
+func (r *schemaRegistry) register(t reflect.Type) string {
+	if name, ok := r.names[t]; ok {
+		return name
+	}
+	name := typeName(t)
+	if _, taken := r.schemas[name]; taken {
+		// Dos paquetes pueden declarar el mismo nombre (p. ej. ErrorResponse)
+		pkg := t.PkgPath()
+		if i := strings.LastIndex(pkg, "/"); i >= 0 {
+			pkg = pkg[i+1:]
+		}
+		name = capitalize(pkg) + name
+	}
+	r.names[t] = name
+	// Reservar el nombre antes de recorrer los campos para soportar tipos recursivos
+	r.schemas[name] = &Schema{Type: "object"}
+	*r.schemas[name] = *r.structSchema(t)
+	return name
+}
+
+// typeName es el nombre del tipo; en los genéricos agrega el nombre de cada argumento sin paquete
+// (ResultadoBusqueda[ApiEscuela/models.Noticia] queda ResultadoBusquedaNoticia)
+func typeName(t reflect.Type) string {
+	name := t.Name()
+	i := strings.IndexByte(name, '[')
+	if i < 0 {
+		return name
+	}
+	base := name[:i]
+	for _, arg := range strings.Split(strings.TrimSuffix(name[i+1:], "]"), ",") {
+		base += arg[strings.LastIndex(arg, ".")+1:]
+	}
+	return base
+}
+
+// structSchema construye el esquema de objeto de un struct siguiendo las reglas de encoding/json
+func (r *schemaRegistry) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	r.collectFields(t, schema)
+	return schema
+}
+
+func (r *schemaRegistry) collectFields(t reflect.Type, schema *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		// Campos embebidos sin nombre JSON (gorm.Model) se aplanan
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				r.collectFields(ft, schema)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop := r.schemaFor(field.Type)
+		if strings.Contains(field.Tag.Get("validate"), "required") {
+			schema.Required = append(schema.Required, name)
+		} else if !strings.Contains(opts, "omitempty") && field.Type.Kind() != reflect.Ptr &&
+			strings.Contains(field.Tag.Get("gorm"), "not null") {
+			schema.Required = append(schema.Required, name)
+		}
+		schema.Properties[name] = prop
+	}
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	runes := []rune(s)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
@@ -0,0 +1,273 @@
+// Code generated by go run ./gen; DO NOT EDIT.
+
+package docs
+
+// handlerSummaries contiene el comentario de documentación de cada método de handler
+var handlerSummaries = map[string]string{
+	"ActividadHandler.CreateActividad":                                            "Crea una nueva actividad",
+	"ActividadHandler.DeleteActividad":                                            "Elimina una actividad",
+	"ActividadHandler.GetActividad":                                               "Obtiene una actividad por ID",
+	"ActividadHandler.GetActividadesByDuracion":                                   "Obtiene actividades por rango de duración",
+	"ActividadHandler.GetActividadesByNombre":                                     "Busca actividades por nombre sin distinguir tildes, las más relevantes primero",
+	"ActividadHandler.GetActividadesByNombreV2":                                   "Busca actividades por nombre con la relevancia y un fragmento resaltado (respuesta v2)",
+	"ActividadHandler.GetActividadesByTematica":                                   "Obtiene actividades por temática",
+	"ActividadHandler.GetAllActividades":                                          "Obtiene todas las actividades",
+	"ActividadHandler.PatchActividad":                                             "Actualiza solo los campos enviados de una actividad (JSON Merge Patch)",
+	"ActividadHandler.UpdateActividad":                                            "Actualiza una actividad",
+	"ActividadHandler.Version":                                                    "Calcula la versión de la lista de las actividades para las peticiones condicionales",
+	"AnaliticaHandler.GetActividades":                                             "Obtiene las actividades con más visitas",
+	"AnaliticaHandler.GetAutoridades":                                             "Obtiene las visitas y dudas asignadas a cada autoridad",
+	"AnaliticaHandler.GetComunicados":                                             "Obtiene el alcance de los comunicados por canal",
+	"AnaliticaHandler.GetDudas":                                                   "Obtiene los tiempos de respuesta de las dudas",
+	"AnaliticaHandler.GetEstudiantes":                                             "Obtiene los estudiantes alcanzados por provincia, ciudad, institución o especialidad",
+	"AnaliticaHandler.GetPanel":                                                   "Obtiene todas las secciones de la analítica del dashboard en una sola respuesta",
+	"AnaliticaHandler.GetTematicas":                                               "Obtiene las temáticas con más visitas",
+	"AnaliticaHandler.GetVisitas":                                                 "Obtiene los programas de visita por día, semana, mes o año",
+	"AuthHandler.ChangePassword":                                                  "Maneja el cambio de contraseña",
+	"AuthHandler.GetProfile":                                                      "Obtiene el perfil del usuario autenticado",
+	"AuthHandler.Login":                                                           "Maneja el inicio de sesión",
+	"AuthHandler.RecoverPassword":                                                 "Maneja la recuperación de contraseña por cédula (público)",
+	"AuthHandler.RefreshToken":                                                    "Genera un nuevo token para el usuario autenticado",
+	"AuthHandler.Register":                                                        "Maneja el registro de nuevos usuarios",
+	"AuthHandler.ResetPassword":                                                   "Maneja el reseteo de contraseña por ID de código (público)",
+	"AuthHandler.ValidateToken":                                                   "Valida si un token es válido (endpoint público para verificación)",
+	"AuthHandler.VerifyCode":                                                      "Maneja la verificación del código temporal (público)",
+	"AutoridadUTEQHandler.CreateAutoridadUTEQ":                                    "Crea una nueva autoridad UTEQ",
+	"AutoridadUTEQHandler.DeleteAutoridadUTEQ":                                    "Elimina una autoridad UTEQ y en cascada su usuario y persona",
+	"AutoridadUTEQHandler.GetAllAutoridadesUTEQ":                                  "Obtiene todas las autoridades UTEQ activas",
+	"AutoridadUTEQHandler.GetAllAutoridadesUTEQIncludingDeleted":                  "Obtiene todas las autoridades UTEQ incluyendo las eliminadas",
+	"AutoridadUTEQHandler.GetAutoridadUTEQ":                                       "Obtiene una autoridad UTEQ por ID",
+	"AutoridadUTEQHandler.GetAutoridadUTEQByPersona":                              "Obtiene autoridad UTEQ por persona",
+	"AutoridadUTEQHandler.GetAutoridadesUTEQByCargo":                              "Obtiene autoridades por cargo",
+	"AutoridadUTEQHandler.GetDeletedAutoridadesUTEQ":                              "Obtiene solo las autoridades UTEQ eliminadas",
+	"AutoridadUTEQHandler.PatchAutoridadUTEQ":                                     "Actualiza solo los campos enviados de una autoridad UTEQ (JSON Merge Patch)",
+	"AutoridadUTEQHandler.RestoreAutoridadUTEQ":                                   "Restaura una autoridad UTEQ eliminada y en cascada su usuario y persona",
+	"AutoridadUTEQHandler.UpdateAutoridadUTEQ":                                    "Actualiza una autoridad UTEQ",
+	"BackupHandler.ExportBackup":                                                  "Descarga un respaldo .tar.gz de todas las tablas y de los archivos subidos",
+	"BackupHandler.RestoreBackup":                                                 "Restaura un respaldo subido en el campo \"archivo\"",
+	"BusquedaHandler.Buscar":                                                      "Busca en personas, instituciones, programas de visita y noticias y agrupa los resultados por tipo",
+	"CiudadHandler.CreateCiudad":                                                  "Crea una nueva ciudad",
+	"CiudadHandler.DeleteCiudad":                                                  "Elimina una ciudad",
+	"CiudadHandler.GetAllCiudades":                                                "Obtiene todas las ciudades",
+	"CiudadHandler.GetCiudad":                                                     "Obtiene una ciudad por ID",
+	"CiudadHandler.GetCiudadByNombre":                                             "Busca ciudades por nombre",
+	"CiudadHandler.GetCiudadesByProvincia":                                        "Obtiene ciudades por provincia",
+	"CiudadHandler.PatchCiudad":                                                   "Actualiza solo los campos enviados de una ciudad (JSON Merge Patch)",
+	"CiudadHandler.UpdateCiudad":                                                  "Actualiza una ciudad",
+	"CiudadHandler.Version":                                                       "Calcula la versión de la lista de las ciudades para las peticiones condicionales",
+	"CodigoHandler.CreateCodigo":                                                  "Crea un nuevo código",
+	"CodigoHandler.DeleteCodigo":                                                  "Elimina un código",
+	"CodigoHandler.GetAllCodigos":                                                 "Obtiene todos los códigos",
+	"CodigoHandler.GetCodigo":                                                     "Obtiene un código por ID",
+	"CodigoHandler.GetCodigosByUsuario":                                           "Obtiene códigos por usuario",
+	"CodigoHandler.MarcarComoExpirado":                                            "Marca un código como expirado",
+	"CodigoHandler.MarcarComoVerificado":                                          "Marca un código como verificado",
+	"CodigoHandler.PatchCodigo":                                                   "Actualiza solo los campos enviados de un código de usuario (JSON Merge Patch)",
+	"CodigoHandler.UpdateCodigo":                                                  "Actualiza un código",
+	"CodigoHandler.VerifyCodigo":                                                  "Verifica un código",
+	"ComunicadoHandler.CreateComunicado":                                          "Crea y envía un nuevo comunicado",
+	"ComunicadoHandler.CreateComunicadoV2":                                        "Crea y envía un nuevo comunicado (respuesta v2)",
+	"ComunicadoHandler.DeleteComunicado":                                          "Elimina un comunicado",
+	"ComunicadoHandler.GetAllComunicados":                                         "Obtiene todos los comunicados",
+	"ComunicadoHandler.GetAllComunicadosV2":                                       "Obtiene todos los comunicados (respuesta v2)",
+	"ComunicadoHandler.GetComunicado":                                             "Obtiene un comunicado por ID",
+	"ComunicadoHandler.GetComunicadoV2":                                           "Obtiene un comunicado por ID (respuesta v2)",
+	"ComunicadoHandler.SearchComunicados":                                         "Busca comunicados por asunto o mensaje sin distinguir tildes, los más relevantes primero",
+	"ComunicadoHandler.SearchComunicadosV2":                                       "Busca comunicados por asunto o mensaje, los más relevantes primero (respuesta v2)",
+	"DetalleAutoridadDetallesVisitaHandler.CreateDetalleAutoridadDetallesVisita":  "Crea un nuevo detalle de autoridad para visita",
+	"DetalleAutoridadDetallesVisitaHandler.DeleteDetalleAutoridadDetallesVisita":  "Elimina un detalle",
+	"DetalleAutoridadDetallesVisitaHandler.DeleteDetallesByAutoridad":             "Elimina todos los detalles de una autoridad específica",
+	"DetalleAutoridadDetallesVisitaHandler.DeleteDetallesByProgramaVisita":        "Elimina todos los detalles de un programa de visita específico",
+	"DetalleAutoridadDetallesVisitaHandler.GetAllDetalleAutoridadDetallesVisitas": "Obtiene todos los detalles",
+	"DetalleAutoridadDetallesVisitaHandler.GetDetalleAutoridadDetallesVisita":     "Obtiene un detalle por ID",
+	"DetalleAutoridadDetallesVisitaHandler.GetDetallesByAutoridad":                "Obtiene todos los detalles de una autoridad",
+	"DetalleAutoridadDetallesVisitaHandler.GetDetallesByProgramaVisita":           "Obtiene todos los detalles de un programa de visita",
+	"DetalleAutoridadDetallesVisitaHandler.GetEstadisticasAsignacion":             "Obtiene estadísticas de asignación de autoridades",
+	"DetalleAutoridadDetallesVisitaHandler.PatchDetalleAutoridadDetallesVisita":   "Actualiza solo los campos enviados de un detalle de autoridad (JSON Merge Patch)",
+	"DetalleAutoridadDetallesVisitaHandler.UpdateDetalleAutoridadDetallesVisita":  "Actualiza un detalle",
+	"DudasHandler.BuscarDudasPorPregunta":                                         "Busca dudas por las palabras de la pregunta sin distinguir tildes, ordenadas por relevancia",
+	"DudasHandler.BuscarDudasPorPreguntaV2":                                       "Busca dudas por las palabras de la pregunta con la relevancia y un fragmento resaltado (respuesta v2)",
+	"DudasHandler.CreateDudas":                                                    "Crea una nueva duda",
+	"DudasHandler.DeleteDudas":                                                    "Elimina una duda",
+	"DudasHandler.GetAllDudas":                                                    "Obtiene todas las dudas",
+	"DudasHandler.GetDudas":                                                       "Obtiene una duda por ID",
+	"DudasHandler.GetDudasByAutoridad":                                            "Obtiene dudas asignadas a una autoridad",
+	"DudasHandler.GetDudasByEstudiante":                                           "Obtiene dudas por estudiante",
+	"DudasHandler.GetDudasByPrivacidad":                                           "Obtiene dudas por tipo de privacidad",
+	"DudasHandler.GetDudasRespondidas":                                            "Obtiene dudas con respuesta",
+	"DudasHandler.GetDudasSinAsignar":                                             "Obtiene dudas sin autoridad asignada",
+	"DudasHandler.GetDudasSinResponder":                                           "Obtiene dudas sin respuesta",
+	"DudasHandler.PatchDudas":                                                     "Actualiza solo los campos enviados de una duda (JSON Merge Patch)",
+	"DudasHandler.ResponderDuda":                                                  "Actualiza la respuesta de una duda",
+	"DudasHandler.UpdateDudas":                                                    "Actualiza una duda",
+	"EstudianteHandler.CreateEstudiante":                                          "Crea un nuevo estudiante",
+	"EstudianteHandler.CreateEstudiantesBulk":                                     "Crea múltiples estudiantes desde una carga masiva (Excel)",
+	"EstudianteHandler.DeleteEstudiante":                                          "Elimina un estudiante y en cascada su usuario y persona",
+	"EstudianteHandler.GetAllEstudiantes":                                         "Obtiene todos los estudiantes activos",
+	"EstudianteHandler.GetAllEstudiantesIncludingDeleted":                         "Obtiene todos los estudiantes incluyendo los eliminados",
+	"EstudianteHandler.GetDeletedEstudiantes":                                     "Obtiene solo los estudiantes eliminados",
+	"EstudianteHandler.GetEstudiante":                                             "Obtiene un estudiante por ID",
+	"EstudianteHandler.GetEstudiantesByCity":                                      "Obtiene estudiantes por ciudad",
+	"EstudianteHandler.GetEstudiantesByEspecialidad":                              "Obtiene estudiantes por especialidad",
+	"EstudianteHandler.GetEstudiantesByInstitucion":                               "Obtiene estudiantes por institución",
+	"EstudianteHandler.PatchEstudiante":                                           "Actualiza solo los campos enviados de un estudiante (JSON Merge Patch)",
+	"EstudianteHandler.RestoreEstudiante":                                         "Restaura un estudiante eliminado y en cascada su usuario y persona",
+	"EstudianteHandler.UpdateEstudiante":                                          "Actualiza un estudiante",
+	"EstudianteUniversitarioHandler.CreateEstudianteUniversitario":                "Crea un nuevo estudiante universitario",
+	"EstudianteUniversitarioHandler.DeleteEstudianteUniversitario":                "Elimina un estudiante universitario",
+	"EstudianteUniversitarioHandler.GetAllEstudiantesUniversitarios":              "Obtiene todos los estudiantes universitarios",
+	"EstudianteUniversitarioHandler.GetEstudianteUniversitario":                   "Obtiene un estudiante universitario por ID",
+	"EstudianteUniversitarioHandler.GetEstudianteUniversitarioByPersona":          "Obtiene estudiante universitario por persona",
+	"EstudianteUniversitarioHandler.GetEstudiantesUniversitariosBySemestre":       "Obtiene estudiantes por semestre",
+	"EstudianteUniversitarioHandler.PatchEstudianteUniversitario":                 "Actualiza solo los campos enviados de un estudiante universitario (JSON Merge Patch)",
+	"EstudianteUniversitarioHandler.UpdateEstudianteUniversitario":                "Actualiza un estudiante universitario",
+	"EventosHandler.Stream":                                                       "Transmite los eventos del usuario (text/event-stream); ?tipos= filtra por tipo",
+	"ExportacionHandler.EsExportacion":                                            "Indica si la petición pide un archivo en lugar de JSON",
+	"ExportacionHandler.Exportable":                                               "Devuelve el middleware que exporta la lista de la ruta a CSV, XLSX o PDF",
+	"ExportacionHandler.GetListasExportables":                                     "Lista las listas que se pueden exportar y sus columnas",
+	"ExportacionHandler.PorCiudad":                                                "Filtra por la ciudad del parámetro ciudad_id",
+	"ExportacionHandler.PorEstadoDuda":                                            "Filtra las dudas por estado (repositories.DudasSinResponder, DudasRespondidas o DudasSinAsignar)",
+	"ExportacionHandler.PorInstitucion":                                           "Filtra por la institución del parámetro institucion_id",
+	"ExportacionHandler.PorProgramaVisita":                                        "Filtra por el programa de visita del parámetro programa_visita_id",
+	"ExportacionHandler.PorRangoFechas":                                           "Filtra por ?inicio= y ?fin=, ambos obligatorios en formato YYYY-MM-DD",
+	"InstitucionHandler.CreateInstitucion":                                        "Crea una nueva institución",
+	"InstitucionHandler.DeleteInstitucion":                                        "Elimina una institución",
+	"InstitucionHandler.GetAllInstituciones":                                      "Obtiene todas las instituciones",
+	"InstitucionHandler.GetInstitucion":                                           "Obtiene una institución por ID",
+	"InstitucionHandler.GetInstitucionesByAutoridad":                              "Busca instituciones por autoridad",
+	"InstitucionHandler.GetInstitucionesByNombre":                                 "Busca instituciones por nombre",
+	"InstitucionHandler.PatchInstitucion":                                         "Actualiza solo los campos enviados de una institución (JSON Merge Patch)",
+	"InstitucionHandler.UpdateInstitucion":                                        "Actualiza una institución",
+	"MediaHandler.DeleteMedia":                                                    "Elimina un archivo y sus variantes. Si lo usan noticias o comunicados responde",
+	"MediaHandler.GetMedia":                                                       "Obtiene un archivo subido con las noticias y comunicados que lo usan",
+	"MediaHandler.ListMedia":                                                      "Lista los archivos subidos; admite los filtros q (nombre o clave), categoria,",
+	"MediaHandler.UpdateVisibilidad":                                              "Hace público o privado un archivo",
+	"NoticiaHandler.CreateNoticia":                                                "Crea una nueva noticia",
+	"NoticiaHandler.DeleteNoticia":                                                "Elimina una noticia",
+	"NoticiaHandler.GetAllNoticias":                                               "Obtiene todas las noticias",
+	"NoticiaHandler.GetNoticia":                                                   "Obtiene una noticia por ID",
+	"NoticiaHandler.GetNoticiasByDescripcion":                                     "Busca noticias por descripción",
+	"NoticiaHandler.GetNoticiasByTitulo":                                          "Busca noticias por título",
+	"NoticiaHandler.GetNoticiasByUsuario":                                         "Obtiene noticias por usuario",
+	"NoticiaHandler.PatchNoticia":                                                 "Actualiza solo los campos enviados de una noticia (JSON Merge Patch)",
+	"NoticiaHandler.SearchNoticias":                                               "Busca noticias por título o descripción sin distinguir tildes; el título pesa más",
+	"NoticiaHandler.SearchNoticiasV2":                                             "Busca noticias por título o descripción con la relevancia y un fragmento resaltado (respuesta v2)",
+	"NoticiaHandler.UpdateNoticia":                                                "Actualiza una noticia",
+	"NotificacionHandler.GetNoLeidas":                                             "Devuelve cuántas notificaciones sin leer tiene el usuario autenticado",
+	"NotificacionHandler.GetNotificaciones":                                       "Lista las notificaciones del usuario autenticado, las más recientes primero",
+	"NotificacionHandler.GetPreferencias":                                         "Devuelve los canales por los que el usuario autenticado recibe sus notificaciones",
+	"NotificacionHandler.MarcarLeida":                                             "Marca como leída una notificación del usuario autenticado",
+	"NotificacionHandler.MarcarTodasLeidas":                                       "Marca como leídas todas las notificaciones del usuario autenticado",
+	"NotificacionHandler.UpdatePreferencias":                                      "Cambia los canales por los que el usuario autenticado recibe sus notificaciones",
+	"PapeleraHandler.GetEliminados":                                               "Lista los registros eliminados de una entidad, los más recientes primero",
+	"PapeleraHandler.GetPapelera":                                                 "Devuelve cuántos registros hay en la papelera de cada entidad",
+	"PapeleraHandler.PurgarElemento":                                              "Elimina definitivamente un registro de la papelera si nada lo usa",
+	"PapeleraHandler.RestaurarElemento":                                           "Restaura un registro de la papelera y los que dependen de él",
+	"PersonaHandler.CreatePersona":                                                "Crea una nueva persona",
+	"PersonaHandler.DeletePersona":                                                "Elimina una persona",
+	"PersonaHandler.GetAllPersonas":                                               "Obtiene todas las personas",
+	"PersonaHandler.GetPersona":                                                   "Obtiene una persona por ID",
+	"PersonaHandler.GetPersonaByCedula":                                           "Obtiene una persona por cédula",
+	"PersonaHandler.GetPersonasByCorreo":                                          "Obtiene personas por correo",
+	"PersonaHandler.PatchPersona":                                                 "Actualiza solo los campos enviados de una persona (JSON Merge Patch)",
+	"PersonaHandler.UpdatePersona":                                                "Actualiza una persona",
+	"ProgramaVisitaHandler.CreateProgramaVisita":                                  "Crea un nuevo programa de visita",
+	"ProgramaVisitaHandler.DeleteProgramaVisita":                                  "Elimina un programa de visita",
+	"ProgramaVisitaHandler.GetAllProgramasVisita":                                 "Obtiene todos los programas de visita",
+	"ProgramaVisitaHandler.GetProgramaVisita":                                     "Obtiene un programa de visita por ID",
+	"ProgramaVisitaHandler.GetProgramasVisitaByFecha":                             "Obtiene programas por fecha",
+	"ProgramaVisitaHandler.GetProgramasVisitaByInstitucion":                       "Obtiene programas por institución",
+	"ProgramaVisitaHandler.GetProgramasVisitaByRangoFecha":                        "Obtiene programas en un rango de fechas",
+	"ProgramaVisitaHandler.PatchProgramaVisita":                                   "Actualiza solo los campos enviados de un programa de visita (JSON Merge Patch)",
+	"ProgramaVisitaHandler.UpdateProgramaVisita":                                  "Actualiza un programa de visita",
+	"ProvinciaHandler.CreateProvincia":                                            "Crea una nueva provincia",
+	"ProvinciaHandler.DeleteProvincia":                                            "Elimina una provincia",
+	"ProvinciaHandler.GetAllProvincias":                                           "Obtiene todas las provincias",
+	"ProvinciaHandler.GetProvincia":                                               "Obtiene una provincia por ID",
+	"ProvinciaHandler.GetProvinciaByNombre":                                       "Busca provincia por nombre",
+	"ProvinciaHandler.PatchProvincia":                                             "Actualiza solo los campos enviados de una provincia (JSON Merge Patch)",
+	"ProvinciaHandler.UpdateProvincia":                                            "Actualiza una provincia",
+	"ProvinciaHandler.Version":                                                    "Calcula la versión de la lista de las provincias para las peticiones condicionales",
+	"TareaHandler.EjecutarTarea":                                                  "Inicia una ejecución manual de la tarea en esta instancia; responde 202 con la",
+	"TareaHandler.GetEjecuciones":                                                 "Devuelve el historial de una tarea (limite, por defecto 20)",
+	"TareaHandler.GetTareas":                                                      "Lista las tareas programadas con su próxima ejecución, la última y el último error",
+	"TematicaHandler.CreateTematica":                                              "Crea una nueva temática",
+	"TematicaHandler.DeleteTematica":                                              "Elimina una temática",
+	"TematicaHandler.GetAllTematicas":                                             "Obtiene todas las temáticas",
+	"TematicaHandler.GetTematica":                                                 "Obtiene una temática por ID",
+	"TematicaHandler.GetTematicasByDescripcion":                                   "Busca temáticas por descripción",
+	"TematicaHandler.GetTematicasByNombre":                                        "Busca temáticas por nombre sin distinguir tildes, ordenadas por relevancia",
+	"TematicaHandler.GetTematicasByNombreV2":                                      "Busca temáticas por nombre con la relevancia y un fragmento resaltado (respuesta v2)",
+	"TematicaHandler.PatchTematica":                                               "Actualiza solo los campos enviados de una temática (JSON Merge Patch)",
+	"TematicaHandler.UpdateTematica":                                              "Actualiza una temática",
+	"TematicaHandler.Version":                                                     "Calcula la versión de la lista de las temáticas para las peticiones condicionales",
+	"TipoUsuarioHandler.CreateTipoUsuario":                                        "Crea un nuevo tipo de usuario",
+	"TipoUsuarioHandler.DeleteTipoUsuario":                                        "Elimina un tipo de usuario",
+	"TipoUsuarioHandler.GetAllTiposUsuario":                                       "Obtiene todos los tipos de usuario",
+	"TipoUsuarioHandler.GetTipoUsuario":                                           "Obtiene un tipo de usuario por ID",
+	"TipoUsuarioHandler.GetTipoUsuarioByNombre":                                   "Busca tipo de usuario por nombre",
+	"TipoUsuarioHandler.NombreTipoUsuario":                                        "Devuelve el nombre del tipo de usuario; se usa para verificar roles en las rutas",
+	"TipoUsuarioHandler.PatchTipoUsuario":                                         "Actualiza solo los campos enviados de un tipo de usuario (JSON Merge Patch)",
+	"TipoUsuarioHandler.UpdateTipoUsuario":                                        "Actualiza un tipo de usuario",
+	"UploadHandler.CancelUploadSession":                                           "Descarta una subida por partes",
+	"UploadHandler.CreateUploadSession":                                           "Inicia una subida por partes",
+	"UploadHandler.FinalizeUploadSession":                                         "Verifica la subida completa y la guarda como un archivo normal",
+	"UploadHandler.GetFile":                                                       "Sirve archivos estáticos",
+	"UploadHandler.GetUploadSession":                                              "Devuelve cuántos bytes se recibieron de una subida, para reanudarla",
+	"UploadHandler.UploadChunk":                                                   "Recibe un fragmento en el cuerpo crudo de la petición. El encabezado",
+	"UploadHandler.UploadFile":                                                    "Maneja la subida de archivos y retorna la URL",
+	"UsuarioHandler.CreateUsuario":                                                "Crea un nuevo usuario",
+	"UsuarioHandler.DeleteUsuario":                                                "Elimina un usuario",
+	"UsuarioHandler.GetAllUsuarios":                                               "Obtiene todos los usuarios",
+	"UsuarioHandler.GetAllUsuariosIncludingDeleted":                               "Obtiene todos los usuarios incluyendo eliminados",
+	"UsuarioHandler.GetDeletedUsuarios":                                           "Obtiene solo los usuarios eliminados",
+	"UsuarioHandler.GetUsuario":                                                   "Obtiene un usuario por ID",
+	"UsuarioHandler.GetUsuarioByUsername":                                         "Busca usuario por nombre de usuario",
+	"UsuarioHandler.GetUsuariosByPersona":                                         "Obtiene usuarios por persona",
+	"UsuarioHandler.GetUsuariosByTipo":                                            "Obtiene usuarios por tipo",
+	"UsuarioHandler.Login":                                                        "Valida credenciales de usuario",
+	"UsuarioHandler.PatchUsuario":                                                 "Actualiza solo los campos enviados de un usuario (JSON Merge Patch)",
+	"UsuarioHandler.RestoreUsuario":                                               "Restaura un usuario eliminado",
+	"UsuarioHandler.UpdateUsuario":                                                "Actualiza un usuario",
+	"VisitaDetalleEstudiantesUniversitariosHandler.CreateVisitaDetalleEstudiantesUniversitarios": "Crea una nueva relación entre estudiante universitario y programa de visita",
+	"VisitaDetalleEstudiantesUniversitariosHandler.DeleteByEstudiante":                           "Elimina todas las relaciones de un estudiante específico",
+	"VisitaDetalleEstudiantesUniversitariosHandler.DeleteByProgramaVisita":                       "Elimina todas las relaciones de un programa de visita específico",
+	"VisitaDetalleEstudiantesUniversitariosHandler.DeleteVisitaDetalleEstudiantesUniversitarios": "Elimina una relación",
+	"VisitaDetalleEstudiantesUniversitariosHandler.GetAllVisitaDetalleEstudiantesUniversitarios": "Obtiene todas las relaciones",
+	"VisitaDetalleEstudiantesUniversitariosHandler.GetEstadisticasParticipacion":                 "Obtiene estadísticas de participación de estudiantes",
+	"VisitaDetalleEstudiantesUniversitariosHandler.GetEstudiantesByProgramaVisita":               "Obtiene estudiantes por programa de visita",
+	"VisitaDetalleEstudiantesUniversitariosHandler.GetProgramasVisitaByEstudiante":               "Obtiene programas de visita por estudiante universitario",
+	"VisitaDetalleEstudiantesUniversitariosHandler.GetVisitaDetalleEstudiantesUniversitarios":    "Obtiene una relación por ID",
+	"VisitaDetalleEstudiantesUniversitariosHandler.PatchVisitaDetalleEstudiantesUniversitarios":  "Actualiza solo los campos enviados de una participación de estudiante universitario (JSON Merge Patch)",
+	"VisitaDetalleEstudiantesUniversitariosHandler.UpdateVisitaDetalleEstudiantesUniversitarios": "Actualiza una relación",
+	"VisitaDetalleHandler.CreateVisitaDetalle":                                                   "Crea un nuevo detalle de visita",
+	"VisitaDetalleHandler.DeleteVisitaDetalle":                                                   "Elimina un detalle de visita",
+	"VisitaDetalleHandler.DeleteVisitaDetallesByActividad":                                       "Elimina todos los detalles de una actividad específica",
+	"VisitaDetalleHandler.DeleteVisitaDetallesByPrograma":                                        "Elimina todos los detalles de un programa de visita específico",
+	"VisitaDetalleHandler.GetAllVisitaDetalles":                                                  "Obtiene todos los detalles de visita",
+	"VisitaDetalleHandler.GetEstadisticasActividades":                                            "Obtiene estadísticas de actividades en visitas",
+	"VisitaDetalleHandler.GetVisitaDetalle":                                                      "Obtiene un detalle de visita por ID",
+	"VisitaDetalleHandler.GetVisitaDetallesByActividad":                                          "Obtiene detalles por actividad",
+	"VisitaDetalleHandler.GetVisitaDetallesByPrograma":                                           "Obtiene detalles por programa de visita",
+	"VisitaDetalleHandler.PatchVisitaDetalle":                                                    "Actualiza solo los campos enviados de un detalle de visita (JSON Merge Patch)",
+	"VisitaDetalleHandler.UpdateVisitaDetalle":                                                   "Actualiza un detalle de visita",
+	"WebhookHandler.CreateWebhook":                                                               "Crea una suscripción; la respuesta es la única que incluye el secreto de la firma",
+	"WebhookHandler.DeleteWebhook":                                                               "Elimina una suscripción; su registro de entregas se conserva",
+	"WebhookHandler.GetEntregas":                                                                 "Lista las entregas de una suscripción, las más recientes primero",
+	"WebhookHandler.GetEventos":                                                                  "Devuelve el catálogo de eventos que se pueden suscribir",
+	"WebhookHandler.GetWebhook":                                                                  "Obtiene una suscripción de webhook por su ID",
+	"WebhookHandler.GetWebhooks":                                                                 "Lista las suscripciones de webhooks",
+	"WebhookHandler.PatchWebhook":                                                                "Actualiza solo los campos enviados de una suscripción (JSON Merge Patch)",
+	"WebhookHandler.ProbarWebhook":                                                               "Envía un evento webhook.ping a la suscripción y devuelve el resultado de la entrega",
+	"WebhookHandler.ReenviarEntrega":                                                             "Vuelve a enviar el cuerpo de una entrega como una entrega nueva",
+	"WebhookHandler.UpdateWebhook":                                                               "Reemplaza la URL, los eventos, la descripción y el estado de una suscripción",
+	"WhatsAppHandler.CancelQueue":                                                                "Cancela todos los mensajes en cola",
+	"WhatsAppHandler.GetQR":                                                                      "Obtiene el código QR actual",
+	"WhatsAppHandler.GetQueueStatus":                                                             "Obtiene el estado de la cola de mensajes",
+	"WhatsAppHandler.GetServiceURL":                                                              "Retorna la URL del servicio; la usa el monitor que publica su estado en /api/eventos",
+	"WhatsAppHandler.GetStatus":                                                                  "Obtiene el estado actual de WhatsApp",
+	"WhatsAppHandler.Logout":                                                                     "Cierra la sesión de WhatsApp",
+	"WhatsAppHandler.SendBulk":                                                                   "Envía mensajes de WhatsApp en cola",
+	"WhatsAppHandler.SendMedia":                                                                  "Envía una imagen por WhatsApp",
+	"WhatsAppHandler.SendMessage":                                                                "Envía un mensaje de WhatsApp",
+}
@@ -0,0 +1,157 @@
+// Package errores define los errores de dominio tipados de la API.
+//
+// Repositorios y servicios devuelven *Error (o un error que lo envuelve) en lugar de mensajes
+// sueltos; los handlers los comparan con errors.Is / errors.As y el ErrorHandler de Fiber los
+// convierte en la respuesta de error estándar. El código de cada error es estable: los clientes
+// pueden depender de él aunque cambie el mensaje.
+package errores
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Tipo clasifica un error de dominio y determina su estado HTTP
+type Tipo string
+
+const (
+	TipoNoEncontrado Tipo = "no_encontrado"
+	TipoConflicto    Tipo = "conflicto"
+	TipoValidacion   Tipo = "validacion"
+	TipoProhibido    Tipo = "prohibido"
+	TipoExterno      Tipo = "externo"
+	TipoPrecondicion Tipo = "precondicion"
+	TipoNoProcesable Tipo = "no_procesable"
+)
+
+// Campo es un campo que no pasó la validación
+type Campo struct {
+	Campo   string `json:"field"`
+	Mensaje string `json:"message"`
+	Valor   string `json:"value,omitempty"`
+}
+
+// Error es un error de dominio con un código estable
+type Error struct {
+	Tipo    Tipo
+	Codigo  string
+	Mensaje string
+	// Restriccion es la restricción de la base de datos violada (solo conflictos)
+	Restriccion string
+	// Campos son los campos inválidos (solo validaciones y errores no procesables)
+	Campos []Campo
+	// Causa es el error original, si lo hay
+	Causa error
+}
+
+// Errores genéricos por tipo; sirven para comparar solo el tipo con errors.Is
+var (
+	ErrNoEncontrado = &Error{Tipo: TipoNoEncontrado}
+	ErrConflicto    = &Error{Tipo: TipoConflicto}
+	ErrValidacion   = &Error{Tipo: TipoValidacion}
+	ErrProhibido    = &Error{Tipo: TipoProhibido}
+	ErrExterno      = &Error{Tipo: TipoExterno}
+	ErrPrecondicion = &Error{Tipo: TipoPrecondicion}
+	ErrNoProcesable = &Error{Tipo: TipoNoProcesable}
+)
+
+// NoEncontrado crea un error de recurso inexistente (404)
+func NoEncontrado(codigo, mensaje string) *Error {
+	return &Error{Tipo: TipoNoEncontrado, Codigo: codigo, Mensaje: mensaje}
+}
+
+// Conflicto crea un error de conflicto con el estado actual, p. ej. un duplicado (409)
+func Conflicto(codigo, mensaje string) *Error {
+	return &Error{Tipo: TipoConflicto, Codigo: codigo, Mensaje: mensaje}
+}
+
+// Validacion crea un error de datos inválidos (400), opcionalmente con los campos afectados
+func Validacion(codigo, mensaje string, campos ...Campo) *Error {
+	return &Error{Tipo: TipoValidacion, Codigo: codigo, Mensaje: mensaje, Campos: campos}
+}
+
+// Prohibido crea un error de operación no permitida para el usuario (403)
+func Prohibido(codigo, mensaje string) *Error {
+	return &Error{Tipo: TipoProhibido, Codigo: codigo, Mensaje: mensaje}
+}
+
+// Externo crea un error de un servicio del que dependemos: base de datos, SMTP, etc. (502)
+func Externo(codigo, mensaje string, causa error) *Error {
+	return &Error{Tipo: TipoExterno, Codigo: codigo, Mensaje: mensaje, Causa: causa}
+}
+
+// Precondicion crea un error de versión desactualizada: el registro cambió desde que el
+// cliente lo leyó (412)
+func Precondicion(codigo, mensaje string) *Error {
+	return &Error{Tipo: TipoPrecondicion, Codigo: codigo, Mensaje: mensaje}
+}
+
+// NoProcesable crea un error de datos bien formados que no se pueden aplicar al registro (422)
+func NoProcesable(codigo, mensaje string, campos ...Campo) *Error {
+	return &Error{Tipo: TipoNoProcesable, Codigo: codigo, Mensaje: mensaje, Campos: campos}
+}
+
+func (e *Error) Error() string {
+	if e.Causa != nil && e.Tipo == TipoExterno {
+		return e.Mensaje + ": " + e.Causa.Error()
+	}
+	return e.Mensaje
+}
+
+func (e *Error) Unwrap() error {
+	return e.Causa
+}
+
+// Is compara por código; si el objetivo no tiene código (ErrConflicto, ...), compara solo el tipo
+func (e *Error) Is(objetivo error) bool {
+	t, ok := objetivo.(*Error)
+	if !ok {
+		return false
+	}
+	if t.Codigo == "" {
+		return t.Tipo == e.Tipo
+	}
+	return t.Codigo == e.Codigo && t.Tipo == e.Tipo
+}
+
+// ConCausa devuelve una copia del error que envuelve la causa. Si la causa es un conflicto,
+// la copia conserva la restricción violada.
+func (e *Error) ConCausa(causa error) *Error {
+	copia := *e
+	copia.Causa = causa
+	var origen *Error
+	if errors.As(causa, &origen) && copia.Restriccion == "" {
+		copia.Restriccion = origen.Restriccion
+	}
+	return &copia
+}
+
+// Estado es el estado HTTP que corresponde al tipo del error
+func (e *Error) Estado() int {
+	switch e.Tipo {
+	case TipoNoEncontrado:
+		return http.StatusNotFound
+	case TipoConflicto:
+		return http.StatusConflict
+	case TipoValidacion:
+		return http.StatusBadRequest
+	case TipoProhibido:
+		return http.StatusForbidden
+	case TipoExterno:
+		return http.StatusBadGateway
+	case TipoPrecondicion:
+		return http.StatusPreconditionFailed
+	case TipoNoProcesable:
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
+
+// Como extrae el error de dominio de err, si lo hay
+func Como(err error) (*Error, bool) {
+	var e *Error
+	if errors.As(err, &e) {
+		return e, true
+	}
+	return nil, false
+}
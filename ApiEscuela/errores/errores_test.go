@@ -0,0 +1,73 @@
+package errores_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"ApiEscuela/errores"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+func TestIsComparaCodigoYTipo(t *testing.T) {
+	duplicado := errores.Conflicto("duplicate_cedula", "Ya existe una persona con esta cédula")
+	envuelto := fmt.Errorf("crear persona: %w", duplicado.ConCausa(errors.New("pg")))
+
+	if !errors.Is(envuelto, duplicado) {
+		t.Error("la copia con causa debe coincidir con el centinela")
+	}
+	if !errors.Is(envuelto, errores.ErrConflicto) {
+		t.Error("debe coincidir con el error genérico de su tipo")
+	}
+	if errors.Is(envuelto, errores.ErrNoEncontrado) || errors.Is(envuelto, errores.Conflicto("duplicate_email", "")) {
+		t.Error("no debe coincidir con otro tipo ni con otro código")
+	}
+	if e, ok := errores.Como(envuelto); !ok || e.Estado() != http.StatusConflict {
+		t.Errorf("Como = %v, %v", e, ok)
+	}
+}
+
+func TestDesdeBD(t *testing.T) {
+	tests := []struct {
+		name            string
+		err             error
+		wantCodigo      string
+		wantEstado      int
+		wantRestriccion string
+	}{
+		{name: "no encontrado", err: gorm.ErrRecordNotFound, wantCodigo: errores.CodigoNoEncontrado, wantEstado: http.StatusNotFound},
+		{name: "único", err: &pgconn.PgError{Code: "23505", ConstraintName: "uni_personas_cedula"}, wantCodigo: errores.CodigoDuplicado, wantEstado: http.StatusConflict, wantRestriccion: "uni_personas_cedula"},
+		{name: "clave foránea", err: fmt.Errorf("envuelto: %w", &pgconn.PgError{Code: "23503", ConstraintName: "fk_usuarios_persona"}), wantCodigo: errores.CodigoReferenciaInvalida, wantEstado: http.StatusConflict, wantRestriccion: "fk_usuarios_persona"},
+		{name: "no nulo", err: &pgconn.PgError{Code: "23502", ColumnName: "cedula"}, wantCodigo: errores.CodigoCampoRequerido, wantEstado: http.StatusBadRequest},
+		{name: "check", err: &pgconn.PgError{Code: "23514", ConstraintName: "chk_estado"}, wantCodigo: errores.CodigoRestriccion, wantEstado: http.StatusBadRequest, wantRestriccion: "chk_estado"},
+		{name: "serialización", err: &pgconn.PgError{Code: "40001"}, wantCodigo: errores.CodigoConcurrencia, wantEstado: http.StatusConflict},
+		{name: "conexión", err: &pgconn.PgError{Code: "08006"}, wantCodigo: errores.CodigoBaseDatos, wantEstado: http.StatusBadGateway},
+		{name: "gorm duplicado", err: gorm.ErrDuplicatedKey, wantCodigo: errores.CodigoDuplicado, wantEstado: http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, ok := errores.Como(errores.DesdeBD(tt.err))
+			if !ok {
+				t.Fatalf("DesdeBD(%v) no devolvió un error tipado", tt.err)
+			}
+			if e.Codigo != tt.wantCodigo || e.Estado() != tt.wantEstado || e.Restriccion != tt.wantRestriccion {
+				t.Errorf("DesdeBD = %+v", e)
+			}
+			var pgErr *pgconn.PgError
+			if !errors.Is(e, tt.err) && !errors.As(e, &pgErr) {
+				t.Error("el error traducido debe envolver el original")
+			}
+		})
+	}
+
+	otro := errors.New("otro")
+	if errores.DesdeBD(otro) != otro || errores.DesdeBD(nil) != nil {
+		t.Error("los errores desconocidos se devuelven sin cambios")
+	}
+	if e, _ := errores.Como(errores.DesdeBD(&pgconn.PgError{Code: "23502", ColumnName: "cedula"})); len(e.Campos) != 1 || e.Campos[0].Campo != "cedula" {
+		t.Errorf("campos = %v", e.Campos)
+	}
+}
@@ -0,0 +1,85 @@
+package errores
+
+import (
+	"database/sql/driver"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// Códigos de los errores que produce DesdeBD
+const (
+	CodigoNoEncontrado       = "not_found"
+	CodigoDuplicado          = "duplicate_key"
+	CodigoReferenciaInvalida = "foreign_key_violation"
+	CodigoCampoRequerido     = "not_null_violation"
+	CodigoRestriccion        = "check_violation"
+	CodigoValorInvalido      = "invalid_value"
+	CodigoConcurrencia       = "concurrent_update"
+	CodigoBaseDatos          = "database_unavailable"
+)
+
+// DesdeBD traduce un error de GORM o de Postgres a un error de dominio. Es el único lugar que
+// conoce los códigos SQLSTATE; los errores que no reconoce se devuelven sin cambios.
+func DesdeBD(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := Como(err); ok {
+		return err
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &Error{Tipo: TipoNoEncontrado, Codigo: CodigoNoEncontrado, Mensaje: "No se encontró el registro solicitado", Causa: err}
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return desdePostgres(pgErr)
+	}
+
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return &Error{Tipo: TipoConflicto, Codigo: CodigoDuplicado, Mensaje: "El registro ya existe", Causa: err}
+	}
+	var netErr net.Error
+	if errors.Is(err, driver.ErrBadConn) || pgconn.Timeout(err) || errors.As(err, &netErr) {
+		return Externo(CodigoBaseDatos, "La base de datos no está disponible", err)
+	}
+	return err
+}
+
+func desdePostgres(pgErr *pgconn.PgError) error {
+	switch {
+	case pgErr.Code == "23505":
+		return &Error{Tipo: TipoConflicto, Codigo: CodigoDuplicado, Mensaje: "El registro ya existe", Restriccion: pgErr.ConstraintName, Causa: pgErr}
+	case pgErr.Code == "23503":
+		return &Error{Tipo: TipoConflicto, Codigo: CodigoReferenciaInvalida, Mensaje: "El registro está relacionado con otros registros o referencia uno inexistente", Restriccion: pgErr.ConstraintName, Causa: pgErr}
+	case pgErr.Code == "23502":
+		return &Error{
+			Tipo:    TipoValidacion,
+			Codigo:  CodigoCampoRequerido,
+			Mensaje: "Falta un campo requerido",
+			Campos:  []Campo{{Campo: pgErr.ColumnName, Mensaje: "El campo es requerido"}},
+			Causa:   pgErr,
+		}
+	case pgErr.Code == "23514":
+		return &Error{Tipo: TipoValidacion, Codigo: CodigoRestriccion, Mensaje: "Los datos no cumplen una restricción", Restriccion: pgErr.ConstraintName, Causa: pgErr}
+	case strings.HasPrefix(pgErr.Code, "22"):
+		return &Error{Tipo: TipoValidacion, Codigo: CodigoValorInvalido, Mensaje: "Valor inválido para la base de datos", Causa: pgErr}
+	case pgErr.Code == "40001" || pgErr.Code == "40P01":
+		return &Error{Tipo: TipoConflicto, Codigo: CodigoConcurrencia, Mensaje: "El registro fue modificado por otra operación; reintente", Causa: pgErr}
+	case strings.HasPrefix(pgErr.Code, "08") || strings.HasPrefix(pgErr.Code, "53") || strings.HasPrefix(pgErr.Code, "57P"):
+		return Externo(CodigoBaseDatos, "La base de datos no está disponible", pgErr)
+	}
+	return pgErr
+}
+
+// Restriccion devuelve la restricción violada por err, o "" si no es un conflicto de la base de datos
+func Restriccion(err error) string {
+	if e, ok := Como(err); ok {
+		return e.Restriccion
+	}
+	return ""
+}
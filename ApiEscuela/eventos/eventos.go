@@ -0,0 +1,223 @@
+// Package eventos es el bus interno con el que los servicios avisan cambios de estado
+// (WhatsApp, envíos de comunicados, cargas masivas, notificaciones) a los clientes conectados
+// a /api/eventos.
+//
+// El bus vive en memoria del proceso: con varias réplicas cada una publica solo lo que ocurre
+// en ella. Guarda los últimos eventos para que un cliente que se reconecta con Last-Event-ID
+// reciba lo que se perdió.
+package eventos
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// Tipos de evento
+const (
+	WhatsAppEstado      = "whatsapp.estado"
+	WhatsAppQR          = "whatsapp.qr"
+	WhatsAppCola        = "whatsapp.cola"
+	ComunicadoProgreso  = "comunicado.progreso"
+	ImportacionProgreso = "importacion.progreso"
+	NotificacionNueva   = "notificacion.nueva"
+)
+
+// Estados son los tipos que describen un estado vigente y no un suceso: una suscripción nueva
+// recibe primero el último evento publicado de cada uno
+var Estados = []string{WhatsAppEstado, WhatsAppQR, WhatsAppCola}
+
+// Historial es la cantidad de eventos que se conservan para las reconexiones
+const Historial = 256
+
+// pendientes es la cantidad de eventos que una suscripción puede acumular sin leer; si la
+// supera se cierra y el cliente debe reconectarse con Last-Event-ID. Alcanza para el historial.
+const pendientes = Historial
+
+// Evento es un aviso publicado en el bus
+type Evento struct {
+	ID    uint64      `json:"id"`
+	Tipo  string      `json:"tipo"`
+	Fecha time.Time   `json:"fecha"`
+	Datos interface{} `json:"datos"`
+	// UsuarioID limita el evento al usuario que inició la operación; 0 lo envía a todos
+	UsuarioID uint `json:"-"`
+	// Administradores limita el evento a las suscripciones de administradores
+	Administradores bool `json:"-"`
+}
+
+// Progreso son los datos de los eventos de avance de un trabajo (envío, carga masiva)
+type Progreso struct {
+	Trabajo    string `json:"trabajo"`
+	Etapa      string `json:"etapa"`
+	Procesados int    `json:"procesados"`
+	Total      int    `json:"total"`
+	Exitosos   int    `json:"exitosos"`
+	Fallidos   int    `json:"fallidos"`
+	Terminado  bool   `json:"terminado"`
+	Mensaje    string `json:"mensaje,omitempty"`
+}
+
+// NuevoTrabajo genera el identificador de un trabajo cuando el cliente no envía uno
+func NuevoTrabajo() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Suscripcion recibe en C los eventos publicados desde que se creó. C se cierra al cancelar la
+// suscripción, al cerrar el bus o si el cliente no lee a tiempo.
+type Suscripcion struct {
+	C             <-chan Evento
+	c             chan Evento
+	usuarioID     uint
+	administrador bool
+	bus           *Bus
+}
+
+// Cancelar deja de recibir eventos
+func (s *Suscripcion) Cancelar() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	s.bus.quitar(s)
+}
+
+// Bus reparte los eventos publicados entre las suscripciones. Un *Bus nil descarta lo que se
+// publica, para los comandos que usan los servicios sin servidor.
+type Bus struct {
+	mu            sync.Mutex
+	ultimo        uint64
+	historial     []Evento
+	vigentes      map[string]Evento // último evento de cada tipo de Estados
+	suscripciones map[*Suscripcion]struct{}
+	cerrado       bool
+}
+
+// New crea un bus vacío
+func New() *Bus {
+	return &Bus{vigentes: map[string]Evento{}, suscripciones: map[*Suscripcion]struct{}{}}
+}
+
+// Publicar envía un evento a todos los usuarios
+func (b *Bus) Publicar(tipo string, datos interface{}) {
+	b.PublicarPara(0, tipo, datos)
+}
+
+// PublicarPara envía un evento solo a las suscripciones del usuario; 0 lo envía a todos
+func (b *Bus) PublicarPara(usuarioID uint, tipo string, datos interface{}) {
+	b.publicar(Evento{Tipo: tipo, Datos: datos, UsuarioID: usuarioID})
+}
+
+// PublicarAdministradores envía un evento solo a las suscripciones de administradores, p. ej. el
+// código QR que vincula la cuenta de WhatsApp
+func (b *Bus) PublicarAdministradores(tipo string, datos interface{}) {
+	b.publicar(Evento{Tipo: tipo, Datos: datos, Administradores: true})
+}
+
+// publicar numera el evento, lo guarda en el historial y lo entrega
+func (b *Bus) publicar(e Evento) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.cerrado {
+		return
+	}
+	b.ultimo++
+	e.ID, e.Fecha = b.ultimo, time.Now()
+	b.historial = append(b.historial, e)
+	if len(b.historial) > Historial {
+		b.historial = b.historial[len(b.historial)-Historial:]
+	}
+	if e.UsuarioID == 0 && esEstado(e.Tipo) {
+		b.vigentes[e.Tipo] = e
+	}
+	for s := range b.suscripciones {
+		b.entregar(s, e)
+	}
+}
+
+// Suscribir crea una suscripción para el usuario; administrador habilita los eventos reservados
+// a los administradores. Con desde > 0 recibe primero los eventos del historial posteriores a ese
+// ID (el Last-Event-ID del cliente); sin él, los estados vigentes.
+func (b *Bus) Suscribir(usuarioID uint, administrador bool, desde uint64) *Suscripcion {
+	c := make(chan Evento, pendientes)
+	s := &Suscripcion{C: c, c: c, usuarioID: usuarioID, administrador: administrador, bus: b}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.cerrado {
+		close(c)
+		return s
+	}
+	b.suscripciones[s] = struct{}{}
+	if desde > 0 {
+		for _, e := range b.historial {
+			if e.ID > desde {
+				b.entregar(s, e)
+			}
+		}
+		return s
+	}
+	for _, tipo := range Estados {
+		if e, ok := b.vigentes[tipo]; ok {
+			b.entregar(s, e)
+		}
+	}
+	return s
+}
+
+// Suscriptores devuelve la cantidad de suscripciones activas
+func (b *Bus) Suscriptores() int {
+	if b == nil {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.suscripciones)
+}
+
+// Cerrar termina todas las suscripciones; lo que se publique después se descarta
+func (b *Bus) Cerrar() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cerrado = true
+	for s := range b.suscripciones {
+		b.quitar(s)
+	}
+}
+
+// entregar envía el evento a la suscripción si le corresponde. b.mu debe estar tomado.
+func (b *Bus) entregar(s *Suscripcion, e Evento) {
+	if _, ok := b.suscripciones[s]; !ok {
+		return
+	}
+	if (e.UsuarioID != 0 && e.UsuarioID != s.usuarioID) || (e.Administradores && !s.administrador) {
+		return
+	}
+	select {
+	case s.c <- e:
+	default:
+		// El cliente no lee: se corta para no retener el bus; al reconectarse recupera el historial
+		b.quitar(s)
+	}
+}
+
+// quitar elimina la suscripción y cierra su canal. b.mu debe estar tomado.
+func (b *Bus) quitar(s *Suscripcion) {
+	if _, ok := b.suscripciones[s]; !ok {
+		return
+	}
+	delete(b.suscripciones, s)
+	close(s.c)
+}
+
+func esEstado(tipo string) bool {
+	for _, t := range Estados {
+		if t == tipo {
+			return true
+		}
+	}
+	return false
+}
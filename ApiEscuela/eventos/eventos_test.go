@@ -0,0 +1,105 @@
+package eventos
+
+import "testing"
+
+// recibidos lee los eventos pendientes de la suscripción sin bloquear
+func recibidos(s *Suscripcion) []Evento {
+	var lista []Evento
+	for {
+		select {
+		case e, ok := <-s.C:
+			if !ok {
+				return lista
+			}
+			lista = append(lista, e)
+		default:
+			return lista
+		}
+	}
+}
+
+func TestBusFiltraPorUsuario(t *testing.T) {
+	bus := New()
+	ana := bus.Suscribir(1, false, 0)
+	luis := bus.Suscribir(2, false, 0)
+
+	bus.Publicar(WhatsAppEstado, "ready")
+	bus.PublicarPara(1, ImportacionProgreso, Progreso{Trabajo: "a"})
+
+	if got := recibidos(ana); len(got) != 2 || got[1].Tipo != ImportacionProgreso {
+		t.Errorf("el usuario 1 recibió %+v", got)
+	}
+	if got := recibidos(luis); len(got) != 1 || got[0].Tipo != WhatsAppEstado {
+		t.Errorf("el usuario 2 recibió %+v, se esperaba solo el evento general", got)
+	}
+
+	luis.Cancelar()
+	if _, ok := <-luis.C; ok || bus.Suscriptores() != 1 {
+		t.Errorf("la suscripción cancelada sigue activa (%d suscriptores)", bus.Suscriptores())
+	}
+}
+
+func TestBusEventosDeAdministradores(t *testing.T) {
+	bus := New()
+	bus.Publicar(ComunicadoProgreso, Progreso{Trabajo: "x"})
+	admin := bus.Suscribir(1, true, 0)
+	estudiante := bus.Suscribir(2, false, 0)
+
+	bus.PublicarAdministradores(WhatsAppQR, "codigo")
+	if got := recibidos(admin); len(got) != 1 || got[0].Datos != "codigo" {
+		t.Errorf("el administrador recibió %+v", got)
+	}
+	if got := recibidos(estudiante); len(got) != 0 {
+		t.Errorf("el estudiante recibió %+v", got)
+	}
+	// Ni el estado vigente ni el historial llegan a quien no es administrador
+	if got := recibidos(bus.Suscribir(3, false, 0)); len(got) != 0 {
+		t.Errorf("suscripción nueva recibió %+v", got)
+	}
+	if got := recibidos(bus.Suscribir(3, false, 1)); len(got) != 0 {
+		t.Errorf("reconexión desde 1 recibió %+v", got)
+	}
+	if got := recibidos(bus.Suscribir(4, true, 0)); len(got) != 1 || got[0].Tipo != WhatsAppQR {
+		t.Errorf("administrador nuevo recibió %+v, se esperaba el último whatsapp.qr", got)
+	}
+}
+
+func TestBusReconexion(t *testing.T) {
+	bus := New()
+	bus.Publicar(WhatsAppEstado, "qr")
+	bus.Publicar(ComunicadoProgreso, Progreso{Trabajo: "x", Etapa: "enviando"})
+	bus.Publicar(WhatsAppEstado, "ready")
+	bus.PublicarPara(9, ImportacionProgreso, Progreso{Trabajo: "y"})
+
+	// Sin Last-Event-ID llega solo el estado vigente de cada tipo de estado
+	if got := recibidos(bus.Suscribir(1, false, 0)); len(got) != 1 || got[0].Datos != "ready" {
+		t.Errorf("suscripción nueva recibió %+v, se esperaba el último whatsapp.estado", got)
+	}
+	// Con Last-Event-ID llega lo publicado después, sin los eventos de otros usuarios
+	got := recibidos(bus.Suscribir(1, false, 1))
+	if len(got) != 2 || got[0].ID != 2 || got[1].ID != 3 {
+		t.Errorf("reconexión desde 1 recibió %+v", got)
+	}
+}
+
+func TestBusCortaSuscripcionLenta(t *testing.T) {
+	bus := New()
+	lenta := bus.Suscribir(1, false, 0)
+	for i := 0; i <= pendientes; i++ {
+		bus.Publicar(ComunicadoProgreso, i)
+	}
+	if bus.Suscriptores() != 0 {
+		t.Fatal("la suscripción que no lee debió cerrarse")
+	}
+	if got := recibidos(lenta); len(got) != pendientes {
+		t.Errorf("se entregaron %d eventos antes del corte, se esperaban %d", len(got), pendientes)
+	}
+
+	bus.Cerrar()
+	bus.Publicar(WhatsAppEstado, "ready")
+	if _, ok := <-bus.Suscribir(1, false, 0).C; ok {
+		t.Error("una suscripción a un bus cerrado debe nacer cerrada")
+	}
+	var nulo *Bus
+	nulo.Publicar(WhatsAppEstado, "ready") // no debe entrar en pánico
+}
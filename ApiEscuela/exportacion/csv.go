@@ -0,0 +1,50 @@
+package exportacion
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+)
+
+// bomUTF8 hace que Excel abra el CSV como UTF-8 y muestre bien las tildes
+const bomUTF8 = "\ufeff"
+
+type escritorCSV struct {
+	w *csv.Writer
+}
+
+func nuevoEscritorCSV(w io.Writer, columnas []Columna) (*escritorCSV, error) {
+	if _, err := io.WriteString(w, bomUTF8); err != nil {
+		return nil, err
+	}
+	e := &escritorCSV{w: csv.NewWriter(w)}
+	titulos := make([]string, len(columnas))
+	for i, c := range columnas {
+		titulos[i] = c.Titulo
+	}
+	return e, e.w.Write(titulos)
+}
+
+func (e *escritorCSV) Fila(valores []string) error {
+	fila := make([]string, len(valores))
+	for i, v := range valores {
+		fila[i] = sinFormula(v)
+	}
+	return e.w.Write(fila)
+}
+
+func (e *escritorCSV) Cerrar() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *escritorCSV) Descartar() {}
+
+// sinFormula antepone un apóstrofo a los valores que una hoja de cálculo interpretaría como
+// fórmula (inyección de CSV)
+func sinFormula(valor string) string {
+	if valor != "" && strings.ContainsRune("=+-@\t\r", rune(valor[0])) {
+		return "'" + valor
+	}
+	return valor
+}
@@ -0,0 +1,81 @@
+// Package exportacion escribe listas de registros como CSV, XLSX o PDF. Las filas se escriben a
+// medida que llegan: el CSV y el XLSX no guardan la lista completa en memoria (el XLSX pasa a un
+// archivo temporal cuando crece), por eso sirven para exportar tablas grandes por lotes.
+package exportacion
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Formatos de exportación
+const (
+	FormatoCSV  = "csv"
+	FormatoXLSX = "xlsx"
+	FormatoPDF  = "pdf"
+)
+
+// Formatos son los formatos admitidos, en el orden en que se ofrecen
+var Formatos = []string{FormatoCSV, FormatoXLSX, FormatoPDF}
+
+// tiposContenido son los Content-Type de cada formato
+var tiposContenido = map[string]string{
+	FormatoCSV:  "text/csv; charset=utf-8",
+	FormatoXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	FormatoPDF:  "application/pdf",
+}
+
+// TipoContenido devuelve el Content-Type del formato, sin parámetros si sinParametros es true
+// (para comparar con el encabezado Accept)
+func TipoContenido(formato string, sinParametros bool) string {
+	tipo := tiposContenido[formato]
+	if sinParametros {
+		tipo, _, _ = strings.Cut(tipo, ";")
+	}
+	return tipo
+}
+
+// EsFormato indica si el formato es uno de Formatos
+func EsFormato(formato string) bool {
+	_, ok := tiposContenido[formato]
+	return ok
+}
+
+// Columna es una columna de la lista exportada
+type Columna struct {
+	// Clave identifica la columna en ?columnas=
+	Clave string `json:"clave"`
+	// Titulo es el encabezado de la columna
+	Titulo string `json:"titulo"`
+	// Ancho es el ancho relativo de la columna en el PDF; 0 equivale a 1
+	Ancho float64 `json:"-"`
+}
+
+// Escritor escribe las filas de una lista; cada fila tiene un valor por columna
+type Escritor interface {
+	Fila(valores []string) error
+	// Cerrar termina el archivo. El XLSX y el PDF se escriben en el io.Writer recién aquí.
+	Cerrar() error
+	// Descartar libera los recursos sin terminar el archivo, cuando la exportación falla a medias
+	Descartar()
+}
+
+// NuevoEscritor crea el escritor del formato con los encabezados de las columnas. titulo
+// encabeza el PDF y nombra la hoja del XLSX.
+func NuevoEscritor(formato string, w io.Writer, titulo string, columnas []Columna) (Escritor, error) {
+	switch formato {
+	case FormatoCSV:
+		return nuevoEscritorCSV(w, columnas)
+	case FormatoXLSX:
+		return nuevoEscritorXLSX(w, titulo, columnas)
+	case FormatoPDF:
+		return nuevoEscritorPDF(w, titulo, columnas), nil
+	}
+	return nil, fmt.Errorf("formato de exportación desconocido: %q", formato)
+}
+
+// NombreArchivo devuelve el nombre del archivo descargado: base, marca de tiempo y extensión
+func NombreArchivo(base, marca, formato string) string {
+	return base + "-" + marca + "." + formato
+}
@@ -0,0 +1,61 @@
+package exportacion
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSinFormula(t *testing.T) {
+	casos := map[string]string{
+		"=SUMA(A1)":   "'=SUMA(A1)",
+		"+593 99":     "'+593 99",
+		"-1":          "'-1",
+		"@usuario":    "'@usuario",
+		"Ana Muñoz":   "Ana Muñoz",
+		"":            "",
+		"correo@uteq": "correo@uteq",
+	}
+	for valor, esperado := range casos {
+		if obtenido := sinFormula(valor); obtenido != esperado {
+			t.Errorf("sinFormula(%q) = %q, se esperaba %q", valor, obtenido, esperado)
+		}
+	}
+}
+
+func TestNombreHoja(t *testing.T) {
+	casos := map[string]string{
+		"Dudas":               "Dudas",
+		"Programas [2025/01]": "Programas -2025-01-",
+		"Participantes de las visitas de la UTEQ": "Participantes de las visitas de",
+		" ": hojaPredeterminada,
+	}
+	for titulo, esperado := range casos {
+		if obtenido := nombreHoja(titulo); obtenido != esperado {
+			t.Errorf("nombreHoja(%q) = %q, se esperaba %q", titulo, obtenido, esperado)
+		}
+	}
+}
+
+func TestEscritorPDFVariasPaginas(t *testing.T) {
+	var salida bytes.Buffer
+	escritor, err := NuevoEscritor(FormatoPDF, &salida, "Dudas", []Columna{
+		{Clave: "pregunta", Titulo: "Pregunta", Ancho: 3},
+		{Clave: "estudiante", Titulo: "Estudiante"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	largo := strings.Repeat("¿Cuándo es la próxima visita al campus? ", 20)
+	for i := 0; i < 120; i++ {
+		if err := escritor.Fila([]string{largo, "Ana Muñoz"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := escritor.Cerrar(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(salida.Bytes(), []byte("%PDF")) || bytes.Count(salida.Bytes(), []byte("/Type /Page\n")) < 2 {
+		t.Errorf("se esperaba un PDF de varias páginas (%d bytes)", salida.Len())
+	}
+}
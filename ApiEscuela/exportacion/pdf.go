@@ -0,0 +1,95 @@
+package exportacion
+
+import (
+	"io"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Medidas del PDF, en milímetros
+const (
+	altoFilaPDF    = 6
+	margenCeldaPDF = 1
+)
+
+// escritorPDF arma una tabla en A4 horizontal con los encabezados repetidos en cada página. A
+// diferencia del CSV y el XLSX, el documento se arma en memoria: conviene para listas que se
+// imprimen, no para exportaciones masivas.
+type escritorPDF struct {
+	w        io.Writer
+	pdf      *gofpdf.Fpdf
+	anchos   []float64
+	traducir func(string) string
+}
+
+func nuevoEscritorPDF(w io.Writer, titulo string, columnas []Columna) *escritorPDF {
+	pdf := gofpdf.New("L", "mm", "A4", "")
+	e := &escritorPDF{w: w, pdf: pdf, traducir: pdf.UnicodeTranslatorFromDescriptor("")}
+	pdf.SetTitle(titulo, true)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.AliasNbPages("")
+
+	// Las columnas se reparten el ancho útil de la página según su ancho relativo
+	anchoPagina, _ := pdf.GetPageSize()
+	izquierdo, _, derecho, _ := pdf.GetMargins()
+	total := 0.0
+	for _, c := range columnas {
+		total += ancho(c)
+	}
+	for _, c := range columnas {
+		e.anchos = append(e.anchos, (anchoPagina-izquierdo-derecho)*ancho(c)/total)
+	}
+
+	generado := time.Now().Format("02/01/2006 15:04")
+	pdf.SetHeaderFunc(func() {
+		pdf.SetFont("Helvetica", "B", 12)
+		pdf.CellFormat(0, 8, e.traducir(titulo), "", 1, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "B", 8)
+		pdf.SetFillColor(230, 230, 230)
+		for i, c := range columnas {
+			pdf.CellFormat(e.anchos[i], altoFilaPDF, e.ajustar(c.Titulo, e.anchos[i]), "1", 0, "L", true, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont("Helvetica", "", 8)
+	})
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont("Helvetica", "I", 7)
+		pdf.CellFormat(0, 5, e.traducir("Generado el "+generado), "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 5, e.traducir("Página ")+"{nb}", "", 0, "R", false, 0, "")
+	})
+	pdf.AddPage()
+	return e
+}
+
+func (e *escritorPDF) Fila(valores []string) error {
+	for i, v := range valores {
+		e.pdf.CellFormat(e.anchos[i], altoFilaPDF, e.ajustar(v, e.anchos[i]), "1", 0, "L", false, 0, "")
+	}
+	e.pdf.Ln(-1)
+	return e.pdf.Error()
+}
+
+func (e *escritorPDF) Cerrar() error {
+	return e.pdf.Output(e.w)
+}
+
+func (e *escritorPDF) Descartar() {}
+
+// ajustar convierte el texto a la codificación del PDF y lo recorta con "..." si no cabe en la celda
+func (e *escritorPDF) ajustar(texto string, ancho float64) string {
+	disponible := ancho - 2*margenCeldaPDF
+	runas := []rune(texto)
+	if e.pdf.GetStringWidth(e.traducir(texto)) <= disponible {
+		return e.traducir(texto)
+	}
+	// Con letra de 8 puntos ningún carácter mide menos de medio milímetro
+	if maximo := int(disponible * 2); len(runas) > maximo {
+		runas = runas[:maximo]
+	}
+	for len(runas) > 0 && e.pdf.GetStringWidth(e.traducir(string(runas)+"...")) > disponible {
+		runas = runas[:len(runas)-1]
+	}
+	return e.traducir(string(runas) + "...")
+}
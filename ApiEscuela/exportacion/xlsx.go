@@ -0,0 +1,120 @@
+package exportacion
+
+import (
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// hojaPredeterminada es la hoja que crea excelize.NewFile
+const hojaPredeterminada = "Sheet1"
+
+// anchoColumnaXLSX es el ancho de columna (en caracteres) por unidad de Columna.Ancho
+const anchoColumnaXLSX = 18
+
+type escritorXLSX struct {
+	w      io.Writer
+	f      *excelize.File
+	hoja   *excelize.StreamWriter
+	nFilas int
+}
+
+func nuevoEscritorXLSX(w io.Writer, titulo string, columnas []Columna) (*escritorXLSX, error) {
+	f := excelize.NewFile()
+	nombre := nombreHoja(titulo)
+	if err := f.SetSheetName(hojaPredeterminada, nombre); err != nil {
+		f.Close()
+		return nil, err
+	}
+	hoja, err := f.NewStreamWriter(nombre)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	e := &escritorXLSX{w: w, f: f, hoja: hoja}
+	if err := e.encabezados(columnas); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return e, nil
+}
+
+// encabezados fija el ancho de las columnas y escribe la fila de títulos en negrita, inmovilizada
+func (e *escritorXLSX) encabezados(columnas []Columna) error {
+	for i, c := range columnas {
+		if err := e.hoja.SetColWidth(i+1, i+1, anchoColumnaXLSX*ancho(c)); err != nil {
+			return err
+		}
+	}
+	if err := e.hoja.SetPanes(&excelize.Panes{
+		Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft",
+	}); err != nil {
+		return err
+	}
+	estilo, err := e.f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return err
+	}
+	titulos := make([]interface{}, len(columnas))
+	for i, c := range columnas {
+		titulos[i] = excelize.Cell{StyleID: estilo, Value: c.Titulo}
+	}
+	return e.siguienteFila(titulos)
+}
+
+func (e *escritorXLSX) Fila(valores []string) error {
+	fila := make([]interface{}, len(valores))
+	for i, v := range valores {
+		fila[i] = v
+	}
+	return e.siguienteFila(fila)
+}
+
+func (e *escritorXLSX) siguienteFila(valores []interface{}) error {
+	e.nFilas++
+	celda, err := excelize.CoordinatesToCellName(1, e.nFilas)
+	if err != nil {
+		return err
+	}
+	return e.hoja.SetRow(celda, valores)
+}
+
+func (e *escritorXLSX) Cerrar() error {
+	defer e.f.Close()
+	if err := e.hoja.Flush(); err != nil {
+		return err
+	}
+	return e.f.Write(e.w)
+}
+
+func (e *escritorXLSX) Descartar() {
+	e.f.Close()
+}
+
+// nombreHoja adapta el título a las reglas de Excel: hasta 31 caracteres y sin : \ / ? * [ ]
+func nombreHoja(titulo string) string {
+	nombre := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`:\/?*[]`, r) {
+			return '-'
+		}
+		return r
+	}, titulo)
+	for utf8.RuneCountInString(nombre) > 31 {
+		_, tam := utf8.DecodeLastRuneInString(nombre)
+		nombre = nombre[:len(nombre)-tam]
+	}
+	if strings.TrimSpace(nombre) == "" {
+		return hojaPredeterminada
+	}
+	return nombre
+}
+
+// ancho devuelve el ancho relativo de la columna
+func ancho(c Columna) float64 {
+	if c.Ancho <= 0 {
+		return 1
+	}
+	return c.Ancho
+}
@@ -1,9 +1,10 @@
 package handlers
 
 import (
+	"ApiEscuela/middleware"
 	"ApiEscuela/models"
 	"ApiEscuela/repositories"
-	"regexp"
+	"ApiEscuela/validacion"
 	"strconv"
 	"strings"
 
@@ -11,11 +12,20 @@ import (
 )
 
 type ActividadHandler struct {
-	actividadRepo *repositories.ActividadRepository
+	actividadRepo repositories.ActividadRepository
+	validador     *validacion.Validador
 }
 
-func NewActividadHandler(actividadRepo *repositories.ActividadRepository) *ActividadHandler {
-	return &ActividadHandler{actividadRepo: actividadRepo}
+func NewActividadHandler(actividadRepo repositories.ActividadRepository, tematicaRepo repositories.TematicaRepository) (*ActividadHandler, error) {
+	validador := validacion.New().
+		ConReferencia("tematica", referencia("tematica_no_existe", "No se encontró la temática con el ID especificado", tematicaRepo))
+	if err := validador.Verificar(models.Actividad{}); err != nil {
+		return nil, err
+	}
+	return &ActividadHandler{
+		actividadRepo: actividadRepo,
+		validador:     validador,
+	}, nil
 }
 
 // CreateActividad crea una nueva actividad
@@ -27,14 +37,9 @@ func (h *ActividadHandler) CreateActividad(c *fiber.Ctx) error {
 		return SendError(c, 400, "invalid_json", "No se puede procesar el JSON. Verifique el formato de los datos", err.Error())
 	}
 
-	// Validar campos requeridos
-	if validationErrors := h.validateActividadRequiredFields(&actividad); len(validationErrors) > 0 {
-		return SendValidationError(c, "Faltan campos requeridos", validationErrors)
-	}
-
-	// Validar datos completos
-	if validationErrors := h.validateActividad(&actividad, false); len(validationErrors) > 0 {
-		return SendValidationError(c, "Los datos proporcionados no son válidos", validationErrors)
+	// Validar según las etiquetas validate del modelo
+	if err := h.validador.Validar(&actividad); err != nil {
+		return err
 	}
 
 	// Limpiar datos
@@ -102,9 +107,9 @@ func (h *ActividadHandler) UpdateActividad(c *fiber.Ctx) error {
 		return SendError(c, 400, "invalid_json", "No se puede procesar el JSON. Verifique el formato de los datos", err.Error())
 	}
 
-	// Validar datos de actualización
-	if validationErrors := h.validateActividad(&updateData, true); len(validationErrors) > 0 {
-		return SendValidationError(c, "Los datos proporcionados no son válidos", validationErrors)
+	// Validar según las etiquetas validate del modelo
+	if err := h.validador.Validar(&updateData); err != nil {
+		return err
 	}
 
 	// Actualizar campos
@@ -120,6 +125,11 @@ func (h *ActividadHandler) UpdateActividad(c *fiber.Ctx) error {
 	return SendSuccess(c, 200, existingActividad)
 }
 
+// PatchActividad actualiza solo los campos enviados de una actividad (JSON Merge Patch)
+func (h *ActividadHandler) PatchActividad(c *fiber.Ctx) error {
+	return mergePatch(c, cargarCon(h.actividadRepo.GetActividadByID), h.UpdateActividad)
+}
+
 // DeleteActividad elimina una actividad
 func (h *ActividadHandler) DeleteActividad(c *fiber.Ctx) error {
 	idStr := c.Params("id")
@@ -169,9 +179,9 @@ func (h *ActividadHandler) GetActividadesByTematica(c *fiber.Ctx) error {
 	return SendSuccess(c, 200, actividades)
 }
 
-// GetActividadesByNombre busca actividades por nombre
+// GetActividadesByNombre busca actividades por nombre sin distinguir tildes, las más relevantes primero
 func (h *ActividadHandler) GetActividadesByNombre(c *fiber.Ctx) error {
-	nombre := c.Params("nombre")
+	nombre := paramBusqueda(c, "nombre")
 
 	// Validar parámetro de búsqueda
 	if validationErrors := h.validateActividadSearchParams(nombre, 0, 0); len(validationErrors) > 0 {
@@ -183,6 +193,22 @@ func (h *ActividadHandler) GetActividadesByNombre(c *fiber.Ctx) error {
 		return SendError(c, 500, "database_error", "Error interno del servidor", "No se pudieron obtener las actividades")
 	}
 
+	return SendSuccess(c, 200, repositories.Registros(actividades))
+}
+
+// GetActividadesByNombreV2 busca actividades por nombre con la relevancia y un fragmento resaltado (respuesta v2)
+func (h *ActividadHandler) GetActividadesByNombreV2(c *fiber.Ctx) error {
+	nombre := paramBusqueda(c, "nombre")
+
+	if validationErrors := h.validateActividadSearchParams(nombre, 0, 0); len(validationErrors) > 0 {
+		return SendValidationError(c, "Los parámetros de búsqueda no son válidos", validationErrors)
+	}
+
+	actividades, err := h.actividadRepo.GetActividadesByNombre(nombre)
+	if err != nil {
+		return SendError(c, 500, "database_error", "Error interno del servidor", "No se pudieron obtener las actividades")
+	}
+
 	return SendSuccess(c, 200, actividades)
 }
 
@@ -214,98 +240,6 @@ func (h *ActividadHandler) GetActividadesByDuracion(c *fiber.Ctx) error {
 	return SendSuccess(c, 200, actividades)
 }
 
-// validateActividad valida los datos de una actividad
-func (h *ActividadHandler) validateActividad(actividad *models.Actividad, isUpdate bool) []ValidationError {
-	var errors []ValidationError
-
-	// Validar Actividad (nombre de la actividad)
-	if strings.TrimSpace(actividad.Actividad) == "" {
-		errors = append(errors, ValidationError{
-			Field:   "actividad",
-			Message: "El nombre de la actividad es requerido",
-			Value:   actividad.Actividad,
-		})
-	} else if len(strings.TrimSpace(actividad.Actividad)) < 3 {
-		errors = append(errors, ValidationError{
-			Field:   "actividad",
-			Message: "El nombre de la actividad debe tener al menos 3 caracteres",
-			Value:   actividad.Actividad,
-		})
-	} else if len(strings.TrimSpace(actividad.Actividad)) > 200 {
-		errors = append(errors, ValidationError{
-			Field:   "actividad",
-			Message: "El nombre de la actividad no puede exceder 200 caracteres",
-			Value:   actividad.Actividad,
-		})
-	} else {
-		// Validar que no contenga solo espacios o caracteres especiales
-		trimmed := strings.TrimSpace(actividad.Actividad)
-		if len(trimmed) == 0 {
-			errors = append(errors, ValidationError{
-				Field:   "actividad",
-				Message: "El nombre de la actividad no puede contener solo espacios",
-				Value:   actividad.Actividad,
-			})
-		}
-
-		// Validar que no contenga caracteres especiales problemáticos
-		specialCharsRegex := regexp.MustCompile(`[<>{}[\]\\|` + "`" + `~!@#$%^&*()+=;:'"<>?/]`)
-		if specialCharsRegex.MatchString(trimmed) {
-			errors = append(errors, ValidationError{
-				Field:   "actividad",
-				Message: "El nombre de la actividad no puede contener caracteres especiales",
-				Value:   actividad.Actividad,
-			})
-		}
-	}
-
-	// Validar TematicaID
-	if actividad.TematicaID == 0 {
-		errors = append(errors, ValidationError{
-			Field:   "tematica_id",
-			Message: "El ID de la temática es requerido",
-		})
-	}
-
-	// Validar Duracion (opcional pero si se proporciona debe ser válida)
-	if actividad.Duracion < 0 {
-		errors = append(errors, ValidationError{
-			Field:   "duracion",
-			Message: "La duración no puede ser negativa",
-			Value:   string(rune(actividad.Duracion)),
-		})
-	} else if actividad.Duracion > 1440 { // 24 horas en minutos
-		errors = append(errors, ValidationError{
-			Field:   "duracion",
-			Message: "La duración no puede exceder 1440 minutos (24 horas)",
-			Value:   string(rune(actividad.Duracion)),
-		})
-	}
-
-	return errors
-}
-
-// validateActividadRequiredFields valida que los campos requeridos estén presentes
-func (h *ActividadHandler) validateActividadRequiredFields(actividad *models.Actividad) []ValidationError {
-	var errors []ValidationError
-
-	if strings.TrimSpace(actividad.Actividad) == "" {
-		errors = append(errors, ValidationError{
-			Field:   "actividad",
-			Message: "El campo actividad es requerido",
-		})
-	}
-
-	if actividad.TematicaID == 0 {
-		errors = append(errors, ValidationError{
-			Field:   "tematica_id",
-			Message: "El campo tematica_id es requerido",
-		})
-	}
-
-	return errors
-}
-
 // validateActividadSearchParams valida los parámetros de búsqueda
 func (h *ActividadHandler) validateActividadSearchParams(nombre string, duracionMin, duracionMax int) []ValidationError {
 	var errors []ValidationError
@@ -356,3 +290,12 @@ func (h *ActividadHandler) validateActividadSearchParams(nombre string, duracion
 
 	return errors
 }
+
+// Version calcula la versión de la lista de las actividades para las peticiones condicionales
+func (h *ActividadHandler) Version() (middleware.Version, error) {
+	lista, err := h.actividadRepo.GetAllActividades()
+	if err != nil {
+		return middleware.Version{}, err
+	}
+	return versionDe("actividades", lista), nil
+}
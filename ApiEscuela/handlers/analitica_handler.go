@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"ApiEscuela/errores"
+	"ApiEscuela/services"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type AnaliticaHandler struct {
+	analiticaService services.AnaliticaService
+}
+
+func NewAnaliticaHandler(analiticaService services.AnaliticaService) *AnaliticaHandler {
+	return &AnaliticaHandler{analiticaService: analiticaService}
+}
+
+// consultaAnalitica lee ?desde=, ?hasta=, ?agrupar=, ?por= y ?limite=
+func consultaAnalitica(c *fiber.Ctx) (services.ConsultaAnalitica, error) {
+	consulta := services.ConsultaAnalitica{
+		Desde:   c.Query("desde"),
+		Hasta:   c.Query("hasta"),
+		Agrupar: c.Query("agrupar"),
+		Por:     c.Query("por"),
+	}
+	if valor := c.Query("limite"); valor != "" {
+		n, err := strconv.Atoi(valor)
+		if err != nil || n < 1 {
+			return consulta, errores.Validacion("analitica_invalida", "Los parámetros de la analítica no son válidos", errores.Campo{
+				Campo:   "limite",
+				Mensaje: "Debe ser un número entre 1 y " + strconv.Itoa(services.LimiteAnaliticaMaximo),
+				Valor:   valor,
+			})
+		}
+		consulta.Limite = n
+	}
+	return consulta, nil
+}
+
+// responderAnalitica responde la sección calculada con la consulta de la petición
+func responderAnalitica[T any](c *fiber.Ctx, calcular func(services.ConsultaAnalitica) (*T, error)) error {
+	consulta, err := consultaAnalitica(c)
+	if err != nil {
+		return err
+	}
+	resultado, err := calcular(consulta)
+	if err != nil {
+		return err
+	}
+	return SendSuccess(c, 200, resultado)
+}
+
+// GetPanel obtiene todas las secciones de la analítica del dashboard en una sola respuesta
+func (h *AnaliticaHandler) GetPanel(c *fiber.Ctx) error {
+	return responderAnalitica(c, h.analiticaService.Panel)
+}
+
+// GetVisitas obtiene los programas de visita por día, semana, mes o año
+func (h *AnaliticaHandler) GetVisitas(c *fiber.Ctx) error {
+	return responderAnalitica(c, h.analiticaService.Visitas)
+}
+
+// GetEstudiantes obtiene los estudiantes alcanzados por provincia, ciudad, institución o especialidad
+func (h *AnaliticaHandler) GetEstudiantes(c *fiber.Ctx) error {
+	return responderAnalitica(c, h.analiticaService.Estudiantes)
+}
+
+// GetTematicas obtiene las temáticas con más visitas
+func (h *AnaliticaHandler) GetTematicas(c *fiber.Ctx) error {
+	return responderAnalitica(c, h.analiticaService.Tematicas)
+}
+
+// GetActividades obtiene las actividades con más visitas
+func (h *AnaliticaHandler) GetActividades(c *fiber.Ctx) error {
+	return responderAnalitica(c, h.analiticaService.Actividades)
+}
+
+// GetAutoridades obtiene las visitas y dudas asignadas a cada autoridad
+func (h *AnaliticaHandler) GetAutoridades(c *fiber.Ctx) error {
+	return responderAnalitica(c, h.analiticaService.Autoridades)
+}
+
+// GetDudas obtiene los tiempos de respuesta de las dudas
+func (h *AnaliticaHandler) GetDudas(c *fiber.Ctx) error {
+	return responderAnalitica(c, h.analiticaService.Dudas)
+}
+
+// GetComunicados obtiene el alcance de los comunicados por canal
+func (h *AnaliticaHandler) GetComunicados(c *fiber.Ctx) error {
+	return responderAnalitica(c, h.analiticaService.Comunicados)
+}
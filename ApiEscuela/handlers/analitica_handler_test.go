@@ -0,0 +1,238 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+	"ApiEscuela/services"
+	"ApiEscuela/testutil"
+)
+
+func TestAnalitica(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+		crear := func(que string, err error) {
+			t.Helper()
+			if err != nil {
+				t.Fatalf("%s: %v", que, err)
+			}
+		}
+		fecha := func(mes time.Month, dia, hora int) time.Time {
+			return time.Date(2030, mes, dia, hora, 0, 0, 0, time.Local)
+		}
+
+		quito := f.Ciudad(f.Provincia("Pichincha"), "Quito")
+		otra := f.Institucion(func(i *models.Institucion) { i.Nombre = "Colegio Técnico Quevedo" })
+		ana := f.Estudiante(f.Persona(), e.Institucion, e.Ciudad)
+		f.Estudiante(f.Persona(), e.Institucion, quito, func(es *models.Estudiante) { es.Especialidad = "" })
+		f.Estudiante(f.Persona(), otra, e.Ciudad, func(es *models.Estudiante) { es.Especialidad = "Contabilidad" })
+		f.Estudiante(f.Persona(), f.Institucion(), e.Ciudad) // institución sin visitas
+
+		programas := make([]*models.ProgramaVisita, 4)
+		for i, p := range []models.ProgramaVisita{
+			{Fecha: fecha(time.March, 10, 9), InstitucionID: e.Institucion.ID},
+			{Fecha: fecha(time.March, 20, 9), InstitucionID: e.Institucion.ID},
+			{Fecha: fecha(time.May, 14, 9), InstitucionID: otra.ID},
+			{Fecha: time.Date(2031, 1, 15, 9, 0, 0, 0, time.Local), InstitucionID: e.Institucion.ID},
+		} {
+			programas[i] = &p
+			crear("programa", repos.ProgramaVisita.CreateProgramaVisita(programas[i]))
+		}
+
+		robotica := &models.Tematica{Nombre: "Robótica"}
+		quimica := &models.Tematica{Nombre: "Química"}
+		crear("temática", repos.Tematica.CreateTematica(robotica))
+		crear("temática", repos.Tematica.CreateTematica(quimica))
+		brazo := &models.Actividad{Actividad: "Brazo robótico", TematicaID: robotica.ID}
+		drones := &models.Actividad{Actividad: "Drones", TematicaID: robotica.ID}
+		laboratorio := &models.Actividad{Actividad: "Laboratorio", TematicaID: quimica.ID}
+		for _, a := range []*models.Actividad{brazo, drones, laboratorio} {
+			crear("actividad", repos.Actividad.CreateActividad(a))
+		}
+		for _, d := range [][2]uint{
+			{programas[0].ID, brazo.ID}, {programas[0].ID, drones.ID}, {programas[1].ID, brazo.ID},
+			{programas[2].ID, laboratorio.ID}, {programas[3].ID, laboratorio.ID},
+		} {
+			crear("detalle", repos.VisitaDetalle.CreateVisitaDetalle(&models.VisitaDetalle{ProgramaVisitaID: d[0], ActividadID: d[1]}))
+		}
+
+		decana := &models.AutoridadUTEQ{PersonaID: f.Persona(func(p *models.Persona) { p.Nombre = "Marta Vera" }).ID, Cargo: "Decana"}
+		crear("autoridad", repos.AutoridadUTEQ.CreateAutoridadUTEQ(decana))
+		crear("autoridad", repos.AutoridadUTEQ.CreateAutoridadUTEQ(&models.AutoridadUTEQ{
+			PersonaID: f.Persona(func(p *models.Persona) { p.Nombre = "Pedro Mora" }).ID, Cargo: "Director",
+		}))
+		for _, p := range []*models.ProgramaVisita{programas[0], programas[2], programas[3]} {
+			crear("asignación", repos.DetalleAutoridadDetallesVisita.CreateDetalleAutoridadDetallesVisita(&models.DetalleAutoridadDetallesVisita{
+				ProgramaVisitaID: p.ID, AutoridadUTEQID: decana.ID,
+			}))
+		}
+
+		respuesta := "Las visitas son los martes"
+		duda := func(pregunta time.Time, horas int, autoridad *uint) *models.Dudas {
+			d := &models.Dudas{Pregunta: "¿Cuándo son las visitas?", EstudianteID: ana.ID, FechaPregunta: pregunta, AutoridadUTEQID: autoridad}
+			if horas > 0 {
+				respondida := pregunta.Add(time.Duration(horas) * time.Hour)
+				d.Respuesta, d.FechaRespuesta = &respuesta, &respondida
+			}
+			return d
+		}
+		for _, d := range []*models.Dudas{
+			duda(fecha(time.March, 11, 10), 2, &decana.ID),
+			duda(fecha(time.March, 12, 10), 10, nil),
+			duda(fecha(time.May, 15, 10), 0, &decana.ID),
+			duda(time.Date(2031, 2, 1, 10, 0, 0, 0, time.Local), 1, &decana.ID),
+		} {
+			crear("duda", repos.Dudas.CreateDudas(d))
+		}
+
+		f.Comunicado(e.Admin, func(c *models.Comunicado) { c.CreatedAt = fecha(time.March, 1, 8); c.EnviadoA = 40 })
+		f.Comunicado(e.Admin, func(c *models.Comunicado) {
+			c.CreatedAt = fecha(time.April, 2, 8)
+			c.EnviadoA = 15
+			c.Canal = "whatsapp"
+		})
+		f.Comunicado(e.Admin, func(c *models.Comunicado) { c.CreatedAt = fecha(time.April, 3, 8); c.Estado = "borrador" })
+		f.Comunicado(e.Admin) // fuera del rango
+
+		obtener := func(ruta string, destino interface{}) {
+			t.Helper()
+			res := testutil.Do(t, app, http.MethodGet, ruta, nil, e.AdminToken)
+			if res.Status != http.StatusOK {
+				t.Fatalf("GET %s = %d: %s", ruta, res.Status, res.Body)
+			}
+			res.JSON(t, &struct {
+				Data interface{} `json:"data"`
+			}{destino})
+		}
+		const rango = "desde=2030-01-01&hasta=2030-12-31"
+
+		// Serie mensual completa, con los meses sin visitas en cero
+		var visitas services.SerieVisitas
+		obtener("/api/analitica/visitas?"+rango, &visitas)
+		if visitas.EncabezadoAnalitica == nil || visitas.Desde != "2030-01-01" || visitas.Hasta != "2030-12-31" || visitas.Agrupacion != "mes" {
+			t.Errorf("encabezado = %+v %q", visitas.EncabezadoAnalitica, visitas.Agrupacion)
+		}
+		if len(visitas.Periodos) != 12 || visitas.Total != 3 || visitas.Periodos[2] != (repositories.ConteoPeriodo{Periodo: "2030-03", Total: 2}) ||
+			visitas.Periodos[4].Total != 1 || visitas.Periodos[0].Total != 0 {
+			t.Errorf("visitas = %+v", visitas)
+		}
+		obtener("/api/analitica/visitas?desde=2030-03-01&hasta=2030-03-31&agrupar=semana", &visitas)
+		semanas := []string{}
+		for _, p := range visitas.Periodos {
+			semanas = append(semanas, fmt.Sprintf("%s=%d", p.Periodo, p.Total))
+		}
+		if strings.Join(semanas, " ") != "2030-02-25=0 2030-03-04=1 2030-03-11=0 2030-03-18=1 2030-03-25=0" {
+			t.Errorf("semanas = %v", semanas)
+		}
+
+		grupos := func(gs []repositories.ConteoGrupo) string {
+			partes := []string{}
+			for _, g := range gs {
+				partes = append(partes, fmt.Sprintf("%s=%d", g.Nombre, g.Total))
+			}
+			return strings.Join(partes, ", ")
+		}
+		var estudiantes services.EstudiantesAlcanzados
+		for por, esperado := range map[string]string{
+			"":             e.Institucion.Nombre + "=2, Colegio Técnico Quevedo=1",
+			"provincia":    "Los Ríos=2, Pichincha=1",
+			"ciudad":       "Quevedo=2, Quito=1",
+			"especialidad": "Contabilidad=1, Informática=1, Sin especialidad=1",
+		} {
+			obtener("/api/analitica/estudiantes?"+rango+"&por="+por, &estudiantes)
+			if obtenido := grupos(estudiantes.Grupos); obtenido != esperado || estudiantes.Total != 3 {
+				t.Errorf("estudiantes por %q = %s (%d)", por, obtenido, estudiantes.Total)
+			}
+		}
+
+		var ranking services.RankingAnalitica
+		obtener("/api/analitica/tematicas?"+rango, &ranking)
+		if obtenido := grupos(ranking.Grupos); obtenido != "Robótica=2, Química=1" || ranking.Grupos[0].ID != robotica.ID {
+			t.Errorf("temáticas = %s", obtenido)
+		}
+		obtener("/api/analitica/actividades?"+rango+"&limite=2", &ranking)
+		if obtenido := grupos(ranking.Grupos); obtenido != "Brazo robótico=2, Drones=1" || ranking.Limite != 2 {
+			t.Errorf("actividades = %s", obtenido)
+		}
+
+		var autoridades services.CargaAutoridades
+		obtener("/api/analitica/autoridades?"+rango, &autoridades)
+		if len(autoridades.Autoridades) != 2 || autoridades.Autoridades[0] != (repositories.CargaAutoridad{
+			AutoridadID: decana.ID, Nombre: "Marta Vera", Cargo: "Decana", Visitas: 2, DudasAsignadas: 2, DudasRespondidas: 1, DudasPendientes: 1,
+		}) || autoridades.Autoridades[1].Visitas != 0 {
+			t.Errorf("autoridades = %+v", autoridades.Autoridades)
+		}
+
+		var dudas services.TiemposDudas
+		obtener("/api/analitica/dudas?"+rango, &dudas)
+		r := dudas.Resumen
+		if r.Preguntas != 3 || r.Respondidas != 2 || r.SinResponder != 1 || r.PromedioHoras == nil ||
+			!cerca(*r.PromedioHoras, 6) || !cerca(*r.MedianaHoras, 6) || !cerca(*r.P90Horas, 9.2) {
+			t.Errorf("resumen de dudas = %+v", r)
+		}
+		if marzo, mayo := dudas.Periodos[2], dudas.Periodos[4]; len(dudas.Periodos) != 12 || marzo.Respondidas != 2 ||
+			mayo.SinResponder != 1 || mayo.PromedioHoras != nil || dudas.Periodos[0].Periodo != "2030-01" {
+			t.Errorf("dudas por mes = %+v", dudas.Periodos)
+		}
+
+		var comunicados services.AlcanceComunicados
+		obtener("/api/analitica/comunicados?"+rango, &comunicados)
+		if len(comunicados.Canales) != 2 ||
+			comunicados.Canales[0] != (repositories.AlcanceCanal{Canal: "correo", Comunicados: 2, Enviados: 1, Destinatarios: 40}) ||
+			comunicados.Canales[1] != (repositories.AlcanceCanal{Canal: "whatsapp", Comunicados: 1, Enviados: 1, Destinatarios: 15}) {
+			t.Errorf("comunicados = %+v", comunicados.Canales)
+		}
+
+		// El panel trae todas las secciones con un solo encabezado
+		var panel map[string]interface{}
+		obtener("/api/analitica/panel?"+rango+"&por=provincia", &panel)
+		if panel["desde"] != "2030-01-01" || panel["generado_en"] == nil {
+			t.Errorf("encabezado del panel = %v", panel["desde"])
+		}
+		for _, seccion := range []string{"visitas", "estudiantes", "tematicas", "actividades", "autoridades", "dudas", "comunicados"} {
+			if datos, ok := panel[seccion].(map[string]interface{}); !ok {
+				t.Errorf("panel sin %s", seccion)
+			} else if _, ok := datos["desde"]; ok {
+				t.Errorf("la sección %s repite el encabezado", seccion)
+			}
+		}
+		if estudiantes, _ := panel["estudiantes"].(map[string]interface{}); estudiantes["por"] != "provincia" {
+			t.Errorf("panel.estudiantes = %v", panel["estudiantes"])
+		}
+
+		// Sin fechas: los últimos doce meses hasta hoy
+		obtener("/api/analitica/visitas", &visitas)
+		if len(visitas.Periodos) != 12 || visitas.Periodos[11].Periodo != time.Now().Format("2006-01") || visitas.Hasta != time.Now().Format("2006-01-02") {
+			t.Errorf("rango por defecto = %s..%s %+v", visitas.Desde, visitas.Hasta, visitas.Periodos)
+		}
+
+		for _, consulta := range []string{
+			"desde=2030-13-01",
+			"desde=2030-06-01&hasta=2030-05-01",
+			"agrupar=hora",
+			"agrupar=dia&desde=2029-01-01&hasta=2030-12-31",
+			"por=barrio",
+			"limite=0",
+			"limite=51",
+		} {
+			res := testutil.Do(t, app, http.MethodGet, "/api/analitica/panel?"+consulta, nil, e.AdminToken)
+			if res.Status != http.StatusBadRequest || !strings.Contains(string(res.Body), "analitica_invalida") {
+				t.Errorf("%s = %d: %s", consulta, res.Status, res.Body)
+			}
+		}
+		if res := testutil.Do(t, app, http.MethodGet, "/api/analitica/panel", nil, ""); res.Status != http.StatusUnauthorized {
+			t.Errorf("sin token = %d", res.Status)
+		}
+	})
+}
+
+func cerca(a, b float64) bool {
+	return a-b < 1e-6 && b-a < 1e-6
+}
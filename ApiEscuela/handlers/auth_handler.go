@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"ApiEscuela/errores"
 	"ApiEscuela/middleware"
 	"ApiEscuela/services"
+	"ApiEscuela/validacion"
+	"errors"
 	"regexp"
 	"strings"
 	"time"
@@ -11,10 +14,10 @@ import (
 )
 
 type AuthHandler struct {
-	authService *services.AuthService
+	authService services.AuthService
 }
 
-func NewAuthHandler(authService *services.AuthService) *AuthHandler {
+func NewAuthHandler(authService services.AuthService) *AuthHandler {
 	return &AuthHandler{
 		authService: authService,
 	}
@@ -36,8 +39,8 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 		})
 	}
 
-	// Validar campos requeridos
-	if loginReq.Usuario == "" || loginReq.Contraseña == "" {
+	// Validar campos requeridos (etiquetas validate de LoginRequest)
+	if err := validacion.Validar(&loginReq); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(middleware.ErrorResponse{
 			Error:      "Campos requeridos faltantes",
 			ErrorCode:  "LOGIN_MISSING_FIELDS",
@@ -52,16 +55,16 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	// Intentar login
 	response, err := h.authService.Login(loginReq)
 	if err != nil {
-		// Determinar el código de error específico basado en el mensaje
+		// Determinar el código de error específico según el error del servicio
 		var errorCode string
-		switch err.Error() {
-		case "usuario no encontrado":
+		switch {
+		case errors.Is(err, services.ErrUsuarioNoEncontrado):
 			errorCode = "LOGIN_USER_NOT_FOUND"
-		case "usuario eliminado - contacte al administrador":
+		case errors.Is(err, services.ErrUsuarioEliminado):
 			errorCode = "LOGIN_USER_DELETED"
-		case "contraseña incorrecta (texto plano)":
+		case errors.Is(err, services.ErrContraseñaTextoPlano):
 			errorCode = "LOGIN_PASSWORD_INCORRECT_PLAIN"
-		case "contraseña incorrecta (hash bcrypt)":
+		case errors.Is(err, services.ErrContraseñaHash):
 			errorCode = "LOGIN_PASSWORD_INCORRECT_HASH"
 		default:
 			errorCode = "LOGIN_FAILED"
@@ -81,7 +84,6 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(response)
 }
 
-// Register maneja el registro de nuevos usuarios
 // RecoverPassword maneja la recuperación de contraseña por cédula (público)
 func (h *AuthHandler) RecoverPassword(c *fiber.Ctx) error {
 	var req struct {
@@ -94,6 +96,10 @@ func (h *AuthHandler) RecoverPassword(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "La cédula es requerida"})
 	}
 	if err := h.authService.RecoverPassword(req.Cedula); err != nil {
+		// Si falla el servidor de correo se responde 502 con el código del error
+		if errors.Is(err, errores.ErrExterno) {
+			return err
+		}
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
 	}
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{"message": "Si la cédula existe, se envió un correo con la contraseña temporal"})
@@ -161,6 +167,7 @@ func (h *AuthHandler) VerifyCode(c *fiber.Ctx) error {
 	}
 }
 
+// Register maneja el registro de nuevos usuarios
 func (h *AuthHandler) Register(c *fiber.Ctx) error {
 	var registerReq services.RegisterRequest
 
@@ -170,17 +177,9 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 		})
 	}
 
-	// Validar campos requeridos
-	if registerReq.Usuario == "" || registerReq.Contraseña == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Usuario y contraseña son requeridos",
-		})
-	}
-
-	if len(registerReq.Contraseña) < 6 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "La contraseña debe tener al menos 6 caracteres",
-		})
+	// Validar según las etiquetas validate de RegisterRequest
+	if err := validacion.Validar(&registerReq); err != nil {
+		return err
 	}
 
 	// Intentar registro
@@ -197,13 +196,12 @@ func (h *AuthHandler) Register(c *fiber.Ctx) error {
 	})
 }
 
-// ChangePassword maneja el cambio de contraseña
 // ResetPassword maneja el reseteo de contraseña por ID de código (público)
 func (h *AuthHandler) ResetPassword(c *fiber.Ctx) error {
 	var req struct {
-		CodigoID  uint   `json:"codigo_id"`
-		UsuarioID uint   `json:"usuario_id"`
-		Clave     string `json:"clave"`
+		CodigoID  uint   `json:"codigo_id" validate:"required"`
+		UsuarioID uint   `json:"usuario_id" validate:"required"`
+		Clave     string `json:"clave" validate:"required,min=6,max=100,sin_espacios,secreto"`
 	}
 
 	// Parsear JSON
@@ -211,69 +209,9 @@ func (h *AuthHandler) ResetPassword(c *fiber.Ctx) error {
 		return SendError(c, 400, "invalid_json", "No se puede procesar el JSON. Verifique el formato de los datos", err.Error())
 	}
 
-	// Validar campos requeridos
-	var validationErrors []ValidationError
-
-	if req.CodigoID == 0 {
-		validationErrors = append(validationErrors, ValidationError{
-			Field:   "codigo_id",
-			Message: "El ID del código es requerido",
-			Value:   "0",
-		})
-	}
-
-	if req.UsuarioID == 0 {
-		validationErrors = append(validationErrors, ValidationError{
-			Field:   "usuario_id",
-			Message: "El ID del usuario es requerido",
-			Value:   "0",
-		})
-	}
-
-	if strings.TrimSpace(req.Clave) == "" {
-		validationErrors = append(validationErrors, ValidationError{
-			Field:   "clave",
-			Message: "La nueva contraseña es requerida",
-			Value:   req.Clave,
-		})
-	} else if len(strings.TrimSpace(req.Clave)) < 6 {
-		validationErrors = append(validationErrors, ValidationError{
-			Field:   "clave",
-			Message: "La contraseña debe tener al menos 6 caracteres",
-			Value:   req.Clave,
-		})
-	} else if len(strings.TrimSpace(req.Clave)) > 100 {
-		validationErrors = append(validationErrors, ValidationError{
-			Field:   "clave",
-			Message: "La contraseña no puede exceder 100 caracteres",
-			Value:   req.Clave,
-		})
-	}
-
-	// Validar fortaleza de la contraseña
-	if strings.TrimSpace(req.Clave) != "" {
-		// Verificar que no sea solo espacios
-		if strings.TrimSpace(req.Clave) != req.Clave {
-			validationErrors = append(validationErrors, ValidationError{
-				Field:   "clave",
-				Message: "La contraseña no puede comenzar o terminar con espacios",
-				Value:   req.Clave,
-			})
-		}
-
-		// Verificar que no contenga caracteres especiales problemáticos
-		if strings.Contains(req.Clave, " ") {
-			validationErrors = append(validationErrors, ValidationError{
-				Field:   "clave",
-				Message: "La contraseña no puede contener espacios",
-				Value:   req.Clave,
-			})
-		}
-	}
-
-	// Si hay errores de validación, retornarlos
-	if len(validationErrors) > 0 {
-		return SendValidationError(c, "Los datos proporcionados no son válidos", validationErrors)
+	// Validar según las etiquetas validate
+	if err := validacion.Validar(&req); err != nil {
+		return err
 	}
 
 	// Limpiar datos
@@ -282,19 +220,17 @@ func (h *AuthHandler) ResetPassword(c *fiber.Ctx) error {
 	// Cambiar contraseña usando el servicio
 	if err := h.authService.ResetPasswordByCodigoID(req.CodigoID, req.UsuarioID, clave); err != nil {
 		// Manejar diferentes tipos de errores del servicio
-		switch err.Error() {
-		case "código no encontrado":
+		switch {
+		case errors.Is(err, services.ErrCodigoNoEncontrado):
 			return SendError(c, 404, "codigo_not_found", "No se encontró el código", "Verifique que el ID del código sea correcto")
-		case "el código no pertenece al usuario especificado":
+		case errors.Is(err, services.ErrCodigoDeOtroUsuario):
 			return SendError(c, 400, "codigo_user_mismatch", "El código no pertenece al usuario especificado", "Verifique que el código y usuario coincidan")
-		case "el código debe estar en estado válido para cambiar la contraseña":
+		case errors.Is(err, services.ErrCodigoEstadoInvalido):
 			return SendError(c, 400, "codigo_invalid_state", "El código debe estar en estado válido", "El código debe estar en estado 'valido' para cambiar la contraseña")
-		case "el código ha expirado":
+		case errors.Is(err, services.ErrCodigoExpirado):
 			return SendError(c, 400, "codigo_expired", "El código ha expirado", "Solicite un nuevo código")
-		case "error al actualizar la contraseña":
-			return SendError(c, 500, "password_update_error", "Error al actualizar la contraseña", "No se pudo cambiar la contraseña")
 		default:
-			return SendError(c, 500, "service_error", "Error interno del servidor", "No se pudo cambiar la contraseña")
+			return SendError(c, 500, "password_update_error", "Error al actualizar la contraseña", "No se pudo cambiar la contraseña")
 		}
 	}
 
@@ -305,6 +241,7 @@ func (h *AuthHandler) ResetPassword(c *fiber.Ctx) error {
 	})
 }
 
+// ChangePassword maneja el cambio de contraseña
 func (h *AuthHandler) ChangePassword(c *fiber.Ctx) error {
 	// Obtener ID del usuario del contexto (del JWT)
 	userID := c.Locals("user_id").(uint)
@@ -0,0 +1,258 @@
+package handlers_test
+
+import (
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"ApiEscuela/models"
+	"ApiEscuela/testutil"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(testutil.RunMain(m))
+}
+
+func TestLogin(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+
+		// Usuario heredado con la contraseña guardada en texto plano
+		f.Usuario(f.Persona(), e.TipoEstudiante, "", func(u *models.Usuario) {
+			u.Usuario = "plano"
+			u.Contraseña = "clave-plana"
+			u.Verificado = false
+		})
+		eliminado := f.Usuario(f.Persona(), e.TipoEstudiante, "secreto1", func(u *models.Usuario) { u.Usuario = "eliminado" })
+		if err := repos.Usuario.DeleteUsuario(eliminado.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		tests := []struct {
+			name       string
+			usuario    string
+			clave      string
+			wantStatus int
+			wantCode   string
+			wantCambio bool
+		}{
+			{name: "hash correcto", usuario: "admin", clave: testutil.ClaveAdmin, wantStatus: http.StatusOK},
+			{name: "texto plano correcto", usuario: "plano", clave: "clave-plana", wantStatus: http.StatusOK, wantCambio: true},
+			{name: "hash incorrecto", usuario: "admin", clave: "otra-clave", wantStatus: http.StatusUnauthorized, wantCode: "LOGIN_PASSWORD_INCORRECT_HASH"},
+			{name: "texto plano incorrecto", usuario: "plano", clave: "otra-clave", wantStatus: http.StatusUnauthorized, wantCode: "LOGIN_PASSWORD_INCORRECT_PLAIN"},
+			{name: "usuario inexistente", usuario: "nadie", clave: "x", wantStatus: http.StatusUnauthorized, wantCode: "LOGIN_USER_NOT_FOUND"},
+			{name: "usuario eliminado", usuario: "eliminado", clave: "secreto1", wantStatus: http.StatusUnauthorized, wantCode: "LOGIN_USER_DELETED"},
+			{name: "campos vacíos", usuario: "", clave: "", wantStatus: http.StatusBadRequest, wantCode: "LOGIN_MISSING_FIELDS"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				resp := testutil.Do(t, app, "POST", "/auth/login", map[string]string{
+					"usuario":    tt.usuario,
+					"contraseña": tt.clave,
+				}, "")
+				if resp.Status != tt.wantStatus {
+					t.Fatalf("status = %d, want %d: %s", resp.Status, tt.wantStatus, resp.Body)
+				}
+				body := resp.Map(t)
+				if tt.wantCode != "" {
+					if body["error_code"] != tt.wantCode {
+						t.Errorf("error_code = %v, want %s", body["error_code"], tt.wantCode)
+					}
+					return
+				}
+				if body["token"] == "" || body["token"] == nil {
+					t.Errorf("respuesta sin token: %s", resp.Body)
+				}
+				if body["requiere_cambio_password"] != tt.wantCambio {
+					t.Errorf("requiere_cambio_password = %v, want %v", body["requiere_cambio_password"], tt.wantCambio)
+				}
+			})
+		}
+	})
+}
+
+func TestRegister(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+		persona := f.Persona()
+
+		tests := []struct {
+			name       string
+			usuario    string
+			clave      string
+			wantStatus int
+		}{
+			{name: "nuevo usuario", usuario: "nuevo", clave: "secreto1", wantStatus: http.StatusCreated},
+			{name: "usuario duplicado", usuario: "admin", clave: "secreto1", wantStatus: http.StatusBadRequest},
+			{name: "contraseña corta", usuario: "corto", clave: "123", wantStatus: http.StatusBadRequest},
+			{name: "sin usuario", usuario: "", clave: "secreto1", wantStatus: http.StatusBadRequest},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				resp := testutil.Do(t, app, "POST", "/auth/register", map[string]interface{}{
+					"usuario":         tt.usuario,
+					"contraseña":      tt.clave,
+					"persona_id":      persona.ID,
+					"tipo_usuario_id": e.TipoEstudiante.ID,
+				}, "")
+				if resp.Status != tt.wantStatus {
+					t.Fatalf("status = %d, want %d: %s", resp.Status, tt.wantStatus, resp.Body)
+				}
+			})
+		}
+
+		// El usuario registrado puede iniciar sesión
+		resp := testutil.Do(t, app, "POST", "/auth/login", map[string]string{"usuario": "nuevo", "contraseña": "secreto1"}, "")
+		if resp.Status != http.StatusOK {
+			t.Fatalf("login tras registro: status = %d: %s", resp.Status, resp.Body)
+		}
+	})
+}
+
+func TestChangePassword(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+
+		tests := []struct {
+			name       string
+			body       map[string]string
+			token      string
+			wantStatus int
+		}{
+			{name: "sin token", body: map[string]string{"old_password": testutil.ClaveAdmin, "new_password": "nueva123"}, wantStatus: http.StatusUnauthorized},
+			{name: "clave actual incorrecta", body: map[string]string{"old_password": "mala", "new_password": "nueva123"}, token: e.AdminToken, wantStatus: http.StatusBadRequest},
+			{name: "nueva clave corta", body: map[string]string{"old_password": testutil.ClaveAdmin, "new_password": "123"}, token: e.AdminToken, wantStatus: http.StatusBadRequest},
+			{name: "cambio correcto", body: map[string]string{"old_password": testutil.ClaveAdmin, "new_password": "nueva123"}, token: e.AdminToken, wantStatus: http.StatusOK},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				resp := testutil.Do(t, app, "POST", "/api/auth/change-password", tt.body, tt.token)
+				if resp.Status != tt.wantStatus {
+					t.Fatalf("status = %d, want %d: %s", resp.Status, tt.wantStatus, resp.Body)
+				}
+			})
+		}
+
+		resp := testutil.Do(t, app, "POST", "/auth/login", map[string]string{"usuario": "admin", "contraseña": "nueva123"}, "")
+		if resp.Status != http.StatusOK {
+			t.Fatalf("login con la nueva clave: status = %d: %s", resp.Status, resp.Body)
+		}
+	})
+}
+
+func TestRecuperacionPorCodigo(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+
+		usuario := f.Usuario(f.Persona(), e.TipoEstudiante, "secreto1")
+		otro := f.Usuario(f.Persona(), e.TipoEstudiante, "secreto1")
+		vigente := f.Codigo(usuario, "123456")
+		expirado := f.Codigo(usuario, "654321")
+		pasado := time.Now().Add(-time.Minute)
+		expirado.ExpiraEn = &pasado
+		if err := repos.CodigoUsuario.Update(expirado); err != nil {
+			t.Fatal(err)
+		}
+
+		t.Run("verify-code", func(t *testing.T) {
+			tests := []struct {
+				name       string
+				codigo     string
+				wantStatus int
+				wantError  string
+			}{
+				{name: "formato inválido", codigo: "12ab", wantStatus: http.StatusBadRequest, wantError: "validation_error"},
+				{name: "no existe", codigo: "000000", wantStatus: http.StatusNotFound, wantError: "codigo_not_found"},
+				{name: "expirado", codigo: "654321", wantStatus: http.StatusBadRequest, wantError: "codigo_expired"},
+				{name: "vigente", codigo: "123456", wantStatus: http.StatusOK},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					resp := testutil.Do(t, app, "POST", "/auth/verify-code", map[string]string{"codigo": tt.codigo}, "")
+					if resp.Status != tt.wantStatus {
+						t.Fatalf("status = %d, want %d: %s", resp.Status, tt.wantStatus, resp.Body)
+					}
+					body := resp.Map(t)
+					if tt.wantError != "" {
+						if body["error"] != tt.wantError {
+							t.Errorf("error = %v, want %s", body["error"], tt.wantError)
+						}
+						return
+					}
+					data := body["data"].(map[string]interface{})
+					if uint(data["codigo_id"].(float64)) != vigente.ID || uint(data["usuario_id"].(float64)) != usuario.ID {
+						t.Errorf("datos inesperados: %v", data)
+					}
+				})
+			}
+		})
+
+		t.Run("reset-password", func(t *testing.T) {
+			tests := []struct {
+				name       string
+				codigoID   uint
+				usuarioID  uint
+				clave      string
+				wantStatus int
+				wantError  string
+			}{
+				{name: "clave con espacios", codigoID: vigente.ID, usuarioID: usuario.ID, clave: "con espacio", wantStatus: http.StatusBadRequest, wantError: "validation_error"},
+				{name: "código inexistente", codigoID: 9999, usuarioID: usuario.ID, clave: "nueva123", wantStatus: http.StatusNotFound, wantError: "codigo_not_found"},
+				{name: "código de otro usuario", codigoID: vigente.ID, usuarioID: otro.ID, clave: "nueva123", wantStatus: http.StatusBadRequest, wantError: "codigo_user_mismatch"},
+				{name: "cambio correcto", codigoID: vigente.ID, usuarioID: usuario.ID, clave: "nueva123", wantStatus: http.StatusOK},
+				{name: "código ya usado", codigoID: vigente.ID, usuarioID: usuario.ID, clave: "nueva456", wantStatus: http.StatusBadRequest, wantError: "codigo_invalid_state"},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					resp := testutil.Do(t, app, "POST", "/auth/reset-password", map[string]interface{}{
+						"codigo_id":  tt.codigoID,
+						"usuario_id": tt.usuarioID,
+						"clave":      tt.clave,
+					}, "")
+					if resp.Status != tt.wantStatus {
+						t.Fatalf("status = %d, want %d: %s", resp.Status, tt.wantStatus, resp.Body)
+					}
+					if tt.wantError != "" {
+						if got := resp.Map(t)["error"]; got != tt.wantError {
+							t.Errorf("error = %v, want %s", got, tt.wantError)
+						}
+					}
+				})
+			}
+
+			resp := testutil.Do(t, app, "POST", "/auth/login", map[string]string{"usuario": usuario.Usuario, "contraseña": "nueva123"}, "")
+			if resp.Status != http.StatusOK {
+				t.Fatalf("login con la clave restablecida: status = %d: %s", resp.Status, resp.Body)
+			}
+		})
+
+		t.Run("recover-password", func(t *testing.T) {
+			sinCorreo := f.Persona(func(p *models.Persona) { p.Correo = nil })
+			tests := []struct {
+				name   string
+				cedula string
+			}{
+				{name: "persona inexistente", cedula: "0000000000"},
+				{name: "persona sin correo", cedula: sinCorreo.Cedula},
+				{name: "cédula vacía", cedula: ""},
+			}
+			for _, tt := range tests {
+				t.Run(tt.name, func(t *testing.T) {
+					resp := testutil.Do(t, app, "POST", "/auth/recover-password", map[string]string{"cedula": tt.cedula}, "")
+					if resp.Status != http.StatusBadRequest {
+						t.Fatalf("status = %d, want 400: %s", resp.Status, resp.Body)
+					}
+				})
+			}
+		})
+	})
+}
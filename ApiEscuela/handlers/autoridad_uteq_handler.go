@@ -3,6 +3,7 @@ package handlers
 import (
 	"ApiEscuela/models"
 	"ApiEscuela/repositories"
+	"ApiEscuela/validacion"
 	"strconv"
 	"strings"
 
@@ -10,15 +11,22 @@ import (
 )
 
 type AutoridadUTEQHandler struct {
-	autoridadRepo *repositories.AutoridadUTEQRepository
-	personaRepo   *repositories.PersonaRepository
+	autoridadRepo repositories.AutoridadUTEQRepository
+	personaRepo   repositories.PersonaRepository
+	validador     *validacion.Validador
 }
 
-func NewAutoridadUTEQHandler(autoridadRepo *repositories.AutoridadUTEQRepository, personaRepo *repositories.PersonaRepository) *AutoridadUTEQHandler {
+func NewAutoridadUTEQHandler(autoridadRepo repositories.AutoridadUTEQRepository, personaRepo repositories.PersonaRepository) (*AutoridadUTEQHandler, error) {
+	validador := validacion.New().
+		ConReferencia("persona", referencia("persona_no_existe", "No se encontró la persona con el ID especificado", personaRepo))
+	if err := validador.Verificar(models.AutoridadUTEQ{}); err != nil {
+		return nil, err
+	}
 	return &AutoridadUTEQHandler{
 		autoridadRepo: autoridadRepo,
 		personaRepo:   personaRepo,
-	}
+		validador:     validador,
+	}, nil
 }
 
 // CreateAutoridadUTEQ crea una nueva autoridad UTEQ
@@ -30,40 +38,21 @@ func (h *AutoridadUTEQHandler) CreateAutoridadUTEQ(c *fiber.Ctx) error {
 		return SendError(c, 400, "json_invalido", "No se puede procesar el JSON. Verifique el formato de los datos", err.Error())
 	}
 
-	// Validar campos requeridos
-	if validationErrors := h.validateAutoridadUTEQRequiredFields(&autoridad); len(validationErrors) > 0 {
-		return SendValidationError(c, "Faltan campos requeridos", validationErrors)
-	}
-
-	// Validar datos completos
-	if validationErrors := h.validateAutoridadUTEQ(&autoridad, false); len(validationErrors) > 0 {
-		return SendValidationError(c, "Los datos proporcionados no son válidos", validationErrors)
+	// Validar según las etiquetas validate del modelo
+	if err := h.validador.Validar(&autoridad); err != nil {
+		return err
 	}
 
-	// Limpiar datos
+	// Limpiar datos; el cargo es opcional y tiene un valor por defecto
 	autoridad.Cargo = strings.TrimSpace(autoridad.Cargo)
-
-	// Verificar que el PersonaID sea válido
-	if autoridad.PersonaID == 0 {
-		return SendError(c, 400, "persona_id_invalido", "El ID de la persona es requerido", "Proporcione un persona_id válido mayor que 0")
-	}
-
-	// Verificar que la persona existe (validación de relación)
-	if !h.personaExists(autoridad.PersonaID) {
-		return SendError(c, 400, "persona_no_existe", "No se encontró la persona con el ID especificado", "Verifique que el persona_id sea correcto y que la persona exista en el sistema")
+	if autoridad.Cargo == "" {
+		autoridad.Cargo = "Autoridad UTEQ"
 	}
 
 	// Crear autoridad
 	if err := h.autoridadRepo.CreateAutoridadUTEQ(&autoridad); err != nil {
-		// Manejar errores específicos del repositorio
-		switch err.Error() {
-		case "persona no encontrada":
-			return SendError(c, 400, "persona_no_existe", "No se encontró la persona con el ID especificado", "Verifique que el persona_id sea correcto y que la persona exista en el sistema")
-		case "autoridad ya existe":
-			return SendError(c, 409, "autoridad_duplicada", "La persona ya tiene un cargo asignado", "Una persona solo puede tener un cargo de autoridad")
-		default:
-			return SendError(c, 500, "error_base_datos", "Error interno del servidor", "No se pudo crear la autoridad UTEQ")
-		}
+		// Persona inexistente y duplicados son errores tipados; ErrorHandler los responde con su código
+		return err
 	}
 
 	return SendSuccess(c, 201, autoridad)
@@ -143,18 +132,13 @@ func (h *AutoridadUTEQHandler) UpdateAutoridadUTEQ(c *fiber.Ctx) error {
 		return SendError(c, 400, "json_invalido", "No se puede procesar el JSON. Verifique el formato de los datos", err.Error())
 	}
 
-	// Validar datos de actualización
-	if validationErrors := h.validateAutoridadUTEQ(&updateData, true); len(validationErrors) > 0 {
-		return SendValidationError(c, "Los datos proporcionados no son válidos", validationErrors)
+	// Validar según las etiquetas validate del modelo
+	if err := h.validador.Validar(&updateData); err != nil {
+		return err
 	}
 
 	// Verificar si la nueva persona ya tiene un cargo asignado (si cambia la persona)
 	if updateData.PersonaID != existingAutoridad.PersonaID {
-		// Verificar que la nueva persona existe
-		if !h.personaExists(updateData.PersonaID) {
-			return SendError(c, 400, "persona_no_existe", "No se encontró la persona con el ID especificado", "Verifique que el persona_id sea correcto y que la persona exista en el sistema")
-		}
-
 		if existingAutoridadByPersona, _ := h.autoridadRepo.GetAutoridadUTEQByPersona(updateData.PersonaID); existingAutoridadByPersona != nil {
 			return SendError(c, 409, "autoridad_duplicada", "La persona ya tiene un cargo asignado", "Una persona solo puede tener un cargo de autoridad")
 		}
@@ -172,6 +156,11 @@ func (h *AutoridadUTEQHandler) UpdateAutoridadUTEQ(c *fiber.Ctx) error {
 	return SendSuccess(c, 200, existingAutoridad)
 }
 
+// PatchAutoridadUTEQ actualiza solo los campos enviados de una autoridad UTEQ (JSON Merge Patch)
+func (h *AutoridadUTEQHandler) PatchAutoridadUTEQ(c *fiber.Ctx) error {
+	return mergePatch(c, cargarCon(h.autoridadRepo.GetAutoridadUTEQByID), h.UpdateAutoridadUTEQ)
+}
+
 // DeleteAutoridadUTEQ elimina una autoridad UTEQ y en cascada su usuario y persona
 func (h *AutoridadUTEQHandler) DeleteAutoridadUTEQ(c *fiber.Ctx) error {
 	idStr := c.Params("id")
@@ -266,61 +255,6 @@ func (h *AutoridadUTEQHandler) GetAutoridadUTEQByPersona(c *fiber.Ctx) error {
 	return SendSuccess(c, 200, autoridad)
 }
 
-// validateAutoridadUTEQ valida los datos de una autoridad UTEQ
-func (h *AutoridadUTEQHandler) validateAutoridadUTEQ(autoridad *models.AutoridadUTEQ, isUpdate bool) []ValidationError {
-	var errors []ValidationError
-
-	// Validar PersonaID
-	if autoridad.PersonaID == 0 {
-		errors = append(errors, ValidationError{
-			Field:   "persona_id",
-			Message: "El ID de la persona es requerido",
-		})
-	}
-
-	// Validar Cargo (opcional pero si se proporciona debe ser válido)
-	cargo := strings.TrimSpace(autoridad.Cargo)
-	if cargo != "" {
-		// Validar longitud mínima
-		if len(cargo) < 2 {
-			errors = append(errors, ValidationError{
-				Field:   "cargo",
-				Message: "El cargo debe tener al menos 2 caracteres",
-				Value:   autoridad.Cargo,
-			})
-		} else if len(cargo) > 100 {
-			// Validar longitud máxima
-			errors = append(errors, ValidationError{
-				Field:   "cargo",
-				Message: "El cargo no puede exceder 100 caracteres",
-				Value:   autoridad.Cargo,
-			})
-		}
-	}
-
-	return errors
-}
-
-// validateAutoridadUTEQRequiredFields valida que los campos requeridos estén presentes
-func (h *AutoridadUTEQHandler) validateAutoridadUTEQRequiredFields(autoridad *models.AutoridadUTEQ) []ValidationError {
-	var errors []ValidationError
-
-	if autoridad.PersonaID == 0 {
-		errors = append(errors, ValidationError{
-			Field:   "persona_id",
-			Message: "El campo persona_id es requerido",
-		})
-	}
-
-	// El cargo es opcional según el modelo, pero si se proporciona debe ser válido
-	if strings.TrimSpace(autoridad.Cargo) == "" {
-		// Si no se proporciona cargo, establecer un valor por defecto
-		autoridad.Cargo = "Autoridad UTEQ"
-	}
-
-	return errors
-}
-
 // validateAutoridadUTEQSearchParams valida los parámetros de búsqueda
 func (h *AutoridadUTEQHandler) validateAutoridadUTEQSearchParams(cargo string) []ValidationError {
 	var errors []ValidationError
@@ -338,14 +272,3 @@ func (h *AutoridadUTEQHandler) validateAutoridadUTEQSearchParams(cargo string) [
 
 	return errors
 }
-
-// personaExists verifica si una persona existe en la base de datos
-func (h *AutoridadUTEQHandler) personaExists(personaID uint) bool {
-	if personaID == 0 {
-		return false
-	}
-
-	var count int64
-	err := h.personaRepo.GetDB().Model(&models.Persona{}).Where("id = ?", personaID).Count(&count).Error
-	return err == nil && count > 0
-}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"ApiEscuela/backup"
+	"ApiEscuela/services"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type BackupHandler struct {
+	backupService services.BackupService
+}
+
+func NewBackupHandler(backupService services.BackupService) *BackupHandler {
+	return &BackupHandler{backupService: backupService}
+}
+
+// ExportBackup descarga un respaldo .tar.gz de todas las tablas y de los archivos subidos
+func (h *BackupHandler) ExportBackup(c *fiber.Ctx) error {
+	// El respaldo se escribe primero en un archivo temporal para poder responder con un error si falla
+	tmp, err := os.CreateTemp("", "apiescuela-backup-*.tar.gz")
+	if err != nil {
+		return SendError(c, 500, "backup_error", "No se pudo generar el respaldo", err.Error())
+	}
+	os.Remove(tmp.Name()) // el archivo se libera al cerrarlo
+
+	opts := backup.OpcionesExportacion{SinArchivos: c.QueryBool("sin_archivos")}
+	if _, err := h.backupService.Exportar(tmp, opts); err != nil {
+		tmp.Close()
+		return h.sendBackupError(c, err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		tmp.Close()
+		return SendError(c, 500, "backup_error", "No se pudo generar el respaldo", err.Error())
+	}
+
+	nombre := fmt.Sprintf("apiescuela-backup-%s.tar.gz", time.Now().Format("20060102-150405"))
+	c.Set(fiber.HeaderContentType, "application/gzip")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, nombre))
+	return c.SendStream(tmp)
+}
+
+// RestoreBackup restaura un respaldo subido en el campo "archivo"
+func (h *BackupHandler) RestoreBackup(c *fiber.Ctx) error {
+	archivo, err := c.FormFile("archivo")
+	if err != nil {
+		return SendError(c, 400, "missing_file", "El archivo de respaldo es requerido", "Envíe el respaldo .tar.gz en el campo 'archivo'")
+	}
+	f, err := archivo.Open()
+	if err != nil {
+		return SendError(c, 400, "invalid_file", "No se pudo leer el archivo de respaldo", err.Error())
+	}
+	defer f.Close()
+
+	opts := backup.OpcionesRestauracion{
+		Reemplazar:  c.FormValue("reemplazar") == "true",
+		SinArchivos: c.FormValue("sin_archivos") == "true",
+		Simular:     c.FormValue("simular") == "true",
+	}
+	resultado, err := h.backupService.Restaurar(f, opts)
+	if err != nil {
+		return h.sendBackupError(c, err)
+	}
+	return SendSuccess(c, 200, resultado)
+}
+
+// sendBackupError traduce los errores del servicio de respaldos a respuestas HTTP
+func (h *BackupHandler) sendBackupError(c *fiber.Ctx, err error) error {
+	var errValidacion *backup.ErrorValidacion
+	switch {
+	case errors.As(err, &errValidacion):
+		return SendError(c, 422, "invalid_backup", "El respaldo no es válido; no se modificó la base de datos", errValidacion.Problemas...)
+	case errors.Is(err, services.ErrBackupNoDisponible):
+		return SendError(c, 503, "backup_unavailable", "Los respaldos no están disponibles", err.Error())
+	default:
+		return SendError(c, 500, "backup_error", "No se pudo completar la operación de respaldo", err.Error())
+	}
+}
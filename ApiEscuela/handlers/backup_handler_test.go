@@ -0,0 +1,40 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"ApiEscuela/testutil"
+)
+
+func TestBackupSoloAdministrador(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+		coAdmin := f.Usuario(f.Persona(), f.TipoUsuario("CoAdministrador"), "secreto1")
+
+		for _, path := range []string{"/api/admin/backup", "/api/v1/admin/backup", "/api/v2/admin/backup"} {
+			if res := testutil.Do(t, app, http.MethodGet, path, nil, testutil.Token(t, coAdmin)); res.Status != http.StatusForbidden {
+				t.Errorf("%s con CoAdministrador = %d, se esperaba 403", path, res.Status)
+			}
+		}
+		if res := testutil.Do(t, app, http.MethodGet, "/api/admin/backup", nil, ""); res.Status != http.StatusUnauthorized {
+			t.Errorf("sin token = %d, se esperaba 401", res.Status)
+		}
+		if res := testutil.DoMultipart(t, app, http.MethodPost, "/api/admin/restore", nil, e.AdminToken); res.Status != http.StatusBadRequest {
+			t.Errorf("restore sin archivo = %d, se esperaba 400: %s", res.Status, res.Body)
+		}
+
+		res := testutil.Do(t, app, http.MethodGet, "/api/admin/backup?sin_archivos=true", nil, e.AdminToken)
+		if repos.DB == nil {
+			if res.Status != http.StatusServiceUnavailable {
+				t.Errorf("backup en memoria = %d, se esperaba 503", res.Status)
+			}
+			return
+		}
+		if res.Status != http.StatusOK || res.Header.Get("Content-Type") != "application/gzip" {
+			t.Errorf("backup = %d %s: %s", res.Status, res.Header.Get("Content-Type"), res.Body)
+		}
+	})
+}
@@ -0,0 +1,17 @@
+package handlers
+
+import (
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// paramBusqueda lee el término de búsqueda de la ruta. Fiber no decodifica la ruta, así que
+// "educaci%C3%B3n" llega codificado; si no se puede decodificar se usa tal como llegó.
+func paramBusqueda(c *fiber.Ctx, nombre string) string {
+	valor := c.Params(nombre)
+	if decodificado, err := url.PathUnescape(valor); err == nil {
+		return decodificado
+	}
+	return valor
+}
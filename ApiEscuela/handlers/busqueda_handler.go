@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"ApiEscuela/services"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type BusquedaHandler struct {
+	busquedaService services.BusquedaService
+}
+
+func NewBusquedaHandler(busquedaService services.BusquedaService) *BusquedaHandler {
+	return &BusquedaHandler{busquedaService: busquedaService}
+}
+
+// Buscar busca en personas, instituciones, programas de visita y noticias y agrupa los resultados por tipo
+func (h *BusquedaHandler) Buscar(c *fiber.Ctx) error {
+	consulta := services.ConsultaBusqueda{Texto: c.Query("q")}
+	if valor := c.Query("tipos"); valor != "" {
+		for _, tipo := range strings.Split(valor, ",") {
+			if tipo = strings.TrimSpace(tipo); tipo != "" {
+				consulta.Tipos = append(consulta.Tipos, tipo)
+			}
+		}
+	}
+	if valor := c.Query("limite"); valor != "" {
+		n, err := strconv.Atoi(valor)
+		if err != nil || n < 1 {
+			return SendValidationError(c, "El límite no es válido", []ValidationError{{
+				Field:   "limite",
+				Message: "Debe ser un número entre 1 y " + strconv.Itoa(services.LimiteBusquedaMaximo),
+				Value:   valor,
+			}})
+		}
+		consulta.Limite = n
+	}
+	switch modo := c.Query("modo"); modo {
+	case "", services.ModoBusquedaCompleta:
+	case services.ModoAutocompletar:
+		consulta.Autocompletar = true
+	default:
+		return SendValidationError(c, "El modo no es válido", []ValidationError{{
+			Field:   "modo",
+			Message: "Debe ser " + services.ModoBusquedaCompleta + " o " + services.ModoAutocompletar,
+			Value:   modo,
+		}})
+	}
+
+	resultado, err := h.busquedaService.Buscar(consulta)
+	if err != nil {
+		return err
+	}
+	return SendSuccess(c, 200, resultado)
+}
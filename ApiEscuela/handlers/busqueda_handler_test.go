@@ -0,0 +1,137 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"ApiEscuela/models"
+	"ApiEscuela/services"
+	"ApiEscuela/testutil"
+)
+
+func TestBusquedaGlobal(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+
+		telefono := "099-555-1234"
+		rafael := f.Persona(func(p *models.Persona) {
+			p.Nombre = "Rafael Muñoz Peña"
+			p.Cedula = "1205123456"
+			p.Telefono = &telefono
+		})
+		f.Persona(func(p *models.Persona) { p.Nombre = "Rafaela Andrade"; p.Cedula = "1700000001" })
+		colegio := f.Institucion(func(i *models.Institucion) {
+			i.Nombre = "Colegio Técnico Quevedo"
+			i.Direccion = "Av. June Guzmán"
+		})
+		fecha := time.Date(2030, 5, 14, 9, 30, 0, 0, time.Local)
+		programa := &models.ProgramaVisita{Fecha: fecha, InstitucionID: colegio.ID}
+		if err := repos.ProgramaVisita.CreateProgramaVisita(programa); err != nil {
+			t.Fatal(err)
+		}
+		noticia := &models.Noticia{Titulo: "Rafael gana la feria", Descripcion: "Proyecto de robótica", UsuarioID: e.Admin.ID}
+		if err := repos.Noticia.CreateNoticia(noticia); err != nil {
+			t.Fatal(err)
+		}
+
+		buscar := func(ruta string) services.ResultadoBusquedaGlobal {
+			t.Helper()
+			res := testutil.Do(t, app, http.MethodGet, ruta, nil, e.AdminToken)
+			if res.Status != http.StatusOK {
+				t.Fatalf("GET %s = %d: %s", ruta, res.Status, res.Body)
+			}
+			var respuesta struct {
+				Data services.ResultadoBusquedaGlobal `json:"data"`
+			}
+			res.JSON(t, &respuesta)
+			return respuesta.Data
+		}
+		grupo := func(r services.ResultadoBusquedaGlobal, tipo string) services.GrupoBusqueda {
+			t.Helper()
+			for _, g := range r.Grupos {
+				if g.Tipo == tipo {
+					return g
+				}
+			}
+			t.Fatalf("sin grupo %s: %+v", tipo, r.Grupos)
+			return services.GrupoBusqueda{}
+		}
+
+		// Todos los tipos, en orden y con el tipo en cada resultado
+		resultado := buscar("/api/search?q=rafael")
+		if len(resultado.Grupos) != len(services.TiposBusqueda) || resultado.Modo != services.ModoBusquedaCompleta {
+			t.Fatalf("resultado = %+v", resultado)
+		}
+		for i, tipo := range services.TiposBusqueda {
+			if resultado.Grupos[i].Tipo != tipo {
+				t.Errorf("grupo %d = %s, se esperaba %s", i, resultado.Grupos[i].Tipo, tipo)
+			}
+		}
+		personas := grupo(resultado, services.TipoBusquedaPersona).Resultados
+		if len(personas) != 2 || personas[0].ID != rafael.ID || personas[0].Tipo != services.TipoBusquedaPersona {
+			t.Errorf("personas = %+v", personas)
+		}
+		if noticias := grupo(resultado, services.TipoBusquedaNoticia).Resultados; len(noticias) != 1 || noticias[0].ID != noticia.ID || noticias[0].Fragmento == "" {
+			t.Errorf("noticias = %+v", noticias)
+		}
+
+		// Nombre sin tildes, inicio de la cédula, correo y teléfono
+		for _, q := range []string{"munoz%20pena", "120512", "PERSONA", "5551234"} {
+			personas := grupo(buscar("/api/search?tipos=persona&q="+q), services.TipoBusquedaPersona).Resultados
+			if len(personas) == 0 {
+				t.Errorf("q=%s sin resultados", q)
+			}
+		}
+		if personas := grupo(buscar("/api/search?tipos=persona&q=1234"), services.TipoBusquedaPersona).Resultados; len(personas) != 1 || personas[0].ID != rafael.ID {
+			t.Errorf("teléfono = %+v", personas)
+		}
+		if personas := grupo(buscar("/api/search?tipos=persona&q=munoz%20andrade"), services.TipoBusquedaPersona).Resultados; len(personas) != 0 {
+			t.Errorf("se esperaban todas las palabras: %+v", personas)
+		}
+
+		// Solo los tipos pedidos y hay_mas cuando se supera el límite
+		resultado = buscar("/api/search?q=rafa&tipos=persona&limite=1")
+		if len(resultado.Grupos) != 1 || len(resultado.Grupos[0].Resultados) != 1 || !resultado.Grupos[0].HayMas {
+			t.Errorf("limite=1: %+v", resultado)
+		}
+
+		// Programas de visita por fecha o por institución
+		for _, q := range []string{"2030-05-14", "14/05/2030", "tecnico%20quevedo"} {
+			programas := grupo(buscar("/api/search?tipos=programa_visita&q="+q), services.TipoBusquedaProgramaVisita).Resultados
+			if len(programas) != 1 || programas[0].ID != programa.ID || programas[0].Titulo != colegio.Nombre || programas[0].Detalle != "14/05/2030 09:30" {
+				t.Errorf("q=%s: %+v", q, programas)
+			}
+		}
+
+		// El autocompletado tolera errores de escritura
+		resultado = buscar("/api/search?modo=autocompletar&tipos=persona,institucion&q=rafel")
+		if personas := grupo(resultado, services.TipoBusquedaPersona).Resultados; len(personas) == 0 || personas[0].ID != rafael.ID {
+			t.Errorf("autocompletar rafel = %+v", personas)
+		}
+		resultado = buscar("/api/search?modo=autocompletar&tipos=institucion,programa_visita&q=colejio")
+		if instituciones := grupo(resultado, services.TipoBusquedaInstitucion).Resultados; len(instituciones) != 1 || instituciones[0].ID != colegio.ID {
+			t.Errorf("autocompletar colejio = %+v", instituciones)
+		}
+		if programas := grupo(resultado, services.TipoBusquedaProgramaVisita).Resultados; len(programas) != 1 || programas[0].ID != programa.ID {
+			t.Errorf("autocompletar programas = %+v", programas)
+		}
+
+		for _, ruta := range []string{
+			"/api/search?q=a",
+			"/api/search?q=rafael&tipos=cursos",
+			"/api/search?q=rafael&limite=51",
+			"/api/search?q=rafael&limite=cero",
+			"/api/search?q=rafael&modo=exacto",
+		} {
+			if res := testutil.Do(t, app, http.MethodGet, ruta, nil, e.AdminToken); res.Status != http.StatusBadRequest {
+				t.Errorf("GET %s = %d: %s", ruta, res.Status, res.Body)
+			}
+		}
+		if res := testutil.Do(t, app, http.MethodGet, "/api/search?q=rafael", nil, ""); res.Status != http.StatusUnauthorized {
+			t.Errorf("sin token = %d", res.Status)
+		}
+	})
+}
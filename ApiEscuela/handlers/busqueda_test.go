@@ -0,0 +1,110 @@
+package handlers_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"ApiEscuela/handlers"
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+	"ApiEscuela/testutil"
+)
+
+func TestBusquedaDeTextoCompleto(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+
+		noticias := []*models.Noticia{
+			{Titulo: "Feria de tecnología", Descripcion: "Los colegios visitan la UTEQ", UsuarioID: e.Admin.ID},
+			{Titulo: "Casa abierta", Descripcion: "Muestra de proyectos de Tecnología <b>aplicada</b>", UsuarioID: e.Admin.ID},
+			{Titulo: "Educación ambiental", Descripcion: "Charla sobre reciclaje", UsuarioID: e.Admin.ID},
+		}
+		for _, n := range noticias {
+			if err := repos.Noticia.CreateNoticia(n); err != nil {
+				t.Fatal(err)
+			}
+		}
+		buscar := func(ruta string, destino interface{}) {
+			t.Helper()
+			res := testutil.Do(t, app, http.MethodGet, ruta, nil, e.AdminToken)
+			if res.Status != http.StatusOK {
+				t.Fatalf("GET %s = %d: %s", ruta, res.Status, res.Body)
+			}
+			res.JSON(t, destino)
+		}
+
+		// Sin tildes, con prefijos y ordenado por relevancia: el título pesa más que la descripción.
+		// v1 devuelve solo los registros; v2 agrega la relevancia y el fragmento.
+		var encontradas []models.Noticia
+		buscar("/api/noticias/buscar/tecnologia", &encontradas)
+		if len(encontradas) != 2 || encontradas[0].ID != noticias[0].ID || encontradas[1].ID != noticias[1].ID {
+			t.Fatalf("buscar tecnologia = %+v", encontradas)
+		}
+		var resultados []repositories.ResultadoBusqueda[models.Noticia]
+		buscar("/api/v2/noticias/buscar/tecnologia", &resultados)
+		if len(resultados) != 2 || resultados[0].Registro.ID != noticias[0].ID || resultados[1].Registro.ID != noticias[1].ID {
+			t.Fatalf("buscar tecnologia en v2 = %+v", resultados)
+		}
+		if resultados[0].Relevancia <= resultados[1].Relevancia {
+			t.Errorf("relevancia del título %v <= de la descripción %v", resultados[0].Relevancia, resultados[1].Relevancia)
+		}
+		fragmento := resultados[1].Fragmento
+		if !strings.Contains(fragmento, "<mark>Tecnología</mark>") || strings.Contains(fragmento, "<b>") || !strings.Contains(fragmento, "&lt;b&gt;") {
+			t.Errorf("fragmento %q", fragmento)
+		}
+		for _, ruta := range []string{"/api/noticias/buscar/educaci%C3%B3n", "/api/noticias/buscar/EDUCACION", "/api/noticias/buscar/ambiental%20educ"} {
+			buscar(ruta, &encontradas)
+			if len(encontradas) != 1 || encontradas[0].ID != noticias[2].ID {
+				t.Errorf("GET %s = %+v", ruta, encontradas)
+			}
+		}
+		// Todas las palabras deben aparecer
+		if buscar("/api/noticias/buscar/feria%20reciclaje", &encontradas); len(encontradas) != 0 {
+			t.Errorf("feria reciclaje = %+v", encontradas)
+		}
+
+		// Las temáticas se buscan solo por nombre
+		innovacion := &models.Tematica{Nombre: "Tecnología e innovación", Descripcion: "Robótica"}
+		ciencias := &models.Tematica{Nombre: "Ciencias", Descripcion: "Innovación en el laboratorio"}
+		for _, tematica := range []*models.Tematica{innovacion, ciencias} {
+			if err := repos.Tematica.CreateTematica(tematica); err != nil {
+				t.Fatal(err)
+			}
+		}
+		var tematicas []models.Tematica
+		buscar("/api/tematicas/nombre/innovacion", &tematicas)
+		if len(tematicas) != 1 || tematicas[0].ID != innovacion.ID {
+			t.Errorf("temáticas = %+v", tematicas)
+		}
+		var tematicasV2 []repositories.ResultadoBusqueda[models.Tematica]
+		buscar("/api/v2/tematicas/nombre/innovacion", &tematicasV2)
+		if len(tematicasV2) != 1 || tematicasV2[0].Registro.ID != innovacion.ID || !strings.Contains(tematicasV2[0].Fragmento, "<mark>innovación</mark>") {
+			t.Errorf("temáticas en v2 = %+v", tematicasV2)
+		}
+
+		// El mensaje de los comunicados se busca sin sus etiquetas HTML
+		comunicado := f.Comunicado(e.Admin, func(c *models.Comunicado) {
+			c.Mensaje = "<p>Abren las inscripciones para la <strong>feria</strong> de ciencias</p>"
+		})
+		f.Comunicado(e.Admin)
+		var comunicados []models.Comunicado
+		buscar("/api/comunicados/buscar/inscripcion%20feria", &comunicados)
+		if len(comunicados) != 1 || comunicados[0].ID != comunicado.ID {
+			t.Fatalf("comunicados = %+v", comunicados)
+		}
+		var comunicadosV2 []repositories.ResultadoBusqueda[handlers.ComunicadoV2]
+		buscar("/api/v2/comunicados/buscar/inscripcion%20feria", &comunicadosV2)
+		if len(comunicadosV2) != 1 || comunicadosV2[0].Registro.ID != comunicado.ID {
+			t.Fatalf("comunicados en v2 = %+v", comunicadosV2)
+		}
+		if fragmento := comunicadosV2[0].Fragmento; !strings.Contains(fragmento, "<mark>feria</mark>") || strings.Contains(fragmento, "strong") {
+			t.Errorf("fragmento del comunicado %q", fragmento)
+		}
+		if buscar("/api/comunicados/buscar/strong", &comunicados); len(comunicados) != 0 {
+			t.Errorf("se encontraron las etiquetas HTML: %+v", comunicados)
+		}
+	})
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"time"
+
+	"ApiEscuela/middleware"
+
+	"gorm.io/gorm"
+)
+
+var tipoGormModel = reflect.TypeOf(gorm.Model{})
+
+// versionDe calcula la versión de una lista de modelos a partir de los ID, UpdatedAt y cantidad
+// de registros, incluidas las relaciones precargadas. El resultado no depende del orden de la lista.
+func versionDe(recurso string, datos interface{}) middleware.Version {
+	var (
+		suma       uint64
+		cantidad   int
+		modificado time.Time
+	)
+	recorrerModelos(reflect.ValueOf(datos), 0, func(tipo string, m gorm.Model) {
+		h := fnv.New64a()
+		h.Write([]byte(tipo))
+		var buf [16]byte
+		binary.BigEndian.PutUint64(buf[:8], uint64(m.ID))
+		binary.BigEndian.PutUint64(buf[8:], uint64(m.UpdatedAt.UnixNano()))
+		h.Write(buf[:])
+		suma += h.Sum64()
+		cantidad++
+		if m.UpdatedAt.After(modificado) {
+			modificado = m.UpdatedAt
+		}
+	})
+	return middleware.Version{
+		ETag:       fmt.Sprintf("W/\"%s-%d-%x\"", recurso, cantidad, suma),
+		Modificado: modificado,
+	}
+}
+
+// profundidadMaxima limita el recorrido de relaciones anidadas
+const profundidadMaxima = 4
+
+// recorrerModelos llama a visitar por cada struct con gorm.Model embebido que encuentre en v
+func recorrerModelos(v reflect.Value, profundidad int, visitar func(tipo string, m gorm.Model)) {
+	if profundidad > profundidadMaxima {
+		return
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			recorrerModelos(v.Elem(), profundidad, visitar)
+		}
+	case reflect.Slice, reflect.Array:
+		switch v.Type().Elem().Kind() {
+		case reflect.Struct, reflect.Ptr, reflect.Interface:
+		default:
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			recorrerModelos(v.Index(i), profundidad, visitar)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		modelo := false
+		for i := 0; i < t.NumField(); i++ {
+			campo := t.Field(i)
+			if campo.Anonymous && campo.Type == tipoGormModel {
+				m := v.Field(i).Interface().(gorm.Model)
+				// Las relaciones no precargadas quedan en su valor cero
+				if m.ID != 0 {
+					visitar(t.Name(), m)
+				}
+				modelo = true
+			}
+		}
+		if !modelo {
+			return
+		}
+		for i := 0; i < t.NumField(); i++ {
+			campo := t.Field(i)
+			if campo.Anonymous || !campo.IsExported() {
+				continue
+			}
+			recorrerModelos(v.Field(i), profundidad+1, visitar)
+		}
+	}
+}
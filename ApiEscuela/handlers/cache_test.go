@@ -0,0 +1,71 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"ApiEscuela/testutil"
+)
+
+func TestPeticionesCondicionalesCatalogo(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+
+		res := testutil.Do(t, app, http.MethodGet, "/api/v1/provincias", nil, e.AdminToken)
+		etag := res.Header.Get("ETag")
+		if res.Status != http.StatusOK || etag == "" || res.Header.Get("Last-Modified") == "" {
+			t.Fatalf("GET provincias = %d, ETag %q, Last-Modified %q", res.Status, etag, res.Header.Get("Last-Modified"))
+		}
+		if cc := res.Header.Get("Cache-Control"); cc != "private, max-age=3600" {
+			t.Errorf("Cache-Control = %q", cc)
+		}
+
+		res = testutil.DoHeaders(t, app, http.MethodGet, "/api/v1/provincias", map[string]string{"If-None-Match": etag}, e.AdminToken)
+		if res.Status != http.StatusNotModified || len(res.Body) != 0 {
+			t.Errorf("If-None-Match vigente = %d (%d bytes), se esperaba 304", res.Status, len(res.Body))
+		}
+		res = testutil.DoHeaders(t, app, http.MethodGet, "/api/v1/provincias", map[string]string{"If-Modified-Since": res.Header.Get("Last-Modified")}, e.AdminToken)
+		if res.Status != http.StatusNotModified {
+			t.Errorf("If-Modified-Since vigente = %d, se esperaba 304", res.Status)
+		}
+
+		// Cambiar una ciudad cambia la versión de las provincias, que la incluyen
+		path := fmt.Sprintf("/api/v1/ciudades/%d", e.Ciudad.ID)
+		if res := testutil.Do(t, app, http.MethodPut, path, map[string]string{"ciudad": "Quevedo Centro"}, e.AdminToken); res.Status != http.StatusOK {
+			t.Fatalf("PUT ciudad = %d: %s", res.Status, res.Body)
+		}
+		res = testutil.DoHeaders(t, app, http.MethodGet, "/api/v1/provincias", map[string]string{"If-None-Match": etag}, e.AdminToken)
+		if res.Status != http.StatusOK || res.Header.Get("ETag") == etag {
+			t.Errorf("después del PUT = %d con ETag %q, se esperaba 200 con un ETag nuevo", res.Status, res.Header.Get("ETag"))
+		}
+
+		res = testutil.DoHeaders(t, app, http.MethodGet, "/api/v1/provincias/999999", map[string]string{"If-None-Match": "W/\"otro\""}, e.AdminToken)
+		if res.Status != http.StatusNotFound || res.Header.Get("ETag") != "" {
+			t.Errorf("provincia inexistente = %d con ETag %q", res.Status, res.Header.Get("ETag"))
+		}
+	})
+}
+
+func TestETagPorContenido(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+
+		res := testutil.Do(t, app, http.MethodGet, "/api/instituciones", nil, e.AdminToken)
+		etag := res.Header.Get("ETag")
+		if res.Status != http.StatusOK || etag == "" {
+			t.Fatalf("GET instituciones = %d, ETag %q", res.Status, etag)
+		}
+		if cc := res.Header.Get("Cache-Control"); cc != "private, no-cache" {
+			t.Errorf("Cache-Control = %q", cc)
+		}
+		res = testutil.DoHeaders(t, app, http.MethodGet, "/api/instituciones", map[string]string{"If-None-Match": etag}, e.AdminToken)
+		if res.Status != http.StatusNotModified {
+			t.Errorf("If-None-Match vigente = %d, se esperaba 304", res.Status)
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"ApiEscuela/middleware"
 	"ApiEscuela/models"
 	"ApiEscuela/repositories"
 	"strconv"
@@ -9,10 +10,10 @@ import (
 )
 
 type CiudadHandler struct {
-	ciudadRepo *repositories.CiudadRepository
+	ciudadRepo repositories.CiudadRepository
 }
 
-func NewCiudadHandler(ciudadRepo *repositories.CiudadRepository) *CiudadHandler {
+func NewCiudadHandler(ciudadRepo repositories.CiudadRepository) *CiudadHandler {
 	return &CiudadHandler{ciudadRepo: ciudadRepo}
 }
 
@@ -97,6 +98,11 @@ func (h *CiudadHandler) UpdateCiudad(c *fiber.Ctx) error {
 	return c.JSON(ciudad)
 }
 
+// PatchCiudad actualiza solo los campos enviados de una ciudad (JSON Merge Patch)
+func (h *CiudadHandler) PatchCiudad(c *fiber.Ctx) error {
+	return mergePatch(c, cargarCon(h.ciudadRepo.GetCiudadByID), h.UpdateCiudad)
+}
+
 // DeleteCiudad elimina una ciudad
 func (h *CiudadHandler) DeleteCiudad(c *fiber.Ctx) error {
 	id, err := strconv.Atoi(c.Params("id"))
@@ -148,4 +154,13 @@ func (h *CiudadHandler) GetCiudadByNombre(c *fiber.Ctx) error {
 	}
 
 	return c.JSON(ciudades)
-}
\ No newline at end of file
+}
+
+// Version calcula la versión de la lista de las ciudades para las peticiones condicionales
+func (h *CiudadHandler) Version() (middleware.Version, error) {
+	lista, err := h.ciudadRepo.GetAllCiudades()
+	if err != nil {
+		return middleware.Version{}, err
+	}
+	return versionDe("ciudades", lista), nil
+}
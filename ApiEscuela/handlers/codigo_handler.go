@@ -3,6 +3,7 @@ package handlers
 import (
 	"ApiEscuela/models"
 	"ApiEscuela/repositories"
+	"ApiEscuela/validacion"
 	"regexp"
 	"strconv"
 	"strings"
@@ -12,11 +13,20 @@ import (
 )
 
 type CodigoHandler struct {
-	codigoRepo *repositories.CodigoUsuarioRepository
+	codigoRepo repositories.CodigoUsuarioRepository
+	validador  *validacion.Validador
 }
 
-func NewCodigoHandler(codigoRepo *repositories.CodigoUsuarioRepository) *CodigoHandler {
-	return &CodigoHandler{codigoRepo: codigoRepo}
+func NewCodigoHandler(codigoRepo repositories.CodigoUsuarioRepository, usuarioRepo repositories.UsuarioRepository) (*CodigoHandler, error) {
+	validador := validacion.New().
+		ConReferencia("usuario", referencia("usuario_no_existe", "No se encontró el usuario con el ID especificado", usuarioRepo))
+	if err := validador.Verificar(models.CodigoUsuario{}); err != nil {
+		return nil, err
+	}
+	return &CodigoHandler{
+		codigoRepo: codigoRepo,
+		validador:  validador,
+	}, nil
 }
 
 // CreateCodigo crea un nuevo código
@@ -28,14 +38,9 @@ func (h *CodigoHandler) CreateCodigo(c *fiber.Ctx) error {
 		return SendError(c, 400, "invalid_json", "No se puede procesar el JSON. Verifique el formato de los datos", err.Error())
 	}
 
-	// Validar campos requeridos
-	if validationErrors := h.validateCodigoRequiredFields(&codigo); len(validationErrors) > 0 {
-		return SendValidationError(c, "Faltan campos requeridos", validationErrors)
-	}
-
-	// Validar datos completos
-	if validationErrors := h.validateCodigo(&codigo, false); len(validationErrors) > 0 {
-		return SendValidationError(c, "Los datos proporcionados no son válidos", validationErrors)
+	// Validar según las etiquetas validate del modelo
+	if err := h.validador.Validar(&codigo); err != nil {
+		return err
 	}
 
 	// Limpiar datos
@@ -121,9 +126,9 @@ func (h *CodigoHandler) UpdateCodigo(c *fiber.Ctx) error {
 		return SendError(c, 400, "invalid_json", "No se puede procesar el JSON. Verifique el formato de los datos", err.Error())
 	}
 
-	// Validar datos de actualización
-	if validationErrors := h.validateCodigo(&updateData, true); len(validationErrors) > 0 {
-		return SendValidationError(c, "Los datos proporcionados no son válidos", validationErrors)
+	// Validar según las etiquetas validate del modelo
+	if err := h.validador.Validar(&updateData); err != nil {
+		return err
 	}
 
 	// Actualizar campos (mantener ID original)
@@ -140,6 +145,11 @@ func (h *CodigoHandler) UpdateCodigo(c *fiber.Ctx) error {
 	return SendSuccess(c, 200, codigo)
 }
 
+// PatchCodigo actualiza solo los campos enviados de un código de usuario (JSON Merge Patch)
+func (h *CodigoHandler) PatchCodigo(c *fiber.Ctx) error {
+	return mergePatch(c, cargarCon(h.codigoRepo.GetByID), h.UpdateCodigo)
+}
+
 // DeleteCodigo elimina un código
 func (h *CodigoHandler) DeleteCodigo(c *fiber.Ctx) error {
 	idStr := c.Params("id")
@@ -275,96 +285,6 @@ func (h *CodigoHandler) MarcarComoExpirado(c *fiber.Ctx) error {
 	})
 }
 
-// validateCodigo valida los datos de un código
-func (h *CodigoHandler) validateCodigo(codigo *models.CodigoUsuario, isUpdate bool) []ValidationError {
-	var errors []ValidationError
-
-	// Validar UsuarioID
-	if codigo.UsuarioID == 0 {
-		errors = append(errors, ValidationError{
-			Field:   "usuario_id",
-			Message: "El ID del usuario es requerido",
-		})
-	}
-
-	// Validar Código
-	if strings.TrimSpace(codigo.Codigo) == "" {
-		errors = append(errors, ValidationError{
-			Field:   "codigo",
-			Message: "El código es requerido",
-			Value:   codigo.Codigo,
-		})
-	} else {
-		// Validar formato del código (6 dígitos numéricos)
-		codigoRegex := regexp.MustCompile(`^\d{6}$`)
-		if !codigoRegex.MatchString(strings.TrimSpace(codigo.Codigo)) {
-			errors = append(errors, ValidationError{
-				Field:   "codigo",
-				Message: "El código debe tener exactamente 6 dígitos numéricos",
-				Value:   codigo.Codigo,
-			})
-		}
-	}
-
-	// Validar Estado (opcional pero si se proporciona debe ser válido)
-	if strings.TrimSpace(codigo.Estado) != "" {
-		estadoValido := strings.TrimSpace(codigo.Estado)
-		if estadoValido != "valido" && estadoValido != "verificado" && estadoValido != "expirado" {
-			errors = append(errors, ValidationError{
-				Field:   "estado",
-				Message: "El estado debe ser 'valido', 'verificado' o 'expirado'",
-				Value:   codigo.Estado,
-			})
-		}
-	}
-
-	// Validar ExpiraEn (opcional pero si se proporciona debe ser válida)
-	if codigo.ExpiraEn != nil {
-		// Verificar que la fecha no sea muy antigua (más de 1 hora)
-		oneHourAgo := time.Now().Add(-1 * time.Hour)
-		if codigo.ExpiraEn.Before(oneHourAgo) {
-			errors = append(errors, ValidationError{
-				Field:   "expira_en",
-				Message: "La fecha de expiración no puede ser de hace más de 1 hora",
-				Value:   codigo.ExpiraEn.Format(time.RFC3339),
-			})
-		}
-
-		// Verificar que la fecha no sea muy futura (más de 24 horas)
-		oneDayFromNow := time.Now().Add(24 * time.Hour)
-		if codigo.ExpiraEn.After(oneDayFromNow) {
-			errors = append(errors, ValidationError{
-				Field:   "expira_en",
-				Message: "La fecha de expiración no puede ser de más de 24 horas en el futuro",
-				Value:   codigo.ExpiraEn.Format(time.RFC3339),
-			})
-		}
-	}
-
-	return errors
-}
-
-// validateCodigoRequiredFields valida que los campos requeridos estén presentes
-func (h *CodigoHandler) validateCodigoRequiredFields(codigo *models.CodigoUsuario) []ValidationError {
-	var errors []ValidationError
-
-	if codigo.UsuarioID == 0 {
-		errors = append(errors, ValidationError{
-			Field:   "usuario_id",
-			Message: "El campo usuario_id es requerido",
-		})
-	}
-
-	if strings.TrimSpace(codigo.Codigo) == "" {
-		errors = append(errors, ValidationError{
-			Field:   "codigo",
-			Message: "El campo codigo es requerido",
-		})
-	}
-
-	return errors
-}
-
 // validateCodigoSearchParams valida los parámetros de búsqueda
 func (h *CodigoHandler) validateCodigoSearchParams(codigo string, estado string, usuarioID uint) []ValidationError {
 	var errors []ValidationError
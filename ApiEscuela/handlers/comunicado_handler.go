@@ -1,13 +1,17 @@
 package handlers
 
 import (
+	"ApiEscuela/eventos"
 	"ApiEscuela/models"
+	"ApiEscuela/repositories"
 	"ApiEscuela/services"
+	"ApiEscuela/storage"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"mime"
-	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -17,17 +21,33 @@ import (
 )
 
 type ComunicadoHandler struct {
-	comunicadoService *services.ComunicadoService
+	comunicadoService services.ComunicadoService
+	archivos          storage.Storage
+	eventos           *eventos.Bus
+	notificaciones    services.NotificacionService
+	webhooks          services.WebhookService
 }
 
-func NewComunicadoHandler(comunicadoService *services.ComunicadoService) *ComunicadoHandler {
+func NewComunicadoHandler(comunicadoService services.ComunicadoService, archivos storage.Storage, bus *eventos.Bus, notificaciones services.NotificacionService, webhooks services.WebhookService) *ComunicadoHandler {
 	return &ComunicadoHandler{
 		comunicadoService: comunicadoService,
+		archivos:          archivos,
+		eventos:           bus,
+		notificaciones:    notificaciones,
+		webhooks:          webhooks,
 	}
 }
 
 // CreateComunicado crea y envía un nuevo comunicado
 func (h *ComunicadoHandler) CreateComunicado(c *fiber.Ctx) error {
+	return h.crearComunicado(c, func(comunicado *models.Comunicado) interface{} {
+		return comunicado
+	})
+}
+
+// crearComunicado procesa el formulario, envía el comunicado y lo guarda.
+// presentar convierte el comunicado guardado a la representación de la versión de la API.
+func (h *ComunicadoHandler) crearComunicado(c *fiber.Ctx, presentar func(*models.Comunicado) interface{}) error {
 	// Parsear el formulario multipart
 	form, err := c.MultipartForm()
 	if err != nil {
@@ -36,6 +56,24 @@ func (h *ComunicadoHandler) CreateComunicado(c *fiber.Ctx) error {
 		})
 	}
 
+	// Avance por /api/eventos; el cliente puede enviar ?trabajo= para reconocer sus eventos.
+	// Si el envío termina antes de completarse, el último evento es la etapa "error".
+	progreso := eventos.Progreso{Trabajo: c.Query("trabajo")}
+	if progreso.Trabajo == "" {
+		progreso.Trabajo = eventos.NuevoTrabajo()
+	}
+	usuarioEventos, _ := c.Locals("user_id").(uint)
+	avance := func(etapa string, procesados, total int) {
+		progreso.Etapa, progreso.Procesados, progreso.Total = etapa, procesados, total
+		h.eventos.PublicarPara(usuarioEventos, eventos.ComunicadoProgreso, progreso)
+	}
+	defer func() {
+		if !progreso.Terminado {
+			progreso.Terminado = true
+			avance("error", progreso.Procesados, progreso.Total)
+		}
+	}()
+
 	// Obtener campos del formulario
 	asunto := ""
 	if asuntos, ok := form.Value["asunto"]; ok && len(asuntos) > 0 {
@@ -92,23 +130,17 @@ func (h *ComunicadoHandler) CreateComunicado(c *fiber.Ctx) error {
 		})
 	}
 
-	// Crear carpeta para los adjuntos con fecha y hora
+	// Los adjuntos se guardan en una carpeta con fecha y hora
 	timestamp := time.Now().Format("2006-01-02_15-04-05")
-	uploadDir := filepath.Join("assets", "comunicados_files", timestamp)
 
 	// Procesar archivos adjuntos (máximo 5MB, solo PDF e imágenes)
 	var adjuntosPaths []string
 	var attachments []services.Attachment
 
 	if files, ok := form.File["adjuntos"]; ok && len(files) > 0 {
-		// Crear el directorio solo si hay archivos
-		if err := os.MkdirAll(uploadDir, 0755); err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error": "Error al crear directorio para adjuntos",
-			})
-		}
+		for i, file := range files {
+			avance("adjuntos", i, len(files))
 
-		for _, file := range files {
 			// Validar tamaño (5MB máximo)
 			if file.Size > 5*1024*1024 {
 				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -148,16 +180,21 @@ func (h *ComunicadoHandler) CreateComunicado(c *fiber.Ctx) error {
 				mimeType = "application/octet-stream"
 			}
 
-			// Guardar archivo en disco
-			filePath := filepath.Join(uploadDir, file.Filename)
-			if err := os.WriteFile(filePath, data, 0644); err != nil {
+			// Guardar archivo en el almacenamiento configurado
+			clave, err := storage.Clave("comunicados_files", timestamp, filepath.Base(file.Filename))
+			if err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": fmt.Sprintf("Nombre de archivo inválido: %s", file.Filename),
+				})
+			}
+			if err := h.archivos.Guardar(c.UserContext(), clave, bytes.NewReader(data), int64(len(data)), mimeType); err != nil {
 				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 					"error": "Error al guardar el archivo adjunto",
 				})
 			}
 
 			// Guardar la ruta relativa para la BD (usando /api/files/ para servir los archivos)
-			relativePath := fmt.Sprintf("/api/files/comunicados_files/%s/%s", timestamp, file.Filename)
+			relativePath := "/api/files/" + clave
 
 			attachments = append(attachments, services.Attachment{
 				Name:     file.Filename,
@@ -192,6 +229,7 @@ func (h *ComunicadoHandler) CreateComunicado(c *fiber.Ctx) error {
 		}
 
 		// Enviar correos masivos
+		avance("enviando", 0, len(correosDestinatarios))
 		result := h.comunicadoService.SendBulkEmails(correosDestinatarios, asunto, mensaje, attachments)
 		enviados = result.Enviados
 		total = result.Total
@@ -224,10 +262,14 @@ func (h *ComunicadoHandler) CreateComunicado(c *fiber.Ctx) error {
 			"error": "Error al guardar el comunicado",
 		})
 	}
+	h.notificaciones.ComunicadoRecibido(comunicado, destinatario)
+	if err := h.webhooks.Publicar(services.EventoComunicadoEnviado, comunicado); err != nil {
+		log.Printf("Error al publicar el comunicado %d en los webhooks: %v", comunicado.ID, err)
+	}
 
 	response := fiber.Map{
 		"success":    true,
-		"comunicado": comunicado,
+		"comunicado": presentar(comunicado),
 		"enviados":   enviados,
 		"total":      total,
 	}
@@ -236,6 +278,12 @@ func (h *ComunicadoHandler) CreateComunicado(c *fiber.Ctx) error {
 		response["errores"] = erroresEnvio
 	}
 
+	progreso.Exitosos, progreso.Fallidos = enviados, total-enviados
+	progreso.Mensaje = strings.Join(erroresEnvio, "; ")
+	progreso.Terminado = true
+	avance("completado", total, total)
+	response["trabajo"] = progreso.Trabajo
+
 	return c.Status(fiber.StatusCreated).JSON(response)
 
 }
@@ -291,9 +339,9 @@ func (h *ComunicadoHandler) DeleteComunicado(c *fiber.Ctx) error {
 	})
 }
 
-// SearchComunicados busca comunicados por asunto
+// SearchComunicados busca comunicados por asunto o mensaje sin distinguir tildes, los más relevantes primero
 func (h *ComunicadoHandler) SearchComunicados(c *fiber.Ctx) error {
-	termino := c.Params("termino")
+	termino := paramBusqueda(c, "termino")
 
 	comunicados, err := h.comunicadoService.SearchComunicados(termino)
 	if err != nil {
@@ -302,5 +350,5 @@ func (h *ComunicadoHandler) SearchComunicados(c *fiber.Ctx) error {
 		})
 	}
 
-	return c.JSON(comunicados)
+	return c.JSON(repositories.Registros(comunicados))
 }
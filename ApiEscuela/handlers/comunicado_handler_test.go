@@ -0,0 +1,140 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"ApiEscuela/models"
+	"ApiEscuela/testutil"
+)
+
+func TestCreateComunicado(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+
+		estudiante := f.Estudiante(f.Persona(), e.Institucion, e.Ciudad)
+		sinCorreo := f.Estudiante(f.Persona(func(p *models.Persona) { p.Correo = nil }), e.Institucion, e.Ciudad)
+
+		campos := func(cambios map[string]string) map[string]string {
+			fields := map[string]string{
+				"asunto":        "Visita a la UTEQ",
+				"destinatarios": fmt.Sprintf(`{"tipo":"estudiantes","ids":[%d]}`, estudiante.ID),
+				"mensaje":       "<p>Los esperamos</p>",
+				"usuario_id":    fmt.Sprint(e.Admin.ID),
+			}
+			for k, v := range cambios {
+				if v == "" {
+					delete(fields, k)
+				} else {
+					fields[k] = v
+				}
+			}
+			return fields
+		}
+
+		tests := []struct {
+			name         string
+			fields       map[string]string
+			wantStatus   int
+			wantError    string
+			wantEnviados float64
+		}{
+			{name: "sin asunto", fields: campos(map[string]string{"asunto": ""}), wantStatus: http.StatusBadRequest, wantError: "El asunto es requerido"},
+			{name: "sin destinatarios", fields: campos(map[string]string{"destinatarios": ""}), wantStatus: http.StatusBadRequest, wantError: "Los destinatarios son requeridos"},
+			{name: "sin mensaje", fields: campos(map[string]string{"mensaje": ""}), wantStatus: http.StatusBadRequest, wantError: "El mensaje es requerido"},
+			{name: "usuario inválido", fields: campos(map[string]string{"usuario_id": "abc"}), wantStatus: http.StatusBadRequest, wantError: "ID de usuario inválido"},
+			{name: "destinatarios mal formados", fields: campos(map[string]string{"destinatarios": "{"}), wantStatus: http.StatusBadRequest, wantError: "Formato de destinatarios inválido"},
+			{name: "tipo de destinatario inválido", fields: campos(map[string]string{"destinatarios": `{"tipo":"otros"}`}), wantStatus: http.StatusInternalServerError},
+			{
+				name:       "destinatarios sin correo",
+				fields:     campos(map[string]string{"destinatarios": fmt.Sprintf(`{"tipo":"estudiantes","ids":[%d]}`, sinCorreo.ID)}),
+				wantStatus: http.StatusBadRequest,
+				wantError:  "No se encontraron destinatarios con correo electrónico",
+			},
+			// Sin SMTP configurado el comunicado se guarda con el error de envío
+			{name: "correo sin SMTP", fields: campos(nil), wantStatus: http.StatusCreated, wantEnviados: 0},
+			{name: "whatsapp", fields: campos(map[string]string{"canal": "whatsapp", "enviado_a": "3"}), wantStatus: http.StatusCreated, wantEnviados: 3},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				resp := testutil.DoMultipart(t, app, "POST", "/api/comunicados", tt.fields, e.AdminToken)
+				if resp.Status != tt.wantStatus {
+					t.Fatalf("status = %d, want %d: %s", resp.Status, tt.wantStatus, resp.Body)
+				}
+				body := resp.Map(t)
+				if tt.wantError != "" {
+					if body["error"] != tt.wantError {
+						t.Errorf("error = %v, want %s", body["error"], tt.wantError)
+					}
+					return
+				}
+				if tt.wantStatus == http.StatusCreated && body["enviados"] != tt.wantEnviados {
+					t.Errorf("enviados = %v, want %v", body["enviados"], tt.wantEnviados)
+				}
+			})
+		}
+
+		comunicados, err := repos.Comunicado.GetAllComunicados()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(comunicados) != 2 {
+			t.Fatalf("comunicados guardados = %d, want 2", len(comunicados))
+		}
+		if comunicados[0].Canal != "whatsapp" {
+			t.Errorf("el más reciente debe listarse primero, canal = %s", comunicados[0].Canal)
+		}
+	})
+}
+
+func TestComunicadoVersiones(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+		comunicado := f.Comunicado(e.Admin, func(c *models.Comunicado) {
+			c.Destinatarios = `{"tipo":"instituciones","ids":[1,2]}`
+			c.Adjuntos = `["/api/files/comunicados_files/a.pdf"]`
+		})
+		path := fmt.Sprintf("/comunicados/%d", comunicado.ID)
+
+		t.Run("v1", func(t *testing.T) {
+			resp := testutil.Do(t, app, "GET", "/api/v1"+path, nil, e.AdminToken)
+			if resp.Status != http.StatusOK {
+				t.Fatalf("status = %d: %s", resp.Status, resp.Body)
+			}
+			if resp.Header.Get("Deprecation") == "" {
+				t.Error("v1 debe anunciar su deprecación")
+			}
+			body := resp.Map(t)
+			if _, ok := body["destinatarios"].(string); !ok {
+				t.Errorf("v1 devuelve destinatarios como cadena, got %T", body["destinatarios"])
+			}
+		})
+
+		t.Run("v2", func(t *testing.T) {
+			resp := testutil.Do(t, app, "GET", "/api/v2"+path, nil, e.AdminToken)
+			if resp.Status != http.StatusOK {
+				t.Fatalf("status = %d: %s", resp.Status, resp.Body)
+			}
+			var body struct {
+				Destinatarios struct {
+					Tipo string `json:"tipo"`
+					IDs  []uint `json:"ids"`
+				} `json:"destinatarios"`
+				Adjuntos []string `json:"adjuntos"`
+			}
+			resp.JSON(t, &body)
+			if body.Destinatarios.Tipo != "instituciones" || len(body.Destinatarios.IDs) != 2 {
+				t.Errorf("destinatarios = %+v", body.Destinatarios)
+			}
+			if len(body.Adjuntos) != 1 || !strings.HasSuffix(body.Adjuntos[0], "a.pdf") {
+				t.Errorf("adjuntos = %v", body.Adjuntos)
+			}
+		})
+	})
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+	"ApiEscuela/services"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ComunicadoV2 es la representación de un comunicado en /api/v2.
+// A diferencia de v1, destinatarios y adjuntos se devuelven como JSON estructurado
+// en lugar de cadenas con JSON serializado.
+type ComunicadoV2 struct {
+	ID            uint                       `json:"ID"`
+	CreatedAt     time.Time                  `json:"CreatedAt"`
+	UpdatedAt     time.Time                  `json:"UpdatedAt"`
+	Asunto        string                     `json:"asunto"`
+	Destinatarios *services.DestinatarioInfo `json:"destinatarios"`
+	Mensaje       string                     `json:"mensaje"`
+	Adjuntos      []string                   `json:"adjuntos"`
+	UsuarioID     uint                       `json:"usuario_id"`
+	EnviadoA      int                        `json:"enviado_a"`
+	Estado        string                     `json:"estado"`
+	Canal         string                     `json:"canal"`
+	Usuario       models.Usuario             `json:"usuario,omitempty"`
+}
+
+// NewComunicadoV2 convierte un comunicado almacenado a su representación v2
+func NewComunicadoV2(comunicado *models.Comunicado) ComunicadoV2 {
+	v2 := ComunicadoV2{
+		ID:        comunicado.ID,
+		CreatedAt: comunicado.CreatedAt,
+		UpdatedAt: comunicado.UpdatedAt,
+		Asunto:    comunicado.Asunto,
+		Mensaje:   comunicado.Mensaje,
+		Adjuntos:  []string{},
+		UsuarioID: comunicado.UsuarioID,
+		EnviadoA:  comunicado.EnviadoA,
+		Estado:    comunicado.Estado,
+		Canal:     comunicado.Canal,
+		Usuario:   comunicado.Usuario,
+	}
+
+	// Los registros antiguos pueden tener JSON vacío o inválido; se devuelven como null / []
+	var destinatario services.DestinatarioInfo
+	if err := json.Unmarshal([]byte(comunicado.Destinatarios), &destinatario); err == nil {
+		v2.Destinatarios = &destinatario
+	}
+	var adjuntos []string
+	if err := json.Unmarshal([]byte(comunicado.Adjuntos), &adjuntos); err == nil && adjuntos != nil {
+		v2.Adjuntos = adjuntos
+	}
+	return v2
+}
+
+func newComunicadosV2(comunicados []models.Comunicado) []ComunicadoV2 {
+	result := make([]ComunicadoV2, 0, len(comunicados))
+	for i := range comunicados {
+		result = append(result, NewComunicadoV2(&comunicados[i]))
+	}
+	return result
+}
+
+// CreateComunicadoV2 crea y envía un nuevo comunicado (respuesta v2)
+func (h *ComunicadoHandler) CreateComunicadoV2(c *fiber.Ctx) error {
+	return h.crearComunicado(c, func(comunicado *models.Comunicado) interface{} {
+		return NewComunicadoV2(comunicado)
+	})
+}
+
+// GetComunicadoV2 obtiene un comunicado por ID (respuesta v2)
+func (h *ComunicadoHandler) GetComunicadoV2(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "ID de comunicado inválido",
+		})
+	}
+
+	comunicado, err := h.comunicadoService.GetComunicadoByID(uint(id))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Comunicado no encontrado",
+		})
+	}
+
+	return c.JSON(NewComunicadoV2(comunicado))
+}
+
+// GetAllComunicadosV2 obtiene todos los comunicados (respuesta v2)
+func (h *ComunicadoHandler) GetAllComunicadosV2(c *fiber.Ctx) error {
+	comunicados, err := h.comunicadoService.GetAllComunicados()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "No se pueden obtener los comunicados",
+		})
+	}
+
+	return c.JSON(newComunicadosV2(comunicados))
+}
+
+// SearchComunicadosV2 busca comunicados por asunto o mensaje, los más relevantes primero (respuesta v2)
+func (h *ComunicadoHandler) SearchComunicadosV2(c *fiber.Ctx) error {
+	comunicados, err := h.comunicadoService.SearchComunicados(paramBusqueda(c, "termino"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "No se pueden buscar los comunicados",
+		})
+	}
+
+	resultados := make([]repositories.ResultadoBusqueda[ComunicadoV2], 0, len(comunicados))
+	for i := range comunicados {
+		resultados = append(resultados, repositories.ResultadoBusqueda[ComunicadoV2]{
+			Registro:   NewComunicadoV2(&comunicados[i].Registro),
+			Relevancia: comunicados[i].Relevancia,
+			Fragmento:  comunicados[i].Fragmento,
+		})
+	}
+	return c.JSON(resultados)
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"fmt"
+
+	"ApiEscuela/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// PreconditionResponse es la respuesta 412: el error estándar más la versión actual del
+// registro, para que el cliente pueda mostrar sus cambios junto a los del otro usuario
+type PreconditionResponse struct {
+	ErrorResponse
+	Current interface{} `json:"current"`
+}
+
+// etagRegistro es el ETag fuerte de un registro; cambia con cada actualización (UpdatedAt)
+func etagRegistro(recurso string, m gorm.Model) string {
+	return fmt.Sprintf("\"%s-%d-%d\"", recurso, m.ID, m.UpdatedAt.UnixNano())
+}
+
+// conVersion agrega el ETag del registro a la respuesta. Devuelve true si el cliente ya tiene
+// esa versión (If-None-Match), en cuyo caso solo hay que responder 304.
+func conVersion(c *fiber.Ctx, etag string) bool {
+	c.Set(fiber.HeaderETag, etag)
+	return middleware.IfNoneMatchCoincide(c, etag)
+}
+
+// verificarVersion comprueba la precondición If-Match de una escritura sobre el registro actual.
+// If-Match es opcional mientras los clientes no envíen el ETag, salvo con requerido
+// (IF_MATCH_REQUIRED=true); si lo envían se comprueba siempre. Si no se cumple, envía la
+// respuesta (428 sin If-Match, 412 con la versión actual) y devuelve false junto con el
+// resultado del envío.
+func verificarVersion(c *fiber.Ctx, etag string, actual interface{}, requerido bool) (bool, error) {
+	enviado, cumple := middleware.IfMatch(c, etag)
+	if !enviado && requerido {
+		return false, SendError(c, fiber.StatusPreconditionRequired, "precondition_required",
+			"Se requiere el encabezado If-Match con el ETag del registro", "Obtenga el registro con GET y envíe su ETag en If-Match")
+	}
+	if enviado && !cumple {
+		return false, versionDesactualizada(c, etag, actual)
+	}
+	return true, nil
+}
+
+// versionDesactualizada responde 412 con la versión actual del registro y su ETag
+func versionDesactualizada(c *fiber.Ctx, etag string, actual interface{}) error {
+	c.Set(fiber.HeaderETag, etag)
+	return c.Status(fiber.StatusPreconditionFailed).JSON(PreconditionResponse{
+		ErrorResponse: *NewErrorResponse(c, fiber.StatusPreconditionFailed, "precondition_failed", "Otro usuario modificó el registro desde que se leyó"),
+		Current:       actual,
+	})
+}
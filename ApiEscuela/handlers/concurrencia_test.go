@@ -0,0 +1,138 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+	"ApiEscuela/testutil"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestConcurrenciaOptimistaPersonas(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+		persona := f.Persona()
+		ruta := fmt.Sprintf("/api/personas/%d", persona.ID)
+
+		res := testutil.Do(t, app, http.MethodGet, ruta, nil, e.AdminToken)
+		etag := res.Header.Get("ETag")
+		if res.Status != http.StatusOK || etag == "" || etag[:2] == "W/" {
+			t.Fatalf("GET = %d con ETag %q, se esperaba un ETag fuerte", res.Status, etag)
+		}
+		if res := testutil.DoHeaders(t, app, http.MethodGet, ruta, map[string]string{"If-None-Match": etag}, e.AdminToken); res.Status != http.StatusNotModified {
+			t.Errorf("GET con If-None-Match = %d, se esperaba 304", res.Status)
+		}
+
+		cambios := map[string]interface{}{"nombre": "Nombre del primero", "cedula": persona.Cedula, "correo": *persona.Correo}
+		estricta := repos
+		estricta.IfMatchRequerido = true
+		if res := testutil.Do(t, testutil.NewApp(estricta), http.MethodPut, ruta, cambios, e.AdminToken); res.Status != http.StatusPreconditionRequired {
+			t.Errorf("PUT sin If-Match = %d, se esperaba 428: %s", res.Status, res.Body)
+		}
+
+		// El primero guarda con la versión que leyó y recibe la nueva
+		res = put(t, app, ruta, cambios, etag, e.AdminToken)
+		nuevo := res.Header.Get("ETag")
+		if res.Status != http.StatusOK || nuevo == "" || nuevo == etag {
+			t.Fatalf("PUT con If-Match = %d con ETag %q: %s", res.Status, nuevo, res.Body)
+		}
+
+		// El segundo, con la versión anterior, recibe 412 con la actual
+		cambios["nombre"] = "Nombre del segundo"
+		res = put(t, app, ruta, cambios, etag, e.AdminToken)
+		if res.Status != http.StatusPreconditionFailed || res.Header.Get("ETag") != nuevo {
+			t.Fatalf("PUT con versión vieja = %d con ETag %q: %s", res.Status, res.Header.Get("ETag"), res.Body)
+		}
+		var conflicto struct {
+			Error   string         `json:"error"`
+			Current models.Persona `json:"current"`
+		}
+		res.JSON(t, &conflicto)
+		if conflicto.Error != "precondition_failed" || conflicto.Current.Nombre != "Nombre del primero" {
+			t.Errorf("412 inesperado: %s", res.Body)
+		}
+
+		if res := testutil.DoHeaders(t, app, http.MethodDelete, ruta, map[string]string{"If-Match": etag}, e.AdminToken); res.Status != http.StatusPreconditionFailed {
+			t.Errorf("DELETE con versión vieja = %d, se esperaba 412", res.Status)
+		}
+		if res := testutil.DoHeaders(t, app, http.MethodDelete, ruta, map[string]string{"If-Match": nuevo}, e.AdminToken); res.Status != http.StatusOK {
+			t.Errorf("DELETE con la versión actual = %d: %s", res.Status, res.Body)
+		}
+	})
+}
+
+func TestConcurrenciaOptimistaProgramasVisita(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+		fecha := time.Now().Add(72 * time.Hour).Truncate(time.Second)
+		programa := &models.ProgramaVisita{Fecha: fecha, Fechafin: fecha.Add(2 * time.Hour), InstitucionID: e.Institucion.ID}
+		if err := repos.ProgramaVisita.CreateProgramaVisita(programa); err != nil {
+			t.Fatal(err)
+		}
+
+		// Dos coordinadores leen el programa; el segundo guarda después del primero
+		primero, err := repos.ProgramaVisita.GetProgramaVisitaByID(programa.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		segundo := *primero
+		primero.Fechafin = fecha.Add(3 * time.Hour)
+		if err := repos.ProgramaVisita.UpdateProgramaVisita(primero); err != nil {
+			t.Fatal(err)
+		}
+		segundo.Fechafin = fecha.Add(4 * time.Hour)
+		if err := repos.ProgramaVisita.UpdateProgramaVisita(&segundo); !errors.Is(err, repositories.ErrVersionDesactualizada) {
+			t.Errorf("guardar una versión vieja = %v, se esperaba ErrVersionDesactualizada", err)
+		}
+
+		ruta := fmt.Sprintf("/api/programas-visita/%d", programa.ID)
+		res := testutil.Do(t, app, http.MethodGet, ruta, nil, e.AdminToken)
+		etag := res.Header.Get("ETag")
+		cambios := map[string]interface{}{
+			"fecha":          fecha.Format(time.RFC3339),
+			"fechafin":       fecha.Add(5 * time.Hour).Format(time.RFC3339),
+			"institucion_id": e.Institucion.ID,
+			"UpdatedAt":      "2000-01-01T00:00:00Z",
+		}
+		if res := put(t, app, ruta, cambios, etag, e.AdminToken); res.Status != http.StatusOK {
+			t.Fatalf("PUT con If-Match = %d: %s", res.Status, res.Body)
+		}
+		res = put(t, app, ruta, cambios, etag, e.AdminToken)
+		if res.Status != http.StatusPreconditionFailed {
+			t.Fatalf("PUT con versión vieja = %d: %s", res.Status, res.Body)
+		}
+		var conflicto struct {
+			Current models.ProgramaVisita `json:"current"`
+		}
+		res.JSON(t, &conflicto)
+		if !conflicto.Current.Fechafin.Equal(fecha.Add(5 * time.Hour)) {
+			t.Errorf("el 412 no trae la versión actual: %s", res.Body)
+		}
+
+		// Sin IF_MATCH_REQUIRED=true pueden escribir los clientes que aún no envían If-Match
+		if res := testutil.Do(t, app, http.MethodDelete, ruta, nil, e.AdminToken); res.Status != http.StatusOK {
+			t.Errorf("DELETE sin If-Match por defecto = %d: %s", res.Status, res.Body)
+		}
+	})
+}
+
+// put envía un PUT con cuerpo JSON y el encabezado If-Match
+func put(t *testing.T, app *fiber.App, ruta string, body interface{}, etag, token string) *testutil.Response {
+	t.Helper()
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testutil.DoRaw(t, app, http.MethodPut, ruta, map[string]string{"Content-Type": "application/json", "If-Match": etag}, data, token)
+}
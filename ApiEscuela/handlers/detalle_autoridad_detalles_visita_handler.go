@@ -3,17 +3,20 @@ package handlers
 import (
 	"ApiEscuela/models"
 	"ApiEscuela/repositories"
+	"ApiEscuela/services"
+	"log"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 type DetalleAutoridadDetallesVisitaHandler struct {
-	detalleRepo *repositories.DetalleAutoridadDetallesVisitaRepository
+	detalleRepo    repositories.DetalleAutoridadDetallesVisitaRepository
+	notificaciones services.NotificacionService
 }
 
-func NewDetalleAutoridadDetallesVisitaHandler(detalleRepo *repositories.DetalleAutoridadDetallesVisitaRepository) *DetalleAutoridadDetallesVisitaHandler {
-	return &DetalleAutoridadDetallesVisitaHandler{detalleRepo: detalleRepo}
+func NewDetalleAutoridadDetallesVisitaHandler(detalleRepo repositories.DetalleAutoridadDetallesVisitaRepository, notificaciones services.NotificacionService) *DetalleAutoridadDetallesVisitaHandler {
+	return &DetalleAutoridadDetallesVisitaHandler{detalleRepo: detalleRepo, notificaciones: notificaciones}
 }
 
 // CreateDetalleAutoridadDetallesVisita crea un nuevo detalle de autoridad para visita
@@ -45,6 +48,9 @@ func (h *DetalleAutoridadDetallesVisitaHandler) CreateDetalleAutoridadDetallesVi
 			"error": "No se puede crear el detalle de autoridad",
 		})
 	}
+	if err := h.notificaciones.AutoridadAsignada(&detalle); err != nil {
+		log.Printf("Error al notificar la asignación de la autoridad %d: %v", detalle.AutoridadUTEQID, err)
+	}
 
 	return c.Status(fiber.StatusCreated).JSON(detalle)
 }
@@ -111,6 +117,11 @@ func (h *DetalleAutoridadDetallesVisitaHandler) UpdateDetalleAutoridadDetallesVi
 	return c.JSON(detalle)
 }
 
+// PatchDetalleAutoridadDetallesVisita actualiza solo los campos enviados de un detalle de autoridad (JSON Merge Patch)
+func (h *DetalleAutoridadDetallesVisitaHandler) PatchDetalleAutoridadDetallesVisita(c *fiber.Ctx) error {
+	return mergePatch(c, cargarCon(h.detalleRepo.GetDetalleAutoridadDetallesVisitaByID), h.UpdateDetalleAutoridadDetallesVisita)
+}
+
 // DeleteDetalleAutoridadDetallesVisita elimina un detalle
 func (h *DetalleAutoridadDetallesVisitaHandler) DeleteDetalleAutoridadDetallesVisita(c *fiber.Ctx) error {
 	id, err := strconv.Atoi(c.Params("id"))
@@ -3,6 +3,9 @@ package handlers
 import (
 	"ApiEscuela/models"
 	"ApiEscuela/repositories"
+	"ApiEscuela/services"
+	"ApiEscuela/validacion"
+	"log"
 	"strconv"
 	"strings"
 
@@ -10,11 +13,25 @@ import (
 )
 
 type DudasHandler struct {
-	dudasRepo *repositories.DudasRepository
+	dudasRepo      repositories.DudasRepository
+	notificaciones services.NotificacionService
+	webhooks       services.WebhookService
+	validador      *validacion.Validador
 }
 
-func NewDudasHandler(dudasRepo *repositories.DudasRepository) *DudasHandler {
-	return &DudasHandler{dudasRepo: dudasRepo}
+func NewDudasHandler(dudasRepo repositories.DudasRepository, estudianteRepo repositories.EstudianteRepository, autoridadRepo repositories.AutoridadUTEQRepository, notificaciones services.NotificacionService, webhooks services.WebhookService) (*DudasHandler, error) {
+	validador := validacion.New().
+		ConReferencia("estudiante", referencia("estudiante_no_existe", "No se encontró el estudiante con el ID especificado", estudianteRepo)).
+		ConReferencia("autoridad", referencia("autoridad_no_existe", "No se encontró la autoridad con el ID especificado", autoridadRepo))
+	if err := validador.Verificar(models.Dudas{}); err != nil {
+		return nil, err
+	}
+	return &DudasHandler{
+		dudasRepo:      dudasRepo,
+		notificaciones: notificaciones,
+		webhooks:       webhooks,
+		validador:      validador,
+	}, nil
 }
 
 // CreateDudas crea una nueva duda
@@ -26,14 +43,9 @@ func (h *DudasHandler) CreateDudas(c *fiber.Ctx) error {
 		return SendError(c, 400, "json_invalido", "No se puede procesar el JSON. Verifique el formato de los datos", err.Error())
 	}
 
-	// Validar campos requeridos
-	if validationErrors := h.validateDudasRequiredFields(&duda); len(validationErrors) > 0 {
-		return SendValidationError(c, "Faltan campos requeridos", validationErrors)
-	}
-
-	// Validar datos completos
-	if validationErrors := h.validateDudas(&duda, false); len(validationErrors) > 0 {
-		return SendValidationError(c, "Los datos proporcionados no son válidos", validationErrors)
+	// Validar según las etiquetas validate del modelo
+	if err := h.validador.Validar(&duda); err != nil {
+		return err
 	}
 
 	// Crear duda
@@ -98,9 +110,9 @@ func (h *DudasHandler) UpdateDudas(c *fiber.Ctx) error {
 		return SendError(c, 400, "json_invalido", "No se puede procesar el JSON. Verifique el formato de los datos", err.Error())
 	}
 
-	// Validar datos de actualización
-	if validationErrors := h.validateDudas(&updateData, true); len(validationErrors) > 0 {
-		return SendValidationError(c, "Los datos proporcionados no son válidos", validationErrors)
+	// Validar según las etiquetas validate del modelo
+	if err := h.validador.Validar(&updateData); err != nil {
+		return err
 	}
 
 	// Actualizar campos
@@ -118,6 +130,11 @@ func (h *DudasHandler) UpdateDudas(c *fiber.Ctx) error {
 	return SendSuccess(c, 200, existingDuda)
 }
 
+// PatchDudas actualiza solo los campos enviados de una duda (JSON Merge Patch)
+func (h *DudasHandler) PatchDudas(c *fiber.Ctx) error {
+	return mergePatch(c, cargarCon(h.dudasRepo.GetDudasByID), h.UpdateDudas)
+}
+
 // DeleteDudas elimina una duda
 func (h *DudasHandler) DeleteDudas(c *fiber.Ctx) error {
 	idStr := c.Params("id")
@@ -217,9 +234,9 @@ func (h *DudasHandler) GetDudasSinAsignar(c *fiber.Ctx) error {
 	return SendSuccess(c, 200, dudas)
 }
 
-// BuscarDudasPorPregunta busca dudas por contenido de la pregunta
+// BuscarDudasPorPregunta busca dudas por las palabras de la pregunta sin distinguir tildes, ordenadas por relevancia
 func (h *DudasHandler) BuscarDudasPorPregunta(c *fiber.Ctx) error {
-	termino := c.Params("termino")
+	termino := paramBusqueda(c, "termino")
 	if termino == "" {
 		return SendError(c, 400, "termino_faltante", "El término de búsqueda es requerido", "Proporcione un término de búsqueda")
 	}
@@ -234,6 +251,25 @@ func (h *DudasHandler) BuscarDudasPorPregunta(c *fiber.Ctx) error {
 		return SendError(c, 500, "error_base_datos", "Error interno del servidor", "No se pudieron obtener las dudas")
 	}
 
+	return SendSuccess(c, 200, repositories.Registros(dudas))
+}
+
+// BuscarDudasPorPreguntaV2 busca dudas por las palabras de la pregunta con la relevancia y un fragmento resaltado (respuesta v2)
+func (h *DudasHandler) BuscarDudasPorPreguntaV2(c *fiber.Ctx) error {
+	termino := paramBusqueda(c, "termino")
+	if termino == "" {
+		return SendError(c, 400, "termino_faltante", "El término de búsqueda es requerido", "Proporcione un término de búsqueda")
+	}
+
+	if validationErrors := h.validateDudasSearchParams(termino); len(validationErrors) > 0 {
+		return SendValidationError(c, "Los parámetros de búsqueda no son válidos", validationErrors)
+	}
+
+	dudas, err := h.dudasRepo.BuscarDudasPorPregunta(termino)
+	if err != nil {
+		return SendError(c, 500, "error_base_datos", "Error interno del servidor", "No se pudieron obtener las dudas")
+	}
+
 	return SendSuccess(c, 200, dudas)
 }
 
@@ -288,6 +324,14 @@ func (h *DudasHandler) ResponderDuda(c *fiber.Ctx) error {
 	if err := h.dudasRepo.ResponderDuda(uint(dudaID), requestData.Respuesta, requestData.AutoridadUTEQID); err != nil {
 		return SendError(c, 500, "error_base_datos", "Error interno del servidor", "No se pudo responder la duda")
 	}
+	if err := h.notificaciones.DudaRespondida(uint(dudaID)); err != nil {
+		log.Printf("Error al notificar la respuesta de la duda %d: %v", dudaID, err)
+	}
+	if duda, err := h.dudasRepo.GetDudasByID(uint(dudaID)); err == nil {
+		if err := h.webhooks.Publicar(services.EventoDudaRespondida, duda); err != nil {
+			log.Printf("Error al publicar la respuesta de la duda %d en los webhooks: %v", dudaID, err)
+		}
+	}
 
 	return SendSuccess(c, 200, fiber.Map{
 		"message": "Duda respondida exitosamente",
@@ -321,94 +365,6 @@ func (h *DudasHandler) GetDudasByPrivacidad(c *fiber.Ctx) error {
 	return SendSuccess(c, 200, dudas)
 }
 
-// validateDudas valida los datos de una duda
-func (h *DudasHandler) validateDudas(duda *models.Dudas, isUpdate bool) []ValidationError {
-	var errors []ValidationError
-
-	// Validar Pregunta
-	pregunta := strings.TrimSpace(duda.Pregunta)
-	if pregunta == "" {
-		errors = append(errors, ValidationError{
-			Field:   "pregunta",
-			Message: "La pregunta es requerida",
-			Value:   duda.Pregunta,
-		})
-	} else if len(pregunta) < 10 {
-		errors = append(errors, ValidationError{
-			Field:   "pregunta",
-			Message: "La pregunta debe tener al menos 10 caracteres",
-			Value:   duda.Pregunta,
-		})
-	} else if len(pregunta) > 1000 {
-		errors = append(errors, ValidationError{
-			Field:   "pregunta",
-			Message: "La pregunta no puede exceder 1000 caracteres",
-			Value:   duda.Pregunta,
-		})
-	}
-
-	// Validar EstudianteID
-	if duda.EstudianteID == 0 {
-		errors = append(errors, ValidationError{
-			Field:   "estudiante_id",
-			Message: "El ID del estudiante es requerido",
-		})
-	}
-
-	// Validar Privacidad
-	privacidad := strings.TrimSpace(duda.Privacidad)
-	if privacidad != "" && privacidad != "privado" && privacidad != "publico" {
-		errors = append(errors, ValidationError{
-			Field:   "privacidad",
-			Message: "El tipo de privacidad debe ser 'privado' o 'publico'",
-			Value:   duda.Privacidad,
-		})
-	}
-
-	// Validar Respuesta (opcional pero si se proporciona debe ser válida)
-	if duda.Respuesta != nil {
-		respuesta := strings.TrimSpace(*duda.Respuesta)
-		if respuesta != "" {
-			if len(respuesta) < 5 {
-				errors = append(errors, ValidationError{
-					Field:   "respuesta",
-					Message: "La respuesta debe tener al menos 5 caracteres",
-					Value:   *duda.Respuesta,
-				})
-			} else if len(respuesta) > 2000 {
-				errors = append(errors, ValidationError{
-					Field:   "respuesta",
-					Message: "La respuesta no puede exceder 2000 caracteres",
-					Value:   *duda.Respuesta,
-				})
-			}
-		}
-	}
-
-	return errors
-}
-
-// validateDudasRequiredFields valida que los campos requeridos estén presentes
-func (h *DudasHandler) validateDudasRequiredFields(duda *models.Dudas) []ValidationError {
-	var errors []ValidationError
-
-	if strings.TrimSpace(duda.Pregunta) == "" {
-		errors = append(errors, ValidationError{
-			Field:   "pregunta",
-			Message: "El campo pregunta es requerido",
-		})
-	}
-
-	if duda.EstudianteID == 0 {
-		errors = append(errors, ValidationError{
-			Field:   "estudiante_id",
-			Message: "El campo estudiante_id es requerido",
-		})
-	}
-
-	return errors
-}
-
 // validateDudasSearchParams valida los parámetros de búsqueda
 func (h *DudasHandler) validateDudasSearchParams(termino string) []ValidationError {
 	var errors []ValidationError
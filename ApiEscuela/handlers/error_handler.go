@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"errors"
+	"log"
 	"time"
 
+	"ApiEscuela/errores"
+
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -86,3 +90,57 @@ func SendSuccess(c *fiber.Ctx, statusCode int, data interface{}) error {
 		"method":      c.Method(),
 	})
 }
+
+// ErrorHandler es el manejador de errores de Fiber: convierte en la respuesta de error estándar
+// los errores que devuelven los handlers. Los errores de dominio (errores.Error) conservan su
+// código estable y su estado HTTP; los demás se responden como error interno.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	if e, ok := errores.Como(err); ok {
+		if e.Tipo == errores.TipoExterno {
+			log.Printf("Error de un servicio externo en %s %s: %v", c.Method(), c.Path(), err)
+		}
+		if len(e.Campos) > 0 {
+			validacion := make([]ValidationError, 0, len(e.Campos))
+			for _, campo := range e.Campos {
+				validacion = append(validacion, ValidationError{Field: campo.Campo, Message: campo.Mensaje, Value: campo.Valor})
+			}
+			respuesta := NewValidationResponse(c, e.Mensaje, validacion)
+			respuesta.Error = e.Codigo
+			respuesta.StatusCode = e.Estado()
+			return c.Status(e.Estado()).JSON(respuesta)
+		}
+		return SendError(c, e.Estado(), e.Codigo, e.Mensaje)
+	}
+
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return SendError(c, fiberErr.Code, codigoHTTP(fiberErr.Code), fiberErr.Message)
+	}
+
+	log.Printf("Error no controlado en %s %s: %v", c.Method(), c.Path(), err)
+	return SendError(c, fiber.StatusInternalServerError, "internal_error", "Error interno del servidor")
+}
+
+// codigoHTTP da un código estable a los errores propios de Fiber (ruta inexistente, cuerpo demasiado grande, ...)
+func codigoHTTP(estado int) string {
+	switch estado {
+	case fiber.StatusNotFound:
+		return "not_found"
+	case fiber.StatusMethodNotAllowed:
+		return "method_not_allowed"
+	case fiber.StatusRequestEntityTooLarge:
+		return "payload_too_large"
+	case fiber.StatusBadRequest:
+		return "bad_request"
+	case fiber.StatusUnauthorized:
+		return "unauthorized"
+	case fiber.StatusForbidden:
+		return "forbidden"
+	case fiber.StatusTooManyRequests:
+		return "too_many_requests"
+	}
+	if estado >= 500 {
+		return "internal_error"
+	}
+	return "http_error"
+}
@@ -0,0 +1,84 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"ApiEscuela/errores"
+	"ApiEscuela/handlers"
+	"ApiEscuela/testutil"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestErrorHandlerErroresTipados(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: handlers.ErrorHandler})
+	app.Get("/conflicto", func(c *fiber.Ctx) error {
+		return errores.Conflicto("codigo_repetido", "El código ya existe")
+	})
+	app.Get("/validacion", func(c *fiber.Ctx) error {
+		return errores.Validacion("datos_invalidos", "Datos inválidos", errores.Campo{Campo: "nombre", Mensaje: "Requerido"})
+	})
+	app.Get("/externo", func(c *fiber.Ctx) error {
+		return errores.Externo("smtp_error", "No se pudo enviar el correo", fiber.ErrServiceUnavailable)
+	})
+	app.Get("/interno", func(c *fiber.Ctx) error {
+		return fiber.ErrTeapot
+	})
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantError  string
+	}{
+		{"/conflicto", http.StatusConflict, "codigo_repetido"},
+		{"/validacion", http.StatusBadRequest, "datos_invalidos"},
+		{"/externo", http.StatusBadGateway, "smtp_error"},
+		{"/interno", http.StatusTeapot, "http_error"},
+		{"/no-existe", http.StatusNotFound, "not_found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			res := testutil.Do(t, app, http.MethodGet, tt.path, nil, "")
+			if res.Status != tt.wantStatus {
+				t.Fatalf("status = %d, se esperaba %d: %s", res.Status, tt.wantStatus, res.Body)
+			}
+			body := res.Map(t)
+			if body["error"] != tt.wantError || body["success"] != false || body["status_code"] != float64(tt.wantStatus) {
+				t.Errorf("respuesta inesperada: %s", res.Body)
+			}
+		})
+	}
+
+	body := testutil.Do(t, app, http.MethodGet, "/validacion", nil, "").Map(t)
+	validacion, _ := body["validation"].([]interface{})
+	if len(validacion) != 1 || validacion[0].(map[string]interface{})["field"] != "nombre" {
+		t.Errorf("campos de validación = %v", body["validation"])
+	}
+}
+
+func TestUsuarioDuplicadoHTTP(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+
+		persona := f.Persona()
+		nuevo := map[string]interface{}{
+			"usuario":         "repetido",
+			"contraseña":      "secreto1",
+			"persona_id":      persona.ID,
+			"tipo_usuario_id": e.TipoEstudiante.ID,
+		}
+		if res := testutil.Do(t, app, http.MethodPost, "/api/usuarios", nuevo, e.AdminToken); res.Status != http.StatusCreated {
+			t.Fatalf("crear = %d: %s", res.Status, res.Body)
+		}
+		res := testutil.Do(t, app, http.MethodPost, "/api/usuarios", nuevo, e.AdminToken)
+		if res.Status != http.StatusConflict {
+			t.Fatalf("duplicado = %d, se esperaba 409: %s", res.Status, res.Body)
+		}
+		if code := res.Map(t)["error"]; code != "usuario_duplicado" {
+			t.Errorf("error = %v, se esperaba usuario_duplicado", code)
+		}
+	})
+}
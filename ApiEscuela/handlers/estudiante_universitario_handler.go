@@ -3,21 +3,29 @@ package handlers
 import (
 	"ApiEscuela/models"
 	"ApiEscuela/repositories"
+	"ApiEscuela/validacion"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 type EstudianteUniversitarioHandler struct {
-	estudianteUnivRepo *repositories.EstudianteUniversitarioRepository
-	personaRepo        *repositories.PersonaRepository
+	estudianteUnivRepo repositories.EstudianteUniversitarioRepository
+	personaRepo        repositories.PersonaRepository
+	validador          *validacion.Validador
 }
 
-func NewEstudianteUniversitarioHandler(estudianteUnivRepo *repositories.EstudianteUniversitarioRepository, personaRepo *repositories.PersonaRepository) *EstudianteUniversitarioHandler {
+func NewEstudianteUniversitarioHandler(estudianteUnivRepo repositories.EstudianteUniversitarioRepository, personaRepo repositories.PersonaRepository) (*EstudianteUniversitarioHandler, error) {
+	validador := validacion.New().
+		ConReferencia("persona", referencia("persona_no_existe", "No se encontró la persona con el ID especificado", personaRepo))
+	if err := validador.Verificar(models.EstudianteUniversitario{}); err != nil {
+		return nil, err
+	}
 	return &EstudianteUniversitarioHandler{
 		estudianteUnivRepo: estudianteUnivRepo,
 		personaRepo:        personaRepo,
-	}
+		validador:          validador,
+	}, nil
 }
 
 // CreateEstudianteUniversitario crea un nuevo estudiante universitario
@@ -29,24 +37,9 @@ func (h *EstudianteUniversitarioHandler) CreateEstudianteUniversitario(c *fiber.
 		return SendError(c, 400, "json_invalido", "No se puede procesar el JSON. Verifique el formato de los datos", err.Error())
 	}
 
-	// Validar campos requeridos
-	if validationErrors := h.validateEstudianteUniversitarioRequiredFields(&estudiante); len(validationErrors) > 0 {
-		return SendValidationError(c, "Faltan campos requeridos", validationErrors)
-	}
-
-	// Validar datos completos
-	if validationErrors := h.validateEstudianteUniversitario(&estudiante, false); len(validationErrors) > 0 {
-		return SendValidationError(c, "Los datos proporcionados no son válidos", validationErrors)
-	}
-
-	// Verificar que el PersonaID sea válido
-	if estudiante.PersonaID == 0 {
-		return SendError(c, 400, "persona_id_invalido", "El ID de la persona es requerido", "Proporcione un persona_id válido mayor que 0")
-	}
-
-	// Verificar que la persona existe (validación de relación)
-	if !h.personaExists(estudiante.PersonaID) {
-		return SendError(c, 400, "persona_no_existe", "No se encontró la persona con el ID especificado", "Verifique que el persona_id sea correcto y que la persona exista en el sistema")
+	// Validar según las etiquetas validate del modelo
+	if err := h.validador.Validar(&estudiante); err != nil {
+		return err
 	}
 
 	// Verificar si la persona ya tiene un registro de estudiante universitario
@@ -56,13 +49,8 @@ func (h *EstudianteUniversitarioHandler) CreateEstudianteUniversitario(c *fiber.
 
 	// Crear estudiante universitario
 	if err := h.estudianteUnivRepo.CreateEstudianteUniversitario(&estudiante); err != nil {
-		// Manejar errores específicos del repositorio
-		switch err.Error() {
-		case "estudiante universitario ya existe":
-			return SendError(c, 409, "estudiante_univ_duplicado", "La persona ya tiene un registro de estudiante universitario", "Una persona solo puede tener un registro de estudiante universitario")
-		default:
-			return SendError(c, 500, "error_base_datos", "Error interno del servidor", "No se pudo crear el estudiante universitario")
-		}
+		// Los duplicados son errores tipados; ErrorHandler los responde con su código
+		return err
 	}
 
 	return SendSuccess(c, 201, estudiante)
@@ -122,18 +110,13 @@ func (h *EstudianteUniversitarioHandler) UpdateEstudianteUniversitario(c *fiber.
 		return SendError(c, 400, "json_invalido", "No se puede procesar el JSON. Verifique el formato de los datos", err.Error())
 	}
 
-	// Validar datos de actualización
-	if validationErrors := h.validateEstudianteUniversitario(&updateData, true); len(validationErrors) > 0 {
-		return SendValidationError(c, "Los datos proporcionados no son válidos", validationErrors)
+	// Validar según las etiquetas validate del modelo
+	if err := h.validador.Validar(&updateData); err != nil {
+		return err
 	}
 
 	// Verificar si la nueva persona ya tiene un registro de estudiante universitario (si cambia la persona)
 	if updateData.PersonaID != existingEstudiante.PersonaID {
-		// Verificar que la nueva persona existe
-		if !h.personaExists(updateData.PersonaID) {
-			return SendError(c, 400, "persona_no_existe", "No se encontró la persona con el ID especificado", "Verifique que el persona_id sea correcto y que la persona exista en el sistema")
-		}
-
 		if existingEstudianteByPersona, _ := h.estudianteUnivRepo.GetEstudianteUniversitarioByPersona(updateData.PersonaID); existingEstudianteByPersona != nil {
 			return SendError(c, 409, "estudiante_univ_duplicado", "La persona ya tiene un registro de estudiante universitario", "Una persona solo puede tener un registro de estudiante universitario")
 		}
@@ -145,18 +128,18 @@ func (h *EstudianteUniversitarioHandler) UpdateEstudianteUniversitario(c *fiber.
 
 	// Guardar cambios
 	if err := h.estudianteUnivRepo.UpdateEstudianteUniversitario(existingEstudiante); err != nil {
-		// Manejar errores específicos del repositorio
-		switch err.Error() {
-		case "estudiante universitario ya existe":
-			return SendError(c, 409, "estudiante_univ_duplicado", "La persona ya tiene un registro de estudiante universitario", "Una persona solo puede tener un registro de estudiante universitario")
-		default:
-			return SendError(c, 500, "error_base_datos", "Error interno del servidor", "No se pudo actualizar el estudiante universitario")
-		}
+		// Los duplicados son errores tipados; ErrorHandler los responde con su código
+		return err
 	}
 
 	return SendSuccess(c, 200, existingEstudiante)
 }
 
+// PatchEstudianteUniversitario actualiza solo los campos enviados de un estudiante universitario (JSON Merge Patch)
+func (h *EstudianteUniversitarioHandler) PatchEstudianteUniversitario(c *fiber.Ctx) error {
+	return mergePatch(c, cargarCon(h.estudianteUnivRepo.GetEstudianteUniversitarioByID), h.UpdateEstudianteUniversitario)
+}
+
 // DeleteEstudianteUniversitario elimina un estudiante universitario
 func (h *EstudianteUniversitarioHandler) DeleteEstudianteUniversitario(c *fiber.Ctx) error {
 	idStr := c.Params("id")
@@ -236,50 +219,6 @@ func (h *EstudianteUniversitarioHandler) GetEstudianteUniversitarioByPersona(c *
 	return SendSuccess(c, 200, estudiante)
 }
 
-// validateEstudianteUniversitario valida los datos de un estudiante universitario
-func (h *EstudianteUniversitarioHandler) validateEstudianteUniversitario(estudiante *models.EstudianteUniversitario, isUpdate bool) []ValidationError {
-	var errors []ValidationError
-
-	// Validar PersonaID
-	if estudiante.PersonaID == 0 {
-		errors = append(errors, ValidationError{
-			Field:   "persona_id",
-			Message: "El ID de la persona es requerido",
-		})
-	}
-
-	// Validar Semestre (opcional pero si se proporciona debe ser válido)
-	if estudiante.Semestre < 0 {
-		errors = append(errors, ValidationError{
-			Field:   "semestre",
-			Message: "El semestre no puede ser negativo",
-			Value:   strconv.Itoa(estudiante.Semestre),
-		})
-	} else if estudiante.Semestre > 0 && estudiante.Semestre > 20 { // Máximo 20 semestres (10 años)
-		errors = append(errors, ValidationError{
-			Field:   "semestre",
-			Message: "El semestre no puede exceder 20 (10 años)",
-			Value:   strconv.Itoa(estudiante.Semestre),
-		})
-	}
-
-	return errors
-}
-
-// validateEstudianteUniversitarioRequiredFields valida que los campos requeridos estén presentes
-func (h *EstudianteUniversitarioHandler) validateEstudianteUniversitarioRequiredFields(estudiante *models.EstudianteUniversitario) []ValidationError {
-	var errors []ValidationError
-
-	if estudiante.PersonaID == 0 {
-		errors = append(errors, ValidationError{
-			Field:   "persona_id",
-			Message: "El campo persona_id es requerido",
-		})
-	}
-
-	return errors
-}
-
 // validateEstudianteUniversitarioSearchParams valida los parámetros de búsqueda
 func (h *EstudianteUniversitarioHandler) validateEstudianteUniversitarioSearchParams(semestre int) []ValidationError {
 	var errors []ValidationError
@@ -303,14 +242,3 @@ func (h *EstudianteUniversitarioHandler) validateEstudianteUniversitarioSearchPa
 
 	return errors
 }
-
-// personaExists verifica si una persona existe en la base de datos
-func (h *EstudianteUniversitarioHandler) personaExists(personaID uint) bool {
-	if personaID == 0 {
-		return false
-	}
-
-	var count int64
-	err := h.personaRepo.GetDB().Model(&models.Persona{}).Where("id = ?", personaID).Count(&count).Error
-	return err == nil && count > 0
-}
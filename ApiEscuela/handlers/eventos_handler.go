@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"ApiEscuela/eventos"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Latido es cada cuánto se envía un comentario por el stream para que los proxies no cierren
+// la conexión inactiva
+const Latido = 25 * time.Second
+
+// EventosHandler transmite los eventos del bus por Server-Sent Events
+type EventosHandler struct {
+	bus *eventos.Bus
+}
+
+// NewEventosHandler crea el handler de eventos
+func NewEventosHandler(bus *eventos.Bus) *EventosHandler {
+	return &EventosHandler{bus: bus}
+}
+
+// Stream transmite los eventos del usuario (text/event-stream); ?tipos= filtra por tipo
+func (h *EventosHandler) Stream(c *fiber.Ctx) error {
+	usuarioID, _ := c.Locals("user_id").(uint)
+	// Los eventos reservados a los administradores (los de WhatsApp) solo llegan a ellos
+	administrador, _ := c.Locals("administrador").(bool)
+	ultimo := c.Get("Last-Event-ID")
+	if ultimo == "" {
+		ultimo = c.Query("last_event_id")
+	}
+	var desde uint64
+	if ultimo != "" {
+		var err error
+		if desde, err = strconv.ParseUint(ultimo, 10, 64); err != nil {
+			return SendError(c, 400, "last_event_id_invalido", "Last-Event-ID debe ser el ID numérico de un evento")
+		}
+	}
+	tipos := map[string]bool{}
+	for _, tipo := range strings.Split(c.Query("tipos"), ",") {
+		if tipo = strings.TrimSpace(tipo); tipo != "" {
+			tipos[tipo] = true
+		}
+	}
+
+	sub := h.bus.Suscribir(usuarioID, administrador, desde)
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no") // nginx no debe acumular el stream
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer sub.Cancelar()
+		latido := time.NewTicker(Latido)
+		defer latido.Stop()
+
+		fmt.Fprint(w, "retry: 3000\n\n")
+		if w.Flush() != nil {
+			return
+		}
+		for {
+			select {
+			case e, ok := <-sub.C:
+				if !ok {
+					return
+				}
+				if len(tipos) > 0 && !tipos[e.Tipo] {
+					continue
+				}
+				datos, err := json.Marshal(e)
+				if err != nil {
+					continue
+				}
+				fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Tipo, datos)
+			case <-latido.C:
+				fmt.Fprint(w, ": latido\n\n")
+			}
+			// Flush falla cuando el cliente cerró la conexión
+			if w.Flush() != nil {
+				return
+			}
+		}
+	})
+	return nil
+}
@@ -0,0 +1,104 @@
+package handlers_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"ApiEscuela/eventos"
+	"ApiEscuela/testutil"
+)
+
+func TestEventosStream(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		bus := eventos.New()
+		repos.Eventos = bus
+		app := testutil.NewApp(repos)
+
+		if res := testutil.Do(t, app, http.MethodGet, "/api/eventos", nil, ""); res.Status != http.StatusUnauthorized {
+			t.Fatalf("GET /api/eventos sin token = %d, se esperaba 401", res.Status)
+		}
+
+		// Estado vigente antes de conectarse: el administrador lo recibe al suscribirse
+		bus.PublicarAdministradores(eventos.WhatsAppEstado, map[string]string{"status": "ready"})
+
+		// EventSource no envía headers: el token va en la URL
+		abrir := func(token string) chan string {
+			cuerpo := make(chan string, 1)
+			go func() {
+				req := httptest.NewRequest(http.MethodGet, "/api/eventos?tipos=whatsapp.estado,importacion.progreso&access_token="+token, nil)
+				resp, err := app.Test(req, -1)
+				if err != nil {
+					cuerpo <- err.Error()
+					return
+				}
+				defer resp.Body.Close()
+				data, _ := io.ReadAll(resp.Body)
+				cuerpo <- resp.Header.Get("Content-Type") + "\n" + string(data)
+			}()
+			return cuerpo
+		}
+		cuerpo := abrir(e.AdminToken)
+		// Los estados de WhatsApp (con el QR que vincula la cuenta) no llegan a los demás usuarios
+		estudiante := f.Usuario(f.Persona(), e.TipoEstudiante, "clave123")
+		cuerpoEstudiante := abrir(testutil.Token(t, estudiante))
+		limite := time.Now().Add(5 * time.Second)
+		for bus.Suscriptores() < 2 {
+			if time.Now().After(limite) {
+				t.Fatal("el stream no se suscribió al bus")
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+
+		filas := []map[string]interface{}{{
+			"cedula": "3000000004", "nombre": "Ana Pérez",
+			"institucion_id": e.Institucion.ID, "ciudad_id": e.Ciudad.ID,
+		}}
+		res := testutil.Do(t, app, http.MethodPost, "/api/estudiantes/bulk?trabajo=carga-1", map[string]interface{}{"estudiantes": filas}, e.AdminToken)
+		if res.Status != http.StatusOK || res.Map(t)["trabajo"] != "carga-1" {
+			t.Fatalf("carga masiva = %d: %s", res.Status, res.Body)
+		}
+		bus.PublicarPara(e.Admin.ID+100, eventos.ImportacionProgreso, eventos.Progreso{Trabajo: "de-otro"})
+		bus.Publicar(eventos.ComunicadoProgreso, eventos.Progreso{Trabajo: "filtrado"})
+
+		// Al cerrar el bus termina el stream y la respuesta queda completa
+		bus.Cerrar()
+		esperar := func(cuerpo chan string) string {
+			select {
+			case stream := <-cuerpo:
+				return stream
+			case <-time.After(5 * time.Second):
+				t.Fatal("el stream no terminó al cerrar el bus")
+				return ""
+			}
+		}
+		stream, streamEstudiante := esperar(cuerpo), esperar(cuerpoEstudiante)
+
+		if !strings.HasPrefix(stream, "text/event-stream\n") {
+			t.Errorf("Content-Type inesperado: %q", strings.SplitN(stream, "\n", 2)[0])
+		}
+		for _, esperado := range []string{
+			"event: whatsapp.estado\n",
+			`"status":"ready"`,
+			"event: importacion.progreso\n",
+			`"trabajo":"carga-1","etapa":"estudiantes","procesados":1,"total":1,"exitosos":1,"fallidos":0,"terminado":true`,
+		} {
+			if !strings.Contains(stream, esperado) {
+				t.Errorf("el stream no contiene %q:\n%s", esperado, stream)
+			}
+		}
+		for _, ajeno := range []string{"de-otro", "filtrado"} {
+			if strings.Contains(stream, ajeno) {
+				t.Errorf("el stream contiene el evento %q que no le corresponde:\n%s", ajeno, stream)
+			}
+		}
+		if strings.Contains(streamEstudiante, "whatsapp.estado") {
+			t.Errorf("un estudiante recibió el estado de WhatsApp:\n%s", streamEstudiante)
+		}
+	})
+}
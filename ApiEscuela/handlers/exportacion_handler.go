@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"ApiEscuela/errores"
+	"ApiEscuela/exportacion"
+	"ApiEscuela/repositories"
+	"ApiEscuela/services"
+	"bufio"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// formatoJSON es la respuesta normal de la ruta, sin exportar
+const formatoJSON = "json"
+
+type ExportacionHandler struct {
+	exportacionService services.ExportacionService
+}
+
+func NewExportacionHandler(exportacionService services.ExportacionService) *ExportacionHandler {
+	return &ExportacionHandler{exportacionService: exportacionService}
+}
+
+// FiltroRuta completa el filtro de la exportación con los parámetros de la ruta
+type FiltroRuta func(c *fiber.Ctx, filtro *repositories.FiltroExportacion) error
+
+// ListasExportables son los formatos de exportación y las listas que se pueden exportar
+type ListasExportables struct {
+	Formatos []string                   `json:"formatos"`
+	Listas   []services.ListaExportable `json:"listas"`
+}
+
+// GetListasExportables lista las listas que se pueden exportar y sus columnas
+func (h *ExportacionHandler) GetListasExportables(c *fiber.Ctx) error {
+	return SendSuccess(c, 200, ListasExportables{Formatos: exportacion.Formatos, Listas: h.exportacionService.Listas()})
+}
+
+// Exportable devuelve el middleware que exporta la lista de la ruta a CSV, XLSX o PDF
+//
+// El formato se pide con ?format= o con el encabezado Accept; si se pide JSON sigue al handler
+// de la ruta. ?columnas= elige las columnas y su orden.
+func (h *ExportacionHandler) Exportable(lista string, filtros ...FiltroRuta) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Vary(fiber.HeaderAccept)
+		formato, err := formatoPedido(c)
+		if err != nil {
+			return err
+		}
+		if formato == formatoJSON {
+			return c.Next()
+		}
+
+		var filtro repositories.FiltroExportacion
+		for _, completar := range filtros {
+			if err := completar(c, &filtro); err != nil {
+				return err
+			}
+		}
+		var columnas []string
+		for _, clave := range strings.Split(c.Query("columnas"), ",") {
+			if clave = strings.TrimSpace(clave); clave != "" {
+				columnas = append(columnas, clave)
+			}
+		}
+		exp, err := h.exportacionService.Preparar(lista, filtro, columnas)
+		if err != nil {
+			return err
+		}
+
+		nombre := exportacion.NombreArchivo(lista, time.Now().Format("20060102-150405"), formato)
+		c.Set(fiber.HeaderContentType, exportacion.TipoContenido(formato, false))
+		c.Set(fiber.HeaderContentDisposition, `attachment; filename="`+nombre+`"`)
+		// Las filas se leen por lotes mientras se envía la respuesta. Si la base de datos falla a
+		// mitad, el estado ya se envió: el archivo queda incompleto y el error solo se registra.
+		c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+			if err := exp.Escribir(w, formato); err != nil {
+				log.Printf("Error al exportar %s en %s: %v", lista, formato, err)
+			}
+			if err := w.Flush(); err != nil {
+				log.Printf("Error al enviar la exportación de %s: %v", lista, err)
+			}
+		})
+		return nil
+	}
+}
+
+// EsExportacion indica si la petición pide un archivo en lugar de JSON
+//
+// Las exportaciones se envían a medida que se generan, así que no pueden pasar por middlewares
+// que lean el cuerpo.
+func (h *ExportacionHandler) EsExportacion(c *fiber.Ctx) bool {
+	formato, err := formatoPedido(c)
+	return err == nil && formato != formatoJSON
+}
+
+// formatoPedido obtiene el formato de ?format= o, si no está, del encabezado Accept
+func formatoPedido(c *fiber.Ctx) (string, error) {
+	if formato := strings.ToLower(c.Query("format")); formato != "" {
+		if formato != formatoJSON && !exportacion.EsFormato(formato) {
+			return "", errores.Validacion("formato_invalido", "El formato de exportación no es válido", errores.Campo{
+				Campo:   "format",
+				Mensaje: "Debe ser " + formatoJSON + ", " + strings.Join(exportacion.Formatos, ", "),
+				Valor:   formato,
+			})
+		}
+		return formato, nil
+	}
+	ofertas := []string{fiber.MIMEApplicationJSON}
+	for _, formato := range exportacion.Formatos {
+		ofertas = append(ofertas, exportacion.TipoContenido(formato, true))
+	}
+	aceptado := c.Accepts(ofertas...)
+	for _, formato := range exportacion.Formatos {
+		if aceptado == exportacion.TipoContenido(formato, true) {
+			return formato, nil
+		}
+	}
+	return formatoJSON, nil
+}
+
+// PorInstitucion filtra por la institución del parámetro institucion_id
+func (h *ExportacionHandler) PorInstitucion(c *fiber.Ctx, filtro *repositories.FiltroExportacion) (err error) {
+	filtro.InstitucionID, err = idRuta(c, "institucion_id")
+	return err
+}
+
+// PorCiudad filtra por la ciudad del parámetro ciudad_id
+func (h *ExportacionHandler) PorCiudad(c *fiber.Ctx, filtro *repositories.FiltroExportacion) (err error) {
+	filtro.CiudadID, err = idRuta(c, "ciudad_id")
+	return err
+}
+
+// PorProgramaVisita filtra por el programa de visita del parámetro programa_visita_id
+func (h *ExportacionHandler) PorProgramaVisita(c *fiber.Ctx, filtro *repositories.FiltroExportacion) (err error) {
+	filtro.ProgramaVisitaID, err = idRuta(c, "programa_visita_id")
+	return err
+}
+
+// PorRangoFechas filtra por ?inicio= y ?fin=, ambos obligatorios en formato YYYY-MM-DD
+func (h *ExportacionHandler) PorRangoFechas(c *fiber.Ctx, filtro *repositories.FiltroExportacion) error {
+	var campos []errores.Campo
+	fechas := make([]time.Time, 2)
+	for i, nombre := range []string{"inicio", "fin"} {
+		valor := c.Query(nombre)
+		fecha, err := time.Parse("2006-01-02", valor)
+		if err != nil {
+			campos = append(campos, errores.Campo{Campo: nombre, Mensaje: "Se requiere una fecha en formato YYYY-MM-DD", Valor: valor})
+		}
+		fechas[i] = fecha
+	}
+	if len(campos) > 0 {
+		return errores.Validacion("rango_fechas_invalido", "El rango de fechas no es válido", campos...)
+	}
+	filtro.Desde, filtro.Hasta = &fechas[0], &fechas[1]
+	return nil
+}
+
+// PorEstadoDuda filtra las dudas por estado (repositories.DudasSinResponder, DudasRespondidas o DudasSinAsignar)
+func (h *ExportacionHandler) PorEstadoDuda(estado string) FiltroRuta {
+	return func(c *fiber.Ctx, filtro *repositories.FiltroExportacion) error {
+		filtro.EstadoDudas = estado
+		return nil
+	}
+}
+
+// idRuta lee un ID positivo del parámetro de la ruta
+func idRuta(c *fiber.Ctx, nombre string) (uint, error) {
+	valor := c.Params(nombre)
+	id, err := strconv.ParseUint(valor, 10, 32)
+	if err != nil || id == 0 {
+		return 0, errores.Validacion(nombre+"_invalido", "El ID no es válido", errores.Campo{
+			Campo:   nombre,
+			Mensaje: "Debe ser un número entero positivo",
+			Valor:   valor,
+		})
+	}
+	return uint(id), nil
+}
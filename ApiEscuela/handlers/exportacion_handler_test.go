@@ -0,0 +1,192 @@
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"ApiEscuela/handlers"
+	"ApiEscuela/models"
+	"ApiEscuela/services"
+	"ApiEscuela/testutil"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestExportacionListas(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+
+		otra := f.Institucion(func(i *models.Institucion) { i.Nombre = "Colegio Técnico Quevedo" })
+		ana := f.Estudiante(f.Persona(func(p *models.Persona) { p.Nombre = "Ana Muñoz"; p.Cedula = "1200000001" }), e.Institucion, e.Ciudad)
+		f.Estudiante(f.Persona(func(p *models.Persona) { p.Nombre = "Luis Peña"; p.Cedula = "1200000002" }), otra, e.Ciudad)
+
+		fecha := time.Date(2030, 5, 14, 9, 30, 0, 0, time.Local)
+		programa := &models.ProgramaVisita{Fecha: fecha, InstitucionID: otra.ID}
+		if err := repos.ProgramaVisita.CreateProgramaVisita(programa); err != nil {
+			t.Fatal(err)
+		}
+		if err := repos.ProgramaVisita.CreateProgramaVisita(&models.ProgramaVisita{Fecha: fecha.AddDate(1, 0, 0), InstitucionID: otra.ID}); err != nil {
+			t.Fatal(err)
+		}
+		universitario := &models.EstudianteUniversitario{PersonaID: f.Persona(func(p *models.Persona) { p.Nombre = "Carla Ruiz" }).ID, Semestre: 6}
+		if err := repos.EstudianteUniversitario.CreateEstudianteUniversitario(universitario); err != nil {
+			t.Fatal(err)
+		}
+		participante := &models.VisitaDetalleEstudiantesUniversitarios{EstudianteUniversitarioID: universitario.ID, ProgramaVisitaID: programa.ID}
+		if err := repos.VisitaDetalleEstudiantesUniversitarios.CreateVisitaDetalleEstudiantesUniversitarios(participante); err != nil {
+			t.Fatal(err)
+		}
+
+		respuesta := "Las visitas son los martes"
+		for _, duda := range []*models.Dudas{
+			{Pregunta: "¿Cuándo son las visitas?", EstudianteID: ana.ID, Privacidad: "publico", FechaPregunta: fecha, Respuesta: &respuesta},
+			{Pregunta: "¿Hay transporte para la visita?", EstudianteID: ana.ID, Privacidad: "publico", FechaPregunta: fecha},
+		} {
+			if err := repos.Dudas.CreateDudas(duda); err != nil {
+				t.Fatal(err)
+			}
+		}
+		f.Comunicado(e.Admin, func(c *models.Comunicado) {
+			c.Asunto = "=HYPERLINK(\"http://example.com\")"
+			c.Mensaje = "<p>Visita <strong>confirmada</strong> &amp; lista</p>"
+		})
+
+		exportar := func(ruta string, headers map[string]string) *testutil.Response {
+			t.Helper()
+			res := testutil.DoHeaders(t, app, http.MethodGet, ruta, headers, e.AdminToken)
+			if res.Status != http.StatusOK {
+				t.Fatalf("GET %s = %d: %s", ruta, res.Status, res.Body)
+			}
+			return res
+		}
+		filasCSV := func(ruta string) [][]string {
+			t.Helper()
+			res := exportar(ruta, nil)
+			if tipo := res.Header.Get("Content-Type"); tipo != "text/csv; charset=utf-8" {
+				t.Errorf("%s: Content-Type = %q", ruta, tipo)
+			}
+			cuerpo, ok := bytes.CutPrefix(res.Body, []byte("\ufeff"))
+			if !ok {
+				t.Errorf("%s: el CSV no empieza con BOM", ruta)
+			}
+			filas, err := csv.NewReader(bytes.NewReader(cuerpo)).ReadAll()
+			if err != nil {
+				t.Fatalf("%s: CSV inválido: %v", ruta, err)
+			}
+			return filas
+		}
+
+		// Todas las columnas, con encabezados en español y el archivo como adjunto
+		res := exportar("/api/estudiantes?format=csv", nil)
+		if disposicion := res.Header.Get("Content-Disposition"); !strings.HasPrefix(disposicion, `attachment; filename="estudiantes-`) || !strings.HasSuffix(disposicion, `.csv"`) {
+			t.Errorf("Content-Disposition = %q", disposicion)
+		}
+		filas := filasCSV("/api/estudiantes?format=csv")
+		if len(filas) != 3 || strings.Join(filas[0][:3], ",") != "ID,Nombre,Cédula" {
+			t.Fatalf("estudiantes = %q", filas)
+		}
+		if filas[1][1] != "Ana Muñoz" || filas[1][6] != "Quevedo" || filas[1][7] != "Los Ríos" {
+			t.Errorf("fila = %q", filas[1])
+		}
+
+		// Columnas elegidas en orden y filtro de la ruta
+		filas = filasCSV(fmt.Sprintf("/api/estudiantes/institucion/%d?format=csv&columnas=cedula,nombre,cedula", otra.ID))
+		if len(filas) != 2 || strings.Join(filas[0], ",") != "Cédula,Nombre" || strings.Join(filas[1], ",") != "1200000002,Luis Peña" {
+			t.Errorf("por institución = %q", filas)
+		}
+
+		// XLSX por el encabezado Accept
+		res = exportar(fmt.Sprintf("/api/estudiantes/ciudad/%d", e.Ciudad.ID), map[string]string{
+			"Accept": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		})
+		libro, err := excelize.OpenReader(bytes.NewReader(res.Body))
+		if err != nil {
+			t.Fatalf("XLSX inválido: %v", err)
+		}
+		hojas := libro.GetSheetList()
+		celdas, err := libro.GetRows(hojas[0])
+		if err != nil || len(celdas) != 3 || celdas[0][1] != "Nombre" || hojas[0] != "Estudiantes" {
+			t.Errorf("XLSX %v = %q (%v)", hojas, celdas, err)
+		}
+		libro.Close()
+
+		// Rango de fechas: antes quedaba tapado por /:id
+		rango := "/api/programas-visita/rango-fecha?inicio=2030-05-01&fin=2030-05-31"
+		if res := exportar(rango+"&format=pdf", nil); !bytes.HasPrefix(res.Body, []byte("%PDF")) || res.Header.Get("Content-Type") != "application/pdf" {
+			t.Errorf("PDF = %s %.20q", res.Header.Get("Content-Type"), res.Body)
+		}
+		filas = filasCSV(rango + "&format=csv&columnas=fecha,institucion")
+		if len(filas) != 2 || filas[1][0] != "14/05/2030 09:30" || filas[1][1] != otra.Nombre {
+			t.Errorf("rango = %q", filas)
+		}
+		var programas []models.ProgramaVisita
+		exportar(rango, nil).JSON(t, &programas)
+		if len(programas) != 1 || programas[0].ID != programa.ID {
+			t.Errorf("rango JSON = %+v", programas)
+		}
+
+		filas = filasCSV(fmt.Sprintf("/api/visita-detalle-estudiantes-universitarios/programa-visita/%d?format=csv&columnas=nombre,semestre,institucion", programa.ID))
+		if len(filas) != 2 || strings.Join(filas[1], ",") != "Carla Ruiz,6,"+otra.Nombre {
+			t.Errorf("participantes = %q", filas)
+		}
+
+		filas = filasCSV("/api/dudas/sin-responder?format=csv&columnas=pregunta,estudiante,respuesta")
+		if len(filas) != 2 || strings.Join(filas[1], "|") != "¿Hay transporte para la visita?|Ana Muñoz|" {
+			t.Errorf("sin responder = %q", filas)
+		}
+		if filas = filasCSV("/api/dudas/respondidas?format=csv&columnas=respuesta"); len(filas) != 2 || filas[1][0] != respuesta {
+			t.Errorf("respondidas = %q", filas)
+		}
+
+		// Sin fórmulas en el CSV y el mensaje sin HTML, también en la versión 2
+		for _, ruta := range []string{"/api/comunicados", "/api/v2/comunicados"} {
+			filas = filasCSV(ruta + "?format=CSV&columnas=asunto,mensaje,remitente")
+			if len(filas) != 2 || strings.Join(filas[1], "|") != `'=HYPERLINK("http://example.com")|Visita confirmada & lista|admin` {
+				t.Errorf("%s = %q", ruta, filas)
+			}
+		}
+
+		// Sin formato de exportación la ruta responde el JSON de siempre
+		res = exportar("/api/estudiantes", map[string]string{"Accept": "application/json, text/csv;q=0.5"})
+		if !strings.HasPrefix(res.Header.Get("Content-Type"), "application/json") || !strings.Contains(res.Header.Get("Vary"), "Accept") {
+			t.Errorf("JSON: %v", res.Header)
+		}
+		if res := exportar("/api/dudas/sin-asignar?format=json", nil); !strings.HasPrefix(res.Header.Get("Content-Type"), "application/json") {
+			t.Errorf("format=json: %v", res.Header)
+		}
+
+		var listas struct {
+			Data handlers.ListasExportables `json:"data"`
+		}
+		exportar("/api/exportaciones", nil).JSON(t, &listas)
+		if len(listas.Data.Listas) != 5 || len(listas.Data.Formatos) != 3 {
+			t.Errorf("exportaciones = %+v", listas.Data)
+		}
+		for _, lista := range listas.Data.Listas {
+			if lista.Nombre == services.ListaDudas && (len(lista.Columnas) == 0 || lista.Columnas[0].Clave != "id") {
+				t.Errorf("columnas de dudas = %+v", lista.Columnas)
+			}
+		}
+
+		for _, ruta := range []string{
+			"/api/estudiantes?format=docx",
+			"/api/estudiantes?format=csv&columnas=nombre,clave",
+			"/api/estudiantes/institucion/cero?format=csv",
+			"/api/programas-visita/rango-fecha?inicio=2030-05-01&format=xlsx",
+		} {
+			if res := testutil.Do(t, app, http.MethodGet, ruta, nil, e.AdminToken); res.Status != http.StatusBadRequest {
+				t.Errorf("GET %s = %d: %s", ruta, res.Status, res.Body)
+			}
+		}
+		if res := testutil.Do(t, app, http.MethodGet, "/api/estudiantes?format=csv", nil, ""); res.Status != http.StatusUnauthorized {
+			t.Errorf("sin token = %d", res.Status)
+		}
+	})
+}
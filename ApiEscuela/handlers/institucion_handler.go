@@ -9,10 +9,10 @@ import (
 )
 
 type InstitucionHandler struct {
-	institucionRepo *repositories.InstitucionRepository
+	institucionRepo repositories.InstitucionRepository
 }
 
-func NewInstitucionHandler(institucionRepo *repositories.InstitucionRepository) *InstitucionHandler {
+func NewInstitucionHandler(institucionRepo repositories.InstitucionRepository) *InstitucionHandler {
 	return &InstitucionHandler{institucionRepo: institucionRepo}
 }
 
@@ -97,6 +97,11 @@ func (h *InstitucionHandler) UpdateInstitucion(c *fiber.Ctx) error {
 	return c.JSON(institucion)
 }
 
+// PatchInstitucion actualiza solo los campos enviados de una institución (JSON Merge Patch)
+func (h *InstitucionHandler) PatchInstitucion(c *fiber.Ctx) error {
+	return mergePatch(c, cargarCon(h.institucionRepo.GetInstitucionByID), h.UpdateInstitucion)
+}
+
 // DeleteInstitucion elimina una institución
 func (h *InstitucionHandler) DeleteInstitucion(c *fiber.Ctx) error {
 	id, err := strconv.Atoi(c.Params("id"))
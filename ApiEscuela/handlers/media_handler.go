@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"ApiEscuela/repositories"
+	"ApiEscuela/services"
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type MediaHandler struct {
+	media services.MediaService
+}
+
+func NewMediaHandler(media services.MediaService) *MediaHandler {
+	return &MediaHandler{media: media}
+}
+
+// ListMedia lista los archivos subidos; admite los filtros q (nombre o clave), categoria,
+// visibilidad, usuario_id y sin_referencias=true
+func (h *MediaHandler) ListMedia(c *fiber.Ctx) error {
+	filtro := repositories.FiltroMedia{
+		Texto:          c.Query("q"),
+		Categoria:      c.Query("categoria"),
+		Visibilidad:    c.Query("visibilidad"),
+		SinReferencias: c.QueryBool("sin_referencias"),
+	}
+	if v := c.Query("usuario_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return SendError(c, 400, "invalid_query", "usuario_id inválido")
+		}
+		filtro.UsuarioID = uint(id)
+	}
+
+	medias, err := h.media.ListarMedia(filtro)
+	if err != nil {
+		return SendError(c, 500, "media_error", "No se pueden obtener los archivos", err.Error())
+	}
+	return c.JSON(medias)
+}
+
+// GetMedia obtiene un archivo subido con las noticias y comunicados que lo usan
+func (h *MediaHandler) GetMedia(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return SendError(c, 400, "invalid_id", "ID de archivo inválido")
+	}
+	media, err := h.media.GetMedia(uint(id))
+	if err != nil {
+		return h.errorMedia(c, err)
+	}
+	return c.JSON(media)
+}
+
+// UpdateVisibilidad hace público o privado un archivo
+func (h *MediaHandler) UpdateVisibilidad(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return SendError(c, 400, "invalid_id", "ID de archivo inválido")
+	}
+	var datos struct {
+		Visibilidad string `json:"visibilidad"`
+	}
+	if err := c.BodyParser(&datos); err != nil {
+		return SendError(c, 400, "invalid_json", "No se puede procesar el JSON")
+	}
+	media, err := h.media.CambiarVisibilidad(uint(id), datos.Visibilidad)
+	if err != nil {
+		return h.errorMedia(c, err)
+	}
+	return c.JSON(media)
+}
+
+// DeleteMedia elimina un archivo y sus variantes. Si lo usan noticias o comunicados responde
+// 409, salvo que se envíe forzar=true.
+func (h *MediaHandler) DeleteMedia(c *fiber.Ctx) error {
+	id, err := strconv.Atoi(c.Params("id"))
+	if err != nil {
+		return SendError(c, 400, "invalid_id", "ID de archivo inválido")
+	}
+	if err := h.media.EliminarArchivo(c.UserContext(), uint(id), c.QueryBool("forzar")); err != nil {
+		return h.errorMedia(c, err)
+	}
+	return c.JSON(fiber.Map{
+		"message": "Archivo eliminado exitosamente",
+	})
+}
+
+// errorMedia traduce los errores de la biblioteca de archivos a respuestas HTTP
+func (h *MediaHandler) errorMedia(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return SendError(c, 404, "not_found", "Archivo no encontrado")
+	case errors.Is(err, services.ErrVisibilidadInvalida):
+		return SendError(c, 400, "invalid_visibility", err.Error())
+	case errors.Is(err, services.ErrMediaEnUso):
+		return SendError(c, 409, "media_in_use", err.Error(), "Use forzar=true para eliminarlo de todos modos")
+	default:
+		return SendError(c, 500, "media_error", "Error en la biblioteca de archivos", err.Error())
+	}
+}
@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"mime"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"ApiEscuela/errores"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// MIMEMergePatch es el tipo de contenido de JSON Merge Patch (RFC 7396)
+const MIMEMergePatch = "application/merge-patch+json"
+
+// Cambio es un campo que modificó un PATCH, con su valor anterior y el nuevo
+type Cambio struct {
+	Campo    string      `json:"campo"`
+	Anterior interface{} `json:"anterior"`
+	Nuevo    interface{} `json:"nuevo"`
+}
+
+// cargador obtiene el registro actual, base del merge patch
+type cargador func(id uint) (interface{}, error)
+
+// cargarCon adapta el GetXByID de un repositorio a cargador
+func cargarCon[T any](get func(id uint) (*T, error)) cargador {
+	return func(id uint) (interface{}, error) {
+		return get(id)
+	}
+}
+
+// mergePatch atiende un PATCH con JSON Merge Patch (RFC 7396): aplica el parche a los campos
+// modificables del registro actual y entrega el objeto completo al handler PUT del recurso, que
+// valida, comprueba If-Match y guarda como siempre. null solo se acepta en los campos con la
+// etiqueta patch:"null", que el PUT vacía; en los demás el PUT conservaría el valor, así que se
+// responde 422. A la respuesta 200 del PUT se agrega "cambios" con los campos que realmente
+// cambiaron; las demás se devuelven sin modificar.
+func mergePatch(c *fiber.Ctx, cargar cargador, put fiber.Handler) error {
+	if tipo, _, err := mime.ParseMediaType(c.Get(fiber.HeaderContentType)); err != nil || (tipo != MIMEMergePatch && tipo != fiber.MIMEApplicationJSON) {
+		return SendError(c, fiber.StatusUnsupportedMediaType, "unsupported_media_type", "El parche debe enviarse como "+MIMEMergePatch)
+	}
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || id == 0 {
+		return SendError(c, 400, "invalid_id", "El ID no es válido", "El ID debe ser un número entero positivo")
+	}
+
+	var parche map[string]interface{}
+	if err := json.Unmarshal(c.Body(), &parche); err != nil || parche == nil {
+		return SendError(c, 400, "invalid_patch", "El parche debe ser un objeto JSON", "Envíe solo los campos que cambian; null vacía los campos opcionales")
+	}
+	actual, err := cargar(uint(id))
+	if err != nil {
+		return SendError(c, 404, "not_found", "No se encontró el registro solicitado", "Verifique que el ID sea correcto")
+	}
+
+	campos := camposModificables(reflect.TypeOf(actual))
+	var invalidos []errores.Campo
+	for campo := range parche {
+		if _, ok := campos[campo]; !ok {
+			invalidos = append(invalidos, errores.Campo{Campo: campo, Mensaje: "El campo no existe o no se puede modificar"})
+		}
+	}
+	if len(invalidos) > 0 {
+		sort.Slice(invalidos, func(i, j int) bool { return invalidos[i].Campo < invalidos[j].Campo })
+		return errores.Validacion("campos_no_modificables", "El parche incluye campos que no se pueden modificar", invalidos...)
+	}
+	var noVaciables []errores.Campo
+	for campo, valor := range parche {
+		if valor == nil && campos[campo].Tag.Get("patch") != "null" {
+			noVaciables = append(noVaciables, errores.Campo{Campo: campo, Mensaje: "El campo no se puede vaciar; envíe un valor"})
+		}
+	}
+	if len(noVaciables) > 0 {
+		sort.Slice(noVaciables, func(i, j int) bool { return noVaciables[i].Campo < noVaciables[j].Campo })
+		return errores.NoProcesable("campos_no_vaciables", "El parche vacía campos que no se pueden vaciar", noVaciables...)
+	}
+
+	antes, err := representacion(actual, campos)
+	if err != nil {
+		return err
+	}
+	// Un miembro eliminado por el parche se envía al PUT con el valor vacío de su tipo
+	nuevo := aplicarMergePatch(copiarJSON(antes), parche)
+	for campo, valor := range parche {
+		if valor == nil {
+			nuevo[campo] = reflect.Zero(campos[campo].Type).Interface()
+		}
+	}
+	cuerpo, err := json.Marshal(nuevo)
+	if err != nil {
+		return err
+	}
+	c.Request().SetBody(cuerpo)
+	c.Request().Header.SetContentType(fiber.MIMEApplicationJSON)
+	if err := put(c); err != nil {
+		return err
+	}
+	// Solo un 200 trae el registro guardado. Cualquier otra respuesta del PUT (400, 404, 412,
+	// 428 o un 2xx/3xx sin el registro) se devuelve tal cual, sin "cambios"
+	if c.Response().StatusCode() != fiber.StatusOK {
+		return nil
+	}
+
+	// El cambio se calcula sobre lo guardado, que incluye la normalización del PUT (espacios, etc.)
+	guardado, err := cargar(uint(id))
+	if err != nil {
+		return nil
+	}
+	despues, err := representacion(guardado, campos)
+	if err != nil {
+		return nil
+	}
+	cambios := diferencias(antes, despues)
+	if len(cambios) > 0 {
+		nombres := make([]string, len(cambios))
+		for i, cambio := range cambios {
+			nombres[i] = cambio.Campo
+		}
+		log.Printf("PATCH %s por el usuario %v: %s", c.Path(), c.Locals("user_id"), strings.Join(nombres, ", "))
+	}
+	return agregarCambios(c, cambios)
+}
+
+// aplicarMergePatch aplica el parche al objeto según RFC 7396 §2: null elimina el miembro y los
+// objetos se combinan recursivamente; cualquier otro valor reemplaza al anterior
+func aplicarMergePatch(objetivo map[string]interface{}, parche map[string]interface{}) map[string]interface{} {
+	if objetivo == nil {
+		objetivo = map[string]interface{}{}
+	}
+	for clave, valor := range parche {
+		if valor == nil {
+			delete(objetivo, clave)
+			continue
+		}
+		if sub, ok := valor.(map[string]interface{}); ok {
+			anterior, _ := objetivo[clave].(map[string]interface{})
+			objetivo[clave] = aplicarMergePatch(anterior, sub)
+			continue
+		}
+		objetivo[clave] = valor
+	}
+	return objetivo
+}
+
+// camposModificables devuelve, por nombre JSON, los campos del modelo que un PATCH puede cambiar:
+// excluye gorm.Model, las relaciones y los campos con la etiqueta patch:"-"
+func camposModificables(t reflect.Type) map[string]reflect.StructField {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	campos := map[string]reflect.StructField{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() || f.Type == reflect.TypeOf(gorm.Model{}) || f.Tag.Get("patch") == "-" || esRelacion(f.Type) {
+			continue
+		}
+		nombre, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if nombre == "-" {
+			continue
+		}
+		if nombre == "" {
+			nombre = f.Name
+		}
+		campos[nombre] = f
+	}
+	return campos
+}
+
+// esRelacion indica si el tipo es otro modelo (o una lista de modelos) cargado por GORM
+func esRelacion(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	campo, ok := t.FieldByName("Model")
+	return ok && campo.Anonymous && campo.Type == reflect.TypeOf(gorm.Model{})
+}
+
+// representacion es el JSON del registro reducido a los campos modificables
+func representacion(registro interface{}, campos map[string]reflect.StructField) (map[string]interface{}, error) {
+	datos, err := json.Marshal(registro)
+	if err != nil {
+		return nil, err
+	}
+	var todo map[string]interface{}
+	if err := json.Unmarshal(datos, &todo); err != nil {
+		return nil, err
+	}
+	for clave := range todo {
+		if _, ok := campos[clave]; !ok {
+			delete(todo, clave)
+		}
+	}
+	return todo, nil
+}
+
+// copiarJSON copia el primer nivel de un objeto JSON; aplicarMergePatch crea los niveles internos
+func copiarJSON(m map[string]interface{}) map[string]interface{} {
+	copia := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		copia[k] = v
+	}
+	return copia
+}
+
+// diferencias lista, ordenados por nombre, los campos cuyo valor cambió
+func diferencias(antes, despues map[string]interface{}) []Cambio {
+	claves := map[string]bool{}
+	for k := range antes {
+		claves[k] = true
+	}
+	for k := range despues {
+		claves[k] = true
+	}
+	cambios := []Cambio{}
+	for k := range claves {
+		if !reflect.DeepEqual(antes[k], despues[k]) {
+			cambios = append(cambios, Cambio{Campo: k, Anterior: antes[k], Nuevo: despues[k]})
+		}
+	}
+	sort.Slice(cambios, func(i, j int) bool { return cambios[i].Campo < cambios[j].Campo })
+	return cambios
+}
+
+// agregarCambios agrega "cambios" al objeto JSON que respondió el handler PUT
+func agregarCambios(c *fiber.Ctx, cambios []Cambio) error {
+	var respuesta map[string]json.RawMessage
+	if err := json.Unmarshal(c.Response().Body(), &respuesta); err != nil || respuesta == nil {
+		return nil
+	}
+	datos, err := json.Marshal(cambios)
+	if err != nil {
+		return err
+	}
+	respuesta["cambios"] = datos
+	return c.JSON(respuesta)
+}
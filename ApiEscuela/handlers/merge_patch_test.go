@@ -0,0 +1,175 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"ApiEscuela/testutil"
+)
+
+func TestMergePatchPersona(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+		persona := f.Persona()
+		ruta := fmt.Sprintf("/api/personas/%d", persona.ID)
+		parchar := func(parche string, etag string) *testutil.Response {
+			t.Helper()
+			headers := map[string]string{"Content-Type": "application/merge-patch+json", "If-Match": etag}
+			return testutil.DoRaw(t, app, http.MethodPatch, ruta, headers, []byte(parche), e.AdminToken)
+		}
+		etag := testutil.Do(t, app, http.MethodGet, ruta, nil, e.AdminToken).Header.Get("ETag")
+
+		// Solo cambia el teléfono; el resto se conserva y la respuesta trae el cambio
+		res := parchar(`{"telefono": "0991234567"}`, etag)
+		if res.Status != http.StatusOK {
+			t.Fatalf("PATCH = %d: %s", res.Status, res.Body)
+		}
+		var respuesta struct {
+			Data struct {
+				Nombre   string  `json:"nombre"`
+				Cedula   string  `json:"cedula"`
+				Correo   *string `json:"correo"`
+				Telefono *string `json:"telefono"`
+			} `json:"data"`
+			Cambios []struct {
+				Campo    string      `json:"campo"`
+				Anterior interface{} `json:"anterior"`
+				Nuevo    interface{} `json:"nuevo"`
+			} `json:"cambios"`
+		}
+		res.JSON(t, &respuesta)
+		if d := respuesta.Data; d.Nombre != persona.Nombre || d.Cedula != persona.Cedula || d.Correo == nil || d.Telefono == nil {
+			t.Errorf("PATCH no conservó los demás campos: %s", res.Body)
+		}
+		if len(respuesta.Cambios) != 1 || respuesta.Cambios[0].Campo != "telefono" || respuesta.Cambios[0].Anterior != nil {
+			t.Errorf("cambios = %+v", respuesta.Cambios)
+		}
+
+		// null elimina el correo, permitido porque ya hay teléfono
+		res = parchar(`{"correo": null}`, res.Header.Get("ETag"))
+		if res.Status != http.StatusOK {
+			t.Fatalf("PATCH correo null = %d: %s", res.Status, res.Body)
+		}
+		guardada, err := repos.Persona.GetPersonaByID(persona.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if guardada.Correo != nil && *guardada.Correo != "" {
+			t.Errorf("correo = %q, se esperaba vacío", *guardada.Correo)
+		}
+
+		// El parche pasa por las validaciones y la concurrencia del PUT
+		if res := parchar(`{"nombre": "X"}`, res.Header.Get("ETag")); res.Status != http.StatusBadRequest {
+			t.Errorf("PATCH con nombre inválido = %d: %s", res.Status, res.Body)
+		}
+		if res := parchar(`{"nombre": "Otro Nombre"}`, etag); res.Status != http.StatusPreconditionFailed {
+			t.Errorf("PATCH con versión vieja = %d, se esperaba 412", res.Status)
+		}
+	})
+}
+
+func TestMergePatchRechazos(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+		ruta := fmt.Sprintf("/api/instituciones/%d", e.Institucion.ID)
+		cabeceras := map[string]string{"Content-Type": "application/merge-patch+json"}
+
+		res := testutil.DoRaw(t, app, http.MethodPatch, ruta, cabeceras, []byte(`{"id": 99, "estudiantes": [], "desconocido": 1}`), e.AdminToken)
+		if res.Status != http.StatusBadRequest {
+			t.Fatalf("PATCH con campos no modificables = %d: %s", res.Status, res.Body)
+		}
+		validacion, _ := res.Map(t)["validation"].([]interface{})
+		if len(validacion) != 3 {
+			t.Errorf("campos de validación = %s", res.Body)
+		}
+
+		texto := map[string]string{"Content-Type": "text/plain"}
+		if res := testutil.DoRaw(t, app, http.MethodPatch, ruta, texto, []byte(`{"nombre": "Nueva"}`), e.AdminToken); res.Status != http.StatusUnsupportedMediaType {
+			t.Errorf("PATCH como text/plain = %d, se esperaba 415", res.Status)
+		}
+		if res := testutil.DoRaw(t, app, http.MethodPatch, ruta, cabeceras, []byte(`["nombre"]`), e.AdminToken); res.Status != http.StatusBadRequest {
+			t.Errorf("PATCH con un arreglo = %d, se esperaba 400", res.Status)
+		}
+		if res := testutil.DoRaw(t, app, http.MethodPatch, "/api/instituciones/999999", cabeceras, []byte(`{"nombre": "Nueva"}`), e.AdminToken); res.Status != http.StatusNotFound {
+			t.Errorf("PATCH de una institución inexistente = %d, se esperaba 404", res.Status)
+		}
+	})
+}
+
+func TestMergePatchSinCambios(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+		ruta := fmt.Sprintf("/api/instituciones/%d", e.Institucion.ID)
+		headers := map[string]string{"Content-Type": "application/json"}
+
+		parche, _ := json.Marshal(map[string]string{"nombre": e.Institucion.Nombre})
+		res := testutil.DoRaw(t, app, http.MethodPatch, ruta, headers, parche, e.AdminToken)
+		if res.Status != http.StatusOK {
+			t.Fatalf("PATCH = %d: %s", res.Status, res.Body)
+		}
+		if cambios, ok := res.Map(t)["cambios"].([]interface{}); !ok || len(cambios) != 0 {
+			t.Errorf("cambios = %v, se esperaba una lista vacía", res.Map(t)["cambios"])
+		}
+	})
+}
+
+func TestMergePatchNull(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+		cabeceras := map[string]string{"Content-Type": "application/merge-patch+json"}
+		parchar := func(ruta, parche string) *testutil.Response {
+			t.Helper()
+			return testutil.DoRaw(t, app, http.MethodPatch, ruta, cabeceras, []byte(parche), e.AdminToken)
+		}
+
+		// El PUT de usuarios conserva el nombre de usuario vacío: null no puede eliminarlo
+		res := parchar(fmt.Sprintf("/api/usuarios/%d", e.Admin.ID), `{"usuario": null}`)
+		if res.Status != http.StatusUnprocessableEntity || res.Map(t)["error"] != "campos_no_vaciables" {
+			t.Fatalf("PATCH usuario null = %d: %s", res.Status, res.Body)
+		}
+		if validacion, _ := res.Map(t)["validation"].([]interface{}); len(validacion) != 1 {
+			t.Errorf("campos de validación = %s", res.Body)
+		}
+		if usuario, err := repos.Usuario.GetUsuarioByID(e.Admin.ID); err != nil || usuario.Usuario != e.Admin.Usuario {
+			t.Errorf("el usuario cambió: %+v, %v", usuario, err)
+		}
+
+		// En los webhooks null vacía la descripción y se rechaza en activo
+		res = testutil.Do(t, app, http.MethodPost, "/api/webhooks", map[string]interface{}{
+			"url": "https://example.com/hook", "eventos": []string{"duda.answered"}, "descripcion": "Sistema académico",
+		}, e.AdminToken)
+		var creado struct {
+			Data struct {
+				ID uint `json:"id"`
+			} `json:"data"`
+		}
+		res.JSON(t, &creado)
+		if res.Status != http.StatusCreated || creado.Data.ID == 0 {
+			t.Fatalf("crear webhook = %d: %s", res.Status, res.Body)
+		}
+		ruta := fmt.Sprintf("/api/webhooks/%d", creado.Data.ID)
+		if res := parchar(ruta, `{"descripcion": null, "activo": null}`); res.Status != http.StatusUnprocessableEntity {
+			t.Errorf("PATCH activo null = %d: %s", res.Status, res.Body)
+		}
+		if res := parchar(ruta, `{"descripcion": null}`); res.Status != http.StatusOK {
+			t.Fatalf("PATCH descripcion null = %d: %s", res.Status, res.Body)
+		}
+		webhook, err := repos.Webhook.GetWebhookByID(creado.Data.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if webhook.Descripcion != "" || !webhook.Activo || webhook.URL != "https://example.com/hook" {
+			t.Errorf("webhook guardado = %+v", webhook)
+		}
+	})
+}
@@ -3,17 +3,28 @@ package handlers
 import (
 	"ApiEscuela/models"
 	"ApiEscuela/repositories"
+	"ApiEscuela/services"
+	"log"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 type NoticiaHandler struct {
-	noticiaRepo *repositories.NoticiaRepository
+	noticiaRepo repositories.NoticiaRepository
+	media       services.MediaService
 }
 
-func NewNoticiaHandler(noticiaRepo *repositories.NoticiaRepository) *NoticiaHandler {
-	return &NoticiaHandler{noticiaRepo: noticiaRepo}
+func NewNoticiaHandler(noticiaRepo repositories.NoticiaRepository, media services.MediaService) *NoticiaHandler {
+	return &NoticiaHandler{noticiaRepo: noticiaRepo, media: media}
+}
+
+// actualizarArchivos registra en la biblioteca los archivos que usa la noticia; si falla, la
+// recolección de huérfanos reconstruye las referencias antes de eliminar
+func (h *NoticiaHandler) actualizarArchivos(id uint, claves []string) {
+	if err := h.media.ActualizarReferencias(services.EntidadNoticia, id, claves); err != nil {
+		log.Printf("Error al actualizar los archivos de la noticia %d: %v", id, err)
+	}
 }
 
 // CreateNoticia crea una nueva noticia
@@ -31,6 +42,7 @@ func (h *NoticiaHandler) CreateNoticia(c *fiber.Ctx) error {
 			"error": "No se puede crear la noticia",
 		})
 	}
+	h.actualizarArchivos(noticia.ID, services.ClavesNoticia(&noticia))
 
 	return c.Status(fiber.StatusCreated).JSON(noticia)
 }
@@ -93,10 +105,16 @@ func (h *NoticiaHandler) UpdateNoticia(c *fiber.Ctx) error {
 			"error": "No se puede actualizar la noticia",
 		})
 	}
+	h.actualizarArchivos(noticia.ID, services.ClavesNoticia(noticia))
 
 	return c.JSON(noticia)
 }
 
+// PatchNoticia actualiza solo los campos enviados de una noticia (JSON Merge Patch)
+func (h *NoticiaHandler) PatchNoticia(c *fiber.Ctx) error {
+	return mergePatch(c, cargarCon(h.noticiaRepo.GetNoticiaByID), h.UpdateNoticia)
+}
+
 // DeleteNoticia elimina una noticia
 func (h *NoticiaHandler) DeleteNoticia(c *fiber.Ctx) error {
 	id, err := strconv.Atoi(c.Params("id"))
@@ -111,6 +129,7 @@ func (h *NoticiaHandler) DeleteNoticia(c *fiber.Ctx) error {
 			"error": "No se puede eliminar la noticia",
 		})
 	}
+	h.actualizarArchivos(uint(id), nil)
 
 	return c.JSON(fiber.Map{
 		"message": "Noticia eliminada exitosamente",
@@ -164,9 +183,23 @@ func (h *NoticiaHandler) GetNoticiasByDescripcion(c *fiber.Ctx) error {
 	return c.JSON(noticias)
 }
 
-// SearchNoticias busca noticias por título o descripción
+// SearchNoticias busca noticias por título o descripción sin distinguir tildes; el título pesa más
 func (h *NoticiaHandler) SearchNoticias(c *fiber.Ctx) error {
-	termino := c.Params("termino")
+	termino := paramBusqueda(c, "termino")
+
+	noticias, err := h.noticiaRepo.SearchNoticias(termino)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "No se pueden buscar las noticias",
+		})
+	}
+
+	return c.JSON(repositories.Registros(noticias))
+}
+
+// SearchNoticiasV2 busca noticias por título o descripción con la relevancia y un fragmento resaltado (respuesta v2)
+func (h *NoticiaHandler) SearchNoticiasV2(c *fiber.Ctx) error {
+	termino := paramBusqueda(c, "termino")
 
 	noticias, err := h.noticiaRepo.SearchNoticias(termino)
 	if err != nil {
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"ApiEscuela/services"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Límites de GET /api/notificaciones
+const (
+	limiteNotificaciones       = 50
+	limiteNotificacionesMaximo = 200
+)
+
+type NotificacionHandler struct {
+	notificacionService services.NotificacionService
+}
+
+func NewNotificacionHandler(notificacionService services.NotificacionService) *NotificacionHandler {
+	return &NotificacionHandler{notificacionService: notificacionService}
+}
+
+// PreferenciasRequest son los canales a cambiar; los campos omitidos conservan su valor
+type PreferenciasRequest struct {
+	App      *bool `json:"app"`
+	Correo   *bool `json:"correo"`
+	WhatsApp *bool `json:"whatsapp"`
+}
+
+// GetNotificaciones lista las notificaciones del usuario autenticado, las más recientes primero
+func (h *NotificacionHandler) GetNotificaciones(c *fiber.Ctx) error {
+	usuarioID, _ := c.Locals("user_id").(uint)
+	limite := limiteNotificaciones
+	if valor := c.Query("limite"); valor != "" {
+		n, err := strconv.Atoi(valor)
+		if err != nil || n < 1 || n > limiteNotificacionesMaximo {
+			return SendValidationError(c, "El límite no es válido", []ValidationError{{
+				Field:   "limite",
+				Message: "Debe ser un número entre 1 y " + strconv.Itoa(limiteNotificacionesMaximo),
+				Value:   valor,
+			}})
+		}
+		limite = n
+	}
+	notificaciones, err := h.notificacionService.GetNotificaciones(usuarioID, c.QueryBool("no_leidas"), limite)
+	if err != nil {
+		return SendError(c, 500, "error_base_datos", "Error interno del servidor", "No se pudieron obtener las notificaciones")
+	}
+	return SendSuccess(c, 200, notificaciones)
+}
+
+// GetNoLeidas devuelve cuántas notificaciones sin leer tiene el usuario autenticado
+func (h *NotificacionHandler) GetNoLeidas(c *fiber.Ctx) error {
+	usuarioID, _ := c.Locals("user_id").(uint)
+	total, err := h.notificacionService.ContarNoLeidas(usuarioID)
+	if err != nil {
+		return SendError(c, 500, "error_base_datos", "Error interno del servidor", "No se pudieron contar las notificaciones")
+	}
+	return SendSuccess(c, 200, fiber.Map{"no_leidas": total})
+}
+
+// MarcarLeida marca como leída una notificación del usuario autenticado
+func (h *NotificacionHandler) MarcarLeida(c *fiber.Ctx) error {
+	usuarioID, _ := c.Locals("user_id").(uint)
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || id == 0 {
+		return SendError(c, 400, "id_invalido", "El ID de la notificación no es válido", "El ID debe ser un número entero positivo")
+	}
+	notificacion, err := h.notificacionService.MarcarLeida(usuarioID, uint(id))
+	if err != nil {
+		return err
+	}
+	return SendSuccess(c, 200, notificacion)
+}
+
+// MarcarTodasLeidas marca como leídas todas las notificaciones del usuario autenticado
+func (h *NotificacionHandler) MarcarTodasLeidas(c *fiber.Ctx) error {
+	usuarioID, _ := c.Locals("user_id").(uint)
+	total, err := h.notificacionService.MarcarTodasLeidas(usuarioID)
+	if err != nil {
+		return SendError(c, 500, "error_base_datos", "Error interno del servidor", "No se pudieron marcar las notificaciones")
+	}
+	return SendSuccess(c, 200, fiber.Map{
+		"message":  "Notificaciones marcadas como leídas",
+		"marcadas": total,
+	})
+}
+
+// GetPreferencias devuelve los canales por los que el usuario autenticado recibe sus notificaciones
+func (h *NotificacionHandler) GetPreferencias(c *fiber.Ctx) error {
+	usuarioID, _ := c.Locals("user_id").(uint)
+	preferencias, err := h.notificacionService.GetPreferencias(usuarioID)
+	if err != nil {
+		return SendError(c, 500, "error_base_datos", "Error interno del servidor", "No se pudieron obtener las preferencias")
+	}
+	return SendSuccess(c, 200, preferencias)
+}
+
+// UpdatePreferencias cambia los canales por los que el usuario autenticado recibe sus notificaciones
+func (h *NotificacionHandler) UpdatePreferencias(c *fiber.Ctx) error {
+	usuarioID, _ := c.Locals("user_id").(uint)
+	var req PreferenciasRequest
+	if err := c.BodyParser(&req); err != nil {
+		return SendError(c, 400, "json_invalido", "No se puede procesar el JSON. Verifique el formato de los datos", err.Error())
+	}
+	preferencias, err := h.notificacionService.GetPreferencias(usuarioID)
+	if err != nil {
+		return SendError(c, 500, "error_base_datos", "Error interno del servidor", "No se pudieron obtener las preferencias")
+	}
+	if req.App != nil {
+		preferencias.App = *req.App
+	}
+	if req.Correo != nil {
+		preferencias.Correo = *req.Correo
+	}
+	if req.WhatsApp != nil {
+		preferencias.WhatsApp = *req.WhatsApp
+	}
+	if err := h.notificacionService.GuardarPreferencias(preferencias); err != nil {
+		return SendError(c, 500, "error_base_datos", "Error interno del servidor", "No se pudieron guardar las preferencias")
+	}
+	return SendSuccess(c, 200, preferencias)
+}
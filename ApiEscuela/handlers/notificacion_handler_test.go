@@ -0,0 +1,120 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"ApiEscuela/models"
+	"ApiEscuela/services"
+	"ApiEscuela/testutil"
+)
+
+func TestCentroDeNotificaciones(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+
+		programa := &models.ProgramaVisita{Fecha: time.Date(2026, 11, 3, 9, 0, 0, 0, time.Local), InstitucionID: e.Institucion.ID}
+		if err := repos.ProgramaVisita.CreateProgramaVisita(programa); err != nil {
+			t.Fatal(err)
+		}
+		tipoAutoridad := f.TipoUsuario("Autoridad")
+		persona := f.Persona()
+		usuario := f.Usuario(persona, tipoAutoridad, "clave123")
+		token := testutil.Token(t, usuario)
+		autoridad := &models.AutoridadUTEQ{PersonaID: persona.ID, Cargo: "Decano"}
+		if err := repos.AutoridadUTEQ.CreateAutoridadUTEQ(autoridad); err != nil {
+			t.Fatal(err)
+		}
+		// La misma persona también es estudiante universitario
+		universitario := &models.EstudianteUniversitario{PersonaID: persona.ID, Semestre: 5}
+		if err := repos.EstudianteUniversitario.CreateEstudianteUniversitario(universitario); err != nil {
+			t.Fatal(err)
+		}
+
+		if res := testutil.Do(t, app, http.MethodPost, "/api/detalle-autoridad-detalles-visita", map[string]interface{}{
+			"programa_visita_id": programa.ID, "autoridad_uteq_id": autoridad.ID,
+		}, e.AdminToken); res.Status != http.StatusCreated {
+			t.Fatalf("asignar autoridad = %d: %s", res.Status, res.Body)
+		}
+		if res := testutil.Do(t, app, http.MethodPost, "/api/visita-detalle-estudiantes-universitarios", map[string]interface{}{
+			"programa_visita_id": programa.ID, "estudiante_universitario_id": universitario.ID,
+		}, e.AdminToken); res.Status != http.StatusCreated {
+			t.Fatalf("inscribir estudiante = %d: %s", res.Status, res.Body)
+		}
+
+		var lista struct {
+			Data []models.Notificacion `json:"data"`
+		}
+		res := testutil.Do(t, app, http.MethodGet, "/api/notificaciones", nil, token)
+		if res.Status != http.StatusOK {
+			t.Fatalf("GET /api/notificaciones = %d: %s", res.Status, res.Body)
+		}
+		res.JSON(t, &lista)
+		if len(lista.Data) != 2 || lista.Data[0].Tipo != services.NotificacionInscripcionVisita || lista.Data[1].Tipo != services.NotificacionAutoridadAsignada {
+			t.Fatalf("notificaciones = %+v", lista.Data)
+		}
+		if lista.Data[1].RecursoID != programa.ID || lista.Data[1].Mensaje != "Fuiste asignado a la visita de "+e.Institucion.Nombre+" del 03/11/2026 09:00" {
+			t.Errorf("notificación de asignación inesperada: %+v", lista.Data[1])
+		}
+		// Cada usuario ve solo las suyas
+		if res := testutil.Do(t, app, http.MethodGet, "/api/notificaciones", nil, e.AdminToken); len(res.Map(t)["data"].([]interface{})) != 0 {
+			t.Errorf("el administrador ve notificaciones ajenas: %s", res.Body)
+		}
+
+		primera := lista.Data[1].ID
+		if res := testutil.Do(t, app, http.MethodPut, fmt.Sprintf("/api/notificaciones/%d/leida", primera), nil, e.AdminToken); res.Status != http.StatusNotFound {
+			t.Errorf("marcar una notificación ajena = %d, se esperaba 404", res.Status)
+		}
+		res = testutil.Do(t, app, http.MethodPut, fmt.Sprintf("/api/notificaciones/%d/leida", primera), nil, token)
+		if res.Status != http.StatusOK || res.Map(t)["data"].(map[string]interface{})["leida"] != true {
+			t.Fatalf("marcar leída = %d: %s", res.Status, res.Body)
+		}
+		res = testutil.Do(t, app, http.MethodGet, "/api/notificaciones?no_leidas=true", nil, token)
+		if res.JSON(t, &lista); len(lista.Data) != 1 || lista.Data[0].ID == primera {
+			t.Errorf("no leídas = %+v", lista.Data)
+		}
+		if res := testutil.Do(t, app, http.MethodGet, "/api/notificaciones?limite=0", nil, token); res.Status != http.StatusBadRequest {
+			t.Errorf("limite=0 = %d, se esperaba 400", res.Status)
+		}
+
+		res = testutil.Do(t, app, http.MethodPut, "/api/notificaciones/leidas", nil, token)
+		if res.Status != http.StatusOK || res.Map(t)["data"].(map[string]interface{})["marcadas"] != float64(1) {
+			t.Errorf("marcar todas = %d: %s", res.Status, res.Body)
+		}
+		res = testutil.Do(t, app, http.MethodGet, "/api/notificaciones/no-leidas", nil, token)
+		if res.Map(t)["data"].(map[string]interface{})["no_leidas"] != float64(0) {
+			t.Errorf("no leídas tras marcar todas: %s", res.Body)
+		}
+	})
+}
+
+func TestPreferenciasDeNotificacion(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+
+		var preferencias struct {
+			Data models.PreferenciaNotificacion `json:"data"`
+		}
+		res := testutil.Do(t, app, http.MethodGet, "/api/notificaciones/preferencias", nil, e.AdminToken)
+		if res.JSON(t, &preferencias); res.Status != http.StatusOK || !preferencias.Data.App || preferencias.Data.Correo || preferencias.Data.WhatsApp {
+			t.Fatalf("preferencias por defecto = %d: %s", res.Status, res.Body)
+		}
+
+		// Los campos omitidos conservan su valor
+		res = testutil.Do(t, app, http.MethodPut, "/api/notificaciones/preferencias", map[string]bool{"correo": true}, e.AdminToken)
+		if res.JSON(t, &preferencias); res.Status != http.StatusOK || !preferencias.Data.App || !preferencias.Data.Correo {
+			t.Fatalf("PUT preferencias = %d: %s", res.Status, res.Body)
+		}
+		testutil.Do(t, app, http.MethodPut, "/api/notificaciones/preferencias", map[string]bool{"app": false, "whatsapp": true}, e.AdminToken)
+		res = testutil.Do(t, app, http.MethodGet, "/api/notificaciones/preferencias", nil, e.AdminToken)
+		if res.JSON(t, &preferencias); preferencias.Data.App || !preferencias.Data.Correo || !preferencias.Data.WhatsApp || preferencias.Data.UsuarioID != e.Admin.ID {
+			t.Errorf("preferencias guardadas = %s", res.Body)
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"ApiEscuela/services"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type PapeleraHandler struct {
+	papeleraService services.PapeleraService
+}
+
+func NewPapeleraHandler(papeleraService services.PapeleraService) *PapeleraHandler {
+	return &PapeleraHandler{papeleraService: papeleraService}
+}
+
+// GetPapelera devuelve cuántos registros hay en la papelera de cada entidad
+func (h *PapeleraHandler) GetPapelera(c *fiber.Ctx) error {
+	resumen, err := h.papeleraService.Resumen()
+	if err != nil {
+		return err
+	}
+	return c.JSON(resumen)
+}
+
+// GetEliminados lista los registros eliminados de una entidad, los más recientes primero
+func (h *PapeleraHandler) GetEliminados(c *fiber.Ctx) error {
+	elementos, err := h.papeleraService.GetEliminados(c.Params("entidad"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(elementos)
+}
+
+// RestaurarElemento restaura un registro de la papelera y los que dependen de él
+func (h *PapeleraHandler) RestaurarElemento(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || id == 0 {
+		return SendError(c, 400, "id_invalido", "El ID del registro no es válido", "El ID debe ser un número entero positivo")
+	}
+	if err := h.papeleraService.Restaurar(c.Params("entidad"), uint(id)); err != nil {
+		return err
+	}
+	return SendSuccess(c, 200, fiber.Map{
+		"message": "Registro restaurado exitosamente",
+		"entidad": c.Params("entidad"),
+		"id":      id,
+	})
+}
+
+// PurgarElemento elimina definitivamente un registro de la papelera si nada lo usa
+func (h *PapeleraHandler) PurgarElemento(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || id == 0 {
+		return SendError(c, 400, "id_invalido", "El ID del registro no es válido", "El ID debe ser un número entero positivo")
+	}
+	if err := h.papeleraService.Purgar(c.Params("entidad"), uint(id)); err != nil {
+		return err
+	}
+	return SendSuccess(c, 200, fiber.Map{
+		"message": "Registro eliminado definitivamente",
+		"entidad": c.Params("entidad"),
+		"id":      id,
+	})
+}
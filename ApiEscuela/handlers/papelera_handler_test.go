@@ -0,0 +1,137 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"ApiEscuela/models"
+	"ApiEscuela/testutil"
+)
+
+func TestPapeleraHTTP(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+
+		estudiante := f.Usuario(f.Persona(), e.TipoEstudiante, "secreto1")
+		if res := testutil.Do(t, app, http.MethodGet, "/api/papelera", nil, testutil.Token(t, estudiante)); res.Status != http.StatusForbidden {
+			t.Errorf("papelera con Estudiante = %d, se esperaba 403", res.Status)
+		}
+		if res := testutil.Do(t, app, http.MethodGet, "/api/papelera/no-existe", nil, e.AdminToken); res.Status != http.StatusNotFound {
+			t.Errorf("entidad desconocida = %d, se esperaba 404", res.Status)
+		}
+
+		// Una institución eliminada aparece en la papelera y se restaura
+		institucion := f.Institucion()
+		if err := repos.Institucion.DeleteInstitucion(institucion.ID); err != nil {
+			t.Fatal(err)
+		}
+		var eliminados []struct {
+			Entidad  string                 `json:"entidad"`
+			ID       uint                   `json:"id"`
+			Registro map[string]interface{} `json:"registro"`
+		}
+		res := testutil.Do(t, app, http.MethodGet, "/api/papelera/instituciones", nil, e.AdminToken)
+		if res.Status != http.StatusOK {
+			t.Fatalf("eliminados = %d: %s", res.Status, res.Body)
+		}
+		res.JSON(t, &eliminados)
+		if len(eliminados) != 1 || eliminados[0].ID != institucion.ID || eliminados[0].Registro["nombre"] != institucion.Nombre {
+			t.Fatalf("eliminados inesperados: %s", res.Body)
+		}
+		var resumen []struct {
+			Entidad  string `json:"entidad"`
+			Cantidad int64  `json:"cantidad"`
+		}
+		testutil.Do(t, app, http.MethodGet, "/api/papelera", nil, e.AdminToken).JSON(t, &resumen)
+		for _, r := range resumen {
+			if (r.Entidad == "instituciones") != (r.Cantidad == 1) {
+				t.Errorf("resumen inesperado: %+v", resumen)
+				break
+			}
+		}
+
+		ruta := fmt.Sprintf("/api/papelera/instituciones/%d", institucion.ID)
+		if res := testutil.Do(t, app, http.MethodPut, ruta+"/restore", nil, e.AdminToken); res.Status != http.StatusOK {
+			t.Fatalf("restaurar = %d: %s", res.Status, res.Body)
+		}
+		if _, err := repos.Institucion.GetInstitucionByID(institucion.ID); err != nil {
+			t.Errorf("la institución no se restauró: %v", err)
+		}
+		if res := testutil.Do(t, app, http.MethodPut, ruta+"/restore", nil, e.AdminToken); res.Status != http.StatusNotFound {
+			t.Errorf("restaurar un registro activo = %d, se esperaba 404", res.Status)
+		}
+
+		// Una ciudad no se restaura mientras su provincia siga en la papelera
+		provincia := f.Provincia("Guayas")
+		ciudad := f.Ciudad(provincia, "Milagro")
+		if err := repos.Ciudad.DeleteCiudad(ciudad.ID); err != nil {
+			t.Fatal(err)
+		}
+		if err := repos.Provincia.DeleteProvincia(provincia.ID); err != nil {
+			t.Fatal(err)
+		}
+		res = testutil.Do(t, app, http.MethodPut, fmt.Sprintf("/api/papelera/ciudades/%d/restore", ciudad.ID), nil, e.AdminToken)
+		if res.Status != http.StatusConflict || res.Map(t)["error"] != "papelera_padre_eliminado" {
+			t.Errorf("restaurar ciudad con provincia eliminada = %d: %s", res.Status, res.Body)
+		}
+
+		// La provincia no se purga mientras la ciudad, aunque eliminada, la use
+		rutaProvincia := fmt.Sprintf("/api/papelera/provincias/%d", provincia.ID)
+		res = testutil.Do(t, app, http.MethodDelete, rutaProvincia, nil, e.AdminToken)
+		if res.Status != http.StatusConflict || res.Map(t)["error"] != "papelera_dependencias" {
+			t.Errorf("purgar provincia con ciudades = %d: %s", res.Status, res.Body)
+		}
+		if res := testutil.Do(t, app, http.MethodDelete, fmt.Sprintf("/api/papelera/ciudades/%d", ciudad.ID), nil, e.AdminToken); res.Status != http.StatusOK {
+			t.Fatalf("purgar ciudad = %d: %s", res.Status, res.Body)
+		}
+		if res := testutil.Do(t, app, http.MethodDelete, rutaProvincia, nil, e.AdminToken); res.Status != http.StatusOK {
+			t.Fatalf("purgar provincia = %d: %s", res.Status, res.Body)
+		}
+		if res := testutil.Do(t, app, http.MethodPut, rutaProvincia+"/restore", nil, e.AdminToken); res.Status != http.StatusNotFound {
+			t.Errorf("restaurar provincia purgada = %d, se esperaba 404", res.Status)
+		}
+
+		// Restaurar un estudiante restaura también su persona y su usuario
+		persona := f.Persona()
+		usuario := f.Usuario(persona, e.TipoEstudiante, "secreto1")
+		alumno := f.Estudiante(persona, e.Institucion, e.Ciudad)
+		if err := repos.Estudiante.DeleteEstudiante(alumno.ID); err != nil {
+			t.Fatal(err)
+		}
+		if res := testutil.Do(t, app, http.MethodPut, fmt.Sprintf("/api/papelera/estudiantes/%d/restore", alumno.ID), nil, e.AdminToken); res.Status != http.StatusOK {
+			t.Fatalf("restaurar estudiante = %d: %s", res.Status, res.Body)
+		}
+		if _, err := repos.Persona.GetPersonaByID(persona.ID); err != nil {
+			t.Errorf("la persona no se restauró: %v", err)
+		}
+		if _, err := repos.Usuario.GetUsuarioByID(usuario.ID); err != nil {
+			t.Errorf("el usuario no se restauró: %v", err)
+		}
+
+		// Un usuario con notificaciones no se purga; un webhook eliminado está en la papelera
+		avisado := f.Usuario(f.Persona(), e.TipoEstudiante, "secreto1")
+		if err := repos.Notificacion.CreateNotificacion(&models.Notificacion{UsuarioID: avisado.ID, Tipo: "comunicado", Titulo: "Aviso"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := repos.Usuario.DeleteUsuario(avisado.ID); err != nil {
+			t.Fatal(err)
+		}
+		res = testutil.Do(t, app, http.MethodDelete, fmt.Sprintf("/api/papelera/usuarios/%d", avisado.ID), nil, e.AdminToken)
+		if res.Status != http.StatusConflict || res.Map(t)["error"] != "papelera_dependencias" {
+			t.Errorf("purgar usuario con notificaciones = %d: %s", res.Status, res.Body)
+		}
+		webhook := &models.Webhook{URL: "https://example.com/hook", Eventos: []string{"noticia.creada"}, Secreto: "secreto", Activo: true}
+		if err := repos.Webhook.CreateWebhook(webhook); err != nil {
+			t.Fatal(err)
+		}
+		if err := repos.Webhook.DeleteWebhook(webhook.ID); err != nil {
+			t.Fatal(err)
+		}
+		if res := testutil.Do(t, app, http.MethodPut, fmt.Sprintf("/api/papelera/webhooks/%d/restore", webhook.ID), nil, e.AdminToken); res.Status != http.StatusOK {
+			t.Errorf("restaurar webhook = %d: %s", res.Status, res.Body)
+		}
+	})
+}
@@ -3,20 +3,22 @@ package handlers
 import (
 	"ApiEscuela/models"
 	"ApiEscuela/repositories"
+	"ApiEscuela/validacion"
+	"errors"
 	"regexp"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 type PersonaHandler struct {
-	personaRepo *repositories.PersonaRepository
+	personaRepo      repositories.PersonaRepository
+	ifMatchRequerido bool // exige If-Match en PUT y DELETE (IF_MATCH_REQUIRED)
 }
 
-func NewPersonaHandler(personaRepo *repositories.PersonaRepository) *PersonaHandler {
-	return &PersonaHandler{personaRepo: personaRepo}
+func NewPersonaHandler(personaRepo repositories.PersonaRepository, ifMatchRequerido bool) *PersonaHandler {
+	return &PersonaHandler{personaRepo: personaRepo, ifMatchRequerido: ifMatchRequerido}
 }
 
 // CreatePersona crea una nueva persona
@@ -28,14 +30,9 @@ func (h *PersonaHandler) CreatePersona(c *fiber.Ctx) error {
 		return SendError(c, 400, "invalid_json", "No se puede procesar el JSON. Verifique el formato de los datos", err.Error())
 	}
 
-	// Validar campos requeridos
-	if validationErrors := h.validatePersonaRequiredFields(&persona); len(validationErrors) > 0 {
-		return SendValidationError(c, "Faltan campos requeridos", validationErrors)
-	}
-
-	// Validar datos completos
-	if validationErrors := h.validatePersona(&persona, false); len(validationErrors) > 0 {
-		return SendValidationError(c, "Los datos proporcionados no son válidos", validationErrors)
+	// Validar según las etiquetas validate del modelo
+	if err := validacion.Validar(&persona); err != nil {
+		return err
 	}
 
 	// Limpiar datos
@@ -75,17 +72,8 @@ func (h *PersonaHandler) CreatePersona(c *fiber.Ctx) error {
 
 	// Crear persona
 	if err := h.personaRepo.CreatePersona(&persona); err != nil {
-		// Manejar errores específicos del repositorio
-		switch err.Error() {
-		case "cedula repetida":
-			return SendError(c, 409, "duplicate_cedula", "Ya existe una persona con esta cédula", "La cédula debe ser única")
-		case "correo repetido":
-			return SendError(c, 409, "duplicate_email", "Ya existe una persona con este correo electrónico", "El correo debe ser único")
-		case "persona ya existe":
-			return SendError(c, 409, "persona_duplicada", "Ya existe una persona con estos datos", "Verifique que los datos sean únicos")
-		default:
-			return SendError(c, 500, "database_error", "Error interno del servidor", "No se pudo crear la persona")
-		}
+		// Los duplicados son errores tipados; ErrorHandler los responde con su código
+		return err
 	}
 
 	return SendSuccess(c, 201, persona)
@@ -107,6 +95,9 @@ func (h *PersonaHandler) GetPersona(c *fiber.Ctx) error {
 	if err != nil {
 		return SendError(c, 404, "person_not_found", "No se encontró la persona solicitada", "Verifique que el ID sea correcto")
 	}
+	if conVersion(c, etagRegistro("personas", persona.Model)) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
 
 	return SendSuccess(c, 200, persona)
 }
@@ -138,6 +129,10 @@ func (h *PersonaHandler) UpdatePersona(c *fiber.Ctx) error {
 	if err != nil {
 		return SendError(c, 404, "person_not_found", "No se encontró la persona solicitada", "Verifique que el ID sea correcto")
 	}
+	// If-Match debe traer la versión leída; si otro usuario la cambió, 412 con la actual
+	if ok, err := verificarVersion(c, etagRegistro("personas", existingPersona.Model), existingPersona, h.ifMatchRequerido); !ok {
+		return err
+	}
 
 	// Parsear datos de actualización
 	var updateData models.Persona
@@ -145,14 +140,9 @@ func (h *PersonaHandler) UpdatePersona(c *fiber.Ctx) error {
 		return SendError(c, 400, "invalid_json", "No se puede procesar el JSON. Verifique el formato de los datos", err.Error())
 	}
 
-	// Validar campos requeridos
-	if validationErrors := h.validatePersonaRequiredFields(&updateData); len(validationErrors) > 0 {
-		return SendValidationError(c, "Faltan campos requeridos", validationErrors)
-	}
-
-	// Validar datos de actualización
-	if validationErrors := h.validatePersona(&updateData, true); len(validationErrors) > 0 {
-		return SendValidationError(c, "Los datos proporcionados no son válidos", validationErrors)
+	// Validar según las etiquetas validate del modelo
+	if err := validacion.Validar(&updateData); err != nil {
+		return err
 	}
 
 	// Actualizar campos (mantener ID original)
@@ -206,24 +196,30 @@ func (h *PersonaHandler) UpdatePersona(c *fiber.Ctx) error {
 		}
 	}
 
-	// Actualizar en base de datos
+	// Actualizar en base de datos; falla si otro usuario la guardó mientras tanto
 	if err := h.personaRepo.UpdatePersona(&persona); err != nil {
-		// Manejar errores específicos del repositorio
-		switch err.Error() {
-		case "cedula repetida":
-			return SendError(c, 409, "duplicate_cedula", "Ya existe otra persona con esta cédula", "La cédula debe ser única")
-		case "correo repetido":
-			return SendError(c, 409, "duplicate_email", "Ya existe otra persona con este correo electrónico", "El correo debe ser único")
-		case "persona ya existe":
-			return SendError(c, 409, "persona_duplicada", "Ya existe otra persona con estos datos", "Verifique que los datos sean únicos")
-		default:
-			return SendError(c, 500, "database_error", "Error interno del servidor", "No se pudo actualizar la persona")
+		if errors.Is(err, repositories.ErrVersionDesactualizada) {
+			if actual, errActual := h.personaRepo.GetPersonaByID(persona.ID); errActual == nil {
+				return versionDesactualizada(c, etagRegistro("personas", actual.Model), actual)
+			}
 		}
+		// Los duplicados son errores tipados; ErrorHandler los responde con su código
+		return err
 	}
 
+	// Se responde la versión guardada, con su ETag para la próxima escritura
+	if actualizada, err := h.personaRepo.GetPersonaByID(persona.ID); err == nil {
+		persona = *actualizada
+	}
+	c.Set(fiber.HeaderETag, etagRegistro("personas", persona.Model))
 	return SendSuccess(c, 200, persona)
 }
 
+// PatchPersona actualiza solo los campos enviados de una persona (JSON Merge Patch)
+func (h *PersonaHandler) PatchPersona(c *fiber.Ctx) error {
+	return mergePatch(c, cargarCon(h.personaRepo.GetPersonaByID), h.UpdatePersona)
+}
+
 // DeletePersona elimina una persona
 func (h *PersonaHandler) DeletePersona(c *fiber.Ctx) error {
 	idStr := c.Params("id")
@@ -241,6 +237,9 @@ func (h *PersonaHandler) DeletePersona(c *fiber.Ctx) error {
 	if err != nil {
 		return SendError(c, 404, "person_not_found", "No se encontró la persona solicitada", "Verifique que el ID sea correcto")
 	}
+	if ok, err := verificarVersion(c, etagRegistro("personas", persona.Model), persona, h.ifMatchRequerido); !ok {
+		return err
+	}
 
 	// Verificar si la persona tiene relaciones (estudiantes, autoridades, usuarios)
 	if len(persona.Estudiantes) > 0 || len(persona.EstudiantesUniv) > 0 || len(persona.AutoridadesUTEQ) > 0 || len(persona.Usuarios) > 0 {
@@ -298,146 +297,3 @@ func (h *PersonaHandler) GetPersonasByCorreo(c *fiber.Ctx) error {
 
 	return SendSuccess(c, 200, personas)
 }
-
-// validatePersona valida los datos de una persona
-func (h *PersonaHandler) validatePersona(persona *models.Persona, isUpdate bool) []ValidationError {
-	var errors []ValidationError
-
-	// Validar nombre
-	nombre := strings.TrimSpace(persona.Nombre)
-	if nombre == "" {
-		errors = append(errors, ValidationError{
-			Field:   "nombre",
-			Message: "El nombre es requerido",
-			Value:   persona.Nombre,
-		})
-	} else if len(nombre) < 2 {
-		errors = append(errors, ValidationError{
-			Field:   "nombre",
-			Message: "El nombre debe tener al menos 2 caracteres",
-			Value:   persona.Nombre,
-		})
-	} else if len(nombre) > 100 {
-		errors = append(errors, ValidationError{
-			Field:   "nombre",
-			Message: "El nombre no puede exceder 100 caracteres",
-			Value:   persona.Nombre,
-		})
-	}
-
-	// Validar cédula
-	cedula := strings.TrimSpace(persona.Cedula)
-	if cedula == "" {
-		errors = append(errors, ValidationError{
-			Field:   "cedula",
-			Message: "La cédula es requerida",
-			Value:   persona.Cedula,
-		})
-	} else {
-		// Validar formato de cédula (más flexible)
-		cedulaRegex := regexp.MustCompile(`^\d{6,15}$`)
-		if !cedulaRegex.MatchString(cedula) {
-			errors = append(errors, ValidationError{
-				Field:   "cedula",
-				Message: "La cédula debe tener entre 6 y 15 dígitos numéricos",
-				Value:   persona.Cedula,
-			})
-		}
-	}
-
-	// Validar correo (opcional pero si se proporciona debe ser válido)
-	if persona.Correo != nil {
-		correo := strings.TrimSpace(*persona.Correo)
-		if correo != "" {
-			emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
-			if !emailRegex.MatchString(correo) {
-				errors = append(errors, ValidationError{
-					Field:   "correo",
-					Message: "El formato del correo electrónico no es válido",
-					Value:   correo,
-				})
-			} else if len(correo) > 255 {
-				errors = append(errors, ValidationError{
-					Field:   "correo",
-					Message: "El correo no puede exceder 255 caracteres",
-					Value:   correo,
-				})
-			}
-		}
-	}
-
-	// Validar teléfono (opcional pero si se proporciona debe ser válido)
-	if persona.Telefono != nil {
-		telefono := strings.TrimSpace(*persona.Telefono)
-		if telefono != "" {
-			// Verificar que tenga al menos 7 dígitos
-			digitCount := 0
-			for _, char := range telefono {
-				if char >= '0' && char <= '9' {
-					digitCount++
-				}
-			}
-			if digitCount < 7 {
-				errors = append(errors, ValidationError{
-					Field:   "telefono",
-					Message: "El teléfono debe contener al menos 7 dígitos",
-					Value:   telefono,
-				})
-			}
-		}
-	}
-
-	// Validar fecha de nacimiento (opcional pero si se proporciona debe ser válida)
-	if !persona.FechaNacimiento.IsZero() {
-		now := time.Now()
-		// Verificar que la fecha no sea futura
-		if persona.FechaNacimiento.After(now) {
-			errors = append(errors, ValidationError{
-				Field:   "fecha_nacimiento",
-				Message: "La fecha de nacimiento no puede ser futura",
-				Value:   persona.FechaNacimiento.Format("2006-01-02"),
-			})
-		}
-	}
-
-	return errors
-}
-
-// validatePersonaRequiredFields valida que los campos requeridos estén presentes
-func (h *PersonaHandler) validatePersonaRequiredFields(persona *models.Persona) []ValidationError {
-	var errors []ValidationError
-
-	if strings.TrimSpace(persona.Nombre) == "" {
-		errors = append(errors, ValidationError{
-			Field:   "nombre",
-			Message: "El campo nombre es requerido",
-		})
-	}
-
-	if strings.TrimSpace(persona.Cedula) == "" {
-		errors = append(errors, ValidationError{
-			Field:   "cedula",
-			Message: "El campo cédula es requerido",
-		})
-	}
-
-	// Validar que al menos uno de los campos de contacto (correo o teléfono) esté presente
-	correo := ""
-	if persona.Correo != nil {
-		correo = strings.TrimSpace(*persona.Correo)
-	}
-
-	telefono := ""
-	if persona.Telefono != nil {
-		telefono = strings.TrimSpace(*persona.Telefono)
-	}
-
-	if correo == "" && telefono == "" {
-		errors = append(errors, ValidationError{
-			Field:   "contacto",
-			Message: "Debe proporcionar al menos un teléfono o un correo electrónico",
-		})
-	}
-
-	return errors
-}
@@ -3,6 +3,10 @@ package handlers
 import (
 	"ApiEscuela/models"
 	"ApiEscuela/repositories"
+	"ApiEscuela/services"
+	"ApiEscuela/validacion"
+	"errors"
+	"log"
 	"strconv"
 	"time"
 
@@ -10,11 +14,24 @@ import (
 )
 
 type ProgramaVisitaHandler struct {
-	programaRepo *repositories.ProgramaVisitaRepository
+	programaRepo     repositories.ProgramaVisitaRepository
+	webhooks         services.WebhookService
+	validador        *validacion.Validador
+	ifMatchRequerido bool // exige If-Match en PUT y DELETE (IF_MATCH_REQUIRED)
 }
 
-func NewProgramaVisitaHandler(programaRepo *repositories.ProgramaVisitaRepository) *ProgramaVisitaHandler {
-	return &ProgramaVisitaHandler{programaRepo: programaRepo}
+func NewProgramaVisitaHandler(programaRepo repositories.ProgramaVisitaRepository, institucionRepo repositories.InstitucionRepository, webhooks services.WebhookService, ifMatchRequerido bool) (*ProgramaVisitaHandler, error) {
+	validador := validacion.New().
+		ConReferencia("institucion", referencia("institucion_no_existe", "No se encontró la institución con el ID especificado", institucionRepo))
+	if err := validador.Verificar(models.ProgramaVisita{}); err != nil {
+		return nil, err
+	}
+	return &ProgramaVisitaHandler{
+		programaRepo:     programaRepo,
+		webhooks:         webhooks,
+		validador:        validador,
+		ifMatchRequerido: ifMatchRequerido,
+	}, nil
 }
 
 // CreateProgramaVisita crea un nuevo programa de visita
@@ -27,11 +44,19 @@ func (h *ProgramaVisitaHandler) CreateProgramaVisita(c *fiber.Ctx) error {
 		})
 	}
 
+	// Validar según las etiquetas validate del modelo
+	if err := h.validador.Validar(&programa); err != nil {
+		return err
+	}
+
 	if err := h.programaRepo.CreateProgramaVisita(&programa); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "No se puede crear el programa de visita",
 		})
 	}
+	if err := h.webhooks.Publicar(services.EventoProgramaVisitaCreado, programa); err != nil {
+		log.Printf("Error al publicar el programa de visita %d en los webhooks: %v", programa.ID, err)
+	}
 
 	return c.Status(fiber.StatusCreated).JSON(programa)
 }
@@ -51,6 +76,9 @@ func (h *ProgramaVisitaHandler) GetProgramaVisita(c *fiber.Ctx) error {
 			"error": "Programa de visita no encontrado",
 		})
 	}
+	if conVersion(c, etagRegistro("programas-visita", programa.Model)) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
 
 	return c.JSON(programa)
 }
@@ -83,21 +111,55 @@ func (h *ProgramaVisitaHandler) UpdateProgramaVisita(c *fiber.Ctx) error {
 		})
 	}
 
+	// If-Match debe traer la versión leída; si otro usuario la cambió, 412 con la actual
+	if ok, err := verificarVersion(c, etagRegistro("programas-visita", programa.Model), programa, h.ifMatchRequerido); !ok {
+		return err
+	}
+
+	fechaAnterior := programa.Fecha
+	modelo := programa.Model
 	if err := c.BodyParser(programa); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "No se puede procesar el JSON",
 		})
 	}
+	// El cuerpo no puede cambiar el ID ni la versión con que se leyó el programa
+	programa.Model = modelo
+	if err := h.validador.Validar(programa); err != nil {
+		return err
+	}
+	// Si la visita cambia de fecha se vuelve a enviar el recordatorio
+	if !programa.Fecha.Equal(fechaAnterior) {
+		programa.RecordatorioEnviadoEn = nil
+	}
 
 	if err := h.programaRepo.UpdateProgramaVisita(programa); err != nil {
+		if errors.Is(err, repositories.ErrVersionDesactualizada) {
+			if actual, errActual := h.programaRepo.GetProgramaVisitaByID(programa.ID); errActual == nil {
+				return versionDesactualizada(c, etagRegistro("programas-visita", actual.Model), actual)
+			}
+		}
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "No se puede actualizar el programa de visita",
 		})
 	}
 
+	// Se responde la versión guardada, con su ETag para la próxima escritura
+	if actualizado, err := h.programaRepo.GetProgramaVisitaByID(programa.ID); err == nil {
+		programa = actualizado
+	}
+	if err := h.webhooks.Publicar(services.EventoProgramaVisitaActualizado, programa); err != nil {
+		log.Printf("Error al publicar el programa de visita %d en los webhooks: %v", programa.ID, err)
+	}
+	c.Set(fiber.HeaderETag, etagRegistro("programas-visita", programa.Model))
 	return c.JSON(programa)
 }
 
+// PatchProgramaVisita actualiza solo los campos enviados de un programa de visita (JSON Merge Patch)
+func (h *ProgramaVisitaHandler) PatchProgramaVisita(c *fiber.Ctx) error {
+	return mergePatch(c, cargarCon(h.programaRepo.GetProgramaVisitaByID), h.UpdateProgramaVisita)
+}
+
 // DeleteProgramaVisita elimina un programa de visita
 func (h *ProgramaVisitaHandler) DeleteProgramaVisita(c *fiber.Ctx) error {
 	id, err := strconv.Atoi(c.Params("id"))
@@ -107,6 +169,16 @@ func (h *ProgramaVisitaHandler) DeleteProgramaVisita(c *fiber.Ctx) error {
 		})
 	}
 
+	programa, err := h.programaRepo.GetProgramaVisitaByID(uint(id))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Programa de visita no encontrado",
+		})
+	}
+	if ok, err := verificarVersion(c, etagRegistro("programas-visita", programa.Model), programa, h.ifMatchRequerido); !ok {
+		return err
+	}
+
 	if err := h.programaRepo.DeleteProgramaVisita(uint(id)); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "No se puede eliminar el programa de visita",
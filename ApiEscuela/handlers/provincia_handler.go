@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"ApiEscuela/middleware"
 	"ApiEscuela/models"
 	"ApiEscuela/repositories"
 	"strconv"
@@ -9,10 +10,10 @@ import (
 )
 
 type ProvinciaHandler struct {
-	provinciaRepo *repositories.ProvinciaRepository
+	provinciaRepo repositories.ProvinciaRepository
 }
 
-func NewProvinciaHandler(provinciaRepo *repositories.ProvinciaRepository) *ProvinciaHandler {
+func NewProvinciaHandler(provinciaRepo repositories.ProvinciaRepository) *ProvinciaHandler {
 	return &ProvinciaHandler{provinciaRepo: provinciaRepo}
 }
 
@@ -97,6 +98,11 @@ func (h *ProvinciaHandler) UpdateProvincia(c *fiber.Ctx) error {
 	return c.JSON(provincia)
 }
 
+// PatchProvincia actualiza solo los campos enviados de una provincia (JSON Merge Patch)
+func (h *ProvinciaHandler) PatchProvincia(c *fiber.Ctx) error {
+	return mergePatch(c, cargarCon(h.provinciaRepo.GetProvinciaByID), h.UpdateProvincia)
+}
+
 // DeleteProvincia elimina una provincia
 func (h *ProvinciaHandler) DeleteProvincia(c *fiber.Ctx) error {
 	id, err := strconv.Atoi(c.Params("id"))
@@ -129,4 +135,13 @@ func (h *ProvinciaHandler) GetProvinciaByNombre(c *fiber.Ctx) error {
 	}
 
 	return c.JSON(provincia)
-}
\ No newline at end of file
+}
+
+// Version calcula la versión de la lista de las provincias para las peticiones condicionales
+func (h *ProvinciaHandler) Version() (middleware.Version, error) {
+	lista, err := h.provinciaRepo.GetAllProvincias()
+	if err != nil {
+		return middleware.Version{}, err
+	}
+	return versionDe("provincias", lista), nil
+}
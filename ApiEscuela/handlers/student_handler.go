@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"ApiEscuela/eventos"
 	"ApiEscuela/models"
 	"ApiEscuela/repositories"
 	"ApiEscuela/services"
+	"ApiEscuela/validacion"
 	"encoding/json"
+	"errors"
+	"log"
 	"strconv"
 	"strings"
 	"time"
@@ -13,24 +17,36 @@ import (
 )
 
 type EstudianteHandler struct {
-	estudianteRepo  *repositories.EstudianteRepository
-	personaRepo     *repositories.PersonaRepository
-	institucionRepo *repositories.InstitucionRepository
-	ciudadRepo      *repositories.CiudadRepository
-	usuarioRepo     *repositories.UsuarioRepository
-	tipoUsuarioRepo *repositories.TipoUsuarioRepository
-	authService     *services.AuthService
+	estudianteRepo  repositories.EstudianteRepository
+	personaRepo     repositories.PersonaRepository
+	institucionRepo repositories.InstitucionRepository
+	ciudadRepo      repositories.CiudadRepository
+	usuarioRepo     repositories.UsuarioRepository
+	tipoUsuarioRepo repositories.TipoUsuarioRepository
+	authService     services.AuthService
+	validador       *validacion.Validador
+	eventos         *eventos.Bus
+	webhooks        services.WebhookService
 }
 
 func NewEstudianteHandler(
-	estudianteRepo *repositories.EstudianteRepository,
-	personaRepo *repositories.PersonaRepository,
-	institucionRepo *repositories.InstitucionRepository,
-	ciudadRepo *repositories.CiudadRepository,
-	usuarioRepo *repositories.UsuarioRepository,
-	tipoUsuarioRepo *repositories.TipoUsuarioRepository,
-	authService *services.AuthService,
-) *EstudianteHandler {
+	estudianteRepo repositories.EstudianteRepository,
+	personaRepo repositories.PersonaRepository,
+	institucionRepo repositories.InstitucionRepository,
+	ciudadRepo repositories.CiudadRepository,
+	usuarioRepo repositories.UsuarioRepository,
+	tipoUsuarioRepo repositories.TipoUsuarioRepository,
+	authService services.AuthService,
+	bus *eventos.Bus,
+	webhooks services.WebhookService,
+) (*EstudianteHandler, error) {
+	validador := validacion.New().
+		ConReferencia("persona", referencia("persona_no_existe", "No se encontró la persona con el ID especificado", personaRepo)).
+		ConReferencia("institucion", referencia("institucion_no_existe", "No se encontró la institución con el ID especificado", institucionRepo)).
+		ConReferencia("ciudad", referencia("ciudad_no_existe", "No se encontró la ciudad con el ID especificado", ciudadRepo))
+	if err := validador.Verificar(models.Estudiante{}, BulkEstudianteRequest{}); err != nil {
+		return nil, err
+	}
 	return &EstudianteHandler{
 		estudianteRepo:  estudianteRepo,
 		personaRepo:     personaRepo,
@@ -39,7 +55,10 @@ func NewEstudianteHandler(
 		usuarioRepo:     usuarioRepo,
 		tipoUsuarioRepo: tipoUsuarioRepo,
 		authService:     authService,
-	}
+		eventos:         bus,
+		webhooks:        webhooks,
+		validador:       validador,
+	}, nil
 }
 
 // CreateEstudiante crea un nuevo estudiante
@@ -51,14 +70,9 @@ func (h *EstudianteHandler) CreateEstudiante(c *fiber.Ctx) error {
 		return SendError(c, 400, "json_invalido", "No se puede procesar el JSON. Verifique el formato de los datos", err.Error())
 	}
 
-	// Validar campos requeridos
-	if validationErrors := h.validateEstudianteRequiredFields(&estudiante); len(validationErrors) > 0 {
-		return SendValidationError(c, "Faltan campos requeridos", validationErrors)
-	}
-
-	// Validar datos completos
-	if validationErrors := h.validateEstudiante(&estudiante, false); len(validationErrors) > 0 {
-		return SendValidationError(c, "Los datos proporcionados no son válidos", validationErrors)
+	// Validar según las etiquetas validate del modelo
+	if err := h.validador.Validar(&estudiante); err != nil {
+		return err
 	}
 
 	// Limpiar datos
@@ -70,38 +84,12 @@ func (h *EstudianteHandler) CreateEstudiante(c *fiber.Ctx) error {
 		}
 	}
 
-	// Verificar que los IDs sean válidos
-	if estudiante.PersonaID == 0 {
-		return SendError(c, 400, "persona_id_invalido", "El ID de la persona es requerido", "Proporcione un persona_id válido mayor que 0")
-	}
-	if estudiante.InstitucionID == 0 {
-		return SendError(c, 400, "institucion_id_invalido", "El ID de la institución es requerido", "Proporcione un institucion_id válido mayor que 0")
-	}
-	if estudiante.CiudadID == 0 {
-		return SendError(c, 400, "ciudad_id_invalido", "El ID de la ciudad es requerido", "Proporcione un ciudad_id válido mayor que 0")
-	}
-
-	// Verificar que las relaciones existen (validación de relaciones)
-	if !h.personaExists(estudiante.PersonaID) {
-		return SendError(c, 400, "persona_no_existe", "No se encontró la persona con el ID especificado", "Verifique que el persona_id sea correcto y que la persona exista en el sistema")
-	}
-	if !h.institucionExists(estudiante.InstitucionID) {
-		return SendError(c, 400, "institucion_no_existe", "No se encontró la institución con el ID especificado", "Verifique que el institucion_id sea correcto y que la institución exista en el sistema")
-	}
-	if !h.ciudadExists(estudiante.CiudadID) {
-		return SendError(c, 400, "ciudad_no_existe", "No se encontró la ciudad con el ID especificado", "Verifique que el ciudad_id sea correcto y que la ciudad exista en el sistema")
-	}
-
 	// Crear estudiante
 	if err := h.estudianteRepo.CreateEstudiante(&estudiante); err != nil {
-		// Manejar errores específicos del repositorio
-		switch err.Error() {
-		case "estudiante ya existe":
-			return SendError(c, 409, "estudiante_duplicado", "La persona ya tiene un registro de estudiante", "Una persona solo puede tener un registro de estudiante")
-		default:
-			return SendError(c, 500, "error_base_datos", "Error interno del servidor", "No se pudo crear el estudiante")
-		}
+		// Los duplicados son errores tipados; ErrorHandler los responde con su código
+		return err
 	}
+	h.publicarEstudiante(&estudiante)
 
 	return SendSuccess(c, 201, estudiante)
 }
@@ -180,9 +168,9 @@ func (h *EstudianteHandler) UpdateEstudiante(c *fiber.Ctx) error {
 		return SendError(c, 400, "json_invalido", "No se puede procesar el JSON. Verifique el formato de los datos", err.Error())
 	}
 
-	// Validar datos de actualización
-	if validationErrors := h.validateEstudiante(&updateData, true); len(validationErrors) > 0 {
-		return SendValidationError(c, "Los datos proporcionados no son válidos", validationErrors)
+	// Validar según las etiquetas validate del modelo
+	if err := h.validador.Validar(&updateData); err != nil {
+		return err
 	}
 
 	// Verificar si la nueva persona ya tiene un registro de estudiante (si cambia la persona)
@@ -227,18 +215,18 @@ func (h *EstudianteHandler) UpdateEstudiante(c *fiber.Ctx) error {
 
 	// Guardar cambios
 	if err := h.estudianteRepo.UpdateEstudiante(existingEstudiante); err != nil {
-		// Manejar errores específicos del repositorio
-		switch err.Error() {
-		case "estudiante ya existe":
-			return SendError(c, 409, "estudiante_duplicado", "La persona ya tiene un registro de estudiante", "Una persona solo puede tener un registro de estudiante")
-		default:
-			return SendError(c, 500, "error_base_datos", "Error interno del servidor", "No se pudo actualizar el estudiante")
-		}
+		// Los duplicados son errores tipados; ErrorHandler los responde con su código
+		return err
 	}
 
 	return SendSuccess(c, 200, existingEstudiante)
 }
 
+// PatchEstudiante actualiza solo los campos enviados de un estudiante (JSON Merge Patch)
+func (h *EstudianteHandler) PatchEstudiante(c *fiber.Ctx) error {
+	return mergePatch(c, cargarCon(h.estudianteRepo.GetEstudianteByID), h.UpdateEstudiante)
+}
+
 // DeleteEstudiante elimina un estudiante y en cascada su usuario y persona
 func (h *EstudianteHandler) DeleteEstudiante(c *fiber.Ctx) error {
 	idStr := c.Params("id")
@@ -355,14 +343,14 @@ func (h *EstudianteHandler) GetEstudiantesByEspecialidad(c *fiber.Ctx) error {
 
 // BulkEstudianteRequest representa un estudiante en la carga masiva
 type BulkEstudianteRequest struct {
-	Cedula          string `json:"cedula"`
-	Nombre          string `json:"nombre"`
-	Correo          string `json:"correo"`
-	Telefono        string `json:"telefono"`
+	Cedula          string `json:"cedula" validate:"required,cedula"`
+	Nombre          string `json:"nombre" validate:"required,min=2,max=100"`
+	Correo          string `json:"correo" validate:"email,max=255"`
+	Telefono        string `json:"telefono" validate:"telefono"`
 	FechaNacimiento string `json:"fecha_nacimiento"`
-	InstitucionID   uint   `json:"institucion_id"`
-	CiudadID        uint   `json:"ciudad_id"`
-	Especialidad    string `json:"especialidad"`
+	InstitucionID   uint   `json:"institucion_id" validate:"required,existe=institucion"`
+	CiudadID        uint   `json:"ciudad_id" validate:"required,existe=ciudad"`
+	Especialidad    string `json:"especialidad" validate:"max=100"`
 }
 
 // BulkEstudianteResult representa el resultado de procesar un estudiante
@@ -398,7 +386,25 @@ func (h *EstudianteHandler) CreateEstudiantesBulk(c *fiber.Ctx) error {
 	var exitosos []BulkEstudianteResult
 	var fallidos []BulkEstudianteResult
 
+	// Avance por /api/eventos; el cliente puede enviar ?trabajo= para reconocer sus eventos
+	usuarioID, _ := c.Locals("user_id").(uint)
+	trabajo := c.Query("trabajo")
+	if trabajo == "" {
+		trabajo = eventos.NuevoTrabajo()
+	}
+	total := len(request.Estudiantes)
+	paso := total/50 + 1 // unos 50 eventos por carga, sin importar su tamaño
+	avance := func(procesados int, terminado bool) {
+		h.eventos.PublicarPara(usuarioID, eventos.ImportacionProgreso, eventos.Progreso{
+			Trabajo: trabajo, Etapa: "estudiantes", Procesados: procesados, Total: total,
+			Exitosos: len(exitosos), Fallidos: len(fallidos), Terminado: terminado,
+		})
+	}
+
 	for i, est := range request.Estudiantes {
+		if i%paso == 0 {
+			avance(i, false)
+		}
 		fila := i + 1 // Fila en el Excel (1-indexed, asumiendo que la fila 1 es el encabezado)
 		result := BulkEstudianteResult{
 			Fila:   fila,
@@ -406,47 +412,13 @@ func (h *EstudianteHandler) CreateEstudiantesBulk(c *fiber.Ctx) error {
 			Nombre: est.Nombre,
 		}
 
-		// Validar campos requeridos
-		if strings.TrimSpace(est.Cedula) == "" {
-			result.Error = "La cédula es requerida"
-			fallidos = append(fallidos, result)
-			continue
-		}
-		if strings.TrimSpace(est.Nombre) == "" {
-			result.Error = "El nombre es requerido"
+		// Validar la fila según las etiquetas validate de BulkEstudianteRequest
+		if err := h.validador.Validar(&est); err != nil {
+			result.Error = primerMensaje(err)
 			fallidos = append(fallidos, result)
 			continue
 		}
-		if est.InstitucionID == 0 {
-			result.Error = "La institución es requerida"
-			fallidos = append(fallidos, result)
-			continue
-		}
-		if est.CiudadID == 0 {
-			result.Error = "La ciudad es requerida"
-			fallidos = append(fallidos, result)
-			continue
-		}
-
-		// Validar que la cédula tenga 10 dígitos
 		cedula := strings.TrimSpace(est.Cedula)
-		if len(cedula) != 10 {
-			result.Error = "La cédula debe tener 10 dígitos"
-			fallidos = append(fallidos, result)
-			continue
-		}
-
-		// Verificar que institución y ciudad existen
-		if !h.institucionExists(est.InstitucionID) {
-			result.Error = "La institución especificada no existe"
-			fallidos = append(fallidos, result)
-			continue
-		}
-		if !h.ciudadExists(est.CiudadID) {
-			result.Error = "La ciudad especificada no existe"
-			fallidos = append(fallidos, result)
-			continue
-		}
 
 		// Parsear fecha de nacimiento si se proporciona
 		var fechaNacimiento time.Time
@@ -482,13 +454,14 @@ func (h *EstudianteHandler) CreateEstudiantesBulk(c *fiber.Ctx) error {
 		}
 
 		if err := h.personaRepo.CreatePersona(persona); err != nil {
-			errorMsg := "Error al crear persona"
-			if strings.Contains(err.Error(), "cedula") || strings.Contains(err.Error(), "duplicado") {
-				errorMsg = "Ya existe una persona con esta cédula"
-			} else if strings.Contains(err.Error(), "correo") {
-				errorMsg = "Ya existe una persona con este correo"
+			switch {
+			case errors.Is(err, repositories.ErrCedulaDuplicada):
+				result.Error = "Ya existe una persona con esta cédula"
+			case errors.Is(err, repositories.ErrCorreoDuplicado):
+				result.Error = "Ya existe una persona con este correo"
+			default:
+				result.Error = "Error al crear persona"
 			}
-			result.Error = errorMsg
 			fallidos = append(fallidos, result)
 			continue
 		}
@@ -513,11 +486,10 @@ func (h *EstudianteHandler) CreateEstudiantesBulk(c *fiber.Ctx) error {
 		if err := h.usuarioRepo.CreateUsuario(usuario); err != nil {
 			// Rollback: eliminar persona
 			h.personaRepo.DeletePersona(persona.ID)
-			errorMsg := "Error al crear usuario"
-			if strings.Contains(err.Error(), "duplicado") || strings.Contains(err.Error(), "repetido") {
-				errorMsg = "Ya existe un usuario con esta cédula"
+			result.Error = "Error al crear usuario"
+			if errors.Is(err, repositories.ErrUsuarioDuplicado) {
+				result.Error = "Ya existe un usuario con esta cédula"
 			}
-			result.Error = errorMsg
 			fallidos = append(fallidos, result)
 			continue
 		}
@@ -534,22 +506,26 @@ func (h *EstudianteHandler) CreateEstudiantesBulk(c *fiber.Ctx) error {
 			// Rollback: eliminar usuario y persona
 			h.usuarioRepo.DeleteUsuario(usuario.ID)
 			h.personaRepo.DeletePersona(persona.ID)
-			errorMsg := "Error al crear estudiante"
-			if strings.Contains(err.Error(), "duplicado") || strings.Contains(err.Error(), "existe") {
-				errorMsg = "Ya existe un estudiante para esta persona"
+			result.Error = "Error al crear estudiante"
+			if errors.Is(err, repositories.ErrEstudianteDuplicado) {
+				result.Error = "Ya existe un estudiante para esta persona"
 			}
-			result.Error = errorMsg
 			fallidos = append(fallidos, result)
 			continue
 		}
+		estudiante.Persona = *persona
+		h.publicarEstudiante(estudiante)
 
 		// Éxito
 		result.Usuario = cedula
 		exitosos = append(exitosos, result)
 	}
 
+	avance(total, true)
+
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"success":        true,
+		"trabajo":        trabajo,
 		"total":          len(request.Estudiantes),
 		"total_exitosos": len(exitosos),
 		"total_fallidos": len(fallidos),
@@ -558,86 +534,11 @@ func (h *EstudianteHandler) CreateEstudiantesBulk(c *fiber.Ctx) error {
 	})
 }
 
-// validateEstudiante valida los datos de un estudiante
-func (h *EstudianteHandler) validateEstudiante(estudiante *models.Estudiante, isUpdate bool) []ValidationError {
-	var errors []ValidationError
-
-	// Validar PersonaID
-	if estudiante.PersonaID == 0 {
-		errors = append(errors, ValidationError{
-			Field:   "persona_id",
-			Message: "El ID de la persona es requerido",
-		})
-	}
-
-	// Validar InstitucionID
-	if estudiante.InstitucionID == 0 {
-		errors = append(errors, ValidationError{
-			Field:   "institucion_id",
-			Message: "El ID de la institución es requerido",
-		})
-	}
-
-	// Validar CiudadID
-	if estudiante.CiudadID == 0 {
-		errors = append(errors, ValidationError{
-			Field:   "ciudad_id",
-			Message: "El ID de la ciudad es requerido",
-		})
-	}
-
-	// Validar Especialidad (opcional pero si se proporciona debe ser válida)
-	if strings.TrimSpace(estudiante.Especialidad) != "" {
-		trimmedEspecialidad := strings.TrimSpace(estudiante.Especialidad)
-
-		// Validar longitud mínima
-		if len(trimmedEspecialidad) < 2 {
-			errors = append(errors, ValidationError{
-				Field:   "especialidad",
-				Message: "La especialidad debe tener al menos 2 caracteres",
-				Value:   estudiante.Especialidad,
-			})
-		}
-
-		// Validar longitud máxima
-		if len(trimmedEspecialidad) > 100 {
-			errors = append(errors, ValidationError{
-				Field:   "especialidad",
-				Message: "La especialidad no puede exceder 100 caracteres",
-				Value:   estudiante.Especialidad,
-			})
-		}
-	}
-
-	return errors
-}
-
-// validateEstudianteRequiredFields valida que los campos requeridos estén presentes
-func (h *EstudianteHandler) validateEstudianteRequiredFields(estudiante *models.Estudiante) []ValidationError {
-	var errors []ValidationError
-
-	if estudiante.PersonaID == 0 {
-		errors = append(errors, ValidationError{
-			Field:   "persona_id",
-			Message: "El campo persona_id es requerido",
-		})
-	}
-
-	if estudiante.InstitucionID == 0 {
-		errors = append(errors, ValidationError{
-			Field:   "institucion_id",
-			Message: "El campo institucion_id es requerido",
-		})
+// publicarEstudiante avisa a los webhooks suscritos que se registró un estudiante
+func (h *EstudianteHandler) publicarEstudiante(estudiante *models.Estudiante) {
+	if err := h.webhooks.Publicar(services.EventoEstudianteCreado, estudiante); err != nil {
+		log.Printf("Error al publicar el estudiante %d en los webhooks: %v", estudiante.ID, err)
 	}
-
-	if estudiante.CiudadID == 0 {
-		errors = append(errors, ValidationError{
-			Field:   "ciudad_id",
-			Message: "El campo ciudad_id es requerido",
-		})
-	}
-
-	return errors
 }
 
 // validateEstudianteSearchParams valida los parámetros de búsqueda
@@ -658,39 +559,6 @@ func (h *EstudianteHandler) validateEstudianteSearchParams(especialidad string)
 	return errors
 }
 
-// personaExists verifica si una persona existe en la base de datos
-func (h *EstudianteHandler) personaExists(personaID uint) bool {
-	if personaID == 0 {
-		return false
-	}
-
-	var count int64
-	err := h.personaRepo.GetDB().Model(&models.Persona{}).Where("id = ?", personaID).Count(&count).Error
-	return err == nil && count > 0
-}
-
-// institucionExists verifica si una institución existe en la base de datos
-func (h *EstudianteHandler) institucionExists(institucionID uint) bool {
-	if institucionID == 0 {
-		return false
-	}
-
-	var count int64
-	err := h.institucionRepo.GetDB().Model(&models.Institucion{}).Where("id = ?", institucionID).Count(&count).Error
-	return err == nil && count > 0
-}
-
-// ciudadExists verifica si una ciudad existe en la base de datos
-func (h *EstudianteHandler) ciudadExists(ciudadID uint) bool {
-	if ciudadID == 0 {
-		return false
-	}
-
-	var count int64
-	err := h.ciudadRepo.GetDB().Model(&models.Ciudad{}).Where("id = ?", ciudadID).Count(&count).Error
-	return err == nil && count > 0
-}
-
 // isValidJSON verifica si un slice de bytes contiene JSON válido
 func isValidJSON(data []byte) bool {
 	if len(data) == 0 {
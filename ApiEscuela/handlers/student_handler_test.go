@@ -0,0 +1,149 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"ApiEscuela/models"
+	"ApiEscuela/testutil"
+)
+
+func TestCreateEstudiante(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+		persona := f.Persona()
+
+		tests := []struct {
+			name          string
+			personaID     uint
+			institucionID uint
+			ciudadID      uint
+			wantStatus    int
+			wantError     string
+		}{
+			{name: "faltan campos", wantStatus: http.StatusBadRequest, wantError: "validation_error"},
+			{name: "persona inexistente", personaID: 9999, institucionID: e.Institucion.ID, ciudadID: e.Ciudad.ID, wantStatus: http.StatusBadRequest, wantError: "persona_no_existe"},
+			{name: "institución inexistente", personaID: persona.ID, institucionID: 9999, ciudadID: e.Ciudad.ID, wantStatus: http.StatusBadRequest, wantError: "institucion_no_existe"},
+			{name: "ciudad inexistente", personaID: persona.ID, institucionID: e.Institucion.ID, ciudadID: 9999, wantStatus: http.StatusBadRequest, wantError: "ciudad_no_existe"},
+			{name: "creación correcta", personaID: persona.ID, institucionID: e.Institucion.ID, ciudadID: e.Ciudad.ID, wantStatus: http.StatusCreated},
+			{name: "persona ya es estudiante", personaID: persona.ID, institucionID: e.Institucion.ID, ciudadID: e.Ciudad.ID, wantStatus: http.StatusConflict, wantError: "estudiante_duplicado"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				resp := testutil.Do(t, app, "POST", "/api/estudiantes", map[string]interface{}{
+					"persona_id":     tt.personaID,
+					"institucion_id": tt.institucionID,
+					"ciudad_id":      tt.ciudadID,
+					"especialidad":   "Bachillerato",
+				}, e.AdminToken)
+				if resp.Status != tt.wantStatus {
+					t.Fatalf("status = %d, want %d: %s", resp.Status, tt.wantStatus, resp.Body)
+				}
+				if tt.wantError != "" {
+					if got := resp.Map(t)["error"]; got != tt.wantError {
+						t.Errorf("error = %v, want %s", got, tt.wantError)
+					}
+				}
+			})
+		}
+	})
+}
+
+func TestEliminarYRestaurarEstudiante(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+
+		persona := f.Persona()
+		usuario := f.Usuario(persona, e.TipoEstudiante, "secreto1")
+		estudiante := f.Estudiante(persona, e.Institucion, e.Ciudad)
+
+		conDuda := f.Estudiante(f.Persona(), e.Institucion, e.Ciudad)
+		if err := repos.Dudas.CreateDudas(&models.Dudas{Pregunta: "¿Horario?", EstudianteID: conDuda.ID}); err != nil {
+			t.Fatal(err)
+		}
+
+		path := fmt.Sprintf("/api/estudiantes/%d", estudiante.ID)
+		tests := []struct {
+			name       string
+			method     string
+			path       string
+			wantStatus int
+		}{
+			{name: "inexistente", method: "GET", path: "/api/estudiantes/9999", wantStatus: http.StatusNotFound},
+			{name: "con dudas no se elimina", method: "DELETE", path: fmt.Sprintf("/api/estudiantes/%d", conDuda.ID), wantStatus: http.StatusConflict},
+			{name: "eliminar", method: "DELETE", path: path, wantStatus: http.StatusOK},
+			{name: "eliminado no se encuentra", method: "GET", path: path, wantStatus: http.StatusNotFound},
+			{name: "restaurar", method: "PUT", path: path + "/restore", wantStatus: http.StatusOK},
+			{name: "restaurado se encuentra", method: "GET", path: path, wantStatus: http.StatusOK},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				resp := testutil.Do(t, app, tt.method, tt.path, nil, e.AdminToken)
+				if resp.Status != tt.wantStatus {
+					t.Fatalf("status = %d, want %d: %s", resp.Status, tt.wantStatus, resp.Body)
+				}
+				if tt.name == "eliminar" {
+					// La eliminación es en cascada: el usuario ya no puede iniciar sesión
+					login := testutil.Do(t, app, "POST", "/auth/login", map[string]string{"usuario": usuario.Usuario, "contraseña": "secreto1"}, "")
+					if login.Status != http.StatusUnauthorized {
+						t.Errorf("login tras eliminar: status = %d: %s", login.Status, login.Body)
+					}
+				}
+			})
+		}
+
+		login := testutil.Do(t, app, "POST", "/auth/login", map[string]string{"usuario": usuario.Usuario, "contraseña": "secreto1"}, "")
+		if login.Status != http.StatusOK {
+			t.Errorf("login tras restaurar: status = %d: %s", login.Status, login.Body)
+		}
+	})
+}
+
+func TestCargaMasivaEstudiantes(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+		existente := f.Persona(func(p *models.Persona) { p.Cedula = "1200000030" })
+
+		fila := func(cedula, nombre string) map[string]interface{} {
+			return map[string]interface{}{
+				"cedula":         cedula,
+				"nombre":         nombre,
+				"institucion_id": e.Institucion.ID,
+				"ciudad_id":      e.Ciudad.ID,
+			}
+		}
+		resp := testutil.Do(t, app, "POST", "/api/estudiantes/bulk", map[string]interface{}{
+			"estudiantes": []map[string]interface{}{
+				fila("1200000006", "Ana"),
+				fila("1200000014", "Luis"),
+				fila("1200000001", "Dígito verificador incorrecto"),
+				fila(existente.Cedula, "Cédula repetida"),
+				fila("1200000022", ""),
+			},
+		}, e.AdminToken)
+		if resp.Status != http.StatusOK {
+			t.Fatalf("status = %d: %s", resp.Status, resp.Body)
+		}
+		var result struct {
+			TotalExitosos int `json:"total_exitosos"`
+			TotalFallidos int `json:"total_fallidos"`
+		}
+		resp.JSON(t, &result)
+		if result.TotalExitosos != 2 || result.TotalFallidos != 3 {
+			t.Fatalf("exitosos = %d, fallidos = %d: %s", result.TotalExitosos, result.TotalFallidos, resp.Body)
+		}
+
+		// La cédula es el usuario y la contraseña inicial
+		login := testutil.Do(t, app, "POST", "/auth/login", map[string]string{"usuario": "1200000006", "contraseña": "1200000006"}, "")
+		if login.Status != http.StatusOK {
+			t.Errorf("login del estudiante importado: status = %d: %s", login.Status, login.Body)
+		}
+	})
+}
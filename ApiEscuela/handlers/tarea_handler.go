@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"ApiEscuela/tareas"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type TareaHandler struct {
+	programador *tareas.Programador
+}
+
+func NewTareaHandler(programador *tareas.Programador) *TareaHandler {
+	return &TareaHandler{programador: programador}
+}
+
+// GetTareas lista las tareas programadas con su próxima ejecución, la última y el último error
+func (h *TareaHandler) GetTareas(c *fiber.Ctx) error {
+	estados, err := h.programador.Estados()
+	if err != nil {
+		return SendError(c, 500, "tasks_error", "No se pueden obtener las tareas", err.Error())
+	}
+	return c.JSON(fiber.Map{
+		"instancia": h.programador.InstanciaActual(),
+		"lider":     h.programador.EsLider(),
+		"tareas":    estados,
+	})
+}
+
+// GetEjecuciones devuelve el historial de una tarea (limite, por defecto 20)
+func (h *TareaHandler) GetEjecuciones(c *fiber.Ctx) error {
+	limite := c.QueryInt("limite", 20)
+	if limite <= 0 || limite > 500 {
+		return SendError(c, 400, "invalid_query", "limite debe estar entre 1 y 500")
+	}
+	ejecuciones, err := h.programador.Ejecuciones(c.Params("nombre"), limite)
+	if err != nil {
+		return h.errorTarea(c, err)
+	}
+	return c.JSON(ejecuciones)
+}
+
+// EjecutarTarea inicia una ejecución manual de la tarea en esta instancia; responde 202 con la
+// ejecución en curso, cuyo resultado se consulta en el historial
+func (h *TareaHandler) EjecutarTarea(c *fiber.Ctx) error {
+	ejecucion, err := h.programador.Ejecutar(c.Params("nombre"))
+	if err != nil {
+		return h.errorTarea(c, err)
+	}
+	return c.Status(fiber.StatusAccepted).JSON(ejecucion)
+}
+
+// errorTarea traduce los errores del programador a respuestas HTTP
+func (h *TareaHandler) errorTarea(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, tareas.ErrTareaNoEncontrada):
+		return SendError(c, 404, "not_found", "Tarea no encontrada")
+	case errors.Is(err, tareas.ErrTareaEnCurso):
+		return SendError(c, 409, "task_running", err.Error())
+	default:
+		return SendError(c, 500, "tasks_error", "Error en las tareas programadas", err.Error())
+	}
+}
@@ -0,0 +1,97 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"ApiEscuela/repositories"
+	"ApiEscuela/testutil"
+)
+
+func TestTareasProgramadasHTTP(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+
+		vencido := f.Codigo(e.Admin, "111111")
+		pasado := time.Now().Add(-time.Minute)
+		vencido.ExpiraEn = &pasado
+		if err := repos.CodigoUsuario.Update(vencido); err != nil {
+			t.Fatal(err)
+		}
+		vigente := f.Codigo(e.Admin, "222222")
+
+		estudiante := f.Usuario(f.Persona(), e.TipoEstudiante, "secreto1")
+		if res := testutil.Do(t, app, http.MethodGet, "/api/admin/tareas", nil, testutil.Token(t, estudiante)); res.Status != http.StatusForbidden {
+			t.Errorf("tareas con Estudiante = %d, se esperaba 403", res.Status)
+		}
+
+		var listado struct {
+			Lider  bool `json:"lider"`
+			Tareas []struct {
+				Nombre  string `json:"nombre"`
+				Proxima string `json:"proxima"`
+			} `json:"tareas"`
+		}
+		res := testutil.Do(t, app, http.MethodGet, "/api/admin/tareas", nil, e.AdminToken)
+		if res.Status != http.StatusOK {
+			t.Fatalf("tareas = %d: %s", res.Status, res.Body)
+		}
+		res.JSON(t, &listado)
+		nombres := map[string]bool{}
+		for _, tarea := range listado.Tareas {
+			nombres[tarea.Nombre] = tarea.Proxima != ""
+		}
+		for _, nombre := range []string{"expirar-codigos", "purgar-subidas", "recolectar-archivos", "recordatorios-visitas", "purgar-papelera"} {
+			if !nombres[nombre] {
+				t.Errorf("falta la tarea %s o su próxima ejecución: %s", nombre, res.Body)
+			}
+		}
+
+		res = testutil.Do(t, app, http.MethodPost, "/api/admin/tareas/expirar-codigos/ejecutar", nil, e.AdminToken)
+		if res.Status != http.StatusAccepted {
+			t.Fatalf("ejecutar = %d: %s", res.Status, res.Body)
+		}
+		if estado := res.Map(t)["estado"]; estado != repositories.EjecucionEnCurso {
+			t.Errorf("estado inicial = %v", estado)
+		}
+
+		// La ejecución sigue en segundo plano; el historial muestra cuándo termina
+		var ejecuciones []map[string]interface{}
+		for limite := time.Now().Add(5 * time.Second); ; {
+			res = testutil.Do(t, app, http.MethodGet, "/api/admin/tareas/expirar-codigos/ejecuciones", nil, e.AdminToken)
+			if res.Status != http.StatusOK {
+				t.Fatalf("ejecuciones = %d: %s", res.Status, res.Body)
+			}
+			res.JSON(t, &ejecuciones)
+			if len(ejecuciones) == 1 && ejecuciones[0]["estado"] != repositories.EjecucionEnCurso {
+				break
+			}
+			if time.Now().After(limite) {
+				t.Fatalf("la ejecución no terminó: %s", res.Body)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		if ejecuciones[0]["estado"] != repositories.EjecucionExito || ejecuciones[0]["disparo"] != "manual" || ejecuciones[0]["resultado"] != "1 códigos expirados" {
+			t.Errorf("ejecución inesperada: %v", ejecuciones[0])
+		}
+		for codigo, estado := range map[uint]string{vencido.ID: repositories.EstadoExpirado, vigente.ID: repositories.EstadoValido} {
+			rec, err := repos.CodigoUsuario.GetByID(codigo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rec.Estado != estado {
+				t.Errorf("código %d en estado %s, se esperaba %s", codigo, rec.Estado, estado)
+			}
+		}
+
+		if res := testutil.Do(t, app, http.MethodPost, "/api/admin/tareas/no-existe/ejecutar", nil, e.AdminToken); res.Status != http.StatusNotFound {
+			t.Errorf("tarea inexistente = %d, se esperaba 404", res.Status)
+		}
+		if res := testutil.Do(t, app, http.MethodGet, "/api/admin/tareas/expirar-codigos/ejecuciones?limite=0", nil, e.AdminToken); res.Status != http.StatusBadRequest {
+			t.Errorf("limite=0 = %d, se esperaba 400", res.Status)
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"ApiEscuela/middleware"
 	"ApiEscuela/models"
 	"ApiEscuela/repositories"
 	"strconv"
@@ -9,10 +10,10 @@ import (
 )
 
 type TematicaHandler struct {
-	tematicaRepo *repositories.TematicaRepository
+	tematicaRepo repositories.TematicaRepository
 }
 
-func NewTematicaHandler(tematicaRepo *repositories.TematicaRepository) *TematicaHandler {
+func NewTematicaHandler(tematicaRepo repositories.TematicaRepository) *TematicaHandler {
 	return &TematicaHandler{tematicaRepo: tematicaRepo}
 }
 
@@ -97,6 +98,11 @@ func (h *TematicaHandler) UpdateTematica(c *fiber.Ctx) error {
 	return c.JSON(tematica)
 }
 
+// PatchTematica actualiza solo los campos enviados de una temática (JSON Merge Patch)
+func (h *TematicaHandler) PatchTematica(c *fiber.Ctx) error {
+	return mergePatch(c, cargarCon(h.tematicaRepo.GetTematicaByID), h.UpdateTematica)
+}
+
 // DeleteTematica elimina una temática
 func (h *TematicaHandler) DeleteTematica(c *fiber.Ctx) error {
 	id, err := strconv.Atoi(c.Params("id"))
@@ -117,9 +123,9 @@ func (h *TematicaHandler) DeleteTematica(c *fiber.Ctx) error {
 	})
 }
 
-// GetTematicasByNombre busca temáticas por nombre
+// GetTematicasByNombre busca temáticas por nombre sin distinguir tildes, ordenadas por relevancia
 func (h *TematicaHandler) GetTematicasByNombre(c *fiber.Ctx) error {
-	nombre := c.Params("nombre")
+	nombre := paramBusqueda(c, "nombre")
 	
 	tematicas, err := h.tematicaRepo.GetTematicasByNombre(nombre)
 	if err != nil {
@@ -128,6 +134,20 @@ func (h *TematicaHandler) GetTematicasByNombre(c *fiber.Ctx) error {
 		})
 	}
 
+	return c.JSON(repositories.Registros(tematicas))
+}
+
+// GetTematicasByNombreV2 busca temáticas por nombre con la relevancia y un fragmento resaltado (respuesta v2)
+func (h *TematicaHandler) GetTematicasByNombreV2(c *fiber.Ctx) error {
+	nombre := paramBusqueda(c, "nombre")
+
+	tematicas, err := h.tematicaRepo.GetTematicasByNombre(nombre)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "No se pueden obtener las temáticas",
+		})
+	}
+
 	return c.JSON(tematicas)
 }
 
@@ -143,4 +163,13 @@ func (h *TematicaHandler) GetTematicasByDescripcion(c *fiber.Ctx) error {
 	}
 
 	return c.JSON(tematicas)
-}
\ No newline at end of file
+}
+
+// Version calcula la versión de la lista de las temáticas para las peticiones condicionales
+func (h *TematicaHandler) Version() (middleware.Version, error) {
+	lista, err := h.tematicaRepo.GetAllTematicas()
+	if err != nil {
+		return middleware.Version{}, err
+	}
+	return versionDe("tematicas", lista), nil
+}
@@ -9,10 +9,10 @@ import (
 )
 
 type TipoUsuarioHandler struct {
-	tipoUsuarioRepo *repositories.TipoUsuarioRepository
+	tipoUsuarioRepo repositories.TipoUsuarioRepository
 }
 
-func NewTipoUsuarioHandler(tipoUsuarioRepo *repositories.TipoUsuarioRepository) *TipoUsuarioHandler {
+func NewTipoUsuarioHandler(tipoUsuarioRepo repositories.TipoUsuarioRepository) *TipoUsuarioHandler {
 	return &TipoUsuarioHandler{tipoUsuarioRepo: tipoUsuarioRepo}
 }
 
@@ -97,6 +97,11 @@ func (h *TipoUsuarioHandler) UpdateTipoUsuario(c *fiber.Ctx) error {
 	return c.JSON(tipoUsuario)
 }
 
+// PatchTipoUsuario actualiza solo los campos enviados de un tipo de usuario (JSON Merge Patch)
+func (h *TipoUsuarioHandler) PatchTipoUsuario(c *fiber.Ctx) error {
+	return mergePatch(c, cargarCon(h.tipoUsuarioRepo.GetTipoUsuarioByID), h.UpdateTipoUsuario)
+}
+
 // DeleteTipoUsuario elimina un tipo de usuario
 func (h *TipoUsuarioHandler) DeleteTipoUsuario(c *fiber.Ctx) error {
 	id, err := strconv.Atoi(c.Params("id"))
@@ -129,4 +134,12 @@ func (h *TipoUsuarioHandler) GetTipoUsuarioByNombre(c *fiber.Ctx) error {
 	}
 
 	return c.JSON(tipoUsuario)
-}
\ No newline at end of file
+}
+// NombreTipoUsuario devuelve el nombre del tipo de usuario; se usa para verificar roles en las rutas
+func (h *TipoUsuarioHandler) NombreTipoUsuario(id uint) (string, error) {
+	tipoUsuario, err := h.tipoUsuarioRepo.GetTipoUsuarioByID(id)
+	if err != nil {
+		return "", err
+	}
+	return tipoUsuario.Nombre, nil
+}
@@ -1,21 +1,37 @@
 package handlers
 
 import (
+	"ApiEscuela/imagenes"
+	"ApiEscuela/inspeccion"
+	"ApiEscuela/models"
+	"ApiEscuela/services"
+	"ApiEscuela/storage"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
-	"mime/multipart"
+	"io"
+	"log"
+	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
 
-type UploadHandler struct{}
+type UploadHandler struct {
+	archivos  storage.Storage
+	subidas   services.UploadService
+	inspector *inspeccion.Inspector
+	media     services.MediaService
+}
 
-func NewUploadHandler() *UploadHandler {
-	return &UploadHandler{}
+func NewUploadHandler(archivos storage.Storage, subidas services.UploadService, inspector *inspeccion.Inspector, media services.MediaService) *UploadHandler {
+	return &UploadHandler{archivos: archivos, subidas: subidas, inspector: inspector, media: media}
 }
 
 // UploadFile maneja la subida de archivos y retorna la URL
@@ -40,61 +56,99 @@ func (h *UploadHandler) UploadFile(c *fiber.Ctx) error {
 	// Debug: Log del userID obtenido
 	fmt.Printf("DEBUG: UserID obtenido del token: %d\n", userID)
 
-	// Validar el archivo
-	if err := h.validarArchivo(file); err != nil {
+	// Validar el nombre y el tamaño antes de leer el contenido
+	if _, err := inspeccion.Validar(file.Filename, file.Size); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
-
-	// Determinar el tipo de archivo y carpeta
-	tipoArchivo, carpetaDestino := h.determinarTipoYCarpeta(file.Header.Get("Content-Type"))
-	if carpetaDestino == "" {
+	visibilidad, err := services.ValidarVisibilidad(c.FormValue("visibilidad"))
+	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"error": "Tipo de archivo no soportado",
+			"error": err.Error(),
 		})
 	}
 
-	// Generar nombre único para el archivo
-	extension := filepath.Ext(file.Filename)
-	nombreArchivo := h.generarNombreArchivo(extension)
-
-	// Crear la ruta completa
-	rutaCompleta := filepath.Join("assets", carpetaDestino, nombreArchivo)
+	contenido, err := file.Open()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Error al leer el archivo",
+		})
+	}
+	defer contenido.Close()
 
-	// Crear el directorio si no existe
-	if err := os.MkdirAll(filepath.Dir(rutaCompleta), 0755); err != nil {
+	// Si ya se subió un archivo idéntico se devuelve ese en lugar de guardar una copia
+	h256 := sha256.New()
+	if _, err := io.Copy(h256, contenido); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Error al crear directorio",
+			"error": "Error al leer el archivo",
 		})
 	}
+	suma := hex.EncodeToString(h256.Sum(nil))
+	duplicado, err := h.media.BuscarDuplicado(c.UserContext(), suma, visibilidad)
+	if err != nil {
+		log.Printf("Error al buscar duplicados de %s: %v", file.Filename, err)
+	}
+	if duplicado != nil {
+		respuesta := fiber.Map{
+			"message":   "El archivo ya existía y se reutilizó",
+			"url":       h.urlPublica(c, duplicado.Clave),
+			"tipo":      duplicado.Categoria,
+			"tamano":    duplicado.Tamano,
+			"sha256":    suma,
+			"duplicado": true,
+		}
+		agregarMedia(respuesta, duplicado)
+		return c.JSON(respuesta)
+	}
+
+	// Verificar el contenido real (el Content-Type del cliente no se usa), buscar virus y
+	// quitar los metadatos de las imágenes
+	archivo, err := h.inspector.Preparar(c.UserContext(), file.Filename, contenido, file.Size)
+	if err != nil {
+		return h.errorInspeccion(c, err)
+	}
 
-	// Guardar el archivo
-	if err := c.SaveFile(file, rutaCompleta); err != nil {
+	// Generar nombre único para el archivo
+	extension := strings.ToLower(filepath.Ext(file.Filename))
+	nombreArchivo := h.generarNombreArchivo(extension)
+
+	// Guardar el archivo en el almacenamiento configurado
+	clave := archivo.Tipo.Carpeta + "/" + nombreArchivo
+	if err := h.archivos.Guardar(c.UserContext(), clave, archivo.Contenido, archivo.Tamano, archivo.Tipo.ContentType); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Error al guardar el archivo",
 		})
 	}
 
-	// Generar URL publica
-	baseURL := os.Getenv("BASE_URL")
-	if baseURL == "" {
-		// Fallback: construir la URL desde el request
-		baseURL = c.BaseURL()
-		// Si detectamos que estamos en el servidor de produccion, agregar el puerto
-		if strings.Contains(c.Get("Host"), ":9612") {
-			baseURL = strings.Replace(baseURL, "aplicaciones.uteq.edu.ec", "aplicaciones.uteq.edu.ec:9612", 1)
-		}
+	respuesta := fiber.Map{
+		"message":   "Archivo subido exitosamente",
+		"url":       h.urlPublica(c, clave),
+		"tipo":      archivo.Tipo.Categoria,
+		"tamano":    archivo.Tamano,
+		"sha256":    suma,
+		"duplicado": false,
 	}
 
-	urlArchivo := fmt.Sprintf("%s/api/files/%s/%s", baseURL, carpetaDestino, nombreArchivo)
-
-	return c.JSON(fiber.Map{
-		"message": "Archivo subido exitosamente",
-		"url":     urlArchivo,
-		"tipo":    tipoArchivo,
-		"tamano":  file.Size,
+	// Registrar el archivo en la biblioteca y generar las variantes de las imágenes; sin registro
+	// el archivo se sirve igual
+	media, err := h.media.RegistrarArchivo(c.UserContext(), services.NuevoMedia{
+		Clave:       clave,
+		Nombre:      filepath.Base(file.Filename),
+		Categoria:   archivo.Tipo.Categoria,
+		ContentType: archivo.Tipo.ContentType,
+		Tamano:      archivo.Tamano,
+		Sha256:      suma,
+		UsuarioID:   userID,
+		Visibilidad: visibilidad,
+		Datos:       archivo.Datos,
 	})
+	if err != nil {
+		log.Printf("Error al registrar %s en la biblioteca: %v", clave, err)
+	}
+	agregarMedia(respuesta, media)
+
+	return c.JSON(respuesta)
 }
 
 // GetFile sirve archivos estáticos
@@ -123,70 +177,275 @@ func (h *UploadHandler) GetFile(c *fiber.Ctx) error {
 		}
 	}
 
-	// Construir la ruta completa del archivo
-	var rutaCompleta string
-	if decodedSubcarpeta != "" {
-		// Para rutas con subcarpeta: assets/{tipo}/{subcarpeta}/{nombre}
-		rutaCompleta = filepath.Join("assets", tipo, decodedSubcarpeta, decodedNombre)
-	} else {
-		// Para rutas simples: assets/{tipo}/{nombre}
-		rutaCompleta = filepath.Join("assets", tipo, decodedNombre)
+	// Construir la clave del archivo: {tipo}/{nombre} o {tipo}/{subcarpeta}/{nombre}
+	clave, err := storage.Clave(tipo, decodedSubcarpeta, decodedNombre)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Ruta de archivo inválida",
+		})
+	}
+
+	// Las variantes solo se sirven con ?size o ?w, para aplicar la visibilidad de su imagen
+	if services.EsVariante(clave) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Archivo no encontrado",
+		})
+	}
+	// Los archivos privados solo se sirven a usuarios autenticados
+	media, err := h.media.BuscarPorClave(clave)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Error al leer el archivo",
+		})
+	}
+	privado := media != nil && media.Visibilidad == services.VisibilidadPrivada
+	if _, autenticado := c.Locals("user_id").(uint); privado && !autenticado {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Archivo no encontrado",
+		})
 	}
 
-	// Verificar que el archivo existe físicamente
-	if _, err := os.Stat(rutaCompleta); os.IsNotExist(err) {
+	// Variante redimensionada de una imagen: ?size=thumb|medium|large o ?w=<ancho mínimo>
+	if tamano, ancho := c.Query("size"), c.Query("w"); tamano != "" || ancho != "" {
+		if tamano != "" {
+			if _, ok := imagenes.TamanoPorNombre(tamano); !ok {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": "size debe ser thumb, medium o large",
+				})
+			}
+		}
+		anchoMinimo := 0
+		if ancho != "" {
+			if anchoMinimo, err = strconv.Atoi(ancho); err != nil || anchoMinimo <= 0 {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": "w debe ser un ancho en píxeles",
+				})
+			}
+		}
+		variante, err := h.media.BuscarVariante(clave, tamano, anchoMinimo, strings.Contains(c.Get(fiber.HeaderAccept), "image/webp"))
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Error al buscar la variante de la imagen",
+			})
+		}
+		if variante != nil {
+			clave = variante.Clave
+		}
+		// La variante elegida depende de si el cliente acepta WebP
+		c.Vary(fiber.HeaderAccept)
+	}
+
+	contenido, obj, err := h.archivos.Abrir(c.UserContext(), clave)
+	if errors.Is(err, storage.ErrNoEncontrado) {
 		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
 			"error": "Archivo no encontrado",
 		})
 	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Error al leer el archivo",
+		})
+	}
 
-	// Servir el archivo
-	return c.SendFile(rutaCompleta)
+	// Servir el archivo desde el almacenamiento (el stream se cierra al terminar la respuesta)
+	c.Set(fiber.HeaderContentType, obj.ContentType)
+	if privado {
+		c.Set(fiber.HeaderCacheControl, "private, no-cache")
+	}
+	if !obj.Modificado.IsZero() {
+		c.Set(fiber.HeaderLastModified, obj.Modificado.UTC().Format(http.TimeFormat))
+	}
+	return c.SendStream(contenido, int(obj.Tamano))
 }
 
 // Funciones auxiliares
 
-func (h *UploadHandler) validarArchivo(file *multipart.FileHeader) error {
-	// Validar tamaño (máximo 50MB)
-	const maxSize = 50 * 1024 * 1024
-	if file.Size > maxSize {
-		return fmt.Errorf("el archivo es demasiado grande (máximo 50MB)")
+// errorInspeccion responde 400 si el archivo no es del tipo declarado, 422 si contiene un virus
+// y 503 si no se pudo analizar
+func (h *UploadHandler) errorInspeccion(c *fiber.Ctx, err error) error {
+	var amenaza *inspeccion.Amenaza
+	switch {
+	case errors.As(err, &amenaza):
+		log.Printf("Archivo rechazado por el escáner de virus: %s (usuario %v)", amenaza.Firma, c.Locals("user_id"))
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	case inspeccion.EsRechazo(err):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	default:
+		log.Printf("Error al inspeccionar el archivo: %v", err)
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "No se pudo analizar el archivo, intente nuevamente",
+		})
 	}
+}
 
-	// Validar extensión
-	extension := strings.ToLower(filepath.Ext(file.Filename))
-	extensionesPermitidas := []string{".jpg", ".jpeg", ".png", ".gif", ".mp4", ".avi", ".mov", ".pdf", ".doc", ".docx", ".txt"}
+// agregarMedia agrega a la respuesta de una subida el ID del archivo en la biblioteca, su
+// visibilidad y, en las imágenes, las dimensiones y el BlurHash
+func agregarMedia(respuesta fiber.Map, media *models.Media) {
+	if media == nil {
+		return
+	}
+	respuesta["id"] = media.ID
+	respuesta["visibilidad"] = media.Visibilidad
+	if media.Ancho > 0 {
+		respuesta["ancho"] = media.Ancho
+		respuesta["alto"] = media.Alto
+		respuesta["blurhash"] = media.Blurhash
+	}
+}
 
-	permitida := false
-	for _, ext := range extensionesPermitidas {
-		if extension == ext {
-			permitida = true
-			break
+// urlPublica construye la URL con la que se descarga un archivo del almacenamiento
+func (h *UploadHandler) urlPublica(c *fiber.Ctx, clave string) string {
+	baseURL := os.Getenv("BASE_URL")
+	if baseURL == "" {
+		// Fallback: construir la URL desde el request
+		baseURL = c.BaseURL()
+		// Si detectamos que estamos en el servidor de produccion, agregar el puerto
+		if strings.Contains(c.Get("Host"), ":9612") {
+			baseURL = strings.Replace(baseURL, "aplicaciones.uteq.edu.ec", "aplicaciones.uteq.edu.ec:9612", 1)
 		}
 	}
+	return fmt.Sprintf("%s/api/files/%s", baseURL, clave)
+}
 
-	if !permitida {
-		return fmt.Errorf("tipo de archivo no permitido")
+func (h *UploadHandler) generarNombreArchivo(extension string) string {
+	timestamp := time.Now().Unix()
+	random := time.Now().UnixNano() % 10000
+	return fmt.Sprintf("%d_%d%s", timestamp, random, extension)
+}
+
+// Subidas por partes: permiten enviar archivos más grandes que el límite del cuerpo de la
+// petición en fragmentos, y reanudar la subida si se corta la conexión.
+
+// CreateUploadSession inicia una subida por partes
+func (h *UploadHandler) CreateUploadSession(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(uint)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Usuario no autenticado",
+		})
+	}
+
+	var datos services.NuevaSubida
+	if err := c.BodyParser(&datos); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "No se puede procesar el JSON",
+		})
+	}
+
+	sesion, err := h.subidas.CreateUpload(userID, datos)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
 	}
 
-	return nil
+	c.Set("Location", c.BaseURL()+c.Path()+"/"+sesion.ID)
+	c.Set("Upload-Offset", "0")
+	return c.Status(fiber.StatusCreated).JSON(sesion)
 }
 
-func (h *UploadHandler) determinarTipoYCarpeta(mimeType string) (string, string) {
+// GetUploadSession devuelve cuántos bytes se recibieron de una subida, para reanudarla
+func (h *UploadHandler) GetUploadSession(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(uint)
+
+	sesion, err := h.subidas.GetUpload(userID, c.Params("id"))
+	if err != nil {
+		return h.errorSubida(c, err)
+	}
+
+	c.Set("Upload-Offset", strconv.FormatInt(sesion.Offset, 10))
+	c.Set("Upload-Length", strconv.FormatInt(sesion.Tamano, 10))
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	return c.JSON(sesion)
+}
+
+// UploadChunk recibe un fragmento en el cuerpo crudo de la petición. El encabezado
+// Upload-Offset indica dónde empieza y Upload-Checksum ("sha256 <base64>") es opcional.
+func (h *UploadHandler) UploadChunk(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(uint)
+
+	offset, err := strconv.ParseInt(c.Get("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "El encabezado Upload-Offset es requerido",
+		})
+	}
+
+	sesion, err := h.subidas.WriteChunk(userID, c.Params("id"), offset, c.Body(), c.Get("Upload-Checksum"))
+	if err != nil {
+		return h.errorSubida(c, err)
+	}
+
+	c.Set("Upload-Offset", strconv.FormatInt(sesion.Offset, 10))
+	return c.JSON(sesion)
+}
+
+// FinalizeUploadSession verifica la subida completa y la guarda como un archivo normal
+func (h *UploadHandler) FinalizeUploadSession(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(uint)
+
+	archivo, err := h.subidas.FinalizeUpload(userID, c.Params("id"))
 	switch {
-	case strings.HasPrefix(mimeType, "image/"):
-		return "image", "images"
-	case strings.HasPrefix(mimeType, "video/"):
-		return "video", "videos"
-	case strings.HasPrefix(mimeType, "application/") || strings.HasPrefix(mimeType, "text/"):
-		return "document", "documents"
-	default:
-		return "", ""
+	case errors.Is(err, services.ErrSubidaNoEncontrada), errors.Is(err, services.ErrSubidaIncompleta), errors.Is(err, services.ErrChecksumInvalido):
+		return h.errorSubida(c, err)
+	case err != nil:
+		// Archivo rechazado, o falla del escáner o del almacenamiento (la subida se puede reintentar)
+		return h.errorInspeccion(c, err)
+	}
+
+	mensaje := "Archivo subido exitosamente"
+	if archivo.Duplicado {
+		mensaje = "El archivo ya existía y se reutilizó"
 	}
+	respuesta := fiber.Map{
+		"message":   mensaje,
+		"url":       h.urlPublica(c, archivo.Clave),
+		"tipo":      archivo.Tipo,
+		"tamano":    archivo.Tamano,
+		"sha256":    archivo.Sha256,
+		"duplicado": archivo.Duplicado,
+	}
+	agregarMedia(respuesta, archivo.Media)
+	return c.JSON(respuesta)
 }
 
-func (h *UploadHandler) generarNombreArchivo(extension string) string {
-	timestamp := time.Now().Unix()
-	random := time.Now().UnixNano() % 10000
-	return fmt.Sprintf("%d_%d%s", timestamp, random, extension)
+// CancelUploadSession descarta una subida por partes
+func (h *UploadHandler) CancelUploadSession(c *fiber.Ctx) error {
+	userID, _ := c.Locals("user_id").(uint)
+
+	if err := h.subidas.CancelUpload(userID, c.Params("id")); err != nil {
+		return h.errorSubida(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// errorSubida traduce los errores de las subidas por partes a respuestas HTTP
+func (h *UploadHandler) errorSubida(c *fiber.Ctx, err error) error {
+	var offsetInvalido *services.ErrOffsetInvalido
+	switch {
+	case errors.Is(err, services.ErrSubidaNoEncontrada):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.As(err, &offsetInvalido):
+		c.Set("Upload-Offset", strconv.FormatInt(offsetInvalido.Esperado, 10))
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error":  err.Error(),
+			"offset": offsetInvalido.Esperado,
+		})
+	case errors.Is(err, services.ErrChecksumInvalido):
+		// 460 es el código que usa tus para un checksum que no coincide
+		return c.Status(460).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, services.ErrSubidaIncompleta):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, services.ErrFragmentoExcedido):
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{"error": err.Error()})
+	case inspeccion.EsRechazo(err):
+		return h.errorInspeccion(c, err)
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	}
 }
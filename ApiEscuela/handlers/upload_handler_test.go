@@ -0,0 +1,341 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"ApiEscuela/inspeccion/clamdtest"
+	"ApiEscuela/storage"
+	"ApiEscuela/testutil"
+)
+
+// pngDePrueba genera una imagen PNG válida de 2x2 píxeles
+func pngDePrueba(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestSubirYServirArchivo(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+
+		logo := pngDePrueba(t)
+		// El Content-Type del cliente se ignora: el tipo se detecta por el contenido
+		res := testutil.DoFile(t, app, http.MethodPost, "/api/upload", "file", "logo.png", "application/octet-stream", logo, e.AdminToken)
+		if res.Status != http.StatusOK {
+			t.Fatalf("upload = %d: %s", res.Status, res.Body)
+		}
+		var subida struct {
+			URL  string `json:"url"`
+			Tipo string `json:"tipo"`
+		}
+		res.JSON(t, &subida)
+		i := strings.Index(subida.URL, "/api/files/images/")
+		if i < 0 || subida.Tipo != "image" {
+			t.Fatalf("respuesta = %+v", subida)
+		}
+
+		res = testutil.Do(t, app, http.MethodGet, subida.URL[i:], nil, "")
+		if res.Status != http.StatusOK || !bytes.HasPrefix(res.Body, []byte("\x89PNG")) || res.Header.Get("Content-Type") != "image/png" {
+			t.Errorf("GET archivo = %d %q %s", res.Status, res.Body, res.Header.Get("Content-Type"))
+		}
+		if res := testutil.Do(t, app, http.MethodGet, "/api/files/images/no-existe.png", nil, ""); res.Status != http.StatusNotFound {
+			t.Errorf("archivo inexistente = %d, se esperaba 404", res.Status)
+		}
+		if res := testutil.Do(t, app, http.MethodGet, "/api/files/images/..%2F..%2Fconfig.env", nil, ""); res.Status != http.StatusBadRequest {
+			t.Errorf("ruta fuera del almacenamiento = %d, se esperaba 400", res.Status)
+		}
+	})
+}
+
+func TestVariantesDeImagenHTTP(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+
+		img := image.NewNRGBA(image.Rect(0, 0, 1000, 500))
+		for i := range img.Pix {
+			img.Pix[i] = uint8(i)
+		}
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			t.Fatal(err)
+		}
+		res := testutil.DoFile(t, app, http.MethodPost, "/api/upload", "file", "portada.png", "image/png", buf.Bytes(), e.AdminToken)
+		if res.Status != http.StatusOK {
+			t.Fatalf("upload = %d: %s", res.Status, res.Body)
+		}
+		var subida struct {
+			URL      string `json:"url"`
+			Ancho    int    `json:"ancho"`
+			Alto     int    `json:"alto"`
+			Blurhash string `json:"blurhash"`
+		}
+		res.JSON(t, &subida)
+		if subida.Ancho != 1000 || subida.Alto != 500 || subida.Blurhash == "" {
+			t.Fatalf("respuesta = %+v", subida)
+		}
+		ruta := subida.URL[strings.Index(subida.URL, "/api/files/"):]
+
+		res = testutil.Do(t, app, http.MethodGet, ruta+"?size=thumb", nil, "")
+		if res.Status != http.StatusOK || res.Header.Get("Content-Type") != "image/jpeg" {
+			t.Fatalf("thumb = %d %s", res.Status, res.Header.Get("Content-Type"))
+		}
+		cfg, _, err := image.DecodeConfig(bytes.NewReader(res.Body))
+		if err != nil || cfg.Width != 320 || cfg.Height != 160 {
+			t.Errorf("thumb = %+v, %v", cfg, err)
+		}
+		if !strings.Contains(res.Header.Get("Vary"), "Accept") {
+			t.Errorf("Vary = %q", res.Header.Get("Vary"))
+		}
+
+		// Con Accept image/webp se sirve la variante WebP si existe; si no, la JPEG
+		res = testutil.DoHeaders(t, app, http.MethodGet, ruta+"?w=500", map[string]string{"Accept": "image/webp,*/*"}, "")
+		if ct := res.Header.Get("Content-Type"); res.Status != http.StatusOK || (ct != "image/webp" && ct != "image/jpeg") {
+			t.Errorf("w=500 = %d %s", res.Status, ct)
+		}
+		// Más ancho que cualquier variante: el original
+		res = testutil.Do(t, app, http.MethodGet, ruta+"?w=5000", nil, "")
+		if res.Status != http.StatusOK || res.Header.Get("Content-Type") != "image/png" {
+			t.Errorf("w=5000 = %d %s", res.Status, res.Header.Get("Content-Type"))
+		}
+
+		for _, q := range []string{"?size=gigante", "?w=abc", "?w=0"} {
+			if res := testutil.Do(t, app, http.MethodGet, ruta+q, nil, ""); res.Status != http.StatusBadRequest {
+				t.Errorf("%s = %d, se esperaba 400", q, res.Status)
+			}
+		}
+	})
+}
+
+func TestSubirArchivoRechazado(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+
+		casos := []struct {
+			nombre, contentType string
+			contenido           []byte
+			status              int
+		}{
+			{"acta.pdf", "application/pdf", []byte("MZ\x90\x00 ejecutable"), http.StatusBadRequest},
+			{"foto.jpg", "image/jpeg", pngDePrueba(t), http.StatusBadRequest},
+			{"programa.exe", "application/octet-stream", []byte("MZ"), http.StatusBadRequest},
+			{"notas.txt", "text/plain", []byte(clamdtest.EICAR), http.StatusUnprocessableEntity},
+		}
+		for _, c := range casos {
+			res := testutil.DoFile(t, app, http.MethodPost, "/api/upload", "file", c.nombre, c.contentType, c.contenido, e.AdminToken)
+			if res.Status != c.status {
+				t.Errorf("%s = %d: %s; se esperaba %d", c.nombre, res.Status, res.Body, c.status)
+			}
+		}
+		guardados := 0
+		repos.Archivos.Listar(context.Background(), "", func(storage.Objeto) error { guardados++; return nil })
+		if guardados != 0 {
+			t.Errorf("se guardaron %d archivos rechazados", guardados)
+		}
+	})
+}
+
+func TestSubidaPorPartesHTTP(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+
+		// Más grande que el límite de 4MB del cuerpo de una petición normal
+		contenido := append([]byte("\x00\x00\x00\x18ftypisom\x00\x00\x02\x00isomiso2"), bytes.Repeat([]byte("video!"), 1024*1024)...)
+		suma := sha256.Sum256(contenido)
+		res := testutil.Do(t, app, http.MethodPost, "/api/upload/sesiones", map[string]interface{}{
+			"nombre": "clase.mp4", "tamano": len(contenido), "sha256": hex.EncodeToString(suma[:]),
+		}, e.AdminToken)
+		if res.Status != http.StatusCreated {
+			t.Fatalf("crear sesión = %d: %s", res.Status, res.Body)
+		}
+		var sesion struct {
+			ID     string `json:"id"`
+			Offset int64  `json:"offset"`
+		}
+		res.JSON(t, &sesion)
+		ruta := "/api/upload/sesiones/" + sesion.ID
+
+		const fragmento = 1024 * 1024
+		for offset := 0; offset < len(contenido); offset += fragmento {
+			parte := contenido[offset:min(offset+fragmento, len(contenido))]
+			sumaParte := sha256.Sum256(parte)
+			res = testutil.DoRaw(t, app, http.MethodPatch, ruta, map[string]string{
+				"Content-Type":    "application/offset+octet-stream",
+				"Upload-Offset":   strconv.Itoa(offset),
+				"Upload-Checksum": "sha256 " + base64.StdEncoding.EncodeToString(sumaParte[:]),
+			}, parte, e.AdminToken)
+			if res.Status != http.StatusOK || res.Header.Get("Upload-Offset") != strconv.Itoa(offset+len(parte)) {
+				t.Fatalf("PATCH offset %d = %d %s: %s", offset, res.Status, res.Header.Get("Upload-Offset"), res.Body)
+			}
+		}
+
+		// Reenviar un fragmento ya recibido indica desde dónde continuar
+		res = testutil.DoRaw(t, app, http.MethodPatch, ruta, map[string]string{"Upload-Offset": "0"}, []byte("x"), e.AdminToken)
+		if res.Status != http.StatusConflict || res.Header.Get("Upload-Offset") != strconv.Itoa(len(contenido)) {
+			t.Errorf("offset repetido = %d %s", res.Status, res.Header.Get("Upload-Offset"))
+		}
+		res = testutil.DoHeaders(t, app, http.MethodHead, ruta, nil, e.AdminToken)
+		if res.Status != http.StatusOK || res.Header.Get("Upload-Offset") != strconv.Itoa(len(contenido)) {
+			t.Errorf("HEAD sesión = %d %s", res.Status, res.Header.Get("Upload-Offset"))
+		}
+
+		res = testutil.Do(t, app, http.MethodPost, ruta+"/finalizar", nil, e.AdminToken)
+		if res.Status != http.StatusOK {
+			t.Fatalf("finalizar = %d: %s", res.Status, res.Body)
+		}
+		var subida struct {
+			URL  string `json:"url"`
+			Tipo string `json:"tipo"`
+		}
+		res.JSON(t, &subida)
+		i := strings.Index(subida.URL, "/api/files/videos/")
+		if i < 0 || subida.Tipo != "video" {
+			t.Fatalf("respuesta = %+v", subida)
+		}
+		res = testutil.Do(t, app, http.MethodGet, subida.URL[i:], nil, "")
+		if res.Status != http.StatusOK || !bytes.Equal(res.Body, contenido) {
+			t.Errorf("GET archivo = %d, %d bytes", res.Status, len(res.Body))
+		}
+
+		if res := testutil.Do(t, app, http.MethodGet, ruta, nil, e.AdminToken); res.Status != http.StatusNotFound {
+			t.Errorf("sesión finalizada = %d, se esperaba 404", res.Status)
+		}
+	})
+}
+
+func TestCancelarSubidaPorPartes(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+
+		res := testutil.Do(t, app, http.MethodPost, "/api/upload/sesiones", map[string]interface{}{
+			"nombre": "programa.exe", "tamano": 10,
+		}, e.AdminToken)
+		if res.Status != http.StatusBadRequest {
+			t.Errorf("extensión no permitida = %d, se esperaba 400", res.Status)
+		}
+
+		res = testutil.Do(t, app, http.MethodPost, "/api/upload/sesiones", map[string]interface{}{
+			"nombre": "acta.pdf", "tamano": 10,
+		}, e.AdminToken)
+		var sesion struct {
+			ID string `json:"id"`
+		}
+		res.JSON(t, &sesion)
+		ruta := "/api/upload/sesiones/" + sesion.ID
+
+		if res := testutil.Do(t, app, http.MethodDelete, ruta, nil, e.AdminToken); res.Status != http.StatusNoContent {
+			t.Fatalf("cancelar = %d: %s", res.Status, res.Body)
+		}
+		if res := testutil.Do(t, app, http.MethodGet, ruta, nil, e.AdminToken); res.Status != http.StatusNotFound {
+			t.Errorf("sesión cancelada = %d, se esperaba 404", res.Status)
+		}
+	})
+}
+
+func TestBibliotecaDeArchivosHTTP(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+
+		subir := func() (url string, id float64, duplicado bool) {
+			t.Helper()
+			var buf bytes.Buffer
+			w := multipart.NewWriter(&buf)
+			w.WriteField("visibilidad", "privado")
+			part, _ := w.CreateFormFile("file", "notas.txt")
+			part.Write([]byte("calificaciones del primer parcial"))
+			w.Close()
+			res := testutil.DoRaw(t, app, http.MethodPost, "/api/upload", map[string]string{"Content-Type": w.FormDataContentType()}, buf.Bytes(), e.AdminToken)
+			if res.Status != http.StatusOK {
+				t.Fatalf("upload = %d: %s", res.Status, res.Body)
+			}
+			m := res.Map(t)
+			return m["url"].(string), m["id"].(float64), m["duplicado"].(bool)
+		}
+		url, id, duplicado := subir()
+		if duplicado {
+			t.Error("la primera subida no es un duplicado")
+		}
+		// Un archivo idéntico no se guarda dos veces
+		url2, id2, duplicado := subir()
+		if !duplicado || url2 != url || id2 != id {
+			t.Errorf("segunda subida = %s %v %v, se esperaba %s %v duplicado", url2, id2, duplicado, url, id)
+		}
+		ruta := url[strings.Index(url, "/api/files/"):]
+		rutaMedia := "/api/media/" + strconv.Itoa(int(id))
+
+		if res := testutil.Do(t, app, http.MethodGet, ruta, nil, ""); res.Status != http.StatusNotFound {
+			t.Errorf("archivo privado sin token = %d, se esperaba 404", res.Status)
+		}
+		if res := testutil.Do(t, app, http.MethodGet, ruta, nil, e.AdminToken); res.Status != http.StatusOK {
+			t.Errorf("archivo privado con token = %d", res.Status)
+		}
+		res := testutil.Do(t, app, http.MethodPut, rutaMedia+"/visibilidad", map[string]string{"visibilidad": "publico"}, e.AdminToken)
+		if res.Status != http.StatusOK {
+			t.Fatalf("cambiar visibilidad = %d: %s", res.Status, res.Body)
+		}
+		if res := testutil.Do(t, app, http.MethodGet, ruta, nil, ""); res.Status != http.StatusOK {
+			t.Errorf("archivo público = %d", res.Status)
+		}
+
+		var lista []struct {
+			ID     uint   `json:"id"`
+			Nombre string `json:"nombre"`
+		}
+		testutil.Do(t, app, http.MethodGet, "/api/media?q=notas&categoria=document", nil, e.AdminToken).JSON(t, &lista)
+		if len(lista) != 1 || lista[0].Nombre != "notas.txt" {
+			t.Errorf("lista = %+v", lista)
+		}
+
+		// Una noticia que usa el archivo impide eliminarlo
+		res = testutil.Do(t, app, http.MethodPost, "/api/noticias", map[string]interface{}{
+			"titulo": "Notas", "url_noticia": url, "usuario_id": e.Admin.ID,
+		}, e.AdminToken)
+		if res.Status != http.StatusCreated {
+			t.Fatalf("crear noticia = %d: %s", res.Status, res.Body)
+		}
+		var media struct {
+			Referencias []struct {
+				Entidad string `json:"entidad"`
+			} `json:"referencias"`
+		}
+		testutil.Do(t, app, http.MethodGet, rutaMedia, nil, e.AdminToken).JSON(t, &media)
+		if len(media.Referencias) != 1 || media.Referencias[0].Entidad != "noticia" {
+			t.Errorf("referencias = %+v", media.Referencias)
+		}
+		if res := testutil.Do(t, app, http.MethodDelete, rutaMedia, nil, e.AdminToken); res.Status != http.StatusConflict {
+			t.Errorf("eliminar en uso = %d, se esperaba 409", res.Status)
+		}
+		if res := testutil.Do(t, app, http.MethodDelete, rutaMedia+"?forzar=true", nil, e.AdminToken); res.Status != http.StatusOK {
+			t.Errorf("eliminar forzado = %d: %s", res.Status, res.Body)
+		}
+		if res := testutil.Do(t, app, http.MethodGet, ruta, nil, ""); res.Status != http.StatusNotFound {
+			t.Errorf("archivo eliminado = %d, se esperaba 404", res.Status)
+		}
+	})
+}
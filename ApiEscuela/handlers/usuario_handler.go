@@ -9,10 +9,10 @@ import (
 )
 
 type UsuarioHandler struct {
-	usuarioRepo *repositories.UsuarioRepository
+	usuarioRepo repositories.UsuarioRepository
 }
 
-func NewUsuarioHandler(usuarioRepo *repositories.UsuarioRepository) *UsuarioHandler {
+func NewUsuarioHandler(usuarioRepo repositories.UsuarioRepository) *UsuarioHandler {
 	return &UsuarioHandler{usuarioRepo: usuarioRepo}
 }
 
@@ -27,12 +27,8 @@ func (h *UsuarioHandler) CreateUsuario(c *fiber.Ctx) error {
 	}
 
 	if err := h.usuarioRepo.CreateUsuario(&usuario); err != nil {
-		switch err {
-		case repositories.ErrUsuarioDuplicado:
-			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "usuario repetido"})
-		default:
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "No se puede crear el usuario"})
-		}
+		// Los duplicados son errores tipados; ErrorHandler los responde con su código
+		return err
 	}
 
 	// No devolver la contraseña en la respuesta
@@ -123,12 +119,8 @@ func (h *UsuarioHandler) UpdateUsuario(c *fiber.Ctx) error {
 	}
 
 	if err := h.usuarioRepo.UpdateUsuario(usuario); err != nil {
-		switch err {
-		case repositories.ErrUsuarioDuplicado:
-			return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": "usuario repetido"})
-		default:
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "No se puede actualizar el usuario"})
-		}
+		// Los duplicados son errores tipados; ErrorHandler los responde con su código
+		return err
 	}
 
 	// No devolver la contraseña
@@ -136,6 +128,11 @@ func (h *UsuarioHandler) UpdateUsuario(c *fiber.Ctx) error {
 	return c.JSON(usuario)
 }
 
+// PatchUsuario actualiza solo los campos enviados de un usuario (JSON Merge Patch)
+func (h *UsuarioHandler) PatchUsuario(c *fiber.Ctx) error {
+	return mergePatch(c, cargarCon(h.usuarioRepo.GetUsuarioByID), h.UpdateUsuario)
+}
+
 // DeleteUsuario elimina un usuario
 func (h *UsuarioHandler) DeleteUsuario(c *fiber.Ctx) error {
 	id, err := strconv.Atoi(c.Params("id"))
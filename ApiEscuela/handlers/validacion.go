@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"ApiEscuela/errores"
+	"ApiEscuela/validacion"
+)
+
+// existente es un repositorio que puede comprobar si un ID existe
+type existente interface {
+	ExistsByID(id uint) (bool, error)
+}
+
+// referencia adapta ExistsByID de un repositorio a la regla validate:"existe=<nombre>"
+func referencia(codigo, mensaje string, repo existente) validacion.Referencia {
+	return validacion.Referencia{
+		Codigo:  codigo,
+		Mensaje: mensaje,
+		Existe:  repo.ExistsByID,
+	}
+}
+
+// primerMensaje es el mensaje del primer campo inválido de un error de validación, o el
+// mensaje del error si no lleva campos
+func primerMensaje(err error) string {
+	if e, ok := errores.Como(err); ok && len(e.Campos) > 0 {
+		return e.Campos[0].Mensaje
+	}
+	return err.Error()
+}
@@ -0,0 +1,91 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"ApiEscuela/testutil"
+)
+
+func TestValidacionPorEtiquetas(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+
+		tests := []struct {
+			name       string
+			path       string
+			body       map[string]interface{}
+			wantStatus int
+			wantError  string
+			wantCampo  string
+		}{
+			{
+				name:       "persona con teléfono inválido",
+				path:       "/api/personas",
+				body:       map[string]interface{}{"nombre": "Ana Pérez", "cedula": "1200000006", "telefono": "12345"},
+				wantStatus: http.StatusBadRequest,
+				wantError:  "validation_error",
+				wantCampo:  "telefono",
+			},
+			{
+				name:       "persona sin correo ni teléfono",
+				path:       "/api/personas",
+				body:       map[string]interface{}{"nombre": "Ana Pérez", "cedula": "1200000006"},
+				wantStatus: http.StatusBadRequest,
+				wantError:  "validation_error",
+				wantCampo:  "correo",
+			},
+			{
+				name:       "persona válida",
+				path:       "/api/personas",
+				body:       map[string]interface{}{"nombre": "Ana Pérez", "cedula": "1200000006", "telefono": "099 123 4567"},
+				wantStatus: http.StatusCreated,
+			},
+			{
+				name:       "estudiante universitario con persona inexistente",
+				path:       "/api/estudiantes-universitarios",
+				body:       map[string]interface{}{"persona_id": 9999, "semestre": 3},
+				wantStatus: http.StatusBadRequest,
+				wantError:  "persona_no_existe",
+				wantCampo:  "persona_id",
+			},
+			{
+				name:       "programa de visita con institución inexistente",
+				path:       "/api/programas-visita",
+				body:       map[string]interface{}{"fecha": "2030-05-10T09:00:00Z", "institucion_id": 9999},
+				wantStatus: http.StatusBadRequest,
+				wantError:  "institucion_no_existe",
+				wantCampo:  "institucion_id",
+			},
+			{
+				name:       "duda con estudiante inexistente",
+				path:       "/api/dudas",
+				body:       map[string]interface{}{"pregunta": "¿Cuándo es la próxima visita?", "estudiante_id": 9999},
+				wantStatus: http.StatusBadRequest,
+				wantError:  "estudiante_no_existe",
+				wantCampo:  "estudiante_id",
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				res := testutil.Do(t, app, http.MethodPost, tt.path, tt.body, e.AdminToken)
+				if res.Status != tt.wantStatus {
+					t.Fatalf("status = %d, se esperaba %d: %s", res.Status, tt.wantStatus, res.Body)
+				}
+				if tt.wantError == "" {
+					return
+				}
+				body := res.Map(t)
+				if body["error"] != tt.wantError {
+					t.Errorf("error = %v, se esperaba %s", body["error"], tt.wantError)
+				}
+				campos, _ := body["validation"].([]interface{})
+				if len(campos) == 0 || campos[0].(map[string]interface{})["field"] != tt.wantCampo {
+					t.Errorf("campos de validación = %v, se esperaba %s", body["validation"], tt.wantCampo)
+				}
+			})
+		}
+	})
+}
@@ -3,17 +3,20 @@ package handlers
 import (
 	"ApiEscuela/models"
 	"ApiEscuela/repositories"
+	"ApiEscuela/services"
+	"log"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 type VisitaDetalleEstudiantesUniversitariosHandler struct {
-	visitaDetalleEstudiantesRepo *repositories.VisitaDetalleEstudiantesUniversitariosRepository
+	visitaDetalleEstudiantesRepo repositories.VisitaDetalleEstudiantesUniversitariosRepository
+	notificaciones               services.NotificacionService
 }
 
-func NewVisitaDetalleEstudiantesUniversitariosHandler(visitaDetalleEstudiantesRepo *repositories.VisitaDetalleEstudiantesUniversitariosRepository) *VisitaDetalleEstudiantesUniversitariosHandler {
-	return &VisitaDetalleEstudiantesUniversitariosHandler{visitaDetalleEstudiantesRepo: visitaDetalleEstudiantesRepo}
+func NewVisitaDetalleEstudiantesUniversitariosHandler(visitaDetalleEstudiantesRepo repositories.VisitaDetalleEstudiantesUniversitariosRepository, notificaciones services.NotificacionService) *VisitaDetalleEstudiantesUniversitariosHandler {
+	return &VisitaDetalleEstudiantesUniversitariosHandler{visitaDetalleEstudiantesRepo: visitaDetalleEstudiantesRepo, notificaciones: notificaciones}
 }
 
 // CreateVisitaDetalleEstudiantesUniversitarios crea una nueva relación entre estudiante universitario y programa de visita
@@ -45,6 +48,9 @@ func (h *VisitaDetalleEstudiantesUniversitariosHandler) CreateVisitaDetalleEstud
 			"error": "No se puede crear la relación",
 		})
 	}
+	if err := h.notificaciones.EstudianteInscrito(&relacion); err != nil {
+		log.Printf("Error al notificar la inscripción del estudiante universitario %d: %v", relacion.EstudianteUniversitarioID, err)
+	}
 
 	return c.Status(fiber.StatusCreated).JSON(relacion)
 }
@@ -111,6 +117,11 @@ func (h *VisitaDetalleEstudiantesUniversitariosHandler) UpdateVisitaDetalleEstud
 	return c.JSON(relacion)
 }
 
+// PatchVisitaDetalleEstudiantesUniversitarios actualiza solo los campos enviados de una participación de estudiante universitario (JSON Merge Patch)
+func (h *VisitaDetalleEstudiantesUniversitariosHandler) PatchVisitaDetalleEstudiantesUniversitarios(c *fiber.Ctx) error {
+	return mergePatch(c, cargarCon(h.visitaDetalleEstudiantesRepo.GetVisitaDetalleEstudiantesUniversitariosByID), h.UpdateVisitaDetalleEstudiantesUniversitarios)
+}
+
 // DeleteVisitaDetalleEstudiantesUniversitarios elimina una relación
 func (h *VisitaDetalleEstudiantesUniversitariosHandler) DeleteVisitaDetalleEstudiantesUniversitarios(c *fiber.Ctx) error {
 	id, err := strconv.Atoi(c.Params("id"))
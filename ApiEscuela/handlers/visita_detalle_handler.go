@@ -9,10 +9,10 @@ import (
 )
 
 type VisitaDetalleHandler struct {
-	visitaDetalleRepo *repositories.VisitaDetalleRepository
+	visitaDetalleRepo repositories.VisitaDetalleRepository
 }
 
-func NewVisitaDetalleHandler(visitaDetalleRepo *repositories.VisitaDetalleRepository) *VisitaDetalleHandler {
+func NewVisitaDetalleHandler(visitaDetalleRepo repositories.VisitaDetalleRepository) *VisitaDetalleHandler {
 	return &VisitaDetalleHandler{visitaDetalleRepo: visitaDetalleRepo}
 }
 
@@ -111,6 +111,11 @@ func (h *VisitaDetalleHandler) UpdateVisitaDetalle(c *fiber.Ctx) error {
 	return c.JSON(detalle)
 }
 
+// PatchVisitaDetalle actualiza solo los campos enviados de un detalle de visita (JSON Merge Patch)
+func (h *VisitaDetalleHandler) PatchVisitaDetalle(c *fiber.Ctx) error {
+	return mergePatch(c, cargarCon(h.visitaDetalleRepo.GetVisitaDetalleByID), h.UpdateVisitaDetalle)
+}
+
 // DeleteVisitaDetalle elimina un detalle de visita
 func (h *VisitaDetalleHandler) DeleteVisitaDetalle(c *fiber.Ctx) error {
 	id, err := strconv.Atoi(c.Params("id"))
@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"ApiEscuela/errores"
+	"ApiEscuela/models"
+	"ApiEscuela/services"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Límites de GET /api/webhooks/:id/entregas
+const (
+	limiteEntregas       = 50
+	limiteEntregasMaximo = 500
+)
+
+type WebhookHandler struct {
+	webhookService services.WebhookService
+}
+
+func NewWebhookHandler(webhookService services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService}
+}
+
+// WebhookRequest son los datos de una suscripción. Activo es true si se omite; el secreto solo
+// se acepta al crearla y, si se omite, se genera.
+type WebhookRequest struct {
+	URL         string   `json:"url"`
+	Descripcion string   `json:"descripcion"`
+	Eventos     []string `json:"eventos"`
+	Activo      *bool    `json:"activo"`
+	Secreto     string   `json:"secreto,omitempty"`
+}
+
+// webhook convierte la petición en el modelo
+func (r WebhookRequest) webhook() *models.Webhook {
+	return &models.Webhook{
+		URL:         r.URL,
+		Descripcion: r.Descripcion,
+		Eventos:     r.Eventos,
+		Activo:      r.Activo == nil || *r.Activo,
+		Secreto:     r.Secreto,
+	}
+}
+
+// GetEventos devuelve el catálogo de eventos que se pueden suscribir
+func (h *WebhookHandler) GetEventos(c *fiber.Ctx) error {
+	return SendSuccess(c, 200, h.webhookService.EventosDisponibles())
+}
+
+// GetWebhooks lista las suscripciones de webhooks
+func (h *WebhookHandler) GetWebhooks(c *fiber.Ctx) error {
+	webhooks, err := h.webhookService.GetWebhooks()
+	if err != nil {
+		return SendError(c, 500, "error_base_datos", "Error interno del servidor", "No se pudieron obtener los webhooks")
+	}
+	return SendSuccess(c, 200, webhooks)
+}
+
+// GetWebhook obtiene una suscripción de webhook por su ID
+func (h *WebhookHandler) GetWebhook(c *fiber.Ctx) error {
+	id, err := idWebhook(c)
+	if err != nil {
+		return err
+	}
+	webhook, err := h.webhookService.GetWebhook(id)
+	if err != nil {
+		return err
+	}
+	return SendSuccess(c, 200, webhook)
+}
+
+// CreateWebhook crea una suscripción; la respuesta es la única que incluye el secreto de la firma
+func (h *WebhookHandler) CreateWebhook(c *fiber.Ctx) error {
+	var req WebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return SendError(c, 400, "json_invalido", "No se puede procesar el JSON. Verifique el formato de los datos", err.Error())
+	}
+	creado, err := h.webhookService.CrearWebhook(req.webhook())
+	if err != nil {
+		return err
+	}
+	return SendSuccess(c, 201, creado)
+}
+
+// UpdateWebhook reemplaza la URL, los eventos, la descripción y el estado de una suscripción
+func (h *WebhookHandler) UpdateWebhook(c *fiber.Ctx) error {
+	id, err := idWebhook(c)
+	if err != nil {
+		return err
+	}
+	var req WebhookRequest
+	if err := c.BodyParser(&req); err != nil {
+		return SendError(c, 400, "json_invalido", "No se puede procesar el JSON. Verifique el formato de los datos", err.Error())
+	}
+	if req.Secreto != "" {
+		return SendValidationError(c, "El secreto no se puede cambiar", []ValidationError{{
+			Field:   "secreto",
+			Message: "Elimine el webhook y créelo de nuevo para cambiar el secreto",
+		}})
+	}
+	webhook := req.webhook()
+	webhook.ID = id
+	if err := h.webhookService.ActualizarWebhook(webhook); err != nil {
+		return err
+	}
+	return SendSuccess(c, 200, webhook)
+}
+
+// PatchWebhook actualiza solo los campos enviados de una suscripción (JSON Merge Patch)
+func (h *WebhookHandler) PatchWebhook(c *fiber.Ctx) error {
+	return mergePatch(c, cargarCon(h.webhookService.GetWebhook), h.UpdateWebhook)
+}
+
+// DeleteWebhook elimina una suscripción; su registro de entregas se conserva
+func (h *WebhookHandler) DeleteWebhook(c *fiber.Ctx) error {
+	id, err := idWebhook(c)
+	if err != nil {
+		return err
+	}
+	if err := h.webhookService.EliminarWebhook(id); err != nil {
+		return err
+	}
+	return SendSuccess(c, 200, fiber.Map{"message": "Webhook eliminado exitosamente"})
+}
+
+// ProbarWebhook envía un evento webhook.ping a la suscripción y devuelve el resultado de la entrega
+func (h *WebhookHandler) ProbarWebhook(c *fiber.Ctx) error {
+	id, err := idWebhook(c)
+	if err != nil {
+		return err
+	}
+	entrega, err := h.webhookService.Probar(id)
+	if err != nil {
+		return err
+	}
+	return SendSuccess(c, 200, entrega)
+}
+
+// GetEntregas lista las entregas de una suscripción, las más recientes primero
+func (h *WebhookHandler) GetEntregas(c *fiber.Ctx) error {
+	id, err := idWebhook(c)
+	if err != nil {
+		return err
+	}
+	limite := limiteEntregas
+	if valor := c.Query("limite"); valor != "" {
+		n, err := strconv.Atoi(valor)
+		if err != nil || n < 1 || n > limiteEntregasMaximo {
+			return SendValidationError(c, "El límite no es válido", []ValidationError{{
+				Field:   "limite",
+				Message: "Debe ser un número entre 1 y " + strconv.Itoa(limiteEntregasMaximo),
+				Value:   valor,
+			}})
+		}
+		limite = n
+	}
+	entregas, err := h.webhookService.GetEntregas(id, limite)
+	if err != nil {
+		return err
+	}
+	return SendSuccess(c, 200, entregas)
+}
+
+// ReenviarEntrega vuelve a enviar el cuerpo de una entrega como una entrega nueva
+func (h *WebhookHandler) ReenviarEntrega(c *fiber.Ctx) error {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || id == 0 {
+		return SendError(c, 400, "id_invalido", "El ID de la entrega no es válido", "El ID debe ser un número entero positivo")
+	}
+	entrega, err := h.webhookService.Reenviar(uint(id))
+	if err != nil {
+		return err
+	}
+	return SendSuccess(c, 200, entrega)
+}
+
+// idWebhook lee el ID de la suscripción de la ruta
+func idWebhook(c *fiber.Ctx) (uint, error) {
+	id, err := strconv.ParseUint(c.Params("id"), 10, 32)
+	if err != nil || id == 0 {
+		return 0, errores.Validacion("id_invalido", "El ID del webhook no es válido",
+			errores.Campo{Campo: "id", Mensaje: "Debe ser un número entero positivo", Valor: c.Params("id")})
+	}
+	return uint(id), nil
+}
@@ -0,0 +1,157 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+	"ApiEscuela/services"
+	"ApiEscuela/testutil"
+)
+
+// entregaRecibida es una petición que llegó al receptor local
+type entregaRecibida struct {
+	evento, firma, timestamp string
+	cuerpo                   []byte
+}
+
+func TestWebhooksDeEstudiantes(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		app := testutil.NewApp(repos)
+
+		recibidas := make(chan entregaRecibida, 10)
+		receptor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			cuerpo, _ := io.ReadAll(r.Body)
+			recibidas <- entregaRecibida{
+				evento:    r.Header.Get(services.CabeceraEventoWebhook),
+				firma:     r.Header.Get(services.CabeceraFirmaWebhook),
+				timestamp: r.Header.Get(services.CabeceraTimestampWebhook),
+				cuerpo:    cuerpo,
+			}
+		}))
+		defer receptor.Close()
+		esperar := func() entregaRecibida {
+			t.Helper()
+			select {
+			case r := <-recibidas:
+				return r
+			case <-time.After(5 * time.Second):
+				t.Fatal("el receptor no recibió la entrega")
+				return entregaRecibida{}
+			}
+		}
+
+		// Solo el administrador gestiona los webhooks
+		estudiante := f.Usuario(f.Persona(), e.TipoEstudiante, "clave123")
+		if res := testutil.Do(t, app, http.MethodGet, "/api/webhooks", nil, testutil.Token(t, estudiante)); res.Status != http.StatusForbidden {
+			t.Errorf("GET /api/webhooks como estudiante = %d, se esperaba 403", res.Status)
+		}
+		res := testutil.Do(t, app, http.MethodPost, "/api/webhooks", map[string]interface{}{
+			"url": "ftp://receptor", "eventos": []string{"estudiante.deleted"},
+		}, e.AdminToken)
+		if res.Status != http.StatusBadRequest {
+			t.Errorf("webhook inválido = %d: %s", res.Status, res.Body)
+		}
+		if res := testutil.Do(t, app, http.MethodGet, "/api/webhooks/eventos", nil, e.AdminToken); len(res.Map(t)["data"].([]interface{})) != 5 {
+			t.Errorf("catálogo de eventos: %s", res.Body)
+		}
+
+		var creado struct {
+			Data services.WebhookCreado `json:"data"`
+		}
+		res = testutil.Do(t, app, http.MethodPost, "/api/webhooks", map[string]interface{}{
+			"url": receptor.URL, "eventos": []string{services.EventoEstudianteCreado}, "descripcion": "Sistema académico",
+		}, e.AdminToken)
+		if res.JSON(t, &creado); res.Status != http.StatusCreated || creado.Data.Secreto == "" || !creado.Data.Activo {
+			t.Fatalf("POST /api/webhooks = %d: %s", res.Status, res.Body)
+		}
+		ruta := fmt.Sprintf("/api/webhooks/%d", creado.Data.ID)
+		if res := testutil.Do(t, app, http.MethodGet, ruta, nil, e.AdminToken); res.Status != http.StatusOK || res.Map(t)["data"].(map[string]interface{})["secreto"] != nil {
+			t.Errorf("GET %s expone el secreto o falla: %d %s", ruta, res.Status, res.Body)
+		}
+
+		// Registrar un estudiante entrega el evento firmado
+		persona := f.Persona()
+		res = testutil.Do(t, app, http.MethodPost, "/api/estudiantes", map[string]interface{}{
+			"persona_id": persona.ID, "institucion_id": e.Institucion.ID, "ciudad_id": e.Ciudad.ID, "especialidad": "Informática",
+		}, e.AdminToken)
+		if res.Status != http.StatusCreated {
+			t.Fatalf("POST /api/estudiantes = %d: %s", res.Status, res.Body)
+		}
+		entrega := esperar()
+		if entrega.evento != services.EventoEstudianteCreado || entrega.firma != services.FirmaWebhook(creado.Data.Secreto, entrega.timestamp, entrega.cuerpo) {
+			t.Errorf("entrega sin firma válida: %+v", entrega)
+		}
+		var carga struct {
+			Evento string            `json:"evento"`
+			Datos  models.Estudiante `json:"datos"`
+		}
+		if err := json.Unmarshal(entrega.cuerpo, &carga); err != nil || carga.Datos.PersonaID != persona.ID || carga.Datos.Especialidad != "Informática" {
+			t.Errorf("cuerpo de la entrega: %s (%v)", entrega.cuerpo, err)
+		}
+
+		// El registro de entregas y el reenvío
+		var entregas struct {
+			Data []models.EntregaWebhook `json:"data"`
+		}
+		var lista []models.EntregaWebhook
+		for intento := 0; intento < 50; intento++ {
+			res = testutil.Do(t, app, http.MethodGet, ruta+"/entregas", nil, e.AdminToken)
+			if res.JSON(t, &entregas); len(entregas.Data) == 1 && entregas.Data[0].Estado == repositories.EntregaEntregada {
+				break
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+		if lista = entregas.Data; len(lista) != 1 || lista[0].Estado != repositories.EntregaEntregada || lista[0].UltimoEstado != http.StatusOK {
+			t.Fatalf("entregas = %s", res.Body)
+		}
+		res = testutil.Do(t, app, http.MethodPost, fmt.Sprintf("/api/webhooks/entregas/%d/reenviar", lista[0].ID), nil, e.AdminToken)
+		if res.Status != http.StatusOK || res.Map(t)["data"].(map[string]interface{})["estado"] != repositories.EntregaEntregada {
+			t.Fatalf("reenviar = %d: %s", res.Status, res.Body)
+		}
+		if reenvio := esperar(); string(reenvio.cuerpo) != string(entrega.cuerpo) {
+			t.Errorf("el reenvío cambió el cuerpo: %s", reenvio.cuerpo)
+		}
+		if res := testutil.Do(t, app, http.MethodPost, "/api/webhooks/entregas/9999/reenviar", nil, e.AdminToken); res.Status != http.StatusNotFound {
+			t.Errorf("reenviar una entrega inexistente = %d, se esperaba 404", res.Status)
+		}
+
+		// La prueba llega aunque el webhook no esté suscrito a webhook.ping
+		if res := testutil.Do(t, app, http.MethodPost, ruta+"/prueba", nil, e.AdminToken); res.Status != http.StatusOK {
+			t.Fatalf("prueba = %d: %s", res.Status, res.Body)
+		}
+		if ping := esperar(); ping.evento != services.EventoWebhookPrueba {
+			t.Errorf("evento de prueba %q", ping.evento)
+		}
+
+		// Desactivado deja de recibir eventos
+		res = testutil.Do(t, app, http.MethodPut, ruta, map[string]interface{}{
+			"url": receptor.URL, "eventos": []string{services.EventoEstudianteCreado}, "activo": false,
+		}, e.AdminToken)
+		if res.Status != http.StatusOK || res.Map(t)["data"].(map[string]interface{})["activo"] != false {
+			t.Fatalf("PUT %s = %d: %s", ruta, res.Status, res.Body)
+		}
+		testutil.Do(t, app, http.MethodPost, "/api/estudiantes", map[string]interface{}{
+			"persona_id": f.Persona().ID, "institucion_id": e.Institucion.ID, "ciudad_id": e.Ciudad.ID, "especialidad": "Contabilidad",
+		}, e.AdminToken)
+		select {
+		case r := <-recibidas:
+			t.Errorf("un webhook desactivado recibió %s", r.cuerpo)
+		case <-time.After(100 * time.Millisecond):
+		}
+		if res := testutil.Do(t, app, http.MethodDelete, ruta, nil, e.AdminToken); res.Status != http.StatusOK {
+			t.Errorf("DELETE %s = %d", ruta, res.Status)
+		}
+		if res := testutil.Do(t, app, http.MethodGet, ruta, nil, e.AdminToken); res.Status != http.StatusNotFound {
+			t.Errorf("GET tras eliminar = %d, se esperaba 404", res.Status)
+		}
+	})
+}
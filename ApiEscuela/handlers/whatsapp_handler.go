@@ -412,7 +412,7 @@ func (h *WhatsAppHandler) CancelQueue(c *fiber.Ctx) error {
 	return c.JSON(cancelResp)
 }
 
-// GetServiceURL retorna la URL del servicio para uso en el proxy WebSocket
+// GetServiceURL retorna la URL del servicio; la usa el monitor que publica su estado en /api/eventos
 func (h *WhatsAppHandler) GetServiceURL() string {
 	return h.serviceURL
 }
@@ -0,0 +1,106 @@
+package imagenes
+
+import (
+	"image"
+	"math"
+	"strings"
+)
+
+// caracteresBase83 es el alfabeto de la codificación base 83 de BlurHash
+const caracteresBase83 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+// Blurhash codifica la imagen según https://blurha.sh con componentesX x componentesY
+// frecuencias (entre 1 y 9). El resultado es un texto corto que el frontend decodifica para
+// mostrar un marcador borroso mientras carga la imagen.
+func Blurhash(img image.Image, componentesX, componentesY int) string {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w == 0 || h == 0 {
+		return ""
+	}
+
+	// Los píxeles se pasan una sola vez a RGB lineal
+	lineal := make([][3]float64, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			r, g, bl, _ := img.At(b.Min.X+x, b.Min.Y+y).RGBA()
+			lineal[y*w+x] = [3]float64{srgbALineal(r >> 8), srgbALineal(g >> 8), srgbALineal(bl >> 8)}
+		}
+	}
+
+	factores := make([][3]float64, 0, componentesX*componentesY)
+	for j := 0; j < componentesY; j++ {
+		for i := 0; i < componentesX; i++ {
+			normalizacion := 2.0
+			if i == 0 && j == 0 {
+				normalizacion = 1
+			}
+			var f [3]float64
+			for y := 0; y < h; y++ {
+				cosY := math.Cos(math.Pi * float64(j) * float64(y) / float64(h))
+				for x := 0; x < w; x++ {
+					base := math.Cos(math.Pi*float64(i)*float64(x)/float64(w)) * cosY
+					p := lineal[y*w+x]
+					f[0] += base * p[0]
+					f[1] += base * p[1]
+					f[2] += base * p[2]
+				}
+			}
+			escala := normalizacion / float64(w*h)
+			factores = append(factores, [3]float64{f[0] * escala, f[1] * escala, f[2] * escala})
+		}
+	}
+
+	var hash strings.Builder
+	base83(&hash, (componentesX-1)+(componentesY-1)*9, 1)
+
+	dc, ac := factores[0], factores[1:]
+	maximo := 1.0
+	if len(ac) > 0 {
+		actual := 0.0
+		for _, f := range ac {
+			actual = math.Max(actual, math.Max(math.Abs(f[0]), math.Max(math.Abs(f[1]), math.Abs(f[2]))))
+		}
+		cuantizado := int(math.Max(0, math.Min(82, math.Floor(actual*166-0.5))))
+		maximo = float64(cuantizado+1) / 166
+		base83(&hash, cuantizado, 1)
+	} else {
+		base83(&hash, 0, 1)
+	}
+
+	base83(&hash, linealASRGB(dc[0])<<16+linealASRGB(dc[1])<<8+linealASRGB(dc[2]), 4)
+	for _, f := range ac {
+		q := func(v float64) int {
+			return int(math.Max(0, math.Min(18, math.Floor(potenciaConSigno(v/maximo, 0.5)*9+9.5))))
+		}
+		base83(&hash, q(f[0])*19*19+q(f[1])*19+q(f[2]), 2)
+	}
+	return hash.String()
+}
+
+func base83(sb *strings.Builder, valor, largo int) {
+	for i := 1; i <= largo; i++ {
+		digito := (valor / int(math.Pow(83, float64(largo-i)))) % 83
+		sb.WriteByte(caracteresBase83[digito])
+	}
+}
+
+func srgbALineal(v uint32) float64 {
+	c := float64(v) / 255
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+func linealASRGB(v float64) int {
+	c := math.Max(0, math.Min(1, v))
+	if c <= 0.0031308 {
+		return int(c*12.92*255 + 0.5)
+	}
+	return int((1.055*math.Pow(c, 1/2.4)-0.055)*255 + 0.5)
+}
+
+func potenciaConSigno(v, exp float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(v), exp), v)
+}
@@ -0,0 +1,147 @@
+// Package imagenes genera las variantes redimensionadas de las imágenes subidas (miniatura,
+// mediana y grande, en JPEG y WebP) y su BlurHash, para que los clientes no descarguen la
+// foto original cuando solo necesitan mostrarla pequeña.
+package imagenes
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	_ "image/png" // registra el decodificador PNG
+
+	"github.com/HugoSmits86/nativewebp"
+	"golang.org/x/image/draw"
+)
+
+// Tamano es una variante que se genera de cada imagen
+type Tamano struct {
+	Nombre string
+	Ancho  int // ancho máximo; el alto mantiene la proporción
+}
+
+// Tamanos son las variantes en orden creciente
+var Tamanos = []Tamano{
+	{Nombre: "thumb", Ancho: 320},
+	{Nombre: "medium", Ancho: 800},
+	{Nombre: "large", Ancho: 1600},
+}
+
+// TamanoPorNombre devuelve la variante con el nombre indicado
+func TamanoPorNombre(nombre string) (Tamano, bool) {
+	for _, t := range Tamanos {
+		if t.Nombre == nombre {
+			return t, true
+		}
+	}
+	return Tamano{}, false
+}
+
+// Formatos de las variantes
+const (
+	FormatoJPEG = "jpeg"
+	FormatoWebP = "webp"
+)
+
+// calidadJPEG es la calidad de las variantes JPEG
+const calidadJPEG = 82
+
+// anchoBlurhash es el ancho de la copia reducida sobre la que se calcula el BlurHash
+const anchoBlurhash = 32
+
+// Variante es una versión redimensionada de la imagen
+type Variante struct {
+	Nombre      string
+	Formato     string
+	ContentType string
+	Ancho       int
+	Alto        int
+	Datos       []byte
+}
+
+// Resultado es lo que se obtiene al procesar una imagen
+type Resultado struct {
+	Ancho     int
+	Alto      int
+	Blurhash  string
+	Variantes []Variante
+}
+
+// Procesar decodifica la imagen y genera sus variantes. Solo se generan las variantes más
+// angostas que el original (nunca se amplía) y, para cada una, un JPEG y un WebP sin pérdida;
+// el WebP se descarta si pesa más que el JPEG de una imagen sin transparencia. Los GIF animados
+// conservan solo el original para no perder la animación.
+func Procesar(datos []byte) (*Resultado, error) {
+	img, formato, err := image.Decode(bytes.NewReader(datos))
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo decodificar la imagen: %w", err)
+	}
+	b := img.Bounds()
+	res := &Resultado{Ancho: b.Dx(), Alto: b.Dy(), Blurhash: Blurhash(redimensionar(img, anchoBlurhash), 4, 3)}
+
+	if formato == "gif" {
+		if g, err := gif.DecodeAll(bytes.NewReader(datos)); err == nil && len(g.Image) > 1 {
+			return res, nil
+		}
+	}
+
+	transparente := !opaca(img)
+	for _, t := range Tamanos {
+		if t.Ancho >= res.Ancho {
+			break
+		}
+		reducida := redimensionar(img, t.Ancho)
+		rb := reducida.Bounds()
+
+		var jpg bytes.Buffer
+		if err := jpeg.Encode(&jpg, sobreBlanco(reducida), &jpeg.Options{Quality: calidadJPEG}); err != nil {
+			return nil, err
+		}
+		res.Variantes = append(res.Variantes, Variante{
+			Nombre: t.Nombre, Formato: FormatoJPEG, ContentType: "image/jpeg", Ancho: rb.Dx(), Alto: rb.Dy(), Datos: jpg.Bytes(),
+		})
+
+		var webp bytes.Buffer
+		if err := nativewebp.Encode(&webp, reducida, nil); err != nil {
+			return nil, err
+		}
+		if transparente || webp.Len() < jpg.Len() {
+			res.Variantes = append(res.Variantes, Variante{
+				Nombre: t.Nombre, Formato: FormatoWebP, ContentType: "image/webp", Ancho: rb.Dx(), Alto: rb.Dy(), Datos: webp.Bytes(),
+			})
+		}
+	}
+	return res, nil
+}
+
+// redimensionar reduce la imagen al ancho indicado manteniendo la proporción
+func redimensionar(img image.Image, ancho int) image.Image {
+	b := img.Bounds()
+	if b.Dx() <= ancho {
+		return img
+	}
+	alto := max(1, b.Dy()*ancho/b.Dx())
+	destino := image.NewNRGBA(image.Rect(0, 0, ancho, alto))
+	draw.CatmullRom.Scale(destino, destino.Bounds(), img, b, draw.Src, nil)
+	return destino
+}
+
+// sobreBlanco compone la imagen sobre fondo blanco; JPEG no admite transparencia
+func sobreBlanco(img image.Image) image.Image {
+	if opaca(img) {
+		return img
+	}
+	destino := image.NewRGBA(img.Bounds())
+	draw.Draw(destino, destino.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(destino, destino.Bounds(), img, img.Bounds().Min, draw.Over)
+	return destino
+}
+
+func opaca(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package imagenes_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"ApiEscuela/imagenes"
+)
+
+func uniforme(w, h int, c color.Color) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestBlurhashColorUniforme(t *testing.T) {
+	// 4x3 componentes: "L" codifica el tamaño y el color promedio (DC) de rojo puro, FF0000, es "TI:j"
+	hash := imagenes.Blurhash(uniforme(8, 6, color.NRGBA{R: 255, A: 255}), 4, 3)
+	if len(hash) != 28 || hash[0] != 'L' || hash[2:6] != "TI:j" {
+		t.Errorf("Blurhash = %q", hash)
+	}
+}
+
+func TestProcesarFoto(t *testing.T) {
+	img := uniforme(2000, 1000, color.NRGBA{R: 40, G: 120, B: 200, A: 255})
+	for x := 0; x < 1000; x++ {
+		for y := 0; y < 1000; y++ {
+			img.Set(x, y, color.NRGBA{R: 230, G: 200, B: 20, A: 255})
+		}
+	}
+	var foto bytes.Buffer
+	jpeg.Encode(&foto, img, nil)
+
+	res, err := imagenes.Procesar(foto.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Ancho != 2000 || res.Alto != 1000 || len(res.Blurhash) != 28 {
+		t.Errorf("resultado = %dx%d %q", res.Ancho, res.Alto, res.Blurhash)
+	}
+
+	jpegs := map[string]imagenes.Variante{}
+	for _, v := range res.Variantes {
+		if v.Formato == imagenes.FormatoJPEG {
+			jpegs[v.Nombre] = v
+		}
+	}
+	for _, tam := range imagenes.Tamanos {
+		v, ok := jpegs[tam.Nombre]
+		if !ok {
+			t.Errorf("falta la variante JPEG %s", tam.Nombre)
+			continue
+		}
+		if v.Ancho != tam.Ancho || v.Alto != tam.Ancho/2 {
+			t.Errorf("%s = %dx%d", tam.Nombre, v.Ancho, v.Alto)
+		}
+		cfg, err := jpeg.DecodeConfig(bytes.NewReader(v.Datos))
+		if err != nil || cfg.Width != v.Ancho {
+			t.Errorf("%s no es un JPEG de %d px: %v", tam.Nombre, v.Ancho, err)
+		}
+	}
+}
+
+func TestProcesarImagenPequena(t *testing.T) {
+	var buf bytes.Buffer
+	png.Encode(&buf, uniforme(500, 250, color.NRGBA{G: 255, A: 128}))
+
+	res, err := imagenes.Procesar(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Solo la miniatura es más angosta que el original; con transparencia también se genera WebP
+	formatos := map[string]bool{}
+	for _, v := range res.Variantes {
+		if v.Nombre != "thumb" {
+			t.Errorf("variante %s de una imagen de 500 px", v.Nombre)
+		}
+		formatos[v.Formato] = true
+		if v.Formato == imagenes.FormatoWebP && !bytes.HasPrefix(v.Datos, []byte("RIFF")) {
+			t.Error("la variante WebP no tiene la cabecera RIFF")
+		}
+	}
+	if !formatos[imagenes.FormatoJPEG] || !formatos[imagenes.FormatoWebP] {
+		t.Errorf("formatos = %v", formatos)
+	}
+}
+
+func TestProcesarGIFAnimado(t *testing.T) {
+	paleta := color.Palette{color.Black, color.White}
+	g := &gif.GIF{}
+	for i := 0; i < 2; i++ {
+		g.Image = append(g.Image, image.NewPaletted(image.Rect(0, 0, 600, 400), paleta))
+		g.Delay = append(g.Delay, 10)
+	}
+	var buf bytes.Buffer
+	gif.EncodeAll(&buf, g)
+
+	res, err := imagenes.Procesar(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Ancho != 600 || len(res.Variantes) != 0 || res.Blurhash == "" {
+		t.Errorf("GIF animado = %dx%d, %d variantes", res.Ancho, res.Alto, len(res.Variantes))
+	}
+}
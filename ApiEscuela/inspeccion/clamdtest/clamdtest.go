@@ -0,0 +1,138 @@
+// Package clamdtest levanta un servidor local que habla el protocolo de clamd (PING e INSTREAM)
+// para probar el escáner de virus sin instalar ClamAV. Solo reconoce el archivo de prueba EICAR.
+package clamdtest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// EICAR es el archivo de prueba estándar que todos los antivirus detectan
+const EICAR = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// Firma es el nombre con el que el servidor informa el archivo EICAR
+const Firma = "Eicar-Test-Signature"
+
+// Servidor es un clamd de prueba
+type Servidor struct {
+	ln  net.Listener
+	wg  sync.WaitGroup
+	mu  sync.Mutex
+	max int
+	// Escaneos cuenta los INSTREAM recibidos
+	escaneos int
+}
+
+// New inicia el servidor en un puerto libre de localhost; se detiene al terminar la prueba
+func New(t testing.TB) *Servidor {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("clamdtest: %v", err)
+	}
+	s := &Servidor{ln: ln}
+	s.wg.Add(1)
+	go s.aceptar()
+	t.Cleanup(s.Close)
+	return s
+}
+
+// Direccion devuelve la dirección para inspeccion.NewClamAV
+func (s *Servidor) Direccion() string {
+	return "tcp://" + s.ln.Addr().String()
+}
+
+// SetStreamMaxLength simula el límite StreamMaxLength de clamd; 0 es sin límite
+func (s *Servidor) SetStreamMaxLength(n int) {
+	s.mu.Lock()
+	s.max = n
+	s.mu.Unlock()
+}
+
+// Escaneos devuelve cuántos archivos se analizaron
+func (s *Servidor) Escaneos() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.escaneos
+}
+
+// Close detiene el servidor
+func (s *Servidor) Close() {
+	s.ln.Close()
+	s.wg.Wait()
+}
+
+func (s *Servidor) aceptar() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()
+			s.atender(conn)
+		}()
+	}
+}
+
+func (s *Servidor) atender(conn net.Conn) {
+	r := bufio.NewReader(conn)
+	// Los comandos empiezan con "z" (terminan en nulo) o "n" (terminan en salto de línea)
+	prefijo, err := r.ReadByte()
+	if err != nil {
+		return
+	}
+	fin := byte(0)
+	if prefijo == 'n' {
+		fin = '\n'
+	}
+	comando, err := r.ReadString(fin)
+	if err != nil {
+		return
+	}
+	responder := func(msg string) { conn.Write(append([]byte(msg), fin)) }
+
+	switch strings.TrimSuffix(comando, string(fin)) {
+	case "PING":
+		responder("PONG")
+	case "INSTREAM":
+		s.mu.Lock()
+		max := s.max
+		s.escaneos++
+		s.mu.Unlock()
+
+		var contenido bytes.Buffer
+		for {
+			var largo uint32
+			if err := binary.Read(r, binary.BigEndian, &largo); err != nil {
+				return
+			}
+			if largo == 0 {
+				break
+			}
+			if max > 0 && contenido.Len()+int(largo) > max {
+				responder("INSTREAM size limit exceeded. ERROR")
+				return
+			}
+			if _, err := io.CopyN(&contenido, r, int64(largo)); err != nil {
+				return
+			}
+		}
+		if bytes.Contains(contenido.Bytes(), []byte(EICAR)) {
+			responder("stream: " + Firma + " FOUND")
+		} else {
+			responder("stream: OK")
+		}
+	default:
+		responder("UNKNOWN COMMAND")
+	}
+}
@@ -0,0 +1,147 @@
+package inspeccion
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+)
+
+// Escaner analiza el contenido de un archivo en busca de virus
+type Escaner interface {
+	// Escanear devuelve *Amenaza si el contenido está infectado, u otro error si no se pudo analizar
+	Escanear(ctx context.Context, r io.Reader) error
+	// Nombre identifica el escáner en los logs
+	Nombre() string
+}
+
+// Amenaza es el error que se devuelve cuando el escáner encuentra un virus
+type Amenaza struct {
+	Firma string
+}
+
+func (a *Amenaza) Error() string {
+	return fmt.Sprintf("el archivo contiene una amenaza (%s)", a.Firma)
+}
+
+// Ninguno devuelve un escáner que acepta todo; se usa cuando no hay ClamAV configurado
+func Ninguno() Escaner {
+	return ninguno{}
+}
+
+type ninguno struct{}
+
+func (ninguno) Escanear(context.Context, io.Reader) error { return nil }
+func (ninguno) Nombre() string                            { return "ninguno" }
+
+// ClamAV analiza los archivos con un servidor clamd usando el comando INSTREAM
+type ClamAV struct {
+	red       string
+	direccion string
+	timeout   time.Duration
+}
+
+// fragmentoClamAV es el tamaño de cada fragmento enviado a clamd
+const fragmentoClamAV = 64 * 1024
+
+// NewClamAV crea un escáner para clamd en direccion: "tcp://host:puerto", "host:puerto" o
+// "unix:///ruta/clamd.sock". clamd debe tener StreamMaxLength de al menos 50M.
+func NewClamAV(direccion string, timeout time.Duration) *ClamAV {
+	red := "tcp"
+	if d, ok := strings.CutPrefix(direccion, "unix://"); ok {
+		red, direccion = "unix", d
+	} else {
+		direccion = strings.TrimPrefix(direccion, "tcp://")
+	}
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	return &ClamAV{red: red, direccion: direccion, timeout: timeout}
+}
+
+// Nombre identifica el escáner en los logs
+func (c *ClamAV) Nombre() string {
+	return "clamav (" + c.red + "://" + c.direccion + ")"
+}
+
+// Escanear envía el contenido a clamd en fragmentos y lee el veredicto
+func (c *ClamAV) Escanear(ctx context.Context, r io.Reader) error {
+	d := net.Dialer{Timeout: c.timeout}
+	conn, err := d.DialContext(ctx, c.red, c.direccion)
+	if err != nil {
+		return fmt.Errorf("clamav: %w", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(c.timeout))
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return fmt.Errorf("clamav: %w", err)
+	}
+	buf := make([]byte, 4+fragmentoClamAV)
+	for {
+		n, err := io.ReadFull(r, buf[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(buf, uint32(n))
+			if _, werr := conn.Write(buf[:4+n]); werr != nil {
+				// clamd corta la conexión si se supera StreamMaxLength; su respuesta explica el motivo
+				return c.veredicto(conn, werr)
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if _, err := conn.Write([]byte{0, 0, 0, 0}); err != nil {
+		return c.veredicto(conn, err)
+	}
+	return c.veredicto(conn, nil)
+}
+
+// veredicto interpreta la respuesta de clamd: "stream: OK", "stream: <firma> FOUND" o un error
+func (c *ClamAV) veredicto(conn net.Conn, errEnvio error) error {
+	// Con el prefijo "z" la respuesta termina en un byte nulo
+	resp, err := bufio.NewReader(conn).ReadBytes(0)
+	respuesta := strings.TrimSpace(string(bytes.TrimRight(resp, "\x00")))
+	if respuesta == "" {
+		if errEnvio != nil {
+			return fmt.Errorf("clamav: %w", errEnvio)
+		}
+		return fmt.Errorf("clamav: respuesta vacía: %v", err)
+	}
+	respuesta = strings.TrimPrefix(respuesta, "stream: ")
+	switch {
+	case respuesta == "OK":
+		return nil
+	case strings.HasSuffix(respuesta, " FOUND"):
+		return &Amenaza{Firma: strings.TrimSuffix(respuesta, " FOUND")}
+	default:
+		return fmt.Errorf("clamav: %s", respuesta)
+	}
+}
+
+// EscanerDesdeEntorno crea el escáner según CLAMAV_ADDRESS y CLAMAV_TIMEOUT; sin dirección no se
+// buscan virus
+func EscanerDesdeEntorno() (Escaner, error) {
+	direccion := os.Getenv("CLAMAV_ADDRESS")
+	if direccion == "" {
+		return Ninguno(), nil
+	}
+	timeout := 30 * time.Second
+	if v := os.Getenv("CLAMAV_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("CLAMAV_TIMEOUT inválido: %w", err)
+		}
+		timeout = d
+	}
+	return NewClamAV(direccion, timeout), nil
+}
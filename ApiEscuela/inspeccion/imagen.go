@@ -0,0 +1,182 @@
+package inspeccion
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+)
+
+// ErrImagenInvalida se devuelve cuando la imagen no se puede decodificar o es demasiado grande
+var ErrImagenInvalida = errors.New("la imagen está dañada o no es válida")
+
+// PixelesMaximos limita el tamaño de las imágenes decodificadas (evita "bombas" de descompresión)
+const PixelesMaximos = 64 * 1000 * 1000
+
+// calidadJPEG es la calidad con la que se vuelven a codificar las fotos
+const calidadJPEG = 90
+
+// sanearImagen decodifica la imagen y la vuelve a codificar en el mismo formato. Solo se
+// conservan los píxeles: se descartan EXIF (incluida la ubicación GPS), XMP, comentarios y
+// demás metadatos. La orientación EXIF de las fotos se aplica a los píxeles antes de
+// descartarla para que no aparezcan giradas.
+func sanearImagen(contentType string, r io.Reader) ([]byte, error) {
+	datos, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(datos))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrImagenInvalida, err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || int64(cfg.Width)*int64(cfg.Height) > PixelesMaximos {
+		return nil, fmt.Errorf("%w: %dx%d píxeles", ErrImagenInvalida, cfg.Width, cfg.Height)
+	}
+
+	var salida bytes.Buffer
+	switch contentType {
+	case tipoJPEG.ContentType:
+		img, err := jpeg.Decode(bytes.NewReader(datos))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrImagenInvalida, err)
+		}
+		img = orientar(img, orientacionEXIF(datos))
+		err = jpeg.Encode(&salida, img, &jpeg.Options{Quality: calidadJPEG})
+		if err != nil {
+			return nil, err
+		}
+	case tipoPNG.ContentType:
+		img, err := png.Decode(bytes.NewReader(datos))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrImagenInvalida, err)
+		}
+		if err := png.Encode(&salida, img); err != nil {
+			return nil, err
+		}
+	case tipoGIF.ContentType:
+		// DecodeAll conserva la animación; los comentarios y extensiones de aplicación se descartan
+		g, err := gif.DecodeAll(bytes.NewReader(datos))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrImagenInvalida, err)
+		}
+		if err := gif.EncodeAll(&salida, g); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: formato %s no soportado", ErrImagenInvalida, contentType)
+	}
+	return salida.Bytes(), nil
+}
+
+// orientacionEXIF devuelve la etiqueta Orientation (1-8) del segmento APP1 de un JPEG, o 1 si
+// no tiene
+func orientacionEXIF(jpg []byte) int {
+	if len(jpg) < 4 || jpg[0] != 0xff || jpg[1] != 0xd8 {
+		return 1
+	}
+	for i := 2; i+4 <= len(jpg); {
+		if jpg[i] != 0xff {
+			return 1
+		}
+		marcador := jpg[i+1]
+		if marcador == 0xd8 || (marcador >= 0xd0 && marcador <= 0xd7) || marcador == 0x01 {
+			i += 2
+			continue
+		}
+		if marcador == 0xda || marcador == 0xd9 { // inicio de los datos de la imagen
+			return 1
+		}
+		largo := int(binary.BigEndian.Uint16(jpg[i+2:]))
+		if largo < 2 || i+2+largo > len(jpg) {
+			return 1
+		}
+		segmento := jpg[i+4 : i+2+largo]
+		if marcador == 0xe1 && bytes.HasPrefix(segmento, []byte("Exif\x00\x00")) {
+			return orientacionTIFF(segmento[6:])
+		}
+		i += 2 + largo
+	}
+	return 1
+}
+
+// orientacionTIFF busca la etiqueta 0x0112 en el primer IFD de un encabezado TIFF
+func orientacionTIFF(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var orden binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		orden = binary.LittleEndian
+	case "MM":
+		orden = binary.BigEndian
+	default:
+		return 1
+	}
+	ifd := int(orden.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 1
+	}
+	entradas := int(orden.Uint16(tiff[ifd:]))
+	for e := 0; e < entradas; e++ {
+		p := ifd + 2 + e*12
+		if p+12 > len(tiff) {
+			return 1
+		}
+		if orden.Uint16(tiff[p:]) == 0x0112 {
+			if v := int(orden.Uint16(tiff[p+8:])); v >= 1 && v <= 8 {
+				return v
+			}
+			return 1
+		}
+	}
+	return 1
+}
+
+// orientar aplica a los píxeles la transformación indicada por la orientación EXIF
+func orientar(img image.Image, orientacion int) image.Image {
+	if orientacion <= 1 || orientacion > 8 {
+		return img
+	}
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	// Las orientaciones 5 a 8 intercambian el ancho y el alto
+	dw, dh := w, h
+	if orientacion >= 5 {
+		dw, dh = h, w
+	}
+	origen := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(origen, origen.Bounds(), img, b.Min, draw.Src)
+	destino := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientacion {
+			case 2: // espejo horizontal
+				dx, dy = w-1-x, y
+			case 3: // 180°
+				dx, dy = w-1-x, h-1-y
+			case 4: // espejo vertical
+				dx, dy = x, h-1-y
+			case 5: // transpuesta
+				dx, dy = y, x
+			case 6: // 90° en sentido horario
+				dx, dy = h-1-y, x
+			case 7: // transversa
+				dx, dy = h-1-y, w-1-x
+			case 8: // 90° en sentido antihorario
+				dx, dy = y, w-1-x
+			}
+			i := origen.PixOffset(x, y)
+			j := destino.PixOffset(dx, dy)
+			copy(destino.Pix[j:j+4], origen.Pix[i:i+4])
+		}
+	}
+	return destino
+}
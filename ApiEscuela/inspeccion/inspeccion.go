@@ -0,0 +1,114 @@
+// Package inspeccion revisa los archivos subidos antes de guardarlos: detecta el tipo real por
+// su contenido (firmas de bytes) y lo compara con la extensión, lo analiza con un escáner de
+// virus intercambiable y vuelve a codificar las imágenes para quitarles los metadatos EXIF
+// (ubicación GPS, cámara, fecha), ya que se suben fotos de menores.
+package inspeccion
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// TamanoMaximo es el tamaño máximo de un archivo subido
+const TamanoMaximo = 50 * 1024 * 1024
+
+var (
+	// ErrTipoNoPermitido se devuelve cuando la extensión no está en la lista de tipos permitidos
+	ErrTipoNoPermitido = errors.New("tipo de archivo no permitido")
+	// ErrDemasiadoGrande se devuelve cuando el archivo supera TamanoMaximo
+	ErrDemasiadoGrande = errors.New("el archivo es demasiado grande (máximo 50MB)")
+	// ErrContenidoNoCoincide se devuelve cuando el contenido no corresponde a la extensión
+	ErrContenidoNoCoincide = errors.New("el contenido del archivo no corresponde a su extensión")
+)
+
+// Tipo es un tipo de archivo permitido
+type Tipo struct {
+	ContentType string // Content-Type con el que se guarda y se sirve
+	Categoria   string // image, video o document
+	Carpeta     string // carpeta del almacenamiento
+}
+
+// Imagen indica si el archivo se vuelve a codificar para quitarle los metadatos
+func (t *Tipo) Imagen() bool {
+	return t.Categoria == "image"
+}
+
+// Validar verifica el nombre y el tamaño declarados de un archivo y devuelve el tipo que le
+// corresponde por su extensión. El contenido se verifica después con Inspector.Preparar.
+func Validar(nombre string, tamano int64) (*Tipo, error) {
+	if tamano > TamanoMaximo {
+		return nil, ErrDemasiadoGrande
+	}
+	tipo, ok := porExtension[extension(nombre)]
+	if !ok {
+		return nil, ErrTipoNoPermitido
+	}
+	return &tipo, nil
+}
+
+// Archivo es un archivo listo para guardarse
+type Archivo struct {
+	Tipo      *Tipo
+	Contenido io.Reader
+	Tamano    int64
+	Saneado   bool   // la imagen se volvió a codificar sin metadatos
+	Datos     []byte // la imagen saneada, que ya está en memoria; nil para otros tipos
+}
+
+// Inspector aplica las verificaciones de contenido a los archivos subidos
+type Inspector struct {
+	escaner Escaner
+}
+
+// New crea un inspector; escaner puede ser nil para no buscar virus
+func New(escaner Escaner) *Inspector {
+	if escaner == nil {
+		escaner = Ninguno()
+	}
+	return &Inspector{escaner: escaner}
+}
+
+// Escaner devuelve el escáner de virus configurado
+func (i *Inspector) Escaner() Escaner {
+	return i.escaner
+}
+
+// Preparar verifica que el contenido de r corresponda a la extensión de nombre, lo analiza con
+// el escáner y, si es una imagen, la vuelve a codificar sin metadatos
+func (i *Inspector) Preparar(ctx context.Context, nombre string, r io.ReaderAt, tamano int64) (*Archivo, error) {
+	tipo, err := Validar(nombre, tamano)
+	if err != nil {
+		return nil, err
+	}
+	if err := verificarContenido(extension(nombre), r, tamano); err != nil {
+		return nil, err
+	}
+	if err := i.escaner.Escanear(ctx, io.NewSectionReader(r, 0, tamano)); err != nil {
+		return nil, err
+	}
+
+	if !tipo.Imagen() {
+		return &Archivo{Tipo: tipo, Contenido: io.NewSectionReader(r, 0, tamano), Tamano: tamano}, nil
+	}
+	limpia, err := sanearImagen(tipo.ContentType, io.NewSectionReader(r, 0, tamano))
+	if err != nil {
+		return nil, err
+	}
+	return &Archivo{Tipo: tipo, Contenido: bytes.NewReader(limpia), Tamano: int64(len(limpia)), Saneado: true, Datos: limpia}, nil
+}
+
+// EsRechazo indica si el error se debe al archivo (tipo, contenido o virus) y no a una falla
+// del servidor o del escáner
+func EsRechazo(err error) bool {
+	var amenaza *Amenaza
+	return errors.Is(err, ErrTipoNoPermitido) || errors.Is(err, ErrDemasiadoGrande) ||
+		errors.Is(err, ErrContenidoNoCoincide) || errors.Is(err, ErrImagenInvalida) || errors.As(err, &amenaza)
+}
+
+func extension(nombre string) string {
+	return strings.ToLower(filepath.Ext(nombre))
+}
@@ -0,0 +1,200 @@
+package inspeccion_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"ApiEscuela/inspeccion"
+	"ApiEscuela/inspeccion/clamdtest"
+)
+
+func pngDePrueba(t *testing.T) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	img.Set(0, 0, color.RGBA{R: 255, A: 255})
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// jpegConEXIF genera una foto de 32x16 con la mitad superior roja y un segmento APP1 que incluye la orientación y una
+// ubicación GPS ficticia
+func jpegConEXIF(t *testing.T, orientacion byte) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 32, 16))
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 32; x++ {
+			if y < 8 {
+				img.Set(x, y, color.RGBA{R: 255, A: 255})
+			} else {
+				img.Set(x, y, color.RGBA{B: 255, A: 255})
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		t.Fatal(err)
+	}
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08" + // encabezado big endian, IFD0 en 8
+		"\x00\x01" + // una entrada
+		"\x01\x12\x00\x03\x00\x00\x00\x01\x00" + string([]byte{orientacion}) + "\x00\x00" +
+		"\x00\x00\x00\x00" + "GPS -1.0125,-79.4693")
+	app1 := append([]byte("Exif\x00\x00"), tiff...)
+	segmento := append([]byte{0xff, 0xe1, byte((len(app1) + 2) >> 8), byte(len(app1) + 2)}, app1...)
+	return append(append([]byte{0xff, 0xd8}, segmento...), buf.Bytes()[2:]...)
+}
+
+func docxDePrueba(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	z := zip.NewWriter(&buf)
+	for _, nombre := range []string{"[Content_Types].xml", "word/document.xml"} {
+		w, _ := z.Create(nombre)
+		w.Write([]byte("<xml/>"))
+	}
+	z.Close()
+	return buf.Bytes()
+}
+
+func preparar(t *testing.T, i *inspeccion.Inspector, nombre string, datos []byte) (*inspeccion.Archivo, []byte, error) {
+	t.Helper()
+	a, err := i.Preparar(context.Background(), nombre, bytes.NewReader(datos), int64(len(datos)))
+	if err != nil {
+		return nil, nil, err
+	}
+	contenido, err := io.ReadAll(a.Contenido)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a, contenido, nil
+}
+
+func TestDeteccionPorContenido(t *testing.T) {
+	i := inspeccion.New(nil)
+	mp4 := append([]byte("\x00\x00\x00\x18ftypisom\x00\x00\x02\x00"), make([]byte, 64)...)
+
+	aceptados := []struct {
+		nombre      string
+		datos       []byte
+		contentType string
+	}{
+		{"logo.PNG", pngDePrueba(t), "image/png"},
+		{"clase.mp4", mp4, "video/mp4"},
+		{"clase.mov", mp4, "video/quicktime"},
+		{"acta.pdf", []byte("%PDF-1.7\n..."), "application/pdf"},
+		{"informe.docx", docxDePrueba(t), "application/vnd.openxmlformats-officedocument.wordprocessingml.document"},
+		{"notas.txt", []byte("Visita a la UTEQ: año 2024\n"), "text/plain; charset=utf-8"},
+	}
+	for _, c := range aceptados {
+		a, _, err := preparar(t, i, c.nombre, c.datos)
+		if err != nil {
+			t.Errorf("%s: %v", c.nombre, err)
+			continue
+		}
+		if a.Tipo.ContentType != c.contentType {
+			t.Errorf("%s: Content-Type = %s, se esperaba %s", c.nombre, a.Tipo.ContentType, c.contentType)
+		}
+	}
+
+	rechazados := []struct {
+		nombre string
+		datos  []byte
+		err    error
+	}{
+		{"acta.pdf", []byte("MZ\x90\x00 ejecutable"), inspeccion.ErrContenidoNoCoincide},
+		{"foto.jpg", pngDePrueba(t), inspeccion.ErrContenidoNoCoincide},
+		{"informe.docx", []byte("PK\x03\x04 no es un zip"), inspeccion.ErrContenidoNoCoincide},
+		{"notas.txt", []byte("texto\x00binario"), inspeccion.ErrContenidoNoCoincide},
+		{"script.exe", []byte("MZ"), inspeccion.ErrTipoNoPermitido},
+		{"foto.png", []byte("\x89PNG\r\n\x1a\ntruncado"), inspeccion.ErrImagenInvalida},
+	}
+	for _, c := range rechazados {
+		if _, _, err := preparar(t, i, c.nombre, c.datos); !errors.Is(err, c.err) || !inspeccion.EsRechazo(err) {
+			t.Errorf("%s: err = %v, se esperaba %v", c.nombre, err, c.err)
+		}
+	}
+}
+
+func TestImagenSinMetadatos(t *testing.T) {
+	i := inspeccion.New(nil)
+	foto := jpegConEXIF(t, 6) // girada 90° en sentido horario
+
+	a, limpia, err := preparar(t, i, "foto.jpg", foto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !a.Saneado || a.Tamano != int64(len(limpia)) {
+		t.Errorf("archivo = %+v", a)
+	}
+	if bytes.Contains(limpia, []byte("Exif")) || bytes.Contains(limpia, []byte("GPS")) {
+		t.Error("la imagen conserva los metadatos EXIF")
+	}
+
+	// La orientación se aplica a los píxeles: la mitad roja superior queda a la derecha
+	img, err := jpeg.Decode(bytes.NewReader(limpia))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 16 || b.Dy() != 32 {
+		t.Fatalf("tamaño = %v, se esperaba 16x32", b)
+	}
+	if r, _, _, _ := img.At(12, 16).RGBA(); r < 0xc000 {
+		t.Errorf("la mitad derecha debería ser roja, r = %x", r)
+	}
+	if r, _, _, _ := img.At(3, 16).RGBA(); r > 0x4000 {
+		t.Errorf("la mitad izquierda no debería ser roja, r = %x", r)
+	}
+}
+
+func TestEscanerClamAV(t *testing.T) {
+	clamd := clamdtest.New(t)
+	i := inspeccion.New(inspeccion.NewClamAV(clamd.Direccion(), time.Second))
+
+	if _, _, err := preparar(t, i, "notas.txt", []byte("contenido limpio")); err != nil {
+		t.Errorf("archivo limpio: %v", err)
+	}
+
+	_, _, err := preparar(t, i, "virus.txt", []byte(clamdtest.EICAR))
+	var amenaza *inspeccion.Amenaza
+	if !errors.As(err, &amenaza) || amenaza.Firma != clamdtest.Firma || !inspeccion.EsRechazo(err) {
+		t.Errorf("EICAR: err = %v", err)
+	}
+
+	// Un contenido grande se envía en varios fragmentos
+	grande := []byte(strings.Repeat("línea de texto\n", 20000) + clamdtest.EICAR)
+	if _, _, err := preparar(t, i, "largo.txt", grande); !errors.As(err, &amenaza) {
+		t.Errorf("EICAR al final de un archivo grande: err = %v", err)
+	}
+	if clamd.Escaneos() != 3 {
+		t.Errorf("escaneos = %d, se esperaban 3", clamd.Escaneos())
+	}
+
+	// Si clamd rechaza el archivo por su tamaño, no es una amenaza sino una falla del escáner
+	clamd.SetStreamMaxLength(1024)
+	if _, _, err := preparar(t, i, "largo.txt", grande); err == nil || inspeccion.EsRechazo(err) {
+		t.Errorf("límite de clamd: err = %v", err)
+	}
+}
+
+func TestEscanerNoDisponible(t *testing.T) {
+	clamd := clamdtest.New(t)
+	direccion := clamd.Direccion()
+	clamd.Close()
+
+	i := inspeccion.New(inspeccion.NewClamAV(direccion, time.Second))
+	if _, _, err := preparar(t, i, "notas.txt", []byte("hola")); err == nil || inspeccion.EsRechazo(err) {
+		t.Errorf("clamd caído: err = %v; el archivo no debe aceptarse sin analizar", err)
+	}
+}
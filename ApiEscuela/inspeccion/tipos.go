@@ -0,0 +1,153 @@
+package inspeccion
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"unicode/utf8"
+)
+
+var (
+	tipoJPEG = Tipo{ContentType: "image/jpeg", Categoria: "image", Carpeta: "images"}
+	tipoPNG  = Tipo{ContentType: "image/png", Categoria: "image", Carpeta: "images"}
+	tipoGIF  = Tipo{ContentType: "image/gif", Categoria: "image", Carpeta: "images"}
+	tipoMP4  = Tipo{ContentType: "video/mp4", Categoria: "video", Carpeta: "videos"}
+	tipoMOV  = Tipo{ContentType: "video/quicktime", Categoria: "video", Carpeta: "videos"}
+	tipoAVI  = Tipo{ContentType: "video/x-msvideo", Categoria: "video", Carpeta: "videos"}
+	tipoPDF  = Tipo{ContentType: "application/pdf", Categoria: "document", Carpeta: "documents"}
+	tipoDOC  = Tipo{ContentType: "application/msword", Categoria: "document", Carpeta: "documents"}
+	tipoDOCX = Tipo{ContentType: "application/vnd.openxmlformats-officedocument.wordprocessingml.document", Categoria: "document", Carpeta: "documents"}
+	tipoTXT  = Tipo{ContentType: "text/plain; charset=utf-8", Categoria: "document", Carpeta: "documents"}
+)
+
+// porExtension es la lista de tipos permitidos
+var porExtension = map[string]Tipo{
+	".jpg": tipoJPEG, ".jpeg": tipoJPEG,
+	".png":  tipoPNG,
+	".gif":  tipoGIF,
+	".mp4":  tipoMP4,
+	".mov":  tipoMOV,
+	".avi":  tipoAVI,
+	".pdf":  tipoPDF,
+	".doc":  tipoDOC,
+	".docx": tipoDOCX,
+	".txt":  tipoTXT,
+}
+
+// Extensiones devuelve las extensiones permitidas
+func Extensiones() []string {
+	exts := make([]string, 0, len(porExtension))
+	for ext := range porExtension {
+		exts = append(exts, ext)
+	}
+	return exts
+}
+
+// formato es una familia de archivos que se reconoce por su contenido
+type formato struct {
+	extensiones []string
+	detectar    func(cabecera []byte, r io.ReaderAt, tamano int64) bool
+}
+
+// formatos se prueban en orden; el texto va al final porque solo se reconoce por descarte
+var formatos = []formato{
+	{[]string{".jpg", ".jpeg"}, prefijo("\xff\xd8\xff")},
+	{[]string{".png"}, prefijo("\x89PNG\r\n\x1a\n")},
+	{[]string{".gif"}, func(c []byte, _ io.ReaderAt, _ int64) bool {
+		return bytes.HasPrefix(c, []byte("GIF87a")) || bytes.HasPrefix(c, []byte("GIF89a"))
+	}},
+	// MP4 y QuickTime comparten el formato ISO de cajas ("ftyp", "moov", ...)
+	{[]string{".mp4", ".mov"}, esISOBMFF},
+	{[]string{".avi"}, func(c []byte, _ io.ReaderAt, _ int64) bool {
+		return len(c) >= 12 && string(c[:4]) == "RIFF" && string(c[8:12]) == "AVI "
+	}},
+	{[]string{".pdf"}, prefijo("%PDF-")},
+	// Documento de Word 97-2003 (contenedor OLE2)
+	{[]string{".doc"}, prefijo("\xd0\xcf\x11\xe0\xa1\xb1\x1a\xe1")},
+	{[]string{".docx"}, esDocx},
+	{[]string{".txt"}, esTexto},
+}
+
+// cabeceraMaxima es cuántos bytes se leen para reconocer el formato
+const cabeceraMaxima = 8192
+
+// verificarContenido comprueba que el contenido corresponda a la extensión
+func verificarContenido(ext string, r io.ReaderAt, tamano int64) error {
+	cabecera := make([]byte, min(tamano, cabeceraMaxima))
+	n, err := r.ReadAt(cabecera, 0)
+	if err != nil && err != io.EOF {
+		return err
+	}
+	cabecera = cabecera[:n]
+
+	for _, f := range formatos {
+		if !f.detectar(cabecera, r, tamano) {
+			continue
+		}
+		for _, e := range f.extensiones {
+			if e == ext {
+				return nil
+			}
+		}
+		return ErrContenidoNoCoincide
+	}
+	return ErrContenidoNoCoincide
+}
+
+func prefijo(firma string) func([]byte, io.ReaderAt, int64) bool {
+	return func(c []byte, _ io.ReaderAt, _ int64) bool {
+		return bytes.HasPrefix(c, []byte(firma))
+	}
+}
+
+// esISOBMFF reconoce MP4/QuickTime por el tipo de la primera caja
+func esISOBMFF(c []byte, _ io.ReaderAt, _ int64) bool {
+	if len(c) < 12 {
+		return false
+	}
+	switch string(c[4:8]) {
+	case "ftyp", "moov", "mdat", "wide", "free", "skip":
+		return true
+	}
+	return false
+}
+
+// esDocx reconoce un ZIP de Office Open XML con el documento principal de Word
+func esDocx(c []byte, r io.ReaderAt, tamano int64) bool {
+	if !bytes.HasPrefix(c, []byte("PK\x03\x04")) {
+		return false
+	}
+	z, err := zip.NewReader(r, tamano)
+	if err != nil {
+		return false
+	}
+	tiposContenido, documento := false, false
+	for _, f := range z.File {
+		switch f.Name {
+		case "[Content_Types].xml":
+			tiposContenido = true
+		case "word/document.xml":
+			documento = true
+		}
+	}
+	return tiposContenido && documento
+}
+
+// esTexto acepta UTF-8 sin bytes nulos ni caracteres de control binarios
+func esTexto(c []byte, _ io.ReaderAt, tamano int64) bool {
+	// Una secuencia UTF-8 puede quedar cortada al final de la cabecera
+	if int64(len(c)) < tamano {
+		for i := 0; i < utf8.UTFMax-1 && len(c) > 0 && !utf8.Valid(c); i++ {
+			c = c[:len(c)-1]
+		}
+	}
+	if !utf8.Valid(c) {
+		return false
+	}
+	for _, b := range c {
+		if b < 0x20 && b != '\n' && b != '\r' && b != '\t' && b != '\f' {
+			return false
+		}
+	}
+	return true
+}
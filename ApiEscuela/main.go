@@ -1,13 +1,24 @@
 package main
 
 import (
+	"ApiEscuela/database"
+	"ApiEscuela/docs"
+	"ApiEscuela/eventos"
 	"ApiEscuela/handlers"
-	"ApiEscuela/models"
+	"ApiEscuela/inspeccion"
+	"ApiEscuela/middleware"
 	"ApiEscuela/repositories"
+	"ApiEscuela/repositories/cached"
 	"ApiEscuela/routers"
 	"ApiEscuela/services"
+	"ApiEscuela/storage"
+	"ApiEscuela/tareas"
+	"context"
+	"errors"
+	"flag"
 	"log"
 	"os"
+	"path/filepath"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -24,11 +35,19 @@ func main() {
 		log.Printf("Info: No se encontró archivo .env, usando variables de entorno del sistema")
 	}
 
+	// Subcomandos de administración (seed, ...): se ejecutan en lugar del servidor
+	nombreComando, cmd, err := buscarComando(os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	// Inicializar Fiber
 	app := fiber.New(fiber.Config{
 		AppName: "ApiEscuela v1.0",
 		// Configurar para aceptar JSON automáticamente
 		BodyLimit: 4 * 1024 * 1024, // 4MB
+		// Respuesta de error estándar para los errores tipados que devuelven los handlers
+		ErrorHandler: handlers.ErrorHandler,
 	})
 
 	// Middleware para detectar JSON automáticamente
@@ -48,7 +67,10 @@ func main() {
 	app.Use(cors.New(cors.Config{
 		AllowOrigins: "*",
 		AllowMethods: "GET,POST,HEAD,PUT,DELETE,PATCH,OPTIONS",
-		AllowHeaders: "Origin, Content-Type, Accept, Authorization",
+		AllowHeaders: "Origin, Content-Type, Accept, Authorization, Last-Event-ID, " + middleware.PreconditionHeaders,
+		// Permitir al frontend leer los avisos de rutas obsoletas, los validadores de caché y el
+		// nombre de los archivos exportados
+		ExposeHeaders: middleware.DeprecationHeaders + ", " + middleware.CacheHeaders + ", Content-Disposition",
 	}))
 
 	// Configurar Viper
@@ -56,6 +78,26 @@ func main() {
 	config.AutomaticEnv()
 	config.SetDefault("APP_PORT", "3000")
 	config.SetDefault("APP_ENV", "development")
+	config.SetDefault("CATALOG_CACHE_TTL", "5m")
+	config.SetDefault("UPLOAD_TMP_DIR", filepath.Join(os.TempDir(), "apiescuela-uploads"))
+	config.SetDefault("UPLOAD_SESSION_TTL", "24h")
+	config.SetDefault("MEDIA_GC_INTERVAL", "24h")
+	config.SetDefault("MEDIA_GC_GRACE", "168h")
+	config.SetDefault("SCHEDULER_ENABLED", true)
+	config.SetDefault("SCHEDULE_EXPIRE_CODES", "*/5 * * * *")
+	config.SetDefault("SCHEDULE_PURGE_UPLOADS", "@hourly")
+	config.SetDefault("SCHEDULE_VISIT_REMINDERS", "0 * * * *")
+	config.SetDefault("VISIT_REMINDER_AHEAD", "24h")
+	config.SetDefault("SCHEDULER_HISTORY_RETENTION", "720h")
+	config.SetDefault("SCHEDULE_PURGE_TRASH", "@daily")
+	config.SetDefault("TRASH_RETENTION", "0")
+	config.SetDefault("SCHEDULE_WEBHOOK_RETRIES", "* * * * *")
+	config.SetDefault("WEBHOOK_MAX_ATTEMPTS", 8)
+	config.SetDefault("WEBHOOK_RETRY_BASE", "30s")
+	config.SetDefault("ANALYTICS_CACHE_TTL", "15m")
+	config.SetDefault("SCHEDULE_ANALYTICS_REFRESH", "*/10 * * * *")
+	config.SetDefault("IF_MATCH_REQUIRED", false)
+	config.SetDefault("WHATSAPP_POLL_INTERVAL", "2s")
 
 	config.SetConfigName("config")
 	config.SetConfigType("env")
@@ -92,89 +134,181 @@ func main() {
 		log.Fatalf("Error al conectar con la base de datos: %v", err)
 	}
 
-	// Automigración de todos los modelos
-	if err := db.AutoMigrate(
-		&models.Provincia{},
-		&models.Ciudad{},
-		&models.Persona{},
-		&models.TipoUsuario{},
-		&models.Usuario{},
-		&models.Institucion{},
-		&models.Estudiante{},
-		&models.EstudianteUniversitario{},
-		&models.AutoridadUTEQ{},
-		&models.Tematica{},
-		&models.Actividad{},
-		&models.ProgramaVisita{},
-		&models.DetalleAutoridadDetallesVisita{},
-		&models.VisitaDetalle{},
-		&models.Dudas{},
-		&models.VisitaDetalleEstudiantesUniversitarios{},
-		&models.CodigoUsuario{},
-		&models.Noticia{},
-		&models.Comunicado{},
-	); err != nil {
-		log.Fatalf("Error en la automigración: %v", err)
+	// Automigración de todos los modelos y migraciones manuales (tabla de códigos y esquema de la
+	// búsqueda de texto completo); la usan también los comandos
+	if err := database.Migrate(db); err != nil {
+		log.Fatalf("Error en la migración: %v", err)
+	}
+
+	if cmd != nil {
+		if err := cmd.Ejecutar(db, os.Args[2:]); err != nil && !errors.Is(err, flag.ErrHelp) {
+			log.Fatalf("Error en el comando %s: %v", nombreComando, err)
+		}
+		return
 	}
 
+	// Almacenamiento de archivos subidos (carpeta local o bucket S3, según STORAGE_DRIVER)
+	archivos, err := storage.DesdeEntorno()
+	if err != nil {
+		log.Fatalf("Error al configurar el almacenamiento de archivos: %v", err)
+	}
+	log.Printf("Almacenamiento de archivos: %s", archivos.Nombre())
+
+	// Escáner de virus para los archivos subidos (CLAMAV_ADDRESS; sin dirección no se analizan)
+	escaner, err := inspeccion.EscanerDesdeEntorno()
+	if err != nil {
+		log.Fatalf("Error al configurar el escáner de virus: %v", err)
+	}
+	log.Printf("Escáner de virus: %s", escaner.Nombre())
+	inspector := inspeccion.New(escaner)
+
+	// Caché en memoria de los catálogos; CATALOG_CACHE_TTL=0 la desactiva
+	catalogCache := cached.NewStore(config.GetDuration("CATALOG_CACHE_TTL"))
+
 	// Inicializar repositorios
 	estudianteRepo := repositories.NewEstudianteRepository(db)
 	personaRepo := repositories.NewPersonaRepository(db)
-	provinciaRepo := repositories.NewProvinciaRepository(db)
-	ciudadRepo := repositories.NewCiudadRepository(db)
+	provinciaRepo := cached.NewProvinciaRepository(repositories.NewProvinciaRepository(db), catalogCache)
+	ciudadRepo := cached.NewCiudadRepository(repositories.NewCiudadRepository(db), catalogCache)
 	institucionRepo := repositories.NewInstitucionRepository(db)
 	tipoUsuarioRepo := repositories.NewTipoUsuarioRepository(db)
 	usuarioRepo := repositories.NewUsuarioRepository(db)
 	estudianteUnivRepo := repositories.NewEstudianteUniversitarioRepository(db)
 	autoridadRepo := repositories.NewAutoridadUTEQRepository(db)
-	tematicaRepo := repositories.NewTematicaRepository(db)
-	actividadRepo := repositories.NewActividadRepository(db)
+	tematicaRepo := cached.NewTematicaRepository(repositories.NewTematicaRepository(db), catalogCache)
+	actividadRepo := cached.NewActividadRepository(repositories.NewActividadRepository(db), catalogCache)
 	programaVisitaRepo := repositories.NewProgramaVisitaRepository(db)
 	detalleAutoridadDetallesVisitaRepo := repositories.NewDetalleAutoridadDetallesVisitaRepository(db)
-	visitaDetalleRepo := repositories.NewVisitaDetalleRepository(db)
+	visitaDetalleRepo := cached.NewVisitaDetalleRepository(repositories.NewVisitaDetalleRepository(db), catalogCache)
 	dudasRepo := repositories.NewDudasRepository(db)
 	visitaDetalleEstudiantesUniversitariosRepo := repositories.NewVisitaDetalleEstudiantesUniversitariosRepository(db)
 	codigoUsuarioRepo := repositories.NewCodigoUsuarioRepository(db)
-
-	// Ejecutar migración manual para la tabla de códigos
-	if err := codigoUsuarioRepo.MigrarColumnaExpiraEn(); err != nil {
-		log.Printf("Advertencia: Error al migrar tabla de códigos: %v", err)
-	} else {
-		log.Printf("Migración de tabla de códigos completada exitosamente")
-	}
-
 	noticiaRepo := repositories.NewNoticiaRepository(db)
 	comunicadoRepo := repositories.NewComunicadoRepository(db)
 
 	// Inicializar servicios (antes de handlers que los necesiten)
 	authService := services.NewAuthService(usuarioRepo, personaRepo, codigoUsuarioRepo)
-	comunicadoService := services.NewComunicadoService(comunicadoRepo, estudianteRepo, institucionRepo)
+	mediaService := services.NewMediaService(repositories.NewMediaRepository(db), archivos)
+	comunicadoService := services.NewComunicadoService(comunicadoRepo, estudianteRepo, institucionRepo, archivos, mediaService)
+	backupService := services.NewBackupService(db, archivos, catalogCache.InvalidateAll)
+	uploadService := services.NewUploadService(config.GetString("UPLOAD_TMP_DIR"), config.GetDuration("UPLOAD_SESSION_TTL"), archivos, inspector, mediaService)
+
+	archivoService := services.NewArchivoService(noticiaRepo, comunicadoRepo, archivos, mediaService)
+	recordatorioService := services.NewRecordatorioService(programaVisitaRepo, detalleAutoridadDetallesVisitaRepo, autoridadRepo, comunicadoService)
+	papeleraService := services.NewPapeleraService(repositories.NewPapeleraRepository(db), noticiaRepo, comunicadoRepo, mediaService, catalogCache.InvalidateAll)
+	// Webhooks: cada entrega fallida se reintenta con espera exponencial desde WEBHOOK_RETRY_BASE
+	// hasta WEBHOOK_MAX_ATTEMPTS intentos
+	webhookService := services.NewWebhookService(repositories.NewWebhookRepository(db), services.ConfigWebhooks{
+		MaxIntentos: config.GetInt("WEBHOOK_MAX_ATTEMPTS"),
+		EsperaBase:  config.GetDuration("WEBHOOK_RETRY_BASE"),
+	})
+	// Analítica del dashboard: los agregados se guardan ANALYTICS_CACHE_TTL y la tarea
+	// refrescar-analitica recalcula el panel por defecto antes de que venza
+	analiticaService := services.NewAnaliticaService(repositories.NewAnaliticaRepository(db), config.GetDuration("ANALYTICS_CACHE_TTL"))
+
+	// Tareas programadas. Con varias réplicas solo las ejecuta la que obtiene el bloqueo asesor
+	// de Postgres; SCHEDULER_ENABLED=false las deja disponibles solo para ejecución manual.
+	// MEDIA_GC_INTERVAL=0 desactiva la recolección de archivos (files gc sigue disponible).
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatalf("Error al obtener la conexión de la base de datos: %v", err)
+	}
+	ejecucionTareaRepo := repositories.NewEjecucionTareaRepository(db)
+	programador := tareas.New(ejecucionTareaRepo, tareas.NewBloqueoAsesor(sqlDB, tareas.ClaveBloqueo))
+	configTareas := services.ConfigTareas{
+		ExpirarCodigos:            config.GetString("SCHEDULE_EXPIRE_CODES"),
+		PurgarSubidas:             config.GetString("SCHEDULE_PURGE_UPLOADS"),
+		GraciaArchivos:            config.GetDuration("MEDIA_GC_GRACE"),
+		RecordatoriosVisitas:      config.GetString("SCHEDULE_VISIT_REMINDERS"),
+		AnticipacionRecordatorios: config.GetDuration("VISIT_REMINDER_AHEAD"),
+		RetencionHistorial:        config.GetDuration("SCHEDULER_HISTORY_RETENTION"),
+		PurgarPapelera:            config.GetString("SCHEDULE_PURGE_TRASH"),
+		RetencionPapelera:         config.GetDuration("TRASH_RETENTION"),
+		ReintentarWebhooks:        config.GetString("SCHEDULE_WEBHOOK_RETRIES"),
+		RefrescarAnalitica:        config.GetString("SCHEDULE_ANALYTICS_REFRESH"),
+	}
+	if intervalo := config.GetDuration("MEDIA_GC_INTERVAL"); intervalo > 0 {
+		configTareas.RecolectarArchivos = "@every " + intervalo.String()
+	}
+	for _, tarea := range services.TareasProgramadas(configTareas, services.DependenciasTareas{
+		Codigos:       codigoUsuarioRepo,
+		Historial:     ejecucionTareaRepo,
+		Subidas:       uploadService,
+		Archivos:      archivoService,
+		Recordatorios: recordatorioService,
+		Papelera:      papeleraService,
+		Webhooks:      webhookService,
+		Analitica:     analiticaService,
+	}) {
+		if err := programador.Registrar(tarea); err != nil {
+			log.Fatalf("Error al registrar la tarea programada: %v", err)
+		}
+	}
+	if config.GetBool("SCHEDULER_ENABLED") {
+		programador.Iniciar(context.Background())
+	}
+
+	// Bus de eventos en tiempo real (/api/eventos)
+	bus := eventos.New()
+
+	// Notificaciones de los usuarios: en la aplicación, por correo o por WhatsApp según sus preferencias
+	whatsappHandler := handlers.NewWhatsAppHandler()
+	notificacionService := services.NewNotificacionService(services.DependenciasNotificaciones{
+		Notificaciones:            repositories.NewNotificacionRepository(db),
+		Usuarios:                  usuarioRepo,
+		Dudas:                     dudasRepo,
+		Estudiantes:               estudianteRepo,
+		EstudiantesUniversitarios: estudianteUnivRepo,
+		Autoridades:               autoridadRepo,
+		Programas:                 programaVisitaRepo,
+		Correo:                    comunicadoService,
+		WhatsApp:                  services.NewClienteWhatsApp(whatsappHandler.GetServiceURL()),
+		Eventos:                   bus,
+	})
 
 	// Inicializar handlers
-	estudianteHandler := handlers.NewEstudianteHandler(estudianteRepo, personaRepo, institucionRepo, ciudadRepo, usuarioRepo, tipoUsuarioRepo, authService)
-	personaHandler := handlers.NewPersonaHandler(personaRepo)
+	ifMatchRequerido := config.GetBool("IF_MATCH_REQUIRED")
+	estudianteHandler, errEstudiante := handlers.NewEstudianteHandler(estudianteRepo, personaRepo, institucionRepo, ciudadRepo, usuarioRepo, tipoUsuarioRepo, authService, bus, webhookService)
+	personaHandler := handlers.NewPersonaHandler(personaRepo, ifMatchRequerido)
 	provinciaHandler := handlers.NewProvinciaHandler(provinciaRepo)
 	ciudadHandler := handlers.NewCiudadHandler(ciudadRepo)
 	institucionHandler := handlers.NewInstitucionHandler(institucionRepo)
 	tipoUsuarioHandler := handlers.NewTipoUsuarioHandler(tipoUsuarioRepo)
 	usuarioHandler := handlers.NewUsuarioHandler(usuarioRepo)
-	estudianteUnivHandler := handlers.NewEstudianteUniversitarioHandler(estudianteUnivRepo, personaRepo)
-	autoridadHandler := handlers.NewAutoridadUTEQHandler(autoridadRepo, personaRepo)
+	estudianteUnivHandler, errEstudianteUniv := handlers.NewEstudianteUniversitarioHandler(estudianteUnivRepo, personaRepo)
+	autoridadHandler, errAutoridad := handlers.NewAutoridadUTEQHandler(autoridadRepo, personaRepo)
 	tematicaHandler := handlers.NewTematicaHandler(tematicaRepo)
-	actividadHandler := handlers.NewActividadHandler(actividadRepo)
-	programaVisitaHandler := handlers.NewProgramaVisitaHandler(programaVisitaRepo)
-	detalleAutoridadDetallesVisitaHandler := handlers.NewDetalleAutoridadDetallesVisitaHandler(detalleAutoridadDetallesVisitaRepo)
+	actividadHandler, errActividad := handlers.NewActividadHandler(actividadRepo, tematicaRepo)
+	programaVisitaHandler, errProgramaVisita := handlers.NewProgramaVisitaHandler(programaVisitaRepo, institucionRepo, webhookService, ifMatchRequerido)
+	detalleAutoridadDetallesVisitaHandler := handlers.NewDetalleAutoridadDetallesVisitaHandler(detalleAutoridadDetallesVisitaRepo, notificacionService)
 	visitaDetalleHandler := handlers.NewVisitaDetalleHandler(visitaDetalleRepo)
-	dudasHandler := handlers.NewDudasHandler(dudasRepo)
-	visitaDetalleEstudiantesUniversitariosHandler := handlers.NewVisitaDetalleEstudiantesUniversitariosHandler(visitaDetalleEstudiantesUniversitariosRepo)
-	noticiaHandler := handlers.NewNoticiaHandler(noticiaRepo)
-	uploadHandler := handlers.NewUploadHandler()
-	codigoHandler := handlers.NewCodigoHandler(codigoUsuarioRepo)
+	dudasHandler, errDudas := handlers.NewDudasHandler(dudasRepo, estudianteRepo, autoridadRepo, notificacionService, webhookService)
+	visitaDetalleEstudiantesUniversitariosHandler := handlers.NewVisitaDetalleEstudiantesUniversitariosHandler(visitaDetalleEstudiantesUniversitariosRepo, notificacionService)
+	noticiaHandler := handlers.NewNoticiaHandler(noticiaRepo, mediaService)
+	uploadHandler := handlers.NewUploadHandler(archivos, uploadService, inspector, mediaService)
+	codigoHandler, errCodigo := handlers.NewCodigoHandler(codigoUsuarioRepo, usuarioRepo)
+	// Los handlers con reglas validate:"existe=..." comprueban sus validadores al crearse: una regla
+	// mal escrita o una referencia sin registrar detienen el inicio
+	if err := errors.Join(errEstudiante, errEstudianteUniv, errAutoridad, errActividad, errProgramaVisita, errDudas, errCodigo); err != nil {
+		log.Fatalf("Error al crear los handlers: %v", err)
+	}
 
 	// Inicializar handlers que dependen de servicios
 	authHandler := handlers.NewAuthHandler(authService)
-	comunicadoHandler := handlers.NewComunicadoHandler(comunicadoService)
-	whatsappHandler := handlers.NewWhatsAppHandler()
+	comunicadoHandler := handlers.NewComunicadoHandler(comunicadoService, archivos, bus, notificacionService, webhookService)
+	// Estado de WhatsApp para /api/eventos: solo se consulta mientras hay clientes conectados.
+	// WHATSAPP_POLL_INTERVAL=0 lo desactiva.
+	services.NewMonitorWhatsApp(whatsappHandler.GetServiceURL(), bus, config.GetDuration("WHATSAPP_POLL_INTERVAL")).Iniciar(context.Background())
+	backupHandler := handlers.NewBackupHandler(backupService)
+	mediaHandler := handlers.NewMediaHandler(mediaService)
+	tareaHandler := handlers.NewTareaHandler(programador)
+	papeleraHandler := handlers.NewPapeleraHandler(papeleraService)
+	eventosHandler := handlers.NewEventosHandler(bus)
+	notificacionHandler := handlers.NewNotificacionHandler(notificacionService)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+	busquedaHandler := handlers.NewBusquedaHandler(services.NewBusquedaService(repositories.NewBusquedaRepository(db), noticiaRepo))
+	exportacionHandler := handlers.NewExportacionHandler(services.NewExportacionService(repositories.NewExportacionRepository(db)))
+	analiticaHandler := handlers.NewAnaliticaHandler(analiticaService)
 
 	// Crear contenedor de todos los handlers
 	allHandlers := routers.NewAllHandlers(
@@ -200,27 +334,34 @@ func main() {
 		codigoHandler,
 		comunicadoHandler,
 		whatsappHandler,
+		backupHandler,
+		mediaHandler,
+		tareaHandler,
+		papeleraHandler,
+		eventosHandler,
+		notificacionHandler,
+		webhookHandler,
+		busquedaHandler,
+		exportacionHandler,
+		analiticaHandler,
 	)
 
 	// Configurar todas las rutas
 	routers.SetupAllRoutes(app, allHandlers)
 
-	// Ruta de bienvenida
-	app.Get("/", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{
-			"message": "¡Bienvenido a ApiEscuela!",
-			"version": "4.2.2",
-			"status":  "running",
-		})
-	})
+	// Documentación OpenAPI (público)
+	app.Get("/docs", docs.UIHandler())
+	app.Get("/docs/openapi.json", docs.SpecHandler(app))
 
-	// Ruta de salud
-	app.Get("/health", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{
-			"status":   "healthy",
-			"database": "connected",
-		})
-	})
+	// Verificar que la especificación OpenAPI cubre todas las rutas registradas
+	if problemas := docs.CheckRoutes(app); len(problemas) > 0 {
+		for _, p := range problemas {
+			log.Printf("Advertencia OpenAPI: %s", p)
+		}
+		if config.GetBool("OPENAPI_STRICT") {
+			log.Fatalf("La especificación OpenAPI no está sincronizada con las rutas (%d problemas)", len(problemas))
+		}
+	}
 
 	// Iniciar servidor
 	port := config.GetString("APP_PORT")
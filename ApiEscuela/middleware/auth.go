@@ -202,3 +202,15 @@ func OptionalJWTMiddleware() fiber.Handler {
 		return c.Next()
 	}
 }
+
+// TokenEnQuery usa el parámetro access_token como header Authorization cuando este falta. Es
+// para los clientes que no pueden enviar headers, como EventSource del navegador; debe ir antes
+// de JWTMiddleware y solo en las rutas que lo necesitan, porque la URL queda en los logs.
+func TokenEnQuery() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if token := c.Query("access_token"); token != "" && c.Get("Authorization") == "" {
+			c.Request().Header.Set("Authorization", "Bearer "+token)
+		}
+		return c.Next()
+	}
+}
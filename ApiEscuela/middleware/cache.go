@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CacheHeaders son los encabezados de validación que el frontend debe poder leer (CORS Expose-Headers)
+const CacheHeaders = "ETag, Last-Modified"
+
+// Version identifica el estado actual de un recurso para las peticiones condicionales
+type Version struct {
+	ETag       string
+	Modificado time.Time
+}
+
+// VersionFunc calcula la versión actual de un recurso
+type VersionFunc func() (Version, error)
+
+// CacheControl agrega a las respuestas GET y HEAD el encabezado Cache-Control con la política indicada.
+// Si version no es nil, agrega además ETag y Last-Modified y responde 304 Not Modified sin ejecutar
+// el handler cuando If-None-Match (o, en su ausencia, If-Modified-Since) indica que el cliente ya
+// tiene la versión actual.
+func CacheControl(politica string, version VersionFunc) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead {
+			return c.Next()
+		}
+		c.Set(fiber.HeaderCacheControl, politica)
+		if version == nil {
+			return c.Next()
+		}
+
+		v, err := version()
+		if err != nil {
+			// Sin versión la respuesta se sirve completa; el handler reportará el error si persiste
+			return c.Next()
+		}
+		modificado := v.Modificado.UTC().Truncate(time.Second)
+		c.Set(fiber.HeaderETag, v.ETag)
+		if !modificado.IsZero() {
+			c.Set(fiber.HeaderLastModified, modificado.Format(http.TimeFormat))
+		}
+
+		if noModificado(c, v.ETag, modificado) {
+			return c.SendStatus(fiber.StatusNotModified)
+		}
+
+		if err := c.Next(); err != nil {
+			return err
+		}
+		// La versión describe la colección; una respuesta de error no debe quedar validada con ella
+		if c.Response().StatusCode() != fiber.StatusOK {
+			c.Response().Header.Del(fiber.HeaderETag)
+			c.Response().Header.Del(fiber.HeaderLastModified)
+		}
+		return nil
+	}
+}
+
+// noModificado evalúa las precondiciones de la petición según RFC 9110 §13.2.2
+func noModificado(c *fiber.Ctx, etag string, modificado time.Time) bool {
+	if inm := c.Get(fiber.HeaderIfNoneMatch); inm != "" {
+		return etagCoincide(inm, etag)
+	}
+	ims := c.Get(fiber.HeaderIfModifiedSince)
+	if ims == "" || modificado.IsZero() {
+		return false
+	}
+	desde, err := http.ParseTime(ims)
+	if err != nil {
+		return false
+	}
+	return !modificado.After(desde)
+}
+
+// etagCoincide compara la lista de If-None-Match con etag usando comparación débil
+func etagCoincide(lista, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidato := range strings.Split(lista, ",") {
+		candidato = strings.TrimSpace(candidato)
+		if candidato == "*" || strings.TrimPrefix(candidato, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DeprecationHeaders son los encabezados que el frontend debe poder leer (CORS Expose-Headers)
+const DeprecationHeaders = "Deprecation, Sunset, Link"
+
+// Deprecated agrega a las respuestas los encabezados Deprecation (RFC 9745), Sunset (RFC 8594)
+// y Link con la ruta que reemplaza al recurso, si se indica
+func Deprecated(since, sunset time.Time, successor string) fiber.Handler {
+	deprecation := "@" + strconv.FormatInt(since.Unix(), 10)
+	sunsetHeader := ""
+	if !sunset.IsZero() {
+		sunsetHeader = sunset.UTC().Format(http.TimeFormat)
+	}
+	link := ""
+	if successor != "" {
+		link = fmt.Sprintf("<%s>; rel=\"successor-version\"", successor)
+	}
+
+	return func(c *fiber.Ctx) error {
+		c.Set("Deprecation", deprecation)
+		if sunsetHeader != "" {
+			c.Set("Sunset", sunsetHeader)
+		}
+		if link != "" {
+			c.Append("Link", link)
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// PreconditionHeaders son los encabezados condicionales que el frontend envía (CORS Allow-Headers)
+const PreconditionHeaders = "If-Match, If-None-Match"
+
+// IfNoneMatchCoincide indica si el cliente ya tiene la versión etag según If-None-Match
+// (comparación débil)
+func IfNoneMatchCoincide(c *fiber.Ctx, etag string) bool {
+	inm := c.Get(fiber.HeaderIfNoneMatch)
+	return inm != "" && etagCoincide(inm, etag)
+}
+
+// IfMatch evalúa la precondición If-Match con comparación fuerte (RFC 9110 §13.1.1): enviado
+// indica si la petición la incluye y cumple si "*" o alguno de sus ETag coincide con etag.
+// Los ETag débiles nunca cumplen.
+func IfMatch(c *fiber.Ctx, etag string) (enviado, cumple bool) {
+	im := c.Get(fiber.HeaderIfMatch)
+	if strings.TrimSpace(im) == "" {
+		return false, false
+	}
+	for _, candidato := range strings.Split(im, ",") {
+		candidato = strings.TrimSpace(candidato)
+		if candidato == "*" || (candidato == etag && !strings.HasPrefix(etag, "W/")) {
+			return true, true
+		}
+	}
+	return true, false
+}
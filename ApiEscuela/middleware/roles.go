@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RoleResolver obtiene el nombre del tipo de usuario a partir de su ID
+type RoleResolver func(tipoUsuarioID uint) (string, error)
+
+// RequireRoles permite continuar solo a los usuarios cuyo tipo de usuario está en roles.
+// Debe usarse después de JWTMiddleware, que guarda tipo_usuario_id en el contexto.
+func RequireRoles(resolver RoleResolver, roles ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if tieneRol(c, resolver, roles...) {
+			return c.Next()
+		}
+		return c.Status(fiber.StatusForbidden).JSON(ErrorResponse{
+			Error:      "Acceso denegado",
+			ErrorCode:  "AUTH_FORBIDDEN",
+			Message:    "Esta operación requiere uno de los roles: " + strings.Join(roles, ", "),
+			StatusCode: 403,
+			Timestamp:  time.Now().Format(time.RFC3339),
+			Path:       c.Path(),
+			Method:     c.Method(),
+		})
+	}
+}
+
+// IdentificarAdministrador guarda en el contexto ("administrador") si el usuario es del tipo
+// Administrador, para las rutas abiertas a todos que muestran más a los administradores.
+// Debe usarse después de JWTMiddleware.
+func IdentificarAdministrador(resolver RoleResolver) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals("administrador", tieneRol(c, resolver, "Administrador"))
+		return c.Next()
+	}
+}
+
+// tieneRol indica si el tipo de usuario del contexto está en roles
+func tieneRol(c *fiber.Ctx, resolver RoleResolver, roles ...string) bool {
+	tipoUsuarioID, _ := c.Locals("tipo_usuario_id").(uint)
+	nombre, err := resolver(tipoUsuarioID)
+	if err != nil {
+		return false
+	}
+	for _, rol := range roles {
+		if strings.EqualFold(strings.TrimSpace(nombre), rol) {
+			return true
+		}
+	}
+	return false
+}
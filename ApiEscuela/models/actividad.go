@@ -5,9 +5,9 @@ import "gorm.io/gorm"
 // Actividad representa una actividad dentro de una temática
 type Actividad struct {
 	gorm.Model
-	Actividad   string `json:"actividad" gorm:"not null"`
-	TematicaID  uint   `json:"tematica_id" gorm:"not null"`
-	Duracion    int    `json:"duracion"` // en minutos
+	Actividad   string `json:"actividad" gorm:"not null" validate:"required,min=3,max=200,sin_simbolos"`
+	TematicaID  uint   `json:"tematica_id" gorm:"not null" validate:"required,existe=tematica"`
+	Duracion    int    `json:"duracion" validate:"min=0,max=1440"` // en minutos
 	
 	// Relaciones
 	Tematica       Tematica        `json:"tematica,omitempty" gorm:"foreignKey:TematicaID"`
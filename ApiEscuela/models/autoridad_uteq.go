@@ -5,8 +5,8 @@ import "gorm.io/gorm"
 // AutoridadUTEQ representa una autoridad de la UTEQ
 type AutoridadUTEQ struct {
 	gorm.Model
-	PersonaID uint   `json:"persona_id" gorm:"not null"`
-	Cargo     string `json:"cargo"`
+	PersonaID uint   `json:"persona_id" gorm:"not null" validate:"required,existe=persona"`
+	Cargo     string `json:"cargo" validate:"min=2,max=100"`
 
 	// Relaciones
 	Persona                         Persona                          `json:"persona,omitempty" gorm:"foreignKey:PersonaID"`
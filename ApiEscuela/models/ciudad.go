@@ -7,6 +7,7 @@ type Ciudad struct {
 	gorm.Model
 	ProvinciaID uint       `json:"provincia_id" gorm:"not null"`
 	Ciudad      string     `json:"ciudad" gorm:"not null"`
+	Codigo      string     `json:"codigo,omitempty" gorm:"size:4;index"` // Código DPA del INEC (cantón)
 	Provincia   Provincia  `json:"provincia,omitempty" gorm:"foreignKey:ProvinciaID"`
 	Estudiantes []Estudiante `json:"estudiantes,omitempty" gorm:"foreignKey:CiudadID"`
 }
\ No newline at end of file
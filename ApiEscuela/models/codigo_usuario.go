@@ -12,11 +12,11 @@ import (
 // Estado: valido, verificado, expirado
 type CodigoUsuario struct {
 	gorm.Model
-	UsuarioID uint       `json:"usuario_id" gorm:"not null;index"`
+	UsuarioID uint       `json:"usuario_id" gorm:"not null;index" validate:"required,existe=usuario"`
 	Usuario   Usuario    `json:"usuario,omitempty" gorm:"foreignKey:UsuarioID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
-	Codigo    string     `json:"codigo" gorm:"not null;size:10;index"`
-	ExpiraEn  *time.Time `json:"expira_en" gorm:"index;null"`
-	Estado    string     `json:"estado" gorm:"not null;default:'valido';size:20;index"`
+	Codigo    string     `json:"codigo" gorm:"not null;size:10;index" validate:"required,numeric,len=6"`
+	ExpiraEn  *time.Time `json:"expira_en" gorm:"index;null" validate:"ventana=-1h..24h" patch:"null"`
+	Estado    string     `json:"estado" gorm:"not null;default:'valido';size:20;index" validate:"oneof=valido verificado expirado"`
 }
 
 // TableName fuerza el nombre de la tabla a "codigosusuarios"
@@ -8,13 +8,13 @@ import (
 // Dudas representa las dudas que pueden tener los estudiantes
 type Dudas struct {
 	gorm.Model
-	Pregunta         string     `json:"pregunta" gorm:"not null"`
+	Pregunta         string     `json:"pregunta" gorm:"not null" validate:"required,min=10,max=1000"`
 	FechaPregunta    time.Time  `json:"fecha_pregunta" gorm:"not null;default:CURRENT_TIMESTAMP"`
-	Respuesta        *string    `json:"respuesta,omitempty"`        // Opcional - puntero para permitir null
+	Respuesta        *string    `json:"respuesta,omitempty" validate:"min=5,max=2000" patch:"null"`        // Opcional - puntero para permitir null
 	FechaRespuesta   *time.Time `json:"fecha_respuesta,omitempty"`  // Opcional - se establece cuando se responde
-	Privacidad       string     `json:"privacidad" gorm:"not null;default:'publico';check:privacidad IN ('privado','publico')"`
-	EstudianteID     uint       `json:"estudiante_id" gorm:"not null"`
-	AutoridadUTEQID  *uint      `json:"autoridad_uteq_id,omitempty"` // Opcional - puede no estar asignada
+	Privacidad       string     `json:"privacidad" gorm:"not null;default:'publico';check:privacidad IN ('privado','publico')" validate:"oneof=privado publico"`
+	EstudianteID     uint       `json:"estudiante_id" gorm:"not null" validate:"required,existe=estudiante"`
+	AutoridadUTEQID  *uint      `json:"autoridad_uteq_id,omitempty" patch:"null" validate:"existe=autoridad"` // Opcional - puede no estar asignada
 	
 	// Relaciones
 	Estudiante    Estudiante     `json:"estudiante,omitempty" gorm:"foreignKey:EstudianteID"`
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EjecucionTarea registra una ejecución de una tarea programada: cuándo, en qué instancia,
+// si fue programada o manual y cómo terminó
+type EjecucionTarea struct {
+	gorm.Model
+	Tarea     string     `json:"tarea" gorm:"size:100;not null;index"`
+	Disparo   string     `json:"disparo" gorm:"size:20;not null"` // programado o manual
+	Instancia string     `json:"instancia" gorm:"size:255"`       // host y PID que la ejecutó
+	Inicio    time.Time  `json:"inicio" gorm:"not null;index"`
+	Fin       *time.Time `json:"fin"`
+	Estado    string     `json:"estado" gorm:"size:20;not null;index"` // en_curso, exito o error
+	Resultado string     `json:"resultado" gorm:"type:text"`
+	Error     string     `json:"error" gorm:"type:text"`
+}
@@ -5,8 +5,8 @@ import "gorm.io/gorm"
 // EstudianteUniversitario representa un estudiante universitario
 type EstudianteUniversitario struct {
 	gorm.Model
-	PersonaID uint   `json:"persona_id" gorm:"not null"`
-	Semestre  int    `json:"semestre"`
+	PersonaID uint   `json:"persona_id" gorm:"not null" validate:"required,existe=persona"`
+	Semestre  int    `json:"semestre" validate:"min=0,max=20"`
 	
 	// Relaciones
 	Persona                                 Persona                                   `json:"persona,omitempty" gorm:"foreignKey:PersonaID"`
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Media guarda los metadatos de un archivo subido: quién lo subió, su checksum, visibilidad,
+// qué noticias y comunicados lo usan y, en las imágenes, dimensiones, BlurHash y variantes
+type Media struct {
+	gorm.Model
+	Clave         string         `json:"clave" gorm:"size:500;unique;not null"` // clave en el almacenamiento (/api/files/{clave})
+	Nombre        string         `json:"nombre" gorm:"size:255"`                // nombre original del archivo
+	Categoria     string         `json:"categoria" gorm:"size:20;index"`        // image, video o document
+	ContentType   string         `json:"content_type" gorm:"size:100"`
+	Tamano        int64          `json:"tamano"`
+	Sha256        string         `json:"sha256" gorm:"size:64;index"`                  // del archivo tal como se subió
+	Visibilidad   string         `json:"visibilidad" gorm:"size:20;default:'publico'"` // publico o privado
+	UsuarioID     *uint          `json:"usuario_id"`                                   // nil en archivos anteriores a la biblioteca
+	ReutilizadoEn *time.Time     `json:"reutilizado_en,omitempty"`                     // última vez que una subida idéntica devolvió este archivo
+	Ancho         int            `json:"ancho"`
+	Alto          int            `json:"alto"`
+	Blurhash      string         `json:"blurhash" gorm:"size:100"`
+	Variantes     VariantesMedia `json:"variantes" gorm:"type:jsonb"`
+
+	// Relaciones
+	Usuario     *Usuario          `json:"usuario,omitempty" gorm:"foreignKey:UsuarioID"`
+	Referencias []MediaReferencia `json:"referencias" gorm:"foreignKey:MediaID"`
+}
+
+// MediaReferencia indica que una noticia o un comunicado usa un archivo
+type MediaReferencia struct {
+	gorm.Model
+	MediaID   uint   `json:"media_id" gorm:"not null;uniqueIndex:idx_media_referencia"`
+	Entidad   string `json:"entidad" gorm:"size:20;not null;uniqueIndex:idx_media_referencia"` // noticia o comunicado
+	EntidadID uint   `json:"entidad_id" gorm:"not null;uniqueIndex:idx_media_referencia"`
+
+	// Relaciones
+	Media *Media `json:"-" gorm:"foreignKey:MediaID"`
+}
+
+// VarianteMedia es una versión redimensionada de una imagen
+type VarianteMedia struct {
+	Nombre      string `json:"nombre"`  // thumb, medium o large
+	Formato     string `json:"formato"` // jpeg o webp
+	Clave       string `json:"clave"`
+	ContentType string `json:"content_type"`
+	Ancho       int    `json:"ancho"`
+	Alto        int    `json:"alto"`
+	Tamano      int64  `json:"tamano"`
+}
+
+// VariantesMedia se guarda como un arreglo JSON
+type VariantesMedia []VarianteMedia
+
+// Value implementa driver.Valuer
+func (v VariantesMedia) Value() (driver.Value, error) {
+	if v == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+// Scan implementa sql.Scanner
+func (v *VariantesMedia) Scan(src interface{}) error {
+	var datos []byte
+	switch s := src.(type) {
+	case nil:
+		*v = nil
+		return nil
+	case []byte:
+		datos = s
+	case string:
+		datos = []byte(s)
+	default:
+		return fmt.Errorf("tipo no soportado para VariantesMedia: %T", src)
+	}
+	return json.Unmarshal(datos, v)
+}
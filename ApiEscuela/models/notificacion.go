@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Notificacion es un aviso del centro de notificaciones de un usuario: una duda respondida,
+// una visita asignada, un comunicado recibido, etc.
+type Notificacion struct {
+	gorm.Model
+	UsuarioID uint       `json:"usuario_id" gorm:"not null;index"`
+	Tipo      string     `json:"tipo" gorm:"size:50;not null"` // duda_respondida, autoridad_asignada, inscripcion_visita, comunicado
+	Titulo    string     `json:"titulo" gorm:"size:255;not null"`
+	Mensaje   string     `json:"mensaje" gorm:"type:text"`
+	Recurso   string     `json:"recurso,omitempty" gorm:"size:100"` // ruta de la API del registro relacionado, p. ej. dudas
+	RecursoID uint       `json:"recurso_id,omitempty"`
+	Leida     bool       `json:"leida" gorm:"not null;index"`
+	LeidaEn   *time.Time `json:"leida_en"`
+}
+
+// PreferenciaNotificacion indica por qué canales recibe un usuario sus notificaciones. Un
+// usuario sin preferencias guardadas las recibe solo en la aplicación.
+type PreferenciaNotificacion struct {
+	gorm.Model
+	UsuarioID uint `json:"usuario_id" gorm:"not null;uniqueIndex"`
+	App       bool `json:"app"`
+	Correo    bool `json:"correo"`
+	WhatsApp  bool `json:"whatsapp"`
+}
@@ -9,11 +9,11 @@ import (
 // Persona representa la información básica de una persona
 type Persona struct {
 	gorm.Model
-	Nombre          string    `json:"nombre" gorm:"not null"`
-	FechaNacimiento time.Time `json:"fecha_nacimiento"`
-	Correo          *string   `json:"correo" gorm:"unique"`
-	Telefono        *string   `json:"telefono"`
-	Cedula          string    `json:"cedula" gorm:"unique;not null"`
+	Nombre          string    `json:"nombre" gorm:"not null" validate:"required,min=2,max=100"`
+	FechaNacimiento time.Time `json:"fecha_nacimiento" validate:"pasado"`
+	Correo          *string   `json:"correo" gorm:"unique" validate:"required_without=Telefono,email,max=255" patch:"null"`
+	Telefono        *string   `json:"telefono" validate:"telefono" patch:"null"`
+	Cedula          string    `json:"cedula" gorm:"unique;not null" validate:"required,numeric,min=6,max=15"`
 
 	// Relaciones
 	Estudiantes     []Estudiante              `json:"estudiantes,omitempty" gorm:"foreignKey:PersonaID"`
@@ -8,9 +8,10 @@ import (
 // ProgramaVisita representa un programa de visita programado
 type ProgramaVisita struct {
 	gorm.Model
-	Fecha         time.Time   `json:"fecha" gorm:"not null"`
-	Fechafin         time.Time   `json:"fechafin" gorm:"null"`
-	InstitucionID uint        `json:"institucion_id" gorm:"not null"`
+	Fecha         time.Time   `json:"fecha" gorm:"not null" validate:"required"`
+	Fechafin         time.Time   `json:"fechafin" gorm:"null" validate:"despues=Fecha"`
+	InstitucionID uint        `json:"institucion_id" gorm:"not null" validate:"required,existe=institucion"`
+	RecordatorioEnviadoEn *time.Time `json:"recordatorio_enviado_en,omitempty" patch:"-"` // lo completa la tarea recordatorios-visitas
 	
 	// Relaciones
 	Institucion   Institucion   `json:"institucion,omitempty" gorm:"foreignKey:InstitucionID"`
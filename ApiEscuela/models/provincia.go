@@ -6,5 +6,6 @@ import "gorm.io/gorm"
 type Provincia struct {
 	gorm.Model
 	Provincia string    `json:"provincia" gorm:"not null"`
+	Codigo    string    `json:"codigo,omitempty" gorm:"size:2;index"` // Código DPA del INEC
 	Ciudades  []Ciudad  `json:"ciudades,omitempty" gorm:"foreignKey:ProvinciaID"`
 }
\ No newline at end of file
@@ -8,10 +8,10 @@ import (
 // Estudiante representa un estudiante del sistema
 type Estudiante struct {
 	gorm.Model
-	PersonaID     uint           `json:"persona_id" gorm:"not null"`
-	InstitucionID uint           `json:"institucion_id" gorm:"not null"`
-	CiudadID      uint           `json:"ciudad_id" gorm:"not null"`
-	Especialidad  string         `json:"especialidad"`
+	PersonaID     uint           `json:"persona_id" gorm:"not null" validate:"required,existe=persona"`
+	InstitucionID uint           `json:"institucion_id" gorm:"not null" validate:"required,existe=institucion"`
+	CiudadID      uint           `json:"ciudad_id" gorm:"not null" validate:"required,existe=ciudad"`
+	Especialidad  string         `json:"especialidad" validate:"min=2,max=100"`
 	RedSocial     datatypes.JSON `json:"redsocial,omitempty" gorm:"type:jsonb"`
 
 	// Relaciones
@@ -6,7 +6,7 @@ import "gorm.io/gorm"
 type Usuario struct {
 	gorm.Model
 	Usuario       string `json:"usuario" gorm:"unique;not null"`
-	Contraseña    string `json:"contraseña" gorm:"not null"`
+	Contraseña    string `json:"contraseña" gorm:"not null" patch:"-"`
 	PersonaID     uint   `json:"persona_id" gorm:"not null"`
 	TipoUsuarioID uint   `json:"tipo_usuario_id" gorm:"not null"`
 	Verificado    bool   `json:"verificado" gorm:"default:false"`
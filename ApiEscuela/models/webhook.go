@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Webhook es la suscripción de un sistema externo a eventos de la API: cada evento suscrito
+// se envía por POST a URL firmado con Secreto (HMAC-SHA256)
+type Webhook struct {
+	gorm.Model
+	URL         string                      `json:"url" gorm:"size:2048;not null"`
+	Descripcion string                      `json:"descripcion" gorm:"size:255" patch:"null"`
+	Eventos     datatypes.JSONSlice[string] `json:"eventos" gorm:"type:jsonb;not null"`
+	Secreto     string                      `json:"-" gorm:"size:128;not null"`
+	Activo      bool                        `json:"activo" gorm:"not null;index"`
+}
+
+// EntregaWebhook registra el envío de un evento a un webhook: el cuerpo firmado, los intentos
+// realizados y cuándo toca el siguiente
+type EntregaWebhook struct {
+	gorm.Model
+	WebhookID      uint       `json:"webhook_id" gorm:"not null;index"`
+	Evento         string     `json:"evento" gorm:"size:100;not null"`
+	Payload        string     `json:"payload" gorm:"type:text;not null"`    // cuerpo JSON enviado, idéntico en cada intento
+	Estado         string     `json:"estado" gorm:"size:20;not null;index"` // pendiente, entregada o fallida
+	Intentos       int        `json:"intentos" gorm:"not null"`
+	ProximoIntento *time.Time `json:"proximo_intento" gorm:"index"` // nil si ya no se reintentará
+	UltimoEstado   int        `json:"ultimo_estado"`                // código HTTP de la última respuesta; 0 sin respuesta
+	UltimoError    string     `json:"ultimo_error" gorm:"type:text"`
+	EntregadaEn    *time.Time `json:"entregada_en"`
+	ReenvioDe      *uint      `json:"reenvio_de,omitempty"` // entrega original de un reenvío manual
+}
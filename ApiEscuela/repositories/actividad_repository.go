@@ -5,21 +5,21 @@ import (
 	"gorm.io/gorm"
 )
 
-type ActividadRepository struct {
+type actividadRepository struct {
 	db *gorm.DB
 }
 
-func NewActividadRepository(db *gorm.DB) *ActividadRepository {
-	return &ActividadRepository{db: db}
+func NewActividadRepository(db *gorm.DB) ActividadRepository {
+	return &actividadRepository{db: db}
 }
 
 // CreateActividad crea una nueva actividad
-func (r *ActividadRepository) CreateActividad(actividad *models.Actividad) error {
+func (r *actividadRepository) CreateActividad(actividad *models.Actividad) error {
 	return r.db.Create(actividad).Error
 }
 
 // GetActividadByID obtiene una actividad por ID
-func (r *ActividadRepository) GetActividadByID(id uint) (*models.Actividad, error) {
+func (r *actividadRepository) GetActividadByID(id uint) (*models.Actividad, error) {
 	var actividad models.Actividad
 	err := r.db.Preload("Tematica").Preload("VisitaDetalles").
 		First(&actividad, id).Error
@@ -30,7 +30,7 @@ func (r *ActividadRepository) GetActividadByID(id uint) (*models.Actividad, erro
 }
 
 // GetAllActividades obtiene todas las actividades
-func (r *ActividadRepository) GetAllActividades() ([]models.Actividad, error) {
+func (r *actividadRepository) GetAllActividades() ([]models.Actividad, error) {
 	var actividades []models.Actividad
 	err := r.db.Preload("Tematica").Preload("VisitaDetalles").
 		Find(&actividades).Error
@@ -38,17 +38,17 @@ func (r *ActividadRepository) GetAllActividades() ([]models.Actividad, error) {
 }
 
 // UpdateActividad actualiza una actividad
-func (r *ActividadRepository) UpdateActividad(actividad *models.Actividad) error {
+func (r *actividadRepository) UpdateActividad(actividad *models.Actividad) error {
 	return r.db.Save(actividad).Error
 }
 
 // DeleteActividad elimina una actividad
-func (r *ActividadRepository) DeleteActividad(id uint) error {
+func (r *actividadRepository) DeleteActividad(id uint) error {
 	return r.db.Delete(&models.Actividad{}, id).Error
 }
 
 // GetActividadesByTematica obtiene actividades por temática
-func (r *ActividadRepository) GetActividadesByTematica(tematicaID uint) ([]models.Actividad, error) {
+func (r *actividadRepository) GetActividadesByTematica(tematicaID uint) ([]models.Actividad, error) {
 	var actividades []models.Actividad
 	err := r.db.Where("tematica_id = ?", tematicaID).
 		Preload("Tematica").Preload("VisitaDetalles").
@@ -56,17 +56,14 @@ func (r *ActividadRepository) GetActividadesByTematica(tematicaID uint) ([]model
 	return actividades, err
 }
 
-// GetActividadesByNombre busca actividades por nombre
-func (r *ActividadRepository) GetActividadesByNombre(nombre string) ([]models.Actividad, error) {
-	var actividades []models.Actividad
-	err := r.db.Where("actividad ILIKE ?", "%"+nombre+"%").
-		Preload("Tematica").Preload("VisitaDetalles").
-		Find(&actividades).Error
-	return actividades, err
+// GetActividadesByNombre busca actividades por las palabras del nombre, sin distinguir tildes
+func (r *actividadRepository) GetActividadesByNombre(nombre string) ([]ResultadoBusqueda[models.Actividad], error) {
+	return buscarTexto[models.Actividad](r.db.Preload("Tematica").Preload("VisitaDetalles"),
+		ConsultaTexto(nombre, "A"), "actividad")
 }
 
 // GetActividadesByDuracion obtiene actividades por duración
-func (r *ActividadRepository) GetActividadesByDuracion(duracionMin, duracionMax int) ([]models.Actividad, error) {
+func (r *actividadRepository) GetActividadesByDuracion(duracionMin, duracionMax int) ([]models.Actividad, error) {
 	var actividades []models.Actividad
 	err := r.db.Where("duracion BETWEEN ? AND ?", duracionMin, duracionMax).
 		Preload("Tematica").Preload("VisitaDetalles").
@@ -0,0 +1,141 @@
+package repositories
+
+import (
+	"fmt"
+	"time"
+)
+
+// Agrupaciones de las series de la analítica
+const (
+	AgruparDia    = "dia"
+	AgruparSemana = "semana"
+	AgruparMes    = "mes"
+	AgruparAnio   = "anio"
+)
+
+// Dimensiones de los estudiantes alcanzados
+const (
+	AnaliticaPorProvincia    = "provincia"
+	AnaliticaPorCiudad       = "ciudad"
+	AnaliticaPorInstitucion  = "institucion"
+	AnaliticaPorEspecialidad = "especialidad"
+)
+
+// SinEspecialidad agrupa a los estudiantes que no indicaron su especialidad
+const SinEspecialidad = "Sin especialidad"
+
+// RangoAnalitica es el intervalo [Desde, Hasta) de los agregados
+type RangoAnalitica struct {
+	Desde time.Time
+	Hasta time.Time
+}
+
+// ConteoPeriodo es el total de un período de una serie; Periodo es la etiqueta de EtiquetaPeriodo
+type ConteoPeriodo struct {
+	Periodo string `json:"periodo"`
+	Total   int64  `json:"total"`
+}
+
+// ConteoGrupo es el total de un grupo de un desglose; ID es 0 en los grupos sin tabla propia
+// (especialidad)
+type ConteoGrupo struct {
+	ID     uint   `json:"id,omitempty"`
+	Nombre string `json:"nombre"`
+	Total  int64  `json:"total"`
+}
+
+// CargaAutoridad son las visitas asignadas a una autoridad y las dudas que le asignaron en el rango
+type CargaAutoridad struct {
+	AutoridadID      uint   `json:"autoridad_id"`
+	Nombre           string `json:"nombre"`
+	Cargo            string `json:"cargo"`
+	Visitas          int64  `json:"visitas"`
+	DudasAsignadas   int64  `json:"dudas_asignadas"`
+	DudasRespondidas int64  `json:"dudas_respondidas"`
+	DudasPendientes  int64  `json:"dudas_pendientes"`
+}
+
+// TiemposRespuesta resume las dudas de un período: cuántas se respondieron y en cuántas horas.
+// Los tiempos son nil si no hay dudas respondidas con fecha de respuesta.
+type TiemposRespuesta struct {
+	Periodo       string   `json:"periodo,omitempty"`
+	Preguntas     int64    `json:"preguntas"`
+	Respondidas   int64    `json:"respondidas"`
+	SinResponder  int64    `json:"sin_responder"`
+	PromedioHoras *float64 `json:"promedio_horas"`
+	MedianaHoras  *float64 `json:"mediana_horas"`
+	P90Horas      *float64 `json:"p90_horas"`
+}
+
+// AlcanceCanal resume los comunicados de un canal: Enviados cuenta los de estado enviado y
+// Destinatarios suma los destinatarios de esos envíos
+type AlcanceCanal struct {
+	Canal         string `json:"canal"`
+	Comunicados   int64  `json:"comunicados"`
+	Enviados      int64  `json:"enviados"`
+	Destinatarios int64  `json:"destinatarios"`
+}
+
+// EsAgrupacion indica si la agrupación es una de las de la analítica
+func EsAgrupacion(agrupacion string) bool {
+	switch agrupacion {
+	case AgruparDia, AgruparSemana, AgruparMes, AgruparAnio:
+		return true
+	}
+	return false
+}
+
+// InicioPeriodo devuelve el comienzo del período que contiene t en la zona horaria local; las
+// semanas empiezan el lunes, como date_trunc('week') de Postgres
+func InicioPeriodo(t time.Time, agrupacion string) time.Time {
+	t = t.In(time.Local)
+	y, m, d := t.Date()
+	switch agrupacion {
+	case AgruparSemana:
+		return time.Date(y, m, d-(int(t.Weekday())+6)%7, 0, 0, 0, 0, time.Local)
+	case AgruparMes:
+		return time.Date(y, m, 1, 0, 0, 0, 0, time.Local)
+	case AgruparAnio:
+		return time.Date(y, 1, 1, 0, 0, 0, 0, time.Local)
+	}
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
+
+// SiguientePeriodo devuelve el comienzo del período que sigue al que empieza en inicio
+func SiguientePeriodo(inicio time.Time, agrupacion string) time.Time {
+	switch agrupacion {
+	case AgruparSemana:
+		return inicio.AddDate(0, 0, 7)
+	case AgruparMes:
+		return inicio.AddDate(0, 1, 0)
+	case AgruparAnio:
+		return inicio.AddDate(1, 0, 0)
+	}
+	return inicio.AddDate(0, 0, 1)
+}
+
+// EtiquetaPeriodo identifica el período que contiene t: 2025-03-14 (día), 2025-03-10 (lunes de la
+// semana), 2025-03 (mes) o 2025 (año)
+func EtiquetaPeriodo(t time.Time, agrupacion string) string {
+	inicio := InicioPeriodo(t, agrupacion)
+	switch agrupacion {
+	case AgruparMes:
+		return inicio.Format("2006-01")
+	case AgruparAnio:
+		return inicio.Format("2006")
+	}
+	return inicio.Format("2006-01-02")
+}
+
+// expresionPeriodo es la expresión SQL de EtiquetaPeriodo sobre la columna
+func expresionPeriodo(columna, agrupacion string) string {
+	switch agrupacion {
+	case AgruparSemana:
+		return fmt.Sprintf("to_char(date_trunc('week', %s), 'YYYY-MM-DD')", columna)
+	case AgruparMes:
+		return fmt.Sprintf("to_char(%s, 'YYYY-MM')", columna)
+	case AgruparAnio:
+		return fmt.Sprintf("to_char(%s, 'YYYY')", columna)
+	}
+	return fmt.Sprintf("to_char(%s, 'YYYY-MM-DD')", columna)
+}
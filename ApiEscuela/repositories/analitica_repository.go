@@ -0,0 +1,185 @@
+package repositories
+
+import (
+	"fmt"
+	"reflect"
+
+	"ApiEscuela/models"
+
+	"gorm.io/gorm"
+)
+
+// dudaRespondidaSQL es la condición de DudasRespondidas sobre la tabla de dudas con el alias du
+const dudaRespondidaSQL = "du.respuesta IS NOT NULL AND du.respuesta <> ''"
+
+type analiticaRepository struct {
+	db *gorm.DB
+}
+
+func NewAnaliticaRepository(db *gorm.DB) AnaliticaRepository {
+	return &analiticaRepository{db: db}
+}
+
+// tabla es el nombre de la tabla del modelo según la estrategia de nombres de la conexión
+func (r *analiticaRepository) tabla(modelo interface{}) string {
+	return r.db.NamingStrategy.TableName(reflect.TypeOf(modelo).Name())
+}
+
+// argumentos son los parámetros con nombre del rango para las consultas SQL
+func argumentos(rango RangoAnalitica) map[string]interface{} {
+	return map[string]interface{}{"desde": rango.Desde, "hasta": rango.Hasta}
+}
+
+// VisitasPorPeriodo cuenta los programas de visita por período de su fecha
+func (r *analiticaRepository) VisitasPorPeriodo(rango RangoAnalitica, agrupacion string) ([]ConteoPeriodo, error) {
+	conteos := []ConteoPeriodo{}
+	err := r.db.Model(&models.ProgramaVisita{}).
+		Select(expresionPeriodo("fecha", agrupacion)+" AS periodo, COUNT(*) AS total").
+		Where("fecha >= ? AND fecha < ?", rango.Desde, rango.Hasta).
+		Group("periodo").Order("periodo").
+		Scan(&conteos).Error
+	return conteos, err
+}
+
+// EstudiantesAlcanzados cuenta los estudiantes activos de las instituciones visitadas en el rango
+func (r *analiticaRepository) EstudiantesAlcanzados(rango RangoAnalitica, dimension string) ([]ConteoGrupo, error) {
+	ciudades := fmt.Sprintf("JOIN %s c ON c.id = e.ciudad_id AND c.deleted_at IS NULL", r.tabla(models.Ciudad{}))
+	var id, nombre, joins string
+	switch dimension {
+	case AnaliticaPorProvincia:
+		id, nombre = "pr.id", "pr.provincia"
+		joins = ciudades + fmt.Sprintf(" JOIN %s pr ON pr.id = c.provincia_id AND pr.deleted_at IS NULL", r.tabla(models.Provincia{}))
+	case AnaliticaPorCiudad:
+		id, nombre, joins = "c.id", "c.ciudad", ciudades
+	case AnaliticaPorEspecialidad:
+		id, nombre = "0", "COALESCE(NULLIF(TRIM(e.especialidad), ''), @sin_especialidad)"
+	default:
+		id, nombre = "i.id", "i.nombre"
+		joins = fmt.Sprintf("JOIN %s i ON i.id = e.institucion_id AND i.deleted_at IS NULL", r.tabla(models.Institucion{}))
+	}
+	sql := fmt.Sprintf(`SELECT %s AS id, %s AS nombre, COUNT(DISTINCT e.id) AS total
+		FROM %s e %s
+		WHERE e.deleted_at IS NULL AND e.institucion_id IN (
+			SELECT pv.institucion_id FROM %s pv
+			WHERE pv.deleted_at IS NULL AND pv.fecha >= @desde AND pv.fecha < @hasta)
+		GROUP BY 1, 2 ORDER BY total DESC, nombre, id`,
+		id, nombre, r.tabla(models.Estudiante{}), joins, r.tabla(models.ProgramaVisita{}))
+	args := argumentos(rango)
+	args["sin_especialidad"] = SinEspecialidad
+	conteos := []ConteoGrupo{}
+	err := r.db.Raw(sql, args).Scan(&conteos).Error
+	return conteos, err
+}
+
+// TematicasPrincipales cuenta las visitas del rango por temática de sus actividades
+func (r *analiticaRepository) TematicasPrincipales(rango RangoAnalitica, limite int) ([]ConteoGrupo, error) {
+	return r.visitasPorActividad(rango, "t.id", "t.nombre", limite)
+}
+
+// ActividadesPrincipales cuenta las visitas del rango por actividad
+func (r *analiticaRepository) ActividadesPrincipales(rango RangoAnalitica, limite int) ([]ConteoGrupo, error) {
+	return r.visitasPorActividad(rango, "a.id", "a.actividad", limite)
+}
+
+// visitasPorActividad cuenta las visitas distintas del rango agrupando sus detalles por la
+// actividad (alias a) o su temática (alias t)
+func (r *analiticaRepository) visitasPorActividad(rango RangoAnalitica, id, nombre string, limite int) ([]ConteoGrupo, error) {
+	sql := fmt.Sprintf(`SELECT %s AS id, %s AS nombre, COUNT(DISTINCT pv.id) AS total
+		FROM %s vd
+		JOIN %s pv ON pv.id = vd.programa_visita_id AND pv.deleted_at IS NULL
+		JOIN %s a ON a.id = vd.actividad_id AND a.deleted_at IS NULL
+		JOIN %s t ON t.id = a.tematica_id AND t.deleted_at IS NULL
+		WHERE vd.deleted_at IS NULL AND pv.fecha >= @desde AND pv.fecha < @hasta
+		GROUP BY 1, 2 ORDER BY total DESC, nombre, id LIMIT @limite`,
+		id, nombre, r.tabla(models.VisitaDetalle{}), r.tabla(models.ProgramaVisita{}),
+		r.tabla(models.Actividad{}), r.tabla(models.Tematica{}))
+	args := argumentos(rango)
+	args["limite"] = limite
+	conteos := []ConteoGrupo{}
+	err := r.db.Raw(sql, args).Scan(&conteos).Error
+	return conteos, err
+}
+
+// CargaAutoridades cuenta por autoridad activa las visitas del rango a las que fue asignada y las
+// dudas que le asignaron y que se preguntaron en el rango
+func (r *analiticaRepository) CargaAutoridades(rango RangoAnalitica) ([]CargaAutoridad, error) {
+	dudas := fmt.Sprintf(`SELECT COUNT(*) FROM %s du
+			WHERE du.autoridad_uteq_id = au.id AND du.deleted_at IS NULL
+			AND du.fecha_pregunta >= @desde AND du.fecha_pregunta < @hasta`, r.tabla(models.Dudas{}))
+	sql := fmt.Sprintf(`SELECT au.id AS autoridad_id, p.nombre AS nombre, au.cargo AS cargo,
+		(SELECT COUNT(DISTINCT pv.id) FROM %s dav
+			JOIN %s pv ON pv.id = dav.programa_visita_id AND pv.deleted_at IS NULL
+			WHERE dav.autoridad_uteq_id = au.id AND dav.deleted_at IS NULL
+			AND pv.fecha >= @desde AND pv.fecha < @hasta) AS visitas,
+		(%s) AS dudas_asignadas,
+		(%s AND %s) AS dudas_respondidas
+		FROM %s au
+		JOIN %s p ON p.id = au.persona_id AND p.deleted_at IS NULL
+		WHERE au.deleted_at IS NULL
+		ORDER BY visitas DESC, dudas_asignadas DESC, nombre, autoridad_id`,
+		r.tabla(models.DetalleAutoridadDetallesVisita{}), r.tabla(models.ProgramaVisita{}),
+		dudas, dudas, dudaRespondidaSQL,
+		r.tabla(models.AutoridadUTEQ{}), r.tabla(models.Persona{}))
+	cargas := []CargaAutoridad{}
+	if err := r.db.Raw(sql, argumentos(rango)).Scan(&cargas).Error; err != nil {
+		return nil, err
+	}
+	for i := range cargas {
+		cargas[i].DudasPendientes = cargas[i].DudasAsignadas - cargas[i].DudasRespondidas
+	}
+	return cargas, nil
+}
+
+// TiemposRespuestaDudas resume las dudas preguntadas en el rango; las horas de respuesta solo
+// cuentan las dudas respondidas que tienen fecha de respuesta
+func (r *analiticaRepository) TiemposRespuestaDudas(rango RangoAnalitica, agrupacion string) (*TiemposRespuesta, []TiemposRespuesta, error) {
+	consulta := func(periodo, grupo string) ([]TiemposRespuesta, error) {
+		sql := fmt.Sprintf(`SELECT %s AS periodo, COUNT(*) AS preguntas,
+			COUNT(*) FILTER (WHERE respondida) AS respondidas,
+			AVG(horas) AS promedio_horas,
+			percentile_cont(0.5) WITHIN GROUP (ORDER BY horas) AS mediana_horas,
+			percentile_cont(0.9) WITHIN GROUP (ORDER BY horas) AS p90_horas
+			FROM (
+				SELECT du.fecha_pregunta, %s AS respondida,
+					CASE WHEN %s THEN (EXTRACT(EPOCH FROM du.fecha_respuesta - du.fecha_pregunta) / 3600)::float8 END AS horas
+				FROM %s du
+				WHERE du.deleted_at IS NULL AND du.fecha_pregunta >= @desde AND du.fecha_pregunta < @hasta
+			) d %s`,
+			periodo, dudaRespondidaSQL, dudaRespondidaSQL, r.tabla(models.Dudas{}), grupo)
+		tiempos := []TiemposRespuesta{}
+		if err := r.db.Raw(sql, argumentos(rango)).Scan(&tiempos).Error; err != nil {
+			return nil, err
+		}
+		for i := range tiempos {
+			tiempos[i].SinResponder = tiempos[i].Preguntas - tiempos[i].Respondidas
+		}
+		return tiempos, nil
+	}
+
+	total, err := consulta("''", "")
+	if err != nil {
+		return nil, nil, err
+	}
+	periodos, err := consulta(expresionPeriodo("d.fecha_pregunta", agrupacion), "GROUP BY 1 ORDER BY 1")
+	if err != nil {
+		return nil, nil, err
+	}
+	resumen := TiemposRespuesta{}
+	if len(total) > 0 {
+		resumen = total[0]
+	}
+	return &resumen, periodos, nil
+}
+
+// AlcanceComunicados resume por canal los comunicados creados en el rango
+func (r *analiticaRepository) AlcanceComunicados(rango RangoAnalitica) ([]AlcanceCanal, error) {
+	alcance := []AlcanceCanal{}
+	err := r.db.Model(&models.Comunicado{}).
+		Select(`canal, COUNT(*) AS comunicados,
+			COUNT(*) FILTER (WHERE estado = 'enviado') AS enviados,
+			COALESCE(SUM(enviado_a) FILTER (WHERE estado = 'enviado'), 0) AS destinatarios`).
+		Where("created_at >= ? AND created_at < ?", rango.Desde, rango.Hasta).
+		Group("canal").Order("comunicados DESC, canal").
+		Scan(&alcance).Error
+	return alcance, err
+}
@@ -1,49 +1,40 @@
 package repositories
 
 import (
+	"ApiEscuela/errores"
 	"ApiEscuela/models"
-	"errors"
-	"strings"
 
 	"gorm.io/gorm"
 )
 
-type AutoridadUTEQRepository struct {
+type autoridadUTEQRepository struct {
 	db *gorm.DB
 }
 
 var (
-	ErrAutoridadDuplicada = errors.New("autoridad ya existe")
+	ErrAutoridadDuplicada = errores.Conflicto("autoridad_duplicada", "La persona ya tiene un cargo asignado")
+	// ErrAutoridadSinPersona se devuelve al crear una autoridad para una persona inexistente
+	ErrAutoridadSinPersona = errores.Validacion("persona_no_existe", "No se encontró la persona con el ID especificado",
+		errores.Campo{Campo: "persona_id", Mensaje: "La persona no existe"})
 )
 
 func classifyUniqueAutoridadError(err error) error {
-	// Detección por mensaje si no está disponible pgconn
-	msg := err.Error()
-	if strings.Contains(msg, "duplicate key value") || strings.Contains(msg, "UNIQUE constraint") {
-		if strings.Contains(msg, "persona_id") {
-			return ErrAutoridadDuplicada
-		}
-		return ErrAutoridadDuplicada
-	}
-	if errors.Is(err, gorm.ErrDuplicatedKey) {
-		return ErrAutoridadDuplicada
-	}
-	return err
+	return traducirDuplicado(err, ErrAutoridadDuplicada)
 }
 
-func NewAutoridadUTEQRepository(db *gorm.DB) *AutoridadUTEQRepository {
-	return &AutoridadUTEQRepository{db: db}
+func NewAutoridadUTEQRepository(db *gorm.DB) AutoridadUTEQRepository {
+	return &autoridadUTEQRepository{db: db}
 }
 
 // CreateAutoridadUTEQ crea una nueva autoridad UTEQ
-func (r *AutoridadUTEQRepository) CreateAutoridadUTEQ(autoridad *models.AutoridadUTEQ) error {
+func (r *autoridadUTEQRepository) CreateAutoridadUTEQ(autoridad *models.AutoridadUTEQ) error {
 	// Verificar que la persona existe
 	var personaCount int64
 	if err := r.db.Model(&models.Persona{}).Where("id = ?", autoridad.PersonaID).Count(&personaCount).Error; err != nil {
 		return err
 	}
 	if personaCount == 0 {
-		return errors.New("persona no encontrada")
+		return ErrAutoridadSinPersona
 	}
 
 	// Prevalidar que no exista otra autoridad con la misma persona
@@ -61,7 +52,7 @@ func (r *AutoridadUTEQRepository) CreateAutoridadUTEQ(autoridad *models.Autorida
 }
 
 // GetAutoridadUTEQByID obtiene una autoridad UTEQ por ID
-func (r *AutoridadUTEQRepository) GetAutoridadUTEQByID(id uint) (*models.AutoridadUTEQ, error) {
+func (r *autoridadUTEQRepository) GetAutoridadUTEQByID(id uint) (*models.AutoridadUTEQ, error) {
 	var autoridad models.AutoridadUTEQ
 	err := r.db.Preload("Persona").Preload("DetalleAutoridadDetallesVisitas").
 		Preload("Dudas").First(&autoridad, id).Error
@@ -72,7 +63,7 @@ func (r *AutoridadUTEQRepository) GetAutoridadUTEQByID(id uint) (*models.Autorid
 }
 
 // GetAllAutoridadesUTEQ obtiene todas las autoridades UTEQ
-func (r *AutoridadUTEQRepository) GetAllAutoridadesUTEQ() ([]models.AutoridadUTEQ, error) {
+func (r *autoridadUTEQRepository) GetAllAutoridadesUTEQ() ([]models.AutoridadUTEQ, error) {
 	var autoridades []models.AutoridadUTEQ
 	err := r.db.Preload("Persona").Preload("DetalleAutoridadDetallesVisitas").
 		Preload("Dudas").Find(&autoridades).Error
@@ -80,7 +71,7 @@ func (r *AutoridadUTEQRepository) GetAllAutoridadesUTEQ() ([]models.AutoridadUTE
 }
 
 // UpdateAutoridadUTEQ actualiza una autoridad UTEQ
-func (r *AutoridadUTEQRepository) UpdateAutoridadUTEQ(autoridad *models.AutoridadUTEQ) error {
+func (r *autoridadUTEQRepository) UpdateAutoridadUTEQ(autoridad *models.AutoridadUTEQ) error {
 	// Verificar duplicado por persona en otro registro
 	var count int64
 	if err := r.db.Model(&models.AutoridadUTEQ{}).
@@ -98,7 +89,7 @@ func (r *AutoridadUTEQRepository) UpdateAutoridadUTEQ(autoridad *models.Autorida
 }
 
 // DeleteAutoridadUTEQ elimina una autoridad UTEQ y en cascada su usuario y persona
-func (r *AutoridadUTEQRepository) DeleteAutoridadUTEQ(id uint) error {
+func (r *autoridadUTEQRepository) DeleteAutoridadUTEQ(id uint) error {
 	// Iniciar transacción
 	tx := r.db.Begin()
 	if tx.Error != nil {
@@ -139,7 +130,7 @@ func (r *AutoridadUTEQRepository) DeleteAutoridadUTEQ(id uint) error {
 }
 
 // RestoreAutoridadUTEQ restaura una autoridad UTEQ eliminada y en cascada su usuario y persona
-func (r *AutoridadUTEQRepository) RestoreAutoridadUTEQ(id uint) error {
+func (r *autoridadUTEQRepository) RestoreAutoridadUTEQ(id uint) error {
 	// Iniciar transacción
 	tx := r.db.Begin()
 	if tx.Error != nil {
@@ -180,7 +171,7 @@ func (r *AutoridadUTEQRepository) RestoreAutoridadUTEQ(id uint) error {
 }
 
 // GetAllAutoridadesUTEQIncludingDeleted obtiene todas las autoridades UTEQ incluyendo las eliminadas
-func (r *AutoridadUTEQRepository) GetAllAutoridadesUTEQIncludingDeleted() ([]models.AutoridadUTEQ, error) {
+func (r *autoridadUTEQRepository) GetAllAutoridadesUTEQIncludingDeleted() ([]models.AutoridadUTEQ, error) {
 	var autoridades []models.AutoridadUTEQ
 	err := r.db.Unscoped().Preload("Persona").Preload("DetalleAutoridadDetallesVisitas").
 		Preload("Dudas").Find(&autoridades).Error
@@ -188,7 +179,7 @@ func (r *AutoridadUTEQRepository) GetAllAutoridadesUTEQIncludingDeleted() ([]mod
 }
 
 // GetDeletedAutoridadesUTEQ obtiene solo las autoridades UTEQ eliminadas
-func (r *AutoridadUTEQRepository) GetDeletedAutoridadesUTEQ() ([]models.AutoridadUTEQ, error) {
+func (r *autoridadUTEQRepository) GetDeletedAutoridadesUTEQ() ([]models.AutoridadUTEQ, error) {
 	var autoridades []models.AutoridadUTEQ
 	err := r.db.Unscoped().Where("deleted_at IS NOT NULL").
 		Preload("Persona").Preload("DetalleAutoridadDetallesVisitas").
@@ -197,7 +188,7 @@ func (r *AutoridadUTEQRepository) GetDeletedAutoridadesUTEQ() ([]models.Autorida
 }
 
 // GetAutoridadesUTEQByCargo obtiene autoridades por cargo
-func (r *AutoridadUTEQRepository) GetAutoridadesUTEQByCargo(cargo string) ([]models.AutoridadUTEQ, error) {
+func (r *autoridadUTEQRepository) GetAutoridadesUTEQByCargo(cargo string) ([]models.AutoridadUTEQ, error) {
 	var autoridades []models.AutoridadUTEQ
 	err := r.db.Where("cargo ILIKE ?", "%"+cargo+"%").
 		Preload("Persona").Preload("DetalleAutoridadDetallesVisitas").
@@ -206,7 +197,7 @@ func (r *AutoridadUTEQRepository) GetAutoridadesUTEQByCargo(cargo string) ([]mod
 }
 
 // GetAutoridadUTEQByPersona obtiene autoridad UTEQ por persona
-func (r *AutoridadUTEQRepository) GetAutoridadUTEQByPersona(personaID uint) (*models.AutoridadUTEQ, error) {
+func (r *autoridadUTEQRepository) GetAutoridadUTEQByPersona(personaID uint) (*models.AutoridadUTEQ, error) {
 	var autoridad models.AutoridadUTEQ
 	err := r.db.Where("persona_id = ?", personaID).
 		Preload("Persona").Preload("DetalleAutoridadDetallesVisitas").
@@ -216,3 +207,10 @@ func (r *AutoridadUTEQRepository) GetAutoridadUTEQByPersona(personaID uint) (*mo
 	}
 	return &autoridad, nil
 }
+
+// ExistsByID verifica si existe una autoridad con el ID indicado
+func (r *autoridadUTEQRepository) ExistsByID(id uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.AutoridadUTEQ{}).Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,140 @@
+package repositories
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"ApiEscuela/database"
+
+	"gorm.io/gorm"
+)
+
+// ResultadoBusqueda es un registro encontrado por una búsqueda de texto con su relevancia (ts_rank_cd
+// en la búsqueda de texto completo, similitud de trigramas en la búsqueda global) y, en la búsqueda
+// de texto completo, un fragmento con las coincidencias entre <mark> y </mark>. El resto del
+// fragmento se escapa como HTML.
+type ResultadoBusqueda[T any] struct {
+	Registro   T       `json:"registro"`
+	Relevancia float64 `json:"relevancia"`
+	Fragmento  string  `json:"fragmento,omitempty"`
+}
+
+// Registros devuelve los registros de los resultados en el mismo orden, del más relevante al menos
+// relevante; la versión 1 de la API responde las búsquedas solo con los registros
+func Registros[T any](resultados []ResultadoBusqueda[T]) []T {
+	registros := make([]T, len(resultados))
+	for i, resultado := range resultados {
+		registros[i] = resultado.Registro
+	}
+	return registros
+}
+
+// puntaje es la relevancia y el fragmento de un registro, que se calculan antes de leerlo
+type puntaje struct {
+	ID         uint
+	Relevancia float64
+	Fragmento  string
+}
+
+// opcionesFragmento configura ts_headline: hasta dos fragmentos con las coincidencias en <mark>
+const opcionesFragmento = `StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=" … "`
+
+// PalabrasBusqueda separa el término de búsqueda en palabras; los signos y espacios solo separan
+func PalabrasBusqueda(termino string) []string {
+	return strings.FieldsFunc(termino, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+}
+
+// ConsultaTexto arma la consulta tsquery del término: cada palabra debe coincidir con el inicio de
+// alguna palabra del texto. pesos limita la búsqueda a las columnas de esos pesos ("A" es el nombre
+// o el título); vacío busca en todas. Devuelve "" si el término no tiene palabras.
+func ConsultaTexto(termino, pesos string) string {
+	palabras := PalabrasBusqueda(termino)
+	for i, p := range palabras {
+		palabras[i] = p + ":*" + pesos
+	}
+	return strings.Join(palabras, " & ")
+}
+
+// formatosFechaBusqueda son los formatos de fecha que la búsqueda global reconoce en el término
+var formatosFechaBusqueda = []string{"2006-01-02", "02/01/2006"}
+
+// FechaBusqueda interpreta el término como una fecha (2025-05-10 o 10/05/2025); los programas de
+// visita se buscan por la fecha además de por el nombre de la institución
+func FechaBusqueda(termino string) (time.Time, bool) {
+	for _, formato := range formatosFechaBusqueda {
+		if fecha, err := time.ParseInLocation(formato, strings.TrimSpace(termino), time.Local); err == nil {
+			return fecha, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// SoloDigitos devuelve los dígitos del término, para comparar teléfonos escritos con espacios o guiones
+func SoloDigitos(termino string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, termino)
+}
+
+// patronLike escapa los comodines de LIKE para buscar el texto literal
+func patronLike(texto string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(texto)
+}
+
+// buscarTexto busca con la consulta tsquery en la columna busqueda de la tabla de T, de la más
+// relevante a la menos relevante; texto es la expresión SQL de la que se extrae el fragmento
+// resaltado y db lleva los Preload de los registros. Sin consulta no hay resultados.
+func buscarTexto[T any](db *gorm.DB, consulta, texto string) ([]ResultadoBusqueda[T], error) {
+	if consulta == "" {
+		return []ResultadoBusqueda[T]{}, nil
+	}
+	tsquery := fmt.Sprintf("to_tsquery('%s', ?)", database.ConfiguracionBusqueda)
+	var puntajes []puntaje
+	err := db.Session(&gorm.Session{NewDB: true}).Model(new(T)).
+		Select(fmt.Sprintf("id, ts_rank_cd(busqueda, %[1]s) AS relevancia, ts_headline('%[2]s', %[3]s, %[1]s, ?) AS fragmento",
+			tsquery, database.ConfiguracionBusqueda, escaparHTML(texto)), consulta, consulta, opcionesFragmento).
+		Where("busqueda @@ "+tsquery, consulta).
+		Order("relevancia DESC, id").
+		Scan(&puntajes).Error
+	if err != nil {
+		return nil, err
+	}
+	return cargarResultados[T](db, puntajes)
+}
+
+// cargarResultados lee con db los registros de los puntajes y los devuelve en el mismo orden
+func cargarResultados[T any](db *gorm.DB, puntajes []puntaje) ([]ResultadoBusqueda[T], error) {
+	resultados := make([]ResultadoBusqueda[T], 0, len(puntajes))
+	if len(puntajes) == 0 {
+		return resultados, nil
+	}
+	ids := make([]uint, len(puntajes))
+	for i, p := range puntajes {
+		ids[i] = p.ID
+	}
+	var registros []T
+	if err := db.Find(&registros, ids).Error; err != nil {
+		return nil, err
+	}
+	porID := make(map[uint]T, len(registros))
+	for _, registro := range registros {
+		porID[uint(reflect.ValueOf(registro).FieldByName("ID").Uint())] = registro
+	}
+	for _, p := range puntajes {
+		if registro, ok := porID[p.ID]; ok {
+			resultados = append(resultados, ResultadoBusqueda[T]{Registro: registro, Relevancia: p.Relevancia, Fragmento: p.Fragmento})
+		}
+	}
+	return resultados, nil
+}
+
+// escaparHTML escapa la expresión SQL de texto para que el fragmento solo contenga las etiquetas <mark>
+func escaparHTML(texto string) string {
+	return fmt.Sprintf("replace(replace(replace(%s, '&', '&amp;'), '<', '&lt;'), '>', '&gt;')", texto)
+}
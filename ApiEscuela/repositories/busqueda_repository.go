@@ -0,0 +1,122 @@
+package repositories
+
+import (
+	"strings"
+
+	"ApiEscuela/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DigitosTelefono es la cantidad mínima de dígitos del término para buscarlo en los teléfonos
+const DigitosTelefono = 4
+
+// nombreInstitucion es el nombre sin tildes de la institución unida al programa de visita con Joins("Institucion")
+const nombreInstitucion = `sin_tildes("Institucion".nombre)`
+
+type busquedaRepository struct {
+	db *gorm.DB
+}
+
+func NewBusquedaRepository(db *gorm.DB) BusquedaRepository {
+	return &busquedaRepository{db: db}
+}
+
+// BuscarPersonas busca personas cuyo nombre contiene todas las palabras del término, cuya cédula
+// empieza con él o cuyo correo o teléfono lo contienen
+func (r *busquedaRepository) BuscarPersonas(termino string, limite int) ([]ResultadoBusqueda[models.Persona], error) {
+	condicion := r.db.Where("cedula LIKE ?", patronLike(termino)+"%").
+		Or("lower(correo) LIKE ?", "%"+patronLike(strings.ToLower(termino))+"%")
+	if digitos := SoloDigitos(termino); len(digitos) >= DigitosTelefono {
+		condicion = condicion.Or(`regexp_replace(telefono, '\D', '', 'g') LIKE ?`, "%"+digitos+"%")
+	}
+	if palabras := PalabrasBusqueda(termino); len(palabras) > 0 {
+		condicion = condicion.Or(contienePalabras(r.db, "sin_tildes(nombre)", palabras))
+	}
+	return porSimilitud[models.Persona](r.db, "concat_ws(' ', nombre, cedula, correo, telefono)", termino, condicion, limite)
+}
+
+// BuscarInstituciones busca instituciones cuyo nombre contiene todas las palabras del término
+func (r *busquedaRepository) BuscarInstituciones(termino string, limite int) ([]ResultadoBusqueda[models.Institucion], error) {
+	palabras := PalabrasBusqueda(termino)
+	if len(palabras) == 0 {
+		return []ResultadoBusqueda[models.Institucion]{}, nil
+	}
+	return porSimilitud[models.Institucion](r.db, "nombre", termino, contienePalabras(r.db, "sin_tildes(nombre)", palabras), limite)
+}
+
+// BuscarProgramasVisita busca programas de visita de la fecha indicada en el término o cuya
+// institución contiene todas sus palabras, los más recientes primero
+func (r *busquedaRepository) BuscarProgramasVisita(termino string, limite int) ([]models.ProgramaVisita, error) {
+	programas := []models.ProgramaVisita{}
+	query := r.db.Joins("Institucion")
+	if fecha, ok := FechaBusqueda(termino); ok {
+		query = query.Where("fecha >= ? AND fecha < ?", fecha, fecha.AddDate(0, 0, 1))
+	} else if palabras := PalabrasBusqueda(termino); len(palabras) > 0 {
+		query = query.Where(contienePalabras(r.db, nombreInstitucion, palabras))
+	} else {
+		return programas, nil
+	}
+	err := query.Order("fecha DESC").Limit(limite).Find(&programas).Error
+	return programas, err
+}
+
+// AutocompletarPersonas sugiere personas por nombre o por el inicio de la cédula
+func (r *busquedaRepository) AutocompletarPersonas(termino string, limite int) ([]ResultadoBusqueda[models.Persona], error) {
+	condicion := parecido(r.db, "sin_tildes(nombre)", termino).Or("cedula LIKE ?", patronLike(termino)+"%")
+	return porSimilitud[models.Persona](r.db, "nombre", termino, condicion, limite)
+}
+
+// AutocompletarInstituciones sugiere instituciones por nombre
+func (r *busquedaRepository) AutocompletarInstituciones(termino string, limite int) ([]ResultadoBusqueda[models.Institucion], error) {
+	return porSimilitud[models.Institucion](r.db, "nombre", termino, parecido(r.db, "sin_tildes(nombre)", termino), limite)
+}
+
+// AutocompletarProgramasVisita sugiere programas de visita por el nombre de la institución, de la
+// institución más parecida a la menos parecida y luego los más recientes primero
+func (r *busquedaRepository) AutocompletarProgramasVisita(termino string, limite int) ([]models.ProgramaVisita, error) {
+	programas := []models.ProgramaVisita{}
+	err := r.db.Joins("Institucion").
+		Where(parecido(r.db, nombreInstitucion, termino)).
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "word_similarity(sin_tildes(?), " + nombreInstitucion + ") DESC, fecha DESC",
+			Vars:               []interface{}{termino},
+			WithoutParentheses: true,
+		}}).
+		Limit(limite).Find(&programas).Error
+	return programas, err
+}
+
+// porSimilitud devuelve hasta limite registros de T que cumplen la condición, de los más parecidos
+// al término a los menos parecidos y luego por nombre; la relevancia es word_similarity del
+// término con la expresión SQL
+func porSimilitud[T any](db *gorm.DB, expresion, termino string, condicion *gorm.DB, limite int) ([]ResultadoBusqueda[T], error) {
+	var puntajes []puntaje
+	err := db.Model(new(T)).
+		Select("id, word_similarity(sin_tildes(?), sin_tildes("+expresion+")) AS relevancia", termino).
+		Where(condicion).
+		Order("relevancia DESC, nombre, id").Limit(limite).
+		Scan(&puntajes).Error
+	if err != nil {
+		return nil, err
+	}
+	return cargarResultados[T](db, puntajes)
+}
+
+// contienePalabras arma la condición de que la expresión (ya sin tildes) contenga todas las palabras
+func contienePalabras(db *gorm.DB, expresion string, palabras []string) *gorm.DB {
+	condicion := db.Where(expresion+" LIKE '%' || sin_tildes(?) || '%'", palabras[0])
+	for _, p := range palabras[1:] {
+		condicion = condicion.Where(expresion+" LIKE '%' || sin_tildes(?) || '%'", p)
+	}
+	return condicion
+}
+
+// parecido arma la condición del autocompletado: la expresión (ya sin tildes) contiene el término o
+// alguna de sus partes se le parece según pg_trgm.word_similarity_threshold. Ambas usan el índice de
+// trigramas.
+func parecido(db *gorm.DB, expresion, termino string) *gorm.DB {
+	return db.Where("sin_tildes(?) <% "+expresion, termino).
+		Or(expresion+" LIKE '%' || sin_tildes(?) || '%'", patronLike(termino))
+}
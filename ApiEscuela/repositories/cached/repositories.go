@@ -0,0 +1,184 @@
+package cached
+
+import (
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+)
+
+// provinciaRepository guarda en caché la lista de provincias
+type provinciaRepository struct {
+	repositories.ProvinciaRepository
+	s *Store
+}
+
+// NewProvinciaRepository envuelve el repositorio con la caché
+func NewProvinciaRepository(inner repositories.ProvinciaRepository, s *Store) repositories.ProvinciaRepository {
+	return &provinciaRepository{ProvinciaRepository: inner, s: s}
+}
+
+// GetAllProvincias obtiene todas las provincias
+func (r *provinciaRepository) GetAllProvincias() ([]models.Provincia, error) {
+	return cachedList(r.s, grupoProvincias, r.ProvinciaRepository.GetAllProvincias)
+}
+
+// CreateProvincia crea una nueva provincia
+func (r *provinciaRepository) CreateProvincia(provincia *models.Provincia) error {
+	defer r.s.Invalidate(grupoProvincias)
+	return r.ProvinciaRepository.CreateProvincia(provincia)
+}
+
+// UpdateProvincia actualiza una provincia
+func (r *provinciaRepository) UpdateProvincia(provincia *models.Provincia) error {
+	defer r.s.Invalidate(grupoProvincias)
+	return r.ProvinciaRepository.UpdateProvincia(provincia)
+}
+
+// DeleteProvincia elimina una provincia
+func (r *provinciaRepository) DeleteProvincia(id uint) error {
+	defer r.s.Invalidate(grupoProvincias)
+	return r.ProvinciaRepository.DeleteProvincia(id)
+}
+
+// ciudadRepository guarda en caché la lista de ciudades
+type ciudadRepository struct {
+	repositories.CiudadRepository
+	s *Store
+}
+
+// NewCiudadRepository envuelve el repositorio con la caché
+func NewCiudadRepository(inner repositories.CiudadRepository, s *Store) repositories.CiudadRepository {
+	return &ciudadRepository{CiudadRepository: inner, s: s}
+}
+
+// GetAllCiudades obtiene todas las ciudades
+func (r *ciudadRepository) GetAllCiudades() ([]models.Ciudad, error) {
+	return cachedList(r.s, grupoCiudades, r.CiudadRepository.GetAllCiudades)
+}
+
+// CreateCiudad crea una nueva ciudad
+func (r *ciudadRepository) CreateCiudad(ciudad *models.Ciudad) error {
+	defer r.s.Invalidate(grupoCiudades)
+	return r.CiudadRepository.CreateCiudad(ciudad)
+}
+
+// UpdateCiudad actualiza una ciudad
+func (r *ciudadRepository) UpdateCiudad(ciudad *models.Ciudad) error {
+	defer r.s.Invalidate(grupoCiudades)
+	return r.CiudadRepository.UpdateCiudad(ciudad)
+}
+
+// DeleteCiudad elimina una ciudad
+func (r *ciudadRepository) DeleteCiudad(id uint) error {
+	defer r.s.Invalidate(grupoCiudades)
+	return r.CiudadRepository.DeleteCiudad(id)
+}
+
+// tematicaRepository guarda en caché la lista de temáticas
+type tematicaRepository struct {
+	repositories.TematicaRepository
+	s *Store
+}
+
+// NewTematicaRepository envuelve el repositorio con la caché
+func NewTematicaRepository(inner repositories.TematicaRepository, s *Store) repositories.TematicaRepository {
+	return &tematicaRepository{TematicaRepository: inner, s: s}
+}
+
+// GetAllTematicas obtiene todas las temáticas
+func (r *tematicaRepository) GetAllTematicas() ([]models.Tematica, error) {
+	return cachedList(r.s, grupoTematicas, r.TematicaRepository.GetAllTematicas)
+}
+
+// CreateTematica crea una nueva temática
+func (r *tematicaRepository) CreateTematica(tematica *models.Tematica) error {
+	defer r.s.Invalidate(grupoTematicas)
+	return r.TematicaRepository.CreateTematica(tematica)
+}
+
+// UpdateTematica actualiza una temática
+func (r *tematicaRepository) UpdateTematica(tematica *models.Tematica) error {
+	defer r.s.Invalidate(grupoTematicas)
+	return r.TematicaRepository.UpdateTematica(tematica)
+}
+
+// DeleteTematica elimina una temática
+func (r *tematicaRepository) DeleteTematica(id uint) error {
+	defer r.s.Invalidate(grupoTematicas)
+	return r.TematicaRepository.DeleteTematica(id)
+}
+
+// actividadRepository guarda en caché la lista de actividades
+type actividadRepository struct {
+	repositories.ActividadRepository
+	s *Store
+}
+
+// NewActividadRepository envuelve el repositorio con la caché
+func NewActividadRepository(inner repositories.ActividadRepository, s *Store) repositories.ActividadRepository {
+	return &actividadRepository{ActividadRepository: inner, s: s}
+}
+
+// GetAllActividades obtiene todas las actividades
+func (r *actividadRepository) GetAllActividades() ([]models.Actividad, error) {
+	return cachedList(r.s, grupoActividades, r.ActividadRepository.GetAllActividades)
+}
+
+// CreateActividad crea una nueva actividad
+func (r *actividadRepository) CreateActividad(actividad *models.Actividad) error {
+	defer r.s.Invalidate(grupoActividades)
+	return r.ActividadRepository.CreateActividad(actividad)
+}
+
+// UpdateActividad actualiza una actividad
+func (r *actividadRepository) UpdateActividad(actividad *models.Actividad) error {
+	defer r.s.Invalidate(grupoActividades)
+	return r.ActividadRepository.UpdateActividad(actividad)
+}
+
+// DeleteActividad elimina una actividad
+func (r *actividadRepository) DeleteActividad(id uint) error {
+	defer r.s.Invalidate(grupoActividades)
+	return r.ActividadRepository.DeleteActividad(id)
+}
+
+// visitaDetalleRepository no guarda nada en caché: sus escrituras invalidan la lista de actividades,
+// que precarga los visita detalles
+type visitaDetalleRepository struct {
+	repositories.VisitaDetalleRepository
+	s *Store
+}
+
+// NewVisitaDetalleRepository envuelve el repositorio para invalidar la caché al escribir
+func NewVisitaDetalleRepository(inner repositories.VisitaDetalleRepository, s *Store) repositories.VisitaDetalleRepository {
+	return &visitaDetalleRepository{VisitaDetalleRepository: inner, s: s}
+}
+
+// CreateVisitaDetalle crea un nuevo visita detalle
+func (r *visitaDetalleRepository) CreateVisitaDetalle(detalle *models.VisitaDetalle) error {
+	defer r.s.Invalidate(grupoVisitaDetalles)
+	return r.VisitaDetalleRepository.CreateVisitaDetalle(detalle)
+}
+
+// UpdateVisitaDetalle actualiza un visita detalle
+func (r *visitaDetalleRepository) UpdateVisitaDetalle(detalle *models.VisitaDetalle) error {
+	defer r.s.Invalidate(grupoVisitaDetalles)
+	return r.VisitaDetalleRepository.UpdateVisitaDetalle(detalle)
+}
+
+// DeleteVisitaDetalle elimina un visita detalle
+func (r *visitaDetalleRepository) DeleteVisitaDetalle(id uint) error {
+	defer r.s.Invalidate(grupoVisitaDetalles)
+	return r.VisitaDetalleRepository.DeleteVisitaDetalle(id)
+}
+
+// DeleteVisitaDetallesByPrograma elimina los visita detalles de un programa
+func (r *visitaDetalleRepository) DeleteVisitaDetallesByPrograma(programaID uint) error {
+	defer r.s.Invalidate(grupoVisitaDetalles)
+	return r.VisitaDetalleRepository.DeleteVisitaDetallesByPrograma(programaID)
+}
+
+// DeleteVisitaDetallesByActividad elimina los visita detalles de una actividad
+func (r *visitaDetalleRepository) DeleteVisitaDetallesByActividad(actividadID uint) error {
+	defer r.s.Invalidate(grupoVisitaDetalles)
+	return r.VisitaDetalleRepository.DeleteVisitaDetallesByActividad(actividadID)
+}
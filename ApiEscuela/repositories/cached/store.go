@@ -0,0 +1,111 @@
+// Package cached envuelve los repositorios de catálogos (provincias, ciudades, temáticas y
+// actividades) con una caché en memoria de sus listas completas.
+//
+// Las escrituras hechas a través de estos repositorios invalidan la lista del recurso y las de los
+// recursos que la incluyen por Preload. Las escrituras de otros procesos (comandos, otras réplicas)
+// se reflejan cuando la entrada expira (TTL).
+//
+// Los repositorios embeben la interfaz original: un método de escritura nuevo debe sobrescribirse
+// aquí para que invalide la caché.
+package cached
+
+import (
+	"sync"
+	"time"
+)
+
+// Grupos de la caché, uno por recurso
+const (
+	grupoProvincias     = "provincias"
+	grupoCiudades       = "ciudades"
+	grupoTematicas      = "tematicas"
+	grupoActividades    = "actividades"
+	grupoVisitaDetalles = "visita-detalles"
+)
+
+// dependientes son los grupos cuyas listas precargan registros del grupo clave
+var dependientes = map[string][]string{
+	grupoProvincias:     {grupoCiudades},    // las ciudades incluyen su provincia
+	grupoCiudades:       {grupoProvincias},  // las provincias incluyen sus ciudades
+	grupoTematicas:      {grupoActividades}, // las actividades incluyen su temática
+	grupoActividades:    {grupoTematicas},   // las temáticas incluyen sus actividades
+	grupoVisitaDetalles: {grupoActividades}, // las actividades incluyen sus visita detalles
+}
+
+// Store guarda las listas en memoria; es seguro para uso concurrente
+type Store struct {
+	mu       sync.RWMutex
+	ttl      time.Duration
+	entradas map[string]entrada
+	// generacion aumenta con cada invalidación; una carga que empezó antes no se guarda
+	generacion uint64
+	now        func() time.Time
+}
+
+type entrada struct {
+	valor  interface{}
+	expira time.Time
+}
+
+// NewStore crea una caché cuyas entradas expiran después de ttl; con ttl <= 0 no guarda nada
+func NewStore(ttl time.Duration) *Store {
+	return &Store{ttl: ttl, entradas: map[string]entrada{}, now: time.Now}
+}
+
+// get devuelve la entrada vigente del grupo y la generación actual
+func (s *Store) get(grupo string) (interface{}, uint64, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	e, ok := s.entradas[grupo]
+	if !ok || !s.now().Before(e.expira) {
+		return nil, s.generacion, false
+	}
+	return e.valor, s.generacion, true
+}
+
+// set guarda el valor del grupo si no hubo invalidaciones desde la generación indicada
+func (s *Store) set(grupo string, valor interface{}, generacion uint64) {
+	if s.ttl <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if generacion != s.generacion {
+		return
+	}
+	s.entradas[grupo] = entrada{valor: valor, expira: s.now().Add(s.ttl)}
+}
+
+// Invalidate descarta el grupo y los grupos que lo incluyen
+func (s *Store) Invalidate(grupo string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.generacion++
+	delete(s.entradas, grupo)
+	for _, d := range dependientes[grupo] {
+		delete(s.entradas, d)
+	}
+}
+
+// InvalidateAll descarta todas las entradas (por ejemplo, después de restaurar un respaldo)
+func (s *Store) InvalidateAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.generacion++
+	s.entradas = map[string]entrada{}
+}
+
+// cachedList devuelve la lista del grupo desde la caché o la carga con load.
+// Siempre devuelve una copia del slice para que el llamador pueda modificarlo.
+func cachedList[T any](s *Store, grupo string, load func() ([]T, error)) ([]T, error) {
+	v, generacion, ok := s.get(grupo)
+	if ok {
+		return append([]T(nil), v.([]T)...), nil
+	}
+	lista, err := load()
+	if err != nil {
+		return nil, err
+	}
+	s.set(grupo, append([]T(nil), lista...), generacion)
+	return lista, nil
+}
@@ -0,0 +1,94 @@
+package cached
+
+import (
+	"testing"
+	"time"
+
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+	"ApiEscuela/repositories/memory"
+)
+
+// contador cuenta las lecturas que llegan al repositorio envuelto
+type contador struct {
+	repositories.ProvinciaRepository
+	lecturas int
+}
+
+func (c *contador) GetAllProvincias() ([]models.Provincia, error) {
+	c.lecturas++
+	return c.ProvinciaRepository.GetAllProvincias()
+}
+
+func TestProvinciasCacheadas(t *testing.T) {
+	m := memory.NewRepositories()
+	inner := &contador{ProvinciaRepository: m.Provincia}
+	s := NewStore(time.Minute)
+	ahora := time.Now()
+	s.now = func() time.Time { return ahora }
+	repo := NewProvinciaRepository(inner, s)
+
+	if err := repo.CreateProvincia(&models.Provincia{Provincia: "Los Ríos"}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		lista, err := repo.GetAllProvincias()
+		if err != nil || len(lista) != 1 {
+			t.Fatalf("GetAllProvincias = %v, %v", lista, err)
+		}
+		// Modificar la copia devuelta no debe alterar la caché
+		lista[0].Provincia = "otra"
+	}
+	if inner.lecturas != 1 {
+		t.Errorf("lecturas = %d, se esperaba 1", inner.lecturas)
+	}
+	if lista, _ := repo.GetAllProvincias(); lista[0].Provincia != "Los Ríos" {
+		t.Errorf("la caché devolvió una lista modificada: %+v", lista)
+	}
+
+	if err := repo.CreateProvincia(&models.Provincia{Provincia: "Pichincha"}); err != nil {
+		t.Fatal(err)
+	}
+	if lista, _ := repo.GetAllProvincias(); len(lista) != 2 || inner.lecturas != 2 {
+		t.Errorf("la escritura debe invalidar la caché: %d provincias, %d lecturas", len(lista), inner.lecturas)
+	}
+
+	ahora = ahora.Add(time.Minute)
+	repo.GetAllProvincias()
+	if inner.lecturas != 3 {
+		t.Errorf("la entrada vencida debe recargarse: %d lecturas", inner.lecturas)
+	}
+}
+
+func TestInvalidateDependientes(t *testing.T) {
+	s := NewStore(time.Minute)
+	for _, g := range []string{grupoProvincias, grupoCiudades, grupoTematicas, grupoActividades} {
+		s.set(g, []int{1}, 0)
+	}
+	s.Invalidate(grupoVisitaDetalles)
+	if _, _, ok := s.get(grupoActividades); ok {
+		t.Error("los visita detalles deben invalidar las actividades")
+	}
+	if _, _, ok := s.get(grupoTematicas); !ok {
+		t.Error("las temáticas no dependen de los visita detalles")
+	}
+	s.Invalidate(grupoCiudades)
+	if _, _, ok := s.get(grupoProvincias); ok {
+		t.Error("las ciudades deben invalidar las provincias")
+	}
+}
+
+func TestCargaConcurrenteConInvalidacion(t *testing.T) {
+	s := NewStore(time.Minute)
+	// Una escritura que termina mientras se carga la lista no debe dejar datos viejos en caché
+	lista, _ := cachedList(s, grupoProvincias, func() ([]int, error) {
+		s.Invalidate(grupoProvincias)
+		return []int{1}, nil
+	})
+	if len(lista) != 1 {
+		t.Fatalf("lista = %v", lista)
+	}
+	if _, _, ok := s.get(grupoProvincias); ok {
+		t.Error("la carga iniciada antes de la invalidación no debe guardarse")
+	}
+}
@@ -6,26 +6,21 @@ import (
 	"gorm.io/gorm"
 )
 
-type CiudadRepository struct {
+type ciudadRepository struct {
 	db *gorm.DB
 }
 
-func NewCiudadRepository(db *gorm.DB) *CiudadRepository {
-	return &CiudadRepository{db: db}
-}
-
-// GetDB retorna la instancia de la base de datos para uso interno
-func (r *CiudadRepository) GetDB() *gorm.DB {
-	return r.db
+func NewCiudadRepository(db *gorm.DB) CiudadRepository {
+	return &ciudadRepository{db: db}
 }
 
 // CreateCiudad crea una nueva ciudad
-func (r *CiudadRepository) CreateCiudad(ciudad *models.Ciudad) error {
+func (r *ciudadRepository) CreateCiudad(ciudad *models.Ciudad) error {
 	return r.db.Create(ciudad).Error
 }
 
 // GetCiudadByID obtiene una ciudad por ID
-func (r *CiudadRepository) GetCiudadByID(id uint) (*models.Ciudad, error) {
+func (r *ciudadRepository) GetCiudadByID(id uint) (*models.Ciudad, error) {
 	var ciudad models.Ciudad
 	err := r.db.Preload("Provincia").Preload("Estudiantes").
 		First(&ciudad, id).Error
@@ -36,24 +31,24 @@ func (r *CiudadRepository) GetCiudadByID(id uint) (*models.Ciudad, error) {
 }
 
 // GetAllCiudades obtiene todas las ciudades
-func (r *CiudadRepository) GetAllCiudades() ([]models.Ciudad, error) {
+func (r *ciudadRepository) GetAllCiudades() ([]models.Ciudad, error) {
 	var ciudades []models.Ciudad
 	err := r.db.Preload("Provincia").Find(&ciudades).Error
 	return ciudades, err
 }
 
 // UpdateCiudad actualiza una ciudad
-func (r *CiudadRepository) UpdateCiudad(ciudad *models.Ciudad) error {
+func (r *ciudadRepository) UpdateCiudad(ciudad *models.Ciudad) error {
 	return r.db.Save(ciudad).Error
 }
 
 // DeleteCiudad elimina una ciudad
-func (r *CiudadRepository) DeleteCiudad(id uint) error {
+func (r *ciudadRepository) DeleteCiudad(id uint) error {
 	return r.db.Delete(&models.Ciudad{}, id).Error
 }
 
 // GetCiudadesByProvincia obtiene ciudades por provincia
-func (r *CiudadRepository) GetCiudadesByProvincia(provinciaID uint) ([]models.Ciudad, error) {
+func (r *ciudadRepository) GetCiudadesByProvincia(provinciaID uint) ([]models.Ciudad, error) {
 	var ciudades []models.Ciudad
 	err := r.db.Where("provincia_id = ?", provinciaID).
 		Preload("Provincia").Find(&ciudades).Error
@@ -61,9 +56,16 @@ func (r *CiudadRepository) GetCiudadesByProvincia(provinciaID uint) ([]models.Ci
 }
 
 // GetCiudadByNombre busca ciudad por nombre
-func (r *CiudadRepository) GetCiudadByNombre(nombre string) ([]models.Ciudad, error) {
+func (r *ciudadRepository) GetCiudadByNombre(nombre string) ([]models.Ciudad, error) {
 	var ciudades []models.Ciudad
 	err := r.db.Where("ciudad ILIKE ?", "%"+nombre+"%").
 		Preload("Provincia").Find(&ciudades).Error
 	return ciudades, err
 }
+
+// ExistsByID verifica si existe una ciudad con el ID indicado
+func (r *ciudadRepository) ExistsByID(id uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Ciudad{}).Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
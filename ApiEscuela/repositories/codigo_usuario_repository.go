@@ -14,16 +14,16 @@ const (
 	EstadoExpirado   = "expirado"
 )
 
-type CodigoUsuarioRepository struct {
+type codigoUsuarioRepository struct {
 	db *gorm.DB
 }
 
-func NewCodigoUsuarioRepository(db *gorm.DB) *CodigoUsuarioRepository {
-	return &CodigoUsuarioRepository{db: db}
+func NewCodigoUsuarioRepository(db *gorm.DB) CodigoUsuarioRepository {
+	return &codigoUsuarioRepository{db: db}
 }
 
 // Crear inserta un nuevo código para un usuario con expiración de 3 minutos
-func (r *CodigoUsuarioRepository) Crear(usuarioID uint, codigo string) error {
+func (r *codigoUsuarioRepository) Crear(usuarioID uint, codigo string) error {
 	expiraEn := time.Now().Add(3 * time.Minute)
 	record := &models.CodigoUsuario{
 		UsuarioID: usuarioID,
@@ -35,7 +35,7 @@ func (r *CodigoUsuarioRepository) Crear(usuarioID uint, codigo string) error {
 }
 
 // ExisteVigentePorUsuario verifica si el usuario tiene un código válido y no expirado
-func (r *CodigoUsuarioRepository) ExisteVigentePorUsuario(usuarioID uint) (bool, error) {
+func (r *codigoUsuarioRepository) ExisteVigentePorUsuario(usuarioID uint) (bool, error) {
 	var count int64
 	err := r.db.Model(&models.CodigoUsuario{}).
 		Where("usuario_id = ? AND estado = ? AND expira_en IS NOT NULL AND expira_en > ?", usuarioID, EstadoValido, time.Now()).
@@ -44,7 +44,7 @@ func (r *CodigoUsuarioRepository) ExisteVigentePorUsuario(usuarioID uint) (bool,
 }
 
 // FindLatestByCodigo obtiene el último registro creado para un código dado
-func (r *CodigoUsuarioRepository) FindLatestByCodigo(codigo string) (*models.CodigoUsuario, error) {
+func (r *codigoUsuarioRepository) FindLatestByCodigo(codigo string) (*models.CodigoUsuario, error) {
 	var rec models.CodigoUsuario
 	err := r.db.Where("codigo = ?", codigo).Order("created_at DESC").First(&rec).Error
 	if err != nil {
@@ -54,12 +54,12 @@ func (r *CodigoUsuarioRepository) FindLatestByCodigo(codigo string) (*models.Cod
 }
 
 // Update actualiza un registro de código de usuario
-func (r *CodigoUsuarioRepository) Update(rec *models.CodigoUsuario) error {
+func (r *codigoUsuarioRepository) Update(rec *models.CodigoUsuario) error {
 	return r.db.Save(rec).Error
 }
 
 // GetByID obtiene un código por su ID
-func (r *CodigoUsuarioRepository) GetByID(id uint) (*models.CodigoUsuario, error) {
+func (r *codigoUsuarioRepository) GetByID(id uint) (*models.CodigoUsuario, error) {
 	var rec models.CodigoUsuario
 	err := r.db.First(&rec, id).Error
 	if err != nil {
@@ -69,7 +69,7 @@ func (r *CodigoUsuarioRepository) GetByID(id uint) (*models.CodigoUsuario, error
 }
 
 // MarcarComoVerificado marca un código como verificado
-func (r *CodigoUsuarioRepository) MarcarComoVerificado(id uint) error {
+func (r *codigoUsuarioRepository) MarcarComoVerificado(id uint) error {
 	return r.db.Model(&models.CodigoUsuario{}).Where("id = ?", id).Updates(map[string]interface{}{
 		"estado":    EstadoVerificado,
 		"expira_en": nil,
@@ -77,34 +77,39 @@ func (r *CodigoUsuarioRepository) MarcarComoVerificado(id uint) error {
 }
 
 // MarcarComoExpirado marca un código como expirado
-func (r *CodigoUsuarioRepository) MarcarComoExpirado(id uint) error {
+func (r *codigoUsuarioRepository) MarcarComoExpirado(id uint) error {
 	return r.db.Model(&models.CodigoUsuario{}).Where("id = ?", id).Update("estado", EstadoExpirado).Error
 }
 
 // GetCodigosValidosExpirados obtiene códigos que están en estado válido pero ya expiraron por tiempo
-func (r *CodigoUsuarioRepository) GetCodigosValidosExpirados(usuarioID uint) ([]models.CodigoUsuario, error) {
+func (r *codigoUsuarioRepository) GetCodigosValidosExpirados(usuarioID uint) ([]models.CodigoUsuario, error) {
 	var codigos []models.CodigoUsuario
 	err := r.db.Where("usuario_id = ? AND estado = ? AND expira_en IS NOT NULL AND expira_en <= ?", 
 		usuarioID, EstadoValido, time.Now()).Find(&codigos).Error
 	return codigos, err
 }
 
-// MigrarColumnaExpiraEn permite valores NULL en la columna expira_en
-func (r *CodigoUsuarioRepository) MigrarColumnaExpiraEn() error {
-	// Primero, agregar la columna estado si no existe
-	if err := r.db.Exec("ALTER TABLE codigosusuarios ADD COLUMN IF NOT EXISTS estado VARCHAR(20) DEFAULT 'valido'").Error; err != nil {
-		return err
-	}
-	
-	// Crear índice para estado si no existe
-	if err := r.db.Exec("CREATE INDEX IF NOT EXISTS idx_codigosusuarios_estado ON codigosusuarios(estado)").Error; err != nil {
-		return err
-	}
-	
-	// Modificar la columna expira_en para permitir NULL
-	if err := r.db.Exec("ALTER TABLE codigosusuarios ALTER COLUMN expira_en DROP NOT NULL").Error; err != nil {
-		return err
-	}
-	
-	return nil
+// ExpirarVigentesPorUsuario marca como expirados todos los códigos válidos del usuario
+func (r *codigoUsuarioRepository) ExpirarVigentesPorUsuario(usuarioID uint) (int64, error) {
+	result := r.db.Model(&models.CodigoUsuario{}).
+		Where("usuario_id = ? AND estado = ?", usuarioID, EstadoValido).
+		Update("estado", EstadoExpirado)
+	return result.RowsAffected, result.Error
+}
+
+// ExpirarVencidos marca como expirados los códigos válidos cuya fecha de expiración ya pasó
+func (r *codigoUsuarioRepository) ExpirarVencidos(ahora time.Time) (int64, error) {
+	result := r.db.Model(&models.CodigoUsuario{}).
+		Where("estado = ? AND expira_en IS NOT NULL AND expira_en <= ?", EstadoValido, ahora).
+		Update("estado", EstadoExpirado)
+	return result.RowsAffected, result.Error
+}
+
+// PurgarExpirados elimina definitivamente los códigos que dejaron de ser utilizables antes de la fecha indicada
+func (r *codigoUsuarioRepository) PurgarExpirados(antes time.Time) (int64, error) {
+	result := r.db.Unscoped().
+		Where("(estado <> ? AND updated_at < ?) OR (estado = ? AND expira_en IS NOT NULL AND expira_en < ?)",
+			EstadoValido, antes, EstadoValido, antes).
+		Delete(&models.CodigoUsuario{})
+	return result.RowsAffected, result.Error
 }
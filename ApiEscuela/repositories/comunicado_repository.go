@@ -6,26 +6,21 @@ import (
 	"gorm.io/gorm"
 )
 
-type ComunicadoRepository struct {
+type comunicadoRepository struct {
 	db *gorm.DB
 }
 
-func NewComunicadoRepository(db *gorm.DB) *ComunicadoRepository {
-	return &ComunicadoRepository{db: db}
-}
-
-// GetDB retorna la instancia de la base de datos
-func (r *ComunicadoRepository) GetDB() *gorm.DB {
-	return r.db
+func NewComunicadoRepository(db *gorm.DB) ComunicadoRepository {
+	return &comunicadoRepository{db: db}
 }
 
 // CreateComunicado crea un nuevo comunicado
-func (r *ComunicadoRepository) CreateComunicado(comunicado *models.Comunicado) error {
+func (r *comunicadoRepository) CreateComunicado(comunicado *models.Comunicado) error {
 	return r.db.Create(comunicado).Error
 }
 
 // GetComunicadoByID obtiene un comunicado por ID
-func (r *ComunicadoRepository) GetComunicadoByID(id uint) (*models.Comunicado, error) {
+func (r *comunicadoRepository) GetComunicadoByID(id uint) (*models.Comunicado, error) {
 	var comunicado models.Comunicado
 	err := r.db.Preload("Usuario").Preload("Usuario.Persona").
 		First(&comunicado, id).Error
@@ -36,7 +31,7 @@ func (r *ComunicadoRepository) GetComunicadoByID(id uint) (*models.Comunicado, e
 }
 
 // GetAllComunicados obtiene todos los comunicados ordenados por fecha de creación descendente
-func (r *ComunicadoRepository) GetAllComunicados() ([]models.Comunicado, error) {
+func (r *comunicadoRepository) GetAllComunicados() ([]models.Comunicado, error) {
 	var comunicados []models.Comunicado
 	err := r.db.Preload("Usuario").Preload("Usuario.Persona").
 		Order("created_at DESC").
@@ -45,17 +40,17 @@ func (r *ComunicadoRepository) GetAllComunicados() ([]models.Comunicado, error)
 }
 
 // UpdateComunicado actualiza un comunicado
-func (r *ComunicadoRepository) UpdateComunicado(comunicado *models.Comunicado) error {
+func (r *comunicadoRepository) UpdateComunicado(comunicado *models.Comunicado) error {
 	return r.db.Save(comunicado).Error
 }
 
 // DeleteComunicado elimina un comunicado
-func (r *ComunicadoRepository) DeleteComunicado(id uint) error {
+func (r *comunicadoRepository) DeleteComunicado(id uint) error {
 	return r.db.Delete(&models.Comunicado{}, id).Error
 }
 
 // GetComunicadosByUsuario obtiene comunicados por usuario
-func (r *ComunicadoRepository) GetComunicadosByUsuario(usuarioID uint) ([]models.Comunicado, error) {
+func (r *comunicadoRepository) GetComunicadosByUsuario(usuarioID uint) ([]models.Comunicado, error) {
 	var comunicados []models.Comunicado
 	err := r.db.Where("usuario_id = ?", usuarioID).
 		Preload("Usuario").Preload("Usuario.Persona").
@@ -64,12 +59,9 @@ func (r *ComunicadoRepository) GetComunicadosByUsuario(usuarioID uint) ([]models
 	return comunicados, err
 }
 
-// SearchComunicados busca comunicados por asunto
-func (r *ComunicadoRepository) SearchComunicados(termino string) ([]models.Comunicado, error) {
-	var comunicados []models.Comunicado
-	err := r.db.Where("asunto ILIKE ?", "%"+termino+"%").
-		Preload("Usuario").Preload("Usuario.Persona").
-		Order("created_at DESC").
-		Find(&comunicados).Error
-	return comunicados, err
+// SearchComunicados busca comunicados por las palabras del asunto o del mensaje, sin distinguir
+// tildes; las coincidencias en el asunto pesan más
+func (r *comunicadoRepository) SearchComunicados(termino string) ([]ResultadoBusqueda[models.Comunicado], error) {
+	return buscarTexto[models.Comunicado](r.db.Preload("Usuario").Preload("Usuario.Persona"), ConsultaTexto(termino, ""),
+		`concat_ws(' — ', asunto, regexp_replace(mensaje, '<[^>]*>', ' ', 'g'))`)
 }
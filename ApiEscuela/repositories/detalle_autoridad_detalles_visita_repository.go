@@ -5,21 +5,21 @@ import (
 	"gorm.io/gorm"
 )
 
-type DetalleAutoridadDetallesVisitaRepository struct {
+type detalleAutoridadDetallesVisitaRepository struct {
 	db *gorm.DB
 }
 
-func NewDetalleAutoridadDetallesVisitaRepository(db *gorm.DB) *DetalleAutoridadDetallesVisitaRepository {
-	return &DetalleAutoridadDetallesVisitaRepository{db: db}
+func NewDetalleAutoridadDetallesVisitaRepository(db *gorm.DB) DetalleAutoridadDetallesVisitaRepository {
+	return &detalleAutoridadDetallesVisitaRepository{db: db}
 }
 
 // CreateDetalleAutoridadDetallesVisita crea un nuevo detalle de autoridad para visita
-func (r *DetalleAutoridadDetallesVisitaRepository) CreateDetalleAutoridadDetallesVisita(detalle *models.DetalleAutoridadDetallesVisita) error {
+func (r *detalleAutoridadDetallesVisitaRepository) CreateDetalleAutoridadDetallesVisita(detalle *models.DetalleAutoridadDetallesVisita) error {
 	return r.db.Create(detalle).Error
 }
 
 // GetDetalleAutoridadDetallesVisitaByID obtiene un detalle por ID
-func (r *DetalleAutoridadDetallesVisitaRepository) GetDetalleAutoridadDetallesVisitaByID(id uint) (*models.DetalleAutoridadDetallesVisita, error) {
+func (r *detalleAutoridadDetallesVisitaRepository) GetDetalleAutoridadDetallesVisitaByID(id uint) (*models.DetalleAutoridadDetallesVisita, error) {
 	var detalle models.DetalleAutoridadDetallesVisita
 	err := r.db.Preload("ProgramaVisita").
 		Preload("ProgramaVisita.Institucion").
@@ -32,7 +32,7 @@ func (r *DetalleAutoridadDetallesVisitaRepository) GetDetalleAutoridadDetallesVi
 }
 
 // GetAllDetalleAutoridadDetallesVisitas obtiene todos los detalles
-func (r *DetalleAutoridadDetallesVisitaRepository) GetAllDetalleAutoridadDetallesVisitas() ([]models.DetalleAutoridadDetallesVisita, error) {
+func (r *detalleAutoridadDetallesVisitaRepository) GetAllDetalleAutoridadDetallesVisitas() ([]models.DetalleAutoridadDetallesVisita, error) {
 	var detalles []models.DetalleAutoridadDetallesVisita
 	err := r.db.Preload("ProgramaVisita").
 		Preload("ProgramaVisita.Institucion").
@@ -42,17 +42,17 @@ func (r *DetalleAutoridadDetallesVisitaRepository) GetAllDetalleAutoridadDetalle
 }
 
 // UpdateDetalleAutoridadDetallesVisita actualiza un detalle
-func (r *DetalleAutoridadDetallesVisitaRepository) UpdateDetalleAutoridadDetallesVisita(detalle *models.DetalleAutoridadDetallesVisita) error {
+func (r *detalleAutoridadDetallesVisitaRepository) UpdateDetalleAutoridadDetallesVisita(detalle *models.DetalleAutoridadDetallesVisita) error {
 	return r.db.Save(detalle).Error
 }
 
 // DeleteDetalleAutoridadDetallesVisita elimina un detalle
-func (r *DetalleAutoridadDetallesVisitaRepository) DeleteDetalleAutoridadDetallesVisita(id uint) error {
+func (r *detalleAutoridadDetallesVisitaRepository) DeleteDetalleAutoridadDetallesVisita(id uint) error {
 	return r.db.Delete(&models.DetalleAutoridadDetallesVisita{}, id).Error
 }
 
 // GetDetallesByProgramaVisitaID obtiene todos los detalles de un programa de visita específico
-func (r *DetalleAutoridadDetallesVisitaRepository) GetDetallesByProgramaVisitaID(programaVisitaID uint) ([]models.DetalleAutoridadDetallesVisita, error) {
+func (r *detalleAutoridadDetallesVisitaRepository) GetDetallesByProgramaVisitaID(programaVisitaID uint) ([]models.DetalleAutoridadDetallesVisita, error) {
 	var detalles []models.DetalleAutoridadDetallesVisita
 	err := r.db.Where("programa_visita_id = ?", programaVisitaID).
 		Preload("ProgramaVisita").Preload("AutoridadUTEQ").Find(&detalles).Error
@@ -60,7 +60,7 @@ func (r *DetalleAutoridadDetallesVisitaRepository) GetDetallesByProgramaVisitaID
 }
 
 // GetDetallesByAutoridadID obtiene todos los detalles de una autoridad específica
-func (r *DetalleAutoridadDetallesVisitaRepository) GetDetallesByAutoridadID(autoridadID uint) ([]models.DetalleAutoridadDetallesVisita, error) {
+func (r *detalleAutoridadDetallesVisitaRepository) GetDetallesByAutoridadID(autoridadID uint) ([]models.DetalleAutoridadDetallesVisita, error) {
 	var detalles []models.DetalleAutoridadDetallesVisita
 	err := r.db.Where("autoridad_uteq_id = ?", autoridadID).
 		Preload("ProgramaVisita").Preload("AutoridadUTEQ").Find(&detalles).Error
@@ -68,17 +68,17 @@ func (r *DetalleAutoridadDetallesVisitaRepository) GetDetallesByAutoridadID(auto
 }
 
 // DeleteDetallesByProgramaVisitaID elimina todos los detalles de un programa de visita
-func (r *DetalleAutoridadDetallesVisitaRepository) DeleteDetallesByProgramaVisitaID(programaVisitaID uint) error {
+func (r *detalleAutoridadDetallesVisitaRepository) DeleteDetallesByProgramaVisitaID(programaVisitaID uint) error {
 	return r.db.Where("programa_visita_id = ?", programaVisitaID).Delete(&models.DetalleAutoridadDetallesVisita{}).Error
 }
 
 // DeleteDetallesByAutoridadID elimina todos los detalles de una autoridad específica
-func (r *DetalleAutoridadDetallesVisitaRepository) DeleteDetallesByAutoridadID(autoridadID uint) error {
+func (r *detalleAutoridadDetallesVisitaRepository) DeleteDetallesByAutoridadID(autoridadID uint) error {
 	return r.db.Where("autoridad_uteq_id = ?", autoridadID).Delete(&models.DetalleAutoridadDetallesVisita{}).Error
 }
 
 // ExistsRelation verifica si ya existe una relación entre programa de visita y autoridad
-func (r *DetalleAutoridadDetallesVisitaRepository) ExistsRelation(programaVisitaID, autoridadID uint) (bool, error) {
+func (r *detalleAutoridadDetallesVisitaRepository) ExistsRelation(programaVisitaID, autoridadID uint) (bool, error) {
 	var count int64
 	err := r.db.Model(&models.DetalleAutoridadDetallesVisita{}).
 		Where("programa_visita_id = ? AND autoridad_uteq_id = ?", programaVisitaID, autoridadID).
@@ -87,7 +87,7 @@ func (r *DetalleAutoridadDetallesVisitaRepository) ExistsRelation(programaVisita
 }
 
 // GetEstadisticasAsignacion obtiene estadísticas de asignación de autoridades
-func (r *DetalleAutoridadDetallesVisitaRepository) GetEstadisticasAsignacion() (map[string]interface{}, error) {
+func (r *detalleAutoridadDetallesVisitaRepository) GetEstadisticasAsignacion() (map[string]interface{}, error) {
 	var totalAsignaciones int64
 	var totalAutoridadesUnicas int64
 	var totalProgramasUnicos int64
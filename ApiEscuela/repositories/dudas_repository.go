@@ -6,16 +6,16 @@ import (
 	"gorm.io/gorm"
 )
 
-type DudasRepository struct {
+type dudasRepository struct {
 	db *gorm.DB
 }
 
-func NewDudasRepository(db *gorm.DB) *DudasRepository {
-	return &DudasRepository{db: db}
+func NewDudasRepository(db *gorm.DB) DudasRepository {
+	return &dudasRepository{db: db}
 }
 
 // CreateDudas crea una nueva duda
-func (r *DudasRepository) CreateDudas(duda *models.Dudas) error {
+func (r *dudasRepository) CreateDudas(duda *models.Dudas) error {
 	// Establecer la fecha de pregunta automáticamente si no está establecida
 	if duda.FechaPregunta.IsZero() {
 		duda.FechaPregunta = time.Now()
@@ -24,7 +24,7 @@ func (r *DudasRepository) CreateDudas(duda *models.Dudas) error {
 }
 
 // GetDudasByID obtiene una duda por ID
-func (r *DudasRepository) GetDudasByID(id uint) (*models.Dudas, error) {
+func (r *dudasRepository) GetDudasByID(id uint) (*models.Dudas, error) {
 	var duda models.Dudas
 	err := r.db.Preload("Estudiante").Preload("Estudiante.Persona").
 		Preload("Estudiante.Institucion").Preload("Estudiante.Ciudad").
@@ -37,7 +37,7 @@ func (r *DudasRepository) GetDudasByID(id uint) (*models.Dudas, error) {
 }
 
 // GetAllDudas obtiene todas las dudas
-func (r *DudasRepository) GetAllDudas() ([]models.Dudas, error) {
+func (r *dudasRepository) GetAllDudas() ([]models.Dudas, error) {
 	var dudas []models.Dudas
 	err := r.db.Preload("Estudiante").Preload("Estudiante.Persona").
 		Preload("Estudiante.Institucion").Preload("Estudiante.Ciudad").
@@ -47,17 +47,17 @@ func (r *DudasRepository) GetAllDudas() ([]models.Dudas, error) {
 }
 
 // UpdateDudas actualiza una duda
-func (r *DudasRepository) UpdateDudas(duda *models.Dudas) error {
+func (r *dudasRepository) UpdateDudas(duda *models.Dudas) error {
 	return r.db.Save(duda).Error
 }
 
 // DeleteDudas elimina una duda
-func (r *DudasRepository) DeleteDudas(id uint) error {
+func (r *dudasRepository) DeleteDudas(id uint) error {
 	return r.db.Delete(&models.Dudas{}, id).Error
 }
 
 // GetDudasByEstudiante obtiene dudas por estudiante
-func (r *DudasRepository) GetDudasByEstudiante(estudianteID uint) ([]models.Dudas, error) {
+func (r *dudasRepository) GetDudasByEstudiante(estudianteID uint) ([]models.Dudas, error) {
 	var dudas []models.Dudas
 	err := r.db.Where("estudiante_id = ?", estudianteID).
 		Preload("Estudiante").Preload("Estudiante.Persona").
@@ -68,7 +68,7 @@ func (r *DudasRepository) GetDudasByEstudiante(estudianteID uint) ([]models.Duda
 }
 
 // GetDudasByAutoridad obtiene dudas asignadas a una autoridad
-func (r *DudasRepository) GetDudasByAutoridad(autoridadID uint) ([]models.Dudas, error) {
+func (r *dudasRepository) GetDudasByAutoridad(autoridadID uint) ([]models.Dudas, error) {
 	var dudas []models.Dudas
 	err := r.db.Where("autoridad_uteq_id = ?", autoridadID).
 		Preload("Estudiante").Preload("Estudiante.Persona").
@@ -79,7 +79,7 @@ func (r *DudasRepository) GetDudasByAutoridad(autoridadID uint) ([]models.Dudas,
 }
 
 // GetDudasSinResponder obtiene dudas sin respuesta
-func (r *DudasRepository) GetDudasSinResponder() ([]models.Dudas, error) {
+func (r *dudasRepository) GetDudasSinResponder() ([]models.Dudas, error) {
 	var dudas []models.Dudas
 	err := r.db.Where("respuesta IS NULL OR respuesta = ''").
 		Preload("Estudiante").Preload("Estudiante.Persona").
@@ -90,7 +90,7 @@ func (r *DudasRepository) GetDudasSinResponder() ([]models.Dudas, error) {
 }
 
 // GetDudasRespondidas obtiene dudas con respuesta
-func (r *DudasRepository) GetDudasRespondidas() ([]models.Dudas, error) {
+func (r *dudasRepository) GetDudasRespondidas() ([]models.Dudas, error) {
 	var dudas []models.Dudas
 	err := r.db.Where("respuesta IS NOT NULL AND respuesta != ''").
 		Preload("Estudiante").Preload("Estudiante.Persona").
@@ -101,7 +101,7 @@ func (r *DudasRepository) GetDudasRespondidas() ([]models.Dudas, error) {
 }
 
 // GetDudasSinAsignar obtiene dudas sin autoridad asignada
-func (r *DudasRepository) GetDudasSinAsignar() ([]models.Dudas, error) {
+func (r *dudasRepository) GetDudasSinAsignar() ([]models.Dudas, error) {
 	var dudas []models.Dudas
 	err := r.db.Where("autoridad_uteq_id IS NULL").
 		Preload("Estudiante").Preload("Estudiante.Persona").
@@ -110,20 +110,18 @@ func (r *DudasRepository) GetDudasSinAsignar() ([]models.Dudas, error) {
 	return dudas, err
 }
 
-// BuscarDudasPorPregunta busca dudas por contenido de la pregunta
-func (r *DudasRepository) BuscarDudasPorPregunta(termino string) ([]models.Dudas, error) {
-	var dudas []models.Dudas
-	err := r.db.Where("pregunta ILIKE ?", "%"+termino+"%").
-		Preload("Estudiante").Preload("Estudiante.Persona").
+// BuscarDudasPorPregunta busca dudas por las palabras de la pregunta, sin distinguir tildes, de la
+// más relevante a la menos relevante
+func (r *dudasRepository) BuscarDudasPorPregunta(termino string) ([]ResultadoBusqueda[models.Dudas], error) {
+	relaciones := r.db.Preload("Estudiante").Preload("Estudiante.Persona").
 		Preload("Estudiante.Institucion").Preload("Estudiante.Ciudad").
-		Preload("AutoridadUTEQ").Preload("AutoridadUTEQ.Persona").
-		Find(&dudas).Error
-	return dudas, err
+		Preload("AutoridadUTEQ").Preload("AutoridadUTEQ.Persona")
+	return buscarTexto[models.Dudas](relaciones, ConsultaTexto(termino, "A"), "concat_ws(' — ', pregunta, respuesta)")
 }
 
 
 // ResponderDuda actualiza la respuesta de una duda
-func (r *DudasRepository) ResponderDuda(dudaID uint, respuesta string,autoridadID uint) error {
+func (r *dudasRepository) ResponderDuda(dudaID uint, respuesta string,autoridadID uint) error {
 	now := time.Now()
 	return r.db.Model(&models.Dudas{}).Where("id = ?", dudaID).
 		Updates(map[string]interface{}{
@@ -134,7 +132,7 @@ func (r *DudasRepository) ResponderDuda(dudaID uint, respuesta string,autoridadI
 }
 
 // GetDudasByPrivacidad obtiene dudas por tipo de privacidad
-func (r *DudasRepository) GetDudasByPrivacidad(privacidad string) ([]models.Dudas, error) {
+func (r *dudasRepository) GetDudasByPrivacidad(privacidad string) ([]models.Dudas, error) {
 	var dudas []models.Dudas
 	err := r.db.Where("privacidad = ?", privacidad).
 		Preload("Estudiante").Preload("Estudiante.Persona").
@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"ApiEscuela/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Estados de las ejecuciones de tareas programadas
+const (
+	EjecucionEnCurso = "en_curso"
+	EjecucionExito   = "exito"
+	EjecucionError   = "error"
+)
+
+type ejecucionTareaRepository struct {
+	db *gorm.DB
+}
+
+func NewEjecucionTareaRepository(db *gorm.DB) EjecucionTareaRepository {
+	return &ejecucionTareaRepository{db: db}
+}
+
+// CreateEjecucion registra el inicio de una ejecución
+func (r *ejecucionTareaRepository) CreateEjecucion(ejecucion *models.EjecucionTarea) error {
+	return r.db.Create(ejecucion).Error
+}
+
+// UpdateEjecucion guarda el resultado de una ejecución
+func (r *ejecucionTareaRepository) UpdateEjecucion(ejecucion *models.EjecucionTarea) error {
+	return r.db.Save(ejecucion).Error
+}
+
+// GetEjecucionesByTarea obtiene las últimas ejecuciones de una tarea, de la más reciente a la más antigua
+func (r *ejecucionTareaRepository) GetEjecucionesByTarea(tarea string, limite int) ([]models.EjecucionTarea, error) {
+	var ejecuciones []models.EjecucionTarea
+	err := r.db.Where("tarea = ?", tarea).Order("inicio DESC, id DESC").Limit(limite).Find(&ejecuciones).Error
+	return ejecuciones, err
+}
+
+// GetUltimaEjecucion obtiene la ejecución más reciente de una tarea
+func (r *ejecucionTareaRepository) GetUltimaEjecucion(tarea string) (*models.EjecucionTarea, error) {
+	var ejecucion models.EjecucionTarea
+	err := r.db.Where("tarea = ?", tarea).Order("inicio DESC, id DESC").First(&ejecucion).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ejecucion, nil
+}
+
+// GetUltimoError obtiene la ejecución fallida más reciente de una tarea
+func (r *ejecucionTareaRepository) GetUltimoError(tarea string) (*models.EjecucionTarea, error) {
+	var ejecucion models.EjecucionTarea
+	err := r.db.Where("tarea = ? AND estado = ?", tarea, EjecucionError).Order("inicio DESC, id DESC").First(&ejecucion).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ejecucion, nil
+}
+
+// PurgarEjecuciones elimina definitivamente las ejecuciones terminadas que empezaron antes de la fecha indicada
+func (r *ejecucionTareaRepository) PurgarEjecuciones(antes time.Time) (int64, error) {
+	result := r.db.Unscoped().Where("inicio < ? AND estado <> ?", antes, EjecucionEnCurso).Delete(&models.EjecucionTarea{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"strings"
+
+	"ApiEscuela/errores"
+)
+
+// restriccionUnica asocia una columna única con el error que se devuelve cuando se repite
+type restriccionUnica struct {
+	columna string
+	err     *errores.Error
+}
+
+// traducirDuplicado traduce err con errores.DesdeBD y, si es un duplicado, lo reemplaza por el
+// error de la primera columna que aparece en la restricción violada, o por porDefecto
+func traducirDuplicado(err error, porDefecto *errores.Error, unicas ...restriccionUnica) error {
+	err = errores.DesdeBD(err)
+	e, ok := errores.Como(err)
+	if !ok || e.Tipo != errores.TipoConflicto || e.Codigo != errores.CodigoDuplicado {
+		return err
+	}
+	for _, u := range unicas {
+		if strings.Contains(e.Restriccion, u.columna) {
+			return u.err.ConCausa(err)
+		}
+	}
+	return porDefecto.ConCausa(err)
+}
@@ -1,40 +1,29 @@
 package repositories
 
 import (
+	"ApiEscuela/errores"
 	"ApiEscuela/models"
-	"errors"
-	"strings"
 	"gorm.io/gorm"
 )
 
-type EstudianteUniversitarioRepository struct {
+type estudianteUniversitarioRepository struct {
 	db *gorm.DB
 }
 
 var (
-	ErrEstudianteUnivDuplicado = errors.New("estudiante universitario ya existe")
+	ErrEstudianteUnivDuplicado = errores.Conflicto("estudiante_univ_duplicado", "La persona ya tiene un registro de estudiante universitario")
 )
 
 func classifyUniqueEstudianteUnivError(err error) error {
-	msg := err.Error()
-	if strings.Contains(msg, "duplicate key value") || strings.Contains(msg, "UNIQUE constraint") {
-		if strings.Contains(msg, "persona_id") || strings.Contains(msg, "persona") {
-			return ErrEstudianteUnivDuplicado
-		}
-		return ErrEstudianteUnivDuplicado
-	}
-	if errors.Is(err, gorm.ErrDuplicatedKey) {
-		return ErrEstudianteUnivDuplicado
-	}
-	return err
+	return traducirDuplicado(err, ErrEstudianteUnivDuplicado)
 }
 
-func NewEstudianteUniversitarioRepository(db *gorm.DB) *EstudianteUniversitarioRepository {
-	return &EstudianteUniversitarioRepository{db: db}
+func NewEstudianteUniversitarioRepository(db *gorm.DB) EstudianteUniversitarioRepository {
+	return &estudianteUniversitarioRepository{db: db}
 }
 
 // CreateEstudianteUniversitario crea un nuevo estudiante universitario
-func (r *EstudianteUniversitarioRepository) CreateEstudianteUniversitario(estudiante *models.EstudianteUniversitario) error {
+func (r *estudianteUniversitarioRepository) CreateEstudianteUniversitario(estudiante *models.EstudianteUniversitario) error {
 	// Prevalidar por persona
 	var count int64
 	if err := r.db.Model(&models.EstudianteUniversitario{}).Where("persona_id = ?", estudiante.PersonaID).Count(&count).Error; err != nil {
@@ -50,7 +39,7 @@ func (r *EstudianteUniversitarioRepository) CreateEstudianteUniversitario(estudi
 }
 
 // GetEstudianteUniversitarioByID obtiene un estudiante universitario por ID
-func (r *EstudianteUniversitarioRepository) GetEstudianteUniversitarioByID(id uint) (*models.EstudianteUniversitario, error) {
+func (r *estudianteUniversitarioRepository) GetEstudianteUniversitarioByID(id uint) (*models.EstudianteUniversitario, error) {
 	var estudiante models.EstudianteUniversitario
 	err := r.db.Preload("Persona").Preload("VisitaDetalleEstudiantesUniversitarios").
 		First(&estudiante, id).Error
@@ -61,7 +50,7 @@ func (r *EstudianteUniversitarioRepository) GetEstudianteUniversitarioByID(id ui
 }
 
 // GetAllEstudiantesUniversitarios obtiene todos los estudiantes universitarios
-func (r *EstudianteUniversitarioRepository) GetAllEstudiantesUniversitarios() ([]models.EstudianteUniversitario, error) {
+func (r *estudianteUniversitarioRepository) GetAllEstudiantesUniversitarios() ([]models.EstudianteUniversitario, error) {
 	var estudiantes []models.EstudianteUniversitario
 	err := r.db.Preload("Persona").Preload("VisitaDetalleEstudiantesUniversitarios").
 		Find(&estudiantes).Error
@@ -69,7 +58,7 @@ func (r *EstudianteUniversitarioRepository) GetAllEstudiantesUniversitarios() ([
 }
 
 // UpdateEstudianteUniversitario actualiza un estudiante universitario
-func (r *EstudianteUniversitarioRepository) UpdateEstudianteUniversitario(estudiante *models.EstudianteUniversitario) error {
+func (r *estudianteUniversitarioRepository) UpdateEstudianteUniversitario(estudiante *models.EstudianteUniversitario) error {
 	// Verificar duplicado por persona en otro registro
 	var count int64
 	if err := r.db.Model(&models.EstudianteUniversitario{}).Where("persona_id = ? AND id <> ?", estudiante.PersonaID, estudiante.ID).Count(&count).Error; err != nil {
@@ -85,12 +74,12 @@ func (r *EstudianteUniversitarioRepository) UpdateEstudianteUniversitario(estudi
 }
 
 // DeleteEstudianteUniversitario elimina un estudiante universitario
-func (r *EstudianteUniversitarioRepository) DeleteEstudianteUniversitario(id uint) error {
+func (r *estudianteUniversitarioRepository) DeleteEstudianteUniversitario(id uint) error {
 	return r.db.Delete(&models.EstudianteUniversitario{}, id).Error
 }
 
 // GetEstudiantesUniversitariosBySemestre obtiene estudiantes por semestre
-func (r *EstudianteUniversitarioRepository) GetEstudiantesUniversitariosBySemestre(semestre int) ([]models.EstudianteUniversitario, error) {
+func (r *estudianteUniversitarioRepository) GetEstudiantesUniversitariosBySemestre(semestre int) ([]models.EstudianteUniversitario, error) {
 	var estudiantes []models.EstudianteUniversitario
 	err := r.db.Where("semestre = ?", semestre).
 		Preload("Persona").Preload("VisitaDetalleEstudiantesUniversitarios").
@@ -99,7 +88,7 @@ func (r *EstudianteUniversitarioRepository) GetEstudiantesUniversitariosBySemest
 }
 
 // GetEstudianteUniversitarioByPersona obtiene estudiante universitario por persona
-func (r *EstudianteUniversitarioRepository) GetEstudianteUniversitarioByPersona(personaID uint) (*models.EstudianteUniversitario, error) {
+func (r *estudianteUniversitarioRepository) GetEstudianteUniversitarioByPersona(personaID uint) (*models.EstudianteUniversitario, error) {
 	var estudiante models.EstudianteUniversitario
 	err := r.db.Where("persona_id = ?", personaID).
 		Preload("Persona").Preload("VisitaDetalleEstudiantesUniversitarios").
@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"ApiEscuela/models"
+
+	"gorm.io/gorm"
+)
+
+type exportacionRepository struct {
+	db *gorm.DB
+}
+
+func NewExportacionRepository(db *gorm.DB) ExportacionRepository {
+	return &exportacionRepository{db: db}
+}
+
+// RecorrerEstudiantes recorre los estudiantes de la institución o ciudad del filtro
+func (r *exportacionRepository) RecorrerEstudiantes(filtro FiltroExportacion, fn func([]models.Estudiante) error) error {
+	query := r.db.Preload("Persona").Preload("Institucion").Preload("Ciudad").Preload("Ciudad.Provincia")
+	if filtro.InstitucionID != 0 {
+		query = query.Where("institucion_id = ?", filtro.InstitucionID)
+	}
+	if filtro.CiudadID != 0 {
+		query = query.Where("ciudad_id = ?", filtro.CiudadID)
+	}
+	var lote []models.Estudiante
+	return recorrer(query, &lote, func() error { return fn(lote) })
+}
+
+// RecorrerProgramasVisita recorre los programas de visita de la institución o del rango de fechas del filtro
+func (r *exportacionRepository) RecorrerProgramasVisita(filtro FiltroExportacion, fn func([]models.ProgramaVisita) error) error {
+	query := r.db.Preload("Institucion")
+	if filtro.InstitucionID != 0 {
+		query = query.Where("institucion_id = ?", filtro.InstitucionID)
+	}
+	if filtro.Desde != nil && filtro.Hasta != nil {
+		query = query.Where("fecha BETWEEN ? AND ?", *filtro.Desde, *filtro.Hasta)
+	}
+	var lote []models.ProgramaVisita
+	return recorrer(query, &lote, func() error { return fn(lote) })
+}
+
+// RecorrerParticipantes recorre los estudiantes universitarios asignados al programa de visita del filtro
+func (r *exportacionRepository) RecorrerParticipantes(filtro FiltroExportacion, fn func([]models.VisitaDetalleEstudiantesUniversitarios) error) error {
+	query := r.db.Preload("EstudianteUniversitario").Preload("EstudianteUniversitario.Persona").
+		Preload("ProgramaVisita").Preload("ProgramaVisita.Institucion")
+	if filtro.ProgramaVisitaID != 0 {
+		query = query.Where("programa_visita_id = ?", filtro.ProgramaVisitaID)
+	}
+	var lote []models.VisitaDetalleEstudiantesUniversitarios
+	return recorrer(query, &lote, func() error { return fn(lote) })
+}
+
+// RecorrerDudas recorre las dudas en el estado del filtro
+func (r *exportacionRepository) RecorrerDudas(filtro FiltroExportacion, fn func([]models.Dudas) error) error {
+	query := r.db.Preload("Estudiante").Preload("Estudiante.Persona").Preload("Estudiante.Institucion").
+		Preload("AutoridadUTEQ").Preload("AutoridadUTEQ.Persona")
+	switch filtro.EstadoDudas {
+	case DudasSinResponder:
+		query = query.Where("respuesta IS NULL OR respuesta = ''")
+	case DudasRespondidas:
+		query = query.Where("respuesta IS NOT NULL AND respuesta != ''")
+	case DudasSinAsignar:
+		query = query.Where("autoridad_uteq_id IS NULL")
+	}
+	var lote []models.Dudas
+	return recorrer(query, &lote, func() error { return fn(lote) })
+}
+
+// RecorrerComunicados recorre todos los comunicados
+func (r *exportacionRepository) RecorrerComunicados(filtro FiltroExportacion, fn func([]models.Comunicado) error) error {
+	var lote []models.Comunicado
+	return recorrer(r.db.Preload("Usuario"), &lote, func() error { return fn(lote) })
+}
+
+// recorrer lee la consulta por lotes en destino y llama a fn después de cada lote
+func recorrer(query *gorm.DB, destino interface{}, fn func() error) error {
+	return query.FindInBatches(destino, LoteExportacion, func(tx *gorm.DB, lote int) error {
+		return fn()
+	}).Error
+}
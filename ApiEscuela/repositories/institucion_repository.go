@@ -6,26 +6,21 @@ import (
 	"gorm.io/gorm"
 )
 
-type InstitucionRepository struct {
+type institucionRepository struct {
 	db *gorm.DB
 }
 
-func NewInstitucionRepository(db *gorm.DB) *InstitucionRepository {
-	return &InstitucionRepository{db: db}
-}
-
-// GetDB retorna la instancia de la base de datos para uso interno
-func (r *InstitucionRepository) GetDB() *gorm.DB {
-	return r.db
+func NewInstitucionRepository(db *gorm.DB) InstitucionRepository {
+	return &institucionRepository{db: db}
 }
 
 // CreateInstitucion crea una nueva institución
-func (r *InstitucionRepository) CreateInstitucion(institucion *models.Institucion) error {
+func (r *institucionRepository) CreateInstitucion(institucion *models.Institucion) error {
 	return r.db.Create(institucion).Error
 }
 
 // GetInstitucionByID obtiene una institución por ID
-func (r *InstitucionRepository) GetInstitucionByID(id uint) (*models.Institucion, error) {
+func (r *institucionRepository) GetInstitucionByID(id uint) (*models.Institucion, error) {
 	var institucion models.Institucion
 	err := r.db.Preload("Estudiantes").Preload("ProgramasVisita").
 		First(&institucion, id).Error
@@ -36,7 +31,7 @@ func (r *InstitucionRepository) GetInstitucionByID(id uint) (*models.Institucion
 }
 
 // GetAllInstituciones obtiene todas las instituciones
-func (r *InstitucionRepository) GetAllInstituciones() ([]models.Institucion, error) {
+func (r *institucionRepository) GetAllInstituciones() ([]models.Institucion, error) {
 	var instituciones []models.Institucion
 	err := r.db.Preload("Estudiantes").Preload("ProgramasVisita").
 		Find(&instituciones).Error
@@ -44,17 +39,17 @@ func (r *InstitucionRepository) GetAllInstituciones() ([]models.Institucion, err
 }
 
 // UpdateInstitucion actualiza una institución
-func (r *InstitucionRepository) UpdateInstitucion(institucion *models.Institucion) error {
+func (r *institucionRepository) UpdateInstitucion(institucion *models.Institucion) error {
 	return r.db.Save(institucion).Error
 }
 
 // DeleteInstitucion elimina una institución
-func (r *InstitucionRepository) DeleteInstitucion(id uint) error {
+func (r *institucionRepository) DeleteInstitucion(id uint) error {
 	return r.db.Delete(&models.Institucion{}, id).Error
 }
 
 // GetInstitucionesByNombre busca instituciones por nombre
-func (r *InstitucionRepository) GetInstitucionesByNombre(nombre string) ([]models.Institucion, error) {
+func (r *institucionRepository) GetInstitucionesByNombre(nombre string) ([]models.Institucion, error) {
 	var instituciones []models.Institucion
 	err := r.db.Where("nombre ILIKE ?", "%"+nombre+"%").
 		Preload("Estudiantes").Preload("ProgramasVisita").
@@ -63,10 +58,17 @@ func (r *InstitucionRepository) GetInstitucionesByNombre(nombre string) ([]model
 }
 
 // GetInstitucionesByAutoridad busca instituciones por autoridad
-func (r *InstitucionRepository) GetInstitucionesByAutoridad(autoridad string) ([]models.Institucion, error) {
+func (r *institucionRepository) GetInstitucionesByAutoridad(autoridad string) ([]models.Institucion, error) {
 	var instituciones []models.Institucion
 	err := r.db.Where("autoridad ILIKE ?", "%"+autoridad+"%").
 		Preload("Estudiantes").Preload("ProgramasVisita").
 		Find(&instituciones).Error
 	return instituciones, err
 }
+
+// ExistsByID verifica si existe una institución con el ID indicado
+func (r *institucionRepository) ExistsByID(id uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Institucion{}).Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
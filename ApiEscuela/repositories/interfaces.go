@@ -0,0 +1,399 @@
+package repositories
+
+import (
+	"ApiEscuela/models"
+	"time"
+)
+
+// ActividadRepository define el acceso a datos de actividades
+type ActividadRepository interface {
+	CreateActividad(actividad *models.Actividad) error
+	GetActividadByID(id uint) (*models.Actividad, error)
+	GetAllActividades() ([]models.Actividad, error)
+	UpdateActividad(actividad *models.Actividad) error
+	DeleteActividad(id uint) error
+	GetActividadesByTematica(tematicaID uint) ([]models.Actividad, error)
+	GetActividadesByNombre(nombre string) ([]ResultadoBusqueda[models.Actividad], error)
+	GetActividadesByDuracion(duracionMin, duracionMax int) ([]models.Actividad, error)
+}
+
+// AutoridadUTEQRepository define el acceso a datos de autoridades de la UTEQ
+type AutoridadUTEQRepository interface {
+	CreateAutoridadUTEQ(autoridad *models.AutoridadUTEQ) error
+	GetAutoridadUTEQByID(id uint) (*models.AutoridadUTEQ, error)
+	GetAllAutoridadesUTEQ() ([]models.AutoridadUTEQ, error)
+	UpdateAutoridadUTEQ(autoridad *models.AutoridadUTEQ) error
+	DeleteAutoridadUTEQ(id uint) error
+	RestoreAutoridadUTEQ(id uint) error
+	GetAllAutoridadesUTEQIncludingDeleted() ([]models.AutoridadUTEQ, error)
+	GetDeletedAutoridadesUTEQ() ([]models.AutoridadUTEQ, error)
+	GetAutoridadesUTEQByCargo(cargo string) ([]models.AutoridadUTEQ, error)
+	GetAutoridadUTEQByPersona(personaID uint) (*models.AutoridadUTEQ, error)
+	ExistsByID(id uint) (bool, error)
+}
+
+// CiudadRepository define el acceso a datos de ciudades
+type CiudadRepository interface {
+	CreateCiudad(ciudad *models.Ciudad) error
+	GetCiudadByID(id uint) (*models.Ciudad, error)
+	GetAllCiudades() ([]models.Ciudad, error)
+	UpdateCiudad(ciudad *models.Ciudad) error
+	DeleteCiudad(id uint) error
+	GetCiudadesByProvincia(provinciaID uint) ([]models.Ciudad, error)
+	GetCiudadByNombre(nombre string) ([]models.Ciudad, error)
+	ExistsByID(id uint) (bool, error)
+}
+
+// CodigoUsuarioRepository define el acceso a datos de códigos temporales de usuarios
+type CodigoUsuarioRepository interface {
+	Crear(usuarioID uint, codigo string) error
+	ExisteVigentePorUsuario(usuarioID uint) (bool, error)
+	FindLatestByCodigo(codigo string) (*models.CodigoUsuario, error)
+	Update(rec *models.CodigoUsuario) error
+	GetByID(id uint) (*models.CodigoUsuario, error)
+	MarcarComoVerificado(id uint) error
+	MarcarComoExpirado(id uint) error
+	GetCodigosValidosExpirados(usuarioID uint) ([]models.CodigoUsuario, error)
+	ExpirarVigentesPorUsuario(usuarioID uint) (int64, error)
+	ExpirarVencidos(ahora time.Time) (int64, error)
+	PurgarExpirados(antes time.Time) (int64, error)
+}
+
+// EjecucionTareaRepository define el acceso al historial de ejecuciones de las tareas programadas
+type EjecucionTareaRepository interface {
+	CreateEjecucion(ejecucion *models.EjecucionTarea) error
+	UpdateEjecucion(ejecucion *models.EjecucionTarea) error
+	GetEjecucionesByTarea(tarea string, limite int) ([]models.EjecucionTarea, error)
+	GetUltimaEjecucion(tarea string) (*models.EjecucionTarea, error)
+	GetUltimoError(tarea string) (*models.EjecucionTarea, error)
+	PurgarEjecuciones(antes time.Time) (int64, error)
+}
+
+// ComunicadoRepository define el acceso a datos de comunicados
+type ComunicadoRepository interface {
+	CreateComunicado(comunicado *models.Comunicado) error
+	GetComunicadoByID(id uint) (*models.Comunicado, error)
+	GetAllComunicados() ([]models.Comunicado, error)
+	UpdateComunicado(comunicado *models.Comunicado) error
+	DeleteComunicado(id uint) error
+	GetComunicadosByUsuario(usuarioID uint) ([]models.Comunicado, error)
+	SearchComunicados(termino string) ([]ResultadoBusqueda[models.Comunicado], error)
+}
+
+// DetalleAutoridadDetallesVisitaRepository define el acceso a datos de autoridades asignadas a programas de visita
+type DetalleAutoridadDetallesVisitaRepository interface {
+	CreateDetalleAutoridadDetallesVisita(detalle *models.DetalleAutoridadDetallesVisita) error
+	GetDetalleAutoridadDetallesVisitaByID(id uint) (*models.DetalleAutoridadDetallesVisita, error)
+	GetAllDetalleAutoridadDetallesVisitas() ([]models.DetalleAutoridadDetallesVisita, error)
+	UpdateDetalleAutoridadDetallesVisita(detalle *models.DetalleAutoridadDetallesVisita) error
+	DeleteDetalleAutoridadDetallesVisita(id uint) error
+	GetDetallesByProgramaVisitaID(programaVisitaID uint) ([]models.DetalleAutoridadDetallesVisita, error)
+	GetDetallesByAutoridadID(autoridadID uint) ([]models.DetalleAutoridadDetallesVisita, error)
+	DeleteDetallesByProgramaVisitaID(programaVisitaID uint) error
+	DeleteDetallesByAutoridadID(autoridadID uint) error
+	ExistsRelation(programaVisitaID, autoridadID uint) (bool, error)
+	GetEstadisticasAsignacion() (map[string]interface{}, error)
+}
+
+// DudasRepository define el acceso a datos de dudas
+type DudasRepository interface {
+	CreateDudas(duda *models.Dudas) error
+	GetDudasByID(id uint) (*models.Dudas, error)
+	GetAllDudas() ([]models.Dudas, error)
+	UpdateDudas(duda *models.Dudas) error
+	DeleteDudas(id uint) error
+	GetDudasByEstudiante(estudianteID uint) ([]models.Dudas, error)
+	GetDudasByAutoridad(autoridadID uint) ([]models.Dudas, error)
+	GetDudasSinResponder() ([]models.Dudas, error)
+	GetDudasRespondidas() ([]models.Dudas, error)
+	GetDudasSinAsignar() ([]models.Dudas, error)
+	BuscarDudasPorPregunta(termino string) ([]ResultadoBusqueda[models.Dudas], error)
+	ResponderDuda(dudaID uint, respuesta string, autoridadID uint) error
+	GetDudasByPrivacidad(privacidad string) ([]models.Dudas, error)
+}
+
+// EstudianteUniversitarioRepository define el acceso a datos de estudiantes universitarios
+type EstudianteUniversitarioRepository interface {
+	CreateEstudianteUniversitario(estudiante *models.EstudianteUniversitario) error
+	GetEstudianteUniversitarioByID(id uint) (*models.EstudianteUniversitario, error)
+	GetAllEstudiantesUniversitarios() ([]models.EstudianteUniversitario, error)
+	UpdateEstudianteUniversitario(estudiante *models.EstudianteUniversitario) error
+	DeleteEstudianteUniversitario(id uint) error
+	GetEstudiantesUniversitariosBySemestre(semestre int) ([]models.EstudianteUniversitario, error)
+	GetEstudianteUniversitarioByPersona(personaID uint) (*models.EstudianteUniversitario, error)
+}
+
+// InstitucionRepository define el acceso a datos de instituciones
+type InstitucionRepository interface {
+	CreateInstitucion(institucion *models.Institucion) error
+	GetInstitucionByID(id uint) (*models.Institucion, error)
+	GetAllInstituciones() ([]models.Institucion, error)
+	UpdateInstitucion(institucion *models.Institucion) error
+	DeleteInstitucion(id uint) error
+	GetInstitucionesByNombre(nombre string) ([]models.Institucion, error)
+	GetInstitucionesByAutoridad(autoridad string) ([]models.Institucion, error)
+	ExistsByID(id uint) (bool, error)
+}
+
+// MediaRepository define el acceso a los metadatos de los archivos subidos y a sus referencias
+type MediaRepository interface {
+	SaveMedia(media *models.Media) error
+	GetMediaByID(id uint) (*models.Media, error)
+	GetMediaByClave(clave string) (*models.Media, error)
+	GetMediaBySha256(sha256 string) ([]models.Media, error)
+	SearchMedia(filtro FiltroMedia) ([]models.Media, error)
+	DeleteMediaByClave(clave string) error
+	ReplaceMediaReferencias(entidad string, entidadID uint, mediaIDs []uint) error
+	ReplaceAllMediaReferencias(referencias []models.MediaReferencia) error
+}
+
+// FiltroMedia filtra la biblioteca de archivos; los campos vacíos no filtran
+type FiltroMedia struct {
+	Texto          string // busca en el nombre original y en la clave
+	Categoria      string
+	Visibilidad    string
+	UsuarioID      uint
+	SinReferencias bool // solo archivos que ninguna noticia o comunicado usa
+}
+
+// NoticiaRepository define el acceso a datos de noticias
+type NoticiaRepository interface {
+	CreateNoticia(noticia *models.Noticia) error
+	GetNoticiaByID(id uint) (*models.Noticia, error)
+	GetAllNoticias() ([]models.Noticia, error)
+	UpdateNoticia(noticia *models.Noticia) error
+	DeleteNoticia(id uint) error
+	GetNoticiasByUsuario(usuarioID uint) ([]models.Noticia, error)
+	GetNoticiasByTitulo(titulo string) ([]models.Noticia, error)
+	GetNoticiasByDescripcion(descripcion string) ([]models.Noticia, error)
+	SearchNoticias(termino string) ([]ResultadoBusqueda[models.Noticia], error)
+}
+
+// NotificacionRepository define el acceso a datos de las notificaciones de los usuarios y de sus
+// preferencias de envío
+type NotificacionRepository interface {
+	CreateNotificacion(notificacion *models.Notificacion) error
+	CreateNotificaciones(notificaciones []models.Notificacion) error
+	GetNotificacionesByUsuario(usuarioID uint, soloNoLeidas bool, limite int) ([]models.Notificacion, error)
+	ContarNoLeidas(usuarioID uint) (int64, error)
+	MarcarLeida(usuarioID, id uint, fecha time.Time) (*models.Notificacion, error)
+	MarcarTodasLeidas(usuarioID uint, fecha time.Time) (int64, error)
+	GetPreferencias(usuarioID uint) (*models.PreferenciaNotificacion, error)
+	GetPreferenciasByUsuarios(usuarioIDs []uint) ([]models.PreferenciaNotificacion, error)
+	GuardarPreferencias(preferencias *models.PreferenciaNotificacion) error
+}
+
+// WebhookRepository define el acceso a datos de las suscripciones de webhooks y de su registro de
+// entregas
+type WebhookRepository interface {
+	CreateWebhook(webhook *models.Webhook) error
+	GetWebhookByID(id uint) (*models.Webhook, error)
+	GetAllWebhooks() ([]models.Webhook, error)
+	GetWebhooksActivos() ([]models.Webhook, error)
+	UpdateWebhook(webhook *models.Webhook) error
+	DeleteWebhook(id uint) error
+	CreateEntrega(entrega *models.EntregaWebhook) error
+	GetEntregaByID(id uint) (*models.EntregaWebhook, error)
+	GetEntregasByWebhook(webhookID uint, limite int) ([]models.EntregaWebhook, error)
+	GetEntregasPendientes(ahora time.Time, limite int) ([]models.EntregaWebhook, error)
+	ReclamarEntrega(id uint, ahora, hasta time.Time) (bool, error)
+	UpdateEntrega(entrega *models.EntregaWebhook) error
+}
+
+// BusquedaRepository define las consultas de la búsqueda global. Buscar* exige todas las palabras
+// del término; Autocompletar* tolera errores de escritura con trigramas y solo mira el nombre (y el
+// prefijo de la cédula). Devuelven como máximo limite registros, los más parecidos primero.
+type BusquedaRepository interface {
+	BuscarPersonas(termino string, limite int) ([]ResultadoBusqueda[models.Persona], error)
+	BuscarInstituciones(termino string, limite int) ([]ResultadoBusqueda[models.Institucion], error)
+	BuscarProgramasVisita(termino string, limite int) ([]models.ProgramaVisita, error)
+	AutocompletarPersonas(termino string, limite int) ([]ResultadoBusqueda[models.Persona], error)
+	AutocompletarInstituciones(termino string, limite int) ([]ResultadoBusqueda[models.Institucion], error)
+	AutocompletarProgramasVisita(termino string, limite int) ([]models.ProgramaVisita, error)
+}
+
+// Estados de las dudas en FiltroExportacion
+const (
+	DudasSinResponder = "sin_responder"
+	DudasRespondidas  = "respondidas"
+	DudasSinAsignar   = "sin_asignar"
+)
+
+// LoteExportacion es la cantidad de registros que se leen por consulta al exportar
+const LoteExportacion = 500
+
+// FiltroExportacion restringe los registros exportados; los campos vacíos no filtran. Cada lista
+// usa solo los campos que le corresponden.
+type FiltroExportacion struct {
+	InstitucionID    uint
+	CiudadID         uint
+	ProgramaVisitaID uint
+	Desde, Hasta     *time.Time // fecha del programa de visita, ambos inclusive
+	EstadoDudas      string     // DudasSinResponder, DudasRespondidas o DudasSinAsignar
+}
+
+// ExportacionRepository recorre las listas exportables por lotes de LoteExportacion registros, en
+// orden de ID y con las relaciones que se muestran. Si fn devuelve un error, el recorrido se detiene.
+type ExportacionRepository interface {
+	RecorrerEstudiantes(filtro FiltroExportacion, fn func([]models.Estudiante) error) error
+	RecorrerProgramasVisita(filtro FiltroExportacion, fn func([]models.ProgramaVisita) error) error
+	RecorrerParticipantes(filtro FiltroExportacion, fn func([]models.VisitaDetalleEstudiantesUniversitarios) error) error
+	RecorrerDudas(filtro FiltroExportacion, fn func([]models.Dudas) error) error
+	RecorrerComunicados(filtro FiltroExportacion, fn func([]models.Comunicado) error) error
+}
+
+// AnaliticaRepository calcula los agregados del panel de estadísticas sobre los registros activos
+// del rango [Desde, Hasta). Los desgloses vienen del mayor al menor total.
+type AnaliticaRepository interface {
+	// VisitasPorPeriodo cuenta los programas de visita por período de su fecha; solo incluye los
+	// períodos con visitas
+	VisitasPorPeriodo(rango RangoAnalitica, agrupacion string) ([]ConteoPeriodo, error)
+	// EstudiantesAlcanzados cuenta los estudiantes de las instituciones que tuvieron una visita
+	// en el rango, por la dimensión indicada (AnaliticaPorProvincia, ...)
+	EstudiantesAlcanzados(rango RangoAnalitica, dimension string) ([]ConteoGrupo, error)
+	// TematicasPrincipales cuenta las visitas del rango que incluyeron actividades de cada temática
+	TematicasPrincipales(rango RangoAnalitica, limite int) ([]ConteoGrupo, error)
+	// ActividadesPrincipales cuenta las visitas del rango que incluyeron cada actividad
+	ActividadesPrincipales(rango RangoAnalitica, limite int) ([]ConteoGrupo, error)
+	// CargaAutoridades resume las visitas asignadas y las dudas de cada autoridad activa
+	CargaAutoridades(rango RangoAnalitica) ([]CargaAutoridad, error)
+	// TiemposRespuestaDudas resume las dudas preguntadas en el rango, en total y por período
+	TiemposRespuestaDudas(rango RangoAnalitica, agrupacion string) (*TiemposRespuesta, []TiemposRespuesta, error)
+	// AlcanceComunicados resume los comunicados creados en el rango por canal
+	AlcanceComunicados(rango RangoAnalitica) ([]AlcanceCanal, error)
+}
+
+// PapeleraRepository define el acceso a los registros eliminados lógicamente de las entidades
+// de EntidadesPapelera, por nombre de entidad
+type PapeleraRepository interface {
+	ContarEliminados(entidad string) (int64, error)
+	GetEliminados(entidad string) ([]ElementoPapelera, error)
+	GetEliminadosAntes(entidad string, antes time.Time) ([]uint, error)
+	Restaurar(entidad string, id uint) error
+	Purgar(entidad string, id uint) error
+}
+
+// PersonaRepository define el acceso a datos de personas
+type PersonaRepository interface {
+	CreatePersona(persona *models.Persona) error
+	GetPersonaByID(id uint) (*models.Persona, error)
+	GetPersonaByCedula(cedula string) (*models.Persona, error)
+	GetAllPersonas() ([]models.Persona, error)
+	UpdatePersona(persona *models.Persona) error
+	DeletePersona(id uint) error
+	GetPersonasByCorreo(correo string) ([]models.Persona, error)
+	ExistsByID(id uint) (bool, error)
+}
+
+// ProgramaVisitaRepository define el acceso a datos de programas de visita
+type ProgramaVisitaRepository interface {
+	CreateProgramaVisita(programa *models.ProgramaVisita) error
+	GetProgramaVisitaByID(id uint) (*models.ProgramaVisita, error)
+	GetAllProgramasVisita() ([]models.ProgramaVisita, error)
+	UpdateProgramaVisita(programa *models.ProgramaVisita) error
+	DeleteProgramaVisita(id uint) error
+	GetProgramasVisitaByFecha(fecha time.Time) ([]models.ProgramaVisita, error)
+	GetProgramasVisitaByInstitucion(institucionID uint) ([]models.ProgramaVisita, error)
+	GetProgramasVisitaByRangoFecha(fechaInicio, fechaFin time.Time) ([]models.ProgramaVisita, error)
+	GetProgramasSinRecordatorio(desde, hasta time.Time) ([]models.ProgramaVisita, error)
+	MarcarRecordatorioEnviado(id uint, enviado time.Time) error
+}
+
+// ProvinciaRepository define el acceso a datos de provincias
+type ProvinciaRepository interface {
+	CreateProvincia(provincia *models.Provincia) error
+	GetProvinciaByID(id uint) (*models.Provincia, error)
+	GetAllProvincias() ([]models.Provincia, error)
+	UpdateProvincia(provincia *models.Provincia) error
+	DeleteProvincia(id uint) error
+	GetProvinciaByNombre(nombre string) (*models.Provincia, error)
+}
+
+// EstudianteRepository define el acceso a datos de estudiantes
+type EstudianteRepository interface {
+	CreateEstudiante(estudiante *models.Estudiante) error
+	GetEstudianteByID(id uint) (*models.Estudiante, error)
+	GetAllEstudiantes() ([]models.Estudiante, error)
+	UpdateEstudiante(estudiante *models.Estudiante) error
+	DeleteEstudiante(id uint) error
+	RestoreEstudiante(id uint) error
+	GetAllEstudiantesIncludingDeleted() ([]models.Estudiante, error)
+	GetDeletedEstudiantes() ([]models.Estudiante, error)
+	GetEstudiantesByCity(ciudadID uint) ([]models.Estudiante, error)
+	GetEstudiantesByInstitucion(institucionID uint) ([]models.Estudiante, error)
+	GetEstudiantesByEspecialidad(especialidad string) ([]models.Estudiante, error)
+	ExistsByID(id uint) (bool, error)
+}
+
+// TematicaRepository define el acceso a datos de temáticas
+type TematicaRepository interface {
+	CreateTematica(tematica *models.Tematica) error
+	GetTematicaByID(id uint) (*models.Tematica, error)
+	GetAllTematicas() ([]models.Tematica, error)
+	UpdateTematica(tematica *models.Tematica) error
+	DeleteTematica(id uint) error
+	GetTematicasByNombre(nombre string) ([]ResultadoBusqueda[models.Tematica], error)
+	GetTematicasByDescripcion(descripcion string) ([]models.Tematica, error)
+	ExistsByID(id uint) (bool, error)
+}
+
+// TipoUsuarioRepository define el acceso a datos de tipos de usuario
+type TipoUsuarioRepository interface {
+	CreateTipoUsuario(tipoUsuario *models.TipoUsuario) error
+	GetTipoUsuarioByID(id uint) (*models.TipoUsuario, error)
+	GetAllTiposUsuario() ([]models.TipoUsuario, error)
+	UpdateTipoUsuario(tipoUsuario *models.TipoUsuario) error
+	DeleteTipoUsuario(id uint) error
+	GetTipoUsuarioByNombre(nombre string) (*models.TipoUsuario, error)
+}
+
+// UsuarioRepository define el acceso a datos de usuarios
+type UsuarioRepository interface {
+	CreateUsuario(usuario *models.Usuario) error
+	GetUsuarioByID(id uint) (*models.Usuario, error)
+	GetAllUsuarios() ([]models.Usuario, error)
+	UpdateUsuario(usuario *models.Usuario) error
+	DeleteUsuario(id uint) error
+	GetUsuarioByUsername(username string) (*models.Usuario, error)
+	GetUsuariosByTipo(tipoUsuarioID uint) ([]models.Usuario, error)
+	GetUsuariosByPersona(personaID uint) ([]models.Usuario, error)
+	GetUsuariosByPersonas(personaIDs []uint) ([]models.Usuario, error)
+	ValidateLogin(username, password string) (*models.Usuario, error)
+	GetAllUsuariosIncludingDeleted() ([]models.Usuario, error)
+	GetDeletedUsuarios() ([]models.Usuario, error)
+	RestoreUsuario(id uint) error
+	GetUsuarioByIDIncludingDeleted(id uint) (*models.Usuario, error)
+	GetUsuarioByUsernameIncludingDeleted(username string) (*models.Usuario, error)
+	UpdatePassword(usuarioID uint, nuevaClave string) error
+	ExistsByID(id uint) (bool, error)
+}
+
+// VisitaDetalleEstudiantesUniversitariosRepository define el acceso a datos de estudiantes universitarios asignados a programas de visita
+type VisitaDetalleEstudiantesUniversitariosRepository interface {
+	CreateVisitaDetalleEstudiantesUniversitarios(relacion *models.VisitaDetalleEstudiantesUniversitarios) error
+	GetVisitaDetalleEstudiantesUniversitariosByID(id uint) (*models.VisitaDetalleEstudiantesUniversitarios, error)
+	GetAllVisitaDetalleEstudiantesUniversitarios() ([]models.VisitaDetalleEstudiantesUniversitarios, error)
+	UpdateVisitaDetalleEstudiantesUniversitarios(relacion *models.VisitaDetalleEstudiantesUniversitarios) error
+	DeleteVisitaDetalleEstudiantesUniversitarios(id uint) error
+	GetEstudiantesByProgramaVisita(programaVisitaID uint) ([]models.VisitaDetalleEstudiantesUniversitarios, error)
+	GetProgramasVisitaByEstudiante(estudianteID uint) ([]models.VisitaDetalleEstudiantesUniversitarios, error)
+	DeleteByProgramaVisita(programaVisitaID uint) error
+	DeleteByEstudiante(estudianteID uint) error
+	ExistsRelation(estudianteID, programaVisitaID uint) (bool, error)
+	GetEstadisticasParticipacion() (map[string]interface{}, error)
+}
+
+// VisitaDetalleRepository define el acceso a datos de actividades asignadas a programas de visita
+type VisitaDetalleRepository interface {
+	CreateVisitaDetalle(detalle *models.VisitaDetalle) error
+	GetVisitaDetalleByID(id uint) (*models.VisitaDetalle, error)
+	GetAllVisitaDetalles() ([]models.VisitaDetalle, error)
+	UpdateVisitaDetalle(detalle *models.VisitaDetalle) error
+	DeleteVisitaDetalle(id uint) error
+	GetVisitaDetallesByActividad(actividadID uint) ([]models.VisitaDetalle, error)
+	GetVisitaDetallesByPrograma(programaID uint) ([]models.VisitaDetalle, error)
+	DeleteVisitaDetallesByPrograma(programaID uint) error
+	DeleteVisitaDetallesByActividad(actividadID uint) error
+	ExistsRelation(programaVisitaID, actividadID uint) (bool, error)
+	GetEstadisticasActividades() (map[string]interface{}, error)
+}
@@ -0,0 +1,128 @@
+package repositories
+
+import (
+	"ApiEscuela/models"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type mediaRepository struct {
+	db *gorm.DB
+}
+
+func NewMediaRepository(db *gorm.DB) MediaRepository {
+	return &mediaRepository{db: db}
+}
+
+// SaveMedia crea los metadatos de un archivo o reemplaza los existentes con la misma clave.
+// Las referencias no se modifican.
+func (r *mediaRepository) SaveMedia(media *models.Media) error {
+	var existente models.Media
+	err := r.db.Where("clave = ?", media.Clave).First(&existente).Error
+	if err == nil {
+		media.ID = existente.ID
+		media.CreatedAt = existente.CreatedAt
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return r.db.Omit(clause.Associations).Save(media).Error
+}
+
+// GetMediaByID obtiene un archivo por ID con sus referencias
+func (r *mediaRepository) GetMediaByID(id uint) (*models.Media, error) {
+	var media models.Media
+	err := r.db.Preload("Referencias").First(&media, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+// GetMediaByClave obtiene los metadatos de un archivo por su clave
+func (r *mediaRepository) GetMediaByClave(clave string) (*models.Media, error) {
+	var media models.Media
+	err := r.db.Preload("Referencias").Where("clave = ?", clave).First(&media).Error
+	if err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+// GetMediaBySha256 obtiene los archivos con el checksum indicado, del más antiguo al más nuevo
+func (r *mediaRepository) GetMediaBySha256(sha256 string) ([]models.Media, error) {
+	var medias []models.Media
+	err := r.db.Where("sha256 = ?", sha256).Order("id").Find(&medias).Error
+	return medias, err
+}
+
+// SearchMedia lista los archivos que cumplen el filtro, del más nuevo al más antiguo
+func (r *mediaRepository) SearchMedia(filtro FiltroMedia) ([]models.Media, error) {
+	q := r.db.Preload("Referencias")
+	if filtro.Texto != "" {
+		q = q.Where("(nombre ILIKE ? OR clave ILIKE ?)", "%"+filtro.Texto+"%", "%"+filtro.Texto+"%")
+	}
+	if filtro.Categoria != "" {
+		q = q.Where("categoria = ?", filtro.Categoria)
+	}
+	if filtro.Visibilidad != "" {
+		q = q.Where("visibilidad = ?", filtro.Visibilidad)
+	}
+	if filtro.UsuarioID != 0 {
+		q = q.Where("usuario_id = ?", filtro.UsuarioID)
+	}
+	if filtro.SinReferencias {
+		q = q.Where("id NOT IN (?)", r.db.Model(&models.MediaReferencia{}).Select("media_id"))
+	}
+	var medias []models.Media
+	err := q.Order("created_at DESC, id DESC").Find(&medias).Error
+	return medias, err
+}
+
+// DeleteMediaByClave elimina definitivamente los metadatos y las referencias para que la clave
+// pueda reutilizarse
+func (r *mediaRepository) DeleteMediaByClave(clave string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var media models.Media
+		err := tx.Unscoped().Where("clave = ?", clave).First(&media).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("media_id = ?", media.ID).Delete(&models.MediaReferencia{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(&media).Error
+	})
+}
+
+// ReplaceMediaReferencias reemplaza los archivos que usa una noticia o un comunicado
+func (r *mediaRepository) ReplaceMediaReferencias(entidad string, entidadID uint, mediaIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Where("entidad = ? AND entidad_id = ?", entidad, entidadID).Delete(&models.MediaReferencia{}).Error
+		if err != nil || len(mediaIDs) == 0 {
+			return err
+		}
+		referencias := make([]models.MediaReferencia, len(mediaIDs))
+		for i, id := range mediaIDs {
+			referencias[i] = models.MediaReferencia{MediaID: id, Entidad: entidad, EntidadID: entidadID}
+		}
+		return tx.Omit(clause.Associations).Create(&referencias).Error
+	})
+}
+
+// ReplaceAllMediaReferencias reemplaza todas las referencias, por ejemplo al reconstruirlas
+func (r *mediaRepository) ReplaceAllMediaReferencias(referencias []models.MediaReferencia) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("1 = 1").Delete(&models.MediaReferencia{}).Error; err != nil {
+			return err
+		}
+		if len(referencias) == 0 {
+			return nil
+		}
+		return tx.Omit(clause.Associations).CreateInBatches(&referencias, 500).Error
+	})
+}
@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+)
+
+// ActividadRepository implementa repositories.ActividadRepository en memoria
+type ActividadRepository struct {
+	s *Store
+}
+
+var _ repositories.ActividadRepository = (*ActividadRepository)(nil)
+
+func NewActividadRepository(s *Store) *ActividadRepository {
+	return &ActividadRepository{s: s}
+}
+
+func (r *ActividadRepository) withRelations(a models.Actividad) models.Actividad {
+	a.Tematica = r.s.tematica(a.TematicaID)
+	a.VisitaDetalles = r.s.visitaDetalles.find(false, func(v *models.VisitaDetalle) bool { return v.ActividadID == a.ID })
+	return a
+}
+
+// CreateActividad crea una nueva actividad
+func (r *ActividadRepository) CreateActividad(actividad *models.Actividad) error {
+	return create(r.s, &r.s.actividades, actividad)
+}
+
+// GetActividadByID obtiene una actividad por ID
+func (r *ActividadRepository) GetActividadByID(id uint) (*models.Actividad, error) {
+	return getByID(r.s, &r.s.actividades, id, r.withRelations)
+}
+
+// GetAllActividades obtiene todas las actividades
+func (r *ActividadRepository) GetAllActividades() ([]models.Actividad, error) {
+	return list(r.s, &r.s.actividades, nil, r.withRelations)
+}
+
+// UpdateActividad actualiza una actividad
+func (r *ActividadRepository) UpdateActividad(actividad *models.Actividad) error {
+	return save(r.s, &r.s.actividades, actividad)
+}
+
+// DeleteActividad elimina una actividad
+func (r *ActividadRepository) DeleteActividad(id uint) error {
+	return remove(r.s, &r.s.actividades, byID[models.Actividad](id))
+}
+
+// GetActividadesByTematica obtiene actividades por temática
+func (r *ActividadRepository) GetActividadesByTematica(tematicaID uint) ([]models.Actividad, error) {
+	return list(r.s, &r.s.actividades, func(a *models.Actividad) bool { return a.TematicaID == tematicaID }, r.withRelations)
+}
+
+// GetActividadesByNombre busca actividades por las palabras del nombre, sin distinguir tildes
+func (r *ActividadRepository) GetActividadesByNombre(nombre string) ([]repositories.ResultadoBusqueda[models.Actividad], error) {
+	return search(r.s, &r.s.actividades, nombre, true, func(a *models.Actividad) []string { return []string{a.Actividad} }, r.withRelations)
+}
+
+// GetActividadesByDuracion obtiene actividades por duración
+func (r *ActividadRepository) GetActividadesByDuracion(duracionMin, duracionMax int) ([]models.Actividad, error) {
+	return list(r.s, &r.s.actividades, func(a *models.Actividad) bool {
+		return a.Duracion >= duracionMin && a.Duracion <= duracionMax
+	}, r.withRelations)
+}
@@ -0,0 +1,324 @@
+package memory
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+)
+
+// AnaliticaRepository implementa repositories.AnaliticaRepository en memoria
+type AnaliticaRepository struct {
+	s *Store
+}
+
+var _ repositories.AnaliticaRepository = (*AnaliticaRepository)(nil)
+
+func NewAnaliticaRepository(s *Store) *AnaliticaRepository {
+	return &AnaliticaRepository{s: s}
+}
+
+// enRango indica si t está en [Desde, Hasta)
+func enRango(rango repositories.RangoAnalitica, t time.Time) bool {
+	return !t.Before(rango.Desde) && t.Before(rango.Hasta)
+}
+
+// VisitasPorPeriodo cuenta los programas de visita por período de su fecha
+func (r *AnaliticaRepository) VisitasPorPeriodo(rango repositories.RangoAnalitica, agrupacion string) ([]repositories.ConteoPeriodo, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	totales := map[string]int64{}
+	for _, p := range r.s.programasVisita.find(false, func(p *models.ProgramaVisita) bool { return enRango(rango, p.Fecha) }) {
+		totales[repositories.EtiquetaPeriodo(p.Fecha, agrupacion)]++
+	}
+	conteos := []repositories.ConteoPeriodo{}
+	for periodo, total := range totales {
+		conteos = append(conteos, repositories.ConteoPeriodo{Periodo: periodo, Total: total})
+	}
+	sort.Slice(conteos, func(i, j int) bool { return conteos[i].Periodo < conteos[j].Periodo })
+	return conteos, nil
+}
+
+// EstudiantesAlcanzados cuenta los estudiantes activos de las instituciones visitadas en el rango
+func (r *AnaliticaRepository) EstudiantesAlcanzados(rango repositories.RangoAnalitica, dimension string) ([]repositories.ConteoGrupo, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	visitadas := map[uint]bool{}
+	for _, p := range r.s.programasVisita.find(false, func(p *models.ProgramaVisita) bool { return enRango(rango, p.Fecha) }) {
+		visitadas[p.InstitucionID] = true
+	}
+	grupos := &agrupador{}
+	for _, e := range r.s.estudiantes.find(false, func(e *models.Estudiante) bool { return visitadas[e.InstitucionID] }) {
+		switch dimension {
+		case repositories.AnaliticaPorProvincia, repositories.AnaliticaPorCiudad:
+			c, err := r.s.ciudades.get(e.CiudadID, false)
+			if err != nil {
+				continue
+			}
+			if dimension == repositories.AnaliticaPorCiudad {
+				grupos.sumar(c.ID, c.Ciudad)
+			} else if p, err := r.s.provincias.get(c.ProvinciaID, false); err == nil {
+				grupos.sumar(p.ID, p.Provincia)
+			}
+		case repositories.AnaliticaPorEspecialidad:
+			especialidad := strings.TrimSpace(e.Especialidad)
+			if especialidad == "" {
+				especialidad = repositories.SinEspecialidad
+			}
+			grupos.sumar(0, especialidad)
+		default:
+			if i, err := r.s.instituciones.get(e.InstitucionID, false); err == nil {
+				grupos.sumar(i.ID, i.Nombre)
+			}
+		}
+	}
+	return grupos.ordenados(0), nil
+}
+
+// TematicasPrincipales cuenta las visitas del rango por temática de sus actividades
+func (r *AnaliticaRepository) TematicasPrincipales(rango repositories.RangoAnalitica, limite int) ([]repositories.ConteoGrupo, error) {
+	return r.visitasPorActividad(rango, limite, func(a models.Actividad, t models.Tematica) (uint, string) {
+		return t.ID, t.Nombre
+	})
+}
+
+// ActividadesPrincipales cuenta las visitas del rango por actividad
+func (r *AnaliticaRepository) ActividadesPrincipales(rango repositories.RangoAnalitica, limite int) ([]repositories.ConteoGrupo, error) {
+	return r.visitasPorActividad(rango, limite, func(a models.Actividad, t models.Tematica) (uint, string) {
+		return a.ID, a.Actividad
+	})
+}
+
+// visitasPorActividad cuenta las visitas distintas del rango agrupando sus detalles con grupo
+func (r *AnaliticaRepository) visitasPorActividad(rango repositories.RangoAnalitica, limite int, grupo func(models.Actividad, models.Tematica) (uint, string)) ([]repositories.ConteoGrupo, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	grupos := &agrupador{}
+	contadas := map[[2]uint]bool{}
+	for _, vd := range r.s.visitaDetalles.find(false, nil) {
+		p, err := r.s.programasVisita.get(vd.ProgramaVisitaID, false)
+		if err != nil || !enRango(rango, p.Fecha) {
+			continue
+		}
+		a, err := r.s.actividades.get(vd.ActividadID, false)
+		if err != nil {
+			continue
+		}
+		t, err := r.s.tematicas.get(a.TematicaID, false)
+		if err != nil {
+			continue
+		}
+		id, nombre := grupo(a, t)
+		if clave := [2]uint{id, p.ID}; !contadas[clave] {
+			contadas[clave] = true
+			grupos.sumar(id, nombre)
+		}
+	}
+	return grupos.ordenados(limite), nil
+}
+
+// CargaAutoridades cuenta por autoridad activa las visitas del rango a las que fue asignada y las
+// dudas que le asignaron y que se preguntaron en el rango
+func (r *AnaliticaRepository) CargaAutoridades(rango repositories.RangoAnalitica) ([]repositories.CargaAutoridad, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	visitas := map[uint]map[uint]bool{}
+	for _, d := range r.s.detallesAutoridad.find(false, nil) {
+		if p, err := r.s.programasVisita.get(d.ProgramaVisitaID, false); err == nil && enRango(rango, p.Fecha) {
+			if visitas[d.AutoridadUTEQID] == nil {
+				visitas[d.AutoridadUTEQID] = map[uint]bool{}
+			}
+			visitas[d.AutoridadUTEQID][p.ID] = true
+		}
+	}
+	asignadas, respondidas := map[uint]int64{}, map[uint]int64{}
+	for _, d := range r.s.dudas.find(false, func(d *models.Dudas) bool {
+		return d.AutoridadUTEQID != nil && enRango(rango, d.FechaPregunta)
+	}) {
+		asignadas[*d.AutoridadUTEQID]++
+		if respondida(&d) {
+			respondidas[*d.AutoridadUTEQID]++
+		}
+	}
+
+	cargas := []repositories.CargaAutoridad{}
+	for _, a := range r.s.autoridades.find(false, nil) {
+		p, err := r.s.personas.get(a.PersonaID, false)
+		if err != nil {
+			continue
+		}
+		cargas = append(cargas, repositories.CargaAutoridad{
+			AutoridadID:      a.ID,
+			Nombre:           p.Nombre,
+			Cargo:            a.Cargo,
+			Visitas:          int64(len(visitas[a.ID])),
+			DudasAsignadas:   asignadas[a.ID],
+			DudasRespondidas: respondidas[a.ID],
+			DudasPendientes:  asignadas[a.ID] - respondidas[a.ID],
+		})
+	}
+	sort.SliceStable(cargas, func(i, j int) bool {
+		a, b := cargas[i], cargas[j]
+		if a.Visitas != b.Visitas {
+			return a.Visitas > b.Visitas
+		}
+		if a.DudasAsignadas != b.DudasAsignadas {
+			return a.DudasAsignadas > b.DudasAsignadas
+		}
+		return a.Nombre < b.Nombre
+	})
+	return cargas, nil
+}
+
+// TiemposRespuestaDudas resume las dudas preguntadas en el rango; las horas de respuesta solo
+// cuentan las dudas respondidas que tienen fecha de respuesta
+func (r *AnaliticaRepository) TiemposRespuestaDudas(rango repositories.RangoAnalitica, agrupacion string) (*repositories.TiemposRespuesta, []repositories.TiemposRespuesta, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	total := &tiemposDudas{}
+	porPeriodo := map[string]*tiemposDudas{}
+	for _, d := range r.s.dudas.find(false, func(d *models.Dudas) bool { return enRango(rango, d.FechaPregunta) }) {
+		periodo := repositories.EtiquetaPeriodo(d.FechaPregunta, agrupacion)
+		if porPeriodo[periodo] == nil {
+			porPeriodo[periodo] = &tiemposDudas{}
+		}
+		total.sumar(&d)
+		porPeriodo[periodo].sumar(&d)
+	}
+
+	resumen := total.resumen("")
+	periodos := []repositories.TiemposRespuesta{}
+	for periodo, t := range porPeriodo {
+		periodos = append(periodos, t.resumen(periodo))
+	}
+	sort.Slice(periodos, func(i, j int) bool { return periodos[i].Periodo < periodos[j].Periodo })
+	return &resumen, periodos, nil
+}
+
+// AlcanceComunicados resume por canal los comunicados creados en el rango
+func (r *AnaliticaRepository) AlcanceComunicados(rango repositories.RangoAnalitica) ([]repositories.AlcanceCanal, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	canales := map[string]*repositories.AlcanceCanal{}
+	for _, c := range r.s.comunicados.find(false, func(c *models.Comunicado) bool { return enRango(rango, c.CreatedAt) }) {
+		// Los valores por defecto de las columnas canal y estado
+		canal, estado := c.Canal, c.Estado
+		if canal == "" {
+			canal = "correo"
+		}
+		if estado == "" {
+			estado = "enviado"
+		}
+		alcance := canales[canal]
+		if alcance == nil {
+			alcance = &repositories.AlcanceCanal{Canal: canal}
+			canales[canal] = alcance
+		}
+		alcance.Comunicados++
+		if estado == "enviado" {
+			alcance.Enviados++
+			alcance.Destinatarios += int64(c.EnviadoA)
+		}
+	}
+	alcance := []repositories.AlcanceCanal{}
+	for _, a := range canales {
+		alcance = append(alcance, *a)
+	}
+	sort.Slice(alcance, func(i, j int) bool {
+		if alcance[i].Comunicados != alcance[j].Comunicados {
+			return alcance[i].Comunicados > alcance[j].Comunicados
+		}
+		return alcance[i].Canal < alcance[j].Canal
+	})
+	return alcance, nil
+}
+
+// agrupador suma los totales de los grupos de un desglose por ID y nombre
+type agrupador struct {
+	grupos []repositories.ConteoGrupo
+	indice map[repositories.ConteoGrupo]int
+}
+
+func (g *agrupador) sumar(id uint, nombre string) {
+	if g.indice == nil {
+		g.indice = map[repositories.ConteoGrupo]int{}
+	}
+	clave := repositories.ConteoGrupo{ID: id, Nombre: nombre}
+	i, ok := g.indice[clave]
+	if !ok {
+		i = len(g.grupos)
+		g.indice[clave] = i
+		g.grupos = append(g.grupos, clave)
+	}
+	g.grupos[i].Total++
+}
+
+// ordenados devuelve los grupos del mayor al menor total, hasta limite si es positivo
+func (g *agrupador) ordenados(limite int) []repositories.ConteoGrupo {
+	grupos := append([]repositories.ConteoGrupo{}, g.grupos...)
+	sort.Slice(grupos, func(i, j int) bool {
+		a, b := grupos[i], grupos[j]
+		if a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		if a.Nombre != b.Nombre {
+			return a.Nombre < b.Nombre
+		}
+		return a.ID < b.ID
+	})
+	if limite > 0 && len(grupos) > limite {
+		grupos = grupos[:limite]
+	}
+	return grupos
+}
+
+// tiemposDudas acumula las dudas de un período y las horas que tardaron en responderse
+type tiemposDudas struct {
+	preguntas, respondidas int64
+	horas                  []float64
+}
+
+func (t *tiemposDudas) sumar(d *models.Dudas) {
+	t.preguntas++
+	if !respondida(d) {
+		return
+	}
+	t.respondidas++
+	if d.FechaRespuesta != nil {
+		t.horas = append(t.horas, d.FechaRespuesta.Sub(d.FechaPregunta).Hours())
+	}
+}
+
+func (t *tiemposDudas) resumen(periodo string) repositories.TiemposRespuesta {
+	res := repositories.TiemposRespuesta{
+		Periodo:      periodo,
+		Preguntas:    t.preguntas,
+		Respondidas:  t.respondidas,
+		SinResponder: t.preguntas - t.respondidas,
+	}
+	if len(t.horas) == 0 {
+		return res
+	}
+	sort.Float64s(t.horas)
+	var suma float64
+	for _, h := range t.horas {
+		suma += h
+	}
+	promedio := suma / float64(len(t.horas))
+	mediana, p90 := percentil(t.horas, 0.5), percentil(t.horas, 0.9)
+	res.PromedioHoras, res.MedianaHoras, res.P90Horas = &promedio, &mediana, &p90
+	return res
+}
+
+// percentil interpola entre los valores ordenados como percentile_cont de Postgres
+func percentil(ordenados []float64, p float64) float64 {
+	pos := p * float64(len(ordenados)-1)
+	i := int(math.Floor(pos))
+	if i+1 >= len(ordenados) {
+		return ordenados[i]
+	}
+	return ordenados[i] + (pos-float64(i))*(ordenados[i+1]-ordenados[i])
+}
@@ -0,0 +1,136 @@
+package memory
+
+import (
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+)
+
+// AutoridadUTEQRepository implementa repositories.AutoridadUTEQRepository en memoria
+type AutoridadUTEQRepository struct {
+	s *Store
+}
+
+var _ repositories.AutoridadUTEQRepository = (*AutoridadUTEQRepository)(nil)
+
+func NewAutoridadUTEQRepository(s *Store) *AutoridadUTEQRepository {
+	return &AutoridadUTEQRepository{s: s}
+}
+
+func (r *AutoridadUTEQRepository) withRelations(a models.AutoridadUTEQ) models.AutoridadUTEQ {
+	a.Persona = r.s.persona(a.PersonaID)
+	a.DetalleAutoridadDetallesVisitas = r.s.detallesAutoridad.find(false, func(d *models.DetalleAutoridadDetallesVisita) bool {
+		return d.AutoridadUTEQID == a.ID
+	})
+	a.Dudas = r.s.dudas.find(false, func(d *models.Dudas) bool {
+		return d.AutoridadUTEQID != nil && *d.AutoridadUTEQID == a.ID
+	})
+	return a
+}
+
+// checkPersona evita dos autoridades activas para la misma persona
+func (r *AutoridadUTEQRepository) checkPersona(autoridad *models.AutoridadUTEQ) error {
+	n := r.s.autoridades.count(func(a *models.AutoridadUTEQ) bool {
+		return a.PersonaID == autoridad.PersonaID && a.ID != autoridad.ID
+	})
+	if n > 0 {
+		return repositories.ErrAutoridadDuplicada
+	}
+	return nil
+}
+
+func (r *AutoridadUTEQRepository) find(unscoped bool, match func(*models.AutoridadUTEQ) bool) []models.AutoridadUTEQ {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	return mapRows(r.s.autoridades.find(unscoped, match), r.withRelations)
+}
+
+// CreateAutoridadUTEQ crea una nueva autoridad UTEQ
+func (r *AutoridadUTEQRepository) CreateAutoridadUTEQ(autoridad *models.AutoridadUTEQ) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if _, err := r.s.personas.get(autoridad.PersonaID, false); err != nil {
+		return repositories.ErrAutoridadSinPersona
+	}
+	if err := r.checkPersona(autoridad); err != nil {
+		return err
+	}
+	r.s.autoridades.insert(autoridad)
+	return nil
+}
+
+// GetAutoridadUTEQByID obtiene una autoridad UTEQ por ID
+func (r *AutoridadUTEQRepository) GetAutoridadUTEQByID(id uint) (*models.AutoridadUTEQ, error) {
+	return getByID(r.s, &r.s.autoridades, id, r.withRelations)
+}
+
+// GetAllAutoridadesUTEQ obtiene todas las autoridades UTEQ
+func (r *AutoridadUTEQRepository) GetAllAutoridadesUTEQ() ([]models.AutoridadUTEQ, error) {
+	return r.find(false, nil), nil
+}
+
+// UpdateAutoridadUTEQ actualiza una autoridad UTEQ
+func (r *AutoridadUTEQRepository) UpdateAutoridadUTEQ(autoridad *models.AutoridadUTEQ) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if err := r.checkPersona(autoridad); err != nil {
+		return err
+	}
+	r.s.autoridades.save(autoridad)
+	return nil
+}
+
+// DeleteAutoridadUTEQ elimina una autoridad UTEQ y en cascada su usuario y persona
+func (r *AutoridadUTEQRepository) DeleteAutoridadUTEQ(id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	autoridad, err := r.s.autoridades.get(id, false)
+	if err != nil {
+		return err
+	}
+	r.s.autoridades.softDelete(byID[models.AutoridadUTEQ](id))
+	r.s.usuarios.softDelete(func(u *models.Usuario) bool { return u.PersonaID == autoridad.PersonaID })
+	r.s.personas.softDelete(byID[models.Persona](autoridad.PersonaID))
+	return nil
+}
+
+// RestoreAutoridadUTEQ restaura una autoridad UTEQ eliminada y en cascada su usuario y persona
+func (r *AutoridadUTEQRepository) RestoreAutoridadUTEQ(id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	autoridad, err := r.s.autoridades.get(id, true)
+	if err != nil {
+		return err
+	}
+	r.s.personas.restore(byID[models.Persona](autoridad.PersonaID))
+	r.s.usuarios.restore(func(u *models.Usuario) bool { return u.PersonaID == autoridad.PersonaID })
+	r.s.autoridades.restore(byID[models.AutoridadUTEQ](id))
+	return nil
+}
+
+// GetAllAutoridadesUTEQIncludingDeleted obtiene todas las autoridades UTEQ incluyendo las eliminadas
+func (r *AutoridadUTEQRepository) GetAllAutoridadesUTEQIncludingDeleted() ([]models.AutoridadUTEQ, error) {
+	return r.find(true, nil), nil
+}
+
+// GetDeletedAutoridadesUTEQ obtiene solo las autoridades UTEQ eliminadas
+func (r *AutoridadUTEQRepository) GetDeletedAutoridadesUTEQ() ([]models.AutoridadUTEQ, error) {
+	return r.find(true, isDeleted[models.AutoridadUTEQ]), nil
+}
+
+// GetAutoridadesUTEQByCargo obtiene autoridades por cargo
+func (r *AutoridadUTEQRepository) GetAutoridadesUTEQByCargo(cargo string) ([]models.AutoridadUTEQ, error) {
+	return r.find(false, func(a *models.AutoridadUTEQ) bool { return containsFold(a.Cargo, cargo) }), nil
+}
+
+// GetAutoridadUTEQByPersona obtiene autoridad UTEQ por persona
+func (r *AutoridadUTEQRepository) GetAutoridadUTEQByPersona(personaID uint) (*models.AutoridadUTEQ, error) {
+	return firstWhere(r.s, &r.s.autoridades, func(a *models.AutoridadUTEQ) bool { return a.PersonaID == personaID }, r.withRelations)
+}
+
+// ExistsByID verifica si existe una autoridad con el ID indicado
+func (r *AutoridadUTEQRepository) ExistsByID(id uint) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	_, err := r.s.autoridades.get(id, false)
+	return err == nil, nil
+}
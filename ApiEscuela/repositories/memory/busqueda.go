@@ -0,0 +1,172 @@
+package memory
+
+import (
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"ApiEscuela/repositories"
+)
+
+// Pesos de las columnas A y B, en la escala de ts_rank_cd
+const (
+	pesoPrincipal  = 0.1
+	pesoSecundario = 0.04
+)
+
+// palabrasFragmento limita las palabras del fragmento, como MaxWords de ts_headline
+const palabrasFragmento = 35
+
+// sinTildes quita las tildes como el diccionario unaccent
+var sinTildes = strings.NewReplacer(
+	"á", "a", "à", "a", "ä", "a", "â", "a",
+	"é", "e", "è", "e", "ë", "e", "ê", "e",
+	"í", "i", "ì", "i", "ï", "i", "î", "i",
+	"ó", "o", "ò", "o", "ö", "o", "ô", "o",
+	"ú", "u", "ù", "u", "ü", "u", "û", "u",
+	"ñ", "n", "ç", "c",
+)
+
+// etiquetasHTML reconoce las etiquetas de los mensajes de los comunicados, que no se indexan
+var etiquetasHTML = regexp.MustCompile(`<[^>]*>`)
+
+// normalizar pasa la palabra a minúsculas y sin tildes
+func normalizar(palabra string) string {
+	return sinTildes.Replace(strings.ToLower(palabra))
+}
+
+// search emula la búsqueda de texto completo de los repositorios GORM: cada palabra del término
+// debe ser el inicio de alguna palabra de los textos, sin distinguir mayúsculas ni tildes. El
+// primer texto que devuelve textos tiene el peso A y el resto el B; soloPrincipal limita la
+// búsqueda al primero. No aplica la raíz de las palabras ni descarta las palabras vacías.
+func search[T any](s *Store, t *table[T], termino string, soloPrincipal bool, textos func(*T) []string, rel func(T) T) ([]repositories.ResultadoBusqueda[T], error) {
+	terminos := repositories.PalabrasBusqueda(termino)
+	for i, p := range terminos {
+		terminos[i] = normalizar(p)
+	}
+	resultados := []repositories.ResultadoBusqueda[T]{}
+	if len(terminos) == 0 {
+		return resultados, nil
+	}
+	rows, err := list(s, t, func(row *T) bool {
+		_, ok := relevancia(textos(row), terminos, soloPrincipal)
+		return ok
+	}, rel)
+	if err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		campos := textos(&rows[i])
+		puntaje, _ := relevancia(campos, terminos, soloPrincipal)
+		resultados = append(resultados, repositories.ResultadoBusqueda[T]{
+			Registro: rows[i], Relevancia: puntaje, Fragmento: fragmento(campos, terminos),
+		})
+	}
+	sort.SliceStable(resultados, func(i, j int) bool { return resultados[i].Relevancia > resultados[j].Relevancia })
+	return resultados, nil
+}
+
+// relevancia suma el peso de cada palabra que coincide con algún término; ok indica si todos los
+// términos coinciden
+func relevancia(campos, terminos []string, soloPrincipal bool) (total float64, ok bool) {
+	encontrados := make([]bool, len(terminos))
+	for i, campo := range campos {
+		if i > 0 && soloPrincipal {
+			break
+		}
+		peso := pesoPrincipal
+		if i > 0 {
+			peso = pesoSecundario
+		}
+		for _, palabra := range repositories.PalabrasBusqueda(campo) {
+			palabra = normalizar(palabra)
+			for j, termino := range terminos {
+				if strings.HasPrefix(palabra, termino) {
+					encontrados[j] = true
+					total += peso
+				}
+			}
+		}
+	}
+	for _, e := range encontrados {
+		if !e {
+			return 0, false
+		}
+	}
+	return total, true
+}
+
+// fragmento une los textos, los escapa como HTML y marca las palabras que coinciden con <mark>,
+// desde poco antes de la primera coincidencia y hasta palabrasFragmento palabras
+func fragmento(campos, terminos []string) string {
+	var textos []string
+	for _, c := range campos {
+		if c != "" {
+			textos = append(textos, c)
+		}
+	}
+	texto := strings.Join(textos, " — ")
+
+	// Separa el texto en palabras y separadores, conservando ambos
+	var partes []string
+	inicio, enPalabra := 0, false
+	for i, r := range texto {
+		if i > 0 && esLetra(r) != enPalabra {
+			partes = append(partes, texto[inicio:i])
+			inicio = i
+		}
+		enPalabra = esLetra(r)
+	}
+	if inicio < len(texto) {
+		partes = append(partes, texto[inicio:])
+	}
+
+	coincide := func(parte string) bool {
+		palabra := normalizar(parte)
+		for _, t := range terminos {
+			if esLetra([]rune(parte)[0]) && strings.HasPrefix(palabra, t) {
+				return true
+			}
+		}
+		return false
+	}
+	primera := 0
+	for i, p := range partes {
+		if coincide(p) {
+			primera = i
+			break
+		}
+	}
+	// Cinco palabras de contexto antes de la primera coincidencia (cada palabra va con su separador)
+	desde := primera - 10
+	if desde < 0 {
+		desde = 0
+	}
+	var b strings.Builder
+	if desde > 0 {
+		b.WriteString("… ")
+	}
+	palabras := 0
+	for _, p := range partes[desde:] {
+		if esLetra([]rune(p)[0]) {
+			if palabras == palabrasFragmento {
+				b.WriteString(" …")
+				break
+			}
+			palabras++
+		}
+		if coincide(p) {
+			b.WriteString("<mark>" + html.EscapeString(p) + "</mark>")
+		} else {
+			b.WriteString(html.EscapeString(p))
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// esLetra indica si el carácter forma parte de una palabra
+func esLetra(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
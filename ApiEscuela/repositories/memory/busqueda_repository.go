@@ -0,0 +1,186 @@
+package memory
+
+import (
+	"sort"
+	"strings"
+
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+)
+
+// umbralParecido emula pg_trgm.word_similarity_threshold
+const umbralParecido = 0.6
+
+// BusquedaRepository implementa repositories.BusquedaRepository en memoria. Los trigramas y la
+// similitud se aproximan a los de pg_trgm.
+type BusquedaRepository struct {
+	s         *Store
+	programas *ProgramaVisitaRepository
+}
+
+var _ repositories.BusquedaRepository = (*BusquedaRepository)(nil)
+
+func NewBusquedaRepository(s *Store) *BusquedaRepository {
+	return &BusquedaRepository{s: s, programas: NewProgramaVisitaRepository(s)}
+}
+
+// BuscarPersonas busca personas cuyo nombre contiene todas las palabras del término, cuya cédula
+// empieza con él o cuyo correo o teléfono lo contienen
+func (r *BusquedaRepository) BuscarPersonas(termino string, limite int) ([]repositories.ResultadoBusqueda[models.Persona], error) {
+	palabras := repositories.PalabrasBusqueda(termino)
+	digitos := repositories.SoloDigitos(termino)
+	personas, err := list(r.s, &r.s.personas, func(p *models.Persona) bool {
+		return strings.HasPrefix(p.Cedula, termino) ||
+			(p.Correo != nil && strings.Contains(strings.ToLower(*p.Correo), strings.ToLower(termino))) ||
+			(p.Telefono != nil && len(digitos) >= repositories.DigitosTelefono && strings.Contains(repositories.SoloDigitos(*p.Telefono), digitos)) ||
+			contieneTodas(p.Nombre, palabras)
+	}, nil)
+	return porRelevancia(personas, limite, func(p *models.Persona) float64 {
+		texto := []string{p.Nombre, p.Cedula}
+		if p.Correo != nil {
+			texto = append(texto, *p.Correo)
+		}
+		if p.Telefono != nil {
+			texto = append(texto, *p.Telefono)
+		}
+		return similitud(termino, strings.Join(texto, " "))
+	}, nombrePersona), err
+}
+
+// BuscarInstituciones busca instituciones cuyo nombre contiene todas las palabras del término
+func (r *BusquedaRepository) BuscarInstituciones(termino string, limite int) ([]repositories.ResultadoBusqueda[models.Institucion], error) {
+	palabras := repositories.PalabrasBusqueda(termino)
+	instituciones, err := list(r.s, &r.s.instituciones, func(i *models.Institucion) bool {
+		return contieneTodas(i.Nombre, palabras)
+	}, nil)
+	return porRelevancia(instituciones, limite, func(i *models.Institucion) float64 {
+		return similitud(termino, i.Nombre)
+	}, nombreInstitucion), err
+}
+
+// BuscarProgramasVisita busca programas de visita de la fecha indicada en el término o cuya
+// institución contiene todas sus palabras, los más recientes primero
+func (r *BusquedaRepository) BuscarProgramasVisita(termino string, limite int) ([]models.ProgramaVisita, error) {
+	fecha, esFecha := repositories.FechaBusqueda(termino)
+	palabras := repositories.PalabrasBusqueda(termino)
+	programas, err := list(r.s, &r.s.programasVisita, func(p *models.ProgramaVisita) bool {
+		if esFecha {
+			return !p.Fecha.Before(fecha) && p.Fecha.Before(fecha.AddDate(0, 0, 1))
+		}
+		institucion := r.s.institucion(p.InstitucionID)
+		return institucion.ID != 0 && contieneTodas(institucion.Nombre, palabras)
+	}, r.programas.withRelations)
+	sort.SliceStable(programas, func(i, j int) bool { return programas[i].Fecha.After(programas[j].Fecha) })
+	return limitar(programas, limite), err
+}
+
+// AutocompletarPersonas sugiere personas por nombre o por el inicio de la cédula
+func (r *BusquedaRepository) AutocompletarPersonas(termino string, limite int) ([]repositories.ResultadoBusqueda[models.Persona], error) {
+	personas, err := list(r.s, &r.s.personas, func(p *models.Persona) bool {
+		return parecido(p.Nombre, termino) || strings.HasPrefix(p.Cedula, termino)
+	}, nil)
+	return porRelevancia(personas, limite, func(p *models.Persona) float64 {
+		return similitud(termino, p.Nombre)
+	}, nombrePersona), err
+}
+
+// AutocompletarInstituciones sugiere instituciones por nombre
+func (r *BusquedaRepository) AutocompletarInstituciones(termino string, limite int) ([]repositories.ResultadoBusqueda[models.Institucion], error) {
+	instituciones, err := list(r.s, &r.s.instituciones, func(i *models.Institucion) bool {
+		return parecido(i.Nombre, termino)
+	}, nil)
+	return porRelevancia(instituciones, limite, func(i *models.Institucion) float64 {
+		return similitud(termino, i.Nombre)
+	}, nombreInstitucion), err
+}
+
+// AutocompletarProgramasVisita sugiere programas de visita por el nombre de la institución, de la
+// institución más parecida a la menos parecida y luego los más recientes primero
+func (r *BusquedaRepository) AutocompletarProgramasVisita(termino string, limite int) ([]models.ProgramaVisita, error) {
+	programas, err := list(r.s, &r.s.programasVisita, func(p *models.ProgramaVisita) bool {
+		institucion := r.s.institucion(p.InstitucionID)
+		return institucion.ID != 0 && parecido(institucion.Nombre, termino)
+	}, r.programas.withRelations)
+	sort.SliceStable(programas, func(i, j int) bool {
+		a, b := similitud(termino, programas[i].Institucion.Nombre), similitud(termino, programas[j].Institucion.Nombre)
+		if a != b {
+			return a > b
+		}
+		return programas[i].Fecha.After(programas[j].Fecha)
+	})
+	return limitar(programas, limite), err
+}
+
+// contieneTodas emula sin_tildes(texto) LIKE '%palabra%' para cada palabra; sin palabras no coincide
+func contieneTodas(texto string, palabras []string) bool {
+	texto = normalizar(texto)
+	for _, p := range palabras {
+		if !strings.Contains(texto, normalizar(p)) {
+			return false
+		}
+	}
+	return len(palabras) > 0
+}
+
+// parecido emula la condición del autocompletado: el texto contiene el término o se le parece
+func parecido(texto, termino string) bool {
+	return strings.Contains(normalizar(texto), normalizar(termino)) || similitud(termino, texto) >= umbralParecido
+}
+
+// trigramas devuelve los trigramas de las palabras del texto como pg_trgm: cada palabra, sin
+// tildes y en minúsculas, con dos espacios delante y uno detrás
+func trigramas(texto string) map[string]bool {
+	t := map[string]bool{}
+	for _, p := range repositories.PalabrasBusqueda(normalizar(texto)) {
+		runas := []rune("  " + p + " ")
+		for i := 0; i+3 <= len(runas); i++ {
+			t[string(runas[i:i+3])] = true
+		}
+	}
+	return t
+}
+
+// similitud aproxima word_similarity(termino, texto): la fracción de los trigramas del término que
+// aparecen en el texto
+func similitud(termino, texto string) float64 {
+	delTermino := trigramas(termino)
+	if len(delTermino) == 0 {
+		return 0
+	}
+	delTexto := trigramas(texto)
+	comunes := 0
+	for t := range delTermino {
+		if delTexto[t] {
+			comunes++
+		}
+	}
+	return float64(comunes) / float64(len(delTermino))
+}
+
+// porRelevancia calcula la relevancia de cada registro con puntuar, ordena de la relevancia mayor a
+// la menor y luego por nombre, y aplica el límite
+func porRelevancia[T any](rows []T, limite int, puntuar func(*T) float64, nombre func(*T) string) []repositories.ResultadoBusqueda[T] {
+	resultados := make([]repositories.ResultadoBusqueda[T], len(rows))
+	for i := range rows {
+		resultados[i] = repositories.ResultadoBusqueda[T]{Registro: rows[i], Relevancia: puntuar(&rows[i])}
+	}
+	sort.SliceStable(resultados, func(i, j int) bool {
+		if resultados[i].Relevancia != resultados[j].Relevancia {
+			return resultados[i].Relevancia > resultados[j].Relevancia
+		}
+		return nombre(&resultados[i].Registro) < nombre(&resultados[j].Registro)
+	})
+	return limitar(resultados, limite)
+}
+
+func nombrePersona(p *models.Persona) string { return p.Nombre }
+
+func nombreInstitucion(i *models.Institucion) string { return i.Nombre }
+
+// limitar devuelve como máximo limite registros; limite <= 0 no limita
+func limitar[T any](rows []T, limite int) []T {
+	if limite > 0 && len(rows) > limite {
+		return rows[:limite]
+	}
+	return rows
+}
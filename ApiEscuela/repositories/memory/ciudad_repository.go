@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+)
+
+// CiudadRepository implementa repositories.CiudadRepository en memoria
+type CiudadRepository struct {
+	s *Store
+}
+
+var _ repositories.CiudadRepository = (*CiudadRepository)(nil)
+
+func NewCiudadRepository(s *Store) *CiudadRepository {
+	return &CiudadRepository{s: s}
+}
+
+func (r *CiudadRepository) withProvincia(c models.Ciudad) models.Ciudad {
+	c.Provincia = r.s.provincia(c.ProvinciaID)
+	return c
+}
+
+func (r *CiudadRepository) withRelations(c models.Ciudad) models.Ciudad {
+	c = r.withProvincia(c)
+	c.Estudiantes = r.s.estudiantes.find(false, func(e *models.Estudiante) bool { return e.CiudadID == c.ID })
+	return c
+}
+
+// CreateCiudad crea una nueva ciudad
+func (r *CiudadRepository) CreateCiudad(ciudad *models.Ciudad) error {
+	return create(r.s, &r.s.ciudades, ciudad)
+}
+
+// GetCiudadByID obtiene una ciudad por ID
+func (r *CiudadRepository) GetCiudadByID(id uint) (*models.Ciudad, error) {
+	return getByID(r.s, &r.s.ciudades, id, r.withRelations)
+}
+
+// GetAllCiudades obtiene todas las ciudades
+func (r *CiudadRepository) GetAllCiudades() ([]models.Ciudad, error) {
+	return list(r.s, &r.s.ciudades, nil, r.withProvincia)
+}
+
+// UpdateCiudad actualiza una ciudad
+func (r *CiudadRepository) UpdateCiudad(ciudad *models.Ciudad) error {
+	return save(r.s, &r.s.ciudades, ciudad)
+}
+
+// DeleteCiudad elimina una ciudad
+func (r *CiudadRepository) DeleteCiudad(id uint) error {
+	return remove(r.s, &r.s.ciudades, byID[models.Ciudad](id))
+}
+
+// GetCiudadesByProvincia obtiene ciudades por provincia
+func (r *CiudadRepository) GetCiudadesByProvincia(provinciaID uint) ([]models.Ciudad, error) {
+	return list(r.s, &r.s.ciudades, func(c *models.Ciudad) bool { return c.ProvinciaID == provinciaID }, r.withProvincia)
+}
+
+// GetCiudadByNombre busca ciudad por nombre
+func (r *CiudadRepository) GetCiudadByNombre(nombre string) ([]models.Ciudad, error) {
+	return list(r.s, &r.s.ciudades, func(c *models.Ciudad) bool { return containsFold(c.Ciudad, nombre) }, r.withProvincia)
+}
+
+// ExistsByID verifica si existe una ciudad con el ID indicado
+func (r *CiudadRepository) ExistsByID(id uint) (bool, error) {
+	return exists(r.s, &r.s.ciudades, byID[models.Ciudad](id))
+}
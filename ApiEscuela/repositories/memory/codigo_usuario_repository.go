@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"time"
+
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+
+	"gorm.io/gorm"
+)
+
+// CodigoUsuarioRepository implementa repositories.CodigoUsuarioRepository en memoria
+type CodigoUsuarioRepository struct {
+	s *Store
+}
+
+var _ repositories.CodigoUsuarioRepository = (*CodigoUsuarioRepository)(nil)
+
+func NewCodigoUsuarioRepository(s *Store) *CodigoUsuarioRepository {
+	return &CodigoUsuarioRepository{s: s}
+}
+
+// Crear inserta un nuevo código para un usuario con expiración de 3 minutos
+func (r *CodigoUsuarioRepository) Crear(usuarioID uint, codigo string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	expiraEn := time.Now().Add(3 * time.Minute)
+	r.s.codigos.insert(&models.CodigoUsuario{
+		UsuarioID: usuarioID,
+		Codigo:    codigo,
+		ExpiraEn:  &expiraEn,
+		Estado:    repositories.EstadoValido,
+	})
+	return nil
+}
+
+// ExisteVigentePorUsuario verifica si el usuario tiene un código válido y no expirado
+func (r *CodigoUsuarioRepository) ExisteVigentePorUsuario(usuarioID uint) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	now := time.Now()
+	n := r.s.codigos.count(func(c *models.CodigoUsuario) bool {
+		return c.UsuarioID == usuarioID && c.Estado == repositories.EstadoValido &&
+			c.ExpiraEn != nil && c.ExpiraEn.After(now)
+	})
+	return n > 0, nil
+}
+
+// FindLatestByCodigo obtiene el último registro creado para un código dado
+func (r *CodigoUsuarioRepository) FindLatestByCodigo(codigo string) (*models.CodigoUsuario, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	codigos := r.s.codigos.find(false, func(c *models.CodigoUsuario) bool { return c.Codigo == codigo })
+	if len(codigos) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	// Los IDs son crecientes: el último es el más reciente
+	return &codigos[len(codigos)-1], nil
+}
+
+// Update actualiza un registro de código de usuario
+func (r *CodigoUsuarioRepository) Update(rec *models.CodigoUsuario) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.codigos.save(rec)
+	return nil
+}
+
+// GetByID obtiene un código por su ID
+func (r *CodigoUsuarioRepository) GetByID(id uint) (*models.CodigoUsuario, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	return one(r.s.codigos.get(id, false))
+}
+
+// MarcarComoVerificado marca un código como verificado
+func (r *CodigoUsuarioRepository) MarcarComoVerificado(id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.codigos.update(byID[models.CodigoUsuario](id), func(c *models.CodigoUsuario) {
+		c.Estado = repositories.EstadoVerificado
+		c.ExpiraEn = nil
+	})
+	return nil
+}
+
+// MarcarComoExpirado marca un código como expirado
+func (r *CodigoUsuarioRepository) MarcarComoExpirado(id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.codigos.update(byID[models.CodigoUsuario](id), func(c *models.CodigoUsuario) {
+		c.Estado = repositories.EstadoExpirado
+	})
+	return nil
+}
+
+// GetCodigosValidosExpirados obtiene códigos que están en estado válido pero ya expiraron por tiempo
+func (r *CodigoUsuarioRepository) GetCodigosValidosExpirados(usuarioID uint) ([]models.CodigoUsuario, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	now := time.Now()
+	return r.s.codigos.find(false, func(c *models.CodigoUsuario) bool {
+		return c.UsuarioID == usuarioID && c.Estado == repositories.EstadoValido &&
+			c.ExpiraEn != nil && !c.ExpiraEn.After(now)
+	}), nil
+}
+
+// ExpirarVigentesPorUsuario marca como expirados todos los códigos válidos del usuario
+func (r *CodigoUsuarioRepository) ExpirarVigentesPorUsuario(usuarioID uint) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var n int64
+	r.s.codigos.update(func(c *models.CodigoUsuario) bool {
+		return c.UsuarioID == usuarioID && c.Estado == repositories.EstadoValido
+	}, func(c *models.CodigoUsuario) {
+		c.Estado = repositories.EstadoExpirado
+		n++
+	})
+	return n, nil
+}
+
+// ExpirarVencidos marca como expirados los códigos válidos cuya fecha de expiración ya pasó
+func (r *CodigoUsuarioRepository) ExpirarVencidos(ahora time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	var n int64
+	r.s.codigos.update(func(c *models.CodigoUsuario) bool {
+		return c.Estado == repositories.EstadoValido && c.ExpiraEn != nil && !c.ExpiraEn.After(ahora)
+	}, func(c *models.CodigoUsuario) {
+		c.Estado = repositories.EstadoExpirado
+		n++
+	})
+	return n, nil
+}
+
+// PurgarExpirados elimina definitivamente los códigos que dejaron de ser utilizables antes de la fecha indicada
+func (r *CodigoUsuarioRepository) PurgarExpirados(antes time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.codigos.purge(func(c *models.CodigoUsuario) bool {
+		if c.Estado != repositories.EstadoValido {
+			return c.UpdatedAt.Before(antes)
+		}
+		return c.ExpiraEn != nil && c.ExpiraEn.Before(antes)
+	}), nil
+}
@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+)
+
+// ComunicadoRepository implementa repositories.ComunicadoRepository en memoria
+type ComunicadoRepository struct {
+	s *Store
+}
+
+var _ repositories.ComunicadoRepository = (*ComunicadoRepository)(nil)
+
+func NewComunicadoRepository(s *Store) *ComunicadoRepository {
+	return &ComunicadoRepository{s: s}
+}
+
+func (r *ComunicadoRepository) withRelations(c models.Comunicado) models.Comunicado {
+	c.Usuario = r.s.usuario(c.UsuarioID)
+	return c
+}
+
+// newestFirst ordena por fecha de creación descendente (los IDs son crecientes)
+func newestFirst(comunicados []models.Comunicado, err error) ([]models.Comunicado, error) {
+	for i, j := 0, len(comunicados)-1; i < j; i, j = i+1, j-1 {
+		comunicados[i], comunicados[j] = comunicados[j], comunicados[i]
+	}
+	return comunicados, err
+}
+
+// CreateComunicado crea un nuevo comunicado
+func (r *ComunicadoRepository) CreateComunicado(comunicado *models.Comunicado) error {
+	return create(r.s, &r.s.comunicados, comunicado)
+}
+
+// GetComunicadoByID obtiene un comunicado por ID
+func (r *ComunicadoRepository) GetComunicadoByID(id uint) (*models.Comunicado, error) {
+	return getByID(r.s, &r.s.comunicados, id, r.withRelations)
+}
+
+// GetAllComunicados obtiene todos los comunicados ordenados por fecha de creación descendente
+func (r *ComunicadoRepository) GetAllComunicados() ([]models.Comunicado, error) {
+	return newestFirst(list(r.s, &r.s.comunicados, nil, r.withRelations))
+}
+
+// UpdateComunicado actualiza un comunicado
+func (r *ComunicadoRepository) UpdateComunicado(comunicado *models.Comunicado) error {
+	return save(r.s, &r.s.comunicados, comunicado)
+}
+
+// DeleteComunicado elimina un comunicado
+func (r *ComunicadoRepository) DeleteComunicado(id uint) error {
+	return remove(r.s, &r.s.comunicados, byID[models.Comunicado](id))
+}
+
+// GetComunicadosByUsuario obtiene comunicados por usuario
+func (r *ComunicadoRepository) GetComunicadosByUsuario(usuarioID uint) ([]models.Comunicado, error) {
+	return newestFirst(list(r.s, &r.s.comunicados, func(c *models.Comunicado) bool { return c.UsuarioID == usuarioID }, r.withRelations))
+}
+
+// SearchComunicados busca comunicados por las palabras del asunto o del mensaje (sin etiquetas
+// HTML), sin distinguir tildes
+func (r *ComunicadoRepository) SearchComunicados(termino string) ([]repositories.ResultadoBusqueda[models.Comunicado], error) {
+	return search(r.s, &r.s.comunicados, termino, false, func(c *models.Comunicado) []string {
+		return []string{c.Asunto, etiquetasHTML.ReplaceAllString(c.Mensaje, " ")}
+	}, r.withRelations)
+}
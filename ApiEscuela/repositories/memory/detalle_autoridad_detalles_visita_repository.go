@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+)
+
+// DetalleAutoridadDetallesVisitaRepository implementa repositories.DetalleAutoridadDetallesVisitaRepository en memoria
+type DetalleAutoridadDetallesVisitaRepository struct {
+	s *Store
+}
+
+var _ repositories.DetalleAutoridadDetallesVisitaRepository = (*DetalleAutoridadDetallesVisitaRepository)(nil)
+
+func NewDetalleAutoridadDetallesVisitaRepository(s *Store) *DetalleAutoridadDetallesVisitaRepository {
+	return &DetalleAutoridadDetallesVisitaRepository{s: s}
+}
+
+func (r *DetalleAutoridadDetallesVisitaRepository) withRelations(d models.DetalleAutoridadDetallesVisita) models.DetalleAutoridadDetallesVisita {
+	d.ProgramaVisita = r.s.programaVisita(d.ProgramaVisitaID)
+	d.AutoridadUTEQ, _ = r.s.autoridades.get(d.AutoridadUTEQID, false)
+	return d
+}
+
+func byPrograma(programaVisitaID uint) func(*models.DetalleAutoridadDetallesVisita) bool {
+	return func(d *models.DetalleAutoridadDetallesVisita) bool { return d.ProgramaVisitaID == programaVisitaID }
+}
+
+func byAutoridad(autoridadID uint) func(*models.DetalleAutoridadDetallesVisita) bool {
+	return func(d *models.DetalleAutoridadDetallesVisita) bool { return d.AutoridadUTEQID == autoridadID }
+}
+
+// CreateDetalleAutoridadDetallesVisita crea un nuevo detalle de autoridad para visita
+func (r *DetalleAutoridadDetallesVisitaRepository) CreateDetalleAutoridadDetallesVisita(detalle *models.DetalleAutoridadDetallesVisita) error {
+	return create(r.s, &r.s.detallesAutoridad, detalle)
+}
+
+// GetDetalleAutoridadDetallesVisitaByID obtiene un detalle por ID
+func (r *DetalleAutoridadDetallesVisitaRepository) GetDetalleAutoridadDetallesVisitaByID(id uint) (*models.DetalleAutoridadDetallesVisita, error) {
+	return getByID(r.s, &r.s.detallesAutoridad, id, r.withRelations)
+}
+
+// GetAllDetalleAutoridadDetallesVisitas obtiene todos los detalles
+func (r *DetalleAutoridadDetallesVisitaRepository) GetAllDetalleAutoridadDetallesVisitas() ([]models.DetalleAutoridadDetallesVisita, error) {
+	return list(r.s, &r.s.detallesAutoridad, nil, r.withRelations)
+}
+
+// UpdateDetalleAutoridadDetallesVisita actualiza un detalle
+func (r *DetalleAutoridadDetallesVisitaRepository) UpdateDetalleAutoridadDetallesVisita(detalle *models.DetalleAutoridadDetallesVisita) error {
+	return save(r.s, &r.s.detallesAutoridad, detalle)
+}
+
+// DeleteDetalleAutoridadDetallesVisita elimina un detalle
+func (r *DetalleAutoridadDetallesVisitaRepository) DeleteDetalleAutoridadDetallesVisita(id uint) error {
+	return remove(r.s, &r.s.detallesAutoridad, byID[models.DetalleAutoridadDetallesVisita](id))
+}
+
+// GetDetallesByProgramaVisitaID obtiene todos los detalles de un programa de visita específico
+func (r *DetalleAutoridadDetallesVisitaRepository) GetDetallesByProgramaVisitaID(programaVisitaID uint) ([]models.DetalleAutoridadDetallesVisita, error) {
+	return list(r.s, &r.s.detallesAutoridad, byPrograma(programaVisitaID), r.withRelations)
+}
+
+// GetDetallesByAutoridadID obtiene todos los detalles de una autoridad específica
+func (r *DetalleAutoridadDetallesVisitaRepository) GetDetallesByAutoridadID(autoridadID uint) ([]models.DetalleAutoridadDetallesVisita, error) {
+	return list(r.s, &r.s.detallesAutoridad, byAutoridad(autoridadID), r.withRelations)
+}
+
+// DeleteDetallesByProgramaVisitaID elimina todos los detalles de un programa de visita
+func (r *DetalleAutoridadDetallesVisitaRepository) DeleteDetallesByProgramaVisitaID(programaVisitaID uint) error {
+	return remove(r.s, &r.s.detallesAutoridad, byPrograma(programaVisitaID))
+}
+
+// DeleteDetallesByAutoridadID elimina todos los detalles de una autoridad específica
+func (r *DetalleAutoridadDetallesVisitaRepository) DeleteDetallesByAutoridadID(autoridadID uint) error {
+	return remove(r.s, &r.s.detallesAutoridad, byAutoridad(autoridadID))
+}
+
+// ExistsRelation verifica si ya existe una relación entre programa de visita y autoridad
+func (r *DetalleAutoridadDetallesVisitaRepository) ExistsRelation(programaVisitaID, autoridadID uint) (bool, error) {
+	return exists(r.s, &r.s.detallesAutoridad, func(d *models.DetalleAutoridadDetallesVisita) bool {
+		return d.ProgramaVisitaID == programaVisitaID && d.AutoridadUTEQID == autoridadID
+	})
+}
+
+// GetEstadisticasAsignacion obtiene estadísticas de asignación de autoridades
+func (r *DetalleAutoridadDetallesVisitaRepository) GetEstadisticasAsignacion() (map[string]interface{}, error) {
+	detalles, _ := list(r.s, &r.s.detallesAutoridad, nil, nil)
+	total, autoridades, programas, promedio := estadisticas(detalles,
+		func(d models.DetalleAutoridadDetallesVisita) uint { return d.AutoridadUTEQID },
+		func(d models.DetalleAutoridadDetallesVisita) uint { return d.ProgramaVisitaID })
+	return map[string]interface{}{
+		"total_asignaciones":                total,
+		"total_autoridades_unicas":          autoridades,
+		"total_programas_con_autoridades":   programas,
+		"promedio_autoridades_por_programa": promedio,
+	}, nil
+}
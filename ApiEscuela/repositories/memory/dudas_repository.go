@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"time"
+
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+)
+
+// DudasRepository implementa repositories.DudasRepository en memoria
+type DudasRepository struct {
+	s *Store
+}
+
+var _ repositories.DudasRepository = (*DudasRepository)(nil)
+
+func NewDudasRepository(s *Store) *DudasRepository {
+	return &DudasRepository{s: s}
+}
+
+func (r *DudasRepository) withRelations(d models.Dudas) models.Dudas {
+	d.Estudiante = r.s.estudiante(d.EstudianteID)
+	d.Estudiante.Institucion = r.s.institucion(d.Estudiante.InstitucionID)
+	d.Estudiante.Ciudad, _ = r.s.ciudades.get(d.Estudiante.CiudadID, false)
+	d.AutoridadUTEQ = nil
+	if d.AutoridadUTEQID != nil {
+		if _, err := r.s.autoridades.get(*d.AutoridadUTEQID, false); err == nil {
+			autoridad := r.s.autoridad(*d.AutoridadUTEQID)
+			d.AutoridadUTEQ = &autoridad
+		}
+	}
+	return d
+}
+
+func respondida(d *models.Dudas) bool {
+	return d.Respuesta != nil && *d.Respuesta != ""
+}
+
+// CreateDudas crea una nueva duda
+func (r *DudasRepository) CreateDudas(duda *models.Dudas) error {
+	// Establecer la fecha de pregunta automáticamente si no está establecida
+	if duda.FechaPregunta.IsZero() {
+		duda.FechaPregunta = time.Now()
+	}
+	if duda.Privacidad == "" {
+		duda.Privacidad = "publico"
+	}
+	return create(r.s, &r.s.dudas, duda)
+}
+
+// GetDudasByID obtiene una duda por ID
+func (r *DudasRepository) GetDudasByID(id uint) (*models.Dudas, error) {
+	return getByID(r.s, &r.s.dudas, id, r.withRelations)
+}
+
+// GetAllDudas obtiene todas las dudas
+func (r *DudasRepository) GetAllDudas() ([]models.Dudas, error) {
+	return list(r.s, &r.s.dudas, nil, r.withRelations)
+}
+
+// UpdateDudas actualiza una duda
+func (r *DudasRepository) UpdateDudas(duda *models.Dudas) error {
+	return save(r.s, &r.s.dudas, duda)
+}
+
+// DeleteDudas elimina una duda
+func (r *DudasRepository) DeleteDudas(id uint) error {
+	return remove(r.s, &r.s.dudas, byID[models.Dudas](id))
+}
+
+// GetDudasByEstudiante obtiene dudas por estudiante
+func (r *DudasRepository) GetDudasByEstudiante(estudianteID uint) ([]models.Dudas, error) {
+	return list(r.s, &r.s.dudas, func(d *models.Dudas) bool { return d.EstudianteID == estudianteID }, r.withRelations)
+}
+
+// GetDudasByAutoridad obtiene dudas asignadas a una autoridad
+func (r *DudasRepository) GetDudasByAutoridad(autoridadID uint) ([]models.Dudas, error) {
+	return list(r.s, &r.s.dudas, func(d *models.Dudas) bool {
+		return d.AutoridadUTEQID != nil && *d.AutoridadUTEQID == autoridadID
+	}, r.withRelations)
+}
+
+// GetDudasSinResponder obtiene dudas sin respuesta
+func (r *DudasRepository) GetDudasSinResponder() ([]models.Dudas, error) {
+	return list(r.s, &r.s.dudas, func(d *models.Dudas) bool { return !respondida(d) }, r.withRelations)
+}
+
+// GetDudasRespondidas obtiene dudas con respuesta
+func (r *DudasRepository) GetDudasRespondidas() ([]models.Dudas, error) {
+	return list(r.s, &r.s.dudas, respondida, r.withRelations)
+}
+
+// GetDudasSinAsignar obtiene dudas sin autoridad asignada
+func (r *DudasRepository) GetDudasSinAsignar() ([]models.Dudas, error) {
+	return list(r.s, &r.s.dudas, func(d *models.Dudas) bool { return d.AutoridadUTEQID == nil }, r.withRelations)
+}
+
+// BuscarDudasPorPregunta busca dudas por las palabras de la pregunta, sin distinguir tildes
+func (r *DudasRepository) BuscarDudasPorPregunta(termino string) ([]repositories.ResultadoBusqueda[models.Dudas], error) {
+	return search(r.s, &r.s.dudas, termino, true, func(d *models.Dudas) []string {
+		textos := []string{d.Pregunta}
+		if d.Respuesta != nil {
+			textos = append(textos, *d.Respuesta)
+		}
+		return textos
+	}, r.withRelations)
+}
+
+// ResponderDuda actualiza la respuesta de una duda
+func (r *DudasRepository) ResponderDuda(dudaID uint, respuesta string, autoridadID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	now := time.Now()
+	r.s.dudas.update(byID[models.Dudas](dudaID), func(d *models.Dudas) {
+		d.Respuesta = &respuesta
+		d.FechaRespuesta = &now
+		d.AutoridadUTEQID = &autoridadID
+	})
+	return nil
+}
+
+// GetDudasByPrivacidad obtiene dudas por tipo de privacidad
+func (r *DudasRepository) GetDudasByPrivacidad(privacidad string) ([]models.Dudas, error) {
+	return list(r.s, &r.s.dudas, func(d *models.Dudas) bool { return d.Privacidad == privacidad }, r.withRelations)
+}
@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+
+	"gorm.io/gorm"
+)
+
+// EjecucionTareaRepository implementa repositories.EjecucionTareaRepository en memoria
+type EjecucionTareaRepository struct {
+	s *Store
+}
+
+var _ repositories.EjecucionTareaRepository = (*EjecucionTareaRepository)(nil)
+
+func NewEjecucionTareaRepository(s *Store) *EjecucionTareaRepository {
+	return &EjecucionTareaRepository{s: s}
+}
+
+// CreateEjecucion registra el inicio de una ejecución
+func (r *EjecucionTareaRepository) CreateEjecucion(ejecucion *models.EjecucionTarea) error {
+	return create(r.s, &r.s.ejecucionesTarea, ejecucion)
+}
+
+// UpdateEjecucion guarda el resultado de una ejecución
+func (r *EjecucionTareaRepository) UpdateEjecucion(ejecucion *models.EjecucionTarea) error {
+	return save(r.s, &r.s.ejecucionesTarea, ejecucion)
+}
+
+// GetEjecucionesByTarea obtiene las últimas ejecuciones de una tarea, de la más reciente a la más antigua
+func (r *EjecucionTareaRepository) GetEjecucionesByTarea(tarea string, limite int) ([]models.EjecucionTarea, error) {
+	ejecuciones := r.recientes(func(e *models.EjecucionTarea) bool { return e.Tarea == tarea })
+	if limite > 0 && len(ejecuciones) > limite {
+		ejecuciones = ejecuciones[:limite]
+	}
+	return ejecuciones, nil
+}
+
+// GetUltimaEjecucion obtiene la ejecución más reciente de una tarea
+func (r *EjecucionTareaRepository) GetUltimaEjecucion(tarea string) (*models.EjecucionTarea, error) {
+	return masReciente(r.recientes(func(e *models.EjecucionTarea) bool { return e.Tarea == tarea }))
+}
+
+// GetUltimoError obtiene la ejecución fallida más reciente de una tarea
+func (r *EjecucionTareaRepository) GetUltimoError(tarea string) (*models.EjecucionTarea, error) {
+	return masReciente(r.recientes(func(e *models.EjecucionTarea) bool {
+		return e.Tarea == tarea && e.Estado == repositories.EjecucionError
+	}))
+}
+
+// PurgarEjecuciones elimina definitivamente las ejecuciones terminadas que empezaron antes de la fecha indicada
+func (r *EjecucionTareaRepository) PurgarEjecuciones(antes time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.ejecucionesTarea.purge(func(e *models.EjecucionTarea) bool {
+		return e.Inicio.Before(antes) && e.Estado != repositories.EjecucionEnCurso
+	}), nil
+}
+
+// recientes devuelve las ejecuciones que cumplen el filtro ordenadas por inicio descendente
+func (r *EjecucionTareaRepository) recientes(match func(*models.EjecucionTarea) bool) []models.EjecucionTarea {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	ejecuciones := r.s.ejecucionesTarea.find(false, match)
+	sort.SliceStable(ejecuciones, func(i, j int) bool {
+		if !ejecuciones[i].Inicio.Equal(ejecuciones[j].Inicio) {
+			return ejecuciones[i].Inicio.After(ejecuciones[j].Inicio)
+		}
+		return ejecuciones[i].ID > ejecuciones[j].ID
+	})
+	return ejecuciones
+}
+
+// masReciente devuelve la primera ejecución de una lista ordenada por recientes
+func masReciente(ejecuciones []models.EjecucionTarea) (*models.EjecucionTarea, error) {
+	if len(ejecuciones) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &ejecuciones[0], nil
+}
@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+)
+
+// EstudianteUniversitarioRepository implementa repositories.EstudianteUniversitarioRepository en memoria
+type EstudianteUniversitarioRepository struct {
+	s *Store
+}
+
+var _ repositories.EstudianteUniversitarioRepository = (*EstudianteUniversitarioRepository)(nil)
+
+func NewEstudianteUniversitarioRepository(s *Store) *EstudianteUniversitarioRepository {
+	return &EstudianteUniversitarioRepository{s: s}
+}
+
+func (r *EstudianteUniversitarioRepository) withRelations(e models.EstudianteUniversitario) models.EstudianteUniversitario {
+	e.Persona = r.s.persona(e.PersonaID)
+	e.VisitaDetalleEstudiantesUniversitarios = r.s.visitaDetalleEstudiantesUniversitarios.find(false, func(v *models.VisitaDetalleEstudiantesUniversitarios) bool {
+		return v.EstudianteUniversitarioID == e.ID
+	})
+	return e
+}
+
+// checkPersona evita dos estudiantes universitarios activos para la misma persona
+func (r *EstudianteUniversitarioRepository) checkPersona(estudiante *models.EstudianteUniversitario) error {
+	n := r.s.estudiantesUniversitarios.count(func(e *models.EstudianteUniversitario) bool {
+		return e.PersonaID == estudiante.PersonaID && e.ID != estudiante.ID
+	})
+	if n > 0 {
+		return repositories.ErrEstudianteUnivDuplicado
+	}
+	return nil
+}
+
+// CreateEstudianteUniversitario crea un nuevo estudiante universitario
+func (r *EstudianteUniversitarioRepository) CreateEstudianteUniversitario(estudiante *models.EstudianteUniversitario) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if err := r.checkPersona(estudiante); err != nil {
+		return err
+	}
+	r.s.estudiantesUniversitarios.insert(estudiante)
+	return nil
+}
+
+// GetEstudianteUniversitarioByID obtiene un estudiante universitario por ID
+func (r *EstudianteUniversitarioRepository) GetEstudianteUniversitarioByID(id uint) (*models.EstudianteUniversitario, error) {
+	return getByID(r.s, &r.s.estudiantesUniversitarios, id, r.withRelations)
+}
+
+// GetAllEstudiantesUniversitarios obtiene todos los estudiantes universitarios
+func (r *EstudianteUniversitarioRepository) GetAllEstudiantesUniversitarios() ([]models.EstudianteUniversitario, error) {
+	return list(r.s, &r.s.estudiantesUniversitarios, nil, r.withRelations)
+}
+
+// UpdateEstudianteUniversitario actualiza un estudiante universitario
+func (r *EstudianteUniversitarioRepository) UpdateEstudianteUniversitario(estudiante *models.EstudianteUniversitario) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if err := r.checkPersona(estudiante); err != nil {
+		return err
+	}
+	r.s.estudiantesUniversitarios.save(estudiante)
+	return nil
+}
+
+// DeleteEstudianteUniversitario elimina un estudiante universitario
+func (r *EstudianteUniversitarioRepository) DeleteEstudianteUniversitario(id uint) error {
+	return remove(r.s, &r.s.estudiantesUniversitarios, byID[models.EstudianteUniversitario](id))
+}
+
+// GetEstudiantesUniversitariosBySemestre obtiene estudiantes por semestre
+func (r *EstudianteUniversitarioRepository) GetEstudiantesUniversitariosBySemestre(semestre int) ([]models.EstudianteUniversitario, error) {
+	return list(r.s, &r.s.estudiantesUniversitarios, func(e *models.EstudianteUniversitario) bool { return e.Semestre == semestre }, r.withRelations)
+}
+
+// GetEstudianteUniversitarioByPersona obtiene estudiante universitario por persona
+func (r *EstudianteUniversitarioRepository) GetEstudianteUniversitarioByPersona(personaID uint) (*models.EstudianteUniversitario, error) {
+	return firstWhere(r.s, &r.s.estudiantesUniversitarios, func(e *models.EstudianteUniversitario) bool { return e.PersonaID == personaID }, r.withRelations)
+}
@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+)
+
+// ExportacionRepository implementa repositories.ExportacionRepository en memoria
+type ExportacionRepository struct {
+	s           *Store
+	estudiantes *EstudianteRepository
+	programas   *ProgramaVisitaRepository
+	dudas       *DudasRepository
+	comunicados *ComunicadoRepository
+}
+
+var _ repositories.ExportacionRepository = (*ExportacionRepository)(nil)
+
+func NewExportacionRepository(s *Store) *ExportacionRepository {
+	return &ExportacionRepository{
+		s:           s,
+		estudiantes: NewEstudianteRepository(s),
+		programas:   NewProgramaVisitaRepository(s),
+		dudas:       NewDudasRepository(s),
+		comunicados: NewComunicadoRepository(s),
+	}
+}
+
+// RecorrerEstudiantes recorre los estudiantes de la institución o ciudad del filtro
+func (r *ExportacionRepository) RecorrerEstudiantes(filtro repositories.FiltroExportacion, fn func([]models.Estudiante) error) error {
+	return porLotes(list(r.s, &r.s.estudiantes, func(e *models.Estudiante) bool {
+		return (filtro.InstitucionID == 0 || e.InstitucionID == filtro.InstitucionID) &&
+			(filtro.CiudadID == 0 || e.CiudadID == filtro.CiudadID)
+	}, r.estudiantes.withRelations))(fn)
+}
+
+// RecorrerProgramasVisita recorre los programas de visita de la institución o del rango de fechas del filtro
+func (r *ExportacionRepository) RecorrerProgramasVisita(filtro repositories.FiltroExportacion, fn func([]models.ProgramaVisita) error) error {
+	return porLotes(list(r.s, &r.s.programasVisita, func(p *models.ProgramaVisita) bool {
+		enRango := filtro.Desde == nil || filtro.Hasta == nil ||
+			(!p.Fecha.Before(*filtro.Desde) && !p.Fecha.After(*filtro.Hasta))
+		return enRango && (filtro.InstitucionID == 0 || p.InstitucionID == filtro.InstitucionID)
+	}, r.programas.withRelations))(fn)
+}
+
+// RecorrerParticipantes recorre los estudiantes universitarios asignados al programa de visita del filtro
+func (r *ExportacionRepository) RecorrerParticipantes(filtro repositories.FiltroExportacion, fn func([]participacion) error) error {
+	return porLotes(list(r.s, &r.s.visitaDetalleEstudiantesUniversitarios, func(v *participacion) bool {
+		return filtro.ProgramaVisitaID == 0 || v.ProgramaVisitaID == filtro.ProgramaVisitaID
+	}, func(v participacion) participacion {
+		v.EstudianteUniversitario = r.s.estudianteUniversitario(v.EstudianteUniversitarioID)
+		v.ProgramaVisita = r.s.programaVisita(v.ProgramaVisitaID)
+		return v
+	}))(fn)
+}
+
+// RecorrerDudas recorre las dudas en el estado del filtro
+func (r *ExportacionRepository) RecorrerDudas(filtro repositories.FiltroExportacion, fn func([]models.Dudas) error) error {
+	return porLotes(list(r.s, &r.s.dudas, func(d *models.Dudas) bool {
+		switch filtro.EstadoDudas {
+		case repositories.DudasSinResponder:
+			return !respondida(d)
+		case repositories.DudasRespondidas:
+			return respondida(d)
+		case repositories.DudasSinAsignar:
+			return d.AutoridadUTEQID == nil
+		}
+		return true
+	}, r.dudas.withRelations))(fn)
+}
+
+// RecorrerComunicados recorre todos los comunicados
+func (r *ExportacionRepository) RecorrerComunicados(filtro repositories.FiltroExportacion, fn func([]models.Comunicado) error) error {
+	return porLotes(list(r.s, &r.s.comunicados, nil, r.comunicados.withRelations))(fn)
+}
+
+// porLotes entrega los registros a fn en lotes de repositories.LoteExportacion, como FindInBatches
+func porLotes[T any](rows []T, err error) func(fn func([]T) error) error {
+	return func(fn func([]T) error) error {
+		if err != nil {
+			return err
+		}
+		for inicio := 0; inicio < len(rows); inicio += repositories.LoteExportacion {
+			fin := min(inicio+repositories.LoteExportacion, len(rows))
+			if err := fn(rows[inicio:fin]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,63 @@
+package memory
+
+import (
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+)
+
+// InstitucionRepository implementa repositories.InstitucionRepository en memoria
+type InstitucionRepository struct {
+	s *Store
+}
+
+var _ repositories.InstitucionRepository = (*InstitucionRepository)(nil)
+
+func NewInstitucionRepository(s *Store) *InstitucionRepository {
+	return &InstitucionRepository{s: s}
+}
+
+func (r *InstitucionRepository) withRelations(i models.Institucion) models.Institucion {
+	i.Estudiantes = r.s.estudiantes.find(false, func(e *models.Estudiante) bool { return e.InstitucionID == i.ID })
+	i.ProgramasVisita = r.s.programasVisita.find(false, func(p *models.ProgramaVisita) bool { return p.InstitucionID == i.ID })
+	return i
+}
+
+// CreateInstitucion crea una nueva institución
+func (r *InstitucionRepository) CreateInstitucion(institucion *models.Institucion) error {
+	return create(r.s, &r.s.instituciones, institucion)
+}
+
+// GetInstitucionByID obtiene una institución por ID
+func (r *InstitucionRepository) GetInstitucionByID(id uint) (*models.Institucion, error) {
+	return getByID(r.s, &r.s.instituciones, id, r.withRelations)
+}
+
+// GetAllInstituciones obtiene todas las instituciones
+func (r *InstitucionRepository) GetAllInstituciones() ([]models.Institucion, error) {
+	return list(r.s, &r.s.instituciones, nil, r.withRelations)
+}
+
+// UpdateInstitucion actualiza una institución
+func (r *InstitucionRepository) UpdateInstitucion(institucion *models.Institucion) error {
+	return save(r.s, &r.s.instituciones, institucion)
+}
+
+// DeleteInstitucion elimina una institución
+func (r *InstitucionRepository) DeleteInstitucion(id uint) error {
+	return remove(r.s, &r.s.instituciones, byID[models.Institucion](id))
+}
+
+// GetInstitucionesByNombre busca instituciones por nombre
+func (r *InstitucionRepository) GetInstitucionesByNombre(nombre string) ([]models.Institucion, error) {
+	return list(r.s, &r.s.instituciones, func(i *models.Institucion) bool { return containsFold(i.Nombre, nombre) }, r.withRelations)
+}
+
+// GetInstitucionesByAutoridad busca instituciones por autoridad
+func (r *InstitucionRepository) GetInstitucionesByAutoridad(autoridad string) ([]models.Institucion, error) {
+	return list(r.s, &r.s.instituciones, func(i *models.Institucion) bool { return containsFold(i.Autoridad, autoridad) }, r.withRelations)
+}
+
+// ExistsByID verifica si existe una institución con el ID indicado
+func (r *InstitucionRepository) ExistsByID(id uint) (bool, error) {
+	return exists(r.s, &r.s.instituciones, byID[models.Institucion](id))
+}
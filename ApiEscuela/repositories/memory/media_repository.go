@@ -0,0 +1,128 @@
+package memory
+
+import (
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+// MediaRepository implementa repositories.MediaRepository en memoria
+type MediaRepository struct {
+	s *Store
+}
+
+var _ repositories.MediaRepository = (*MediaRepository)(nil)
+
+func NewMediaRepository(s *Store) *MediaRepository {
+	return &MediaRepository{s: s}
+}
+
+func mediaPorClave(clave string) func(*models.Media) bool {
+	return func(m *models.Media) bool { return m.Clave == clave }
+}
+
+func (r *MediaRepository) withRelations(m models.Media) models.Media {
+	m.Referencias = r.s.mediaReferencias.find(false, func(ref *models.MediaReferencia) bool { return ref.MediaID == m.ID })
+	return m
+}
+
+// SaveMedia crea los metadatos de un archivo o reemplaza los existentes con la misma clave.
+// Las referencias no se modifican.
+func (r *MediaRepository) SaveMedia(media *models.Media) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if existente, err := r.s.medias.first(false, mediaPorClave(media.Clave)); err == nil {
+		media.ID = existente.ID
+		media.CreatedAt = existente.CreatedAt
+	}
+	if media.Visibilidad == "" {
+		media.Visibilidad = "publico"
+	}
+	guardado := *media
+	guardado.Variantes = append(models.VariantesMedia(nil), media.Variantes...)
+	guardado.Referencias = nil
+	guardado.Usuario = nil
+	r.s.medias.save(&guardado)
+	media.ID, media.CreatedAt, media.UpdatedAt = guardado.ID, guardado.CreatedAt, guardado.UpdatedAt
+	return nil
+}
+
+// GetMediaByID obtiene un archivo por ID con sus referencias
+func (r *MediaRepository) GetMediaByID(id uint) (*models.Media, error) {
+	return getByID(r.s, &r.s.medias, id, r.withRelations)
+}
+
+// GetMediaByClave obtiene los metadatos de un archivo por su clave
+func (r *MediaRepository) GetMediaByClave(clave string) (*models.Media, error) {
+	return firstWhere(r.s, &r.s.medias, mediaPorClave(clave), r.withRelations)
+}
+
+// GetMediaBySha256 obtiene los archivos con el checksum indicado, del más antiguo al más nuevo
+func (r *MediaRepository) GetMediaBySha256(sha256 string) ([]models.Media, error) {
+	return list(r.s, &r.s.medias, func(m *models.Media) bool { return m.Sha256 == sha256 }, nil)
+}
+
+// SearchMedia lista los archivos que cumplen el filtro, del más nuevo al más antiguo
+func (r *MediaRepository) SearchMedia(filtro repositories.FiltroMedia) ([]models.Media, error) {
+	r.s.mu.RLock()
+	referenciados := map[uint]bool{}
+	for _, ref := range r.s.mediaReferencias.find(false, nil) {
+		referenciados[ref.MediaID] = true
+	}
+	r.s.mu.RUnlock()
+
+	medias, err := list(r.s, &r.s.medias, func(m *models.Media) bool {
+		return (filtro.Texto == "" || containsFold(m.Nombre, filtro.Texto) || containsFold(m.Clave, filtro.Texto)) &&
+			(filtro.Categoria == "" || m.Categoria == filtro.Categoria) &&
+			(filtro.Visibilidad == "" || m.Visibilidad == filtro.Visibilidad) &&
+			(filtro.UsuarioID == 0 || (m.UsuarioID != nil && *m.UsuarioID == filtro.UsuarioID)) &&
+			(!filtro.SinReferencias || !referenciados[m.ID])
+	}, r.withRelations)
+	sort.SliceStable(medias, func(i, j int) bool {
+		if !medias[i].CreatedAt.Equal(medias[j].CreatedAt) {
+			return medias[i].CreatedAt.After(medias[j].CreatedAt)
+		}
+		return medias[i].ID > medias[j].ID
+	})
+	return medias, err
+}
+
+// DeleteMediaByClave elimina definitivamente los metadatos y las referencias para que la clave
+// pueda reutilizarse
+func (r *MediaRepository) DeleteMediaByClave(clave string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	media, err := r.s.medias.first(true, mediaPorClave(clave))
+	if err == gorm.ErrRecordNotFound {
+		return nil
+	}
+	r.s.mediaReferencias.purge(func(ref *models.MediaReferencia) bool { return ref.MediaID == media.ID })
+	r.s.medias.purge(mediaPorClave(clave))
+	return nil
+}
+
+// ReplaceMediaReferencias reemplaza los archivos que usa una noticia o un comunicado
+func (r *MediaRepository) ReplaceMediaReferencias(entidad string, entidadID uint, mediaIDs []uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.mediaReferencias.purge(func(ref *models.MediaReferencia) bool {
+		return ref.Entidad == entidad && ref.EntidadID == entidadID
+	})
+	for _, id := range mediaIDs {
+		r.s.mediaReferencias.insert(&models.MediaReferencia{MediaID: id, Entidad: entidad, EntidadID: entidadID})
+	}
+	return nil
+}
+
+// ReplaceAllMediaReferencias reemplaza todas las referencias, por ejemplo al reconstruirlas
+func (r *MediaRepository) ReplaceAllMediaReferencias(referencias []models.MediaReferencia) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.mediaReferencias.purge(func(*models.MediaReferencia) bool { return true })
+	for i := range referencias {
+		r.s.mediaReferencias.insert(&referencias[i])
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package memory
+
+import (
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+)
+
+// NoticiaRepository implementa repositories.NoticiaRepository en memoria
+type NoticiaRepository struct {
+	s *Store
+}
+
+var _ repositories.NoticiaRepository = (*NoticiaRepository)(nil)
+
+func NewNoticiaRepository(s *Store) *NoticiaRepository {
+	return &NoticiaRepository{s: s}
+}
+
+func (r *NoticiaRepository) withRelations(n models.Noticia) models.Noticia {
+	n.Usuario = r.s.usuario(n.UsuarioID)
+	return n
+}
+
+// CreateNoticia crea una nueva noticia
+func (r *NoticiaRepository) CreateNoticia(noticia *models.Noticia) error {
+	return create(r.s, &r.s.noticias, noticia)
+}
+
+// GetNoticiaByID obtiene una noticia por ID
+func (r *NoticiaRepository) GetNoticiaByID(id uint) (*models.Noticia, error) {
+	return getByID(r.s, &r.s.noticias, id, r.withRelations)
+}
+
+// GetAllNoticias obtiene todas las noticias
+func (r *NoticiaRepository) GetAllNoticias() ([]models.Noticia, error) {
+	return list(r.s, &r.s.noticias, nil, r.withRelations)
+}
+
+// UpdateNoticia actualiza una noticia
+func (r *NoticiaRepository) UpdateNoticia(noticia *models.Noticia) error {
+	return save(r.s, &r.s.noticias, noticia)
+}
+
+// DeleteNoticia elimina una noticia
+func (r *NoticiaRepository) DeleteNoticia(id uint) error {
+	return remove(r.s, &r.s.noticias, byID[models.Noticia](id))
+}
+
+// GetNoticiasByUsuario obtiene noticias por usuario
+func (r *NoticiaRepository) GetNoticiasByUsuario(usuarioID uint) ([]models.Noticia, error) {
+	return list(r.s, &r.s.noticias, func(n *models.Noticia) bool { return n.UsuarioID == usuarioID }, r.withRelations)
+}
+
+// GetNoticiasByTitulo busca noticias por título
+func (r *NoticiaRepository) GetNoticiasByTitulo(titulo string) ([]models.Noticia, error) {
+	return list(r.s, &r.s.noticias, func(n *models.Noticia) bool { return containsFold(n.Titulo, titulo) }, r.withRelations)
+}
+
+// GetNoticiasByDescripcion busca noticias por descripción
+func (r *NoticiaRepository) GetNoticiasByDescripcion(descripcion string) ([]models.Noticia, error) {
+	return list(r.s, &r.s.noticias, func(n *models.Noticia) bool { return containsFold(n.Descripcion, descripcion) }, r.withRelations)
+}
+
+// SearchNoticias busca noticias por las palabras del título o la descripción, sin distinguir tildes
+func (r *NoticiaRepository) SearchNoticias(termino string) ([]repositories.ResultadoBusqueda[models.Noticia], error) {
+	return search(r.s, &r.s.noticias, termino, false, func(n *models.Noticia) []string {
+		return []string{n.Titulo, n.Descripcion}
+	}, r.withRelations)
+}
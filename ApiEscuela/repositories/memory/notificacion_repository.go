@@ -0,0 +1,127 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+)
+
+// NotificacionRepository implementa repositories.NotificacionRepository en memoria
+type NotificacionRepository struct {
+	s *Store
+}
+
+var _ repositories.NotificacionRepository = (*NotificacionRepository)(nil)
+
+func NewNotificacionRepository(s *Store) *NotificacionRepository {
+	return &NotificacionRepository{s: s}
+}
+
+// CreateNotificacion guarda una notificación nueva
+func (r *NotificacionRepository) CreateNotificacion(notificacion *models.Notificacion) error {
+	return create(r.s, &r.s.notificaciones, notificacion)
+}
+
+// CreateNotificaciones guarda varias notificaciones nuevas; completa el ID de cada una
+func (r *NotificacionRepository) CreateNotificaciones(notificaciones []models.Notificacion) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for i := range notificaciones {
+		r.s.notificaciones.insert(&notificaciones[i])
+	}
+	return nil
+}
+
+// GetNotificacionesByUsuario obtiene las notificaciones de un usuario, de la más reciente a la más
+// antigua; limite <= 0 no limita la cantidad
+func (r *NotificacionRepository) GetNotificacionesByUsuario(usuarioID uint, soloNoLeidas bool, limite int) ([]models.Notificacion, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	notificaciones := r.s.notificaciones.find(false, func(n *models.Notificacion) bool {
+		return n.UsuarioID == usuarioID && (!soloNoLeidas || !n.Leida)
+	})
+	sort.SliceStable(notificaciones, func(i, j int) bool {
+		if !notificaciones[i].CreatedAt.Equal(notificaciones[j].CreatedAt) {
+			return notificaciones[i].CreatedAt.After(notificaciones[j].CreatedAt)
+		}
+		return notificaciones[i].ID > notificaciones[j].ID
+	})
+	if limite > 0 && len(notificaciones) > limite {
+		notificaciones = notificaciones[:limite]
+	}
+	return notificaciones, nil
+}
+
+// ContarNoLeidas cuenta las notificaciones sin leer de un usuario
+func (r *NotificacionRepository) ContarNoLeidas(usuarioID uint) (int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	return r.s.notificaciones.count(func(n *models.Notificacion) bool {
+		return n.UsuarioID == usuarioID && !n.Leida
+	}), nil
+}
+
+// MarcarLeida marca como leída una notificación del usuario; si ya estaba leída conserva la fecha
+// de lectura. Devuelve gorm.ErrRecordNotFound si la notificación no es del usuario.
+func (r *NotificacionRepository) MarcarLeida(usuarioID, id uint, fecha time.Time) (*models.Notificacion, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	delUsuario := func(n *models.Notificacion) bool { return n.ID == id && n.UsuarioID == usuarioID }
+	if _, err := r.s.notificaciones.first(false, delUsuario); err != nil {
+		return nil, err
+	}
+	r.s.notificaciones.update(func(n *models.Notificacion) bool { return delUsuario(n) && !n.Leida }, func(n *models.Notificacion) {
+		n.Leida = true
+		n.LeidaEn = &fecha
+	})
+	notificacion, err := r.s.notificaciones.first(false, delUsuario)
+	return &notificacion, err
+}
+
+// MarcarTodasLeidas marca como leídas todas las notificaciones sin leer del usuario
+func (r *NotificacionRepository) MarcarTodasLeidas(usuarioID uint, fecha time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	noLeida := func(n *models.Notificacion) bool { return n.UsuarioID == usuarioID && !n.Leida }
+	total := r.s.notificaciones.count(noLeida)
+	r.s.notificaciones.update(noLeida, func(n *models.Notificacion) {
+		n.Leida = true
+		n.LeidaEn = &fecha
+	})
+	return total, nil
+}
+
+// GetPreferencias obtiene las preferencias de envío guardadas de un usuario
+func (r *NotificacionRepository) GetPreferencias(usuarioID uint) (*models.PreferenciaNotificacion, error) {
+	return firstWhere(r.s, &r.s.preferenciasNotificacion, func(p *models.PreferenciaNotificacion) bool {
+		return p.UsuarioID == usuarioID
+	}, nil)
+}
+
+// GetPreferenciasByUsuarios obtiene las preferencias guardadas de varios usuarios; los usuarios
+// sin preferencias no aparecen
+func (r *NotificacionRepository) GetPreferenciasByUsuarios(usuarioIDs []uint) ([]models.PreferenciaNotificacion, error) {
+	usuarios := make(map[uint]bool, len(usuarioIDs))
+	for _, id := range usuarioIDs {
+		usuarios[id] = true
+	}
+	return list(r.s, &r.s.preferenciasNotificacion, func(p *models.PreferenciaNotificacion) bool {
+		return usuarios[p.UsuarioID]
+	}, nil)
+}
+
+// GuardarPreferencias crea o reemplaza las preferencias de envío del usuario
+func (r *NotificacionRepository) GuardarPreferencias(preferencias *models.PreferenciaNotificacion) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	actual, err := r.s.preferenciasNotificacion.first(false, func(p *models.PreferenciaNotificacion) bool {
+		return p.UsuarioID == preferencias.UsuarioID
+	})
+	if err == nil {
+		preferencias.ID, preferencias.CreatedAt = actual.ID, actual.CreatedAt
+	}
+	r.s.preferenciasNotificacion.save(preferencias)
+	return nil
+}
@@ -0,0 +1,276 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+)
+
+// PapeleraRepository implementa repositories.PapeleraRepository en memoria
+type PapeleraRepository struct {
+	s *Store
+}
+
+var _ repositories.PapeleraRepository = (*PapeleraRepository)(nil)
+
+func NewPapeleraRepository(s *Store) *PapeleraRepository {
+	return &PapeleraRepository{s: s}
+}
+
+// tablaPapelera accede a una tabla del Store sin conocer su tipo. El Store debe estar bloqueado.
+type tablaPapelera interface {
+	// registro devuelve una copia del registro, incluso eliminado
+	registro(id uint) (interface{}, bool)
+	eliminados() []repositories.ElementoPapelera
+	// contar cuenta los registros, incluidos los eliminados, cuyo campo vale valor
+	contar(campo string, valor uint) int64
+	// eliminadoDonde devuelve el ID de un registro eliminado cuyo campo vale valor, o 0
+	eliminadoDonde(campo string, valor uint) uint
+	restaurar(campo string, valor uint)
+	purgar(id uint)
+}
+
+type tablaGenerica[T any] struct {
+	t *table[T]
+}
+
+func (g tablaGenerica[T]) registro(id uint) (interface{}, bool) {
+	row, err := g.t.get(id, true)
+	if err != nil {
+		return nil, false
+	}
+	return &row, true
+}
+
+func (g tablaGenerica[T]) eliminados() []repositories.ElementoPapelera {
+	var elementos []repositories.ElementoPapelera
+	for _, row := range g.t.find(true, isDeleted[T]) {
+		row := row
+		m := modelOf(&row)
+		elementos = append(elementos, repositories.ElementoPapelera{ID: m.ID, EliminadoEn: m.DeletedAt.Time, Registro: &row})
+	}
+	return elementos
+}
+
+func (g tablaGenerica[T]) contar(campo string, valor uint) int64 {
+	return int64(len(g.t.find(true, campoIgual[T](campo, valor))))
+}
+
+func (g tablaGenerica[T]) eliminadoDonde(campo string, valor uint) uint {
+	match := campoIgual[T](campo, valor)
+	row, err := g.t.first(true, func(row *T) bool { return isDeleted(row) && match(row) })
+	if err != nil {
+		return 0
+	}
+	return modelOf(&row).ID
+}
+
+func (g tablaGenerica[T]) restaurar(campo string, valor uint) {
+	g.t.restore(campoIgual[T](campo, valor))
+}
+
+func (g tablaGenerica[T]) purgar(id uint) {
+	g.t.purge(byID[T](id))
+}
+
+// campoIgual construye un filtro por el valor de un campo uint (o *uint)
+func campoIgual[T any](campo string, valor uint) func(*T) bool {
+	return func(row *T) bool { return repositories.ValorCampo(row, campo) == valor }
+}
+
+// tabla devuelve la tabla de la entidad de la papelera
+func (r *PapeleraRepository) tabla(entidad string) (tablaPapelera, error) {
+	if _, err := repositories.BuscarEntidadPapelera(entidad); err != nil {
+		return nil, err
+	}
+	s := r.s
+	switch entidad {
+	case "dudas":
+		return tablaGenerica[models.Dudas]{&s.dudas}, nil
+	case "visita-detalle-estudiantes-universitarios":
+		return tablaGenerica[models.VisitaDetalleEstudiantesUniversitarios]{&s.visitaDetalleEstudiantesUniversitarios}, nil
+	case "visita-detalles":
+		return tablaGenerica[models.VisitaDetalle]{&s.visitaDetalles}, nil
+	case "detalle-autoridad-detalles-visita":
+		return tablaGenerica[models.DetalleAutoridadDetallesVisita]{&s.detallesAutoridad}, nil
+	case "codigos":
+		return tablaGenerica[models.CodigoUsuario]{&s.codigos}, nil
+	case "noticias":
+		return tablaGenerica[models.Noticia]{&s.noticias}, nil
+	case "comunicados":
+		return tablaGenerica[models.Comunicado]{&s.comunicados}, nil
+	case "notificaciones":
+		return tablaGenerica[models.Notificacion]{&s.notificaciones}, nil
+	case "entregas-webhook":
+		return tablaGenerica[models.EntregaWebhook]{&s.entregasWebhook}, nil
+	case "webhooks":
+		return tablaGenerica[models.Webhook]{&s.webhooks}, nil
+	case "estudiantes":
+		return tablaGenerica[models.Estudiante]{&s.estudiantes}, nil
+	case "estudiantes-universitarios":
+		return tablaGenerica[models.EstudianteUniversitario]{&s.estudiantesUniversitarios}, nil
+	case "autoridades-uteq":
+		return tablaGenerica[models.AutoridadUTEQ]{&s.autoridades}, nil
+	case "programas-visita":
+		return tablaGenerica[models.ProgramaVisita]{&s.programasVisita}, nil
+	case "actividades":
+		return tablaGenerica[models.Actividad]{&s.actividades}, nil
+	case "tematicas":
+		return tablaGenerica[models.Tematica]{&s.tematicas}, nil
+	case "usuarios":
+		return tablaGenerica[models.Usuario]{&s.usuarios}, nil
+	case "tipos-usuario":
+		return tablaGenerica[models.TipoUsuario]{&s.tiposUsuario}, nil
+	case "personas":
+		return tablaGenerica[models.Persona]{&s.personas}, nil
+	case "instituciones":
+		return tablaGenerica[models.Institucion]{&s.instituciones}, nil
+	case "ciudades":
+		return tablaGenerica[models.Ciudad]{&s.ciudades}, nil
+	case "provincias":
+		return tablaGenerica[models.Provincia]{&s.provincias}, nil
+	}
+	return nil, repositories.ErrEntidadSinPapelera
+}
+
+// tablaRelacion devuelve la tabla de una entidad de las relaciones, que puede no tener papelera
+func (r *PapeleraRepository) tablaRelacion(entidad string) (tablaPapelera, error) {
+	switch entidad {
+	case "media":
+		return tablaGenerica[models.Media]{&r.s.medias}, nil
+	case "preferencias-notificacion":
+		return tablaGenerica[models.PreferenciaNotificacion]{&r.s.preferenciasNotificacion}, nil
+	}
+	return r.tabla(entidad)
+}
+
+// ContarEliminados cuenta los registros de la entidad que están en la papelera
+func (r *PapeleraRepository) ContarEliminados(entidad string) (int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	t, err := r.tabla(entidad)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(t.eliminados())), nil
+}
+
+// GetEliminados obtiene los registros de la entidad que están en la papelera, los más recientes primero
+func (r *PapeleraRepository) GetEliminados(entidad string) ([]repositories.ElementoPapelera, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	t, err := r.tabla(entidad)
+	if err != nil {
+		return nil, err
+	}
+	elementos := t.eliminados()
+	for i := range elementos {
+		elementos[i].Entidad = entidad
+	}
+	sort.SliceStable(elementos, func(i, j int) bool {
+		if !elementos[i].EliminadoEn.Equal(elementos[j].EliminadoEn) {
+			return elementos[i].EliminadoEn.After(elementos[j].EliminadoEn)
+		}
+		return elementos[i].ID > elementos[j].ID
+	})
+	return elementos, nil
+}
+
+// GetEliminadosAntes obtiene los ID de los registros de la entidad eliminados antes de la fecha
+func (r *PapeleraRepository) GetEliminadosAntes(entidad string, antes time.Time) ([]uint, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	t, err := r.tabla(entidad)
+	if err != nil {
+		return nil, err
+	}
+	var ids []uint
+	for _, e := range t.eliminados() {
+		if e.EliminadoEn.Before(antes) {
+			ids = append(ids, e.ID)
+		}
+	}
+	return ids, nil
+}
+
+// Restaurar restaura un registro de la papelera y, en cascada, los registros de Restaurar.
+// Falla si alguno de los registros de Requiere está en la papelera.
+func (r *PapeleraRepository) Restaurar(entidad string, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	e, _ := repositories.BuscarEntidadPapelera(entidad)
+	t, registro, err := r.eliminado(entidad, id)
+	if err != nil {
+		return err
+	}
+
+	for _, rel := range e.Requiere {
+		valor := repositories.ValorCampo(registro, rel.Campo)
+		if valor == 0 {
+			continue
+		}
+		otra, err := r.tabla(rel.Entidad)
+		if err != nil {
+			return err
+		}
+		if eliminado := otra.eliminadoDonde(rel.Columna, valor); eliminado != 0 {
+			return repositories.ErrorPadreEliminado(rel.Entidad, eliminado)
+		}
+	}
+	for _, rel := range e.Restaurar {
+		valor := repositories.ValorCampo(registro, rel.Campo)
+		if valor == 0 {
+			continue
+		}
+		otra, err := r.tabla(rel.Entidad)
+		if err != nil {
+			return err
+		}
+		otra.restaurar(rel.Columna, valor)
+	}
+	t.restaurar("ID", id)
+	return nil
+}
+
+// Purgar elimina definitivamente un registro de la papelera. Falla si otros registros, incluso
+// eliminados, lo usan según Dependencias.
+func (r *PapeleraRepository) Purgar(entidad string, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	e, _ := repositories.BuscarEntidadPapelera(entidad)
+	t, _, err := r.eliminado(entidad, id)
+	if err != nil {
+		return err
+	}
+
+	cantidades := map[string]int64{}
+	for _, rel := range e.Dependencias {
+		otra, err := r.tablaRelacion(rel.Entidad)
+		if err != nil {
+			return err
+		}
+		if n := otra.contar(rel.Columna, id); n > 0 {
+			cantidades[rel.Entidad] += n
+		}
+	}
+	if len(cantidades) > 0 {
+		return repositories.ErrorDependencias(cantidades)
+	}
+	t.purgar(id)
+	return nil
+}
+
+// eliminado obtiene la tabla y el registro de la papelera; ErrNoEstaEnPapelera si no existe o no está eliminado
+func (r *PapeleraRepository) eliminado(entidad string, id uint) (tablaPapelera, interface{}, error) {
+	t, err := r.tabla(entidad)
+	if err != nil {
+		return nil, nil, err
+	}
+	registro, ok := t.registro(id)
+	if !ok || t.eliminadoDonde("ID", id) == 0 {
+		return nil, nil, repositories.ErrNoEstaEnPapelera
+	}
+	return t, registro, nil
+}
@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+)
+
+// PersonaRepository implementa repositories.PersonaRepository en memoria
+type PersonaRepository struct {
+	s *Store
+}
+
+var _ repositories.PersonaRepository = (*PersonaRepository)(nil)
+
+func NewPersonaRepository(s *Store) *PersonaRepository {
+	return &PersonaRepository{s: s}
+}
+
+// checkUnique emula los índices UNIQUE de cédula y correo (incluyen eliminados)
+func (r *PersonaRepository) checkUnique(persona *models.Persona) error {
+	for _, p := range r.s.personas.find(true, nil) {
+		if p.ID == persona.ID {
+			continue
+		}
+		if p.Cedula == persona.Cedula {
+			return repositories.ErrCedulaDuplicada
+		}
+		if p.Correo != nil && persona.Correo != nil && *p.Correo == *persona.Correo {
+			return repositories.ErrCorreoDuplicado
+		}
+	}
+	return nil
+}
+
+func (r *PersonaRepository) withRelations(p models.Persona) models.Persona {
+	p.Estudiantes = r.s.estudiantes.find(false, func(e *models.Estudiante) bool { return e.PersonaID == p.ID })
+	p.EstudiantesUniv = r.s.estudiantesUniversitarios.find(false, func(e *models.EstudianteUniversitario) bool { return e.PersonaID == p.ID })
+	p.AutoridadesUTEQ = r.s.autoridades.find(false, func(a *models.AutoridadUTEQ) bool { return a.PersonaID == p.ID })
+	p.Usuarios = r.s.usuarios.find(false, func(u *models.Usuario) bool { return u.PersonaID == p.ID })
+	return p
+}
+
+// CreatePersona crea una nueva persona
+func (r *PersonaRepository) CreatePersona(persona *models.Persona) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if err := r.checkUnique(persona); err != nil {
+		return err
+	}
+	r.s.personas.insert(persona)
+	return nil
+}
+
+// GetPersonaByID obtiene una persona por ID
+func (r *PersonaRepository) GetPersonaByID(id uint) (*models.Persona, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	p, err := r.s.personas.get(id, false)
+	if err != nil {
+		return nil, err
+	}
+	p = r.withRelations(p)
+	return &p, nil
+}
+
+// GetPersonaByCedula obtiene una persona por cédula
+func (r *PersonaRepository) GetPersonaByCedula(cedula string) (*models.Persona, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	p, err := r.s.personas.first(false, func(p *models.Persona) bool { return p.Cedula == cedula })
+	if err != nil {
+		return nil, err
+	}
+	p = r.withRelations(p)
+	return &p, nil
+}
+
+// GetAllPersonas obtiene todas las personas
+func (r *PersonaRepository) GetAllPersonas() ([]models.Persona, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	return mapRows(r.s.personas.find(false, nil), r.withRelations), nil
+}
+
+// UpdatePersona actualiza una persona; ErrVersionDesactualizada si cambió desde que se leyó
+func (r *PersonaRepository) UpdatePersona(persona *models.Persona) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if err := r.s.personas.vigente(persona); err != nil {
+		return err
+	}
+	if err := r.checkUnique(persona); err != nil {
+		return err
+	}
+	r.s.personas.save(persona)
+	return nil
+}
+
+// DeletePersona elimina una persona
+func (r *PersonaRepository) DeletePersona(id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.personas.softDelete(byID[models.Persona](id))
+	return nil
+}
+
+// GetPersonasByCorreo busca personas por correo (búsqueda parcial)
+func (r *PersonaRepository) GetPersonasByCorreo(correo string) ([]models.Persona, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	personas := r.s.personas.find(false, func(p *models.Persona) bool {
+		return p.Correo != nil && containsFold(*p.Correo, correo)
+	})
+	return mapRows(personas, r.withRelations), nil
+}
+
+// ExistsByID verifica si existe una persona con el ID indicado
+func (r *PersonaRepository) ExistsByID(id uint) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	_, err := r.s.personas.get(id, false)
+	return err == nil, nil
+}
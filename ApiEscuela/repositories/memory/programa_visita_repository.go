@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+)
+
+// ProgramaVisitaRepository implementa repositories.ProgramaVisitaRepository en memoria
+type ProgramaVisitaRepository struct {
+	s *Store
+}
+
+var _ repositories.ProgramaVisitaRepository = (*ProgramaVisitaRepository)(nil)
+
+func NewProgramaVisitaRepository(s *Store) *ProgramaVisitaRepository {
+	return &ProgramaVisitaRepository{s: s}
+}
+
+func (r *ProgramaVisitaRepository) withRelations(p models.ProgramaVisita) models.ProgramaVisita {
+	p.Institucion = r.s.institucion(p.InstitucionID)
+	return p
+}
+
+// CreateProgramaVisita crea un nuevo programa de visita
+func (r *ProgramaVisitaRepository) CreateProgramaVisita(programa *models.ProgramaVisita) error {
+	return create(r.s, &r.s.programasVisita, programa)
+}
+
+// GetProgramaVisitaByID obtiene un programa de visita por ID
+func (r *ProgramaVisitaRepository) GetProgramaVisitaByID(id uint) (*models.ProgramaVisita, error) {
+	return getByID(r.s, &r.s.programasVisita, id, r.withRelations)
+}
+
+// GetAllProgramasVisita obtiene todos los programas de visita
+func (r *ProgramaVisitaRepository) GetAllProgramasVisita() ([]models.ProgramaVisita, error) {
+	return list(r.s, &r.s.programasVisita, nil, r.withRelations)
+}
+
+// UpdateProgramaVisita actualiza un programa de visita; ErrVersionDesactualizada si cambió desde que se leyó
+func (r *ProgramaVisitaRepository) UpdateProgramaVisita(programa *models.ProgramaVisita) error {
+	return saveVersion(r.s, &r.s.programasVisita, programa)
+}
+
+// DeleteProgramaVisita elimina un programa de visita
+func (r *ProgramaVisitaRepository) DeleteProgramaVisita(id uint) error {
+	return remove(r.s, &r.s.programasVisita, byID[models.ProgramaVisita](id))
+}
+
+// GetProgramasVisitaByFecha obtiene programas por fecha
+func (r *ProgramaVisitaRepository) GetProgramasVisitaByFecha(fecha time.Time) ([]models.ProgramaVisita, error) {
+	startOfDay := time.Date(fecha.Year(), fecha.Month(), fecha.Day(), 0, 0, 0, 0, fecha.Location())
+	endOfDay := startOfDay.Add(24 * time.Hour)
+	return list(r.s, &r.s.programasVisita, func(p *models.ProgramaVisita) bool {
+		return !p.Fecha.Before(startOfDay) && p.Fecha.Before(endOfDay)
+	}, r.withRelations)
+}
+
+// GetProgramasVisitaByInstitucion obtiene programas por institución
+func (r *ProgramaVisitaRepository) GetProgramasVisitaByInstitucion(institucionID uint) ([]models.ProgramaVisita, error) {
+	return list(r.s, &r.s.programasVisita, func(p *models.ProgramaVisita) bool { return p.InstitucionID == institucionID }, r.withRelations)
+}
+
+// GetProgramasVisitaByRangoFecha obtiene programas en un rango de fechas
+func (r *ProgramaVisitaRepository) GetProgramasVisitaByRangoFecha(fechaInicio, fechaFin time.Time) ([]models.ProgramaVisita, error) {
+	return list(r.s, &r.s.programasVisita, func(p *models.ProgramaVisita) bool {
+		return !p.Fecha.Before(fechaInicio) && !p.Fecha.After(fechaFin)
+	}, r.withRelations)
+}
+
+// GetProgramasSinRecordatorio obtiene los programas que empiezan en el rango y aún no tienen recordatorio enviado
+func (r *ProgramaVisitaRepository) GetProgramasSinRecordatorio(desde, hasta time.Time) ([]models.ProgramaVisita, error) {
+	programas, err := list(r.s, &r.s.programasVisita, func(p *models.ProgramaVisita) bool {
+		return p.RecordatorioEnviadoEn == nil && !p.Fecha.Before(desde) && !p.Fecha.After(hasta)
+	}, r.withRelations)
+	sort.SliceStable(programas, func(i, j int) bool { return programas[i].Fecha.Before(programas[j].Fecha) })
+	return programas, err
+}
+
+// MarcarRecordatorioEnviado registra que ya se envió el recordatorio del programa
+func (r *ProgramaVisitaRepository) MarcarRecordatorioEnviado(id uint, enviado time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.programasVisita.update(byID[models.ProgramaVisita](id), func(p *models.ProgramaVisita) {
+		p.RecordatorioEnviadoEn = &enviado
+	})
+	return nil
+}
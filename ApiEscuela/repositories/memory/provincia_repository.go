@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+)
+
+// ProvinciaRepository implementa repositories.ProvinciaRepository en memoria
+type ProvinciaRepository struct {
+	s *Store
+}
+
+var _ repositories.ProvinciaRepository = (*ProvinciaRepository)(nil)
+
+func NewProvinciaRepository(s *Store) *ProvinciaRepository {
+	return &ProvinciaRepository{s: s}
+}
+
+func (r *ProvinciaRepository) withRelations(p models.Provincia) models.Provincia {
+	p.Ciudades = r.s.ciudades.find(false, func(c *models.Ciudad) bool { return c.ProvinciaID == p.ID })
+	return p
+}
+
+// CreateProvincia crea una nueva provincia
+func (r *ProvinciaRepository) CreateProvincia(provincia *models.Provincia) error {
+	return create(r.s, &r.s.provincias, provincia)
+}
+
+// GetProvinciaByID obtiene una provincia por ID
+func (r *ProvinciaRepository) GetProvinciaByID(id uint) (*models.Provincia, error) {
+	return getByID(r.s, &r.s.provincias, id, r.withRelations)
+}
+
+// GetAllProvincias obtiene todas las provincias
+func (r *ProvinciaRepository) GetAllProvincias() ([]models.Provincia, error) {
+	return list(r.s, &r.s.provincias, nil, r.withRelations)
+}
+
+// UpdateProvincia actualiza una provincia
+func (r *ProvinciaRepository) UpdateProvincia(provincia *models.Provincia) error {
+	return save(r.s, &r.s.provincias, provincia)
+}
+
+// DeleteProvincia elimina una provincia
+func (r *ProvinciaRepository) DeleteProvincia(id uint) error {
+	return remove(r.s, &r.s.provincias, byID[models.Provincia](id))
+}
+
+// GetProvinciaByNombre busca provincia por nombre
+func (r *ProvinciaRepository) GetProvinciaByNombre(nombre string) (*models.Provincia, error) {
+	return firstWhere(r.s, &r.s.provincias, func(p *models.Provincia) bool {
+		return containsFold(p.Provincia, nombre)
+	}, r.withRelations)
+}
@@ -0,0 +1,117 @@
+package memory
+
+import "ApiEscuela/models"
+
+// Las funciones de este archivo emulan los Preload de GORM: devuelven la relación activa
+// o el valor cero si no existe o fue eliminada. El Store debe estar bloqueado al usarlas.
+
+func (s *Store) persona(id uint) models.Persona {
+	p, _ := s.personas.get(id, false)
+	return p
+}
+
+func (s *Store) tipoUsuario(id uint) models.TipoUsuario {
+	t, _ := s.tiposUsuario.get(id, false)
+	return t
+}
+
+func (s *Store) usuario(id uint) models.Usuario {
+	u, err := s.usuarios.get(id, false)
+	if err == nil {
+		u.Persona = s.persona(u.PersonaID)
+	}
+	return u
+}
+
+func (s *Store) provincia(id uint) models.Provincia {
+	p, _ := s.provincias.get(id, false)
+	return p
+}
+
+func (s *Store) ciudad(id uint) models.Ciudad {
+	c, err := s.ciudades.get(id, false)
+	if err == nil {
+		c.Provincia = s.provincia(c.ProvinciaID)
+	}
+	return c
+}
+
+func (s *Store) institucion(id uint) models.Institucion {
+	i, _ := s.instituciones.get(id, false)
+	return i
+}
+
+func (s *Store) tematica(id uint) models.Tematica {
+	t, _ := s.tematicas.get(id, false)
+	return t
+}
+
+func (s *Store) actividad(id uint) models.Actividad {
+	a, err := s.actividades.get(id, false)
+	if err == nil {
+		a.Tematica = s.tematica(a.TematicaID)
+	}
+	return a
+}
+
+func (s *Store) programaVisita(id uint) models.ProgramaVisita {
+	p, err := s.programasVisita.get(id, false)
+	if err == nil {
+		p.Institucion = s.institucion(p.InstitucionID)
+	}
+	return p
+}
+
+func (s *Store) estudiante(id uint) models.Estudiante {
+	e, err := s.estudiantes.get(id, false)
+	if err == nil {
+		e.Persona = s.persona(e.PersonaID)
+	}
+	return e
+}
+
+func (s *Store) estudianteUniversitario(id uint) models.EstudianteUniversitario {
+	e, err := s.estudiantesUniversitarios.get(id, false)
+	if err == nil {
+		e.Persona = s.persona(e.PersonaID)
+	}
+	return e
+}
+
+func (s *Store) autoridad(id uint) models.AutoridadUTEQ {
+	a, err := s.autoridades.get(id, false)
+	if err == nil {
+		a.Persona = s.persona(a.PersonaID)
+	}
+	return a
+}
+
+// one convierte el resultado de una búsqueda en un puntero, como First
+func one[T any](row T, err error) (*T, error) {
+	if err != nil {
+		return nil, err
+	}
+	return &row, nil
+}
+
+// mapRows aplica fn a cada registro (para completar relaciones)
+func mapRows[T any](rows []T, fn func(T) T) []T {
+	for i := range rows {
+		rows[i] = fn(rows[i])
+	}
+	return rows
+}
+
+// estadisticas cuenta registros, valores distintos de dos columnas y el promedio por la segunda
+func estadisticas[T any](rows []T, col1, col2 func(T) uint) (total, distintos1, distintos2 int64, promedio float64) {
+	uno, dos := map[uint]bool{}, map[uint]bool{}
+	for _, row := range rows {
+		uno[col1(row)] = true
+		dos[col2(row)] = true
+	}
+	total, distintos1, distintos2 = int64(len(rows)), int64(len(uno)), int64(len(dos))
+	if distintos2 > 0 {
+		promedio = float64(total) / float64(distintos2)
+	}
+	return
+}
@@ -0,0 +1,354 @@
+// Package memory implementa en memoria las interfaces de ApiEscuela/repositories.
+//
+// Los repositorios creados a partir del mismo Store comparten los datos, de modo que las
+// relaciones que la implementación con GORM resuelve con Preload (la Persona de un Estudiante,
+// los Usuarios de una Persona, etc.) también se resuelven aquí. Las restricciones UNIQUE
+// se emulan devolviendo los mismos errores que los repositorios reales; las claves foráneas
+// no se validan.
+package memory
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+
+	"gorm.io/gorm"
+)
+
+// Store contiene las tablas en memoria compartidas por los repositorios
+type Store struct {
+	mu sync.RWMutex
+
+	provincias                             table[models.Provincia]
+	ciudades                               table[models.Ciudad]
+	personas                               table[models.Persona]
+	tiposUsuario                           table[models.TipoUsuario]
+	usuarios                               table[models.Usuario]
+	instituciones                          table[models.Institucion]
+	estudiantes                            table[models.Estudiante]
+	estudiantesUniversitarios              table[models.EstudianteUniversitario]
+	autoridades                            table[models.AutoridadUTEQ]
+	tematicas                              table[models.Tematica]
+	actividades                            table[models.Actividad]
+	programasVisita                        table[models.ProgramaVisita]
+	detallesAutoridad                      table[models.DetalleAutoridadDetallesVisita]
+	visitaDetalles                         table[models.VisitaDetalle]
+	dudas                                  table[models.Dudas]
+	visitaDetalleEstudiantesUniversitarios table[models.VisitaDetalleEstudiantesUniversitarios]
+	codigos                                table[models.CodigoUsuario]
+	noticias                               table[models.Noticia]
+	comunicados                            table[models.Comunicado]
+	medias                                 table[models.Media]
+	mediaReferencias                       table[models.MediaReferencia]
+	ejecucionesTarea                       table[models.EjecucionTarea]
+	notificaciones                         table[models.Notificacion]
+	preferenciasNotificacion               table[models.PreferenciaNotificacion]
+	webhooks                               table[models.Webhook]
+	entregasWebhook                        table[models.EntregaWebhook]
+}
+
+// NewStore crea un almacén vacío
+func NewStore() *Store {
+	return &Store{}
+}
+
+// Repositories agrupa un repositorio en memoria de cada tipo sobre el mismo Store
+type Repositories struct {
+	Store                                  *Store
+	Provincia                              *ProvinciaRepository
+	Ciudad                                 *CiudadRepository
+	Persona                                *PersonaRepository
+	TipoUsuario                            *TipoUsuarioRepository
+	Usuario                                *UsuarioRepository
+	Institucion                            *InstitucionRepository
+	Estudiante                             *EstudianteRepository
+	EstudianteUniversitario                *EstudianteUniversitarioRepository
+	AutoridadUTEQ                          *AutoridadUTEQRepository
+	Tematica                               *TematicaRepository
+	Actividad                              *ActividadRepository
+	ProgramaVisita                         *ProgramaVisitaRepository
+	DetalleAutoridadDetallesVisita         *DetalleAutoridadDetallesVisitaRepository
+	VisitaDetalle                          *VisitaDetalleRepository
+	Dudas                                  *DudasRepository
+	VisitaDetalleEstudiantesUniversitarios *VisitaDetalleEstudiantesUniversitariosRepository
+	CodigoUsuario                          *CodigoUsuarioRepository
+	Noticia                                *NoticiaRepository
+	Comunicado                             *ComunicadoRepository
+	Media                                  *MediaRepository
+	EjecucionTarea                         *EjecucionTareaRepository
+	Notificacion                           *NotificacionRepository
+	Webhook                                *WebhookRepository
+	Busqueda                               *BusquedaRepository
+	Exportacion                            *ExportacionRepository
+	Analitica                              *AnaliticaRepository
+	Papelera                               *PapeleraRepository
+}
+
+// NewRepositories crea todos los repositorios en memoria sobre un Store nuevo
+func NewRepositories() *Repositories {
+	s := NewStore()
+	return &Repositories{
+		Store:                                  s,
+		Provincia:                              NewProvinciaRepository(s),
+		Ciudad:                                 NewCiudadRepository(s),
+		Persona:                                NewPersonaRepository(s),
+		TipoUsuario:                            NewTipoUsuarioRepository(s),
+		Usuario:                                NewUsuarioRepository(s),
+		Institucion:                            NewInstitucionRepository(s),
+		Estudiante:                             NewEstudianteRepository(s),
+		EstudianteUniversitario:                NewEstudianteUniversitarioRepository(s),
+		AutoridadUTEQ:                          NewAutoridadUTEQRepository(s),
+		Tematica:                               NewTematicaRepository(s),
+		Actividad:                              NewActividadRepository(s),
+		ProgramaVisita:                         NewProgramaVisitaRepository(s),
+		DetalleAutoridadDetallesVisita:         NewDetalleAutoridadDetallesVisitaRepository(s),
+		VisitaDetalle:                          NewVisitaDetalleRepository(s),
+		Dudas:                                  NewDudasRepository(s),
+		VisitaDetalleEstudiantesUniversitarios: NewVisitaDetalleEstudiantesUniversitariosRepository(s),
+		CodigoUsuario:                          NewCodigoUsuarioRepository(s),
+		Noticia:                                NewNoticiaRepository(s),
+		Comunicado:                             NewComunicadoRepository(s),
+		Media:                                  NewMediaRepository(s),
+		EjecucionTarea:                         NewEjecucionTareaRepository(s),
+		Notificacion:                           NewNotificacionRepository(s),
+		Webhook:                                NewWebhookRepository(s),
+		Busqueda:                               NewBusquedaRepository(s),
+		Exportacion:                            NewExportacionRepository(s),
+		Analitica:                              NewAnaliticaRepository(s),
+		Papelera:                               NewPapeleraRepository(s),
+	}
+}
+
+// table es una tabla en memoria indexada por ID. El Store debe estar bloqueado al usarla.
+type table[T any] struct {
+	rows   map[uint]*T
+	nextID uint
+}
+
+// modelOf devuelve el gorm.Model embebido en un registro
+func modelOf[T any](row *T) *gorm.Model {
+	return reflect.ValueOf(row).Elem().FieldByName("Model").Addr().Interface().(*gorm.Model)
+}
+
+func isDeleted[T any](row *T) bool {
+	return modelOf(row).DeletedAt.Valid
+}
+
+// insert guarda una copia del registro asignando ID y fechas, como lo hace Create
+func (t *table[T]) insert(row *T) {
+	if t.rows == nil {
+		t.rows = map[uint]*T{}
+	}
+	m := modelOf(row)
+	if m.ID == 0 {
+		t.nextID++
+		m.ID = t.nextID
+	} else if m.ID > t.nextID {
+		t.nextID = m.ID
+	}
+	now := time.Now()
+	if m.CreatedAt.IsZero() {
+		m.CreatedAt = now
+	}
+	m.UpdatedAt = now
+	stored := *row
+	t.rows[m.ID] = &stored
+}
+
+// save reemplaza el registro completo o lo inserta si no existe, como lo hace Save
+func (t *table[T]) save(row *T) {
+	m := modelOf(row)
+	if existing, ok := t.rows[m.ID]; ok && m.ID != 0 {
+		if m.CreatedAt.IsZero() {
+			m.CreatedAt = modelOf(existing).CreatedAt
+		}
+		m.UpdatedAt = time.Now()
+		stored := *row
+		t.rows[m.ID] = &stored
+		return
+	}
+	t.insert(row)
+}
+
+// vigente comprueba que el registro guardado exista y que su UpdatedAt sea el de row, es decir,
+// que nadie lo modificó desde que se leyó; un UpdatedAt vacío no se comprueba
+func (t *table[T]) vigente(row *T) error {
+	m := modelOf(row)
+	existing, ok := t.rows[m.ID]
+	if !ok || isDeleted(existing) {
+		return gorm.ErrRecordNotFound
+	}
+	if !m.UpdatedAt.IsZero() && !modelOf(existing).UpdatedAt.Equal(m.UpdatedAt) {
+		return repositories.ErrVersionDesactualizada
+	}
+	return nil
+}
+
+// get obtiene una copia del registro; unscoped incluye los eliminados lógicamente
+func (t *table[T]) get(id uint, unscoped bool) (T, error) {
+	row, ok := t.rows[id]
+	if !ok || (!unscoped && isDeleted(row)) {
+		var zero T
+		return zero, gorm.ErrRecordNotFound
+	}
+	return *row, nil
+}
+
+// find devuelve copias de los registros que cumplen el filtro, ordenados por ID
+func (t *table[T]) find(unscoped bool, match func(*T) bool) []T {
+	ids := make([]uint, 0, len(t.rows))
+	for id := range t.rows {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	result := []T{}
+	for _, id := range ids {
+		row := t.rows[id]
+		if !unscoped && isDeleted(row) {
+			continue
+		}
+		if match == nil || match(row) {
+			result = append(result, *row)
+		}
+	}
+	return result
+}
+
+// first devuelve el primer registro que cumple el filtro
+func (t *table[T]) first(unscoped bool, match func(*T) bool) (T, error) {
+	rows := t.find(unscoped, match)
+	if len(rows) == 0 {
+		var zero T
+		return zero, gorm.ErrRecordNotFound
+	}
+	return rows[0], nil
+}
+
+// update modifica en el lugar los registros activos que cumplen el filtro
+func (t *table[T]) update(match func(*T) bool, apply func(*T)) {
+	for _, row := range t.rows {
+		if !isDeleted(row) && match(row) {
+			apply(row)
+			modelOf(row).UpdatedAt = time.Now()
+		}
+	}
+}
+
+// softDelete marca como eliminados los registros activos que cumplen el filtro
+func (t *table[T]) softDelete(match func(*T) bool) {
+	now := time.Now()
+	for _, row := range t.rows {
+		if !isDeleted(row) && match(row) {
+			modelOf(row).DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+		}
+	}
+}
+
+// restore quita la marca de eliminación de los registros que cumplen el filtro
+func (t *table[T]) restore(match func(*T) bool) {
+	for _, row := range t.rows {
+		if match(row) {
+			modelOf(row).DeletedAt = gorm.DeletedAt{}
+		}
+	}
+}
+
+// purge elimina definitivamente los registros que cumplen el filtro, como Unscoped().Delete
+func (t *table[T]) purge(match func(*T) bool) int64 {
+	var n int64
+	for id, row := range t.rows {
+		if match(row) {
+			delete(t.rows, id)
+			n++
+		}
+	}
+	return n
+}
+
+// count cuenta los registros activos que cumplen el filtro
+func (t *table[T]) count(match func(*T) bool) int64 {
+	return int64(len(t.find(false, match)))
+}
+
+// byID construye un filtro por ID
+func byID[T any](id uint) func(*T) bool {
+	return func(row *T) bool { return modelOf(row).ID == id }
+}
+
+// containsFold emula ILIKE '%termino%'
+func containsFold(value, term string) bool {
+	return strings.Contains(strings.ToLower(value), strings.ToLower(term))
+}
+
+// Operaciones comunes de los repositorios: bloquean el Store y completan las relaciones con rel.
+
+func create[T any](s *Store, t *table[T], row *T) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t.insert(row)
+	return nil
+}
+
+func save[T any](s *Store, t *table[T], row *T) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t.save(row)
+	return nil
+}
+
+// saveVersion guarda el registro como save, con bloqueo optimista según su UpdatedAt
+func saveVersion[T any](s *Store, t *table[T], row *T) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := t.vigente(row); err != nil {
+		return err
+	}
+	t.save(row)
+	return nil
+}
+
+func remove[T any](s *Store, t *table[T], match func(*T) bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t.softDelete(match)
+	return nil
+}
+
+func getByID[T any](s *Store, t *table[T], id uint, rel func(T) T) (*T, error) {
+	return firstWhere(s, t, byID[T](id), rel)
+}
+
+func firstWhere[T any](s *Store, t *table[T], match func(*T) bool, rel func(T) T) (*T, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	row, err := t.first(false, match)
+	if err != nil {
+		return nil, err
+	}
+	if rel != nil {
+		row = rel(row)
+	}
+	return &row, nil
+}
+
+func list[T any](s *Store, t *table[T], match func(*T) bool, rel func(T) T) ([]T, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	rows := t.find(false, match)
+	if rel != nil {
+		rows = mapRows(rows, rel)
+	}
+	return rows, nil
+}
+
+func exists[T any](s *Store, t *table[T], match func(*T) bool) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return t.count(match) > 0, nil
+}
@@ -0,0 +1,138 @@
+package memory
+
+import (
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+)
+
+// EstudianteRepository implementa repositories.EstudianteRepository en memoria
+type EstudianteRepository struct {
+	s *Store
+}
+
+var _ repositories.EstudianteRepository = (*EstudianteRepository)(nil)
+
+func NewEstudianteRepository(s *Store) *EstudianteRepository {
+	return &EstudianteRepository{s: s}
+}
+
+func (r *EstudianteRepository) withRelations(e models.Estudiante) models.Estudiante {
+	e.Persona = r.s.persona(e.PersonaID)
+	e.Institucion = r.s.institucion(e.InstitucionID)
+	e.Ciudad = r.s.ciudad(e.CiudadID)
+	return e
+}
+
+// checkPersona evita dos estudiantes activos para la misma persona
+func (r *EstudianteRepository) checkPersona(estudiante *models.Estudiante) error {
+	n := r.s.estudiantes.count(func(e *models.Estudiante) bool {
+		return e.PersonaID == estudiante.PersonaID && e.ID != estudiante.ID
+	})
+	if n > 0 {
+		return repositories.ErrEstudianteDuplicado
+	}
+	return nil
+}
+
+func (r *EstudianteRepository) find(unscoped bool, match func(*models.Estudiante) bool) []models.Estudiante {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	return mapRows(r.s.estudiantes.find(unscoped, match), r.withRelations)
+}
+
+// CreateEstudiante crea un nuevo estudiante
+func (r *EstudianteRepository) CreateEstudiante(estudiante *models.Estudiante) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if err := r.checkPersona(estudiante); err != nil {
+		return err
+	}
+	r.s.estudiantes.insert(estudiante)
+	return nil
+}
+
+// GetEstudianteByID obtiene un estudiante por ID
+func (r *EstudianteRepository) GetEstudianteByID(id uint) (*models.Estudiante, error) {
+	return getByID(r.s, &r.s.estudiantes, id, func(e models.Estudiante) models.Estudiante {
+		e = r.withRelations(e)
+		e.Dudas = r.s.dudas.find(false, func(d *models.Dudas) bool { return d.EstudianteID == e.ID })
+		return e
+	})
+}
+
+// GetAllEstudiantes obtiene todos los estudiantes
+func (r *EstudianteRepository) GetAllEstudiantes() ([]models.Estudiante, error) {
+	return r.find(false, nil), nil
+}
+
+// UpdateEstudiante actualiza un estudiante
+func (r *EstudianteRepository) UpdateEstudiante(estudiante *models.Estudiante) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if err := r.checkPersona(estudiante); err != nil {
+		return err
+	}
+	r.s.estudiantes.save(estudiante)
+	return nil
+}
+
+// DeleteEstudiante elimina un estudiante y en cascada su usuario y persona
+func (r *EstudianteRepository) DeleteEstudiante(id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	estudiante, err := r.s.estudiantes.get(id, false)
+	if err != nil {
+		return err
+	}
+	r.s.estudiantes.softDelete(byID[models.Estudiante](id))
+	r.s.usuarios.softDelete(func(u *models.Usuario) bool { return u.PersonaID == estudiante.PersonaID })
+	r.s.personas.softDelete(byID[models.Persona](estudiante.PersonaID))
+	return nil
+}
+
+// RestoreEstudiante restaura un estudiante eliminado y en cascada su usuario y persona
+func (r *EstudianteRepository) RestoreEstudiante(id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	estudiante, err := r.s.estudiantes.get(id, true)
+	if err != nil {
+		return err
+	}
+	r.s.personas.restore(byID[models.Persona](estudiante.PersonaID))
+	r.s.usuarios.restore(func(u *models.Usuario) bool { return u.PersonaID == estudiante.PersonaID })
+	r.s.estudiantes.restore(byID[models.Estudiante](id))
+	return nil
+}
+
+// GetAllEstudiantesIncludingDeleted obtiene todos los estudiantes incluyendo los eliminados
+func (r *EstudianteRepository) GetAllEstudiantesIncludingDeleted() ([]models.Estudiante, error) {
+	return r.find(true, nil), nil
+}
+
+// GetDeletedEstudiantes obtiene solo los estudiantes eliminados
+func (r *EstudianteRepository) GetDeletedEstudiantes() ([]models.Estudiante, error) {
+	return r.find(true, isDeleted[models.Estudiante]), nil
+}
+
+// GetEstudiantesByCity obtiene estudiantes por ciudad
+func (r *EstudianteRepository) GetEstudiantesByCity(ciudadID uint) ([]models.Estudiante, error) {
+	return r.find(false, func(e *models.Estudiante) bool { return e.CiudadID == ciudadID }), nil
+}
+
+// GetEstudiantesByInstitucion obtiene estudiantes por institución
+func (r *EstudianteRepository) GetEstudiantesByInstitucion(institucionID uint) ([]models.Estudiante, error) {
+	return r.find(false, func(e *models.Estudiante) bool { return e.InstitucionID == institucionID }), nil
+}
+
+// GetEstudiantesByEspecialidad obtiene estudiantes por especialidad
+func (r *EstudianteRepository) GetEstudiantesByEspecialidad(especialidad string) ([]models.Estudiante, error) {
+	return r.find(false, func(e *models.Estudiante) bool { return containsFold(e.Especialidad, especialidad) }), nil
+}
+
+// ExistsByID verifica si existe un estudiante con el ID indicado
+func (r *EstudianteRepository) ExistsByID(id uint) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	_, err := r.s.estudiantes.get(id, false)
+	return err == nil, nil
+}
@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+)
+
+// TematicaRepository implementa repositories.TematicaRepository en memoria
+type TematicaRepository struct {
+	s *Store
+}
+
+var _ repositories.TematicaRepository = (*TematicaRepository)(nil)
+
+func NewTematicaRepository(s *Store) *TematicaRepository {
+	return &TematicaRepository{s: s}
+}
+
+func (r *TematicaRepository) withRelations(t models.Tematica) models.Tematica {
+	t.Actividades = r.s.actividades.find(false, func(a *models.Actividad) bool { return a.TematicaID == t.ID })
+	return t
+}
+
+// CreateTematica crea una nueva temática
+func (r *TematicaRepository) CreateTematica(tematica *models.Tematica) error {
+	return create(r.s, &r.s.tematicas, tematica)
+}
+
+// GetTematicaByID obtiene una temática por ID
+func (r *TematicaRepository) GetTematicaByID(id uint) (*models.Tematica, error) {
+	return getByID(r.s, &r.s.tematicas, id, r.withRelations)
+}
+
+// GetAllTematicas obtiene todas las temáticas
+func (r *TematicaRepository) GetAllTematicas() ([]models.Tematica, error) {
+	return list(r.s, &r.s.tematicas, nil, r.withRelations)
+}
+
+// UpdateTematica actualiza una temática
+func (r *TematicaRepository) UpdateTematica(tematica *models.Tematica) error {
+	return save(r.s, &r.s.tematicas, tematica)
+}
+
+// DeleteTematica elimina una temática
+func (r *TematicaRepository) DeleteTematica(id uint) error {
+	return remove(r.s, &r.s.tematicas, byID[models.Tematica](id))
+}
+
+// GetTematicasByNombre busca temáticas por las palabras del nombre, sin distinguir tildes
+func (r *TematicaRepository) GetTematicasByNombre(nombre string) ([]repositories.ResultadoBusqueda[models.Tematica], error) {
+	return search(r.s, &r.s.tematicas, nombre, true, func(t *models.Tematica) []string {
+		return []string{t.Nombre, t.Descripcion}
+	}, r.withRelations)
+}
+
+// GetTematicasByDescripcion busca temáticas por descripción
+func (r *TematicaRepository) GetTematicasByDescripcion(descripcion string) ([]models.Tematica, error) {
+	return list(r.s, &r.s.tematicas, func(t *models.Tematica) bool { return containsFold(t.Descripcion, descripcion) }, r.withRelations)
+}
+
+// ExistsByID verifica si existe una temática con el ID indicado
+func (r *TematicaRepository) ExistsByID(id uint) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	_, err := r.s.tematicas.get(id, false)
+	return err == nil, nil
+}
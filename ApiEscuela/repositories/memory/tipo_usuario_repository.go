@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+)
+
+// TipoUsuarioRepository implementa repositories.TipoUsuarioRepository en memoria
+type TipoUsuarioRepository struct {
+	s *Store
+}
+
+var _ repositories.TipoUsuarioRepository = (*TipoUsuarioRepository)(nil)
+
+func NewTipoUsuarioRepository(s *Store) *TipoUsuarioRepository {
+	return &TipoUsuarioRepository{s: s}
+}
+
+func (r *TipoUsuarioRepository) withRelations(t models.TipoUsuario) models.TipoUsuario {
+	t.Usuarios = r.s.usuarios.find(false, func(u *models.Usuario) bool { return u.TipoUsuarioID == t.ID })
+	return t
+}
+
+// CreateTipoUsuario crea un nuevo tipo de usuario
+func (r *TipoUsuarioRepository) CreateTipoUsuario(tipoUsuario *models.TipoUsuario) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.tiposUsuario.insert(tipoUsuario)
+	return nil
+}
+
+// GetTipoUsuarioByID obtiene un tipo de usuario por ID
+func (r *TipoUsuarioRepository) GetTipoUsuarioByID(id uint) (*models.TipoUsuario, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	t, err := r.s.tiposUsuario.get(id, false)
+	if err != nil {
+		return nil, err
+	}
+	t = r.withRelations(t)
+	return &t, nil
+}
+
+// GetAllTiposUsuario obtiene todos los tipos de usuario
+func (r *TipoUsuarioRepository) GetAllTiposUsuario() ([]models.TipoUsuario, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	return mapRows(r.s.tiposUsuario.find(false, nil), r.withRelations), nil
+}
+
+// UpdateTipoUsuario actualiza un tipo de usuario
+func (r *TipoUsuarioRepository) UpdateTipoUsuario(tipoUsuario *models.TipoUsuario) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.tiposUsuario.save(tipoUsuario)
+	return nil
+}
+
+// DeleteTipoUsuario elimina un tipo de usuario
+func (r *TipoUsuarioRepository) DeleteTipoUsuario(id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.tiposUsuario.softDelete(byID[models.TipoUsuario](id))
+	return nil
+}
+
+// GetTipoUsuarioByNombre busca tipo de usuario por nombre
+func (r *TipoUsuarioRepository) GetTipoUsuarioByNombre(nombre string) (*models.TipoUsuario, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	t, err := r.s.tiposUsuario.first(false, func(t *models.TipoUsuario) bool { return containsFold(t.Nombre, nombre) })
+	if err != nil {
+		return nil, err
+	}
+	t = r.withRelations(t)
+	return &t, nil
+}
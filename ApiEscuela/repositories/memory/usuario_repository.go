@@ -0,0 +1,166 @@
+package memory
+
+import (
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+)
+
+// UsuarioRepository implementa repositories.UsuarioRepository en memoria
+type UsuarioRepository struct {
+	s *Store
+}
+
+var _ repositories.UsuarioRepository = (*UsuarioRepository)(nil)
+
+func NewUsuarioRepository(s *Store) *UsuarioRepository {
+	return &UsuarioRepository{s: s}
+}
+
+// checkUnique emula el índice UNIQUE del nombre de usuario (incluye eliminados)
+func (r *UsuarioRepository) checkUnique(usuario *models.Usuario) error {
+	_, err := r.s.usuarios.first(true, func(u *models.Usuario) bool {
+		return u.ID != usuario.ID && u.Usuario == usuario.Usuario
+	})
+	if err == nil {
+		return repositories.ErrUsuarioDuplicado
+	}
+	return nil
+}
+
+func (r *UsuarioRepository) withRelations(u models.Usuario) models.Usuario {
+	u.Persona = r.s.persona(u.PersonaID)
+	u.TipoUsuario = r.s.tipoUsuario(u.TipoUsuarioID)
+	return u
+}
+
+func (r *UsuarioRepository) first(unscoped bool, match func(*models.Usuario) bool) (*models.Usuario, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	u, err := r.s.usuarios.first(unscoped, match)
+	if err != nil {
+		return nil, err
+	}
+	u = r.withRelations(u)
+	return &u, nil
+}
+
+func (r *UsuarioRepository) find(unscoped bool, match func(*models.Usuario) bool) []models.Usuario {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	return mapRows(r.s.usuarios.find(unscoped, match), r.withRelations)
+}
+
+// CreateUsuario crea un nuevo usuario
+func (r *UsuarioRepository) CreateUsuario(usuario *models.Usuario) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if err := r.checkUnique(usuario); err != nil {
+		return err
+	}
+	r.s.usuarios.insert(usuario)
+	return nil
+}
+
+// GetUsuarioByID obtiene un usuario por ID
+func (r *UsuarioRepository) GetUsuarioByID(id uint) (*models.Usuario, error) {
+	return r.first(false, byID[models.Usuario](id))
+}
+
+// GetAllUsuarios obtiene todos los usuarios
+func (r *UsuarioRepository) GetAllUsuarios() ([]models.Usuario, error) {
+	return r.find(false, nil), nil
+}
+
+// UpdateUsuario actualiza un usuario
+func (r *UsuarioRepository) UpdateUsuario(usuario *models.Usuario) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if err := r.checkUnique(usuario); err != nil {
+		return err
+	}
+	r.s.usuarios.save(usuario)
+	return nil
+}
+
+// DeleteUsuario elimina un usuario
+func (r *UsuarioRepository) DeleteUsuario(id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.usuarios.softDelete(byID[models.Usuario](id))
+	return nil
+}
+
+// GetUsuarioByUsername busca usuario por nombre de usuario (solo activos)
+func (r *UsuarioRepository) GetUsuarioByUsername(username string) (*models.Usuario, error) {
+	return r.first(false, func(u *models.Usuario) bool { return u.Usuario == username })
+}
+
+// GetUsuariosByTipo obtiene usuarios por tipo
+func (r *UsuarioRepository) GetUsuariosByTipo(tipoUsuarioID uint) ([]models.Usuario, error) {
+	return r.find(false, func(u *models.Usuario) bool { return u.TipoUsuarioID == tipoUsuarioID }), nil
+}
+
+// GetUsuariosByPersona obtiene usuarios por persona
+func (r *UsuarioRepository) GetUsuariosByPersona(personaID uint) ([]models.Usuario, error) {
+	return r.find(false, func(u *models.Usuario) bool { return u.PersonaID == personaID }), nil
+}
+
+// GetUsuariosByPersonas obtiene los usuarios de varias personas
+func (r *UsuarioRepository) GetUsuariosByPersonas(personaIDs []uint) ([]models.Usuario, error) {
+	personas := make(map[uint]bool, len(personaIDs))
+	for _, id := range personaIDs {
+		personas[id] = true
+	}
+	return r.find(false, func(u *models.Usuario) bool { return personas[u.PersonaID] }), nil
+}
+
+// ValidateLogin valida credenciales de usuario
+func (r *UsuarioRepository) ValidateLogin(username, password string) (*models.Usuario, error) {
+	return r.first(false, func(u *models.Usuario) bool {
+		return u.Usuario == username && u.Contraseña == password
+	})
+}
+
+// GetAllUsuariosIncludingDeleted obtiene todos los usuarios incluyendo los eliminados
+func (r *UsuarioRepository) GetAllUsuariosIncludingDeleted() ([]models.Usuario, error) {
+	return r.find(true, nil), nil
+}
+
+// GetDeletedUsuarios obtiene solo los usuarios eliminados
+func (r *UsuarioRepository) GetDeletedUsuarios() ([]models.Usuario, error) {
+	return r.find(true, isDeleted[models.Usuario]), nil
+}
+
+// RestoreUsuario restaura un usuario eliminado (soft delete)
+func (r *UsuarioRepository) RestoreUsuario(id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.usuarios.restore(byID[models.Usuario](id))
+	return nil
+}
+
+// GetUsuarioByIDIncludingDeleted obtiene un usuario por ID incluyendo eliminados
+func (r *UsuarioRepository) GetUsuarioByIDIncludingDeleted(id uint) (*models.Usuario, error) {
+	return r.first(true, byID[models.Usuario](id))
+}
+
+// GetUsuarioByUsernameIncludingDeleted busca usuario por nombre incluyendo eliminados
+func (r *UsuarioRepository) GetUsuarioByUsernameIncludingDeleted(username string) (*models.Usuario, error) {
+	return r.first(true, func(u *models.Usuario) bool { return u.Usuario == username })
+}
+
+// UpdatePassword actualiza la contraseña de un usuario
+func (r *UsuarioRepository) UpdatePassword(usuarioID uint, nuevaClave string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.usuarios.update(byID[models.Usuario](usuarioID), func(u *models.Usuario) { u.Contraseña = nuevaClave })
+	return nil
+}
+
+// ExistsByID verifica si existe un usuario con el ID indicado
+func (r *UsuarioRepository) ExistsByID(id uint) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	_, err := r.s.usuarios.get(id, false)
+	return err == nil, nil
+}
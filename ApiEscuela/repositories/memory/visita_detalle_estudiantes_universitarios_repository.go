@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+)
+
+// VisitaDetalleEstudiantesUniversitariosRepository implementa
+// repositories.VisitaDetalleEstudiantesUniversitariosRepository en memoria
+type VisitaDetalleEstudiantesUniversitariosRepository struct {
+	s *Store
+}
+
+var _ repositories.VisitaDetalleEstudiantesUniversitariosRepository = (*VisitaDetalleEstudiantesUniversitariosRepository)(nil)
+
+func NewVisitaDetalleEstudiantesUniversitariosRepository(s *Store) *VisitaDetalleEstudiantesUniversitariosRepository {
+	return &VisitaDetalleEstudiantesUniversitariosRepository{s: s}
+}
+
+type participacion = models.VisitaDetalleEstudiantesUniversitarios
+
+func (r *VisitaDetalleEstudiantesUniversitariosRepository) withRelations(v participacion) participacion {
+	v.EstudianteUniversitario, _ = r.s.estudiantesUniversitarios.get(v.EstudianteUniversitarioID, false)
+	v.ProgramaVisita = r.s.programaVisita(v.ProgramaVisitaID)
+	return v
+}
+
+func byEstudianteUniversitario(estudianteID uint) func(*participacion) bool {
+	return func(v *participacion) bool { return v.EstudianteUniversitarioID == estudianteID }
+}
+
+func byProgramaVisita(programaVisitaID uint) func(*participacion) bool {
+	return func(v *participacion) bool { return v.ProgramaVisitaID == programaVisitaID }
+}
+
+// CreateVisitaDetalleEstudiantesUniversitarios crea una nueva relación
+func (r *VisitaDetalleEstudiantesUniversitariosRepository) CreateVisitaDetalleEstudiantesUniversitarios(relacion *participacion) error {
+	return create(r.s, &r.s.visitaDetalleEstudiantesUniversitarios, relacion)
+}
+
+// GetVisitaDetalleEstudiantesUniversitariosByID obtiene una relación por ID
+func (r *VisitaDetalleEstudiantesUniversitariosRepository) GetVisitaDetalleEstudiantesUniversitariosByID(id uint) (*participacion, error) {
+	return getByID(r.s, &r.s.visitaDetalleEstudiantesUniversitarios, id, r.withRelations)
+}
+
+// GetAllVisitaDetalleEstudiantesUniversitarios obtiene todas las relaciones
+func (r *VisitaDetalleEstudiantesUniversitariosRepository) GetAllVisitaDetalleEstudiantesUniversitarios() ([]participacion, error) {
+	return list(r.s, &r.s.visitaDetalleEstudiantesUniversitarios, nil, r.withRelations)
+}
+
+// UpdateVisitaDetalleEstudiantesUniversitarios actualiza una relación
+func (r *VisitaDetalleEstudiantesUniversitariosRepository) UpdateVisitaDetalleEstudiantesUniversitarios(relacion *participacion) error {
+	return save(r.s, &r.s.visitaDetalleEstudiantesUniversitarios, relacion)
+}
+
+// DeleteVisitaDetalleEstudiantesUniversitarios elimina una relación
+func (r *VisitaDetalleEstudiantesUniversitariosRepository) DeleteVisitaDetalleEstudiantesUniversitarios(id uint) error {
+	return remove(r.s, &r.s.visitaDetalleEstudiantesUniversitarios, byID[participacion](id))
+}
+
+// GetEstudiantesByProgramaVisita obtiene estudiantes de un programa de visita
+func (r *VisitaDetalleEstudiantesUniversitariosRepository) GetEstudiantesByProgramaVisita(programaVisitaID uint) ([]participacion, error) {
+	return list(r.s, &r.s.visitaDetalleEstudiantesUniversitarios, byProgramaVisita(programaVisitaID), r.withRelations)
+}
+
+// GetProgramasVisitaByEstudiante obtiene programas de visita de un estudiante
+func (r *VisitaDetalleEstudiantesUniversitariosRepository) GetProgramasVisitaByEstudiante(estudianteID uint) ([]participacion, error) {
+	return list(r.s, &r.s.visitaDetalleEstudiantesUniversitarios, byEstudianteUniversitario(estudianteID), r.withRelations)
+}
+
+// DeleteByProgramaVisita elimina todas las relaciones de un programa de visita
+func (r *VisitaDetalleEstudiantesUniversitariosRepository) DeleteByProgramaVisita(programaVisitaID uint) error {
+	return remove(r.s, &r.s.visitaDetalleEstudiantesUniversitarios, byProgramaVisita(programaVisitaID))
+}
+
+// DeleteByEstudiante elimina todas las relaciones de un estudiante
+func (r *VisitaDetalleEstudiantesUniversitariosRepository) DeleteByEstudiante(estudianteID uint) error {
+	return remove(r.s, &r.s.visitaDetalleEstudiantesUniversitarios, byEstudianteUniversitario(estudianteID))
+}
+
+// ExistsRelation verifica si ya existe la relación entre estudiante y programa de visita
+func (r *VisitaDetalleEstudiantesUniversitariosRepository) ExistsRelation(estudianteID, programaVisitaID uint) (bool, error) {
+	return exists(r.s, &r.s.visitaDetalleEstudiantesUniversitarios, func(v *participacion) bool {
+		return v.EstudianteUniversitarioID == estudianteID && v.ProgramaVisitaID == programaVisitaID
+	})
+}
+
+// GetEstadisticasParticipacion obtiene estadísticas de participación
+func (r *VisitaDetalleEstudiantesUniversitariosRepository) GetEstadisticasParticipacion() (map[string]interface{}, error) {
+	relaciones, _ := list(r.s, &r.s.visitaDetalleEstudiantesUniversitarios, nil, nil)
+	total, estudiantes, programas, promedio := estadisticas(relaciones,
+		func(v participacion) uint { return v.EstudianteUniversitarioID },
+		func(v participacion) uint { return v.ProgramaVisitaID })
+	return map[string]interface{}{
+		"total_participaciones":             total,
+		"total_estudiantes_unicos":          estudiantes,
+		"total_programas_con_estudiantes":   programas,
+		"promedio_estudiantes_por_programa": promedio,
+	}, nil
+}
@@ -0,0 +1,89 @@
+package memory
+
+import (
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+)
+
+// VisitaDetalleRepository implementa repositories.VisitaDetalleRepository en memoria
+type VisitaDetalleRepository struct {
+	s *Store
+}
+
+var _ repositories.VisitaDetalleRepository = (*VisitaDetalleRepository)(nil)
+
+func NewVisitaDetalleRepository(s *Store) *VisitaDetalleRepository {
+	return &VisitaDetalleRepository{s: s}
+}
+
+func (r *VisitaDetalleRepository) withRelations(v models.VisitaDetalle) models.VisitaDetalle {
+	v.ProgramaVisita = r.s.programaVisita(v.ProgramaVisitaID)
+	v.Actividad, _ = r.s.actividades.get(v.ActividadID, false)
+	return v
+}
+
+// CreateVisitaDetalle crea un nuevo detalle de visita
+func (r *VisitaDetalleRepository) CreateVisitaDetalle(detalle *models.VisitaDetalle) error {
+	return create(r.s, &r.s.visitaDetalles, detalle)
+}
+
+// GetVisitaDetalleByID obtiene un detalle de visita por ID
+func (r *VisitaDetalleRepository) GetVisitaDetalleByID(id uint) (*models.VisitaDetalle, error) {
+	return getByID(r.s, &r.s.visitaDetalles, id, r.withRelations)
+}
+
+// GetAllVisitaDetalles obtiene todos los detalles de visita
+func (r *VisitaDetalleRepository) GetAllVisitaDetalles() ([]models.VisitaDetalle, error) {
+	return list(r.s, &r.s.visitaDetalles, nil, r.withRelations)
+}
+
+// UpdateVisitaDetalle actualiza un detalle de visita
+func (r *VisitaDetalleRepository) UpdateVisitaDetalle(detalle *models.VisitaDetalle) error {
+	return save(r.s, &r.s.visitaDetalles, detalle)
+}
+
+// DeleteVisitaDetalle elimina un detalle de visita
+func (r *VisitaDetalleRepository) DeleteVisitaDetalle(id uint) error {
+	return remove(r.s, &r.s.visitaDetalles, byID[models.VisitaDetalle](id))
+}
+
+// GetVisitaDetallesByActividad obtiene detalles por actividad
+func (r *VisitaDetalleRepository) GetVisitaDetallesByActividad(actividadID uint) ([]models.VisitaDetalle, error) {
+	return list(r.s, &r.s.visitaDetalles, func(v *models.VisitaDetalle) bool { return v.ActividadID == actividadID }, r.withRelations)
+}
+
+// GetVisitaDetallesByPrograma obtiene detalles por programa de visita
+func (r *VisitaDetalleRepository) GetVisitaDetallesByPrograma(programaID uint) ([]models.VisitaDetalle, error) {
+	return list(r.s, &r.s.visitaDetalles, func(v *models.VisitaDetalle) bool { return v.ProgramaVisitaID == programaID }, r.withRelations)
+}
+
+// DeleteVisitaDetallesByPrograma elimina todos los detalles de un programa de visita
+func (r *VisitaDetalleRepository) DeleteVisitaDetallesByPrograma(programaID uint) error {
+	return remove(r.s, &r.s.visitaDetalles, func(v *models.VisitaDetalle) bool { return v.ProgramaVisitaID == programaID })
+}
+
+// DeleteVisitaDetallesByActividad elimina todos los detalles de una actividad
+func (r *VisitaDetalleRepository) DeleteVisitaDetallesByActividad(actividadID uint) error {
+	return remove(r.s, &r.s.visitaDetalles, func(v *models.VisitaDetalle) bool { return v.ActividadID == actividadID })
+}
+
+// ExistsRelation verifica si ya existe una relación entre programa de visita y actividad
+func (r *VisitaDetalleRepository) ExistsRelation(programaVisitaID, actividadID uint) (bool, error) {
+	return exists(r.s, &r.s.visitaDetalles, func(v *models.VisitaDetalle) bool {
+		return v.ProgramaVisitaID == programaVisitaID && v.ActividadID == actividadID
+	})
+}
+
+// GetEstadisticasActividades obtiene estadísticas de asignación de actividades
+func (r *VisitaDetalleRepository) GetEstadisticasActividades() (map[string]interface{}, error) {
+	detalles, _ := list(r.s, &r.s.visitaDetalles, nil, nil)
+	total, actividades, programas, promedio := estadisticas(detalles,
+		func(v models.VisitaDetalle) uint { return v.ActividadID },
+		func(v models.VisitaDetalle) uint { return v.ProgramaVisitaID })
+	return map[string]interface{}{
+		"total_asignaciones_actividades":    total,
+		"total_actividades_unicas":          actividades,
+		"total_programas_con_actividades":   programas,
+		"promedio_actividades_por_programa": promedio,
+	}, nil
+}
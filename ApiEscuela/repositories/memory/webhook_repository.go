@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"sort"
+	"time"
+
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+)
+
+// WebhookRepository implementa repositories.WebhookRepository en memoria
+type WebhookRepository struct {
+	s *Store
+}
+
+var _ repositories.WebhookRepository = (*WebhookRepository)(nil)
+
+func NewWebhookRepository(s *Store) *WebhookRepository {
+	return &WebhookRepository{s: s}
+}
+
+// CreateWebhook guarda una suscripción nueva
+func (r *WebhookRepository) CreateWebhook(webhook *models.Webhook) error {
+	return create(r.s, &r.s.webhooks, webhook)
+}
+
+// GetWebhookByID obtiene una suscripción por su ID
+func (r *WebhookRepository) GetWebhookByID(id uint) (*models.Webhook, error) {
+	return getByID(r.s, &r.s.webhooks, id, nil)
+}
+
+// GetAllWebhooks obtiene todas las suscripciones
+func (r *WebhookRepository) GetAllWebhooks() ([]models.Webhook, error) {
+	return list(r.s, &r.s.webhooks, nil, nil)
+}
+
+// GetWebhooksActivos obtiene las suscripciones activas
+func (r *WebhookRepository) GetWebhooksActivos() ([]models.Webhook, error) {
+	return list(r.s, &r.s.webhooks, func(w *models.Webhook) bool { return w.Activo }, nil)
+}
+
+// UpdateWebhook guarda los cambios de una suscripción
+func (r *WebhookRepository) UpdateWebhook(webhook *models.Webhook) error {
+	return save(r.s, &r.s.webhooks, webhook)
+}
+
+// DeleteWebhook elimina lógicamente una suscripción; su registro de entregas se conserva
+func (r *WebhookRepository) DeleteWebhook(id uint) error {
+	return remove(r.s, &r.s.webhooks, byID[models.Webhook](id))
+}
+
+// CreateEntrega guarda una entrega nueva
+func (r *WebhookRepository) CreateEntrega(entrega *models.EntregaWebhook) error {
+	return create(r.s, &r.s.entregasWebhook, entrega)
+}
+
+// GetEntregaByID obtiene una entrega por su ID
+func (r *WebhookRepository) GetEntregaByID(id uint) (*models.EntregaWebhook, error) {
+	return getByID(r.s, &r.s.entregasWebhook, id, nil)
+}
+
+// GetEntregasByWebhook obtiene las entregas de una suscripción, de la más reciente a la más
+// antigua; limite <= 0 no limita la cantidad
+func (r *WebhookRepository) GetEntregasByWebhook(webhookID uint, limite int) ([]models.EntregaWebhook, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	entregas := r.s.entregasWebhook.find(false, func(e *models.EntregaWebhook) bool { return e.WebhookID == webhookID })
+	sort.SliceStable(entregas, func(i, j int) bool { return entregas[i].ID > entregas[j].ID })
+	if limite > 0 && len(entregas) > limite {
+		entregas = entregas[:limite]
+	}
+	return entregas, nil
+}
+
+// GetEntregasPendientes obtiene las entregas pendientes cuyo próximo intento ya venció, de la más
+// atrasada a la más reciente; limite <= 0 no limita la cantidad
+func (r *WebhookRepository) GetEntregasPendientes(ahora time.Time, limite int) ([]models.EntregaWebhook, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	entregas := r.s.entregasWebhook.find(false, func(e *models.EntregaWebhook) bool { return vencida(e, ahora) })
+	sort.SliceStable(entregas, func(i, j int) bool { return entregas[i].ProximoIntento.Before(*entregas[j].ProximoIntento) })
+	if limite > 0 && len(entregas) > limite {
+		entregas = entregas[:limite]
+	}
+	return entregas, nil
+}
+
+// ReclamarEntrega aplaza hasta la fecha indicada el próximo intento de una entrega pendiente y
+// vencida, para que un solo proceso la envíe. Devuelve false si otro ya la reclamó.
+func (r *WebhookRepository) ReclamarEntrega(id uint, ahora, hasta time.Time) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	reclamable := func(e *models.EntregaWebhook) bool { return e.ID == id && vencida(e, ahora) }
+	if r.s.entregasWebhook.count(reclamable) == 0 {
+		return false, nil
+	}
+	r.s.entregasWebhook.update(reclamable, func(e *models.EntregaWebhook) { e.ProximoIntento = &hasta })
+	return true, nil
+}
+
+// UpdateEntrega guarda el resultado de un intento
+func (r *WebhookRepository) UpdateEntrega(entrega *models.EntregaWebhook) error {
+	return save(r.s, &r.s.entregasWebhook, entrega)
+}
+
+// vencida indica si a una entrega pendiente ya le toca el siguiente intento
+func vencida(e *models.EntregaWebhook, ahora time.Time) bool {
+	return e.Estado == repositories.EntregaPendiente && e.ProximoIntento != nil && !e.ProximoIntento.After(ahora)
+}
@@ -6,21 +6,21 @@ import (
 	"gorm.io/gorm"
 )
 
-type NoticiaRepository struct {
+type noticiaRepository struct {
 	db *gorm.DB
 }
 
-func NewNoticiaRepository(db *gorm.DB) *NoticiaRepository {
-	return &NoticiaRepository{db: db}
+func NewNoticiaRepository(db *gorm.DB) NoticiaRepository {
+	return &noticiaRepository{db: db}
 }
 
 // CreateNoticia crea una nueva noticia
-func (r *NoticiaRepository) CreateNoticia(noticia *models.Noticia) error {
+func (r *noticiaRepository) CreateNoticia(noticia *models.Noticia) error {
 	return r.db.Create(noticia).Error
 }
 
 // GetNoticiaByID obtiene una noticia por ID
-func (r *NoticiaRepository) GetNoticiaByID(id uint) (*models.Noticia, error) {
+func (r *noticiaRepository) GetNoticiaByID(id uint) (*models.Noticia, error) {
 	var noticia models.Noticia
 	err := r.db.Preload("Usuario").Preload("Usuario.Persona").
 		First(&noticia, id).Error
@@ -31,7 +31,7 @@ func (r *NoticiaRepository) GetNoticiaByID(id uint) (*models.Noticia, error) {
 }
 
 // GetAllNoticias obtiene todas las noticias
-func (r *NoticiaRepository) GetAllNoticias() ([]models.Noticia, error) {
+func (r *noticiaRepository) GetAllNoticias() ([]models.Noticia, error) {
 	var noticias []models.Noticia
 	err := r.db.Preload("Usuario").Preload("Usuario.Persona").
 		Find(&noticias).Error
@@ -39,17 +39,17 @@ func (r *NoticiaRepository) GetAllNoticias() ([]models.Noticia, error) {
 }
 
 // UpdateNoticia actualiza una noticia
-func (r *NoticiaRepository) UpdateNoticia(noticia *models.Noticia) error {
+func (r *noticiaRepository) UpdateNoticia(noticia *models.Noticia) error {
 	return r.db.Save(noticia).Error
 }
 
 // DeleteNoticia elimina una noticia
-func (r *NoticiaRepository) DeleteNoticia(id uint) error {
+func (r *noticiaRepository) DeleteNoticia(id uint) error {
 	return r.db.Delete(&models.Noticia{}, id).Error
 }
 
 // GetNoticiasByUsuario obtiene noticias por usuario
-func (r *NoticiaRepository) GetNoticiasByUsuario(usuarioID uint) ([]models.Noticia, error) {
+func (r *noticiaRepository) GetNoticiasByUsuario(usuarioID uint) ([]models.Noticia, error) {
 	var noticias []models.Noticia
 	err := r.db.Where("usuario_id = ?", usuarioID).
 		Preload("Usuario").Preload("Usuario.Persona").
@@ -58,7 +58,7 @@ func (r *NoticiaRepository) GetNoticiasByUsuario(usuarioID uint) ([]models.Notic
 }
 
 // GetNoticiasByTitulo busca noticias por título
-func (r *NoticiaRepository) GetNoticiasByTitulo(titulo string) ([]models.Noticia, error) {
+func (r *noticiaRepository) GetNoticiasByTitulo(titulo string) ([]models.Noticia, error) {
 	var noticias []models.Noticia
 	err := r.db.Where("titulo ILIKE ?", "%"+titulo+"%").
 		Preload("Usuario").Preload("Usuario.Persona").
@@ -67,7 +67,7 @@ func (r *NoticiaRepository) GetNoticiasByTitulo(titulo string) ([]models.Noticia
 }
 
 // GetNoticiasByDescripcion busca noticias por descripción
-func (r *NoticiaRepository) GetNoticiasByDescripcion(descripcion string) ([]models.Noticia, error) {
+func (r *noticiaRepository) GetNoticiasByDescripcion(descripcion string) ([]models.Noticia, error) {
 	var noticias []models.Noticia
 	err := r.db.Where("descripcion ILIKE ?", "%"+descripcion+"%").
 		Preload("Usuario").Preload("Usuario.Persona").
@@ -75,11 +75,9 @@ func (r *NoticiaRepository) GetNoticiasByDescripcion(descripcion string) ([]mode
 	return noticias, err
 }
 
-// SearchNoticias busca noticias por título o descripción
-func (r *NoticiaRepository) SearchNoticias(termino string) ([]models.Noticia, error) {
-	var noticias []models.Noticia
-	err := r.db.Where("titulo ILIKE ? OR descripcion ILIKE ?", "%"+termino+"%", "%"+termino+"%").
-		Preload("Usuario").Preload("Usuario.Persona").
-		Find(&noticias).Error
-	return noticias, err
+// SearchNoticias busca noticias por las palabras del título o la descripción, sin distinguir tildes;
+// las coincidencias en el título pesan más
+func (r *noticiaRepository) SearchNoticias(termino string) ([]ResultadoBusqueda[models.Noticia], error) {
+	return buscarTexto[models.Noticia](r.db.Preload("Usuario").Preload("Usuario.Persona"),
+		ConsultaTexto(termino, ""), "concat_ws(' — ', titulo, descripcion)")
 }
@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"ApiEscuela/models"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// loteNotificaciones es la cantidad de filas de cada INSERT al guardar varias notificaciones
+const loteNotificaciones = 500
+
+type notificacionRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificacionRepository(db *gorm.DB) NotificacionRepository {
+	return &notificacionRepository{db: db}
+}
+
+// CreateNotificacion guarda una notificación nueva
+func (r *notificacionRepository) CreateNotificacion(notificacion *models.Notificacion) error {
+	return r.db.Create(notificacion).Error
+}
+
+// CreateNotificaciones guarda varias notificaciones nuevas en lotes; completa el ID de cada una
+func (r *notificacionRepository) CreateNotificaciones(notificaciones []models.Notificacion) error {
+	if len(notificaciones) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(&notificaciones, loteNotificaciones).Error
+}
+
+// GetNotificacionesByUsuario obtiene las notificaciones de un usuario, de la más reciente a la más
+// antigua; limite <= 0 no limita la cantidad
+func (r *notificacionRepository) GetNotificacionesByUsuario(usuarioID uint, soloNoLeidas bool, limite int) ([]models.Notificacion, error) {
+	var notificaciones []models.Notificacion
+	query := r.db.Where("usuario_id = ?", usuarioID)
+	if soloNoLeidas {
+		query = query.Where("leida = ?", false)
+	}
+	if limite > 0 {
+		query = query.Limit(limite)
+	}
+	err := query.Order("created_at DESC, id DESC").Find(&notificaciones).Error
+	return notificaciones, err
+}
+
+// ContarNoLeidas cuenta las notificaciones sin leer de un usuario
+func (r *notificacionRepository) ContarNoLeidas(usuarioID uint) (int64, error) {
+	var total int64
+	err := r.db.Model(&models.Notificacion{}).Where("usuario_id = ? AND leida = ?", usuarioID, false).Count(&total).Error
+	return total, err
+}
+
+// MarcarLeida marca como leída una notificación del usuario; si ya estaba leída conserva la fecha
+// de lectura. Devuelve gorm.ErrRecordNotFound si la notificación no es del usuario.
+func (r *notificacionRepository) MarcarLeida(usuarioID, id uint, fecha time.Time) (*models.Notificacion, error) {
+	var notificacion models.Notificacion
+	if err := r.db.Where("id = ? AND usuario_id = ?", id, usuarioID).First(&notificacion).Error; err != nil {
+		return nil, err
+	}
+	if notificacion.Leida {
+		return &notificacion, nil
+	}
+	notificacion.Leida = true
+	notificacion.LeidaEn = &fecha
+	if err := r.db.Model(&notificacion).Updates(map[string]interface{}{"leida": true, "leida_en": fecha}).Error; err != nil {
+		return nil, err
+	}
+	return &notificacion, nil
+}
+
+// MarcarTodasLeidas marca como leídas todas las notificaciones sin leer del usuario
+func (r *notificacionRepository) MarcarTodasLeidas(usuarioID uint, fecha time.Time) (int64, error) {
+	result := r.db.Model(&models.Notificacion{}).
+		Where("usuario_id = ? AND leida = ?", usuarioID, false).
+		Updates(map[string]interface{}{"leida": true, "leida_en": fecha})
+	return result.RowsAffected, result.Error
+}
+
+// GetPreferencias obtiene las preferencias de envío guardadas de un usuario
+func (r *notificacionRepository) GetPreferencias(usuarioID uint) (*models.PreferenciaNotificacion, error) {
+	var preferencias models.PreferenciaNotificacion
+	if err := r.db.Where("usuario_id = ?", usuarioID).First(&preferencias).Error; err != nil {
+		return nil, err
+	}
+	return &preferencias, nil
+}
+
+// GetPreferenciasByUsuarios obtiene las preferencias guardadas de varios usuarios; los usuarios
+// sin preferencias no aparecen
+func (r *notificacionRepository) GetPreferenciasByUsuarios(usuarioIDs []uint) ([]models.PreferenciaNotificacion, error) {
+	preferencias := []models.PreferenciaNotificacion{}
+	if len(usuarioIDs) == 0 {
+		return preferencias, nil
+	}
+	err := r.db.Where("usuario_id IN ?", usuarioIDs).Find(&preferencias).Error
+	return preferencias, err
+}
+
+// GuardarPreferencias crea o reemplaza las preferencias de envío del usuario
+func (r *notificacionRepository) GuardarPreferencias(preferencias *models.PreferenciaNotificacion) error {
+	actual, err := r.GetPreferencias(preferencias.UsuarioID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return r.db.Create(preferencias).Error
+	}
+	if err != nil {
+		return err
+	}
+	preferencias.ID, preferencias.CreatedAt = actual.ID, actual.CreatedAt
+	return r.db.Save(preferencias).Error
+}
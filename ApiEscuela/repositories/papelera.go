@@ -0,0 +1,213 @@
+package repositories
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"ApiEscuela/errores"
+	"ApiEscuela/models"
+)
+
+// Errores de la papelera
+var (
+	ErrEntidadSinPapelera = errores.NoEncontrado("papelera_entidad_desconocida", "La entidad no existe o no tiene papelera")
+	ErrNoEstaEnPapelera   = errores.NoEncontrado("papelera_no_encontrado", "El registro no está en la papelera")
+	// ErrPapeleraDependencias se devuelve con un mensaje que detalla los registros que lo usan
+	ErrPapeleraDependencias = errores.Conflicto("papelera_dependencias", "Otros registros usan el registro; no se puede purgar")
+	// ErrPapeleraPadreEliminado se devuelve con un mensaje que indica qué restaurar primero
+	ErrPapeleraPadreEliminado = errores.Conflicto("papelera_padre_eliminado", "El registro depende de otro que está en la papelera")
+)
+
+// RelacionPapelera son los registros de Entidad cuyo campo Columna es igual al campo Campo del
+// registro. Columna y Campo son nombres de campos Go; Campo vacío es el ID del registro.
+type RelacionPapelera struct {
+	Entidad string
+	Columna string
+	Campo   string
+}
+
+// EntidadPapelera describe una entidad con borrado lógico (gorm.Model) en la papelera
+type EntidadPapelera struct {
+	// Nombre es el de la ruta del recurso, p. ej. "instituciones"
+	Nombre string
+	// Modelo es un puntero a un modelo vacío de la entidad
+	Modelo interface{}
+	// Restaurar son los registros que se restauran junto con el registro, como en RestoreEstudiante
+	Restaurar []RelacionPapelera
+	// Requiere son los registros de los que depende; si están en la papelera no se restaura
+	Requiere []RelacionPapelera
+	// Dependencias son los registros que usan el registro, incluidos los eliminados; impiden purgarlo
+	Dependencias []RelacionPapelera
+}
+
+// nuevo devuelve un puntero a un modelo vacío nuevo; Modelo es compartido y no debe modificarse
+func (e EntidadPapelera) nuevo() interface{} {
+	return reflect.New(reflect.TypeOf(e.Modelo).Elem()).Interface()
+}
+
+// padre es el registro de entidad cuyo ID está en el campo del registro
+func padre(entidad, campo string) RelacionPapelera {
+	return RelacionPapelera{Entidad: entidad, Columna: "ID", Campo: campo}
+}
+
+// usadoPor son los registros de entidad cuya columna apunta al ID del registro
+func usadoPor(entidad, columna string) RelacionPapelera {
+	return RelacionPapelera{Entidad: entidad, Columna: columna}
+}
+
+// EntidadesPapelera son las entidades de la papelera. Las que usan a otras van antes, de modo
+// que la purga automática elimina primero los registros dependientes.
+var EntidadesPapelera = []EntidadPapelera{
+	{Nombre: "dudas", Modelo: &models.Dudas{},
+		Requiere: []RelacionPapelera{padre("estudiantes", "EstudianteID"), padre("autoridades-uteq", "AutoridadUTEQID")}},
+	{Nombre: "visita-detalle-estudiantes-universitarios", Modelo: &models.VisitaDetalleEstudiantesUniversitarios{},
+		Requiere: []RelacionPapelera{padre("estudiantes-universitarios", "EstudianteUniversitarioID"), padre("programas-visita", "ProgramaVisitaID")}},
+	{Nombre: "visita-detalles", Modelo: &models.VisitaDetalle{},
+		Requiere: []RelacionPapelera{padre("programas-visita", "ProgramaVisitaID"), padre("actividades", "ActividadID")}},
+	{Nombre: "detalle-autoridad-detalles-visita", Modelo: &models.DetalleAutoridadDetallesVisita{},
+		Requiere: []RelacionPapelera{padre("programas-visita", "ProgramaVisitaID"), padre("autoridades-uteq", "AutoridadUTEQID")}},
+	{Nombre: "codigos", Modelo: &models.CodigoUsuario{},
+		Requiere: []RelacionPapelera{padre("usuarios", "UsuarioID")}},
+	{Nombre: "noticias", Modelo: &models.Noticia{},
+		Requiere: []RelacionPapelera{padre("usuarios", "UsuarioID")}},
+	{Nombre: "comunicados", Modelo: &models.Comunicado{},
+		Requiere: []RelacionPapelera{padre("usuarios", "UsuarioID")}},
+	{Nombre: "notificaciones", Modelo: &models.Notificacion{},
+		Requiere: []RelacionPapelera{padre("usuarios", "UsuarioID")}},
+	{Nombre: "entregas-webhook", Modelo: &models.EntregaWebhook{},
+		Requiere: []RelacionPapelera{padre("webhooks", "WebhookID")}},
+	{Nombre: "webhooks", Modelo: &models.Webhook{},
+		Dependencias: []RelacionPapelera{usadoPor("entregas-webhook", "WebhookID")}},
+	{Nombre: "estudiantes", Modelo: &models.Estudiante{},
+		Restaurar:    []RelacionPapelera{padre("personas", "PersonaID"), {Entidad: "usuarios", Columna: "PersonaID", Campo: "PersonaID"}},
+		Requiere:     []RelacionPapelera{padre("instituciones", "InstitucionID"), padre("ciudades", "CiudadID")},
+		Dependencias: []RelacionPapelera{usadoPor("dudas", "EstudianteID")}},
+	{Nombre: "estudiantes-universitarios", Modelo: &models.EstudianteUniversitario{},
+		Requiere:     []RelacionPapelera{padre("personas", "PersonaID")},
+		Dependencias: []RelacionPapelera{usadoPor("visita-detalle-estudiantes-universitarios", "EstudianteUniversitarioID")}},
+	{Nombre: "autoridades-uteq", Modelo: &models.AutoridadUTEQ{},
+		Restaurar: []RelacionPapelera{padre("personas", "PersonaID"), {Entidad: "usuarios", Columna: "PersonaID", Campo: "PersonaID"}},
+		Dependencias: []RelacionPapelera{
+			usadoPor("detalle-autoridad-detalles-visita", "AutoridadUTEQID"),
+			usadoPor("dudas", "AutoridadUTEQID"),
+		}},
+	{Nombre: "programas-visita", Modelo: &models.ProgramaVisita{},
+		Requiere: []RelacionPapelera{padre("instituciones", "InstitucionID")},
+		Dependencias: []RelacionPapelera{
+			usadoPor("detalle-autoridad-detalles-visita", "ProgramaVisitaID"),
+			usadoPor("visita-detalles", "ProgramaVisitaID"),
+			usadoPor("visita-detalle-estudiantes-universitarios", "ProgramaVisitaID"),
+		}},
+	{Nombre: "actividades", Modelo: &models.Actividad{},
+		Requiere:     []RelacionPapelera{padre("tematicas", "TematicaID")},
+		Dependencias: []RelacionPapelera{usadoPor("visita-detalles", "ActividadID")}},
+	{Nombre: "tematicas", Modelo: &models.Tematica{},
+		Dependencias: []RelacionPapelera{usadoPor("actividades", "TematicaID")}},
+	{Nombre: "usuarios", Modelo: &models.Usuario{},
+		Requiere: []RelacionPapelera{padre("personas", "PersonaID"), padre("tipos-usuario", "TipoUsuarioID")},
+		Dependencias: []RelacionPapelera{
+			usadoPor("codigos", "UsuarioID"),
+			usadoPor("noticias", "UsuarioID"),
+			usadoPor("comunicados", "UsuarioID"),
+			usadoPor("media", "UsuarioID"),
+			usadoPor("notificaciones", "UsuarioID"),
+			usadoPor("preferencias-notificacion", "UsuarioID"),
+		}},
+	{Nombre: "tipos-usuario", Modelo: &models.TipoUsuario{},
+		Dependencias: []RelacionPapelera{usadoPor("usuarios", "TipoUsuarioID")}},
+	{Nombre: "personas", Modelo: &models.Persona{},
+		Dependencias: []RelacionPapelera{
+			usadoPor("usuarios", "PersonaID"),
+			usadoPor("estudiantes", "PersonaID"),
+			usadoPor("estudiantes-universitarios", "PersonaID"),
+			usadoPor("autoridades-uteq", "PersonaID"),
+		}},
+	{Nombre: "instituciones", Modelo: &models.Institucion{},
+		Dependencias: []RelacionPapelera{usadoPor("estudiantes", "InstitucionID"), usadoPor("programas-visita", "InstitucionID")}},
+	{Nombre: "ciudades", Modelo: &models.Ciudad{},
+		Requiere:     []RelacionPapelera{padre("provincias", "ProvinciaID")},
+		Dependencias: []RelacionPapelera{usadoPor("estudiantes", "CiudadID")}},
+	{Nombre: "provincias", Modelo: &models.Provincia{},
+		Dependencias: []RelacionPapelera{usadoPor("ciudades", "ProvinciaID")}},
+}
+
+// modelosSinPapelera son las entidades sin papelera que usan registros de la papelera
+var modelosSinPapelera = map[string]interface{}{
+	"media":                     &models.Media{},
+	"preferencias-notificacion": &models.PreferenciaNotificacion{},
+}
+
+// ModeloRelacion devuelve un puntero a un modelo vacío nuevo de la entidad de una relación, que
+// puede no tener papelera
+func ModeloRelacion(entidad string) (interface{}, error) {
+	if e, err := BuscarEntidadPapelera(entidad); err == nil {
+		return e.nuevo(), nil
+	}
+	if modelo, ok := modelosSinPapelera[entidad]; ok {
+		return reflect.New(reflect.TypeOf(modelo).Elem()).Interface(), nil
+	}
+	return nil, ErrEntidadSinPapelera
+}
+
+// BuscarEntidadPapelera busca una entidad de la papelera por su nombre
+func BuscarEntidadPapelera(nombre string) (EntidadPapelera, error) {
+	for _, e := range EntidadesPapelera {
+		if e.Nombre == nombre {
+			return e, nil
+		}
+	}
+	return EntidadPapelera{}, ErrEntidadSinPapelera
+}
+
+// ElementoPapelera es un registro eliminado lógicamente
+type ElementoPapelera struct {
+	Entidad     string      `json:"entidad"`
+	ID          uint        `json:"id"`
+	EliminadoEn time.Time   `json:"eliminado_en"`
+	Registro    interface{} `json:"registro"`
+}
+
+// ValorCampo lee un campo uint (o *uint) de un registro; 0 si no existe o es nil
+func ValorCampo(registro interface{}, campo string) uint {
+	if campo == "" {
+		campo = "ID"
+	}
+	v := reflect.Indirect(reflect.ValueOf(registro)).FieldByName(campo)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return 0
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return uint(v.Uint())
+	}
+	return 0
+}
+
+// ErrorDependencias arma ErrPapeleraDependencias con la cantidad de registros de cada entidad que usan el registro
+func ErrorDependencias(cantidades map[string]int64) error {
+	nombres := make([]string, 0, len(cantidades))
+	for nombre := range cantidades {
+		nombres = append(nombres, nombre)
+	}
+	sort.Strings(nombres)
+	partes := make([]string, len(nombres))
+	for i, nombre := range nombres {
+		partes[i] = fmt.Sprintf("%s: %d", nombre, cantidades[nombre])
+	}
+	e := *ErrPapeleraDependencias
+	e.Mensaje = "No se puede purgar: lo usan otros registros (" + strings.Join(partes, ", ") + ")"
+	return &e
+}
+
+// ErrorPadreEliminado arma ErrPapeleraPadreEliminado indicando el registro que hay que restaurar primero
+func ErrorPadreEliminado(entidad string, id uint) error {
+	e := *ErrPapeleraPadreEliminado
+	e.Mensaje = fmt.Sprintf("Restaure primero el registro %d de %s, que está en la papelera", id, entidad)
+	return &e
+}
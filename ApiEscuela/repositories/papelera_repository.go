@@ -0,0 +1,183 @@
+package repositories
+
+import (
+	"errors"
+	"reflect"
+	"time"
+
+	"ApiEscuela/errores"
+
+	"gorm.io/gorm"
+)
+
+type papeleraRepository struct {
+	db *gorm.DB
+}
+
+func NewPapeleraRepository(db *gorm.DB) PapeleraRepository {
+	return &papeleraRepository{db: db}
+}
+
+// ContarEliminados cuenta los registros de la entidad que están en la papelera
+func (r *papeleraRepository) ContarEliminados(entidad string) (int64, error) {
+	e, err := BuscarEntidadPapelera(entidad)
+	if err != nil {
+		return 0, err
+	}
+	var n int64
+	err = r.db.Unscoped().Model(e.nuevo()).Where("deleted_at IS NOT NULL").Count(&n).Error
+	return n, err
+}
+
+// GetEliminados obtiene los registros de la entidad que están en la papelera, los más recientes primero
+func (r *papeleraRepository) GetEliminados(entidad string) ([]ElementoPapelera, error) {
+	e, err := BuscarEntidadPapelera(entidad)
+	if err != nil {
+		return nil, err
+	}
+	registros := reflect.New(reflect.SliceOf(reflect.TypeOf(e.Modelo).Elem()))
+	err = r.db.Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at DESC, id DESC").
+		Find(registros.Interface()).Error
+	if err != nil {
+		return nil, err
+	}
+
+	lista := registros.Elem()
+	elementos := make([]ElementoPapelera, lista.Len())
+	for i := range elementos {
+		registro := lista.Index(i).Addr().Interface()
+		elementos[i] = ElementoPapelera{
+			Entidad:     entidad,
+			ID:          ValorCampo(registro, "ID"),
+			EliminadoEn: eliminadoEn(registro),
+			Registro:    registro,
+		}
+	}
+	return elementos, nil
+}
+
+// GetEliminadosAntes obtiene los ID de los registros de la entidad eliminados antes de la fecha
+func (r *papeleraRepository) GetEliminadosAntes(entidad string, antes time.Time) ([]uint, error) {
+	e, err := BuscarEntidadPapelera(entidad)
+	if err != nil {
+		return nil, err
+	}
+	var ids []uint
+	err = r.db.Unscoped().Model(e.nuevo()).Where("deleted_at IS NOT NULL AND deleted_at < ?", antes).
+		Order("id").Pluck("id", &ids).Error
+	return ids, err
+}
+
+// Restaurar restaura un registro de la papelera y, en cascada, los registros de e.Restaurar.
+// Falla si alguno de los registros de e.Requiere está en la papelera.
+func (r *papeleraRepository) Restaurar(entidad string, id uint) error {
+	e, err := BuscarEntidadPapelera(entidad)
+	if err != nil {
+		return err
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		registro, err := r.eliminado(tx, e, id)
+		if err != nil {
+			return err
+		}
+
+		for _, rel := range e.Requiere {
+			valor := ValorCampo(registro, rel.Campo)
+			if valor == 0 {
+				continue
+			}
+			otra, err := BuscarEntidadPapelera(rel.Entidad)
+			if err != nil {
+				return err
+			}
+			var ids []uint
+			err = tx.Unscoped().Model(otra.nuevo()).Where(r.columna(rel.Columna)+" = ? AND deleted_at IS NOT NULL", valor).
+				Limit(1).Pluck("id", &ids).Error
+			if err != nil {
+				return errores.DesdeBD(err)
+			}
+			if len(ids) > 0 {
+				return ErrorPadreEliminado(rel.Entidad, ids[0])
+			}
+		}
+
+		for _, rel := range e.Restaurar {
+			valor := ValorCampo(registro, rel.Campo)
+			if valor == 0 {
+				continue
+			}
+			otra, err := BuscarEntidadPapelera(rel.Entidad)
+			if err != nil {
+				return err
+			}
+			err = tx.Unscoped().Model(otra.nuevo()).Where(r.columna(rel.Columna)+" = ? AND deleted_at IS NOT NULL", valor).
+				Update("deleted_at", nil).Error
+			if err != nil {
+				return errores.DesdeBD(err)
+			}
+		}
+
+		return errores.DesdeBD(tx.Unscoped().Model(e.nuevo()).Where("id = ?", id).Update("deleted_at", nil).Error)
+	})
+}
+
+// Purgar elimina definitivamente un registro de la papelera. Falla si otros registros, incluso
+// eliminados, lo usan según e.Dependencias.
+func (r *papeleraRepository) Purgar(entidad string, id uint) error {
+	e, err := BuscarEntidadPapelera(entidad)
+	if err != nil {
+		return err
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := r.eliminado(tx, e, id); err != nil {
+			return err
+		}
+
+		cantidades := map[string]int64{}
+		for _, rel := range e.Dependencias {
+			modelo, err := ModeloRelacion(rel.Entidad)
+			if err != nil {
+				return err
+			}
+			var n int64
+			if err := tx.Unscoped().Model(modelo).Where(r.columna(rel.Columna)+" = ?", id).Count(&n).Error; err != nil {
+				return errores.DesdeBD(err)
+			}
+			if n > 0 {
+				cantidades[rel.Entidad] += n
+			}
+		}
+		if len(cantidades) > 0 {
+			return ErrorDependencias(cantidades)
+		}
+
+		return errores.DesdeBD(tx.Unscoped().Delete(e.nuevo(), id).Error)
+	})
+}
+
+// eliminado carga el registro de la papelera; ErrNoEstaEnPapelera si no existe o no está eliminado
+func (r *papeleraRepository) eliminado(tx *gorm.DB, e EntidadPapelera, id uint) (interface{}, error) {
+	registro := e.nuevo()
+	err := tx.Unscoped().Where("deleted_at IS NOT NULL").First(registro, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNoEstaEnPapelera
+	}
+	if err != nil {
+		return nil, errores.DesdeBD(err)
+	}
+	return registro, nil
+}
+
+// columna convierte el nombre de un campo Go en el nombre de la columna
+func (r *papeleraRepository) columna(campo string) string {
+	return r.db.NamingStrategy.ColumnName("", campo)
+}
+
+// eliminadoEn lee la fecha de eliminación del gorm.Model de un registro
+func eliminadoEn(registro interface{}) time.Time {
+	m, ok := reflect.Indirect(reflect.ValueOf(registro)).FieldByName("Model").Interface().(gorm.Model)
+	if !ok || !m.DeletedAt.Valid {
+		return time.Time{}
+	}
+	return m.DeletedAt.Time
+}
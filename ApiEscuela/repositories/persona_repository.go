@@ -1,52 +1,34 @@
 package repositories
 
 import (
+	"ApiEscuela/errores"
 	"ApiEscuela/models"
-	"errors"
-	"strings"
 
 	"gorm.io/gorm"
 )
 
-type PersonaRepository struct {
+type personaRepository struct {
 	db *gorm.DB
 }
 
 var (
-	ErrCedulaDuplicada = errors.New("cedula repetida")
-	ErrCorreoDuplicado = errors.New("correo repetido")
-	ErrPersonaYaExiste = errors.New("persona ya existe")
+	ErrCedulaDuplicada = errores.Conflicto("duplicate_cedula", "Ya existe una persona con esta cédula")
+	ErrCorreoDuplicado = errores.Conflicto("duplicate_email", "Ya existe una persona con este correo electrónico")
+	ErrPersonaYaExiste = errores.Conflicto("persona_duplicada", "Ya existe una persona con estos datos")
 )
 
 func classifyUniquePersonaError(err error) error {
-	// Intento de detección por mensaje cuando no está disponible pgconn
-	msg := err.Error()
-	if strings.Contains(msg, "duplicate key value") || strings.Contains(msg, "UNIQUE constraint") {
-		if strings.Contains(msg, "cedula") {
-			return ErrCedulaDuplicada
-		}
-		if strings.Contains(msg, "correo") {
-			return ErrCorreoDuplicado
-		}
-		return ErrPersonaYaExiste
-	}
-	if errors.Is(err, gorm.ErrDuplicatedKey) {
-		return ErrPersonaYaExiste
-	}
-	return err
-}
-
-func NewPersonaRepository(db *gorm.DB) *PersonaRepository {
-	return &PersonaRepository{db: db}
+	return traducirDuplicado(err, ErrPersonaYaExiste,
+		restriccionUnica{"cedula", ErrCedulaDuplicada},
+		restriccionUnica{"correo", ErrCorreoDuplicado})
 }
 
-// GetDB retorna la instancia de la base de datos para uso interno
-func (r *PersonaRepository) GetDB() *gorm.DB {
-	return r.db
+func NewPersonaRepository(db *gorm.DB) PersonaRepository {
+	return &personaRepository{db: db}
 }
 
 // CreatePersona crea una nueva persona
-func (r *PersonaRepository) CreatePersona(persona *models.Persona) error {
+func (r *personaRepository) CreatePersona(persona *models.Persona) error {
 	if err := r.db.Create(persona).Error; err != nil {
 		return classifyUniquePersonaError(err)
 	}
@@ -54,7 +36,7 @@ func (r *PersonaRepository) CreatePersona(persona *models.Persona) error {
 }
 
 // GetPersonaByID obtiene una persona por ID
-func (r *PersonaRepository) GetPersonaByID(id uint) (*models.Persona, error) {
+func (r *personaRepository) GetPersonaByID(id uint) (*models.Persona, error) {
 	var persona models.Persona
 	err := r.db.Preload("Estudiantes").Preload("EstudiantesUniv").
 		Preload("AutoridadesUTEQ").Preload("Usuarios").
@@ -66,7 +48,7 @@ func (r *PersonaRepository) GetPersonaByID(id uint) (*models.Persona, error) {
 }
 
 // GetPersonaByCedula obtiene una persona por cédula
-func (r *PersonaRepository) GetPersonaByCedula(cedula string) (*models.Persona, error) {
+func (r *personaRepository) GetPersonaByCedula(cedula string) (*models.Persona, error) {
 	var persona models.Persona
 	err := r.db.Where("cedula = ?", cedula).
 		Preload("Estudiantes").Preload("EstudiantesUniv").
@@ -79,7 +61,7 @@ func (r *PersonaRepository) GetPersonaByCedula(cedula string) (*models.Persona,
 }
 
 // GetAllPersonas obtiene todas las personas
-func (r *PersonaRepository) GetAllPersonas() ([]models.Persona, error) {
+func (r *personaRepository) GetAllPersonas() ([]models.Persona, error) {
 	var personas []models.Persona
 	err := r.db.Preload("Estudiantes").Preload("EstudiantesUniv").
 		Preload("AutoridadesUTEQ").Preload("Usuarios").
@@ -87,21 +69,21 @@ func (r *PersonaRepository) GetAllPersonas() ([]models.Persona, error) {
 	return personas, err
 }
 
-// UpdatePersona actualiza una persona
-func (r *PersonaRepository) UpdatePersona(persona *models.Persona) error {
-	if err := r.db.Save(persona).Error; err != nil {
+// UpdatePersona actualiza una persona; ErrVersionDesactualizada si cambió desde que se leyó
+func (r *personaRepository) UpdatePersona(persona *models.Persona) error {
+	if err := guardarVersion(r.db, persona, persona.ID, persona.UpdatedAt); err != nil {
 		return classifyUniquePersonaError(err)
 	}
 	return nil
 }
 
 // DeletePersona elimina una persona
-func (r *PersonaRepository) DeletePersona(id uint) error {
+func (r *personaRepository) DeletePersona(id uint) error {
 	return r.db.Delete(&models.Persona{}, id).Error
 }
 
 // GetPersonasByCorreo busca personas por correo (búsqueda parcial)
-func (r *PersonaRepository) GetPersonasByCorreo(correo string) ([]models.Persona, error) {
+func (r *personaRepository) GetPersonasByCorreo(correo string) ([]models.Persona, error) {
 	var personas []models.Persona
 	err := r.db.Where("correo ILIKE ?", "%"+correo+"%").
 		Preload("Estudiantes").Preload("EstudiantesUniv").
@@ -109,3 +91,10 @@ func (r *PersonaRepository) GetPersonasByCorreo(correo string) ([]models.Persona
 		Find(&personas).Error
 	return personas, err
 }
+
+// ExistsByID verifica si existe una persona con el ID indicado
+func (r *personaRepository) ExistsByID(id uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Persona{}).Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
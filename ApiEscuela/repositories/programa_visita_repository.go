@@ -6,21 +6,21 @@ import (
 	"gorm.io/gorm"
 )
 
-type ProgramaVisitaRepository struct {
+type programaVisitaRepository struct {
 	db *gorm.DB
 }
 
-func NewProgramaVisitaRepository(db *gorm.DB) *ProgramaVisitaRepository {
-	return &ProgramaVisitaRepository{db: db}
+func NewProgramaVisitaRepository(db *gorm.DB) ProgramaVisitaRepository {
+	return &programaVisitaRepository{db: db}
 }
 
 // CreateProgramaVisita crea un nuevo programa de visita
-func (r *ProgramaVisitaRepository) CreateProgramaVisita(programa *models.ProgramaVisita) error {
+func (r *programaVisitaRepository) CreateProgramaVisita(programa *models.ProgramaVisita) error {
 	return r.db.Create(programa).Error
 }
 
 // GetProgramaVisitaByID obtiene un programa de visita por ID
-func (r *ProgramaVisitaRepository) GetProgramaVisitaByID(id uint) (*models.ProgramaVisita, error) {
+func (r *programaVisitaRepository) GetProgramaVisitaByID(id uint) (*models.ProgramaVisita, error) {
 	var programa models.ProgramaVisita
 	err := r.db.Preload("Institucion").
 		First(&programa, id).Error
@@ -31,25 +31,25 @@ func (r *ProgramaVisitaRepository) GetProgramaVisitaByID(id uint) (*models.Progr
 }
 
 // GetAllProgramasVisita obtiene todos los programas de visita
-func (r *ProgramaVisitaRepository) GetAllProgramasVisita() ([]models.ProgramaVisita, error) {
+func (r *programaVisitaRepository) GetAllProgramasVisita() ([]models.ProgramaVisita, error) {
 	var programas []models.ProgramaVisita
 	err := r.db.Preload("Institucion").
 		Find(&programas).Error
 	return programas, err
 }
 
-// UpdateProgramaVisita actualiza un programa de visita
-func (r *ProgramaVisitaRepository) UpdateProgramaVisita(programa *models.ProgramaVisita) error {
-	return r.db.Save(programa).Error
+// UpdateProgramaVisita actualiza un programa de visita; ErrVersionDesactualizada si cambió desde que se leyó
+func (r *programaVisitaRepository) UpdateProgramaVisita(programa *models.ProgramaVisita) error {
+	return guardarVersion(r.db, programa, programa.ID, programa.UpdatedAt)
 }
 
 // DeleteProgramaVisita elimina un programa de visita
-func (r *ProgramaVisitaRepository) DeleteProgramaVisita(id uint) error {
+func (r *programaVisitaRepository) DeleteProgramaVisita(id uint) error {
 	return r.db.Delete(&models.ProgramaVisita{}, id).Error
 }
 
 // GetProgramasVisitaByFecha obtiene programas por fecha
-func (r *ProgramaVisitaRepository) GetProgramasVisitaByFecha(fecha time.Time) ([]models.ProgramaVisita, error) {
+func (r *programaVisitaRepository) GetProgramasVisitaByFecha(fecha time.Time) ([]models.ProgramaVisita, error) {
 	var programas []models.ProgramaVisita
 	startOfDay := time.Date(fecha.Year(), fecha.Month(), fecha.Day(), 0, 0, 0, 0, fecha.Location())
 	endOfDay := startOfDay.Add(24 * time.Hour)
@@ -61,7 +61,7 @@ func (r *ProgramaVisitaRepository) GetProgramasVisitaByFecha(fecha time.Time) ([
 }
 
 // GetProgramasVisitaByInstitucion obtiene programas por institución
-func (r *ProgramaVisitaRepository) GetProgramasVisitaByInstitucion(institucionID uint) ([]models.ProgramaVisita, error) {
+func (r *programaVisitaRepository) GetProgramasVisitaByInstitucion(institucionID uint) ([]models.ProgramaVisita, error) {
 	var programas []models.ProgramaVisita
 	err := r.db.Where("institucion_id = ?", institucionID).
 		Preload("Institucion").
@@ -70,10 +70,25 @@ func (r *ProgramaVisitaRepository) GetProgramasVisitaByInstitucion(institucionID
 }
 
 // GetProgramasVisitaByRangoFecha obtiene programas en un rango de fechas
-func (r *ProgramaVisitaRepository) GetProgramasVisitaByRangoFecha(fechaInicio, fechaFin time.Time) ([]models.ProgramaVisita, error) {
+func (r *programaVisitaRepository) GetProgramasVisitaByRangoFecha(fechaInicio, fechaFin time.Time) ([]models.ProgramaVisita, error) {
 	var programas []models.ProgramaVisita
 	err := r.db.Where("fecha BETWEEN ? AND ?", fechaInicio, fechaFin).
 		Preload("Institucion").
 		Find(&programas).Error
 	return programas, err
-}
\ No newline at end of file
+}
+
+// GetProgramasSinRecordatorio obtiene los programas que empiezan en el rango y aún no tienen recordatorio enviado
+func (r *programaVisitaRepository) GetProgramasSinRecordatorio(desde, hasta time.Time) ([]models.ProgramaVisita, error) {
+	var programas []models.ProgramaVisita
+	err := r.db.Where("fecha >= ? AND fecha <= ? AND recordatorio_enviado_en IS NULL", desde, hasta).
+		Preload("Institucion").
+		Order("fecha").
+		Find(&programas).Error
+	return programas, err
+}
+
+// MarcarRecordatorioEnviado registra que ya se envió el recordatorio del programa
+func (r *programaVisitaRepository) MarcarRecordatorioEnviado(id uint, enviado time.Time) error {
+	return r.db.Model(&models.ProgramaVisita{}).Where("id = ?", id).Update("recordatorio_enviado_en", enviado).Error
+}
@@ -1,40 +1,29 @@
 package repositories
 
 import (
+	"ApiEscuela/errores"
 	"ApiEscuela/models"
-	"errors"
-	"strings"
 	"gorm.io/gorm"
 )
 
-type EstudianteRepository struct {
+type estudianteRepository struct {
 	db *gorm.DB
 }
 
 var (
-	ErrEstudianteDuplicado = errors.New("estudiante ya existe")
+	ErrEstudianteDuplicado = errores.Conflicto("estudiante_duplicado", "La persona ya tiene un registro de estudiante")
 )
 
 func classifyUniqueEstudianteError(err error) error {
-	msg := err.Error()
-	if strings.Contains(msg, "duplicate key value") || strings.Contains(msg, "UNIQUE constraint") {
-		if strings.Contains(msg, "persona_id") || strings.Contains(msg, "persona") {
-			return ErrEstudianteDuplicado
-		}
-		return ErrEstudianteDuplicado
-	}
-	if errors.Is(err, gorm.ErrDuplicatedKey) {
-		return ErrEstudianteDuplicado
-	}
-	return err
+	return traducirDuplicado(err, ErrEstudianteDuplicado)
 }
 
-func NewEstudianteRepository(db *gorm.DB) *EstudianteRepository {
-	return &EstudianteRepository{db: db}
+func NewEstudianteRepository(db *gorm.DB) EstudianteRepository {
+	return &estudianteRepository{db: db}
 }
 
 // CreateEstudiante crea un nuevo estudiante
-func (r *EstudianteRepository) CreateEstudiante(estudiante *models.Estudiante) error {
+func (r *estudianteRepository) CreateEstudiante(estudiante *models.Estudiante) error {
 	// Prevalidar que no exista estudiante para la misma persona
 	var count int64
 	if err := r.db.Model(&models.Estudiante{}).Where("persona_id = ?", estudiante.PersonaID).Count(&count).Error; err != nil {
@@ -50,7 +39,7 @@ func (r *EstudianteRepository) CreateEstudiante(estudiante *models.Estudiante) e
 }
 
 // GetEstudianteByID obtiene un estudiante por ID
-func (r *EstudianteRepository) GetEstudianteByID(id uint) (*models.Estudiante, error) {
+func (r *estudianteRepository) GetEstudianteByID(id uint) (*models.Estudiante, error) {
 	var estudiante models.Estudiante
 	err := r.db.Preload("Persona").Preload("Institucion").
 		Preload("Ciudad").Preload("Ciudad.Provincia").
@@ -62,7 +51,7 @@ func (r *EstudianteRepository) GetEstudianteByID(id uint) (*models.Estudiante, e
 }
 
 // GetAllEstudiantes obtiene todos los estudiantes
-func (r *EstudianteRepository) GetAllEstudiantes() ([]models.Estudiante, error) {
+func (r *estudianteRepository) GetAllEstudiantes() ([]models.Estudiante, error) {
 	var estudiantes []models.Estudiante
 	err := r.db.Preload("Persona").Preload("Institucion").
 		Preload("Ciudad").Preload("Ciudad.Provincia").
@@ -71,7 +60,7 @@ func (r *EstudianteRepository) GetAllEstudiantes() ([]models.Estudiante, error)
 }
 
 // UpdateEstudiante actualiza un estudiante
-func (r *EstudianteRepository) UpdateEstudiante(estudiante *models.Estudiante) error {
+func (r *estudianteRepository) UpdateEstudiante(estudiante *models.Estudiante) error {
 	// Verificar duplicado por persona en otro registro
 	var count int64
 	if err := r.db.Model(&models.Estudiante{}).Where("persona_id = ? AND id <> ?", estudiante.PersonaID, estudiante.ID).Count(&count).Error; err != nil {
@@ -87,7 +76,7 @@ func (r *EstudianteRepository) UpdateEstudiante(estudiante *models.Estudiante) e
 }
 
 // DeleteEstudiante elimina un estudiante y en cascada su usuario y persona
-func (r *EstudianteRepository) DeleteEstudiante(id uint) error {
+func (r *estudianteRepository) DeleteEstudiante(id uint) error {
 	// Iniciar transacción
 	tx := r.db.Begin()
 	if tx.Error != nil {
@@ -128,7 +117,7 @@ func (r *EstudianteRepository) DeleteEstudiante(id uint) error {
 }
 
 // RestoreEstudiante restaura un estudiante eliminado y en cascada su usuario y persona
-func (r *EstudianteRepository) RestoreEstudiante(id uint) error {
+func (r *estudianteRepository) RestoreEstudiante(id uint) error {
 	// Iniciar transacción
 	tx := r.db.Begin()
 	if tx.Error != nil {
@@ -169,7 +158,7 @@ func (r *EstudianteRepository) RestoreEstudiante(id uint) error {
 }
 
 // GetAllEstudiantesIncludingDeleted obtiene todos los estudiantes incluyendo los eliminados
-func (r *EstudianteRepository) GetAllEstudiantesIncludingDeleted() ([]models.Estudiante, error) {
+func (r *estudianteRepository) GetAllEstudiantesIncludingDeleted() ([]models.Estudiante, error) {
 	var estudiantes []models.Estudiante
 	err := r.db.Unscoped().Preload("Persona").Preload("Institucion").
 		Preload("Ciudad").Preload("Ciudad.Provincia").
@@ -178,7 +167,7 @@ func (r *EstudianteRepository) GetAllEstudiantesIncludingDeleted() ([]models.Est
 }
 
 // GetDeletedEstudiantes obtiene solo los estudiantes eliminados
-func (r *EstudianteRepository) GetDeletedEstudiantes() ([]models.Estudiante, error) {
+func (r *estudianteRepository) GetDeletedEstudiantes() ([]models.Estudiante, error) {
 	var estudiantes []models.Estudiante
 	err := r.db.Unscoped().Where("deleted_at IS NOT NULL").
 		Preload("Persona").Preload("Institucion").
@@ -188,7 +177,7 @@ func (r *EstudianteRepository) GetDeletedEstudiantes() ([]models.Estudiante, err
 }
 
 // GetEstudiantesByCity obtiene estudiantes por ciudad
-func (r *EstudianteRepository) GetEstudiantesByCity(ciudadID uint) ([]models.Estudiante, error) {
+func (r *estudianteRepository) GetEstudiantesByCity(ciudadID uint) ([]models.Estudiante, error) {
 	var estudiantes []models.Estudiante
 	err := r.db.Where("ciudad_id = ?", ciudadID).
 		Preload("Persona").Preload("Institucion").
@@ -198,7 +187,7 @@ func (r *EstudianteRepository) GetEstudiantesByCity(ciudadID uint) ([]models.Est
 }
 
 // GetEstudiantesByInstitucion obtiene estudiantes por institución
-func (r *EstudianteRepository) GetEstudiantesByInstitucion(institucionID uint) ([]models.Estudiante, error) {
+func (r *estudianteRepository) GetEstudiantesByInstitucion(institucionID uint) ([]models.Estudiante, error) {
 	var estudiantes []models.Estudiante
 	err := r.db.Where("institucion_id = ?", institucionID).
 		Preload("Persona").Preload("Institucion").
@@ -208,11 +197,18 @@ func (r *EstudianteRepository) GetEstudiantesByInstitucion(institucionID uint) (
 }
 
 // GetEstudiantesByEspecialidad obtiene estudiantes por especialidad
-func (r *EstudianteRepository) GetEstudiantesByEspecialidad(especialidad string) ([]models.Estudiante, error) {
+func (r *estudianteRepository) GetEstudiantesByEspecialidad(especialidad string) ([]models.Estudiante, error) {
 	var estudiantes []models.Estudiante
 	err := r.db.Where("especialidad ILIKE ?", "%"+especialidad+"%").
 		Preload("Persona").Preload("Institucion").
 		Preload("Ciudad").Preload("Ciudad.Provincia").
 		Find(&estudiantes).Error
 	return estudiantes, err
-}
\ No newline at end of file
+}
+
+// ExistsByID verifica si existe un estudiante con el ID indicado
+func (r *estudianteRepository) ExistsByID(id uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Estudiante{}).Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
@@ -5,21 +5,21 @@ import (
 	"gorm.io/gorm"
 )
 
-type TematicaRepository struct {
+type tematicaRepository struct {
 	db *gorm.DB
 }
 
-func NewTematicaRepository(db *gorm.DB) *TematicaRepository {
-	return &TematicaRepository{db: db}
+func NewTematicaRepository(db *gorm.DB) TematicaRepository {
+	return &tematicaRepository{db: db}
 }
 
 // CreateTematica crea una nueva temática
-func (r *TematicaRepository) CreateTematica(tematica *models.Tematica) error {
+func (r *tematicaRepository) CreateTematica(tematica *models.Tematica) error {
 	return r.db.Create(tematica).Error
 }
 
 // GetTematicaByID obtiene una temática por ID
-func (r *TematicaRepository) GetTematicaByID(id uint) (*models.Tematica, error) {
+func (r *tematicaRepository) GetTematicaByID(id uint) (*models.Tematica, error) {
 	var tematica models.Tematica
 	err := r.db.Preload("Actividades").First(&tematica, id).Error
 	if err != nil {
@@ -29,34 +29,39 @@ func (r *TematicaRepository) GetTematicaByID(id uint) (*models.Tematica, error)
 }
 
 // GetAllTematicas obtiene todas las temáticas
-func (r *TematicaRepository) GetAllTematicas() ([]models.Tematica, error) {
+func (r *tematicaRepository) GetAllTematicas() ([]models.Tematica, error) {
 	var tematicas []models.Tematica
 	err := r.db.Preload("Actividades").Find(&tematicas).Error
 	return tematicas, err
 }
 
 // UpdateTematica actualiza una temática
-func (r *TematicaRepository) UpdateTematica(tematica *models.Tematica) error {
+func (r *tematicaRepository) UpdateTematica(tematica *models.Tematica) error {
 	return r.db.Save(tematica).Error
 }
 
 // DeleteTematica elimina una temática
-func (r *TematicaRepository) DeleteTematica(id uint) error {
+func (r *tematicaRepository) DeleteTematica(id uint) error {
 	return r.db.Delete(&models.Tematica{}, id).Error
 }
 
-// GetTematicasByNombre busca temáticas por nombre
-func (r *TematicaRepository) GetTematicasByNombre(nombre string) ([]models.Tematica, error) {
-	var tematicas []models.Tematica
-	err := r.db.Where("nombre ILIKE ?", "%"+nombre+"%").
-		Preload("Actividades").Find(&tematicas).Error
-	return tematicas, err
+// GetTematicasByNombre busca temáticas por las palabras del nombre, sin distinguir tildes
+func (r *tematicaRepository) GetTematicasByNombre(nombre string) ([]ResultadoBusqueda[models.Tematica], error) {
+	return buscarTexto[models.Tematica](r.db.Preload("Actividades"),
+		ConsultaTexto(nombre, "A"), "concat_ws(' — ', nombre, descripcion)")
 }
 
 // GetTematicasByDescripcion busca temáticas por descripción
-func (r *TematicaRepository) GetTematicasByDescripcion(descripcion string) ([]models.Tematica, error) {
+func (r *tematicaRepository) GetTematicasByDescripcion(descripcion string) ([]models.Tematica, error) {
 	var tematicas []models.Tematica
 	err := r.db.Where("descripcion ILIKE ?", "%"+descripcion+"%").
 		Preload("Actividades").Find(&tematicas).Error
 	return tematicas, err
-}
\ No newline at end of file
+}
+
+// ExistsByID verifica si existe una temática con el ID indicado
+func (r *tematicaRepository) ExistsByID(id uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Tematica{}).Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
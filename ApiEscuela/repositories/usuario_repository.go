@@ -1,41 +1,30 @@
 package repositories
 
 import (
+	"ApiEscuela/errores"
 	"ApiEscuela/models"
-	"errors"
-	"strings"
 
 	"gorm.io/gorm"
 )
 
-type UsuarioRepository struct {
+type usuarioRepository struct {
 	db *gorm.DB
 }
 
 var (
-	ErrUsuarioDuplicado = errors.New("usuario repetido")
+	ErrUsuarioDuplicado = errores.Conflicto("usuario_duplicado", "Ya existe un usuario con este nombre")
 )
 
 func classifyUniqueUsuarioError(err error) error {
-	// Detección por mensaje si no está disponible pgconn
-	msg := err.Error()
-	if strings.Contains(msg, "duplicate key value") || strings.Contains(msg, "UNIQUE constraint") {
-		if strings.Contains(msg, "usuario") {
-			return ErrUsuarioDuplicado
-		}
-	}
-	if errors.Is(err, gorm.ErrDuplicatedKey) {
-		return ErrUsuarioDuplicado
-	}
-	return err
+	return traducirDuplicado(err, ErrUsuarioDuplicado)
 }
 
-func NewUsuarioRepository(db *gorm.DB) *UsuarioRepository {
-	return &UsuarioRepository{db: db}
+func NewUsuarioRepository(db *gorm.DB) UsuarioRepository {
+	return &usuarioRepository{db: db}
 }
 
 // CreateUsuario crea un nuevo usuario
-func (r *UsuarioRepository) CreateUsuario(usuario *models.Usuario) error {
+func (r *usuarioRepository) CreateUsuario(usuario *models.Usuario) error {
 	if err := r.db.Create(usuario).Error; err != nil {
 		return classifyUniqueUsuarioError(err)
 	}
@@ -43,7 +32,7 @@ func (r *UsuarioRepository) CreateUsuario(usuario *models.Usuario) error {
 }
 
 // GetUsuarioByID obtiene un usuario por ID
-func (r *UsuarioRepository) GetUsuarioByID(id uint) (*models.Usuario, error) {
+func (r *usuarioRepository) GetUsuarioByID(id uint) (*models.Usuario, error) {
 	var usuario models.Usuario
 	err := r.db.Preload("Persona").Preload("TipoUsuario").
 		First(&usuario, id).Error
@@ -54,7 +43,7 @@ func (r *UsuarioRepository) GetUsuarioByID(id uint) (*models.Usuario, error) {
 }
 
 // GetAllUsuarios obtiene todos los usuarios
-func (r *UsuarioRepository) GetAllUsuarios() ([]models.Usuario, error) {
+func (r *usuarioRepository) GetAllUsuarios() ([]models.Usuario, error) {
 	var usuarios []models.Usuario
 	err := r.db.Preload("Persona").Preload("TipoUsuario").
 		Find(&usuarios).Error
@@ -62,7 +51,7 @@ func (r *UsuarioRepository) GetAllUsuarios() ([]models.Usuario, error) {
 }
 
 // UpdateUsuario actualiza un usuario
-func (r *UsuarioRepository) UpdateUsuario(usuario *models.Usuario) error {
+func (r *usuarioRepository) UpdateUsuario(usuario *models.Usuario) error {
 	if err := r.db.Save(usuario).Error; err != nil {
 		return classifyUniqueUsuarioError(err)
 	}
@@ -70,12 +59,12 @@ func (r *UsuarioRepository) UpdateUsuario(usuario *models.Usuario) error {
 }
 
 // DeleteUsuario elimina un usuario
-func (r *UsuarioRepository) DeleteUsuario(id uint) error {
+func (r *usuarioRepository) DeleteUsuario(id uint) error {
 	return r.db.Delete(&models.Usuario{}, id).Error
 }
 
 // GetUsuarioByUsername busca usuario por nombre de usuario (solo activos)
-func (r *UsuarioRepository) GetUsuarioByUsername(username string) (*models.Usuario, error) {
+func (r *usuarioRepository) GetUsuarioByUsername(username string) (*models.Usuario, error) {
 	var usuario models.Usuario
 	err := r.db.Where("usuario = ? AND deleted_at IS NULL", username).
 		Preload("Persona").Preload("TipoUsuario").
@@ -87,7 +76,7 @@ func (r *UsuarioRepository) GetUsuarioByUsername(username string) (*models.Usuar
 }
 
 // GetUsuariosByTipo obtiene usuarios por tipo
-func (r *UsuarioRepository) GetUsuariosByTipo(tipoUsuarioID uint) ([]models.Usuario, error) {
+func (r *usuarioRepository) GetUsuariosByTipo(tipoUsuarioID uint) ([]models.Usuario, error) {
 	var usuarios []models.Usuario
 	err := r.db.Where("tipo_usuario_id = ?", tipoUsuarioID).
 		Preload("Persona").Preload("TipoUsuario").
@@ -96,7 +85,7 @@ func (r *UsuarioRepository) GetUsuariosByTipo(tipoUsuarioID uint) ([]models.Usua
 }
 
 // GetUsuariosByPersona obtiene usuarios por persona
-func (r *UsuarioRepository) GetUsuariosByPersona(personaID uint) ([]models.Usuario, error) {
+func (r *usuarioRepository) GetUsuariosByPersona(personaID uint) ([]models.Usuario, error) {
 	var usuarios []models.Usuario
 	err := r.db.Where("persona_id = ?", personaID).
 		Preload("Persona").Preload("TipoUsuario").
@@ -104,8 +93,20 @@ func (r *UsuarioRepository) GetUsuariosByPersona(personaID uint) ([]models.Usuar
 	return usuarios, err
 }
 
+// GetUsuariosByPersonas obtiene los usuarios de varias personas
+func (r *usuarioRepository) GetUsuariosByPersonas(personaIDs []uint) ([]models.Usuario, error) {
+	usuarios := []models.Usuario{}
+	if len(personaIDs) == 0 {
+		return usuarios, nil
+	}
+	err := r.db.Where("persona_id IN ?", personaIDs).
+		Preload("Persona").Preload("TipoUsuario").
+		Find(&usuarios).Error
+	return usuarios, err
+}
+
 // ValidateLogin valida credenciales de usuario
-func (r *UsuarioRepository) ValidateLogin(username, password string) (*models.Usuario, error) {
+func (r *usuarioRepository) ValidateLogin(username, password string) (*models.Usuario, error) {
 	var usuario models.Usuario
 	err := r.db.Where("usuario = ? AND contraseña = ?", username, password).
 		Preload("Persona").Preload("TipoUsuario").
@@ -117,7 +118,7 @@ func (r *UsuarioRepository) ValidateLogin(username, password string) (*models.Us
 }
 
 // GetAllUsuariosIncludingDeleted obtiene todos los usuarios incluyendo los eliminados
-func (r *UsuarioRepository) GetAllUsuariosIncludingDeleted() ([]models.Usuario, error) {
+func (r *usuarioRepository) GetAllUsuariosIncludingDeleted() ([]models.Usuario, error) {
 	var usuarios []models.Usuario
 	err := r.db.Unscoped().Preload("Persona").Preload("TipoUsuario").
 		Find(&usuarios).Error
@@ -125,7 +126,7 @@ func (r *UsuarioRepository) GetAllUsuariosIncludingDeleted() ([]models.Usuario,
 }
 
 // GetDeletedUsuarios obtiene solo los usuarios eliminados
-func (r *UsuarioRepository) GetDeletedUsuarios() ([]models.Usuario, error) {
+func (r *usuarioRepository) GetDeletedUsuarios() ([]models.Usuario, error) {
 	var usuarios []models.Usuario
 	err := r.db.Unscoped().Where("deleted_at IS NOT NULL").
 		Preload("Persona").Preload("TipoUsuario").
@@ -134,13 +135,13 @@ func (r *UsuarioRepository) GetDeletedUsuarios() ([]models.Usuario, error) {
 }
 
 // RestoreUsuario restaura un usuario eliminado (soft delete)
-func (r *UsuarioRepository) RestoreUsuario(id uint) error {
+func (r *usuarioRepository) RestoreUsuario(id uint) error {
 	return r.db.Unscoped().Model(&models.Usuario{}).
 		Where("id = ?", id).Update("deleted_at", nil).Error
 }
 
 // GetUsuarioByIDIncludingDeleted obtiene un usuario por ID incluyendo eliminados
-func (r *UsuarioRepository) GetUsuarioByIDIncludingDeleted(id uint) (*models.Usuario, error) {
+func (r *usuarioRepository) GetUsuarioByIDIncludingDeleted(id uint) (*models.Usuario, error) {
 	var usuario models.Usuario
 	err := r.db.Unscoped().Preload("Persona").Preload("TipoUsuario").
 		First(&usuario, id).Error
@@ -151,7 +152,7 @@ func (r *UsuarioRepository) GetUsuarioByIDIncludingDeleted(id uint) (*models.Usu
 }
 
 // GetUsuarioByUsernameIncludingDeleted busca usuario por nombre incluyendo eliminados (para debugging)
-func (r *UsuarioRepository) GetUsuarioByUsernameIncludingDeleted(username string) (*models.Usuario, error) {
+func (r *usuarioRepository) GetUsuarioByUsernameIncludingDeleted(username string) (*models.Usuario, error) {
 	var usuario models.Usuario
 	err := r.db.Unscoped().Where("usuario = ?", username).
 		Preload("Persona").Preload("TipoUsuario").
@@ -163,6 +164,13 @@ func (r *UsuarioRepository) GetUsuarioByUsernameIncludingDeleted(username string
 }
 
 // UpdatePassword actualiza la contraseña de un usuario
-func (r *UsuarioRepository) UpdatePassword(usuarioID uint, nuevaClave string) error {
+func (r *usuarioRepository) UpdatePassword(usuarioID uint, nuevaClave string) error {
 	return r.db.Model(&models.Usuario{}).Where("id = ?", usuarioID).Update("contraseña", nuevaClave).Error
 }
+
+// ExistsByID verifica si existe un usuario con el ID indicado
+func (r *usuarioRepository) ExistsByID(id uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&models.Usuario{}).Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"time"
+
+	"ApiEscuela/errores"
+
+	"gorm.io/gorm"
+)
+
+// ErrVersionDesactualizada se devuelve al guardar un registro que otro usuario modificó desde que se leyó
+var ErrVersionDesactualizada = errores.Precondicion("version_desactualizada", "Otro usuario modificó el registro desde que se leyó")
+
+// guardarVersion guarda todas las columnas del registro solo si su updated_at sigue siendo leido,
+// el valor con que se cargó (bloqueo optimista). Un UpdatedAt vacío guarda sin comprobar.
+func guardarVersion(db *gorm.DB, registro interface{}, id uint, leido time.Time) error {
+	if leido.IsZero() {
+		return db.Save(registro).Error
+	}
+	res := db.Model(registro).Where("updated_at = ?", leido).Select("*").Updates(registro)
+	if res.Error != nil || res.RowsAffected > 0 {
+		return res.Error
+	}
+	// Ninguna fila coincidió: el registro cambió o ya no existe
+	var n int64
+	if err := db.Model(registro).Where("id = ?", id).Count(&n).Error; err != nil {
+		return err
+	}
+	if n == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return ErrVersionDesactualizada
+}
@@ -5,21 +5,21 @@ import (
 	"gorm.io/gorm"
 )
 
-type VisitaDetalleEstudiantesUniversitariosRepository struct {
+type visitaDetalleEstudiantesUniversitariosRepository struct {
 	db *gorm.DB
 }
 
-func NewVisitaDetalleEstudiantesUniversitariosRepository(db *gorm.DB) *VisitaDetalleEstudiantesUniversitariosRepository {
-	return &VisitaDetalleEstudiantesUniversitariosRepository{db: db}
+func NewVisitaDetalleEstudiantesUniversitariosRepository(db *gorm.DB) VisitaDetalleEstudiantesUniversitariosRepository {
+	return &visitaDetalleEstudiantesUniversitariosRepository{db: db}
 }
 
 // CreateVisitaDetalleEstudiantesUniversitarios crea una nueva relación entre estudiante universitario y programa de visita
-func (r *VisitaDetalleEstudiantesUniversitariosRepository) CreateVisitaDetalleEstudiantesUniversitarios(relacion *models.VisitaDetalleEstudiantesUniversitarios) error {
+func (r *visitaDetalleEstudiantesUniversitariosRepository) CreateVisitaDetalleEstudiantesUniversitarios(relacion *models.VisitaDetalleEstudiantesUniversitarios) error {
 	return r.db.Create(relacion).Error
 }
 
 // GetVisitaDetalleEstudiantesUniversitariosByID obtiene una relación por ID
-func (r *VisitaDetalleEstudiantesUniversitariosRepository) GetVisitaDetalleEstudiantesUniversitariosByID(id uint) (*models.VisitaDetalleEstudiantesUniversitarios, error) {
+func (r *visitaDetalleEstudiantesUniversitariosRepository) GetVisitaDetalleEstudiantesUniversitariosByID(id uint) (*models.VisitaDetalleEstudiantesUniversitarios, error) {
 	var relacion models.VisitaDetalleEstudiantesUniversitarios
 	err := r.db.Preload("EstudianteUniversitario").
 		Preload("ProgramaVisita").
@@ -32,7 +32,7 @@ func (r *VisitaDetalleEstudiantesUniversitariosRepository) GetVisitaDetalleEstud
 }
 
 // GetAllVisitaDetalleEstudiantesUniversitarios obtiene todas las relaciones
-func (r *VisitaDetalleEstudiantesUniversitariosRepository) GetAllVisitaDetalleEstudiantesUniversitarios() ([]models.VisitaDetalleEstudiantesUniversitarios, error) {
+func (r *visitaDetalleEstudiantesUniversitariosRepository) GetAllVisitaDetalleEstudiantesUniversitarios() ([]models.VisitaDetalleEstudiantesUniversitarios, error) {
 	var relaciones []models.VisitaDetalleEstudiantesUniversitarios
 	err := r.db.Preload("EstudianteUniversitario").
 		Preload("ProgramaVisita").
@@ -42,17 +42,17 @@ func (r *VisitaDetalleEstudiantesUniversitariosRepository) GetAllVisitaDetalleEs
 }
 
 // UpdateVisitaDetalleEstudiantesUniversitarios actualiza una relación
-func (r *VisitaDetalleEstudiantesUniversitariosRepository) UpdateVisitaDetalleEstudiantesUniversitarios(relacion *models.VisitaDetalleEstudiantesUniversitarios) error {
+func (r *visitaDetalleEstudiantesUniversitariosRepository) UpdateVisitaDetalleEstudiantesUniversitarios(relacion *models.VisitaDetalleEstudiantesUniversitarios) error {
 	return r.db.Save(relacion).Error
 }
 
 // DeleteVisitaDetalleEstudiantesUniversitarios elimina una relación
-func (r *VisitaDetalleEstudiantesUniversitariosRepository) DeleteVisitaDetalleEstudiantesUniversitarios(id uint) error {
+func (r *visitaDetalleEstudiantesUniversitariosRepository) DeleteVisitaDetalleEstudiantesUniversitarios(id uint) error {
 	return r.db.Delete(&models.VisitaDetalleEstudiantesUniversitarios{}, id).Error
 }
 
 // GetEstudiantesByProgramaVisita obtiene estudiantes por programa de visita
-func (r *VisitaDetalleEstudiantesUniversitariosRepository) GetEstudiantesByProgramaVisita(programaVisitaID uint) ([]models.VisitaDetalleEstudiantesUniversitarios, error) {
+func (r *visitaDetalleEstudiantesUniversitariosRepository) GetEstudiantesByProgramaVisita(programaVisitaID uint) ([]models.VisitaDetalleEstudiantesUniversitarios, error) {
 	var relaciones []models.VisitaDetalleEstudiantesUniversitarios
 	err := r.db.Where("programa_visita_id = ?", programaVisitaID).
 		Preload("EstudianteUniversitario").
@@ -63,7 +63,7 @@ func (r *VisitaDetalleEstudiantesUniversitariosRepository) GetEstudiantesByProgr
 }
 
 // GetProgramasVisitaByEstudiante obtiene programas de visita por estudiante universitario
-func (r *VisitaDetalleEstudiantesUniversitariosRepository) GetProgramasVisitaByEstudiante(estudianteID uint) ([]models.VisitaDetalleEstudiantesUniversitarios, error) {
+func (r *visitaDetalleEstudiantesUniversitariosRepository) GetProgramasVisitaByEstudiante(estudianteID uint) ([]models.VisitaDetalleEstudiantesUniversitarios, error) {
 	var relaciones []models.VisitaDetalleEstudiantesUniversitarios
 	err := r.db.Where("estudiante_universitario_id = ?", estudianteID).
 		Preload("EstudianteUniversitario").
@@ -74,17 +74,17 @@ func (r *VisitaDetalleEstudiantesUniversitariosRepository) GetProgramasVisitaByE
 }
 
 // DeleteByProgramaVisita elimina todas las relaciones de un programa de visita específico
-func (r *VisitaDetalleEstudiantesUniversitariosRepository) DeleteByProgramaVisita(programaVisitaID uint) error {
+func (r *visitaDetalleEstudiantesUniversitariosRepository) DeleteByProgramaVisita(programaVisitaID uint) error {
 	return r.db.Where("programa_visita_id = ?", programaVisitaID).Delete(&models.VisitaDetalleEstudiantesUniversitarios{}).Error
 }
 
 // DeleteByEstudiante elimina todas las relaciones de un estudiante específico
-func (r *VisitaDetalleEstudiantesUniversitariosRepository) DeleteByEstudiante(estudianteID uint) error {
+func (r *visitaDetalleEstudiantesUniversitariosRepository) DeleteByEstudiante(estudianteID uint) error {
 	return r.db.Where("estudiante_universitario_id = ?", estudianteID).Delete(&models.VisitaDetalleEstudiantesUniversitarios{}).Error
 }
 
 // ExistsRelation verifica si ya existe una relación entre estudiante y programa de visita
-func (r *VisitaDetalleEstudiantesUniversitariosRepository) ExistsRelation(estudianteID, programaVisitaID uint) (bool, error) {
+func (r *visitaDetalleEstudiantesUniversitariosRepository) ExistsRelation(estudianteID, programaVisitaID uint) (bool, error) {
 	var count int64
 	err := r.db.Model(&models.VisitaDetalleEstudiantesUniversitarios{}).
 		Where("estudiante_universitario_id = ? AND programa_visita_id = ?", estudianteID, programaVisitaID).
@@ -93,7 +93,7 @@ func (r *VisitaDetalleEstudiantesUniversitariosRepository) ExistsRelation(estudi
 }
 
 // GetEstadisticasParticipacion obtiene estadísticas de participación de estudiantes
-func (r *VisitaDetalleEstudiantesUniversitariosRepository) GetEstadisticasParticipacion() (map[string]interface{}, error) {
+func (r *visitaDetalleEstudiantesUniversitariosRepository) GetEstadisticasParticipacion() (map[string]interface{}, error) {
 	var totalRelaciones int64
 	var totalEstudiantesUnicos int64
 	var totalProgramasUnicos int64
@@ -5,21 +5,21 @@ import (
 	"gorm.io/gorm"
 )
 
-type VisitaDetalleRepository struct {
+type visitaDetalleRepository struct {
 	db *gorm.DB
 }
 
-func NewVisitaDetalleRepository(db *gorm.DB) *VisitaDetalleRepository {
-	return &VisitaDetalleRepository{db: db}
+func NewVisitaDetalleRepository(db *gorm.DB) VisitaDetalleRepository {
+	return &visitaDetalleRepository{db: db}
 }
 
 // CreateVisitaDetalle crea un nuevo detalle de visita
-func (r *VisitaDetalleRepository) CreateVisitaDetalle(detalle *models.VisitaDetalle) error {
+func (r *visitaDetalleRepository) CreateVisitaDetalle(detalle *models.VisitaDetalle) error {
 	return r.db.Create(detalle).Error
 }
 
 // GetVisitaDetalleByID obtiene un detalle de visita por ID
-func (r *VisitaDetalleRepository) GetVisitaDetalleByID(id uint) (*models.VisitaDetalle, error) {
+func (r *visitaDetalleRepository) GetVisitaDetalleByID(id uint) (*models.VisitaDetalle, error) {
 	var detalle models.VisitaDetalle
 	err := r.db.Preload("ProgramaVisita").
 		Preload("ProgramaVisita.Institucion").
@@ -32,7 +32,7 @@ func (r *VisitaDetalleRepository) GetVisitaDetalleByID(id uint) (*models.VisitaD
 }
 
 // GetAllVisitaDetalles obtiene todos los detalles de visita
-func (r *VisitaDetalleRepository) GetAllVisitaDetalles() ([]models.VisitaDetalle, error) {
+func (r *visitaDetalleRepository) GetAllVisitaDetalles() ([]models.VisitaDetalle, error) {
 	var detalles []models.VisitaDetalle
 	err := r.db.Preload("ProgramaVisita").
 		Preload("ProgramaVisita.Institucion").
@@ -42,17 +42,17 @@ func (r *VisitaDetalleRepository) GetAllVisitaDetalles() ([]models.VisitaDetalle
 }
 
 // UpdateVisitaDetalle actualiza un detalle de visita
-func (r *VisitaDetalleRepository) UpdateVisitaDetalle(detalle *models.VisitaDetalle) error {
+func (r *visitaDetalleRepository) UpdateVisitaDetalle(detalle *models.VisitaDetalle) error {
 	return r.db.Save(detalle).Error
 }
 
 // DeleteVisitaDetalle elimina un detalle de visita
-func (r *VisitaDetalleRepository) DeleteVisitaDetalle(id uint) error {
+func (r *visitaDetalleRepository) DeleteVisitaDetalle(id uint) error {
 	return r.db.Delete(&models.VisitaDetalle{}, id).Error
 }
 
 // GetVisitaDetallesByActividad obtiene detalles por actividad
-func (r *VisitaDetalleRepository) GetVisitaDetallesByActividad(actividadID uint) ([]models.VisitaDetalle, error) {
+func (r *visitaDetalleRepository) GetVisitaDetallesByActividad(actividadID uint) ([]models.VisitaDetalle, error) {
 	var detalles []models.VisitaDetalle
 	err := r.db.Where("actividad_id = ?", actividadID).
 		Preload("ProgramaVisita").
@@ -63,7 +63,7 @@ func (r *VisitaDetalleRepository) GetVisitaDetallesByActividad(actividadID uint)
 }
 
 // GetVisitaDetallesByPrograma obtiene detalles por programa de visita
-func (r *VisitaDetalleRepository) GetVisitaDetallesByPrograma(programaID uint) ([]models.VisitaDetalle, error) {
+func (r *visitaDetalleRepository) GetVisitaDetallesByPrograma(programaID uint) ([]models.VisitaDetalle, error) {
 	var detalles []models.VisitaDetalle
 	err := r.db.Where("programa_visita_id = ?", programaID).
 		Preload("ProgramaVisita").
@@ -74,17 +74,17 @@ func (r *VisitaDetalleRepository) GetVisitaDetallesByPrograma(programaID uint) (
 }
 
 // DeleteVisitaDetallesByPrograma elimina todos los detalles de un programa de visita específico
-func (r *VisitaDetalleRepository) DeleteVisitaDetallesByPrograma(programaID uint) error {
+func (r *visitaDetalleRepository) DeleteVisitaDetallesByPrograma(programaID uint) error {
 	return r.db.Where("programa_visita_id = ?", programaID).Delete(&models.VisitaDetalle{}).Error
 }
 
 // DeleteVisitaDetallesByActividad elimina todos los detalles de una actividad específica
-func (r *VisitaDetalleRepository) DeleteVisitaDetallesByActividad(actividadID uint) error {
+func (r *visitaDetalleRepository) DeleteVisitaDetallesByActividad(actividadID uint) error {
 	return r.db.Where("actividad_id = ?", actividadID).Delete(&models.VisitaDetalle{}).Error
 }
 
 // ExistsRelation verifica si ya existe una relación entre programa de visita y actividad
-func (r *VisitaDetalleRepository) ExistsRelation(programaVisitaID, actividadID uint) (bool, error) {
+func (r *visitaDetalleRepository) ExistsRelation(programaVisitaID, actividadID uint) (bool, error) {
 	var count int64
 	err := r.db.Model(&models.VisitaDetalle{}).
 		Where("programa_visita_id = ? AND actividad_id = ?", programaVisitaID, actividadID).
@@ -93,7 +93,7 @@ func (r *VisitaDetalleRepository) ExistsRelation(programaVisitaID, actividadID u
 }
 
 // GetEstadisticasActividades obtiene estadísticas de actividades en visitas
-func (r *VisitaDetalleRepository) GetEstadisticasActividades() (map[string]interface{}, error) {
+func (r *visitaDetalleRepository) GetEstadisticasActividades() (map[string]interface{}, error) {
 	var totalDetalles int64
 	var totalActividadesUnicas int64
 	var totalProgramasUnicos int64
@@ -0,0 +1,113 @@
+package repositories
+
+import (
+	"ApiEscuela/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Estados de las entregas de webhooks
+const (
+	EntregaPendiente = "pendiente"
+	EntregaEntregada = "entregada"
+	EntregaFallida   = "fallida"
+)
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+// CreateWebhook guarda una suscripción nueva
+func (r *webhookRepository) CreateWebhook(webhook *models.Webhook) error {
+	return r.db.Create(webhook).Error
+}
+
+// GetWebhookByID obtiene una suscripción por su ID
+func (r *webhookRepository) GetWebhookByID(id uint) (*models.Webhook, error) {
+	var webhook models.Webhook
+	if err := r.db.First(&webhook, id).Error; err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// GetAllWebhooks obtiene todas las suscripciones
+func (r *webhookRepository) GetAllWebhooks() ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+	err := r.db.Order("id").Find(&webhooks).Error
+	return webhooks, err
+}
+
+// GetWebhooksActivos obtiene las suscripciones activas
+func (r *webhookRepository) GetWebhooksActivos() ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+	err := r.db.Where("activo = ?", true).Order("id").Find(&webhooks).Error
+	return webhooks, err
+}
+
+// UpdateWebhook guarda los cambios de una suscripción
+func (r *webhookRepository) UpdateWebhook(webhook *models.Webhook) error {
+	return r.db.Save(webhook).Error
+}
+
+// DeleteWebhook elimina lógicamente una suscripción; su registro de entregas se conserva
+func (r *webhookRepository) DeleteWebhook(id uint) error {
+	return r.db.Delete(&models.Webhook{}, id).Error
+}
+
+// CreateEntrega guarda una entrega nueva
+func (r *webhookRepository) CreateEntrega(entrega *models.EntregaWebhook) error {
+	return r.db.Create(entrega).Error
+}
+
+// GetEntregaByID obtiene una entrega por su ID
+func (r *webhookRepository) GetEntregaByID(id uint) (*models.EntregaWebhook, error) {
+	var entrega models.EntregaWebhook
+	if err := r.db.First(&entrega, id).Error; err != nil {
+		return nil, err
+	}
+	return &entrega, nil
+}
+
+// GetEntregasByWebhook obtiene las entregas de una suscripción, de la más reciente a la más
+// antigua; limite <= 0 no limita la cantidad
+func (r *webhookRepository) GetEntregasByWebhook(webhookID uint, limite int) ([]models.EntregaWebhook, error) {
+	var entregas []models.EntregaWebhook
+	query := r.db.Where("webhook_id = ?", webhookID).Order("id DESC")
+	if limite > 0 {
+		query = query.Limit(limite)
+	}
+	err := query.Find(&entregas).Error
+	return entregas, err
+}
+
+// GetEntregasPendientes obtiene las entregas pendientes cuyo próximo intento ya venció, de la más
+// atrasada a la más reciente; limite <= 0 no limita la cantidad
+func (r *webhookRepository) GetEntregasPendientes(ahora time.Time, limite int) ([]models.EntregaWebhook, error) {
+	var entregas []models.EntregaWebhook
+	query := r.db.Where("estado = ? AND proximo_intento <= ?", EntregaPendiente, ahora).Order("proximo_intento, id")
+	if limite > 0 {
+		query = query.Limit(limite)
+	}
+	err := query.Find(&entregas).Error
+	return entregas, err
+}
+
+// ReclamarEntrega aplaza hasta la fecha indicada el próximo intento de una entrega pendiente y
+// vencida, para que un solo proceso la envíe. Devuelve false si otro ya la reclamó.
+func (r *webhookRepository) ReclamarEntrega(id uint, ahora, hasta time.Time) (bool, error) {
+	result := r.db.Model(&models.EntregaWebhook{}).
+		Where("id = ? AND estado = ? AND proximo_intento <= ?", id, EntregaPendiente, ahora).
+		Update("proximo_intento", hasta)
+	return result.RowsAffected == 1, result.Error
+}
+
+// UpdateEntrega guarda el resultado de un intento
+func (r *webhookRepository) UpdateEntrega(entrega *models.EntregaWebhook) error {
+	return r.db.Save(entrega).Error
+}
@@ -0,0 +1,71 @@
+package routers
+
+import (
+	"ApiEscuela/middleware"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/etag"
+)
+
+// cachePolicy define cómo se cachean las respuestas GET de un recurso
+type cachePolicy struct {
+	// CacheControl es el valor del encabezado Cache-Control
+	CacheControl string
+	// Version calcula el ETag y Last-Modified del recurso a partir de UpdatedAt y la cantidad de
+	// registros. Si es nil, el ETag se calcula sobre el cuerpo de la respuesta.
+	Version func(*AllHandlers) middleware.VersionFunc
+}
+
+// Los catálogos casi no cambian: el navegador puede reutilizarlos sin consultar durante max-age.
+// Las temáticas y actividades cambian con la planificación de visitas, por eso se revalidan siempre.
+var cachePolicies = map[string]cachePolicy{
+	"provincias": {
+		CacheControl: "private, max-age=3600",
+		Version:      func(h *AllHandlers) middleware.VersionFunc { return h.ProvinciaHandler.Version },
+	},
+	"ciudades": {
+		CacheControl: "private, max-age=3600",
+		Version:      func(h *AllHandlers) middleware.VersionFunc { return h.CiudadHandler.Version },
+	},
+	// La lista de tipos de usuario incluye sus usuarios: se valida por el contenido
+	"tipos-usuario": {CacheControl: "private, max-age=600"},
+	"tematicas": {
+		CacheControl: "private, no-cache",
+		Version:      func(h *AllHandlers) middleware.VersionFunc { return h.TematicaHandler.Version },
+	},
+	"actividades": {
+		CacheControl: "private, no-cache",
+		Version:      func(h *AllHandlers) middleware.VersionFunc { return h.ActividadHandler.Version },
+	},
+	// Los respaldos no deben quedar en ninguna caché
+	"admin": {CacheControl: "no-store"},
+	// La papelera cambia con cada borrado en cualquier entidad
+	"papelera": {CacheControl: "no-store"},
+	// Las entregas de webhooks cambian en segundo plano
+	"webhooks": {CacheControl: "no-store"},
+}
+
+// defaultCachePolicy se aplica a los recursos sin política propia
+var defaultCachePolicy = cachePolicy{CacheControl: "private, no-cache"}
+
+// cacheMiddleware devuelve los middlewares de caché del recurso
+func cacheMiddleware(resource string, handlers *AllHandlers) []fiber.Handler {
+	policy, ok := cachePolicies[resource]
+	if !ok {
+		policy = defaultCachePolicy
+	}
+	if policy.Version != nil {
+		return []fiber.Handler{middleware.CacheControl(policy.CacheControl, policy.Version(handlers))}
+	}
+	mws := []fiber.Handler{middleware.CacheControl(policy.CacheControl, nil)}
+	if policy.CacheControl != "no-store" {
+		mws = append(mws, etag.New(etag.Config{
+			Weak: true,
+			// Las exportaciones se envían por partes: calcular el ETag obligaría a leerlas enteras
+			Next: func(c *fiber.Ctx) bool {
+				return c.Method() != fiber.MethodGet || handlers.ExportacionHandler.EsExportacion(c)
+			},
+		}))
+	}
+	return mws
+}
@@ -3,6 +3,8 @@ package routers
 import (
 	"ApiEscuela/handlers"
 	"ApiEscuela/middleware"
+	"ApiEscuela/repositories"
+	"ApiEscuela/services"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -19,18 +21,114 @@ func SetupAllRoutes(app *fiber.App, handlers *AllHandlers) {
 	auth.Post("/verify-code", handlers.AuthHandler.VerifyCode)
 	auth.Post("/reset-password", handlers.AuthHandler.ResetPassword)
 
+	// ==================== API VERSIONADA ====================
+	// Cada versión se monta en /api/{version} y /api es un alias de la versión base.
+	// El alias se registra al final para que su middleware JWT no se ejecute sobre /api/{version}.
+	for i, version := range apiVersions {
+		setupAPIVersion(app, "/api/"+version.Name, i, handlers)
+	}
+	setupAPIVersion(app, "/api", 0, handlers)
+
+	// ==================== RUTAS DEL SISTEMA ====================
+	// Ruta de bienvenida
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{
+			"message": "¡Bienvenido a ApiEscuela!",
+			"version": "4.2.2",
+			"status":  "running",
+		})
+	})
+
+	// Ruta de salud
+	app.Get("/health", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{
+			"status":   "healthy",
+			"database": "connected",
+		})
+	})
+}
+
+// setupAPIVersion registra bajo prefix todas las rutas de la versión apiVersions[index]
+func setupAPIVersion(app *fiber.App, prefix string, index int, handlers *AllHandlers) {
 	// ==================== SERVIR ARCHIVOS ESTÁTICOS (PÚBLICO) ====================
-	app.Get("/api/files/:tipo/:nombre", handlers.UploadHandler.GetFile)
+	// Se registran antes del middleware JWT del prefijo; el token es opcional y solo se
+	// necesita para los archivos privados
+	app.Get(prefix+"/files/:tipo/:nombre", middleware.OptionalJWTMiddleware(), handlers.UploadHandler.GetFile)
 	// Ruta para archivos con subcarpeta (comunicados_files/{fecha}/{archivo})
-	app.Get("/api/files/:tipo/:subcarpeta/:nombre", handlers.UploadHandler.GetFile)
+	app.Get(prefix+"/files/:tipo/:subcarpeta/:nombre", middleware.OptionalJWTMiddleware(), handlers.UploadHandler.GetFile)
+
+	// ==================== EVENTOS EN TIEMPO REAL (SSE) ====================
+	// EventSource del navegador no envía headers: el token puede ir en ?access_token=. Se registra
+	// antes del grupo protegido para que el token de la URL llegue a JWTMiddleware. Los estados de
+	// WhatsApp llegan solo a los administradores.
+	app.Get(prefix+"/eventos", middleware.TokenEnQuery(), middleware.JWTMiddleware(),
+		middleware.IdentificarAdministrador(handlers.TipoUsuarioHandler.NombreTipoUsuario), handlers.EventosHandler.Stream)
 
 	// ==================== RUTAS PROTEGIDAS (CON AUTENTICACIÓN JWT) ====================
 	// Aplicar middleware JWT a todas las rutas protegidas
-	protected := app.Group("/api", middleware.JWTMiddleware())
+	protected := app.Group(prefix, middleware.JWTMiddleware())
+
+	version := apiVersions[index]
+	for _, resource := range apiResources {
+		mws := cacheMiddleware(resource.Name, handlers)
+		if dep, ok := version.Deprecated[resource.Name]; ok {
+			mws = append([]fiber.Handler{middleware.Deprecated(dep.Since, dep.Sunset, dep.Successor)}, mws...)
+		}
+		group := protected.Group("/"+resource.Name, mws...)
+		resolveRegistrar(index, resource)(group, handlers)
+	}
+}
 
-	// ==================== UPLOAD DE ARCHIVOS (PROTEGIDO) ====================
-	upload := protected.Group("/upload")
+// apiResource agrupa las rutas protegidas de un recurso bajo /api/{version}/{Name}
+type apiResource struct {
+	Name     string
+	Register RouteRegistrar
+}
+
+// apiResources son los recursos de la versión base, en orden de registro
+var apiResources = []apiResource{
+	{Name: "upload", Register: setupUploadRoutes},
+	{Name: "media", Register: setupMediaRoutes},
+	{Name: "auth", Register: setupAuthRoutes},
+	{Name: "estudiantes", Register: setupEstudianteRoutes},
+	{Name: "personas", Register: setupPersonaRoutes},
+	{Name: "provincias", Register: setupProvinciaRoutes},
+	{Name: "ciudades", Register: setupCiudadRoutes},
+	{Name: "instituciones", Register: setupInstitucionRoutes},
+	{Name: "tipos-usuario", Register: setupTipoUsuarioRoutes},
+	{Name: "usuarios", Register: setupUsuarioRoutes},
+	{Name: "estudiantes-universitarios", Register: setupEstudianteUniversitarioRoutes},
+	{Name: "autoridades-uteq", Register: setupAutoridadUTEQRoutes},
+	{Name: "tematicas", Register: setupTematicaRoutes},
+	{Name: "actividades", Register: setupActividadRoutes},
+	{Name: "programas-visita", Register: setupProgramaVisitaRoutes},
+	{Name: "detalle-autoridad-detalles-visita", Register: setupDetalleAutoridadDetallesVisitaRoutes},
+	{Name: "visita-detalles", Register: setupVisitaDetalleRoutes},
+	{Name: "dudas", Register: setupDudasRoutes},
+	{Name: "visita-detalle-estudiantes-universitarios", Register: setupVisitaDetalleEstudiantesUniversitariosRoutes},
+	{Name: "noticias", Register: setupNoticiaRoutes},
+	{Name: "codigos", Register: setupCodigoRoutes},
+	{Name: "comunicados", Register: setupComunicadoRoutes},
+	{Name: "whatsapp", Register: setupWhatsAppRoutes},
+	{Name: "admin", Register: setupAdminRoutes},
+	{Name: "papelera", Register: setupPapeleraRoutes},
+	{Name: "notificaciones", Register: setupNotificacionRoutes},
+	{Name: "webhooks", Register: setupWebhookRoutes},
+	{Name: "search", Register: setupBusquedaRoutes},
+	{Name: "exportaciones", Register: setupExportacionRoutes},
+	{Name: "analitica", Register: setupAnaliticaRoutes},
+}
+
+// setupUploadRoutes registra las rutas de upload de archivos
+func setupUploadRoutes(upload fiber.Router, handlers *AllHandlers) {
 	upload.Post("/", handlers.UploadHandler.UploadFile)
+
+	// Subidas por partes (reanudables) para archivos más grandes que el límite del cuerpo
+	upload.Post("/sesiones", handlers.UploadHandler.CreateUploadSession)
+	upload.Get("/sesiones/:id", handlers.UploadHandler.GetUploadSession)
+	upload.Patch("/sesiones/:id", handlers.UploadHandler.UploadChunk)
+	upload.Post("/sesiones/:id/finalizar", handlers.UploadHandler.FinalizeUploadSession)
+	upload.Delete("/sesiones/:id", handlers.UploadHandler.CancelUploadSession)
 	upload.Get("/test", func(c *fiber.Ctx) error {
 		userID := c.Locals("user_id")
 		username := c.Locals("username")
@@ -40,232 +138,283 @@ func SetupAllRoutes(app *fiber.App, handlers *AllHandlers) {
 			"username": username,
 		})
 	})
+}
+
+// setupMediaRoutes registra las rutas de la biblioteca de archivos subidos; cambiar la
+// visibilidad y eliminar están reservados al tipo de usuario Administrador
+func setupMediaRoutes(media fiber.Router, handlers *AllHandlers) {
+	soloAdministrador := middleware.RequireRoles(handlers.TipoUsuarioHandler.NombreTipoUsuario, "Administrador")
+	media.Get("/", handlers.MediaHandler.ListMedia)
+	media.Get("/:id", handlers.MediaHandler.GetMedia)
+	media.Put("/:id/visibilidad", soloAdministrador, handlers.MediaHandler.UpdateVisibilidad)
+	media.Delete("/:id", soloAdministrador, handlers.MediaHandler.DeleteMedia)
+}
 
-	// Rutas de autenticación protegidas
-	authProtected := protected.Group("/auth")
+// setupAuthRoutes registra las rutas de autenticación protegidas
+func setupAuthRoutes(authProtected fiber.Router, handlers *AllHandlers) {
 	authProtected.Get("/profile", handlers.AuthHandler.GetProfile)
 	authProtected.Post("/change-password", handlers.AuthHandler.ChangePassword)
 	authProtected.Post("/refresh-token", handlers.AuthHandler.RefreshToken)
+}
 
-	// ==================== ESTUDIANTES ====================
-	estudiantes := protected.Group("/estudiantes")
+// setupEstudianteRoutes registra las rutas de estudiantes
+func setupEstudianteRoutes(estudiantes fiber.Router, handlers *AllHandlers) {
 	estudiantes.Post("/", handlers.EstudianteHandler.CreateEstudiante)
-	estudiantes.Get("/", handlers.EstudianteHandler.GetAllEstudiantes)
+	estudiantes.Get("/", handlers.ExportacionHandler.Exportable(services.ListaEstudiantes), handlers.EstudianteHandler.GetAllEstudiantes)
 	estudiantes.Get("/all-including-deleted", handlers.EstudianteHandler.GetAllEstudiantesIncludingDeleted)
 	estudiantes.Get("/deleted", handlers.EstudianteHandler.GetDeletedEstudiantes)
 	estudiantes.Get("/:id", handlers.EstudianteHandler.GetEstudiante)
 	estudiantes.Put("/:id", handlers.EstudianteHandler.UpdateEstudiante)
+	estudiantes.Patch("/:id", handlers.EstudianteHandler.PatchEstudiante)
 	estudiantes.Delete("/:id", handlers.EstudianteHandler.DeleteEstudiante)
 	estudiantes.Put("/:id/restore", handlers.EstudianteHandler.RestoreEstudiante)
-	estudiantes.Get("/ciudad/:ciudad_id", handlers.EstudianteHandler.GetEstudiantesByCity)
-	estudiantes.Get("/institucion/:institucion_id", handlers.EstudianteHandler.GetEstudiantesByInstitucion)
+	estudiantes.Get("/ciudad/:ciudad_id", handlers.ExportacionHandler.Exportable(services.ListaEstudiantes, handlers.ExportacionHandler.PorCiudad), handlers.EstudianteHandler.GetEstudiantesByCity)
+	estudiantes.Get("/institucion/:institucion_id", handlers.ExportacionHandler.Exportable(services.ListaEstudiantes, handlers.ExportacionHandler.PorInstitucion), handlers.EstudianteHandler.GetEstudiantesByInstitucion)
 	estudiantes.Get("/especialidad/:especialidad", handlers.EstudianteHandler.GetEstudiantesByEspecialidad)
 	estudiantes.Post("/bulk", handlers.EstudianteHandler.CreateEstudiantesBulk) // Carga masiva desde Excel
+}
 
-	// ==================== PERSONAS ====================
-	personas := protected.Group("/personas")
+// setupPersonaRoutes registra las rutas de personas
+func setupPersonaRoutes(personas fiber.Router, handlers *AllHandlers) {
 	personas.Post("/", handlers.PersonaHandler.CreatePersona)
 	personas.Get("/", handlers.PersonaHandler.GetAllPersonas)
 	personas.Get("/:id", handlers.PersonaHandler.GetPersona)
 	personas.Put("/:id", handlers.PersonaHandler.UpdatePersona)
+	personas.Patch("/:id", handlers.PersonaHandler.PatchPersona)
 	personas.Delete("/:id", handlers.PersonaHandler.DeletePersona)
 	personas.Get("/cedula/:cedula", handlers.PersonaHandler.GetPersonaByCedula)
 	personas.Get("/correo/:correo", handlers.PersonaHandler.GetPersonasByCorreo)
+}
 
-	// ==================== PROVINCIAS ====================
-	provincias := protected.Group("/provincias")
+// setupProvinciaRoutes registra las rutas de provincias
+func setupProvinciaRoutes(provincias fiber.Router, handlers *AllHandlers) {
 	provincias.Post("/", handlers.ProvinciaHandler.CreateProvincia)
 	provincias.Get("/", handlers.ProvinciaHandler.GetAllProvincias)
 	provincias.Get("/:id", handlers.ProvinciaHandler.GetProvincia)
 	provincias.Put("/:id", handlers.ProvinciaHandler.UpdateProvincia)
+	provincias.Patch("/:id", handlers.ProvinciaHandler.PatchProvincia)
 	provincias.Delete("/:id", handlers.ProvinciaHandler.DeleteProvincia)
 	provincias.Get("/nombre/:nombre", handlers.ProvinciaHandler.GetProvinciaByNombre)
+}
 
-	// ==================== CIUDADES ====================
-	ciudades := protected.Group("/ciudades")
+// setupCiudadRoutes registra las rutas de ciudades
+func setupCiudadRoutes(ciudades fiber.Router, handlers *AllHandlers) {
 	ciudades.Post("/", handlers.CiudadHandler.CreateCiudad)
 	ciudades.Get("/", handlers.CiudadHandler.GetAllCiudades)
 	ciudades.Get("/:id", handlers.CiudadHandler.GetCiudad)
 	ciudades.Put("/:id", handlers.CiudadHandler.UpdateCiudad)
+	ciudades.Patch("/:id", handlers.CiudadHandler.PatchCiudad)
 	ciudades.Delete("/:id", handlers.CiudadHandler.DeleteCiudad)
 	ciudades.Get("/provincia/:provincia_id", handlers.CiudadHandler.GetCiudadesByProvincia)
 	ciudades.Get("/nombre/:nombre", handlers.CiudadHandler.GetCiudadByNombre)
+}
 
-	// ==================== INSTITUCIONES ====================
-	instituciones := protected.Group("/instituciones")
+// setupInstitucionRoutes registra las rutas de instituciones
+func setupInstitucionRoutes(instituciones fiber.Router, handlers *AllHandlers) {
 	instituciones.Post("/", handlers.InstitucionHandler.CreateInstitucion)
 	instituciones.Get("/", handlers.InstitucionHandler.GetAllInstituciones)
 	instituciones.Get("/:id", handlers.InstitucionHandler.GetInstitucion)
 	instituciones.Put("/:id", handlers.InstitucionHandler.UpdateInstitucion)
+	instituciones.Patch("/:id", handlers.InstitucionHandler.PatchInstitucion)
 	instituciones.Delete("/:id", handlers.InstitucionHandler.DeleteInstitucion)
 	instituciones.Get("/nombre/:nombre", handlers.InstitucionHandler.GetInstitucionesByNombre)
 	instituciones.Get("/autoridad/:autoridad", handlers.InstitucionHandler.GetInstitucionesByAutoridad)
+}
 
-	// ==================== TIPOS DE USUARIO ====================
-	tiposUsuario := protected.Group("/tipos-usuario")
+// setupTipoUsuarioRoutes registra las rutas de tipos de usuario
+func setupTipoUsuarioRoutes(tiposUsuario fiber.Router, handlers *AllHandlers) {
 	tiposUsuario.Post("/", handlers.TipoUsuarioHandler.CreateTipoUsuario)
 	tiposUsuario.Get("/", handlers.TipoUsuarioHandler.GetAllTiposUsuario)
 	tiposUsuario.Get("/:id", handlers.TipoUsuarioHandler.GetTipoUsuario)
 	tiposUsuario.Put("/:id", handlers.TipoUsuarioHandler.UpdateTipoUsuario)
+	tiposUsuario.Patch("/:id", handlers.TipoUsuarioHandler.PatchTipoUsuario)
 	tiposUsuario.Delete("/:id", handlers.TipoUsuarioHandler.DeleteTipoUsuario)
 	tiposUsuario.Get("/nombre/:nombre", handlers.TipoUsuarioHandler.GetTipoUsuarioByNombre)
+}
 
-	// ==================== USUARIOS ====================
-	usuarios := protected.Group("/usuarios")
+// setupUsuarioRoutes registra las rutas de usuarios
+func setupUsuarioRoutes(usuarios fiber.Router, handlers *AllHandlers) {
 	usuarios.Post("/", handlers.UsuarioHandler.CreateUsuario)
 	usuarios.Get("/", handlers.UsuarioHandler.GetAllUsuarios)
 	usuarios.Get("/all-including-deleted", handlers.UsuarioHandler.GetAllUsuariosIncludingDeleted)
 	usuarios.Get("/deleted", handlers.UsuarioHandler.GetDeletedUsuarios)
 	usuarios.Get("/:id", handlers.UsuarioHandler.GetUsuario)
 	usuarios.Put("/:id", handlers.UsuarioHandler.UpdateUsuario)
+	usuarios.Patch("/:id", handlers.UsuarioHandler.PatchUsuario)
 	usuarios.Delete("/:id", handlers.UsuarioHandler.DeleteUsuario)
 	usuarios.Put("/:id/restore", handlers.UsuarioHandler.RestoreUsuario)
 	usuarios.Get("/username/:username", handlers.UsuarioHandler.GetUsuarioByUsername)
 	usuarios.Get("/tipo/:tipo_usuario_id", handlers.UsuarioHandler.GetUsuariosByTipo)
 	usuarios.Get("/persona/:persona_id", handlers.UsuarioHandler.GetUsuariosByPersona)
+}
 
-	// ==================== ESTUDIANTES UNIVERSITARIOS ====================
-	estudiantesUniv := protected.Group("/estudiantes-universitarios")
+// setupEstudianteUniversitarioRoutes registra las rutas de estudiantes universitarios
+func setupEstudianteUniversitarioRoutes(estudiantesUniv fiber.Router, handlers *AllHandlers) {
 	estudiantesUniv.Post("/", handlers.EstudianteUnivHandler.CreateEstudianteUniversitario)
 	estudiantesUniv.Get("/", handlers.EstudianteUnivHandler.GetAllEstudiantesUniversitarios)
 	estudiantesUniv.Get("/:id", handlers.EstudianteUnivHandler.GetEstudianteUniversitario)
 	estudiantesUniv.Put("/:id", handlers.EstudianteUnivHandler.UpdateEstudianteUniversitario)
+	estudiantesUniv.Patch("/:id", handlers.EstudianteUnivHandler.PatchEstudianteUniversitario)
 	estudiantesUniv.Delete("/:id", handlers.EstudianteUnivHandler.DeleteEstudianteUniversitario)
 	estudiantesUniv.Get("/semestre/:semestre", handlers.EstudianteUnivHandler.GetEstudiantesUniversitariosBySemestre)
 	estudiantesUniv.Get("/persona/:persona_id", handlers.EstudianteUnivHandler.GetEstudianteUniversitarioByPersona)
+}
 
-	// ==================== AUTORIDADES UTEQ ====================
-	autoridades := protected.Group("/autoridades-uteq")
+// setupAutoridadUTEQRoutes registra las rutas de autoridades UTEQ
+func setupAutoridadUTEQRoutes(autoridades fiber.Router, handlers *AllHandlers) {
 	autoridades.Post("/", handlers.AutoridadHandler.CreateAutoridadUTEQ)
 	autoridades.Get("/", handlers.AutoridadHandler.GetAllAutoridadesUTEQ)
 	autoridades.Get("/all-including-deleted", handlers.AutoridadHandler.GetAllAutoridadesUTEQIncludingDeleted)
 	autoridades.Get("/deleted", handlers.AutoridadHandler.GetDeletedAutoridadesUTEQ)
 	autoridades.Get("/:id", handlers.AutoridadHandler.GetAutoridadUTEQ)
 	autoridades.Put("/:id", handlers.AutoridadHandler.UpdateAutoridadUTEQ)
+	autoridades.Patch("/:id", handlers.AutoridadHandler.PatchAutoridadUTEQ)
 	autoridades.Delete("/:id", handlers.AutoridadHandler.DeleteAutoridadUTEQ)
 	autoridades.Put("/:id/restore", handlers.AutoridadHandler.RestoreAutoridadUTEQ)
 	autoridades.Get("/cargo/:cargo", handlers.AutoridadHandler.GetAutoridadesUTEQByCargo)
 	autoridades.Get("/persona/:persona_id", handlers.AutoridadHandler.GetAutoridadUTEQByPersona)
+}
 
-	// ==================== TEMÁTICAS ====================
-	tematicas := protected.Group("/tematicas")
+// setupTematicaRoutes registra las rutas de temáticas
+func setupTematicaRoutes(tematicas fiber.Router, handlers *AllHandlers) {
 	tematicas.Post("/", handlers.TematicaHandler.CreateTematica)
 	tematicas.Get("/", handlers.TematicaHandler.GetAllTematicas)
 	tematicas.Get("/:id", handlers.TematicaHandler.GetTematica)
 	tematicas.Put("/:id", handlers.TematicaHandler.UpdateTematica)
+	tematicas.Patch("/:id", handlers.TematicaHandler.PatchTematica)
 	tematicas.Delete("/:id", handlers.TematicaHandler.DeleteTematica)
 	tematicas.Get("/nombre/:nombre", handlers.TematicaHandler.GetTematicasByNombre)
 	tematicas.Get("/descripcion/:descripcion", handlers.TematicaHandler.GetTematicasByDescripcion)
+}
 
-	// ==================== ACTIVIDADES ====================
-	actividades := protected.Group("/actividades")
+// setupActividadRoutes registra las rutas de actividades
+func setupActividadRoutes(actividades fiber.Router, handlers *AllHandlers) {
 	actividades.Post("/", handlers.ActividadHandler.CreateActividad)
 	actividades.Get("/", handlers.ActividadHandler.GetAllActividades)
 	actividades.Get("/:id", handlers.ActividadHandler.GetActividad)
 	actividades.Put("/:id", handlers.ActividadHandler.UpdateActividad)
+	actividades.Patch("/:id", handlers.ActividadHandler.PatchActividad)
 	actividades.Delete("/:id", handlers.ActividadHandler.DeleteActividad)
 	actividades.Get("/tematica/:tematica_id", handlers.ActividadHandler.GetActividadesByTematica)
 	actividades.Get("/nombre/:nombre", handlers.ActividadHandler.GetActividadesByNombre)
 	actividades.Get("/duracion", handlers.ActividadHandler.GetActividadesByDuracion) // ?min=30&max=120
+}
 
-	// ==================== PROGRAMAS DE VISITA ====================
-	programas := protected.Group("/programas-visita")
+// setupProgramaVisitaRoutes registra las rutas de programas de visita
+func setupProgramaVisitaRoutes(programas fiber.Router, handlers *AllHandlers) {
 	programas.Post("/", handlers.ProgramaVisitaHandler.CreateProgramaVisita)
-	programas.Get("/", handlers.ProgramaVisitaHandler.GetAllProgramasVisita)
+	programas.Get("/", handlers.ExportacionHandler.Exportable(services.ListaProgramasVisita), handlers.ProgramaVisitaHandler.GetAllProgramasVisita)
+	// Antes de "/:id", que la taparía
+	programas.Get("/rango-fecha", handlers.ExportacionHandler.Exportable(services.ListaProgramasVisita, handlers.ExportacionHandler.PorRangoFechas), handlers.ProgramaVisitaHandler.GetProgramasVisitaByRangoFecha) // ?inicio=2024-01-01&fin=2024-12-31
 	programas.Get("/:id", handlers.ProgramaVisitaHandler.GetProgramaVisita)
 	programas.Put("/:id", handlers.ProgramaVisitaHandler.UpdateProgramaVisita)
+	programas.Patch("/:id", handlers.ProgramaVisitaHandler.PatchProgramaVisita)
 	programas.Delete("/:id", handlers.ProgramaVisitaHandler.DeleteProgramaVisita)
 	programas.Get("/fecha/:fecha", handlers.ProgramaVisitaHandler.GetProgramasVisitaByFecha) // YYYY-MM-DD
-	programas.Get("/institucion/:institucion_id", handlers.ProgramaVisitaHandler.GetProgramasVisitaByInstitucion)
-	programas.Get("/rango-fecha", handlers.ProgramaVisitaHandler.GetProgramasVisitaByRangoFecha) // ?inicio=2024-01-01&fin=2024-12-31
+	programas.Get("/institucion/:institucion_id", handlers.ExportacionHandler.Exportable(services.ListaProgramasVisita, handlers.ExportacionHandler.PorInstitucion), handlers.ProgramaVisitaHandler.GetProgramasVisitaByInstitucion)
+}
 
-	// ==================== DETALLE AUTORIDAD DETALLES VISITA ====================
-	detalleAutoridad := protected.Group("/detalle-autoridad-detalles-visita")
+// setupDetalleAutoridadDetallesVisitaRoutes registra las rutas de detalle autoridad detalles visita
+func setupDetalleAutoridadDetallesVisitaRoutes(detalleAutoridad fiber.Router, handlers *AllHandlers) {
 	detalleAutoridad.Post("/", handlers.DetalleAutoridadDetallesVisitaHandler.CreateDetalleAutoridadDetallesVisita)
 	detalleAutoridad.Get("/", handlers.DetalleAutoridadDetallesVisitaHandler.GetAllDetalleAutoridadDetallesVisitas)
 	detalleAutoridad.Get("/:id", handlers.DetalleAutoridadDetallesVisitaHandler.GetDetalleAutoridadDetallesVisita)
 	detalleAutoridad.Put("/:id", handlers.DetalleAutoridadDetallesVisitaHandler.UpdateDetalleAutoridadDetallesVisita)
+	detalleAutoridad.Patch("/:id", handlers.DetalleAutoridadDetallesVisitaHandler.PatchDetalleAutoridadDetallesVisita)
 	detalleAutoridad.Delete("/:id", handlers.DetalleAutoridadDetallesVisitaHandler.DeleteDetalleAutoridadDetallesVisita)
 	detalleAutoridad.Get("/programa-visita/:programa_visita_id", handlers.DetalleAutoridadDetallesVisitaHandler.GetDetallesByProgramaVisita)
 	detalleAutoridad.Get("/autoridad/:autoridad_id", handlers.DetalleAutoridadDetallesVisitaHandler.GetDetallesByAutoridad)
 	detalleAutoridad.Delete("/programa-visita/:programa_visita_id", handlers.DetalleAutoridadDetallesVisitaHandler.DeleteDetallesByProgramaVisita)
 	detalleAutoridad.Delete("/autoridad/:autoridad_id", handlers.DetalleAutoridadDetallesVisitaHandler.DeleteDetallesByAutoridad)
 	detalleAutoridad.Get("/estadisticas", handlers.DetalleAutoridadDetallesVisitaHandler.GetEstadisticasAsignacion)
+}
 
-	// ==================== VISITA DETALLES ====================
-	detalles := protected.Group("/visita-detalles")
+// setupVisitaDetalleRoutes registra las rutas de visita detalles
+func setupVisitaDetalleRoutes(detalles fiber.Router, handlers *AllHandlers) {
 	detalles.Post("/", handlers.VisitaDetalleHandler.CreateVisitaDetalle)
 	detalles.Get("/", handlers.VisitaDetalleHandler.GetAllVisitaDetalles)
 	detalles.Get("/:id", handlers.VisitaDetalleHandler.GetVisitaDetalle)
 	detalles.Put("/:id", handlers.VisitaDetalleHandler.UpdateVisitaDetalle)
+	detalles.Patch("/:id", handlers.VisitaDetalleHandler.PatchVisitaDetalle)
 	detalles.Delete("/:id", handlers.VisitaDetalleHandler.DeleteVisitaDetalle)
 	detalles.Get("/actividad/:actividad_id", handlers.VisitaDetalleHandler.GetVisitaDetallesByActividad)
 	detalles.Get("/programa/:programa_id", handlers.VisitaDetalleHandler.GetVisitaDetallesByPrograma)
 	detalles.Delete("/programa/:programa_id", handlers.VisitaDetalleHandler.DeleteVisitaDetallesByPrograma)
 	detalles.Delete("/actividad/:actividad_id", handlers.VisitaDetalleHandler.DeleteVisitaDetallesByActividad)
 	detalles.Get("/estadisticas", handlers.VisitaDetalleHandler.GetEstadisticasActividades)
+}
 
-	// ==================== DUDAS ====================
-	dudas := protected.Group("/dudas")
+// setupDudasRoutes registra las rutas de dudas
+func setupDudasRoutes(dudas fiber.Router, handlers *AllHandlers) {
 	dudas.Post("/", handlers.DudasHandler.CreateDudas)
-	dudas.Get("/", handlers.DudasHandler.GetAllDudas)
+	dudas.Get("/", handlers.ExportacionHandler.Exportable(services.ListaDudas), handlers.DudasHandler.GetAllDudas)
+	// Antes de "/:id", que las taparía
+	dudas.Get("/sin-responder", handlers.ExportacionHandler.Exportable(services.ListaDudas, handlers.ExportacionHandler.PorEstadoDuda(repositories.DudasSinResponder)), handlers.DudasHandler.GetDudasSinResponder)
+	dudas.Get("/respondidas", handlers.ExportacionHandler.Exportable(services.ListaDudas, handlers.ExportacionHandler.PorEstadoDuda(repositories.DudasRespondidas)), handlers.DudasHandler.GetDudasRespondidas)
+	dudas.Get("/sin-asignar", handlers.ExportacionHandler.Exportable(services.ListaDudas, handlers.ExportacionHandler.PorEstadoDuda(repositories.DudasSinAsignar)), handlers.DudasHandler.GetDudasSinAsignar)
 	dudas.Get("/:id", handlers.DudasHandler.GetDudas)
 	dudas.Put("/:id", handlers.DudasHandler.UpdateDudas)
+	dudas.Patch("/:id", handlers.DudasHandler.PatchDudas)
 	dudas.Delete("/:id", handlers.DudasHandler.DeleteDudas)
 	dudas.Get("/estudiante/:estudiante_id", handlers.DudasHandler.GetDudasByEstudiante)
 	dudas.Get("/autoridad/:autoridad_id", handlers.DudasHandler.GetDudasByAutoridad)
-	dudas.Get("/sin-responder", handlers.DudasHandler.GetDudasSinResponder)
-	dudas.Get("/respondidas", handlers.DudasHandler.GetDudasRespondidas)
-	dudas.Get("/sin-asignar", handlers.DudasHandler.GetDudasSinAsignar)
 	dudas.Get("/privacidad/:privacidad", handlers.DudasHandler.GetDudasByPrivacidad)
 	dudas.Get("/buscar/:termino", handlers.DudasHandler.BuscarDudasPorPregunta)
 	dudas.Put("/:duda_id/responder", handlers.DudasHandler.ResponderDuda)
+}
 
-	// ==================== VISITA DETALLE ESTUDIANTES UNIVERSITARIOS ====================
-	visitaDetalleEstudiantes := protected.Group("/visita-detalle-estudiantes-universitarios")
+// setupVisitaDetalleEstudiantesUniversitariosRoutes registra las rutas de visita detalle estudiantes universitarios
+func setupVisitaDetalleEstudiantesUniversitariosRoutes(visitaDetalleEstudiantes fiber.Router, handlers *AllHandlers) {
 	visitaDetalleEstudiantes.Post("/", handlers.VisitaDetalleEstudiantesUniversitariosHandler.CreateVisitaDetalleEstudiantesUniversitarios)
-	visitaDetalleEstudiantes.Get("/", handlers.VisitaDetalleEstudiantesUniversitariosHandler.GetAllVisitaDetalleEstudiantesUniversitarios)
+	visitaDetalleEstudiantes.Get("/", handlers.ExportacionHandler.Exportable(services.ListaParticipantes), handlers.VisitaDetalleEstudiantesUniversitariosHandler.GetAllVisitaDetalleEstudiantesUniversitarios)
 	visitaDetalleEstudiantes.Get("/:id", handlers.VisitaDetalleEstudiantesUniversitariosHandler.GetVisitaDetalleEstudiantesUniversitarios)
 	visitaDetalleEstudiantes.Put("/:id", handlers.VisitaDetalleEstudiantesUniversitariosHandler.UpdateVisitaDetalleEstudiantesUniversitarios)
+	visitaDetalleEstudiantes.Patch("/:id", handlers.VisitaDetalleEstudiantesUniversitariosHandler.PatchVisitaDetalleEstudiantesUniversitarios)
 	visitaDetalleEstudiantes.Delete("/:id", handlers.VisitaDetalleEstudiantesUniversitariosHandler.DeleteVisitaDetalleEstudiantesUniversitarios)
-	visitaDetalleEstudiantes.Get("/programa-visita/:programa_visita_id", handlers.VisitaDetalleEstudiantesUniversitariosHandler.GetEstudiantesByProgramaVisita)
+	visitaDetalleEstudiantes.Get("/programa-visita/:programa_visita_id", handlers.ExportacionHandler.Exportable(services.ListaParticipantes, handlers.ExportacionHandler.PorProgramaVisita), handlers.VisitaDetalleEstudiantesUniversitariosHandler.GetEstudiantesByProgramaVisita)
 	visitaDetalleEstudiantes.Get("/estudiante/:estudiante_id", handlers.VisitaDetalleEstudiantesUniversitariosHandler.GetProgramasVisitaByEstudiante)
 	visitaDetalleEstudiantes.Delete("/programa-visita/:programa_visita_id", handlers.VisitaDetalleEstudiantesUniversitariosHandler.DeleteByProgramaVisita)
 	visitaDetalleEstudiantes.Delete("/estudiante/:estudiante_id", handlers.VisitaDetalleEstudiantesUniversitariosHandler.DeleteByEstudiante)
 	visitaDetalleEstudiantes.Get("/estadisticas", handlers.VisitaDetalleEstudiantesUniversitariosHandler.GetEstadisticasParticipacion)
+}
 
-	// ==================== NOTICIAS ====================
-	noticias := protected.Group("/noticias")
+// setupNoticiaRoutes registra las rutas de noticias
+func setupNoticiaRoutes(noticias fiber.Router, handlers *AllHandlers) {
 	noticias.Post("/", handlers.NoticiaHandler.CreateNoticia)
 	noticias.Get("/", handlers.NoticiaHandler.GetAllNoticias)
 	noticias.Get("/:id", handlers.NoticiaHandler.GetNoticia)
 	noticias.Put("/:id", handlers.NoticiaHandler.UpdateNoticia)
+	noticias.Patch("/:id", handlers.NoticiaHandler.PatchNoticia)
 	noticias.Delete("/:id", handlers.NoticiaHandler.DeleteNoticia)
 	noticias.Get("/usuario/:usuario_id", handlers.NoticiaHandler.GetNoticiasByUsuario)
 	noticias.Get("/titulo/:titulo", handlers.NoticiaHandler.GetNoticiasByTitulo)
 	noticias.Get("/descripcion/:descripcion", handlers.NoticiaHandler.GetNoticiasByDescripcion)
 	noticias.Get("/buscar/:termino", handlers.NoticiaHandler.SearchNoticias)
+}
 
-	// ==================== CÓDIGOS ====================
-	codigos := protected.Group("/codigos")
+// setupCodigoRoutes registra las rutas de códigos
+func setupCodigoRoutes(codigos fiber.Router, handlers *AllHandlers) {
 	codigos.Post("/", handlers.CodigoHandler.CreateCodigo)
 	codigos.Get("/:id", handlers.CodigoHandler.GetCodigo)
 	codigos.Put("/:id", handlers.CodigoHandler.UpdateCodigo)
+	codigos.Patch("/:id", handlers.CodigoHandler.PatchCodigo)
 	codigos.Delete("/:id", handlers.CodigoHandler.DeleteCodigo)
 	codigos.Post("/verify", handlers.CodigoHandler.VerifyCodigo)
 	codigos.Put("/:id/verificar", handlers.CodigoHandler.MarcarComoVerificado)
 	codigos.Put("/:id/expirado", handlers.CodigoHandler.MarcarComoExpirado)
+}
 
-	// ==================== COMUNICADOS ====================
-	comunicados := protected.Group("/comunicados")
+// setupComunicadoRoutes registra las rutas de comunicados
+func setupComunicadoRoutes(comunicados fiber.Router, handlers *AllHandlers) {
 	comunicados.Post("/", handlers.ComunicadoHandler.CreateComunicado)
-	comunicados.Get("/", handlers.ComunicadoHandler.GetAllComunicados)
+	comunicados.Get("/", handlers.ExportacionHandler.Exportable(services.ListaComunicados), handlers.ComunicadoHandler.GetAllComunicados)
 	comunicados.Get("/:id", handlers.ComunicadoHandler.GetComunicado)
 	comunicados.Delete("/:id", handlers.ComunicadoHandler.DeleteComunicado)
 	comunicados.Get("/buscar/:termino", handlers.ComunicadoHandler.SearchComunicados)
+}
 
-	// ==================== WHATSAPP ====================
-	whatsapp := protected.Group("/whatsapp")
+// setupWhatsAppRoutes registra las rutas de WhatsApp
+func setupWhatsAppRoutes(whatsapp fiber.Router, handlers *AllHandlers) {
 	whatsapp.Get("/status", handlers.WhatsAppHandler.GetStatus)
 	whatsapp.Get("/qr", handlers.WhatsAppHandler.GetQR)
 	whatsapp.Post("/send-message", handlers.WhatsAppHandler.SendMessage)
@@ -274,7 +423,76 @@ func SetupAllRoutes(app *fiber.App, handlers *AllHandlers) {
 	whatsapp.Get("/queue/status", handlers.WhatsAppHandler.GetQueueStatus)
 	whatsapp.Post("/queue/cancel", handlers.WhatsAppHandler.CancelQueue)
 	whatsapp.Post("/logout", handlers.WhatsAppHandler.Logout)
+}
+
+// setupAdminRoutes registra las rutas de administración, reservadas al tipo de usuario Administrador
+func setupAdminRoutes(admin fiber.Router, handlers *AllHandlers) {
+	admin.Use(middleware.RequireRoles(handlers.TipoUsuarioHandler.NombreTipoUsuario, "Administrador"))
+	admin.Get("/backup", handlers.BackupHandler.ExportBackup)
+	admin.Post("/restore", handlers.BackupHandler.RestoreBackup)
+
+	// Tareas programadas: estado, historial y ejecución manual
+	admin.Get("/tareas", handlers.TareaHandler.GetTareas)
+	admin.Get("/tareas/:nombre/ejecuciones", handlers.TareaHandler.GetEjecuciones)
+	admin.Post("/tareas/:nombre/ejecutar", handlers.TareaHandler.EjecutarTarea)
+}
+
+// setupPapeleraRoutes registra las rutas de la papelera, reservadas al tipo de usuario Administrador
+func setupPapeleraRoutes(papelera fiber.Router, handlers *AllHandlers) {
+	papelera.Use(middleware.RequireRoles(handlers.TipoUsuarioHandler.NombreTipoUsuario, "Administrador"))
+	papelera.Get("/", handlers.PapeleraHandler.GetPapelera)
+	papelera.Get("/:entidad", handlers.PapeleraHandler.GetEliminados)
+	papelera.Put("/:entidad/:id/restore", handlers.PapeleraHandler.RestaurarElemento)
+	papelera.Delete("/:entidad/:id", handlers.PapeleraHandler.PurgarElemento)
+}
+
+// setupNotificacionRoutes registra las rutas del centro de notificaciones del usuario autenticado
+func setupNotificacionRoutes(notificaciones fiber.Router, handlers *AllHandlers) {
+	notificaciones.Get("/", handlers.NotificacionHandler.GetNotificaciones)
+	notificaciones.Get("/no-leidas", handlers.NotificacionHandler.GetNoLeidas)
+	notificaciones.Put("/leidas", handlers.NotificacionHandler.MarcarTodasLeidas)
+	notificaciones.Put("/:id/leida", handlers.NotificacionHandler.MarcarLeida)
+	notificaciones.Get("/preferencias", handlers.NotificacionHandler.GetPreferencias)
+	notificaciones.Put("/preferencias", handlers.NotificacionHandler.UpdatePreferencias)
+}
+
+// setupWebhookRoutes registra las suscripciones de webhooks y su registro de entregas, reservados
+// al tipo de usuario Administrador
+func setupWebhookRoutes(webhooks fiber.Router, handlers *AllHandlers) {
+	webhooks.Use(middleware.RequireRoles(handlers.TipoUsuarioHandler.NombreTipoUsuario, "Administrador"))
+	webhooks.Get("/", handlers.WebhookHandler.GetWebhooks)
+	webhooks.Post("/", handlers.WebhookHandler.CreateWebhook)
+	webhooks.Get("/eventos", handlers.WebhookHandler.GetEventos)
+	webhooks.Post("/entregas/:id/reenviar", handlers.WebhookHandler.ReenviarEntrega)
+	webhooks.Get("/:id", handlers.WebhookHandler.GetWebhook)
+	webhooks.Put("/:id", handlers.WebhookHandler.UpdateWebhook)
+	webhooks.Patch("/:id", handlers.WebhookHandler.PatchWebhook)
+	webhooks.Delete("/:id", handlers.WebhookHandler.DeleteWebhook)
+	webhooks.Post("/:id/prueba", handlers.WebhookHandler.ProbarWebhook)
+	webhooks.Get("/:id/entregas", handlers.WebhookHandler.GetEntregas)
+}
+
+// setupBusquedaRoutes registra la búsqueda global y su modo de autocompletado
+func setupBusquedaRoutes(search fiber.Router, handlers *AllHandlers) {
+	search.Get("/", handlers.BusquedaHandler.Buscar)
+}
+
+// setupExportacionRoutes registra la lista de exportaciones disponibles; cada lista se exporta
+// desde su propia ruta con ?format= o el encabezado Accept
+func setupExportacionRoutes(exportaciones fiber.Router, handlers *AllHandlers) {
+	exportaciones.Get("/", handlers.ExportacionHandler.GetListasExportables)
+}
 
+// setupAnaliticaRoutes registra los agregados del dashboard; /panel devuelve todas las secciones
+func setupAnaliticaRoutes(analitica fiber.Router, handlers *AllHandlers) {
+	analitica.Get("/panel", handlers.AnaliticaHandler.GetPanel)
+	analitica.Get("/visitas", handlers.AnaliticaHandler.GetVisitas)
+	analitica.Get("/estudiantes", handlers.AnaliticaHandler.GetEstudiantes)
+	analitica.Get("/tematicas", handlers.AnaliticaHandler.GetTematicas)
+	analitica.Get("/actividades", handlers.AnaliticaHandler.GetActividades)
+	analitica.Get("/autoridades", handlers.AnaliticaHandler.GetAutoridades)
+	analitica.Get("/dudas", handlers.AnaliticaHandler.GetDudas)
+	analitica.Get("/comunicados", handlers.AnaliticaHandler.GetComunicados)
 }
 
 // AllHandlers contiene todos los handlers de la aplicación
@@ -301,6 +519,16 @@ type AllHandlers struct {
 	CodigoHandler                                 *handlers.CodigoHandler
 	ComunicadoHandler                             *handlers.ComunicadoHandler
 	WhatsAppHandler                               *handlers.WhatsAppHandler
+	BackupHandler                                 *handlers.BackupHandler
+	MediaHandler                                  *handlers.MediaHandler
+	TareaHandler                                  *handlers.TareaHandler
+	PapeleraHandler                               *handlers.PapeleraHandler
+	EventosHandler                                *handlers.EventosHandler
+	NotificacionHandler                           *handlers.NotificacionHandler
+	WebhookHandler                                *handlers.WebhookHandler
+	BusquedaHandler                               *handlers.BusquedaHandler
+	ExportacionHandler                            *handlers.ExportacionHandler
+	AnaliticaHandler                              *handlers.AnaliticaHandler
 }
 
 // NewAllHandlers crea una instancia con todos los handlers
@@ -327,6 +555,16 @@ func NewAllHandlers(
 	codigoHandler *handlers.CodigoHandler,
 	comunicadoHandler *handlers.ComunicadoHandler,
 	whatsappHandler *handlers.WhatsAppHandler,
+	backupHandler *handlers.BackupHandler,
+	mediaHandler *handlers.MediaHandler,
+	tareaHandler *handlers.TareaHandler,
+	papeleraHandler *handlers.PapeleraHandler,
+	eventosHandler *handlers.EventosHandler,
+	notificacionHandler *handlers.NotificacionHandler,
+	webhookHandler *handlers.WebhookHandler,
+	busquedaHandler *handlers.BusquedaHandler,
+	exportacionHandler *handlers.ExportacionHandler,
+	analiticaHandler *handlers.AnaliticaHandler,
 ) *AllHandlers {
 	return &AllHandlers{
 		EstudianteHandler:                     estudianteHandler,
@@ -345,11 +583,21 @@ func NewAllHandlers(
 		VisitaDetalleHandler:                  visitaDetalleHandler,
 		DudasHandler:                          dudasHandler,
 		VisitaDetalleEstudiantesUniversitariosHandler: visitaDetalleEstudiantesUniversitariosHandler,
-		NoticiaHandler:    noticiaHandler,
-		UploadHandler:     uploadHandler,
-		AuthHandler:       authHandler,
-		CodigoHandler:     codigoHandler,
-		ComunicadoHandler: comunicadoHandler,
-		WhatsAppHandler:   whatsappHandler,
+		NoticiaHandler:      noticiaHandler,
+		UploadHandler:       uploadHandler,
+		AuthHandler:         authHandler,
+		CodigoHandler:       codigoHandler,
+		ComunicadoHandler:   comunicadoHandler,
+		WhatsAppHandler:     whatsappHandler,
+		BackupHandler:       backupHandler,
+		MediaHandler:        mediaHandler,
+		TareaHandler:        tareaHandler,
+		PapeleraHandler:     papeleraHandler,
+		EventosHandler:      eventosHandler,
+		NotificacionHandler: notificacionHandler,
+		WebhookHandler:      webhookHandler,
+		BusquedaHandler:     busquedaHandler,
+		ExportacionHandler:  exportacionHandler,
+		AnaliticaHandler:    analiticaHandler,
 	}
 }
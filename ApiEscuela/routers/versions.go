@@ -0,0 +1,159 @@
+package routers
+
+import (
+	"ApiEscuela/repositories"
+	"ApiEscuela/services"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RouteRegistrar registra las rutas de un recurso dentro del grupo de su versión
+type RouteRegistrar func(router fiber.Router, handlers *AllHandlers)
+
+// Deprecation describe un recurso programado para eliminarse
+type Deprecation struct {
+	Since     time.Time // Desde cuándo el recurso es obsoleto
+	Sunset    time.Time // Fecha a partir de la cual dejará de responder
+	Successor string    // Ruta que lo reemplaza
+}
+
+// APIVersion describe una versión de la API montada en /api/{Name}
+type APIVersion struct {
+	Name string
+	// Overrides reemplaza las rutas de los recursos indicados; el resto se hereda de la versión anterior
+	Overrides map[string]RouteRegistrar
+	// Deprecated contiene los recursos de esta versión programados para eliminarse
+	Deprecated map[string]Deprecation
+}
+
+// apiVersions ordenadas de la más antigua a la más reciente. La primera es la versión base
+// y es la que atiende el alias /api.
+var apiVersions = []APIVersion{
+	{
+		Name: "v1",
+		Deprecated: map[string]Deprecation{
+			// destinatarios y adjuntos se devuelven como cadenas con JSON serializado
+			"comunicados": {
+				Since:     time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC),
+				Sunset:    time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC),
+				Successor: "/api/v2/comunicados",
+			},
+		},
+	},
+	{
+		Name: "v2",
+		Overrides: map[string]RouteRegistrar{
+			"comunicados": setupComunicadoV2Routes,
+			// las búsquedas de texto devuelven {registro, relevancia, fragmento} en lugar del registro
+			"tematicas":   setupTematicaV2Routes,
+			"actividades": setupActividadV2Routes,
+			"dudas":       setupDudasV2Routes,
+			"noticias":    setupNoticiaV2Routes,
+		},
+	},
+}
+
+// BaseVersion es la versión que atiende el alias /api
+func BaseVersion() string {
+	return apiVersions[0].Name
+}
+
+// resolveRegistrar devuelve el registrador del recurso en la versión apiVersions[index]:
+// el reemplazo más reciente hasta esa versión o, si no existe, el de la versión base
+func resolveRegistrar(index int, resource apiResource) RouteRegistrar {
+	for i := index; i >= 0; i-- {
+		if register, ok := apiVersions[i].Overrides[resource.Name]; ok {
+			return register
+		}
+	}
+	return resource.Register
+}
+
+// DeprecationFor indica si la ruta pertenece a un recurso obsoleto.
+// Acepta rutas versionadas (/api/v1/comunicados/:id) y del alias (/api/comunicados/:id).
+func DeprecationFor(path string) (Deprecation, bool) {
+	rest, ok := strings.CutPrefix(path, "/api/")
+	if !ok {
+		return Deprecation{}, false
+	}
+	segment, remainder, _ := strings.Cut(rest, "/")
+	version, resource := apiVersions[0], segment
+	for _, v := range apiVersions {
+		if v.Name == segment {
+			version = v
+			resource, _, _ = strings.Cut(remainder, "/")
+			break
+		}
+	}
+	dep, ok := version.Deprecated[resource]
+	return dep, ok
+}
+
+// setupComunicadoV2Routes registra las rutas de comunicados de la versión 2
+func setupComunicadoV2Routes(comunicados fiber.Router, handlers *AllHandlers) {
+	comunicados.Post("/", handlers.ComunicadoHandler.CreateComunicadoV2)
+	comunicados.Get("/", handlers.ExportacionHandler.Exportable(services.ListaComunicados), handlers.ComunicadoHandler.GetAllComunicadosV2)
+	comunicados.Get("/:id", handlers.ComunicadoHandler.GetComunicadoV2)
+	comunicados.Delete("/:id", handlers.ComunicadoHandler.DeleteComunicado)
+	comunicados.Get("/buscar/:termino", handlers.ComunicadoHandler.SearchComunicadosV2)
+}
+
+// setupTematicaV2Routes registra las rutas de temáticas de la versión 2
+func setupTematicaV2Routes(tematicas fiber.Router, handlers *AllHandlers) {
+	tematicas.Post("/", handlers.TematicaHandler.CreateTematica)
+	tematicas.Get("/", handlers.TematicaHandler.GetAllTematicas)
+	tematicas.Get("/:id", handlers.TematicaHandler.GetTematica)
+	tematicas.Put("/:id", handlers.TematicaHandler.UpdateTematica)
+	tematicas.Patch("/:id", handlers.TematicaHandler.PatchTematica)
+	tematicas.Delete("/:id", handlers.TematicaHandler.DeleteTematica)
+	tematicas.Get("/nombre/:nombre", handlers.TematicaHandler.GetTematicasByNombreV2)
+	tematicas.Get("/descripcion/:descripcion", handlers.TematicaHandler.GetTematicasByDescripcion)
+}
+
+// setupActividadV2Routes registra las rutas de actividades de la versión 2
+func setupActividadV2Routes(actividades fiber.Router, handlers *AllHandlers) {
+	actividades.Post("/", handlers.ActividadHandler.CreateActividad)
+	actividades.Get("/", handlers.ActividadHandler.GetAllActividades)
+	actividades.Get("/:id", handlers.ActividadHandler.GetActividad)
+	actividades.Put("/:id", handlers.ActividadHandler.UpdateActividad)
+	actividades.Patch("/:id", handlers.ActividadHandler.PatchActividad)
+	actividades.Delete("/:id", handlers.ActividadHandler.DeleteActividad)
+	actividades.Get("/tematica/:tematica_id", handlers.ActividadHandler.GetActividadesByTematica)
+	actividades.Get("/nombre/:nombre", handlers.ActividadHandler.GetActividadesByNombreV2)
+	actividades.Get("/duracion", handlers.ActividadHandler.GetActividadesByDuracion) // ?min=30&max=120
+}
+
+// setupDudasV2Routes registra las rutas de dudas de la versión 2
+func setupDudasV2Routes(dudas fiber.Router, handlers *AllHandlers) {
+	dudas.Post("/", handlers.DudasHandler.CreateDudas)
+	dudas.Get("/", handlers.ExportacionHandler.Exportable(services.ListaDudas), handlers.DudasHandler.GetAllDudas)
+	// Antes de "/:id", que las taparía
+	dudas.Get("/sin-responder", handlers.ExportacionHandler.Exportable(services.ListaDudas, handlers.ExportacionHandler.PorEstadoDuda(repositories.DudasSinResponder)), handlers.DudasHandler.GetDudasSinResponder)
+	dudas.Get("/respondidas", handlers.ExportacionHandler.Exportable(services.ListaDudas, handlers.ExportacionHandler.PorEstadoDuda(repositories.DudasRespondidas)), handlers.DudasHandler.GetDudasRespondidas)
+	dudas.Get("/sin-asignar", handlers.ExportacionHandler.Exportable(services.ListaDudas, handlers.ExportacionHandler.PorEstadoDuda(repositories.DudasSinAsignar)), handlers.DudasHandler.GetDudasSinAsignar)
+	dudas.Get("/:id", handlers.DudasHandler.GetDudas)
+	dudas.Put("/:id", handlers.DudasHandler.UpdateDudas)
+	dudas.Patch("/:id", handlers.DudasHandler.PatchDudas)
+	dudas.Delete("/:id", handlers.DudasHandler.DeleteDudas)
+	dudas.Get("/estudiante/:estudiante_id", handlers.DudasHandler.GetDudasByEstudiante)
+	dudas.Get("/autoridad/:autoridad_id", handlers.DudasHandler.GetDudasByAutoridad)
+	dudas.Get("/privacidad/:privacidad", handlers.DudasHandler.GetDudasByPrivacidad)
+	dudas.Get("/buscar/:termino", handlers.DudasHandler.BuscarDudasPorPreguntaV2)
+	dudas.Put("/:duda_id/responder", handlers.DudasHandler.ResponderDuda)
+}
+
+// setupNoticiaV2Routes registra las rutas de noticias de la versión 2
+func setupNoticiaV2Routes(noticias fiber.Router, handlers *AllHandlers) {
+	noticias.Post("/", handlers.NoticiaHandler.CreateNoticia)
+	noticias.Get("/", handlers.NoticiaHandler.GetAllNoticias)
+	noticias.Get("/:id", handlers.NoticiaHandler.GetNoticia)
+	noticias.Put("/:id", handlers.NoticiaHandler.UpdateNoticia)
+	noticias.Patch("/:id", handlers.NoticiaHandler.PatchNoticia)
+	noticias.Delete("/:id", handlers.NoticiaHandler.DeleteNoticia)
+	noticias.Get("/usuario/:usuario_id", handlers.NoticiaHandler.GetNoticiasByUsuario)
+	noticias.Get("/titulo/:titulo", handlers.NoticiaHandler.GetNoticiasByTitulo)
+	noticias.Get("/descripcion/:descripcion", handlers.NoticiaHandler.GetNoticiasByDescripcion)
+	noticias.Get("/buscar/:termino", handlers.NoticiaHandler.SearchNoticiasV2)
+}
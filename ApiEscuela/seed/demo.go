@@ -0,0 +1,40 @@
+package seed
+
+import "ApiEscuela/models"
+
+// institucionesDemo son instituciones de ejemplo para desarrollo (correos ficticios)
+var institucionesDemo = []models.Institucion{
+	{Nombre: "Unidad Educativa Quevedo", Autoridad: "Rector", Contacto: "0999999901", Correo: "ue.quevedo@example.com", Direccion: "Quevedo, Los Ríos"},
+	{Nombre: "Unidad Educativa Nicolás Infante Díaz", Autoridad: "Rectora", Contacto: "0999999902", Correo: "ue.infantediaz@example.com", Direccion: "Quevedo, Los Ríos"},
+	{Nombre: "Unidad Educativa Siete de Octubre", Autoridad: "Rector", Contacto: "0999999903", Correo: "ue.sieteoctubre@example.com", Direccion: "Quevedo, Los Ríos"},
+	{Nombre: "Unidad Educativa Buena Fe", Autoridad: "Rector", Contacto: "0999999904", Correo: "ue.buenafe@example.com", Direccion: "Buena Fe, Los Ríos"},
+}
+
+// actividadDemo es una actividad de ejemplo con su duración en minutos
+type actividadDemo struct {
+	Nombre   string
+	Duracion int
+}
+
+// tematicasDemo son temáticas de ejemplo con sus actividades
+var tematicasDemo = []struct {
+	Nombre      string
+	Descripcion string
+	Actividades []actividadDemo
+}{
+	{"Ciencias de la Computación", "Software, redes y sistemas de información", []actividadDemo{
+		{"Recorrido por laboratorios de computación", 45},
+		{"Taller de programación para principiantes", 90},
+	}},
+	{"Ciencias Agropecuarias", "Producción agrícola y campus experimental", []actividadDemo{
+		{"Visita a la finca experimental", 120},
+		{"Charla sobre cultivos tropicales", 40},
+	}},
+	{"Ciencias Empresariales", "Administración, contabilidad y emprendimiento", []actividadDemo{
+		{"Feria de emprendimiento estudiantil", 60},
+	}},
+	{"Orientación Vocacional", "Oferta académica y vida universitaria", []actividadDemo{
+		{"Presentación de la oferta académica", 30},
+		{"Recorrido por el campus", 60},
+	}},
+}
@@ -0,0 +1,312 @@
+// Package seed carga los datos de referencia que necesita una instalación nueva:
+// provincias y cantones del Ecuador (DPA del INEC), tipos de usuario y un administrador.
+// Opcionalmente carga datos de demostración para desarrollo.
+//
+// Todas las operaciones son idempotentes: los registros existentes se reconocen por
+// código DPA o por nombre (sin distinguir mayúsculas ni tildes) y no se duplican.
+package seed
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+	"ApiEscuela/services"
+
+	"gorm.io/gorm"
+)
+
+// Repositorios agrupa los repositorios que usa la carga de datos
+type Repositorios struct {
+	Provincia     repositories.ProvinciaRepository
+	Ciudad        repositories.CiudadRepository
+	TipoUsuario   repositories.TipoUsuarioRepository
+	Persona       repositories.PersonaRepository
+	Usuario       repositories.UsuarioRepository
+	CodigoUsuario repositories.CodigoUsuarioRepository
+	Institucion   repositories.InstitucionRepository
+	Tematica      repositories.TematicaRepository
+	Actividad     repositories.ActividadRepository
+}
+
+// Admin contiene los datos del usuario administrador inicial
+type Admin struct {
+	Usuario string
+	Clave   string // si está vacía se genera una aleatoria
+	Cedula  string
+	Nombre  string
+	Correo  string
+}
+
+// Opciones controla qué datos se cargan
+type Opciones struct {
+	Admin Admin
+	Demo  bool
+}
+
+// Conteo resume los cambios hechos en una tabla
+type Conteo struct {
+	Creados      int
+	Actualizados int
+	Existentes   int
+}
+
+func (c Conteo) String() string {
+	return fmt.Sprintf("%d creados, %d actualizados, %d existentes", c.Creados, c.Actualizados, c.Existentes)
+}
+
+// Resultado resume la ejecución de la carga de datos
+type Resultado struct {
+	Provincias    Conteo
+	Ciudades      Conteo
+	TiposUsuario  Conteo
+	Instituciones Conteo
+	Tematicas     Conteo
+	Actividades   Conteo
+
+	AdminCreado   bool
+	AdminOmitido  bool   // no existe el administrador y no se indicó su cédula
+	ClaveGenerada string // clave del administrador cuando no se proporcionó una
+}
+
+// Run carga los datos de referencia y, si se pide, los de demostración
+func Run(r Repositorios, opts Opciones) (*Resultado, error) {
+	res := &Resultado{}
+	if err := cargarProvincias(r, res); err != nil {
+		return res, err
+	}
+	if err := cargarTiposUsuario(r, res); err != nil {
+		return res, err
+	}
+	if err := cargarAdmin(r, opts.Admin, res); err != nil {
+		return res, err
+	}
+	if opts.Demo {
+		if err := cargarDemo(r, res); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// normalizar compara nombres sin distinguir mayúsculas, tildes ni espacios repetidos
+func normalizar(s string) string {
+	s = strings.ToLower(strings.Join(strings.Fields(s), " "))
+	return strings.NewReplacer("á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u", "ñ", "n").Replace(s)
+}
+
+// cargarProvincias crea las provincias y cantones que falten y completa el código DPA de los existentes
+func cargarProvincias(r Repositorios, res *Resultado) error {
+	existentes, err := r.Provincia.GetAllProvincias()
+	if err != nil {
+		return fmt.Errorf("error al obtener las provincias: %v", err)
+	}
+
+	for _, dpa := range provinciasDPA {
+		provincia := buscarProvincia(existentes, dpa)
+		switch {
+		case provincia == nil:
+			provincia = &models.Provincia{Provincia: dpa.Nombre, Codigo: dpa.Codigo}
+			if err := r.Provincia.CreateProvincia(provincia); err != nil {
+				return fmt.Errorf("error al crear la provincia %s: %v", dpa.Nombre, err)
+			}
+			res.Provincias.Creados++
+		case provincia.Codigo != dpa.Codigo:
+			provincia.Codigo = dpa.Codigo
+			if err := r.Provincia.UpdateProvincia(provincia); err != nil {
+				return fmt.Errorf("error al actualizar la provincia %s: %v", dpa.Nombre, err)
+			}
+			res.Provincias.Actualizados++
+		default:
+			res.Provincias.Existentes++
+		}
+
+		if err := cargarCantones(r, provincia, dpa.Cantones, res); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func buscarProvincia(existentes []models.Provincia, dpa provinciaDPA) *models.Provincia {
+	for i := range existentes {
+		if existentes[i].Codigo == dpa.Codigo {
+			return &existentes[i]
+		}
+	}
+	for i := range existentes {
+		if existentes[i].Codigo == "" && normalizar(existentes[i].Provincia) == normalizar(dpa.Nombre) {
+			return &existentes[i]
+		}
+	}
+	return nil
+}
+
+func cargarCantones(r Repositorios, provincia *models.Provincia, cantones []canton, res *Resultado) error {
+	existentes, err := r.Ciudad.GetCiudadesByProvincia(provincia.ID)
+	if err != nil {
+		return fmt.Errorf("error al obtener las ciudades de %s: %v", provincia.Provincia, err)
+	}
+
+	for _, c := range cantones {
+		ciudad := buscarCiudad(existentes, c)
+		switch {
+		case ciudad == nil:
+			ciudad = &models.Ciudad{ProvinciaID: provincia.ID, Ciudad: c.Nombre, Codigo: c.Codigo}
+			if err := r.Ciudad.CreateCiudad(ciudad); err != nil {
+				return fmt.Errorf("error al crear la ciudad %s: %v", c.Nombre, err)
+			}
+			res.Ciudades.Creados++
+		case ciudad.Codigo != c.Codigo:
+			ciudad.Codigo = c.Codigo
+			if err := r.Ciudad.UpdateCiudad(ciudad); err != nil {
+				return fmt.Errorf("error al actualizar la ciudad %s: %v", c.Nombre, err)
+			}
+			res.Ciudades.Actualizados++
+		default:
+			res.Ciudades.Existentes++
+		}
+	}
+	return nil
+}
+
+func buscarCiudad(existentes []models.Ciudad, c canton) *models.Ciudad {
+	for i := range existentes {
+		if existentes[i].Codigo == c.Codigo {
+			return &existentes[i]
+		}
+	}
+	for i := range existentes {
+		if existentes[i].Codigo == "" && normalizar(existentes[i].Ciudad) == normalizar(c.Nombre) {
+			return &existentes[i]
+		}
+	}
+	return nil
+}
+
+// cargarTiposUsuario crea los roles base que falten
+func cargarTiposUsuario(r Repositorios, res *Resultado) error {
+	existentes, err := r.TipoUsuario.GetAllTiposUsuario()
+	if err != nil {
+		return fmt.Errorf("error al obtener los tipos de usuario: %v", err)
+	}
+	nombres := map[string]bool{}
+	for _, tipo := range existentes {
+		nombres[normalizar(tipo.Nombre)] = true
+	}
+
+	for _, base := range tiposUsuarioBase {
+		if nombres[normalizar(base.Nombre)] {
+			res.TiposUsuario.Existentes++
+			continue
+		}
+		tipo := &models.TipoUsuario{Nombre: base.Nombre, Descripcion: base.Descripcion}
+		if err := r.TipoUsuario.CreateTipoUsuario(tipo); err != nil {
+			return fmt.Errorf("error al crear el tipo de usuario %s: %v", base.Nombre, err)
+		}
+		res.TiposUsuario.Creados++
+	}
+	return nil
+}
+
+// cargarAdmin crea el usuario administrador si no existe un usuario con ese nombre
+func cargarAdmin(r Repositorios, admin Admin, res *Resultado) error {
+	if admin.Usuario == "" {
+		admin.Usuario = "admin"
+	}
+	if _, err := r.Usuario.GetUsuarioByUsernameIncludingDeleted(admin.Usuario); err == nil {
+		return nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("error al buscar el usuario %s: %v", admin.Usuario, err)
+	}
+
+	// La persona del administrador necesita una cédula real; sin ella no se crea
+	if admin.Cedula == "" {
+		res.AdminOmitido = true
+		return nil
+	}
+
+	authService := services.NewAuthService(r.Usuario, r.Persona, r.CodigoUsuario)
+	adminService := services.NewAdminService(r.Usuario, r.Persona, r.TipoUsuario, r.CodigoUsuario, authService)
+	_, generada, err := adminService.CreateAdmin(services.NuevoAdmin{
+		Usuario: admin.Usuario,
+		Clave:   admin.Clave,
+		Cedula:  admin.Cedula,
+		Nombre:  admin.Nombre,
+		Correo:  admin.Correo,
+	})
+	if err != nil {
+		return fmt.Errorf("error al crear el administrador: %v", err)
+	}
+	res.AdminCreado = true
+	res.ClaveGenerada = generada
+	return nil
+}
+
+// cargarDemo crea instituciones, temáticas y actividades de ejemplo que no existan
+func cargarDemo(r Repositorios, res *Resultado) error {
+	instituciones, err := r.Institucion.GetAllInstituciones()
+	if err != nil {
+		return fmt.Errorf("error al obtener las instituciones: %v", err)
+	}
+	nombres := map[string]bool{}
+	for _, inst := range instituciones {
+		nombres[normalizar(inst.Nombre)] = true
+	}
+	for _, demo := range institucionesDemo {
+		if nombres[normalizar(demo.Nombre)] {
+			res.Instituciones.Existentes++
+			continue
+		}
+		inst := demo
+		if err := r.Institucion.CreateInstitucion(&inst); err != nil {
+			return fmt.Errorf("error al crear la institución %s: %v", demo.Nombre, err)
+		}
+		res.Instituciones.Creados++
+	}
+
+	tematicas, err := r.Tematica.GetAllTematicas()
+	if err != nil {
+		return fmt.Errorf("error al obtener las temáticas: %v", err)
+	}
+	porNombre := map[string]uint{}
+	for _, t := range tematicas {
+		porNombre[normalizar(t.Nombre)] = t.ID
+	}
+	for _, demo := range tematicasDemo {
+		id, ok := porNombre[normalizar(demo.Nombre)]
+		if ok {
+			res.Tematicas.Existentes++
+		} else {
+			tematica := &models.Tematica{Nombre: demo.Nombre, Descripcion: demo.Descripcion}
+			if err := r.Tematica.CreateTematica(tematica); err != nil {
+				return fmt.Errorf("error al crear la temática %s: %v", demo.Nombre, err)
+			}
+			id = tematica.ID
+			res.Tematicas.Creados++
+		}
+
+		actividades, err := r.Actividad.GetActividadesByTematica(id)
+		if err != nil {
+			return fmt.Errorf("error al obtener las actividades de %s: %v", demo.Nombre, err)
+		}
+		existentes := map[string]bool{}
+		for _, a := range actividades {
+			existentes[normalizar(a.Actividad)] = true
+		}
+		for _, a := range demo.Actividades {
+			if existentes[normalizar(a.Nombre)] {
+				res.Actividades.Existentes++
+				continue
+			}
+			actividad := &models.Actividad{Actividad: a.Nombre, TematicaID: id, Duracion: a.Duracion}
+			if err := r.Actividad.CreateActividad(actividad); err != nil {
+				return fmt.Errorf("error al crear la actividad %s: %v", a.Nombre, err)
+			}
+			res.Actividades.Creados++
+		}
+	}
+	return nil
+}
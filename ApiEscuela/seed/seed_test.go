@@ -0,0 +1,135 @@
+package seed
+
+import (
+	"testing"
+
+	"ApiEscuela/models"
+	"ApiEscuela/repositories/memory"
+)
+
+func memoria() (Repositorios, *memory.Repositories) {
+	m := memory.NewRepositories()
+	return Repositorios{
+		Provincia:     m.Provincia,
+		Ciudad:        m.Ciudad,
+		TipoUsuario:   m.TipoUsuario,
+		Persona:       m.Persona,
+		Usuario:       m.Usuario,
+		CodigoUsuario: m.CodigoUsuario,
+		Institucion:   m.Institucion,
+		Tematica:      m.Tematica,
+		Actividad:     m.Actividad,
+	}, m
+}
+
+func TestDatosDPA(t *testing.T) {
+	if len(provinciasDPA) != 24 {
+		t.Fatalf("provincias = %d, want 24", len(provinciasDPA))
+	}
+	codigos := map[string]bool{}
+	total := 0
+	for _, p := range provinciasDPA {
+		for _, c := range p.Cantones {
+			if c.Codigo[:2] != p.Codigo {
+				t.Errorf("cantón %s (%s) no pertenece a la provincia %s", c.Nombre, c.Codigo, p.Codigo)
+			}
+			if codigos[c.Codigo] {
+				t.Errorf("código de cantón repetido: %s", c.Codigo)
+			}
+			codigos[c.Codigo] = true
+			total++
+		}
+	}
+	if total != 221 {
+		t.Errorf("cantones = %d, want 221", total)
+	}
+}
+
+func TestRunIdempotente(t *testing.T) {
+	r, _ := memoria()
+	opts := Opciones{Admin: Admin{Usuario: "admin", Cedula: "1200000000"}, Demo: true}
+
+	primero, err := Run(r, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if primero.Provincias.Creados != 24 || primero.Ciudades.Creados != 221 || primero.TiposUsuario.Creados != 3 {
+		t.Fatalf("primera carga: %+v", primero)
+	}
+	if !primero.AdminCreado || primero.ClaveGenerada == "" {
+		t.Fatalf("el administrador debe crearse con una clave generada: %+v", primero)
+	}
+
+	segundo, err := Run(r, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for nombre, c := range map[string]Conteo{
+		"provincias": segundo.Provincias, "ciudades": segundo.Ciudades, "tipos": segundo.TiposUsuario,
+		"instituciones": segundo.Instituciones, "tematicas": segundo.Tematicas, "actividades": segundo.Actividades,
+	} {
+		if c.Creados != 0 || c.Actualizados != 0 {
+			t.Errorf("%s: la segunda carga no debe cambiar nada: %s", nombre, c)
+		}
+	}
+	if segundo.AdminCreado {
+		t.Error("el administrador no debe crearse dos veces")
+	}
+
+	tipos, _ := r.TipoUsuario.GetAllTiposUsuario()
+	if len(tipos) != 3 {
+		t.Errorf("tipos de usuario = %d, want 3", len(tipos))
+	}
+	if _, err := r.TipoUsuario.GetTipoUsuarioByNombre("Estudiante"); err != nil {
+		t.Errorf("la carga masiva necesita el tipo Estudiante: %v", err)
+	}
+	admin, err := r.Usuario.GetUsuarioByUsername("admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if admin.Verificado {
+		t.Error("el administrador debe cambiar la contraseña inicial")
+	}
+}
+
+func TestRunCompletaDatosExistentes(t *testing.T) {
+	r, _ := memoria()
+
+	// Datos cargados a mano antes de existir el seed: sin código y con otra capitalización
+	provincia := &models.Provincia{Provincia: "LOS RIOS"}
+	if err := r.Provincia.CreateProvincia(provincia); err != nil {
+		t.Fatal(err)
+	}
+	ciudad := &models.Ciudad{ProvinciaID: provincia.ID, Ciudad: "quevedo"}
+	if err := r.Ciudad.CreateCiudad(ciudad); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.TipoUsuario.CreateTipoUsuario(&models.TipoUsuario{Nombre: "administrador"}); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Run(r, Opciones{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Provincias.Creados != 23 || res.Provincias.Actualizados != 1 {
+		t.Errorf("provincias: %s", res.Provincias)
+	}
+	if res.Ciudades.Creados != 220 || res.Ciudades.Actualizados != 1 {
+		t.Errorf("ciudades: %s", res.Ciudades)
+	}
+	if res.TiposUsuario.Creados != 2 || res.TiposUsuario.Existentes != 1 {
+		t.Errorf("tipos de usuario: %s", res.TiposUsuario)
+	}
+	if !res.AdminOmitido || res.AdminCreado {
+		t.Errorf("sin cédula el administrador se omite: %+v", res)
+	}
+
+	actualizada, err := r.Ciudad.GetCiudadByID(ciudad.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actualizada.Codigo != "1205" || actualizada.Ciudad != "quevedo" {
+		t.Errorf("la ciudad existente solo recibe su código: %+v", actualizada)
+	}
+}
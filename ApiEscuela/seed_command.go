@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"ApiEscuela/repositories"
+	"ApiEscuela/seed"
+
+	"gorm.io/gorm"
+)
+
+// ejecutarSeed carga los datos de referencia; se puede ejecutar varias veces sin duplicar datos
+func ejecutarSeed(db *gorm.DB, args []string) error {
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	demo := fs.Bool("demo", false, "cargar también instituciones, temáticas y actividades de demostración")
+	var admin seed.Admin
+	fs.StringVar(&admin.Usuario, "admin-usuario", envOr("SEED_ADMIN_USUARIO", "admin"), "nombre de usuario del administrador")
+	fs.StringVar(&admin.Clave, "admin-clave", os.Getenv("SEED_ADMIN_CLAVE"), "contraseña del administrador (se genera si está vacía)")
+	fs.StringVar(&admin.Cedula, "admin-cedula", os.Getenv("SEED_ADMIN_CEDULA"), "cédula de la persona del administrador")
+	fs.StringVar(&admin.Nombre, "admin-nombre", envOr("SEED_ADMIN_NOMBRE", "Administrador"), "nombre de la persona del administrador")
+	fs.StringVar(&admin.Correo, "admin-correo", os.Getenv("SEED_ADMIN_CORREO"), "correo de la persona del administrador")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	res, err := seed.Run(seed.Repositorios{
+		Provincia:     repositories.NewProvinciaRepository(db),
+		Ciudad:        repositories.NewCiudadRepository(db),
+		TipoUsuario:   repositories.NewTipoUsuarioRepository(db),
+		Persona:       repositories.NewPersonaRepository(db),
+		Usuario:       repositories.NewUsuarioRepository(db),
+		CodigoUsuario: repositories.NewCodigoUsuarioRepository(db),
+		Institucion:   repositories.NewInstitucionRepository(db),
+		Tematica:      repositories.NewTematicaRepository(db),
+		Actividad:     repositories.NewActividadRepository(db),
+	}, seed.Opciones{Admin: admin, Demo: *demo})
+	if res != nil {
+		fmt.Printf("Provincias:        %s\n", res.Provincias)
+		fmt.Printf("Ciudades:          %s\n", res.Ciudades)
+		fmt.Printf("Tipos de usuario:  %s\n", res.TiposUsuario)
+		if *demo {
+			fmt.Printf("Instituciones:     %s\n", res.Instituciones)
+			fmt.Printf("Temáticas:         %s\n", res.Tematicas)
+			fmt.Printf("Actividades:       %s\n", res.Actividades)
+		}
+		switch {
+		case res.AdminCreado && res.ClaveGenerada != "":
+			fmt.Printf("Administrador %q creado con la contraseña temporal: %s\n", admin.Usuario, res.ClaveGenerada)
+		case res.AdminCreado:
+			fmt.Printf("Administrador %q creado\n", admin.Usuario)
+		case res.AdminOmitido:
+			fmt.Printf("Administrador %q no creado: indique --admin-cedula (o SEED_ADMIN_CEDULA)\n", admin.Usuario)
+		default:
+			fmt.Printf("Administrador %q ya existe\n", admin.Usuario)
+		}
+	}
+	return err
+}
+
+// envOr devuelve la variable de entorno o el valor por defecto si está vacía
+func envOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrUsuarioExiste se devuelve al crear un usuario cuyo nombre ya está registrado (aunque esté eliminado)
+var ErrUsuarioExiste = errors.New("el usuario ya existe")
+
+// adminService implementa las tareas operativas sobre usuarios y códigos
+type adminService struct {
+	usuarioRepo       repositories.UsuarioRepository
+	personaRepo       repositories.PersonaRepository
+	tipoUsuarioRepo   repositories.TipoUsuarioRepository
+	codigoUsuarioRepo repositories.CodigoUsuarioRepository
+	authService       AuthService
+}
+
+// NewAdminService crea una nueva instancia del servicio
+func NewAdminService(
+	usuarioRepo repositories.UsuarioRepository,
+	personaRepo repositories.PersonaRepository,
+	tipoUsuarioRepo repositories.TipoUsuarioRepository,
+	codigoUsuarioRepo repositories.CodigoUsuarioRepository,
+	authService AuthService,
+) AdminService {
+	return &adminService{
+		usuarioRepo:       usuarioRepo,
+		personaRepo:       personaRepo,
+		tipoUsuarioRepo:   tipoUsuarioRepo,
+		codigoUsuarioRepo: codigoUsuarioRepo,
+		authService:       authService,
+	}
+}
+
+// NuevoAdmin contiene los datos para crear un administrador
+type NuevoAdmin struct {
+	Usuario string
+	Clave   string // si está vacía se genera una contraseña temporal
+	Cedula  string
+	Nombre  string
+	Correo  string
+}
+
+// DesbloqueoResult resume lo que hizo UnlockUsuario
+type DesbloqueoResult struct {
+	Restaurado       bool  // el usuario estaba eliminado y se restauró
+	CodigosExpirados int64 // códigos de recuperación vigentes que se invalidaron
+}
+
+// CreateAdmin crea un usuario con el tipo Administrador. Reutiliza la persona si la cédula ya existe.
+// Devuelve la contraseña temporal cuando no se proporcionó una.
+func (s *adminService) CreateAdmin(datos NuevoAdmin) (*models.Usuario, string, error) {
+	datos.Usuario = strings.TrimSpace(datos.Usuario)
+	datos.Cedula = strings.TrimSpace(datos.Cedula)
+	if datos.Usuario == "" {
+		return nil, "", errors.New("el nombre de usuario es requerido")
+	}
+	if datos.Cedula == "" {
+		return nil, "", errors.New("la cédula es requerida")
+	}
+
+	if _, err := s.usuarioRepo.GetUsuarioByUsernameIncludingDeleted(datos.Usuario); err == nil {
+		return nil, "", ErrUsuarioExiste
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, "", fmt.Errorf("error al buscar el usuario: %v", err)
+	}
+
+	tipo, err := s.tipoAdministrador()
+	if err != nil {
+		return nil, "", err
+	}
+
+	persona, err := s.personaRepo.GetPersonaByCedula(datos.Cedula)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		persona = &models.Persona{Nombre: strings.TrimSpace(datos.Nombre), Cedula: datos.Cedula}
+		if persona.Nombre == "" {
+			persona.Nombre = "Administrador"
+		}
+		if correo := strings.TrimSpace(datos.Correo); correo != "" {
+			persona.Correo = &correo
+		}
+		if err := s.personaRepo.CreatePersona(persona); err != nil {
+			return nil, "", fmt.Errorf("error al crear la persona: %v", err)
+		}
+	} else if err != nil {
+		return nil, "", fmt.Errorf("error al buscar la persona: %v", err)
+	}
+
+	clave, generada, err := claveOTemporal(datos.Clave)
+	if err != nil {
+		return nil, "", err
+	}
+	hash, err := s.authService.HashPassword(clave)
+	if err != nil {
+		return nil, "", fmt.Errorf("error al encriptar la contraseña: %v", err)
+	}
+
+	usuario := &models.Usuario{
+		Usuario:       datos.Usuario,
+		Contraseña:    hash,
+		PersonaID:     persona.ID,
+		TipoUsuarioID: tipo.ID,
+		// Sin verificar: el primer login pide cambiar la contraseña
+		Verificado: false,
+	}
+	if err := s.usuarioRepo.CreateUsuario(usuario); err != nil {
+		return nil, "", fmt.Errorf("error al crear el usuario: %v", err)
+	}
+	return usuario, generada, nil
+}
+
+// tipoAdministrador busca el tipo de usuario Administrador por nombre exacto
+// (GetTipoUsuarioByNombre también encontraría "CoAdministrador")
+func (s *adminService) tipoAdministrador() (*models.TipoUsuario, error) {
+	tipos, err := s.tipoUsuarioRepo.GetAllTiposUsuario()
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener los tipos de usuario: %v", err)
+	}
+	for i := range tipos {
+		if strings.EqualFold(strings.TrimSpace(tipos[i].Nombre), "Administrador") {
+			return &tipos[i], nil
+		}
+	}
+	return nil, errors.New("tipo de usuario Administrador no encontrado; ejecute primero el comando seed")
+}
+
+// ResetPassword asigna una nueva contraseña al usuario y le exige cambiarla en el próximo login.
+// Devuelve la contraseña temporal cuando no se proporcionó una.
+func (s *adminService) ResetPassword(username, clave string) (string, error) {
+	usuario, err := s.usuarioRepo.GetUsuarioByUsername(username)
+	if err != nil {
+		return "", fmt.Errorf("usuario no encontrado: %s", username)
+	}
+
+	clave, generada, err := claveOTemporal(clave)
+	if err != nil {
+		return "", err
+	}
+	hash, err := s.authService.HashPassword(clave)
+	if err != nil {
+		return "", fmt.Errorf("error al encriptar la contraseña: %v", err)
+	}
+	if err := s.usuarioRepo.UpdatePassword(usuario.ID, hash); err != nil {
+		return "", errors.New("error al actualizar la contraseña")
+	}
+
+	// Volver a cargar el usuario para no sobrescribir la contraseña recién guardada
+	usuario, err = s.usuarioRepo.GetUsuarioByID(usuario.ID)
+	if err != nil {
+		return "", err
+	}
+	usuario.Verificado = false
+	if err := s.usuarioRepo.UpdateUsuario(usuario); err != nil {
+		return "", fmt.Errorf("error al actualizar el usuario: %v", err)
+	}
+	return generada, nil
+}
+
+// UnlockUsuario devuelve el acceso a un usuario: lo restaura si fue eliminado e invalida
+// los códigos de recuperación vigentes que impiden solicitar uno nuevo ("codigo ya enviado")
+func (s *adminService) UnlockUsuario(username string) (*DesbloqueoResult, error) {
+	usuario, err := s.usuarioRepo.GetUsuarioByUsernameIncludingDeleted(username)
+	if err != nil {
+		return nil, fmt.Errorf("usuario no encontrado: %s", username)
+	}
+
+	result := &DesbloqueoResult{}
+	if usuario.DeletedAt.Valid {
+		if err := s.usuarioRepo.RestoreUsuario(usuario.ID); err != nil {
+			return nil, fmt.Errorf("error al restaurar el usuario: %v", err)
+		}
+		result.Restaurado = true
+	}
+
+	result.CodigosExpirados, err = s.codigoUsuarioRepo.ExpirarVigentesPorUsuario(usuario.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error al invalidar los códigos: %v", err)
+	}
+	return result, nil
+}
+
+// PurgeExpiredCodes elimina definitivamente los códigos verificados o expirados antes de la fecha indicada
+func (s *adminService) PurgeExpiredCodes(antes time.Time) (int64, error) {
+	return s.codigoUsuarioRepo.PurgarExpirados(antes)
+}
+
+// claveOTemporal devuelve la clave indicada o, si está vacía, una temporal aleatoria (también como segundo valor)
+func claveOTemporal(clave string) (string, string, error) {
+	if clave != "" {
+		if len(clave) < 6 {
+			return "", "", errors.New("la contraseña debe tener al menos 6 caracteres")
+		}
+		return clave, "", nil
+	}
+	b := make([]byte, 9)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("error al generar la contraseña: %v", err)
+	}
+	temporal := hex.EncodeToString(b)
+	return temporal, temporal, nil
+}
@@ -0,0 +1,120 @@
+package services_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"ApiEscuela/repositories"
+	"ApiEscuela/services"
+	"ApiEscuela/testutil"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(testutil.RunMain(m))
+}
+
+func nuevoAdminService(repos testutil.Repos) (services.AdminService, services.AuthService) {
+	auth := services.NewAuthService(repos.Usuario, repos.Persona, repos.CodigoUsuario)
+	return services.NewAdminService(repos.Usuario, repos.Persona, repos.TipoUsuario, repos.CodigoUsuario, auth), auth
+}
+
+func TestCreateAdmin(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		admin, auth := nuevoAdminService(repos)
+
+		f.TipoUsuario("CoAdministrador")
+		if _, _, err := admin.CreateAdmin(services.NuevoAdmin{Usuario: "root", Cedula: "0912345678"}); err == nil {
+			t.Fatal("se esperaba error sin el tipo Administrador")
+		}
+		tipo := f.TipoUsuario("Administrador")
+
+		usuario, generada, err := admin.CreateAdmin(services.NuevoAdmin{Usuario: "root", Cedula: "0912345678", Nombre: "Soporte"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if usuario.TipoUsuarioID != tipo.ID || usuario.Verificado {
+			t.Errorf("usuario = tipo %d verificado %v, se esperaba tipo %d sin verificar", usuario.TipoUsuarioID, usuario.Verificado, tipo.ID)
+		}
+		if generada == "" {
+			t.Fatal("se esperaba una contraseña temporal")
+		}
+		if _, err := auth.Login(services.LoginRequest{Usuario: "root", Contraseña: generada}); err != nil {
+			t.Errorf("login con la contraseña temporal: %v", err)
+		}
+
+		// La persona se reutiliza por cédula
+		otro, generada, err := admin.CreateAdmin(services.NuevoAdmin{Usuario: "root2", Cedula: "0912345678", Clave: "segura123"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if otro.PersonaID != usuario.PersonaID || generada != "" {
+			t.Errorf("persona = %d, generada = %q", otro.PersonaID, generada)
+		}
+
+		if _, _, err := admin.CreateAdmin(services.NuevoAdmin{Usuario: "root", Cedula: "0999999999"}); !errors.Is(err, services.ErrUsuarioExiste) {
+			t.Errorf("err = %v, se esperaba ErrUsuarioExiste", err)
+		}
+		if _, _, err := admin.CreateAdmin(services.NuevoAdmin{Usuario: "root3", Cedula: "0999999999", Clave: "corta"}); err == nil {
+			t.Error("se esperaba error por contraseña corta")
+		}
+	})
+}
+
+func TestResetPasswordYUnlock(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		admin, auth := nuevoAdminService(repos)
+
+		if _, err := admin.ResetPassword("admin", "nueva-clave"); err != nil {
+			t.Fatal(err)
+		}
+		resp, err := auth.Login(services.LoginRequest{Usuario: "admin", Contraseña: "nueva-clave"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !resp.RequiereCambioPassword {
+			t.Error("se esperaba requiere_cambio_password tras restablecer la contraseña")
+		}
+		if _, err := admin.ResetPassword("no-existe", ""); err == nil {
+			t.Error("se esperaba error para un usuario inexistente")
+		}
+
+		f.Codigo(e.Admin, "123456")
+		if err := repos.Usuario.DeleteUsuario(e.Admin.ID); err != nil {
+			t.Fatal(err)
+		}
+		result, err := admin.UnlockUsuario("admin")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.Restaurado || result.CodigosExpirados != 1 {
+			t.Errorf("result = %+v, se esperaba restaurado con 1 código expirado", result)
+		}
+		if _, err := repos.Usuario.GetUsuarioByUsername("admin"); err != nil {
+			t.Errorf("el usuario debería estar activo: %v", err)
+		}
+		codigo, err := repos.CodigoUsuario.FindLatestByCodigo("123456")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if codigo.Estado != repositories.EstadoExpirado {
+			t.Errorf("estado = %q, se esperaba %q", codigo.Estado, repositories.EstadoExpirado)
+		}
+
+		// Solo se purgan los códigos que dejaron de servir antes del corte
+		if n, err := admin.PurgeExpiredCodes(time.Now().Add(-time.Hour)); err != nil || n != 0 {
+			t.Errorf("purga con corte anterior = %d, %v; se esperaba 0", n, err)
+		}
+		f.Codigo(e.Admin, "654321")
+		if n, err := admin.PurgeExpiredCodes(time.Now().Add(time.Minute)); err != nil || n != 1 {
+			t.Errorf("purga = %d, %v; se esperaba 1", n, err)
+		}
+		if _, err := repos.CodigoUsuario.FindLatestByCodigo("654321"); err != nil {
+			t.Errorf("el código vigente no debería purgarse: %v", err)
+		}
+	})
+}
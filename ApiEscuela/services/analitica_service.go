@@ -0,0 +1,437 @@
+package services
+
+import (
+	"ApiEscuela/errores"
+	"ApiEscuela/repositories"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Límites de las consultas de la analítica
+const (
+	LimiteAnaliticaMaximo = 50
+	limiteAnalitica       = 10
+	// mesesAnalitica es cuántos meses anteriores al actual incluye el rango por defecto
+	mesesAnalitica = 11
+	// diasAgrupacionDiaria es el rango más largo que se puede agrupar por día
+	diasAgrupacionDiaria = 366
+)
+
+// Secciones del panel de analítica, que también son las claves de la caché
+const (
+	seccionVisitas     = "visitas"
+	seccionEstudiantes = "estudiantes"
+	seccionTematicas   = "tematicas"
+	seccionActividades = "actividades"
+	seccionAutoridades = "autoridades"
+	seccionDudas       = "dudas"
+	seccionComunicados = "comunicados"
+	seccionPanel       = "panel"
+)
+
+// Agrupaciones y dimensiones válidas, en el orden en que se muestran en los errores
+var (
+	AgrupacionesAnalitica = []string{repositories.AgruparDia, repositories.AgruparSemana, repositories.AgruparMes, repositories.AgruparAnio}
+	DimensionesAnalitica  = []string{repositories.AnaliticaPorProvincia, repositories.AnaliticaPorCiudad, repositories.AnaliticaPorInstitucion, repositories.AnaliticaPorEspecialidad}
+)
+
+// ConsultaAnalitica son los parámetros de la analítica. Desde y Hasta (incluido) van en formato
+// YYYY-MM-DD; vacíos toman los últimos doce meses hasta hoy. Agrupar es el período de las series
+// (mes por defecto), Por la dimensión de los estudiantes alcanzados (institución por defecto) y
+// Limite la cantidad de temáticas y actividades (10 por defecto).
+type ConsultaAnalitica struct {
+	Desde   string
+	Hasta   string
+	Agrupar string
+	Por     string
+	Limite  int
+}
+
+// EncabezadoAnalitica describe el rango de una respuesta y cuándo se calculó; los agregados se
+// guardan en caché, así que GeneradoEn puede ser anterior a la petición
+type EncabezadoAnalitica struct {
+	Desde      string    `json:"desde"`
+	Hasta      string    `json:"hasta"`
+	GeneradoEn time.Time `json:"generado_en"`
+}
+
+// SerieVisitas son los programas de visita por período, incluidos los períodos sin visitas
+type SerieVisitas struct {
+	*EncabezadoAnalitica
+	Agrupacion string                       `json:"agrupacion"`
+	Total      int64                        `json:"total"`
+	Periodos   []repositories.ConteoPeriodo `json:"periodos"`
+}
+
+// EstudiantesAlcanzados son los estudiantes de las instituciones visitadas por grupo de la dimensión
+type EstudiantesAlcanzados struct {
+	*EncabezadoAnalitica
+	Por    string                     `json:"por"`
+	Total  int64                      `json:"total"`
+	Grupos []repositories.ConteoGrupo `json:"grupos"`
+}
+
+// RankingAnalitica son las temáticas o actividades con más visitas
+type RankingAnalitica struct {
+	*EncabezadoAnalitica
+	Limite int                        `json:"limite"`
+	Grupos []repositories.ConteoGrupo `json:"grupos"`
+}
+
+// CargaAutoridades es la carga de trabajo de cada autoridad activa, las más ocupadas primero
+type CargaAutoridades struct {
+	*EncabezadoAnalitica
+	Autoridades []repositories.CargaAutoridad `json:"autoridades"`
+}
+
+// TiemposDudas son los tiempos de respuesta de las dudas del rango y por período
+type TiemposDudas struct {
+	*EncabezadoAnalitica
+	Agrupacion string                          `json:"agrupacion"`
+	Resumen    repositories.TiemposRespuesta   `json:"resumen"`
+	Periodos   []repositories.TiemposRespuesta `json:"periodos"`
+}
+
+// AlcanceComunicados es el alcance de los comunicados del rango por canal
+type AlcanceComunicados struct {
+	*EncabezadoAnalitica
+	Canales []repositories.AlcanceCanal `json:"canales"`
+}
+
+// PanelAnalitica reúne todas las secciones para cargar el dashboard con una sola petición; las
+// secciones no repiten el encabezado
+type PanelAnalitica struct {
+	*EncabezadoAnalitica
+	Visitas     SerieVisitas          `json:"visitas"`
+	Estudiantes EstudiantesAlcanzados `json:"estudiantes"`
+	Tematicas   RankingAnalitica      `json:"tematicas"`
+	Actividades RankingAnalitica      `json:"actividades"`
+	Autoridades CargaAutoridades      `json:"autoridades"`
+	Dudas       TiemposDudas          `json:"dudas"`
+	Comunicados AlcanceComunicados    `json:"comunicados"`
+}
+
+// consultaValidada es una ConsultaAnalitica con los valores por defecto aplicados
+type consultaValidada struct {
+	rango        repositories.RangoAnalitica
+	desde, hasta string // fechas del rango, con Hasta incluido
+	agrupar, por string
+	limite       int
+}
+
+// clave identifica la consulta de la sección en la caché
+func (q consultaValidada) clave(seccion string) string {
+	return strings.Join([]string{seccion, q.desde, q.hasta, q.agrupar, q.por, strconv.Itoa(q.limite)}, "|")
+}
+
+// encabezado es el encabezado de una respuesta calculada en el instante indicado
+func (q consultaValidada) encabezado(generado time.Time) *EncabezadoAnalitica {
+	return &EncabezadoAnalitica{Desde: q.desde, Hasta: q.hasta, GeneradoEn: generado}
+}
+
+// entradaAnalitica es un agregado calculado y su vencimiento
+type entradaAnalitica struct {
+	valor  interface{}
+	expira time.Time
+}
+
+// analiticaService calcula los agregados del dashboard y los guarda en memoria durante ttl
+type analiticaService struct {
+	repo repositories.AnaliticaRepository
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu       sync.Mutex
+	entradas map[string]entradaAnalitica
+}
+
+// NewAnaliticaService crea el servicio; con ttl <= 0 los agregados se calculan en cada petición
+func NewAnaliticaService(repo repositories.AnaliticaRepository, ttl time.Duration) AnaliticaService {
+	return &analiticaService{repo: repo, ttl: ttl, now: time.Now, entradas: map[string]entradaAnalitica{}}
+}
+
+// Panel devuelve todas las secciones de la analítica
+func (s *analiticaService) Panel(consulta ConsultaAnalitica) (*PanelAnalitica, error) {
+	q, err := s.validar(consulta)
+	if err != nil {
+		return nil, err
+	}
+	return s.panel(q, false)
+}
+
+// Refrescar vuelve a calcular el panel por defecto aunque esté en caché, para que el dashboard
+// lo encuentre listo
+func (s *analiticaService) Refrescar() error {
+	q, err := s.validar(ConsultaAnalitica{})
+	if err != nil {
+		return err
+	}
+	_, err = s.panel(q, true)
+	return err
+}
+
+func (s *analiticaService) panel(q consultaValidada, forzar bool) (*PanelAnalitica, error) {
+	return cacheado(s, q.clave(seccionPanel), forzar, func(generado time.Time) (*PanelAnalitica, error) {
+		panel := &PanelAnalitica{EncabezadoAnalitica: q.encabezado(generado)}
+		var err error
+		if panel.Visitas, err = s.visitas(q, nil); err != nil {
+			return nil, err
+		}
+		if panel.Estudiantes, err = s.estudiantes(q, nil); err != nil {
+			return nil, err
+		}
+		if panel.Tematicas, err = s.tematicas(q, nil); err != nil {
+			return nil, err
+		}
+		if panel.Actividades, err = s.actividades(q, nil); err != nil {
+			return nil, err
+		}
+		if panel.Autoridades, err = s.autoridades(q, nil); err != nil {
+			return nil, err
+		}
+		if panel.Dudas, err = s.dudas(q, nil); err != nil {
+			return nil, err
+		}
+		if panel.Comunicados, err = s.comunicados(q, nil); err != nil {
+			return nil, err
+		}
+		return panel, nil
+	})
+}
+
+// Visitas devuelve las visitas por período
+func (s *analiticaService) Visitas(consulta ConsultaAnalitica) (*SerieVisitas, error) {
+	return seccion(s, consulta, seccionVisitas, s.visitas)
+}
+
+// Estudiantes devuelve los estudiantes alcanzados por la dimensión pedida
+func (s *analiticaService) Estudiantes(consulta ConsultaAnalitica) (*EstudiantesAlcanzados, error) {
+	return seccion(s, consulta, seccionEstudiantes, s.estudiantes)
+}
+
+// Tematicas devuelve las temáticas con más visitas
+func (s *analiticaService) Tematicas(consulta ConsultaAnalitica) (*RankingAnalitica, error) {
+	return seccion(s, consulta, seccionTematicas, s.tematicas)
+}
+
+// Actividades devuelve las actividades con más visitas
+func (s *analiticaService) Actividades(consulta ConsultaAnalitica) (*RankingAnalitica, error) {
+	return seccion(s, consulta, seccionActividades, s.actividades)
+}
+
+// Autoridades devuelve la carga de trabajo de las autoridades
+func (s *analiticaService) Autoridades(consulta ConsultaAnalitica) (*CargaAutoridades, error) {
+	return seccion(s, consulta, seccionAutoridades, s.autoridades)
+}
+
+// Dudas devuelve los tiempos de respuesta de las dudas
+func (s *analiticaService) Dudas(consulta ConsultaAnalitica) (*TiemposDudas, error) {
+	return seccion(s, consulta, seccionDudas, s.dudas)
+}
+
+// Comunicados devuelve el alcance de los comunicados por canal
+func (s *analiticaService) Comunicados(consulta ConsultaAnalitica) (*AlcanceComunicados, error) {
+	return seccion(s, consulta, seccionComunicados, s.comunicados)
+}
+
+// Las secciones se calculan con el encabezado indicado, nil dentro del panel
+
+func (s *analiticaService) visitas(q consultaValidada, enc *EncabezadoAnalitica) (SerieVisitas, error) {
+	conteos, err := s.repo.VisitasPorPeriodo(q.rango, q.agrupar)
+	if err != nil {
+		return SerieVisitas{}, err
+	}
+	serie := SerieVisitas{EncabezadoAnalitica: enc, Agrupacion: q.agrupar, Periodos: []repositories.ConteoPeriodo{}}
+	totales := map[string]int64{}
+	for _, c := range conteos {
+		totales[c.Periodo] = c.Total
+		serie.Total += c.Total
+	}
+	for _, periodo := range periodosAnalitica(q) {
+		serie.Periodos = append(serie.Periodos, repositories.ConteoPeriodo{Periodo: periodo, Total: totales[periodo]})
+	}
+	return serie, nil
+}
+
+func (s *analiticaService) estudiantes(q consultaValidada, enc *EncabezadoAnalitica) (EstudiantesAlcanzados, error) {
+	grupos, err := s.repo.EstudiantesAlcanzados(q.rango, q.por)
+	if err != nil {
+		return EstudiantesAlcanzados{}, err
+	}
+	alcanzados := EstudiantesAlcanzados{EncabezadoAnalitica: enc, Por: q.por, Grupos: grupos}
+	for _, g := range grupos {
+		alcanzados.Total += g.Total
+	}
+	return alcanzados, nil
+}
+
+func (s *analiticaService) tematicas(q consultaValidada, enc *EncabezadoAnalitica) (RankingAnalitica, error) {
+	grupos, err := s.repo.TematicasPrincipales(q.rango, q.limite)
+	return RankingAnalitica{EncabezadoAnalitica: enc, Limite: q.limite, Grupos: grupos}, err
+}
+
+func (s *analiticaService) actividades(q consultaValidada, enc *EncabezadoAnalitica) (RankingAnalitica, error) {
+	grupos, err := s.repo.ActividadesPrincipales(q.rango, q.limite)
+	return RankingAnalitica{EncabezadoAnalitica: enc, Limite: q.limite, Grupos: grupos}, err
+}
+
+func (s *analiticaService) autoridades(q consultaValidada, enc *EncabezadoAnalitica) (CargaAutoridades, error) {
+	cargas, err := s.repo.CargaAutoridades(q.rango)
+	return CargaAutoridades{EncabezadoAnalitica: enc, Autoridades: cargas}, err
+}
+
+func (s *analiticaService) dudas(q consultaValidada, enc *EncabezadoAnalitica) (TiemposDudas, error) {
+	resumen, periodos, err := s.repo.TiemposRespuestaDudas(q.rango, q.agrupar)
+	if err != nil {
+		return TiemposDudas{}, err
+	}
+	tiempos := TiemposDudas{EncabezadoAnalitica: enc, Agrupacion: q.agrupar, Resumen: *resumen, Periodos: []repositories.TiemposRespuesta{}}
+	porPeriodo := map[string]repositories.TiemposRespuesta{}
+	for _, t := range periodos {
+		porPeriodo[t.Periodo] = t
+	}
+	for _, periodo := range periodosAnalitica(q) {
+		t, ok := porPeriodo[periodo]
+		if !ok {
+			t = repositories.TiemposRespuesta{Periodo: periodo}
+		}
+		tiempos.Periodos = append(tiempos.Periodos, t)
+	}
+	return tiempos, nil
+}
+
+func (s *analiticaService) comunicados(q consultaValidada, enc *EncabezadoAnalitica) (AlcanceComunicados, error) {
+	canales, err := s.repo.AlcanceComunicados(q.rango)
+	return AlcanceComunicados{EncabezadoAnalitica: enc, Canales: canales}, err
+}
+
+// periodosAnalitica son las etiquetas de todos los períodos del rango, para completar las series
+// con los períodos sin registros
+func periodosAnalitica(q consultaValidada) []string {
+	var periodos []string
+	for inicio := repositories.InicioPeriodo(q.rango.Desde, q.agrupar); inicio.Before(q.rango.Hasta); inicio = repositories.SiguientePeriodo(inicio, q.agrupar) {
+		periodos = append(periodos, repositories.EtiquetaPeriodo(inicio, q.agrupar))
+	}
+	return periodos
+}
+
+// seccion valida la consulta y devuelve la sección desde la caché o calculada con calcular
+func seccion[T any](s *analiticaService, consulta ConsultaAnalitica, nombre string, calcular func(consultaValidada, *EncabezadoAnalitica) (T, error)) (*T, error) {
+	q, err := s.validar(consulta)
+	if err != nil {
+		return nil, err
+	}
+	return cacheado(s, q.clave(nombre), false, func(generado time.Time) (*T, error) {
+		valor, err := calcular(q, q.encabezado(generado))
+		if err != nil {
+			return nil, err
+		}
+		return &valor, nil
+	})
+}
+
+// cacheado devuelve el valor vigente de la clave o lo calcula y lo guarda; con forzar lo calcula
+// aunque esté vigente. Los valores guardados no se modifican después.
+func cacheado[T any](s *analiticaService, clave string, forzar bool, calcular func(generado time.Time) (*T, error)) (*T, error) {
+	ahora := s.now()
+	if !forzar {
+		s.mu.Lock()
+		e, ok := s.entradas[clave]
+		s.mu.Unlock()
+		if ok && ahora.Before(e.expira) {
+			return e.valor.(*T), nil
+		}
+	}
+	valor, err := calcular(ahora)
+	if err != nil || s.ttl <= 0 {
+		return valor, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Las consultas con otros parámetros dejan entradas que nadie vuelve a pedir
+	for k, e := range s.entradas {
+		if !ahora.Before(e.expira) {
+			delete(s.entradas, k)
+		}
+	}
+	s.entradas[clave] = entradaAnalitica{valor: valor, expira: ahora.Add(s.ttl)}
+	return valor, nil
+}
+
+// validar comprueba los parámetros y aplica los valores por defecto
+func (s *analiticaService) validar(consulta ConsultaAnalitica) (consultaValidada, error) {
+	var campos []errores.Campo
+	fecha := func(campo, valor string) time.Time {
+		t, err := time.ParseInLocation("2006-01-02", valor, time.Local)
+		if err != nil {
+			campos = append(campos, errores.Campo{Campo: campo, Mensaje: "Se requiere una fecha en formato YYYY-MM-DD", Valor: valor})
+		}
+		return t
+	}
+
+	hoy := repositories.InicioPeriodo(s.now(), repositories.AgruparDia)
+	hasta := hoy
+	if consulta.Hasta != "" {
+		hasta = fecha("hasta", consulta.Hasta)
+	}
+	desde := repositories.InicioPeriodo(hasta, repositories.AgruparMes).AddDate(0, -mesesAnalitica, 0)
+	if consulta.Desde != "" {
+		desde = fecha("desde", consulta.Desde)
+	}
+	if len(campos) == 0 && hasta.Before(desde) {
+		campos = append(campos, errores.Campo{Campo: "hasta", Mensaje: "Debe ser igual o posterior a desde", Valor: consulta.Hasta})
+	}
+
+	q := consultaValidada{
+		rango:   repositories.RangoAnalitica{Desde: desde, Hasta: hasta.AddDate(0, 0, 1)},
+		desde:   desde.Format("2006-01-02"),
+		hasta:   hasta.Format("2006-01-02"),
+		agrupar: consulta.Agrupar,
+		por:     consulta.Por,
+		limite:  consulta.Limite,
+	}
+	if q.agrupar == "" {
+		q.agrupar = repositories.AgruparMes
+	}
+	if !repositories.EsAgrupacion(q.agrupar) {
+		campos = append(campos, errores.Campo{Campo: "agrupar", Mensaje: "Debe ser " + strings.Join(AgrupacionesAnalitica, ", "), Valor: q.agrupar})
+	} else if q.agrupar == repositories.AgruparDia && q.rango.Hasta.Sub(q.rango.Desde) > diasAgrupacionDiaria*24*time.Hour {
+		campos = append(campos, errores.Campo{
+			Campo:   "agrupar",
+			Mensaje: fmt.Sprintf("Para agrupar por día el rango no puede superar los %d días", diasAgrupacionDiaria),
+			Valor:   q.agrupar,
+		})
+	}
+	if q.por == "" {
+		q.por = repositories.AnaliticaPorInstitucion
+	}
+	if !esDimensionAnalitica(q.por) {
+		campos = append(campos, errores.Campo{Campo: "por", Mensaje: "Debe ser " + strings.Join(DimensionesAnalitica, ", "), Valor: q.por})
+	}
+	switch {
+	case q.limite == 0:
+		q.limite = limiteAnalitica
+	case q.limite < 0 || q.limite > LimiteAnaliticaMaximo:
+		campos = append(campos, errores.Campo{
+			Campo:   "limite",
+			Mensaje: "Debe ser un número entre 1 y " + strconv.Itoa(LimiteAnaliticaMaximo),
+			Valor:   strconv.Itoa(q.limite),
+		})
+	}
+	if len(campos) > 0 {
+		return consultaValidada{}, errores.Validacion("analitica_invalida", "Los parámetros de la analítica no son válidos", campos...)
+	}
+	return q, nil
+}
+
+func esDimensionAnalitica(por string) bool {
+	for _, d := range DimensionesAnalitica {
+		if d == por {
+			return true
+		}
+	}
+	return false
+}
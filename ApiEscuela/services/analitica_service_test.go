@@ -0,0 +1,63 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"ApiEscuela/models"
+	"ApiEscuela/services"
+	"ApiEscuela/testutil"
+)
+
+func TestAnaliticaCache(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		analitica := services.NewAnaliticaService(repos.Analitica, time.Hour)
+
+		antes, err := analitica.Visitas(services.ConsultaAnalitica{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		panel, err := analitica.Panel(services.ConsultaAnalitica{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if antes.Total != 0 || panel.Visitas.Total != 0 {
+			t.Fatalf("visitas sin programas = %d, %d", antes.Total, panel.Visitas.Total)
+		}
+
+		if err := repos.ProgramaVisita.CreateProgramaVisita(&models.ProgramaVisita{Fecha: time.Now(), InstitucionID: e.Institucion.ID}); err != nil {
+			t.Fatal(err)
+		}
+
+		// Mientras la entrada esté vigente se devuelve el agregado guardado
+		despues, err := analitica.Visitas(services.ConsultaAnalitica{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if despues.Total != 0 || !despues.GeneradoEn.Equal(antes.GeneradoEn) {
+			t.Errorf("se esperaba la serie en caché: %d visitas generadas en %v", despues.Total, despues.GeneradoEn)
+		}
+		// Otros parámetros son otra entrada
+		if semanal, err := analitica.Visitas(services.ConsultaAnalitica{Agrupar: "semana"}); err != nil || semanal.Total != 1 {
+			t.Errorf("serie semanal = %+v, %v", semanal, err)
+		}
+
+		// La tarea programada recalcula el panel por defecto
+		ejecutar := services.TareasProgramadas(services.ConfigTareas{RefrescarAnalitica: "*/10 * * * *"}, services.DependenciasTareas{Analitica: analitica})
+		if len(ejecutar) != 1 || ejecutar[0].Nombre != services.TareaRefrescarAnalitica {
+			t.Fatalf("tareas = %+v", ejecutar)
+		}
+		if _, err := ejecutar[0].Ejecutar(t.Context()); err != nil {
+			t.Fatal(err)
+		}
+		panel, err = analitica.Panel(services.ConsultaAnalitica{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if panel.Visitas.Total != 1 {
+			t.Errorf("panel refrescado = %d visitas", panel.Visitas.Total)
+		}
+	})
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"ApiEscuela/repositories"
+	"ApiEscuela/storage"
+	"context"
+	"fmt"
+	"time"
+)
+
+// carpetasSubidas son las carpetas del almacenamiento donde el API guarda archivos subidos por los usuarios
+var carpetasSubidas = []string{"images", "videos", "documents", "comunicados_files"}
+
+// archivoService localiza archivos subidos que ya no están referenciados
+type archivoService struct {
+	noticiaRepo    repositories.NoticiaRepository
+	comunicadoRepo repositories.ComunicadoRepository
+	archivos       storage.Storage
+	media          MediaService
+}
+
+// NewArchivoService crea una nueva instancia del servicio
+func NewArchivoService(
+	noticiaRepo repositories.NoticiaRepository,
+	comunicadoRepo repositories.ComunicadoRepository,
+	archivos storage.Storage,
+	media MediaService,
+) ArchivoService {
+	return &archivoService{
+		noticiaRepo:    noticiaRepo,
+		comunicadoRepo: comunicadoRepo,
+		archivos:       archivos,
+		media:          media,
+	}
+}
+
+// OrphanFile es un archivo subido que ningún registro referencia
+type OrphanFile struct {
+	Clave      string
+	Tamano     int64
+	Modificado time.Time
+}
+
+// FindOrphanFiles lista los archivos subidos antes de la fecha indicada que no aparecen en noticias ni comunicados.
+// De paso actualiza las referencias de la biblioteca de archivos.
+func (s *archivoService) FindOrphanFiles(antes time.Time) ([]OrphanFile, error) {
+	referenciados, err := s.archivosReferenciados()
+	if err != nil {
+		return nil, err
+	}
+
+	var huerfanos []OrphanFile
+	for _, carpeta := range carpetasSubidas {
+		err := s.archivos.Listar(context.Background(), carpeta+"/", func(obj storage.Objeto) error {
+			// Los archivos recientes pueden pertenecer a un formulario que aún no se guardó; las
+			// variantes se eliminan junto con su imagen
+			if referenciados[obj.Clave] || !obj.Modificado.Before(antes) || EsVariante(obj.Clave) {
+				return nil
+			}
+			// Un archivo reutilizado hace poco por una subida idéntica tampoco se elimina
+			if media, err := s.media.BuscarPorClave(obj.Clave); err == nil && media != nil && media.ReutilizadoEn != nil && !media.ReutilizadoEn.Before(antes) {
+				return nil
+			}
+			huerfanos = append(huerfanos, OrphanFile{Clave: obj.Clave, Tamano: obj.Tamano, Modificado: obj.Modificado})
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error al recorrer %s: %v", carpeta, err)
+		}
+	}
+	return huerfanos, nil
+}
+
+// RemoveOrphanFiles elimina los archivos indicados junto con sus variantes y metadatos
+func (s *archivoService) RemoveOrphanFiles(archivos []OrphanFile) (int, error) {
+	eliminados := 0
+	for _, archivo := range archivos {
+		if err := s.media.EliminarMedia(context.Background(), archivo.Clave); err != nil {
+			return eliminados, fmt.Errorf("error al eliminar las variantes de %s: %v", archivo.Clave, err)
+		}
+		if err := s.archivos.Eliminar(context.Background(), archivo.Clave); err != nil {
+			return eliminados, fmt.Errorf("error al eliminar %s: %v", archivo.Clave, err)
+		}
+		eliminados++
+	}
+	return eliminados, nil
+}
+
+// archivosReferenciados devuelve las claves de todos los archivos usados por noticias y
+// comunicados y actualiza con ellas las referencias de la biblioteca de archivos
+func (s *archivoService) archivosReferenciados() (map[string]bool, error) {
+	var referencias []ReferenciaArchivo
+	agregar := func(entidad string, id uint, claves []string) {
+		for _, clave := range claves {
+			referencias = append(referencias, ReferenciaArchivo{Entidad: entidad, EntidadID: id, Clave: clave})
+		}
+	}
+
+	noticias, err := s.noticiaRepo.GetAllNoticias()
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener las noticias: %v", err)
+	}
+	for i := range noticias {
+		agregar(EntidadNoticia, noticias[i].ID, ClavesNoticia(&noticias[i]))
+	}
+
+	comunicados, err := s.comunicadoRepo.GetAllComunicados()
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener los comunicados: %v", err)
+	}
+	for i := range comunicados {
+		agregar(EntidadComunicado, comunicados[i].ID, ClavesComunicado(&comunicados[i]))
+	}
+
+	if err := s.media.ReconstruirReferencias(referencias); err != nil {
+		return nil, fmt.Errorf("error al actualizar las referencias de los archivos: %v", err)
+	}
+	referenciados := make(map[string]bool, len(referencias))
+	for _, ref := range referencias {
+		referenciados[ref.Clave] = true
+	}
+	return referenciados, nil
+}
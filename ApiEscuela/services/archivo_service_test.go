@@ -0,0 +1,85 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"ApiEscuela/models"
+	"ApiEscuela/services"
+	"ApiEscuela/storage"
+	"ApiEscuela/testutil"
+)
+
+func TestArchivosHuerfanos(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		ctx := context.Background()
+
+		viejo := time.Now().Add(-48 * time.Hour)
+		escribir := func(clave string, modificado time.Time) {
+			t.Helper()
+			if err := repos.Archivos.Guardar(ctx, clave, strings.NewReader("x"), 1, ""); err != nil {
+				t.Fatal(err)
+			}
+			repos.Archivos.SetModificado(clave, modificado)
+		}
+		escribir("images/portada.jpg", viejo)
+		escribir("images/embebida.png", viejo)
+		escribir("images/huerfana.png", viejo)
+		escribir("images/reciente.png", time.Now())
+		// Las variantes no son huérfanas por sí mismas: se eliminan con su imagen
+		escribir("images/variantes/huerfana-thumb.jpg", viejo)
+		if err := repos.Media.SaveMedia(&models.Media{
+			Clave:     "images/huerfana.png",
+			Variantes: models.VariantesMedia{{Nombre: "thumb", Formato: "jpeg", Clave: "images/variantes/huerfana-thumb.jpg"}},
+		}); err != nil {
+			t.Fatal(err)
+		}
+		escribir("comunicados_files/1700000000/acta final.pdf", viejo)
+		escribir("comunicados_files/1700000001/borrador.pdf", viejo)
+
+		if err := repos.Noticia.CreateNoticia(&models.Noticia{
+			URLNoticia:  "http://localhost:3000/api/files/images/portada.jpg",
+			Descripcion: `<p><img src="/api/files/images/embebida.png"></p>`,
+		}); err != nil {
+			t.Fatal(err)
+		}
+		f.Comunicado(e.Admin, func(c *models.Comunicado) {
+			c.Adjuntos = `["/api/files/comunicados_files/1700000000/acta final.pdf"]`
+		})
+
+		archivos := services.NewArchivoService(repos.Noticia, repos.Comunicado, repos.Archivos, services.NewMediaService(repos.Media, repos.Archivos))
+		huerfanos, err := archivos.FindOrphanFiles(time.Now().Add(-24 * time.Hour))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var claves []string
+		for _, h := range huerfanos {
+			claves = append(claves, h.Clave)
+		}
+		want := []string{"images/huerfana.png", "comunicados_files/1700000001/borrador.pdf"}
+		if len(claves) != len(want) || claves[0] != want[0] || claves[1] != want[1] {
+			t.Fatalf("huérfanos = %v, se esperaba %v", claves, want)
+		}
+
+		if n, err := archivos.RemoveOrphanFiles(huerfanos); err != nil || n != 2 {
+			t.Fatalf("eliminados = %d, %v", n, err)
+		}
+		if _, err := repos.Archivos.Info(ctx, "images/huerfana.png"); !errors.Is(err, storage.ErrNoEncontrado) {
+			t.Error("los huérfanos deberían eliminarse")
+		}
+		if _, err := repos.Archivos.Info(ctx, "images/variantes/huerfana-thumb.jpg"); !errors.Is(err, storage.ErrNoEncontrado) {
+			t.Error("las variantes de los huérfanos deberían eliminarse")
+		}
+		if _, err := repos.Media.GetMediaByClave("images/huerfana.png"); err == nil {
+			t.Error("los metadatos de los huérfanos deberían eliminarse")
+		}
+		if _, err := repos.Archivos.Info(ctx, "images/portada.jpg"); err != nil {
+			t.Error("los archivos referenciados no deben eliminarse")
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"ApiEscuela/errores"
 	"ApiEscuela/middleware"
 	"ApiEscuela/models"
 	"ApiEscuela/repositories"
@@ -18,16 +19,27 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-type AuthService struct {
-	usuarioRepo       *repositories.UsuarioRepository
-	personaRepo       *repositories.PersonaRepository
-	codigoUsuarioRepo *repositories.CodigoUsuarioRepository
+type authService struct {
+	usuarioRepo       repositories.UsuarioRepository
+	personaRepo       repositories.PersonaRepository
+	codigoUsuarioRepo repositories.CodigoUsuarioRepository
 }
 
-var ErrPersonaNoEncontrada = errors.New("persona no encontrada")
+// Errores de autenticación y recuperación de contraseña
+var (
+	ErrPersonaNoEncontrada  = errores.NoEncontrado("persona_no_encontrada", "persona no encontrada")
+	ErrUsuarioNoEncontrado  = errores.NoEncontrado("usuario_no_encontrado", "usuario no encontrado")
+	ErrUsuarioEliminado     = errores.Prohibido("usuario_eliminado", "usuario eliminado - contacte al administrador")
+	ErrContraseñaTextoPlano = errores.Validacion("contraseña_incorrecta", "contraseña incorrecta (texto plano)")
+	ErrContraseñaHash       = errores.Validacion("contraseña_incorrecta_hash", "contraseña incorrecta (hash bcrypt)")
+	ErrCodigoNoEncontrado   = errores.NoEncontrado("codigo_not_found", "código no encontrado")
+	ErrCodigoDeOtroUsuario  = errores.Validacion("codigo_user_mismatch", "el código no pertenece al usuario especificado")
+	ErrCodigoEstadoInvalido = errores.Validacion("codigo_invalid_state", "el código debe estar en estado válido para cambiar la contraseña")
+	ErrCodigoExpirado       = errores.Validacion("codigo_expired", "el código ha expirado")
+)
 
-func NewAuthService(usuarioRepo *repositories.UsuarioRepository, personaRepo *repositories.PersonaRepository, codigoUsuarioRepo *repositories.CodigoUsuarioRepository) *AuthService {
-	return &AuthService{
+func NewAuthService(usuarioRepo repositories.UsuarioRepository, personaRepo repositories.PersonaRepository, codigoUsuarioRepo repositories.CodigoUsuarioRepository) AuthService {
+	return &authService{
 		usuarioRepo:       usuarioRepo,
 		personaRepo:       personaRepo,
 		codigoUsuarioRepo: codigoUsuarioRepo,
@@ -37,7 +49,7 @@ func NewAuthService(usuarioRepo *repositories.UsuarioRepository, personaRepo *re
 // LoginRequest representa la estructura de datos para el login
 type LoginRequest struct {
 	Usuario    string `json:"usuario" validate:"required"`
-	Contraseña string `json:"contraseña" validate:"required"`
+	Contraseña string `json:"contraseña" validate:"required,secreto"`
 }
 
 // LoginResponse representa la respuesta del login
@@ -51,25 +63,25 @@ type LoginResponse struct {
 // RegisterRequest representa la estructura de datos para el registro
 type RegisterRequest struct {
 	Usuario       string `json:"usuario" validate:"required"`
-	Contraseña    string `json:"contraseña" validate:"required,min=6"`
+	Contraseña    string `json:"contraseña" validate:"required,min=6,secreto"`
 	PersonaID     uint   `json:"persona_id" validate:"required"`
 	TipoUsuarioID uint   `json:"tipo_usuario_id" validate:"required"`
 }
 
 // HashPassword encripta una contraseña
-func (s *AuthService) HashPassword(password string) (string, error) {
+func (s *authService) HashPassword(password string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	return string(bytes), err
 }
 
 // CheckPassword verifica si una contraseña coincide con su hash
-func (s *AuthService) CheckPassword(password, hash string) bool {
+func (s *authService) CheckPassword(password, hash string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 	return err == nil
 }
 
 // Login autentica un usuario y devuelve un token JWT
-func (s *AuthService) Login(loginReq LoginRequest) (*LoginResponse, error) {
+func (s *authService) Login(loginReq LoginRequest) (*LoginResponse, error) {
 	// Buscar usuario por nombre de usuario
 	usuario, err := s.usuarioRepo.GetUsuarioByUsername(loginReq.Usuario)
 	if err != nil {
@@ -77,22 +89,22 @@ func (s *AuthService) Login(loginReq LoginRequest) (*LoginResponse, error) {
 		usuarioDeleted, errDeleted := s.usuarioRepo.GetUsuarioByUsernameIncludingDeleted(loginReq.Usuario)
 		if errDeleted == nil && usuarioDeleted != nil {
 			// El usuario existe pero está eliminado
-			return nil, errors.New("usuario eliminado - contacte al administrador")
+			return nil, ErrUsuarioEliminado
 		}
 		// Usuario no existe
-		return nil, errors.New("usuario no encontrado")
+		return nil, ErrUsuarioNoEncontrado
 	}
 
 	// Verificar si la contraseña está encriptada (hash bcrypt tiene al menos 60 caracteres)
 	if len(usuario.Contraseña) < 60 {
 		// Contraseña no está encriptada, comparar directamente
 		if loginReq.Contraseña != usuario.Contraseña {
-			return nil, errors.New("contraseña incorrecta (texto plano)")
+			return nil, ErrContraseñaTextoPlano
 		}
 	} else {
 		// Verificar contraseña encriptada
 		if !s.CheckPassword(loginReq.Contraseña, usuario.Contraseña) {
-			return nil, errors.New("contraseña incorrecta (hash bcrypt)")
+			return nil, ErrContraseñaHash
 		}
 	}
 
@@ -121,7 +133,7 @@ func (s *AuthService) Login(loginReq LoginRequest) (*LoginResponse, error) {
 }
 
 // Register registra un nuevo usuario
-func (s *AuthService) Register(registerReq RegisterRequest) (*models.Usuario, error) {
+func (s *authService) Register(registerReq RegisterRequest) (*models.Usuario, error) {
 	// Verificar si el usuario ya existe
 	existingUser, _ := s.usuarioRepo.GetUsuarioByUsername(registerReq.Usuario)
 	if existingUser != nil {
@@ -154,11 +166,11 @@ func (s *AuthService) Register(registerReq RegisterRequest) (*models.Usuario, er
 }
 
 // ChangePassword cambia la contraseña de un usuario
-func (s *AuthService) ChangePassword(userID uint, oldPassword, newPassword string) error {
+func (s *authService) ChangePassword(userID uint, oldPassword, newPassword string) error {
 	// Obtener usuario
 	usuario, err := s.usuarioRepo.GetUsuarioByID(userID)
 	if err != nil {
-		return errors.New("usuario no encontrado")
+		return ErrUsuarioNoEncontrado
 	}
 
 	// Verificar contraseña actual (soporta tanto encriptada como texto plano)
@@ -198,13 +210,13 @@ func (s *AuthService) ChangePassword(userID uint, oldPassword, newPassword strin
 }
 
 // ResetPassword actualiza la contraseña de un usuario por ID (sin contraseña actual)
-func (s *AuthService) ResetPassword(userID uint, newPassword string) error {
+func (s *authService) ResetPassword(userID uint, newPassword string) error {
 	if strings.TrimSpace(newPassword) == "" {
 		return errors.New("clave requerida")
 	}
 	usuario, err := s.usuarioRepo.GetUsuarioByID(userID)
 	if err != nil {
-		return errors.New("usuario no encontrado")
+		return ErrUsuarioNoEncontrado
 	}
 	usuario.Contraseña = newPassword
 	usuario.Verificado = true
@@ -215,12 +227,12 @@ func (s *AuthService) ResetPassword(userID uint, newPassword string) error {
 }
 
 // GenerateNewToken genera un nuevo token JWT para un usuario
-func (s *AuthService) GenerateNewToken(userID uint, username string, tipoUsuarioID uint) (string, error) {
+func (s *authService) GenerateNewToken(userID uint, username string, tipoUsuarioID uint) (string, error) {
 	return middleware.GenerateJWT(userID, username, tipoUsuarioID)
 }
 
 // RecoverPassword genera una contraseña temporal y la envía por correo
-func (s *AuthService) RecoverPassword(cedula string) error {
+func (s *authService) RecoverPassword(cedula string) error {
 	if strings.TrimSpace(cedula) == "" {
 		return errors.New("la cédula es requerida")
 	}
@@ -295,7 +307,7 @@ func (s *AuthService) RecoverPassword(cedula string) error {
 }
 
 // sendEmail envía un correo usando SMTP con contenido HTML básico
-func (s *AuthService) sendEmail(to, subject, htmlBody string) error {
+func (s *authService) sendEmail(to, subject, htmlBody string) error {
 	// Obtener configuración SMTP desde variables de entorno
 	host := os.Getenv("SMTP_HOST")
 	port := os.Getenv("SMTP_PORT")
@@ -337,10 +349,10 @@ func (s *AuthService) sendEmail(to, subject, htmlBody string) error {
 	err := smtp.SendMail(addr, auth, from, []string{to}, []byte(msgBuilder.String()))
 	if err != nil {
 		fmt.Printf("DEBUG SMTP - Error al enviar correo: %v\n", err)
-	} else {
-		fmt.Printf("DEBUG SMTP - Correo enviado exitosamente\n")
+		return errores.Externo("smtp_error", "No se pudo enviar el correo", err)
 	}
-	return err
+	fmt.Printf("DEBUG SMTP - Correo enviado exitosamente\n")
+	return nil
 }
 
 // generateRandomPassword crea una contraseña aleatoria alfanumérica
@@ -379,7 +391,7 @@ func generateRandomPassword(length int) (string, error) {
 }
 
 // ValidateToken valida un token JWT y devuelve las claims
-func (s *AuthService) ValidateToken(tokenString string) (bool, *middleware.JWTClaims) {
+func (s *authService) ValidateToken(tokenString string) (bool, *middleware.JWTClaims) {
 	claims, err := middleware.ValidateJWT(tokenString)
 	if err != nil {
 		return false, nil
@@ -396,7 +408,7 @@ type VerifyCodeResult struct {
 }
 
 // VerifyCodigo verifica un código: no existe | caducado | verificado
-func (s *AuthService) VerifyCodigo(codigo string) (string, uint, error) {
+func (s *authService) VerifyCodigo(codigo string) (string, uint, error) {
 	codigo = strings.TrimSpace(codigo)
 	if codigo == "" {
 		return "", 0, errors.New("codigo requerido")
@@ -422,7 +434,7 @@ func (s *AuthService) VerifyCodigo(codigo string) (string, uint, error) {
 }
 
 // VerifyCodigoWithDetails verifica un código y retorna información completa
-func (s *AuthService) VerifyCodigoWithDetails(codigo string) (*VerifyCodeResult, error) {
+func (s *authService) VerifyCodigoWithDetails(codigo string) (*VerifyCodeResult, error) {
 	codigo = strings.TrimSpace(codigo)
 	if codigo == "" {
 		return nil, errors.New("codigo requerido")
@@ -465,28 +477,28 @@ func (s *AuthService) VerifyCodigoWithDetails(codigo string) (*VerifyCodeResult,
 }
 
 // ResetPasswordByCodigoID resetea la contraseña usando el ID del código y marca el código como usado
-func (s *AuthService) ResetPasswordByCodigoID(codigoID uint, usuarioID uint, nuevaClave string) error {
+func (s *authService) ResetPasswordByCodigoID(codigoID uint, usuarioID uint, nuevaClave string) error {
 	// Obtener el código por ID
 	rec, err := s.codigoUsuarioRepo.GetByID(codigoID)
 	if err != nil || rec == nil {
-		return errors.New("código no encontrado")
+		return ErrCodigoNoEncontrado
 	}
 
 	// Verificar que el código pertenece al usuario correcto
 	if rec.UsuarioID != usuarioID {
-		return errors.New("el código no pertenece al usuario especificado")
+		return ErrCodigoDeOtroUsuario
 	}
 
 	// Verificar estado del código
 	if rec.Estado != "valido" {
-		return errors.New("el código debe estar en estado válido para cambiar la contraseña")
+		return ErrCodigoEstadoInvalido
 	}
 
 	// Verificar que el código no esté expirado
 	if rec.ExpiraEn == nil || time.Now().After(*rec.ExpiraEn) {
 		// Marcar como expirado si ya pasó el tiempo
 		s.codigoUsuarioRepo.MarcarComoExpirado(codigoID)
-		return errors.New("el código ha expirado")
+		return ErrCodigoExpirado
 	}
 
 	// Cambiar la contraseña del usuario
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"ApiEscuela/backup"
+	"ApiEscuela/storage"
+	"errors"
+	"io"
+
+	"gorm.io/gorm"
+)
+
+// ErrBackupNoDisponible se devuelve cuando el servicio no tiene una base de datos (por ejemplo, en pruebas en memoria)
+var ErrBackupNoDisponible = errors.New("los respaldos requieren una conexión a la base de datos")
+
+// backupService genera y restaura respaldos lógicos de la base de datos
+type backupService struct {
+	db          *gorm.DB
+	archivos    storage.Storage
+	alRestaurar []func()
+}
+
+// NewBackupService crea una nueva instancia del servicio sobre el almacenamiento de archivos indicado.
+// Las funciones alRestaurar se ejecutan después de cada restauración aplicada (por ejemplo, para
+// vaciar cachés en memoria).
+func NewBackupService(db *gorm.DB, archivos storage.Storage, alRestaurar ...func()) BackupService {
+	return &backupService{db: db, archivos: archivos, alRestaurar: alRestaurar}
+}
+
+// Exportar escribe en w un respaldo de todas las tablas y de los assets
+func (s *backupService) Exportar(w io.Writer, opts backup.OpcionesExportacion) (*backup.Manifiesto, error) {
+	if s.db == nil {
+		return nil, ErrBackupNoDisponible
+	}
+	if opts.Archivos == nil {
+		opts.Archivos = s.archivos
+	}
+	return backup.Exportar(s.db, w, opts)
+}
+
+// Restaurar carga un respaldo generado por Exportar
+func (s *backupService) Restaurar(r io.Reader, opts backup.OpcionesRestauracion) (*backup.Resultado, error) {
+	if s.db == nil {
+		return nil, ErrBackupNoDisponible
+	}
+	if opts.Archivos == nil {
+		opts.Archivos = s.archivos
+	}
+	resultado, err := backup.Restaurar(s.db, r, opts)
+	if err == nil && !opts.Simular {
+		for _, f := range s.alRestaurar {
+			f()
+		}
+	}
+	return resultado, err
+}
@@ -0,0 +1,232 @@
+package services
+
+import (
+	"ApiEscuela/errores"
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Tipos de resultado de la búsqueda global
+const (
+	TipoBusquedaPersona        = "persona"
+	TipoBusquedaInstitucion    = "institucion"
+	TipoBusquedaProgramaVisita = "programa_visita"
+	TipoBusquedaNoticia        = "noticia"
+)
+
+// Modos de la búsqueda global: la búsqueda completa exige todas las palabras y mira más campos;
+// el autocompletado es tolerante a errores de escritura y mira los nombres (y la cédula de las personas)
+const (
+	ModoBusquedaCompleta = "completa"
+	ModoAutocompletar    = "autocompletar"
+)
+
+// Límites de la búsqueda global
+const (
+	LongitudMinimaBusqueda = 2
+	LongitudMaximaBusqueda = 100
+	LimiteBusquedaMaximo   = 50
+	limiteBusqueda         = 5
+	limiteAutocompletar    = 10
+)
+
+// TiposBusqueda son los tipos de resultado de la búsqueda global, en el orden de los grupos
+var TiposBusqueda = []string{TipoBusquedaPersona, TipoBusquedaInstitucion, TipoBusquedaProgramaVisita, TipoBusquedaNoticia}
+
+// ConsultaBusqueda es una búsqueda global. Sin Tipos se busca en todos; Limite es la cantidad de
+// resultados por tipo y 0 usa el valor por defecto del modo (5, o 10 al autocompletar).
+type ConsultaBusqueda struct {
+	Texto         string
+	Tipos         []string
+	Limite        int
+	Autocompletar bool
+}
+
+// CoincidenciaBusqueda es un resultado de la búsqueda global con lo necesario para listarlo; el
+// registro completo se obtiene con el endpoint del tipo y el ID
+type CoincidenciaBusqueda struct {
+	Tipo       string  `json:"tipo"`
+	ID         uint    `json:"id"`
+	Titulo     string  `json:"titulo"`
+	Detalle    string  `json:"detalle,omitempty"`
+	Fragmento  string  `json:"fragmento,omitempty"`
+	Relevancia float64 `json:"relevancia,omitempty"`
+}
+
+// GrupoBusqueda son los resultados de un tipo, los más parecidos primero; HayMas indica que hay
+// más resultados que el límite
+type GrupoBusqueda struct {
+	Tipo       string                 `json:"tipo"`
+	Resultados []CoincidenciaBusqueda `json:"resultados"`
+	HayMas     bool                   `json:"hay_mas"`
+}
+
+// ResultadoBusquedaGlobal son los resultados agrupados por tipo, un grupo por cada tipo pedido
+type ResultadoBusquedaGlobal struct {
+	Consulta string          `json:"consulta"`
+	Modo     string          `json:"modo"`
+	Grupos   []GrupoBusqueda `json:"grupos"`
+}
+
+// busquedaService busca a la vez en personas, instituciones, programas de visita y noticias
+type busquedaService struct {
+	busquedaRepo repositories.BusquedaRepository
+	noticiaRepo  repositories.NoticiaRepository
+}
+
+// NewBusquedaService crea una nueva instancia del servicio
+func NewBusquedaService(busquedaRepo repositories.BusquedaRepository, noticiaRepo repositories.NoticiaRepository) BusquedaService {
+	return &busquedaService{busquedaRepo: busquedaRepo, noticiaRepo: noticiaRepo}
+}
+
+// Buscar valida la consulta y devuelve los resultados de cada tipo pedido
+func (s *busquedaService) Buscar(consulta ConsultaBusqueda) (*ResultadoBusquedaGlobal, error) {
+	texto := strings.TrimSpace(consulta.Texto)
+	tipos, limite, err := validarConsultaBusqueda(texto, consulta)
+	if err != nil {
+		return nil, err
+	}
+
+	resultado := &ResultadoBusquedaGlobal{Consulta: texto, Modo: ModoBusquedaCompleta, Grupos: []GrupoBusqueda{}}
+	if consulta.Autocompletar {
+		resultado.Modo = ModoAutocompletar
+	}
+	for _, tipo := range tipos {
+		// Se pide uno más que el límite para saber si hay más
+		coincidencias, err := s.buscarTipo(tipo, texto, limite+1, consulta.Autocompletar)
+		if err != nil {
+			return nil, err
+		}
+		grupo := GrupoBusqueda{Tipo: tipo, Resultados: coincidencias}
+		if len(coincidencias) > limite {
+			grupo.Resultados, grupo.HayMas = coincidencias[:limite], true
+		}
+		resultado.Grupos = append(resultado.Grupos, grupo)
+	}
+	return resultado, nil
+}
+
+// validarConsultaBusqueda comprueba el texto, los tipos y el límite; devuelve los tipos sin
+// repetir en el orden de TiposBusqueda y el límite a usar
+func validarConsultaBusqueda(texto string, consulta ConsultaBusqueda) ([]string, int, error) {
+	var campos []errores.Campo
+	if n := utf8.RuneCountInString(texto); n < LongitudMinimaBusqueda || n > LongitudMaximaBusqueda {
+		campos = append(campos, errores.Campo{
+			Campo:   "q",
+			Mensaje: "Debe tener entre " + strconv.Itoa(LongitudMinimaBusqueda) + " y " + strconv.Itoa(LongitudMaximaBusqueda) + " caracteres",
+			Valor:   texto,
+		})
+	}
+	pedidos := map[string]bool{}
+	for _, tipo := range consulta.Tipos {
+		pedidos[tipo] = true
+	}
+	todos := len(pedidos) == 0
+	tipos := make([]string, 0, len(TiposBusqueda))
+	for _, tipo := range TiposBusqueda {
+		if todos || pedidos[tipo] {
+			tipos = append(tipos, tipo)
+			delete(pedidos, tipo)
+		}
+	}
+	for tipo := range pedidos {
+		campos = append(campos, errores.Campo{
+			Campo:   "tipos",
+			Mensaje: "Tipo desconocido; use " + strings.Join(TiposBusqueda, ", "),
+			Valor:   tipo,
+		})
+	}
+	limite := consulta.Limite
+	switch {
+	case limite == 0 && consulta.Autocompletar:
+		limite = limiteAutocompletar
+	case limite == 0:
+		limite = limiteBusqueda
+	case limite < 0 || limite > LimiteBusquedaMaximo:
+		campos = append(campos, errores.Campo{
+			Campo:   "limite",
+			Mensaje: "Debe ser un número entre 1 y " + strconv.Itoa(LimiteBusquedaMaximo),
+			Valor:   strconv.Itoa(limite),
+		})
+	}
+	if len(campos) > 0 {
+		return nil, 0, errores.Validacion("busqueda_invalida", "Los parámetros de búsqueda no son válidos", campos...)
+	}
+	return tipos, limite, nil
+}
+
+// buscarTipo obtiene como máximo limite coincidencias de un tipo
+func (s *busquedaService) buscarTipo(tipo, texto string, limite int, autocompletar bool) ([]CoincidenciaBusqueda, error) {
+	coincidencias := []CoincidenciaBusqueda{}
+	switch tipo {
+	case TipoBusquedaPersona:
+		buscar := s.busquedaRepo.BuscarPersonas
+		if autocompletar {
+			buscar = s.busquedaRepo.AutocompletarPersonas
+		}
+		personas, err := buscar(texto, limite)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range personas {
+			coincidencias = append(coincidencias, coincidenciaPersona(p.Registro, p.Relevancia))
+		}
+	case TipoBusquedaInstitucion:
+		buscar := s.busquedaRepo.BuscarInstituciones
+		if autocompletar {
+			buscar = s.busquedaRepo.AutocompletarInstituciones
+		}
+		instituciones, err := buscar(texto, limite)
+		if err != nil {
+			return nil, err
+		}
+		for _, i := range instituciones {
+			coincidencias = append(coincidencias, CoincidenciaBusqueda{
+				Tipo: tipo, ID: i.Registro.ID, Titulo: i.Registro.Nombre, Detalle: i.Registro.Direccion, Relevancia: i.Relevancia,
+			})
+		}
+	case TipoBusquedaProgramaVisita:
+		buscar := s.busquedaRepo.BuscarProgramasVisita
+		if autocompletar {
+			buscar = s.busquedaRepo.AutocompletarProgramasVisita
+		}
+		programas, err := buscar(texto, limite)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range programas {
+			coincidencias = append(coincidencias, CoincidenciaBusqueda{
+				Tipo: tipo, ID: p.ID, Titulo: p.Institucion.Nombre, Detalle: p.Fecha.Format("02/01/2006 15:04"),
+			})
+		}
+	case TipoBusquedaNoticia:
+		// La búsqueda de texto completo ya busca por prefijos: sirve también para autocompletar
+		noticias, err := s.noticiaRepo.SearchNoticias(texto)
+		if err != nil {
+			return nil, err
+		}
+		if len(noticias) > limite {
+			noticias = noticias[:limite]
+		}
+		for _, n := range noticias {
+			coincidencias = append(coincidencias, CoincidenciaBusqueda{
+				Tipo: tipo, ID: n.Registro.ID, Titulo: n.Registro.Titulo, Fragmento: n.Fragmento, Relevancia: n.Relevancia,
+			})
+		}
+	}
+	return coincidencias, nil
+}
+
+// coincidenciaPersona muestra la cédula y el correo de la persona como detalle
+func coincidenciaPersona(p models.Persona, relevancia float64) CoincidenciaBusqueda {
+	detalle := "Cédula " + p.Cedula
+	if p.Correo != nil && *p.Correo != "" {
+		detalle += " · " + *p.Correo
+	}
+	return CoincidenciaBusqueda{
+		Tipo: TipoBusquedaPersona, ID: p.ID, Titulo: p.Nombre, Detalle: detalle, Relevancia: relevancia,
+	}
+}
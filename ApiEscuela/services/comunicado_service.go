@@ -3,30 +3,42 @@ package services
 import (
 	"ApiEscuela/models"
 	"ApiEscuela/repositories"
+	"ApiEscuela/storage"
+	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"io"
+	"log"
 	"net/smtp"
 	"os"
+	"path"
 	"strings"
 )
 
-// ComunicadoService maneja la lógica de negocio para comunicados
-type ComunicadoService struct {
-	comunicadoRepo  *repositories.ComunicadoRepository
-	estudianteRepo  *repositories.EstudianteRepository
-	institucionRepo *repositories.InstitucionRepository
+// comunicadoService maneja la lógica de negocio para comunicados
+type comunicadoService struct {
+	comunicadoRepo  repositories.ComunicadoRepository
+	estudianteRepo  repositories.EstudianteRepository
+	institucionRepo repositories.InstitucionRepository
+	archivos        storage.Storage
+	media           MediaService
 }
 
 // NewComunicadoService crea una nueva instancia del servicio
 func NewComunicadoService(
-	comunicadoRepo *repositories.ComunicadoRepository,
-	estudianteRepo *repositories.EstudianteRepository,
-	institucionRepo *repositories.InstitucionRepository,
-) *ComunicadoService {
-	return &ComunicadoService{
+	comunicadoRepo repositories.ComunicadoRepository,
+	estudianteRepo repositories.EstudianteRepository,
+	institucionRepo repositories.InstitucionRepository,
+	archivos storage.Storage,
+	media MediaService,
+) ComunicadoService {
+	return &comunicadoService{
 		comunicadoRepo:  comunicadoRepo,
 		estudianteRepo:  estudianteRepo,
 		institucionRepo: institucionRepo,
+		archivos:        archivos,
+		media:           media,
 	}
 }
 
@@ -51,7 +63,7 @@ type EmailResult struct {
 }
 
 // GetCorreosDestinatarios obtiene la lista de correos según el tipo de destinatario
-func (s *ComunicadoService) GetCorreosDestinatarios(destinatario DestinatarioInfo) ([]string, error) {
+func (s *comunicadoService) GetCorreosDestinatarios(destinatario DestinatarioInfo) ([]string, error) {
 	var correosDestinatarios []string
 
 	switch destinatario.Tipo {
@@ -105,7 +117,7 @@ func (s *ComunicadoService) GetCorreosDestinatarios(destinatario DestinatarioInf
 }
 
 // SendEmailWithAttachments envía un correo con archivos adjuntos a múltiples destinatarios usando BCC
-func (s *ComunicadoService) SendEmailWithAttachments(recipients []string, subject, htmlBody string, attachments []Attachment) error {
+func (s *comunicadoService) SendEmailWithAttachments(recipients []string, subject, htmlBody string, attachments []Attachment) error {
 	host := os.Getenv("SMTP_HOST")
 	port := os.Getenv("SMTP_PORT")
 	user := os.Getenv("SMTP_USER")
@@ -182,7 +194,7 @@ func (s *ComunicadoService) SendEmailWithAttachments(recipients []string, subjec
 
 // SendBulkEmails envía UN SOLO correo a todos los destinatarios usando BCC
 // Esto es más eficiente que enviar correos individuales
-func (s *ComunicadoService) SendBulkEmails(correos []string, subject, htmlBody string, attachments []Attachment) EmailResult {
+func (s *comunicadoService) SendBulkEmails(correos []string, subject, htmlBody string, attachments []Attachment) EmailResult {
 	result := EmailResult{
 		Total:   len(correos),
 		Errores: []string{},
@@ -203,27 +215,103 @@ func (s *ComunicadoService) SendBulkEmails(correos []string, subject, htmlBody s
 	return result
 }
 
-// CreateComunicado crea un nuevo comunicado en la base de datos
-func (s *ComunicadoService) CreateComunicado(comunicado *models.Comunicado) error {
-	return s.comunicadoRepo.CreateComunicado(comunicado)
+// CreateComunicado crea un nuevo comunicado en la base de datos y registra los archivos que usa
+func (s *comunicadoService) CreateComunicado(comunicado *models.Comunicado) error {
+	if err := s.comunicadoRepo.CreateComunicado(comunicado); err != nil {
+		return err
+	}
+	// Si falla, la recolección de huérfanos reconstruye las referencias antes de eliminar
+	if err := s.media.ActualizarReferencias(EntidadComunicado, comunicado.ID, ClavesComunicado(comunicado)); err != nil {
+		log.Printf("Error al registrar los archivos del comunicado %d: %v", comunicado.ID, err)
+	}
+	return nil
 }
 
 // GetComunicadoByID obtiene un comunicado por ID
-func (s *ComunicadoService) GetComunicadoByID(id uint) (*models.Comunicado, error) {
+func (s *comunicadoService) GetComunicadoByID(id uint) (*models.Comunicado, error) {
 	return s.comunicadoRepo.GetComunicadoByID(id)
 }
 
 // GetAllComunicados obtiene todos los comunicados
-func (s *ComunicadoService) GetAllComunicados() ([]models.Comunicado, error) {
+func (s *comunicadoService) GetAllComunicados() ([]models.Comunicado, error) {
 	return s.comunicadoRepo.GetAllComunicados()
 }
 
-// DeleteComunicado elimina un comunicado
-func (s *ComunicadoService) DeleteComunicado(id uint) error {
-	return s.comunicadoRepo.DeleteComunicado(id)
+// DeleteComunicado elimina un comunicado; sus archivos quedan sin esa referencia
+func (s *comunicadoService) DeleteComunicado(id uint) error {
+	if err := s.comunicadoRepo.DeleteComunicado(id); err != nil {
+		return err
+	}
+	if err := s.media.ActualizarReferencias(EntidadComunicado, id, nil); err != nil {
+		log.Printf("Error al quitar las referencias del comunicado %d: %v", id, err)
+	}
+	return nil
 }
 
-// SearchComunicados busca comunicados por asunto
-func (s *ComunicadoService) SearchComunicados(termino string) ([]models.Comunicado, error) {
+// SearchComunicados busca comunicados por asunto o mensaje, de los más relevantes a los menos relevantes
+func (s *comunicadoService) SearchComunicados(termino string) ([]repositories.ResultadoBusqueda[models.Comunicado], error) {
 	return s.comunicadoRepo.SearchComunicados(termino)
 }
+
+// ResendComunicado vuelve a enviar por correo un comunicado guardado, con sus adjuntos,
+// a los destinatarios que resulten hoy de su selección original
+func (s *comunicadoService) ResendComunicado(id uint) (EmailResult, error) {
+	comunicado, err := s.comunicadoRepo.GetComunicadoByID(id)
+	if err != nil {
+		return EmailResult{}, fmt.Errorf("comunicado no encontrado: %v", err)
+	}
+	if comunicado.Canal != "" && comunicado.Canal != "correo" {
+		return EmailResult{}, fmt.Errorf("el comunicado se envió por %s; solo se pueden reenviar comunicados por correo", comunicado.Canal)
+	}
+
+	var destinatario DestinatarioInfo
+	if err := json.Unmarshal([]byte(comunicado.Destinatarios), &destinatario); err != nil {
+		return EmailResult{}, fmt.Errorf("destinatarios inválidos: %v", err)
+	}
+	correos, err := s.GetCorreosDestinatarios(destinatario)
+	if err != nil {
+		return EmailResult{}, err
+	}
+	if len(correos) == 0 {
+		return EmailResult{}, fmt.Errorf("no se encontraron destinatarios con correo electrónico")
+	}
+
+	var adjuntos []string
+	if comunicado.Adjuntos != "" {
+		if err := json.Unmarshal([]byte(comunicado.Adjuntos), &adjuntos); err != nil {
+			return EmailResult{}, fmt.Errorf("adjuntos inválidos: %v", err)
+		}
+	}
+	var attachments []Attachment
+	for _, url := range adjuntos {
+		clave, ok := storage.ClaveDesdeURL(url)
+		if !ok {
+			return EmailResult{}, fmt.Errorf("ruta de adjunto no válida: %s", url)
+		}
+		data, mimeType, err := s.leerAdjunto(clave)
+		if err != nil {
+			return EmailResult{}, fmt.Errorf("no se pudo leer el adjunto %s: %v", url, err)
+		}
+		attachments = append(attachments, Attachment{Name: path.Base(clave), Data: data, MimeType: mimeType})
+	}
+
+	result := s.SendBulkEmails(correos, comunicado.Asunto, comunicado.Mensaje, attachments)
+	if result.Enviados > 0 {
+		comunicado.EnviadoA = result.Enviados
+		if err := s.comunicadoRepo.UpdateComunicado(comunicado); err != nil {
+			return result, fmt.Errorf("correo enviado pero no se pudo actualizar el comunicado: %v", err)
+		}
+	}
+	return result, nil
+}
+
+// leerAdjunto lee un archivo del almacenamiento para adjuntarlo a un correo
+func (s *comunicadoService) leerAdjunto(clave string) ([]byte, string, error) {
+	r, obj, err := s.archivos.Abrir(context.Background(), clave)
+	if err != nil {
+		return nil, "", err
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	return data, obj.ContentType, err
+}
@@ -0,0 +1,341 @@
+package services
+
+import (
+	"ApiEscuela/errores"
+	"ApiEscuela/exportacion"
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+	"html"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Listas exportables
+const (
+	ListaEstudiantes     = "estudiantes"
+	ListaProgramasVisita = "programas-visita"
+	ListaParticipantes   = "participantes"
+	ListaDudas           = "dudas"
+	ListaComunicados     = "comunicados"
+)
+
+// Formatos de las fechas exportadas
+const (
+	formatoFechaHoraExportacion = "02/01/2006 15:04"
+	formatoFechaExportacion     = "02/01/2006"
+)
+
+// ListaExportable describe una lista exportable y sus columnas, en el orden por defecto
+type ListaExportable struct {
+	Nombre   string                `json:"nombre"`
+	Titulo   string                `json:"titulo"`
+	Columnas []exportacion.Columna `json:"columnas"`
+}
+
+// Exportacion es una lista lista para escribirse con las columnas elegidas
+type Exportacion struct {
+	Lista    string
+	Titulo   string
+	Columnas []exportacion.Columna
+	recorrer func(fila func([]string) error) error
+}
+
+// Escribir escribe la lista completa en el formato indicado, lote por lote
+func (e *Exportacion) Escribir(w io.Writer, formato string) error {
+	escritor, err := exportacion.NuevoEscritor(formato, w, e.Titulo, e.Columnas)
+	if err != nil {
+		return err
+	}
+	if err := e.recorrer(escritor.Fila); err != nil {
+		escritor.Descartar()
+		return err
+	}
+	return escritor.Cerrar()
+}
+
+// exportacionService arma las exportaciones de las listas a partir de ExportacionRepository
+type exportacionService struct {
+	repo   repositories.ExportacionRepository
+	listas map[string]definicionExportacion
+}
+
+// NewExportacionService crea una nueva instancia del servicio
+func NewExportacionService(repo repositories.ExportacionRepository) ExportacionService {
+	return &exportacionService{repo: repo, listas: listasExportables()}
+}
+
+// Listas devuelve las listas exportables en orden alfabético de nombre
+func (s *exportacionService) Listas() []ListaExportable {
+	nombres := []string{ListaComunicados, ListaDudas, ListaEstudiantes, ListaParticipantes, ListaProgramasVisita}
+	listas := make([]ListaExportable, 0, len(nombres))
+	for _, nombre := range nombres {
+		d := s.listas[nombre]
+		listas = append(listas, ListaExportable{Nombre: nombre, Titulo: d.titulo(), Columnas: d.columnas()})
+	}
+	return listas
+}
+
+// Preparar valida las columnas pedidas por clave (todas si no se pide ninguna) y devuelve la
+// exportación de la lista con el filtro
+func (s *exportacionService) Preparar(lista string, filtro repositories.FiltroExportacion, claves []string) (*Exportacion, error) {
+	d, ok := s.listas[lista]
+	if !ok {
+		return nil, errores.NoEncontrado("lista_no_encontrada", "La lista "+lista+" no se puede exportar")
+	}
+	todas := d.columnas()
+	indices := make([]int, 0, len(todas))
+	if len(claves) == 0 {
+		for i := range todas {
+			indices = append(indices, i)
+		}
+	}
+	var campos []errores.Campo
+	elegidas := map[int]bool{}
+	for _, clave := range claves {
+		i := indiceColumna(todas, clave)
+		if i < 0 {
+			campos = append(campos, errores.Campo{
+				Campo:   "columnas",
+				Mensaje: "Columna desconocida; use " + clavesColumnas(todas),
+				Valor:   clave,
+			})
+		} else if !elegidas[i] {
+			elegidas[i] = true
+			indices = append(indices, i)
+		}
+	}
+	if len(campos) > 0 {
+		return nil, errores.Validacion("columnas_invalidas", "Las columnas pedidas no son válidas", campos...)
+	}
+
+	columnas := make([]exportacion.Columna, len(indices))
+	for i, indice := range indices {
+		columnas[i] = todas[indice]
+	}
+	return &Exportacion{
+		Lista:    lista,
+		Titulo:   d.titulo(),
+		Columnas: columnas,
+		recorrer: d.recorrer(s.repo, filtro, indices),
+	}, nil
+}
+
+func indiceColumna(columnas []exportacion.Columna, clave string) int {
+	for i, c := range columnas {
+		if c.Clave == clave {
+			return i
+		}
+	}
+	return -1
+}
+
+func clavesColumnas(columnas []exportacion.Columna) string {
+	claves := make([]string, len(columnas))
+	for i, c := range columnas {
+		claves[i] = c.Clave
+	}
+	return strings.Join(claves, ", ")
+}
+
+// definicionExportacion es una lista exportable sin su tipo de registro
+type definicionExportacion interface {
+	titulo() string
+	columnas() []exportacion.Columna
+	// recorrer devuelve la función que escribe las filas con las columnas de los índices
+	recorrer(repo repositories.ExportacionRepository, filtro repositories.FiltroExportacion, indices []int) func(fila func([]string) error) error
+}
+
+// columnaExportable es una columna y cómo obtener su valor de un registro
+type columnaExportable[T any] struct {
+	exportacion.Columna
+	valor func(*T) string
+}
+
+func columna[T any](clave, titulo string, ancho float64, valor func(*T) string) columnaExportable[T] {
+	return columnaExportable[T]{Columna: exportacion.Columna{Clave: clave, Titulo: titulo, Ancho: ancho}, valor: valor}
+}
+
+// listaExportable define una lista de registros de tipo T
+type listaExportable[T any] struct {
+	nombre string
+	cols   []columnaExportable[T]
+	lotes  func(repo repositories.ExportacionRepository, filtro repositories.FiltroExportacion, fn func([]T) error) error
+}
+
+func (l listaExportable[T]) titulo() string {
+	return l.nombre
+}
+
+func (l listaExportable[T]) columnas() []exportacion.Columna {
+	columnas := make([]exportacion.Columna, len(l.cols))
+	for i, c := range l.cols {
+		columnas[i] = c.Columna
+	}
+	return columnas
+}
+
+func (l listaExportable[T]) recorrer(repo repositories.ExportacionRepository, filtro repositories.FiltroExportacion, indices []int) func(fila func([]string) error) error {
+	return func(fila func([]string) error) error {
+		valores := make([]string, len(indices))
+		return l.lotes(repo, filtro, func(registros []T) error {
+			for i := range registros {
+				for j, indice := range indices {
+					valores[j] = l.cols[indice].valor(&registros[i])
+				}
+				if err := fila(valores); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	}
+}
+
+// listasExportables define las columnas de cada lista con encabezados en español
+func listasExportables() map[string]definicionExportacion {
+	return map[string]definicionExportacion{
+		ListaEstudiantes: listaExportable[models.Estudiante]{
+			nombre: "Estudiantes",
+			cols: []columnaExportable[models.Estudiante]{
+				columna("id", "ID", 0.4, func(e *models.Estudiante) string { return idTexto(e.ID) }),
+				columna("nombre", "Nombre", 1.6, func(e *models.Estudiante) string { return e.Persona.Nombre }),
+				columna("cedula", "Cédula", 0.8, func(e *models.Estudiante) string { return e.Persona.Cedula }),
+				columna("correo", "Correo", 1.4, func(e *models.Estudiante) string { return textoOpcional(e.Persona.Correo) }),
+				columna("telefono", "Teléfono", 0.8, func(e *models.Estudiante) string { return textoOpcional(e.Persona.Telefono) }),
+				columna("institucion", "Institución", 1.6, func(e *models.Estudiante) string { return e.Institucion.Nombre }),
+				columna("ciudad", "Ciudad", 0.8, func(e *models.Estudiante) string { return e.Ciudad.Ciudad }),
+				columna("provincia", "Provincia", 0.8, func(e *models.Estudiante) string { return e.Ciudad.Provincia.Provincia }),
+				columna("especialidad", "Especialidad", 1, func(e *models.Estudiante) string { return e.Especialidad }),
+				columna("registrado", "Fecha de registro", 0.9, func(e *models.Estudiante) string { return fechaTexto(e.CreatedAt, formatoFechaExportacion) }),
+			},
+			lotes: func(repo repositories.ExportacionRepository, filtro repositories.FiltroExportacion, fn func([]models.Estudiante) error) error {
+				return repo.RecorrerEstudiantes(filtro, fn)
+			},
+		},
+		ListaProgramasVisita: listaExportable[models.ProgramaVisita]{
+			nombre: "Programas de visita",
+			cols: []columnaExportable[models.ProgramaVisita]{
+				columna("id", "ID", 0.4, func(p *models.ProgramaVisita) string { return idTexto(p.ID) }),
+				columna("fecha", "Fecha", 0.9, func(p *models.ProgramaVisita) string { return fechaTexto(p.Fecha, formatoFechaHoraExportacion) }),
+				columna("fin", "Fin", 0.9, func(p *models.ProgramaVisita) string { return fechaTexto(p.Fechafin, formatoFechaHoraExportacion) }),
+				columna("institucion", "Institución", 1.6, func(p *models.ProgramaVisita) string { return p.Institucion.Nombre }),
+				columna("autoridad", "Autoridad de la institución", 1.2, func(p *models.ProgramaVisita) string { return p.Institucion.Autoridad }),
+				columna("contacto", "Contacto", 0.9, func(p *models.ProgramaVisita) string { return p.Institucion.Contacto }),
+				columna("correo", "Correo", 1.3, func(p *models.ProgramaVisita) string { return p.Institucion.Correo }),
+				columna("direccion", "Dirección", 1.5, func(p *models.ProgramaVisita) string { return p.Institucion.Direccion }),
+			},
+			lotes: func(repo repositories.ExportacionRepository, filtro repositories.FiltroExportacion, fn func([]models.ProgramaVisita) error) error {
+				return repo.RecorrerProgramasVisita(filtro, fn)
+			},
+		},
+		ListaParticipantes: listaExportable[models.VisitaDetalleEstudiantesUniversitarios]{
+			nombre: "Participantes de las visitas",
+			cols: []columnaExportable[models.VisitaDetalleEstudiantesUniversitarios]{
+				columna("id", "ID", 0.4, func(v *models.VisitaDetalleEstudiantesUniversitarios) string { return idTexto(v.ID) }),
+				columna("programa_visita_id", "Programa de visita", 0.6, func(v *models.VisitaDetalleEstudiantesUniversitarios) string {
+					return idTexto(v.ProgramaVisitaID)
+				}),
+				columna("fecha", "Fecha de la visita", 0.9, func(v *models.VisitaDetalleEstudiantesUniversitarios) string {
+					return fechaTexto(v.ProgramaVisita.Fecha, formatoFechaHoraExportacion)
+				}),
+				columna("institucion", "Institución visitante", 1.5, func(v *models.VisitaDetalleEstudiantesUniversitarios) string {
+					return v.ProgramaVisita.Institucion.Nombre
+				}),
+				columna("nombre", "Estudiante universitario", 1.5, func(v *models.VisitaDetalleEstudiantesUniversitarios) string {
+					return v.EstudianteUniversitario.Persona.Nombre
+				}),
+				columna("cedula", "Cédula", 0.8, func(v *models.VisitaDetalleEstudiantesUniversitarios) string {
+					return v.EstudianteUniversitario.Persona.Cedula
+				}),
+				columna("correo", "Correo", 1.3, func(v *models.VisitaDetalleEstudiantesUniversitarios) string {
+					return textoOpcional(v.EstudianteUniversitario.Persona.Correo)
+				}),
+				columna("telefono", "Teléfono", 0.8, func(v *models.VisitaDetalleEstudiantesUniversitarios) string {
+					return textoOpcional(v.EstudianteUniversitario.Persona.Telefono)
+				}),
+				columna("semestre", "Semestre", 0.5, func(v *models.VisitaDetalleEstudiantesUniversitarios) string {
+					return strconv.Itoa(v.EstudianteUniversitario.Semestre)
+				}),
+			},
+			lotes: func(repo repositories.ExportacionRepository, filtro repositories.FiltroExportacion, fn func([]models.VisitaDetalleEstudiantesUniversitarios) error) error {
+				return repo.RecorrerParticipantes(filtro, fn)
+			},
+		},
+		ListaDudas: listaExportable[models.Dudas]{
+			nombre: "Dudas",
+			cols: []columnaExportable[models.Dudas]{
+				columna("id", "ID", 0.4, func(d *models.Dudas) string { return idTexto(d.ID) }),
+				columna("fecha", "Fecha de la pregunta", 0.9, func(d *models.Dudas) string { return fechaTexto(d.FechaPregunta, formatoFechaHoraExportacion) }),
+				columna("estudiante", "Estudiante", 1.3, func(d *models.Dudas) string { return d.Estudiante.Persona.Nombre }),
+				columna("institucion", "Institución", 1.3, func(d *models.Dudas) string { return d.Estudiante.Institucion.Nombre }),
+				columna("pregunta", "Pregunta", 2.5, func(d *models.Dudas) string { return d.Pregunta }),
+				columna("respuesta", "Respuesta", 2.5, func(d *models.Dudas) string { return textoOpcional(d.Respuesta) }),
+				columna("fecha_respuesta", "Fecha de respuesta", 0.9, func(d *models.Dudas) string {
+					if d.FechaRespuesta == nil {
+						return ""
+					}
+					return fechaTexto(*d.FechaRespuesta, formatoFechaHoraExportacion)
+				}),
+				columna("autoridad", "Responsable", 1.2, func(d *models.Dudas) string {
+					if d.AutoridadUTEQ == nil {
+						return ""
+					}
+					return d.AutoridadUTEQ.Persona.Nombre
+				}),
+				columna("privacidad", "Privacidad", 0.6, func(d *models.Dudas) string { return d.Privacidad }),
+			},
+			lotes: func(repo repositories.ExportacionRepository, filtro repositories.FiltroExportacion, fn func([]models.Dudas) error) error {
+				return repo.RecorrerDudas(filtro, fn)
+			},
+		},
+		ListaComunicados: listaExportable[models.Comunicado]{
+			nombre: "Comunicados",
+			cols: []columnaExportable[models.Comunicado]{
+				columna("id", "ID", 0.4, func(c *models.Comunicado) string { return idTexto(c.ID) }),
+				columna("fecha", "Fecha", 0.9, func(c *models.Comunicado) string { return fechaTexto(c.CreatedAt, formatoFechaHoraExportacion) }),
+				columna("asunto", "Asunto", 1.8, func(c *models.Comunicado) string { return c.Asunto }),
+				columna("canal", "Canal", 0.6, func(c *models.Comunicado) string { return c.Canal }),
+				columna("estado", "Estado", 0.6, func(c *models.Comunicado) string { return c.Estado }),
+				columna("enviado_a", "Destinatarios", 0.7, func(c *models.Comunicado) string { return strconv.Itoa(c.EnviadoA) }),
+				columna("remitente", "Remitente", 0.9, func(c *models.Comunicado) string { return c.Usuario.Usuario }),
+				columna("mensaje", "Mensaje", 3, func(c *models.Comunicado) string { return textoPlano(c.Mensaje) }),
+			},
+			lotes: func(repo repositories.ExportacionRepository, filtro repositories.FiltroExportacion, fn func([]models.Comunicado) error) error {
+				return repo.RecorrerComunicados(filtro, fn)
+			},
+		},
+	}
+}
+
+func idTexto(id uint) string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+func textoOpcional(texto *string) string {
+	if texto == nil {
+		return ""
+	}
+	return *texto
+}
+
+// fechaTexto formatea la fecha en la zona horaria local; la fecha cero queda vacía
+func fechaTexto(fecha time.Time, formato string) string {
+	if fecha.IsZero() {
+		return ""
+	}
+	return fecha.Local().Format(formato)
+}
+
+var (
+	etiquetasMensaje = regexp.MustCompile(`<[^>]*>`)
+	espaciosMensaje  = regexp.MustCompile(`\s+`)
+)
+
+// textoPlano quita las etiquetas del HTML de Quill de los comunicados y junta los espacios
+func textoPlano(mensaje string) string {
+	texto := etiquetasMensaje.ReplaceAllString(mensaje, " ")
+	return strings.TrimSpace(espaciosMensaje.ReplaceAllString(html.UnescapeString(texto), " "))
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"ApiEscuela/backup"
+	"ApiEscuela/middleware"
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+	"context"
+	"io"
+	"time"
+)
+
+// AuthService define la lógica de negocio para la autenticación, el registro y la recuperación de contraseñas
+type AuthService interface {
+	HashPassword(password string) (string, error)
+	CheckPassword(password, hash string) bool
+	Login(loginReq LoginRequest) (*LoginResponse, error)
+	Register(registerReq RegisterRequest) (*models.Usuario, error)
+	ChangePassword(userID uint, oldPassword, newPassword string) error
+	ResetPassword(userID uint, newPassword string) error
+	GenerateNewToken(userID uint, username string, tipoUsuarioID uint) (string, error)
+	RecoverPassword(cedula string) error
+	ValidateToken(tokenString string) (bool, *middleware.JWTClaims)
+	VerifyCodigo(codigo string) (string, uint, error)
+	VerifyCodigoWithDetails(codigo string) (*VerifyCodeResult, error)
+	ResetPasswordByCodigoID(codigoID uint, usuarioID uint, nuevaClave string) error
+}
+
+// ComunicadoService define la lógica de negocio para el envío y almacenamiento de comunicados
+type ComunicadoService interface {
+	GetCorreosDestinatarios(destinatario DestinatarioInfo) ([]string, error)
+	SendEmailWithAttachments(recipients []string, subject, htmlBody string, attachments []Attachment) error
+	SendBulkEmails(correos []string, subject, htmlBody string, attachments []Attachment) EmailResult
+	CreateComunicado(comunicado *models.Comunicado) error
+	GetComunicadoByID(id uint) (*models.Comunicado, error)
+	GetAllComunicados() ([]models.Comunicado, error)
+	DeleteComunicado(id uint) error
+	SearchComunicados(termino string) ([]repositories.ResultadoBusqueda[models.Comunicado], error)
+	ResendComunicado(id uint) (EmailResult, error)
+}
+
+// RecordatorioService define el envío de recordatorios de las visitas próximas
+type RecordatorioService interface {
+	EnviarRecordatoriosVisitas(anticipacion time.Duration) (*ResultadoRecordatorios, error)
+}
+
+// NotificacionService define el centro de notificaciones de los usuarios: las genera a partir de
+// los sucesos del dominio y las entrega en la aplicación, por correo o por WhatsApp según las
+// preferencias de cada usuario
+type NotificacionService interface {
+	GetNotificaciones(usuarioID uint, soloNoLeidas bool, limite int) ([]models.Notificacion, error)
+	ContarNoLeidas(usuarioID uint) (int64, error)
+	MarcarLeida(usuarioID, id uint) (*models.Notificacion, error)
+	MarcarTodasLeidas(usuarioID uint) (int64, error)
+	GetPreferencias(usuarioID uint) (*models.PreferenciaNotificacion, error)
+	GuardarPreferencias(preferencias *models.PreferenciaNotificacion) error
+	DudaRespondida(dudaID uint) error
+	AutoridadAsignada(detalle *models.DetalleAutoridadDetallesVisita) error
+	EstudianteInscrito(relacion *models.VisitaDetalleEstudiantesUniversitarios) error
+	ComunicadoRecibido(comunicado *models.Comunicado, destinatario DestinatarioInfo)
+	Esperar()
+}
+
+// WebhookService define las suscripciones de sistemas externos a los eventos de la API y la
+// entrega firmada de esos eventos
+type WebhookService interface {
+	EventosDisponibles() []EventoWebhook
+	GetWebhooks() ([]models.Webhook, error)
+	GetWebhook(id uint) (*models.Webhook, error)
+	CrearWebhook(webhook *models.Webhook) (*WebhookCreado, error)
+	ActualizarWebhook(webhook *models.Webhook) error
+	EliminarWebhook(id uint) error
+	GetEntregas(webhookID uint, limite int) ([]models.EntregaWebhook, error)
+	Publicar(evento string, datos interface{}) error
+	Probar(webhookID uint) (*models.EntregaWebhook, error)
+	Reenviar(entregaID uint) (*models.EntregaWebhook, error)
+	ReintentarPendientes(ahora time.Time) (*ResultadoReintentos, error)
+	Esperar()
+}
+
+// AdminService define las tareas operativas sobre usuarios y códigos que se ejecutan desde la línea de comandos
+type AdminService interface {
+	CreateAdmin(datos NuevoAdmin) (*models.Usuario, string, error)
+	ResetPassword(username, clave string) (string, error)
+	UnlockUsuario(username string) (*DesbloqueoResult, error)
+	PurgeExpiredCodes(antes time.Time) (int64, error)
+}
+
+// ArchivoService define la búsqueda y eliminación de archivos subidos que ya no se usan
+type ArchivoService interface {
+	FindOrphanFiles(antes time.Time) ([]OrphanFile, error)
+	RemoveOrphanFiles(archivos []OrphanFile) (int, error)
+}
+
+// BackupService define la exportación y restauración de respaldos lógicos de los datos
+type BackupService interface {
+	Exportar(w io.Writer, opts backup.OpcionesExportacion) (*backup.Manifiesto, error)
+	Restaurar(r io.Reader, opts backup.OpcionesRestauracion) (*backup.Resultado, error)
+}
+
+// PapeleraService define la papelera de los registros eliminados lógicamente
+type PapeleraService interface {
+	Resumen() ([]ResumenPapelera, error)
+	GetEliminados(entidad string) ([]repositories.ElementoPapelera, error)
+	Restaurar(entidad string, id uint) error
+	Purgar(entidad string, id uint) error
+	PurgarVencidos(antes time.Time) (*ResultadoPurgaPapelera, error)
+}
+
+// UploadService define las subidas por partes (reanudables) de archivos grandes
+type UploadService interface {
+	CreateUpload(usuarioID uint, datos NuevaSubida) (*UploadSession, error)
+	GetUpload(usuarioID uint, id string) (*UploadSession, error)
+	WriteChunk(usuarioID uint, id string, offset int64, datos []byte, checksum string) (*UploadSession, error)
+	FinalizeUpload(usuarioID uint, id string) (*ArchivoSubido, error)
+	CancelUpload(usuarioID uint, id string) error
+	PurgeExpiredUploads() (int, error)
+}
+
+// MediaService define la biblioteca de archivos subidos: metadatos, duplicados, referencias
+// desde noticias y comunicados, y variantes de las imágenes
+type MediaService interface {
+	RegistrarArchivo(ctx context.Context, nuevo NuevoMedia) (*models.Media, error)
+	BuscarDuplicado(ctx context.Context, sha256, visibilidad string) (*models.Media, error)
+	BuscarPorClave(clave string) (*models.Media, error)
+	GetMedia(id uint) (*models.Media, error)
+	ListarMedia(filtro repositories.FiltroMedia) ([]models.Media, error)
+	CambiarVisibilidad(id uint, visibilidad string) (*models.Media, error)
+	EliminarArchivo(ctx context.Context, id uint, forzar bool) error
+	ActualizarReferencias(entidad string, id uint, claves []string) error
+	ReconstruirReferencias(referencias []ReferenciaArchivo) error
+	ProcesarImagen(ctx context.Context, clave string, datos []byte, contentType string) (*models.Media, error)
+	BuscarVariante(clave, nombre string, ancho int, aceptaWebP bool) (*models.VarianteMedia, error)
+	EliminarMedia(ctx context.Context, clave string) error
+	GenerarVariantes(ctx context.Context, opts OpcionesVariantes) (*ResultadoVariantes, error)
+	IndexarArchivos(ctx context.Context, simular bool) (*ResultadoIndexado, error)
+}
+
+// BusquedaService define la búsqueda global en personas, instituciones, programas de visita y
+// noticias, con resultados agrupados por tipo
+type BusquedaService interface {
+	Buscar(consulta ConsultaBusqueda) (*ResultadoBusquedaGlobal, error)
+}
+
+// ExportacionService define la exportación de las listas a CSV, XLSX y PDF
+type ExportacionService interface {
+	Listas() []ListaExportable
+	Preparar(lista string, filtro repositories.FiltroExportacion, columnas []string) (*Exportacion, error)
+}
+
+// AnaliticaService define los agregados del dashboard: series por período y desgloses del rango,
+// guardados en caché por unos minutos
+type AnaliticaService interface {
+	Panel(consulta ConsultaAnalitica) (*PanelAnalitica, error)
+	Visitas(consulta ConsultaAnalitica) (*SerieVisitas, error)
+	Estudiantes(consulta ConsultaAnalitica) (*EstudiantesAlcanzados, error)
+	Tematicas(consulta ConsultaAnalitica) (*RankingAnalitica, error)
+	Actividades(consulta ConsultaAnalitica) (*RankingAnalitica, error)
+	Autoridades(consulta ConsultaAnalitica) (*CargaAutoridades, error)
+	Dudas(consulta ConsultaAnalitica) (*TiemposDudas, error)
+	Comunicados(consulta ConsultaAnalitica) (*AlcanceComunicados, error)
+	Refrescar() error
+}
@@ -0,0 +1,526 @@
+package services
+
+import (
+	"ApiEscuela/imagenes"
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+	"ApiEscuela/storage"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// carpetaVariantes es la subcarpeta, junto a cada imagen, donde se guardan sus variantes
+const carpetaVariantes = "variantes"
+
+// extensionesImagen son las imágenes a las que se les generan variantes
+var extensionesImagen = map[string]bool{".jpg": true, ".jpeg": true, ".png": true, ".gif": true}
+
+// EsVariante indica si la clave es una variante generada y no un archivo subido
+func EsVariante(clave string) bool {
+	return strings.Contains("/"+clave, "/"+carpetaVariantes+"/")
+}
+
+// claveVariante construye la clave de una variante: images/x.jpg -> images/variantes/x-medium.webp
+func claveVariante(clave, nombre, formato string) string {
+	base := path.Base(clave)
+	base = strings.TrimSuffix(base, path.Ext(base))
+	ext := ".jpg"
+	if formato == imagenes.FormatoWebP {
+		ext = ".webp"
+	}
+	return path.Join(path.Dir(clave), carpetaVariantes, base+"-"+nombre+ext)
+}
+
+// Visibilidad de un archivo de la biblioteca
+const (
+	VisibilidadPublica = "publico" // cualquiera con la URL puede descargarlo
+	VisibilidadPrivada = "privado" // solo usuarios autenticados
+)
+
+var (
+	// ErrVisibilidadInvalida se devuelve cuando la visibilidad no es publico ni privado
+	ErrVisibilidadInvalida = errors.New("la visibilidad debe ser publico o privado")
+	// ErrMediaEnUso se devuelve al eliminar un archivo que usan noticias o comunicados
+	ErrMediaEnUso = errors.New("el archivo está en uso por noticias o comunicados")
+)
+
+// categoriasPorCarpeta indica la categoría de los archivos de cada carpeta del almacenamiento
+var categoriasPorCarpeta = map[string]string{
+	"images":            "image",
+	"videos":            "video",
+	"documents":         "document",
+	"comunicados_files": "document",
+}
+
+// mediaService mantiene la biblioteca de archivos subidos: metadatos, referencias y variantes
+// de las imágenes
+type mediaService struct {
+	mediaRepo repositories.MediaRepository
+	archivos  storage.Storage
+}
+
+// NewMediaService crea una nueva instancia del servicio
+func NewMediaService(mediaRepo repositories.MediaRepository, archivos storage.Storage) MediaService {
+	return &mediaService{mediaRepo: mediaRepo, archivos: archivos}
+}
+
+// NuevoMedia describe un archivo recién guardado en el almacenamiento
+type NuevoMedia struct {
+	Clave       string
+	Nombre      string // nombre original
+	Categoria   string // image, video o document
+	ContentType string
+	Tamano      int64
+	Sha256      string // del archivo tal como se subió, para detectar duplicados
+	UsuarioID   uint
+	Visibilidad string // vacío equivale a publico
+	Datos       []byte // contenido de las imágenes, para generar las variantes
+}
+
+// ValidarVisibilidad normaliza la visibilidad de un archivo; vacía equivale a publico
+func ValidarVisibilidad(visibilidad string) (string, error) {
+	switch visibilidad {
+	case "", VisibilidadPublica:
+		return VisibilidadPublica, nil
+	case VisibilidadPrivada:
+		return VisibilidadPrivada, nil
+	}
+	return "", ErrVisibilidadInvalida
+}
+
+// RegistrarArchivo agrega a la biblioteca un archivo subido. Si es una imagen genera sus
+// variantes; si eso falla la imagen se registra igual y se sirve en su tamaño original.
+func (s *mediaService) RegistrarArchivo(ctx context.Context, nuevo NuevoMedia) (*models.Media, error) {
+	visibilidad, err := ValidarVisibilidad(nuevo.Visibilidad)
+	if err != nil {
+		return nil, err
+	}
+	media := &models.Media{
+		Clave:       nuevo.Clave,
+		Nombre:      nuevo.Nombre,
+		Categoria:   nuevo.Categoria,
+		ContentType: nuevo.ContentType,
+		Tamano:      nuevo.Tamano,
+		Sha256:      nuevo.Sha256,
+		Visibilidad: visibilidad,
+		Variantes:   models.VariantesMedia{},
+	}
+	if nuevo.UsuarioID != 0 {
+		media.UsuarioID = &nuevo.UsuarioID
+	}
+	if nuevo.Datos != nil {
+		if err := s.generarVariantes(ctx, media, nuevo.Datos); err != nil {
+			log.Printf("Error al generar las variantes de %s: %v", nuevo.Clave, err)
+		}
+	}
+	if err := s.mediaRepo.SaveMedia(media); err != nil {
+		return nil, err
+	}
+	return media, nil
+}
+
+// BuscarDuplicado devuelve un archivo ya subido con el mismo contenido y visibilidad, o nil.
+// El archivo encontrado se marca como usado para que la recolección de huérfanos no lo elimine
+// antes de que se guarde el formulario que lo va a usar.
+func (s *mediaService) BuscarDuplicado(ctx context.Context, sha256, visibilidad string) (*models.Media, error) {
+	visibilidad, err := ValidarVisibilidad(visibilidad)
+	if err != nil {
+		return nil, err
+	}
+	candidatos, err := s.mediaRepo.GetMediaBySha256(sha256)
+	if err != nil {
+		return nil, err
+	}
+	for i := range candidatos {
+		m := &candidatos[i]
+		if m.Visibilidad != visibilidad {
+			continue
+		}
+		// El registro puede sobrevivir al archivo si se eliminó a mano del almacenamiento
+		if _, err := s.archivos.Info(ctx, m.Clave); err != nil {
+			continue
+		}
+		ahora := time.Now()
+		m.ReutilizadoEn = &ahora
+		if err := s.mediaRepo.SaveMedia(m); err != nil {
+			return nil, err
+		}
+		return m, nil
+	}
+	return nil, nil
+}
+
+// BuscarPorClave devuelve los metadatos de un archivo, o nil si no está en la biblioteca
+func (s *mediaService) BuscarPorClave(clave string) (*models.Media, error) {
+	media, err := s.mediaRepo.GetMediaByClave(clave)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return media, err
+}
+
+// GetMedia obtiene un archivo de la biblioteca por ID
+func (s *mediaService) GetMedia(id uint) (*models.Media, error) {
+	return s.mediaRepo.GetMediaByID(id)
+}
+
+// ListarMedia lista los archivos de la biblioteca que cumplen el filtro
+func (s *mediaService) ListarMedia(filtro repositories.FiltroMedia) ([]models.Media, error) {
+	return s.mediaRepo.SearchMedia(filtro)
+}
+
+// CambiarVisibilidad hace público o privado un archivo
+func (s *mediaService) CambiarVisibilidad(id uint, visibilidad string) (*models.Media, error) {
+	if visibilidad == "" {
+		return nil, ErrVisibilidadInvalida
+	}
+	visibilidad, err := ValidarVisibilidad(visibilidad)
+	if err != nil {
+		return nil, err
+	}
+	media, err := s.mediaRepo.GetMediaByID(id)
+	if err != nil {
+		return nil, err
+	}
+	media.Visibilidad = visibilidad
+	if err := s.mediaRepo.SaveMedia(media); err != nil {
+		return nil, err
+	}
+	return media, nil
+}
+
+// EliminarArchivo elimina un archivo de la biblioteca con sus variantes. Si lo usan noticias o
+// comunicados devuelve ErrMediaEnUso, salvo que forzar sea true.
+func (s *mediaService) EliminarArchivo(ctx context.Context, id uint, forzar bool) error {
+	media, err := s.mediaRepo.GetMediaByID(id)
+	if err != nil {
+		return err
+	}
+	if len(media.Referencias) > 0 && !forzar {
+		return ErrMediaEnUso
+	}
+	if err := s.EliminarMedia(ctx, media.Clave); err != nil {
+		return err
+	}
+	if err := s.archivos.Eliminar(ctx, media.Clave); err != nil && !errors.Is(err, storage.ErrNoEncontrado) {
+		return err
+	}
+	return nil
+}
+
+// ActualizarReferencias registra los archivos que usa una noticia o un comunicado; claves
+// vacío indica que ya no usa ninguno (por ejemplo, porque se eliminó). Las claves que no están
+// en la biblioteca se ignoran.
+func (s *mediaService) ActualizarReferencias(entidad string, id uint, claves []string) error {
+	ids := make([]uint, 0, len(claves))
+	for _, clave := range claves {
+		media, err := s.mediaRepo.GetMediaByClave(clave)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		ids = append(ids, media.ID)
+	}
+	return s.mediaRepo.ReplaceMediaReferencias(entidad, id, ids)
+}
+
+// ReconstruirReferencias reemplaza todas las referencias de la biblioteca
+func (s *mediaService) ReconstruirReferencias(referencias []ReferenciaArchivo) error {
+	ids := map[string]uint{}
+	vistas := map[models.MediaReferencia]bool{}
+	nuevas := make([]models.MediaReferencia, 0, len(referencias))
+	for _, ref := range referencias {
+		id, ok := ids[ref.Clave]
+		if !ok {
+			media, err := s.mediaRepo.GetMediaByClave(ref.Clave)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+			if media != nil {
+				id = media.ID
+			}
+			ids[ref.Clave] = id
+		}
+		nueva := models.MediaReferencia{MediaID: id, Entidad: ref.Entidad, EntidadID: ref.EntidadID}
+		if id == 0 || vistas[nueva] {
+			continue
+		}
+		vistas[nueva] = true
+		nuevas = append(nuevas, nueva)
+	}
+	return s.mediaRepo.ReplaceAllMediaReferencias(nuevas)
+}
+
+// ProcesarImagen genera las variantes de una imagen ya guardada en clave y registra sus
+// dimensiones y BlurHash; conserva el resto de los metadatos si ya estaba en la biblioteca
+func (s *mediaService) ProcesarImagen(ctx context.Context, clave string, datos []byte, contentType string) (*models.Media, error) {
+	media, err := s.mediaRepo.GetMediaByClave(clave)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		media = &models.Media{Clave: clave, Nombre: path.Base(clave), Categoria: "image", Visibilidad: VisibilidadPublica}
+	} else if err != nil {
+		return nil, err
+	}
+	media.ContentType = contentType
+	media.Tamano = int64(len(datos))
+	if err := s.generarVariantes(ctx, media, datos); err != nil {
+		return nil, err
+	}
+	if err := s.mediaRepo.SaveMedia(media); err != nil {
+		return nil, err
+	}
+	return media, nil
+}
+
+// generarVariantes guarda las variantes de una imagen y completa sus dimensiones y BlurHash.
+// Las variantes anteriores que ya no se generan se eliminan.
+func (s *mediaService) generarVariantes(ctx context.Context, media *models.Media, datos []byte) error {
+	res, err := imagenes.Procesar(datos)
+	if err != nil {
+		return err
+	}
+
+	variantes := models.VariantesMedia{}
+	nuevas := map[string]bool{}
+	for _, v := range res.Variantes {
+		claveV := claveVariante(media.Clave, v.Nombre, v.Formato)
+		if err := s.archivos.Guardar(ctx, claveV, bytes.NewReader(v.Datos), int64(len(v.Datos)), v.ContentType); err != nil {
+			return fmt.Errorf("error al guardar la variante %s: %v", claveV, err)
+		}
+		nuevas[claveV] = true
+		variantes = append(variantes, models.VarianteMedia{
+			Nombre: v.Nombre, Formato: v.Formato, Clave: claveV, ContentType: v.ContentType,
+			Ancho: v.Ancho, Alto: v.Alto, Tamano: int64(len(v.Datos)),
+		})
+	}
+	for _, v := range media.Variantes {
+		if !nuevas[v.Clave] {
+			s.archivos.Eliminar(ctx, v.Clave)
+		}
+	}
+
+	media.Ancho = res.Ancho
+	media.Alto = res.Alto
+	media.Blurhash = res.Blurhash
+	media.Variantes = variantes
+	return nil
+}
+
+// BuscarVariante elige la variante de una imagen para un tamaño por nombre (thumb, medium,
+// large) o para un ancho mínimo en píxeles; prefiere WebP si el cliente lo acepta. Devuelve nil
+// si se debe servir el original: no hay metadatos, la imagen es más pequeña que la variante o
+// ninguna variante alcanza el ancho pedido.
+func (s *mediaService) BuscarVariante(clave, nombre string, ancho int, aceptaWebP bool) (*models.VarianteMedia, error) {
+	if nombre != "" {
+		if _, ok := imagenes.TamanoPorNombre(nombre); !ok {
+			return nil, fmt.Errorf("tamaño no válido: use thumb, medium o large")
+		}
+	}
+	media, err := s.mediaRepo.GetMediaByClave(clave)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range imagenes.Tamanos {
+		if nombre != "" && t.Nombre != nombre {
+			continue
+		}
+		var elegida *models.VarianteMedia
+		for i := range media.Variantes {
+			v := &media.Variantes[i]
+			if v.Nombre != t.Nombre || (nombre == "" && v.Ancho < ancho) {
+				continue
+			}
+			if elegida == nil || (aceptaWebP && v.Formato == imagenes.FormatoWebP) {
+				elegida = v
+			}
+		}
+		if elegida != nil {
+			return elegida, nil
+		}
+	}
+	return nil, nil
+}
+
+// EliminarMedia elimina las variantes y los metadatos de un archivo (no el archivo)
+func (s *mediaService) EliminarMedia(ctx context.Context, clave string) error {
+	media, err := s.mediaRepo.GetMediaByClave(clave)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, v := range media.Variantes {
+		if err := s.archivos.Eliminar(ctx, v.Clave); err != nil && !errors.Is(err, storage.ErrNoEncontrado) {
+			return err
+		}
+	}
+	return s.mediaRepo.DeleteMediaByClave(clave)
+}
+
+// OpcionesVariantes configuran la generación de variantes de las imágenes existentes
+type OpcionesVariantes struct {
+	Prefijo    string // carpeta a recorrer; por defecto "images/"
+	Reemplazar bool   // volver a procesar las imágenes que ya tienen metadatos
+	Simular    bool   // solo contar las imágenes que se procesarían
+}
+
+// ResultadoVariantes resume la generación de variantes
+type ResultadoVariantes struct {
+	Procesadas int
+	Omitidas   int
+	Fallidas   int
+	Variantes  int
+	Errores    []string
+}
+
+// GenerarVariantes procesa las imágenes ya guardadas que aún no se procesaron. Una imagen que
+// no se puede procesar se informa en Errores y no detiene el resto.
+func (s *mediaService) GenerarVariantes(ctx context.Context, opts OpcionesVariantes) (*ResultadoVariantes, error) {
+	if opts.Prefijo == "" {
+		opts.Prefijo = "images/"
+	}
+
+	// Primero se listan las claves: procesar agrega variantes a la misma carpeta
+	var claves []string
+	err := s.archivos.Listar(ctx, opts.Prefijo, func(obj storage.Objeto) error {
+		if !EsVariante(obj.Clave) && extensionesImagen[strings.ToLower(path.Ext(obj.Clave))] {
+			claves = append(claves, obj.Clave)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error al recorrer %s: %v", opts.Prefijo, err)
+	}
+
+	res := &ResultadoVariantes{}
+	for _, clave := range claves {
+		if !opts.Reemplazar {
+			// Las imágenes indexadas con files index todavía no tienen dimensiones
+			if media, err := s.mediaRepo.GetMediaByClave(clave); err == nil && media.Ancho > 0 {
+				res.Omitidas++
+				continue
+			}
+		}
+		if opts.Simular {
+			res.Procesadas++
+			continue
+		}
+
+		media, err := s.procesarGuardada(ctx, clave)
+		if err != nil {
+			res.Fallidas++
+			res.Errores = append(res.Errores, fmt.Sprintf("%s: %v", clave, err))
+			continue
+		}
+		res.Procesadas++
+		res.Variantes += len(media.Variantes)
+	}
+	return res, nil
+}
+
+// ResultadoIndexado resume la indexación de los archivos existentes
+type ResultadoIndexado struct {
+	Indexados int
+	Omitidos  int // ya estaban en la biblioteca
+	Fallidos  int
+	Errores   []string
+}
+
+// IndexarArchivos agrega a la biblioteca, con su tamaño y checksum, los archivos subidos antes
+// de que existiera. Quedan públicos y sin usuario; las imágenes no tienen variantes hasta
+// ejecutar GenerarVariantes.
+func (s *mediaService) IndexarArchivos(ctx context.Context, simular bool) (*ResultadoIndexado, error) {
+	var objetos []storage.Objeto
+	for _, carpeta := range carpetasSubidas {
+		err := s.archivos.Listar(ctx, carpeta+"/", func(obj storage.Objeto) error {
+			if !EsVariante(obj.Clave) {
+				objetos = append(objetos, obj)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error al recorrer %s: %v", carpeta, err)
+		}
+	}
+
+	res := &ResultadoIndexado{}
+	for _, obj := range objetos {
+		if _, err := s.mediaRepo.GetMediaByClave(obj.Clave); err == nil {
+			res.Omitidos++
+			continue
+		}
+		if simular {
+			res.Indexados++
+			continue
+		}
+		if err := s.indexar(ctx, obj.Clave); err != nil {
+			res.Fallidos++
+			res.Errores = append(res.Errores, fmt.Sprintf("%s: %v", obj.Clave, err))
+			continue
+		}
+		res.Indexados++
+	}
+	return res, nil
+}
+
+func (s *mediaService) indexar(ctx context.Context, clave string) error {
+	r, obj, err := s.archivos.Abrir(ctx, clave)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	h := sha256.New()
+	tamano, err := io.Copy(h, r)
+	if err != nil {
+		return err
+	}
+	contentType := obj.ContentType
+	if contentType == "" || contentType == "application/octet-stream" {
+		contentType = storage.ContentTypeDe(clave)
+	}
+	carpeta, _, _ := strings.Cut(clave, "/")
+	return s.mediaRepo.SaveMedia(&models.Media{
+		Clave:       clave,
+		Nombre:      path.Base(clave),
+		Categoria:   categoriasPorCarpeta[carpeta],
+		ContentType: contentType,
+		Tamano:      tamano,
+		Sha256:      hex.EncodeToString(h.Sum(nil)),
+		Visibilidad: VisibilidadPublica,
+		Variantes:   models.VariantesMedia{},
+	})
+}
+
+func (s *mediaService) procesarGuardada(ctx context.Context, clave string) (*models.Media, error) {
+	r, obj, err := s.archivos.Abrir(ctx, clave)
+	if err != nil {
+		return nil, err
+	}
+	datos, err := io.ReadAll(r)
+	r.Close()
+	if err != nil {
+		return nil, err
+	}
+	contentType := obj.ContentType
+	if contentType == "" || contentType == "application/octet-stream" {
+		contentType = storage.ContentTypeDe(clave)
+	}
+	return s.ProcesarImagen(ctx, clave, datos, contentType)
+}
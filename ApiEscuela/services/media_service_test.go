@@ -0,0 +1,245 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+	"ApiEscuela/services"
+	"ApiEscuela/storage"
+	"ApiEscuela/testutil"
+)
+
+// imagenPNG genera una imagen opaca del ancho indicado con proporción 2:1
+func imagenPNG(t *testing.T, ancho int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, ancho, ancho/2))
+	for y := 0; y < ancho/2; y++ {
+		for x := 0; x < ancho; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 90, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestVariantesDeImagen(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		ctx := context.Background()
+		media := services.NewMediaService(repos.Media, repos.Archivos)
+
+		datos := imagenPNG(t, 1000)
+		m, err := media.ProcesarImagen(ctx, "images/foto.png", datos, "image/png")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m.Ancho != 1000 || m.Alto != 500 || m.Blurhash == "" {
+			t.Errorf("media = %dx%d %q", m.Ancho, m.Alto, m.Blurhash)
+		}
+		// 1000 px: thumb y medium, no large
+		nombres := map[string]bool{}
+		for _, v := range m.Variantes {
+			nombres[v.Nombre] = true
+			if _, err := repos.Archivos.Info(ctx, v.Clave); err != nil {
+				t.Errorf("variante %s no guardada: %v", v.Clave, err)
+			}
+			if !services.EsVariante(v.Clave) {
+				t.Errorf("%s no se reconoce como variante", v.Clave)
+			}
+		}
+		if !nombres["thumb"] || !nombres["medium"] || nombres["large"] {
+			t.Errorf("variantes = %v", nombres)
+		}
+
+		casos := []struct {
+			nombre string
+			ancho  int
+			webp   bool
+			want   string // nombre de la variante; vacío para el original
+		}{
+			{"thumb", 0, false, "thumb"},
+			{"large", 0, false, ""},
+			{"", 200, false, "thumb"},
+			{"", 321, false, "medium"},
+			{"", 900, false, ""},
+		}
+		for _, c := range casos {
+			v, err := media.BuscarVariante("images/foto.png", c.nombre, c.ancho, c.webp)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := ""
+			if v != nil {
+				got = v.Nombre
+				if v.Formato != "jpeg" {
+					t.Errorf("%+v: formato %s sin Accept image/webp", c, v.Formato)
+				}
+			}
+			if got != c.want {
+				t.Errorf("BuscarVariante(%q, %d) = %q, se esperaba %q", c.nombre, c.ancho, got, c.want)
+			}
+		}
+
+		if v, _ := media.BuscarVariante("images/otra.png", "thumb", 0, false); v != nil {
+			t.Errorf("imagen sin metadatos = %+v, se esperaba el original", v)
+		}
+		if _, err := media.BuscarVariante("images/foto.png", "gigante", 0, false); err == nil {
+			t.Error("un tamaño desconocido debería fallar")
+		}
+	})
+}
+
+func TestGenerarVariantesExistentes(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		ctx := context.Background()
+		media := services.NewMediaService(repos.Media, repos.Archivos)
+		guardar := func(clave string, datos []byte) {
+			t.Helper()
+			if err := repos.Archivos.Guardar(ctx, clave, bytes.NewReader(datos), int64(len(datos)), storage.ContentTypeDe(clave)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		guardar("images/antigua.png", imagenPNG(t, 700))
+		guardar("images/danada.jpg", []byte("no es una imagen"))
+		guardar("documents/acta.pdf", []byte("%PDF-1.4"))
+
+		res, err := media.GenerarVariantes(ctx, services.OpcionesVariantes{Simular: true})
+		if err != nil || res.Procesadas != 2 || res.Variantes != 0 {
+			t.Fatalf("simulación = %+v, %v", res, err)
+		}
+		if _, err := repos.Media.GetMediaByClave("images/antigua.png"); err == nil {
+			t.Error("la simulación no debe procesar")
+		}
+
+		res, err = media.GenerarVariantes(ctx, services.OpcionesVariantes{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res.Procesadas != 1 || res.Fallidas != 1 || res.Variantes == 0 || !strings.HasPrefix(res.Errores[0], "images/danada.jpg") {
+			t.Errorf("resultado = %+v", res)
+		}
+		m, err := repos.Media.GetMediaByClave("images/antigua.png")
+		if err != nil || m.Ancho != 700 || len(m.Variantes) != res.Variantes {
+			t.Fatalf("media = %+v, %v", m, err)
+		}
+
+		// Una segunda pasada omite las imágenes ya procesadas y no toma las variantes como originales
+		res, err = media.GenerarVariantes(ctx, services.OpcionesVariantes{})
+		if err != nil || res.Omitidas != 1 || res.Procesadas != 0 || res.Fallidas != 1 {
+			t.Errorf("segunda pasada = %+v, %v", res, err)
+		}
+	})
+}
+
+func TestBibliotecaDeArchivos(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		ctx := context.Background()
+		media := services.NewMediaService(repos.Media, repos.Archivos)
+
+		registrar := func(clave, sha, visibilidad string) *models.Media {
+			t.Helper()
+			if err := repos.Archivos.Guardar(ctx, clave, strings.NewReader("x"), 1, ""); err != nil {
+				t.Fatal(err)
+			}
+			m, err := media.RegistrarArchivo(ctx, services.NuevoMedia{
+				Clave: clave, Nombre: "acta final.pdf", Categoria: "document", ContentType: "application/pdf",
+				Tamano: 1, Sha256: sha, UsuarioID: e.Admin.ID, Visibilidad: visibilidad,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			return m
+		}
+		acta := registrar("documents/acta.pdf", "aaa", "")
+		privado := registrar("documents/notas.pdf", "bbb", services.VisibilidadPrivada)
+		if acta.Visibilidad != services.VisibilidadPublica || *acta.UsuarioID != e.Admin.ID {
+			t.Errorf("acta = %+v", acta)
+		}
+
+		// Duplicados: mismo contenido y visibilidad, y el archivo debe seguir existiendo
+		if d, err := media.BuscarDuplicado(ctx, "aaa", ""); err != nil || d == nil || d.ID != acta.ID || d.ReutilizadoEn == nil {
+			t.Errorf("duplicado = %+v, %v", d, err)
+		}
+		if d, _ := media.BuscarDuplicado(ctx, "aaa", services.VisibilidadPrivada); d != nil {
+			t.Errorf("un archivo público no debe reutilizarse como privado: %+v", d)
+		}
+		repos.Archivos.Eliminar(ctx, "documents/notas.pdf")
+		if d, _ := media.BuscarDuplicado(ctx, "bbb", services.VisibilidadPrivada); d != nil {
+			t.Errorf("un archivo eliminado del almacenamiento no debe reutilizarse: %+v", d)
+		}
+		if _, err := media.BuscarDuplicado(ctx, "aaa", "oculto"); !errors.Is(err, services.ErrVisibilidadInvalida) {
+			t.Errorf("visibilidad inválida = %v", err)
+		}
+
+		// Referencias
+		noticia := &models.Noticia{Titulo: "Feria", URLNoticia: "/api/files/documents/acta.pdf", UsuarioID: e.Admin.ID}
+		if err := media.ActualizarReferencias(services.EntidadNoticia, 7, services.ClavesNoticia(noticia)); err != nil {
+			t.Fatal(err)
+		}
+		m, err := media.GetMedia(acta.ID)
+		if err != nil || len(m.Referencias) != 1 || m.Referencias[0].Entidad != "noticia" || m.Referencias[0].EntidadID != 7 {
+			t.Fatalf("referencias = %+v, %v", m, err)
+		}
+		sin, err := media.ListarMedia(repositories.FiltroMedia{SinReferencias: true})
+		if err != nil || len(sin) != 1 || sin[0].ID != privado.ID {
+			t.Errorf("sin referencias = %+v, %v", sin, err)
+		}
+		if encontrados, _ := media.ListarMedia(repositories.FiltroMedia{Texto: "ACTA", Visibilidad: services.VisibilidadPublica}); len(encontrados) != 1 {
+			t.Errorf("búsqueda = %+v", encontrados)
+		}
+
+		if err := media.EliminarArchivo(ctx, acta.ID, false); !errors.Is(err, services.ErrMediaEnUso) {
+			t.Errorf("eliminar en uso = %v, se esperaba ErrMediaEnUso", err)
+		}
+		// Al eliminar la noticia el archivo queda libre
+		if err := media.ActualizarReferencias(services.EntidadNoticia, 7, nil); err != nil {
+			t.Fatal(err)
+		}
+		if err := media.EliminarArchivo(ctx, acta.ID, false); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := repos.Archivos.Info(ctx, "documents/acta.pdf"); !errors.Is(err, storage.ErrNoEncontrado) {
+			t.Error("el archivo debería eliminarse del almacenamiento")
+		}
+		if _, err := media.GetMedia(acta.ID); err == nil {
+			t.Error("el archivo debería eliminarse de la biblioteca")
+		}
+	})
+}
+
+func TestIndexarArchivos(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		ctx := context.Background()
+		media := services.NewMediaService(repos.Media, repos.Archivos)
+		for _, clave := range []string{"images/vieja.png", "images/variantes/vieja-thumb.jpg", "comunicados_files/1700000000/acta.pdf"} {
+			if err := repos.Archivos.Guardar(ctx, clave, strings.NewReader("hola"), 4, ""); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		res, err := media.IndexarArchivos(ctx, false)
+		if err != nil || res.Indexados != 2 || res.Omitidos != 0 {
+			t.Fatalf("indexado = %+v, %v", res, err)
+		}
+		acta, err := repos.Media.GetMediaByClave("comunicados_files/1700000000/acta.pdf")
+		// sha256("hola")
+		if err != nil || acta.Categoria != "document" || acta.Tamano != 4 || acta.Sha256 != "b221d9dbb083a7f33428d7c2a3c3198ae925614d70210e28716ccaa7cd4ddb79" {
+			t.Fatalf("acta = %+v, %v", acta, err)
+		}
+		if res, _ := media.IndexarArchivos(ctx, false); res.Indexados != 0 || res.Omitidos != 2 {
+			t.Errorf("segunda pasada = %+v", res)
+		}
+	})
+}
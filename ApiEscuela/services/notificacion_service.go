@@ -0,0 +1,357 @@
+package services
+
+import (
+	"ApiEscuela/errores"
+	"ApiEscuela/eventos"
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+	"errors"
+	"fmt"
+	"html"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Tipos de notificación
+const (
+	NotificacionDudaRespondida    = "duda_respondida"
+	NotificacionAutoridadAsignada = "autoridad_asignada"
+	NotificacionInscripcionVisita = "inscripcion_visita"
+	NotificacionComunicado        = "comunicado"
+)
+
+// loteComunicado es la cantidad de estudiantes que se procesan juntos al avisar un comunicado
+const loteComunicado = 500
+
+// ErrNotificacionNoEncontrada se devuelve al marcar una notificación que no existe o es de otro usuario
+var ErrNotificacionNoEncontrada = errores.NoEncontrado("notificacion_no_encontrada", "La notificación no existe")
+
+// DependenciasNotificaciones son los repositorios y canales que usa el servicio de notificaciones
+type DependenciasNotificaciones struct {
+	Notificaciones            repositories.NotificacionRepository
+	Usuarios                  repositories.UsuarioRepository
+	Dudas                     repositories.DudasRepository
+	Estudiantes               repositories.EstudianteRepository
+	EstudiantesUniversitarios repositories.EstudianteUniversitarioRepository
+	Autoridades               repositories.AutoridadUTEQRepository
+	Programas                 repositories.ProgramaVisitaRepository
+	// Correo envía las notificaciones por correo con la configuración SMTP de los comunicados
+	Correo ComunicadoService
+	// WhatsApp envía las notificaciones por WhatsApp
+	WhatsApp MensajeroWhatsApp
+	// Eventos avisa las notificaciones nuevas por /api/eventos; puede ser nil
+	Eventos *eventos.Bus
+}
+
+// notificacionService genera las notificaciones de los usuarios y las entrega por los canales
+// que cada uno eligió
+type notificacionService struct {
+	DependenciasNotificaciones
+	envios sync.WaitGroup
+}
+
+// NewNotificacionService crea una nueva instancia del servicio
+func NewNotificacionService(deps DependenciasNotificaciones) NotificacionService {
+	return &notificacionService{DependenciasNotificaciones: deps}
+}
+
+// GetNotificaciones obtiene las notificaciones del usuario, de la más reciente a la más antigua
+func (s *notificacionService) GetNotificaciones(usuarioID uint, soloNoLeidas bool, limite int) ([]models.Notificacion, error) {
+	return s.Notificaciones.GetNotificacionesByUsuario(usuarioID, soloNoLeidas, limite)
+}
+
+// ContarNoLeidas cuenta las notificaciones sin leer del usuario
+func (s *notificacionService) ContarNoLeidas(usuarioID uint) (int64, error) {
+	return s.Notificaciones.ContarNoLeidas(usuarioID)
+}
+
+// MarcarLeida marca como leída una notificación del usuario
+func (s *notificacionService) MarcarLeida(usuarioID, id uint) (*models.Notificacion, error) {
+	notificacion, err := s.Notificaciones.MarcarLeida(usuarioID, id, time.Now())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNotificacionNoEncontrada
+	}
+	return notificacion, err
+}
+
+// MarcarTodasLeidas marca como leídas todas las notificaciones del usuario y devuelve cuántas eran
+func (s *notificacionService) MarcarTodasLeidas(usuarioID uint) (int64, error) {
+	return s.Notificaciones.MarcarTodasLeidas(usuarioID, time.Now())
+}
+
+// GetPreferencias obtiene los canales elegidos por el usuario; sin preferencias guardadas
+// recibe las notificaciones solo en la aplicación
+func (s *notificacionService) GetPreferencias(usuarioID uint) (*models.PreferenciaNotificacion, error) {
+	preferencias, err := s.Notificaciones.GetPreferencias(usuarioID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.PreferenciaNotificacion{UsuarioID: usuarioID, App: true}, nil
+	}
+	return preferencias, err
+}
+
+// GuardarPreferencias guarda los canales elegidos por el usuario
+func (s *notificacionService) GuardarPreferencias(preferencias *models.PreferenciaNotificacion) error {
+	return s.Notificaciones.GuardarPreferencias(preferencias)
+}
+
+// DudaRespondida avisa al estudiante que hizo la pregunta que su duda fue respondida
+func (s *notificacionService) DudaRespondida(dudaID uint) error {
+	duda, err := s.Dudas.GetDudasByID(dudaID)
+	if err != nil {
+		return fmt.Errorf("error al obtener la duda %d: %v", dudaID, err)
+	}
+	estudiante, err := s.Estudiantes.GetEstudianteByID(duda.EstudianteID)
+	if err != nil {
+		return fmt.Errorf("error al obtener el estudiante %d: %v", duda.EstudianteID, err)
+	}
+	mensaje := fmt.Sprintf("Tu pregunta \"%s\" ya tiene respuesta", recortar(duda.Pregunta, 80))
+	if duda.Respuesta != nil {
+		mensaje += ": " + recortar(*duda.Respuesta, 200)
+	}
+	return s.notificarPersona(estudiante.PersonaID, models.Notificacion{
+		Tipo:      NotificacionDudaRespondida,
+		Titulo:    "Tu duda fue respondida",
+		Mensaje:   mensaje,
+		Recurso:   "dudas",
+		RecursoID: duda.ID,
+	}, true)
+}
+
+// AutoridadAsignada avisa a la autoridad que fue asignada a un programa de visita
+func (s *notificacionService) AutoridadAsignada(detalle *models.DetalleAutoridadDetallesVisita) error {
+	autoridad, err := s.Autoridades.GetAutoridadUTEQByID(detalle.AutoridadUTEQID)
+	if err != nil {
+		return fmt.Errorf("error al obtener la autoridad %d: %v", detalle.AutoridadUTEQID, err)
+	}
+	visita, err := s.describirVisita(detalle.ProgramaVisitaID)
+	if err != nil {
+		return err
+	}
+	return s.notificarPersona(autoridad.PersonaID, models.Notificacion{
+		Tipo:      NotificacionAutoridadAsignada,
+		Titulo:    "Te asignaron a una visita",
+		Mensaje:   "Fuiste asignado a " + visita,
+		Recurso:   "programas-visita",
+		RecursoID: detalle.ProgramaVisitaID,
+	}, true)
+}
+
+// EstudianteInscrito avisa al estudiante universitario que fue inscrito en un programa de visita
+func (s *notificacionService) EstudianteInscrito(relacion *models.VisitaDetalleEstudiantesUniversitarios) error {
+	estudiante, err := s.EstudiantesUniversitarios.GetEstudianteUniversitarioByID(relacion.EstudianteUniversitarioID)
+	if err != nil {
+		return fmt.Errorf("error al obtener el estudiante universitario %d: %v", relacion.EstudianteUniversitarioID, err)
+	}
+	visita, err := s.describirVisita(relacion.ProgramaVisitaID)
+	if err != nil {
+		return err
+	}
+	return s.notificarPersona(estudiante.PersonaID, models.Notificacion{
+		Tipo:      NotificacionInscripcionVisita,
+		Titulo:    "Te inscribieron en una visita",
+		Mensaje:   "Fuiste inscrito en " + visita,
+		Recurso:   "programas-visita",
+		RecursoID: relacion.ProgramaVisitaID,
+	}, true)
+}
+
+// ComunicadoRecibido avisa en la aplicación a los estudiantes a los que se dirigió un comunicado.
+// El comunicado ya llegó por su propio canal, por eso no se reenvía por correo ni WhatsApp; los
+// comunicados a instituciones no tienen usuarios a quienes avisar. Se ejecuta en segundo plano
+// porque "todos" puede abarcar a miles de estudiantes: los errores solo se registran en el log.
+func (s *notificacionService) ComunicadoRecibido(comunicado *models.Comunicado, destinatario DestinatarioInfo) {
+	notificacion := models.Notificacion{
+		Tipo:      NotificacionComunicado,
+		Titulo:    "Nuevo comunicado",
+		Mensaje:   comunicado.Asunto,
+		Recurso:   "comunicados",
+		RecursoID: comunicado.ID,
+	}
+	s.envios.Add(1)
+	go func() {
+		defer s.envios.Done()
+		personas, err := s.personasDestinatarias(destinatario)
+		if err != nil {
+			log.Printf("Error al notificar el comunicado %d: %v", notificacion.RecursoID, err)
+			return
+		}
+		for inicio := 0; inicio < len(personas); inicio += loteComunicado {
+			lote := personas[inicio:min(inicio+loteComunicado, len(personas))]
+			if err := s.notificarLote(lote, notificacion); err != nil {
+				log.Printf("Error al notificar el comunicado %d a %d personas: %v", notificacion.RecursoID, len(lote), err)
+			}
+		}
+	}()
+}
+
+// personasDestinatarias devuelve las personas de los estudiantes a los que se dirigió un comunicado
+func (s *notificacionService) personasDestinatarias(destinatario DestinatarioInfo) ([]uint, error) {
+	var personas []uint
+	switch destinatario.Tipo {
+	case "todos":
+		estudiantes, err := s.Estudiantes.GetAllEstudiantes()
+		if err != nil {
+			return nil, fmt.Errorf("error al obtener los estudiantes: %v", err)
+		}
+		for _, estudiante := range estudiantes {
+			personas = append(personas, estudiante.PersonaID)
+		}
+	case "estudiantes":
+		for _, id := range destinatario.IDs {
+			if estudiante, err := s.Estudiantes.GetEstudianteByID(id); err == nil {
+				personas = append(personas, estudiante.PersonaID)
+			}
+		}
+	}
+	return personas, nil
+}
+
+// notificarLote guarda la notificación para los usuarios de las personas que la reciben en la
+// aplicación, con una sola consulta de usuarios, una de preferencias y un INSERT por lotes. Si el
+// INSERT falla se guarda una por una y se registra en el log cada usuario que no la recibió.
+func (s *notificacionService) notificarLote(personas []uint, notificacion models.Notificacion) error {
+	usuarios, err := s.Usuarios.GetUsuariosByPersonas(personas)
+	if err != nil {
+		return fmt.Errorf("error al obtener los usuarios: %v", err)
+	}
+	ids := make([]uint, len(usuarios))
+	for i, usuario := range usuarios {
+		ids[i] = usuario.ID
+	}
+	preferencias, err := s.Notificaciones.GetPreferenciasByUsuarios(ids)
+	if err != nil {
+		return fmt.Errorf("error al obtener las preferencias: %v", err)
+	}
+	// Sin preferencias guardadas el usuario recibe las notificaciones en la aplicación
+	enApp := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		enApp[id] = true
+	}
+	for _, p := range preferencias {
+		enApp[p.UsuarioID] = p.App
+	}
+
+	var notificaciones []models.Notificacion
+	for _, id := range ids {
+		if enApp[id] {
+			notificacion.UsuarioID = id
+			notificaciones = append(notificaciones, notificacion)
+		}
+	}
+	if err := s.Notificaciones.CreateNotificaciones(notificaciones); err != nil {
+		log.Printf("Error al guardar %d notificaciones en lote, se guardan una por una: %v", len(notificaciones), err)
+		for i := range notificaciones {
+			notificaciones[i].ID = 0
+			if err := s.Notificaciones.CreateNotificacion(&notificaciones[i]); err != nil {
+				log.Printf("Error al guardar la notificación del usuario %d: %v", notificaciones[i].UsuarioID, err)
+				continue
+			}
+			s.Eventos.PublicarPara(notificaciones[i].UsuarioID, eventos.NotificacionNueva, notificaciones[i])
+		}
+		return nil
+	}
+	for _, n := range notificaciones {
+		s.Eventos.PublicarPara(n.UsuarioID, eventos.NotificacionNueva, n)
+	}
+	return nil
+}
+
+// Esperar espera a que terminen los envíos por correo y WhatsApp en curso
+func (s *notificacionService) Esperar() {
+	s.envios.Wait()
+}
+
+// describirVisita arma el texto con la institución y la fecha de un programa de visita
+func (s *notificacionService) describirVisita(programaID uint) (string, error) {
+	programa, err := s.Programas.GetProgramaVisitaByID(programaID)
+	if err != nil {
+		return "", fmt.Errorf("error al obtener el programa de visita %d: %v", programaID, err)
+	}
+	institucion := programa.Institucion.Nombre
+	if institucion == "" {
+		institucion = fmt.Sprintf("la institución %d", programa.InstitucionID)
+	}
+	return fmt.Sprintf("la visita de %s del %s", institucion, programa.Fecha.Format("02/01/2006 15:04")), nil
+}
+
+// notificarPersona notifica a cada usuario de la persona; externos habilita los envíos por
+// correo y WhatsApp según las preferencias de cada uno
+func (s *notificacionService) notificarPersona(personaID uint, notificacion models.Notificacion, externos bool) error {
+	usuarios, err := s.Usuarios.GetUsuariosByPersona(personaID)
+	if err != nil {
+		return fmt.Errorf("error al obtener los usuarios de la persona %d: %v", personaID, err)
+	}
+	for i := range usuarios {
+		if err := s.notificarUsuario(&usuarios[i], notificacion, externos); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notificarUsuario guarda la notificación en el centro de notificaciones del usuario y la envía
+// en segundo plano por correo y WhatsApp; un envío fallido solo se registra en el log
+func (s *notificacionService) notificarUsuario(usuario *models.Usuario, notificacion models.Notificacion, externos bool) error {
+	preferencias, err := s.GetPreferencias(usuario.ID)
+	if err != nil {
+		return fmt.Errorf("error al obtener las preferencias del usuario %d: %v", usuario.ID, err)
+	}
+	notificacion.UsuarioID = usuario.ID
+	if preferencias.App {
+		if err := s.Notificaciones.CreateNotificacion(&notificacion); err != nil {
+			return fmt.Errorf("error al guardar la notificación: %v", err)
+		}
+		s.Eventos.PublicarPara(usuario.ID, eventos.NotificacionNueva, notificacion)
+	}
+	if !externos {
+		return nil
+	}
+
+	persona := usuario.Persona
+	if preferencias.Correo && s.Correo != nil && persona.Correo != nil && *persona.Correo != "" {
+		correo := *persona.Correo
+		s.enviar("por correo", usuario.ID, func() error {
+			return s.Correo.SendEmailWithAttachments([]string{correo}, notificacion.Titulo, cuerpoNotificacion(&notificacion), nil)
+		})
+	}
+	if preferencias.WhatsApp && s.WhatsApp != nil && persona.Telefono != nil && *persona.Telefono != "" {
+		telefono := *persona.Telefono
+		s.enviar("por WhatsApp", usuario.ID, func() error {
+			return s.WhatsApp.EnviarMensaje(telefono, "*"+notificacion.Titulo+"*\n"+notificacion.Mensaje)
+		})
+	}
+	return nil
+}
+
+// enviar ejecuta un envío externo en segundo plano
+func (s *notificacionService) enviar(canal string, usuarioID uint, envio func() error) {
+	s.envios.Add(1)
+	go func() {
+		defer s.envios.Done()
+		if err := envio(); err != nil {
+			log.Printf("Error al enviar la notificación %s al usuario %d: %v", canal, usuarioID, err)
+		}
+	}()
+}
+
+// cuerpoNotificacion arma el cuerpo HTML del correo de una notificación
+func cuerpoNotificacion(notificacion *models.Notificacion) string {
+	return fmt.Sprintf("<p><strong>%s</strong></p><p>%s</p>"+
+		"<p>Puede ver todas sus notificaciones en la aplicación.</p>"+
+		"<p>Este es un mensaje automático; por favor no responda a este correo.</p>",
+		html.EscapeString(notificacion.Titulo), html.EscapeString(notificacion.Mensaje))
+}
+
+// recortar acorta un texto a max caracteres agregando puntos suspensivos
+func recortar(texto string, max int) string {
+	texto = strings.TrimSpace(texto)
+	runas := []rune(texto)
+	if len(runas) <= max {
+		return texto
+	}
+	return strings.TrimSpace(string(runas[:max])) + "…"
+}
@@ -0,0 +1,218 @@
+package services_test
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"ApiEscuela/eventos"
+	"ApiEscuela/models"
+	"ApiEscuela/services"
+	"ApiEscuela/testutil"
+)
+
+// whatsAppMensajes registra los mensajes de WhatsApp en lugar de enviarlos
+type whatsAppMensajes struct {
+	mu       sync.Mutex
+	enviados []string
+	fallar   bool
+}
+
+func (w *whatsAppMensajes) EnviarMensaje(telefono, mensaje string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.fallar {
+		return errors.New("servicio de WhatsApp no disponible")
+	}
+	w.enviados = append(w.enviados, telefono+": "+mensaje)
+	return nil
+}
+
+func TestNotificacionesPorCanal(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		correo := &correoDePrueba{}
+		whatsapp := &whatsAppMensajes{}
+		bus := eventos.New()
+		servicio := services.NewNotificacionService(services.DependenciasNotificaciones{
+			Notificaciones:            repos.Notificacion,
+			Usuarios:                  repos.Usuario,
+			Dudas:                     repos.Dudas,
+			Estudiantes:               repos.Estudiante,
+			EstudiantesUniversitarios: repos.EstudianteUniversitario,
+			Autoridades:               repos.AutoridadUTEQ,
+			Programas:                 repos.ProgramaVisita,
+			Correo:                    correo,
+			WhatsApp:                  whatsapp,
+			Eventos:                   bus,
+		})
+
+		persona := f.Persona(func(p *models.Persona) {
+			telefono := "0991234567"
+			p.Telefono = &telefono
+		})
+		usuario := f.Usuario(persona, e.TipoEstudiante, "clave123")
+		estudiante := f.Estudiante(persona, e.Institucion, e.Ciudad)
+		sub := bus.Suscribir(usuario.ID, false, 0)
+		duda := &models.Dudas{Pregunta: "¿A qué hora empieza la visita?", EstudianteID: estudiante.ID, Privacidad: "publico", FechaPregunta: time.Now()}
+		if err := repos.Dudas.CreateDudas(duda); err != nil {
+			t.Fatal(err)
+		}
+		respuesta := "A las ocho de la mañana"
+		duda.Respuesta = &respuesta
+		if err := repos.Dudas.UpdateDudas(duda); err != nil {
+			t.Fatal(err)
+		}
+
+		// Sin preferencias guardadas solo se notifica en la aplicación
+		if err := servicio.DudaRespondida(duda.ID); err != nil {
+			t.Fatal(err)
+		}
+		servicio.Esperar()
+		lista, err := servicio.GetNotificaciones(usuario.ID, true, 0)
+		if err != nil || len(lista) != 1 {
+			t.Fatalf("notificaciones = %+v, %v; se esperaba una", lista, err)
+		}
+		if n := lista[0]; n.Tipo != services.NotificacionDudaRespondida || n.Recurso != "dudas" || n.RecursoID != duda.ID || !strings.Contains(n.Mensaje, respuesta) {
+			t.Errorf("notificación inesperada: %+v", n)
+		}
+		select {
+		case ev := <-sub.C:
+			if ev.Tipo != eventos.NotificacionNueva {
+				t.Errorf("evento %q, se esperaba %q", ev.Tipo, eventos.NotificacionNueva)
+			}
+		default:
+			t.Error("no se publicó la notificación en el bus")
+		}
+		if len(correo.enviados) != 0 || len(whatsapp.enviados) != 0 {
+			t.Errorf("sin preferencias se envió correo %v o WhatsApp %v", correo.enviados, whatsapp.enviados)
+		}
+
+		// Solo correo y WhatsApp: no se guarda en la aplicación
+		if err := servicio.GuardarPreferencias(&models.PreferenciaNotificacion{UsuarioID: usuario.ID, Correo: true, WhatsApp: true}); err != nil {
+			t.Fatal(err)
+		}
+		if err := servicio.DudaRespondida(duda.ID); err != nil {
+			t.Fatal(err)
+		}
+		servicio.Esperar()
+		if total, _ := servicio.ContarNoLeidas(usuario.ID); total != 1 {
+			t.Errorf("no leídas = %d, la notificación por correo no debe guardarse en la aplicación", total)
+		}
+		if len(correo.enviados) != 1 || correo.enviados[0][0] != *persona.Correo || correo.asuntos[0] != "Tu duda fue respondida" {
+			t.Errorf("correos enviados: %v %v", correo.enviados, correo.asuntos)
+		}
+		if len(whatsapp.enviados) != 1 || !strings.HasPrefix(whatsapp.enviados[0], "0991234567: *Tu duda fue respondida*") {
+			t.Errorf("mensajes de WhatsApp: %v", whatsapp.enviados)
+		}
+
+		// Un canal caído no impide la notificación ni devuelve error
+		correo.fallar, whatsapp.fallar = true, true
+		if err := servicio.GuardarPreferencias(&models.PreferenciaNotificacion{UsuarioID: usuario.ID, App: true, Correo: true, WhatsApp: true}); err != nil {
+			t.Fatal(err)
+		}
+		if err := servicio.DudaRespondida(duda.ID); err != nil {
+			t.Fatalf("con los canales caídos DudaRespondida = %v", err)
+		}
+		servicio.Esperar()
+		if total, _ := servicio.ContarNoLeidas(usuario.ID); total != 2 {
+			t.Errorf("no leídas = %d, se esperaban 2", total)
+		}
+		preferencias, err := servicio.GetPreferencias(usuario.ID)
+		if err != nil || !preferencias.App || !preferencias.Correo || !preferencias.WhatsApp {
+			t.Errorf("preferencias = %+v, %v", preferencias, err)
+		}
+	})
+}
+
+func TestNotificacionComunicadoSoloEnLaAplicacion(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		correo := &correoDePrueba{}
+		servicio := services.NewNotificacionService(services.DependenciasNotificaciones{
+			Notificaciones: repos.Notificacion,
+			Usuarios:       repos.Usuario,
+			Estudiantes:    repos.Estudiante,
+			Correo:         correo,
+		})
+
+		destinatario := f.Persona()
+		usuario := f.Usuario(destinatario, e.TipoEstudiante, "clave123")
+		estudiante := f.Estudiante(destinatario, e.Institucion, e.Ciudad)
+		otro := f.Persona()
+		otroUsuario := f.Usuario(otro, e.TipoEstudiante, "clave123")
+		f.Estudiante(otro, e.Institucion, e.Ciudad)
+		if err := servicio.GuardarPreferencias(&models.PreferenciaNotificacion{UsuarioID: usuario.ID, App: true, Correo: true}); err != nil {
+			t.Fatal(err)
+		}
+
+		comunicado := f.Comunicado(e.Admin, func(c *models.Comunicado) { c.Asunto = "Cambio de horario" })
+		servicio.ComunicadoRecibido(comunicado, services.DestinatarioInfo{Tipo: "estudiantes", IDs: []uint{estudiante.ID}})
+		servicio.Esperar()
+		lista, _ := servicio.GetNotificaciones(usuario.ID, false, 0)
+		if len(lista) != 1 || lista[0].Mensaje != "Cambio de horario" || lista[0].RecursoID != comunicado.ID {
+			t.Errorf("notificaciones del destinatario: %+v", lista)
+		}
+		if otras, _ := servicio.GetNotificaciones(otroUsuario.ID, false, 0); len(otras) != 0 {
+			t.Errorf("un estudiante que no era destinatario recibió %+v", otras)
+		}
+		if len(correo.enviados) != 0 {
+			t.Errorf("el comunicado no debe reenviarse por correo: %v", correo.enviados)
+		}
+	})
+}
+
+func TestNotificacionComunicadoATodos(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+		bus := eventos.New()
+		servicio := services.NewNotificacionService(services.DependenciasNotificaciones{
+			Notificaciones: repos.Notificacion,
+			Usuarios:       repos.Usuario,
+			Estudiantes:    repos.Estudiante,
+			Eventos:        bus,
+		})
+
+		var usuarios []*models.Usuario
+		for i := 0; i < 3; i++ {
+			persona := f.Persona()
+			usuarios = append(usuarios, f.Usuario(persona, e.TipoEstudiante, "clave123"))
+			f.Estudiante(persona, e.Institucion, e.Ciudad)
+		}
+		// Un estudiante sin usuario no tiene a quién avisar
+		f.Estudiante(f.Persona(), e.Institucion, e.Ciudad)
+		// Quien desactivó la aplicación no recibe la notificación
+		if err := servicio.GuardarPreferencias(&models.PreferenciaNotificacion{UsuarioID: usuarios[2].ID, Correo: true}); err != nil {
+			t.Fatal(err)
+		}
+		sub := bus.Suscribir(usuarios[0].ID, false, 0)
+		defer sub.Cancelar()
+
+		comunicado := f.Comunicado(e.Admin, func(c *models.Comunicado) { c.Asunto = "Feria de ciencias" })
+		servicio.ComunicadoRecibido(comunicado, services.DestinatarioInfo{Tipo: "todos"})
+		servicio.Esperar()
+		for i, usuario := range usuarios {
+			lista, _ := servicio.GetNotificaciones(usuario.ID, false, 0)
+			want := 1
+			if i == 2 {
+				want = 0
+			}
+			if len(lista) != want {
+				t.Errorf("usuario %d: %d notificaciones, se esperaban %d", i, len(lista), want)
+			}
+		}
+		select {
+		case ev := <-sub.C:
+			if ev.Tipo != eventos.NotificacionNueva {
+				t.Errorf("evento %q, se esperaba %q", ev.Tipo, eventos.NotificacionNueva)
+			}
+		default:
+			t.Error("no se publicó la notificación en el bus")
+		}
+	})
+}
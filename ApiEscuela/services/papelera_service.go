@@ -0,0 +1,144 @@
+package services
+
+import (
+	"ApiEscuela/errores"
+	"ApiEscuela/repositories"
+	"errors"
+	"log"
+	"time"
+)
+
+// papeleraService lista, restaura y purga los registros eliminados lógicamente de todas las entidades
+type papeleraService struct {
+	papeleraRepo   repositories.PapeleraRepository
+	noticiaRepo    repositories.NoticiaRepository
+	comunicadoRepo repositories.ComunicadoRepository
+	media          MediaService
+	invalidar      func()
+}
+
+// NewPapeleraService crea una nueva instancia del servicio. invalidar se llama después de
+// restaurar o purgar para descartar los catálogos en caché; puede ser nil.
+func NewPapeleraService(
+	papeleraRepo repositories.PapeleraRepository,
+	noticiaRepo repositories.NoticiaRepository,
+	comunicadoRepo repositories.ComunicadoRepository,
+	media MediaService,
+	invalidar func(),
+) PapeleraService {
+	if invalidar == nil {
+		invalidar = func() {}
+	}
+	return &papeleraService{
+		papeleraRepo:   papeleraRepo,
+		noticiaRepo:    noticiaRepo,
+		comunicadoRepo: comunicadoRepo,
+		media:          media,
+		invalidar:      invalidar,
+	}
+}
+
+// ResumenPapelera es la cantidad de registros de una entidad que están en la papelera
+type ResumenPapelera struct {
+	Entidad  string `json:"entidad"`
+	Cantidad int64  `json:"cantidad"`
+}
+
+// ResultadoPurgaPapelera resume una purga automática de la papelera
+type ResultadoPurgaPapelera struct {
+	Purgados int `json:"purgados"`
+	// Omitidos son los registros vencidos que otros registros todavía usan
+	Omitidos int `json:"omitidos"`
+}
+
+// Resumen devuelve cuántos registros hay en la papelera de cada entidad
+func (s *papeleraService) Resumen() ([]ResumenPapelera, error) {
+	resumen := make([]ResumenPapelera, 0, len(repositories.EntidadesPapelera))
+	for _, e := range repositories.EntidadesPapelera {
+		n, err := s.papeleraRepo.ContarEliminados(e.Nombre)
+		if err != nil {
+			return nil, err
+		}
+		resumen = append(resumen, ResumenPapelera{Entidad: e.Nombre, Cantidad: n})
+	}
+	return resumen, nil
+}
+
+// GetEliminados lista los registros de la entidad que están en la papelera
+func (s *papeleraService) GetEliminados(entidad string) ([]repositories.ElementoPapelera, error) {
+	return s.papeleraRepo.GetEliminados(entidad)
+}
+
+// Restaurar restaura un registro de la papelera junto con los registros que dependen de él en
+// cascada. Las noticias y comunicados restaurados vuelven a marcar como usados sus archivos.
+func (s *papeleraService) Restaurar(entidad string, id uint) error {
+	if err := s.papeleraRepo.Restaurar(entidad, id); err != nil {
+		return err
+	}
+	s.invalidar()
+
+	if err := s.actualizarReferencias(entidad, id); err != nil {
+		// El registro ya está restaurado; la próxima recolección de archivos reconstruye las referencias
+		log.Printf("No se pudieron actualizar las referencias de archivos de %s %d: %v", entidad, id, err)
+	}
+	return nil
+}
+
+// actualizarReferencias registra los archivos que usa una noticia o comunicado restaurado
+func (s *papeleraService) actualizarReferencias(entidad string, id uint) error {
+	switch entidad {
+	case "noticias":
+		noticia, err := s.noticiaRepo.GetNoticiaByID(id)
+		if err != nil {
+			return err
+		}
+		return s.media.ActualizarReferencias(EntidadNoticia, id, ClavesNoticia(noticia))
+	case "comunicados":
+		comunicado, err := s.comunicadoRepo.GetComunicadoByID(id)
+		if err != nil {
+			return err
+		}
+		return s.media.ActualizarReferencias(EntidadComunicado, id, ClavesComunicado(comunicado))
+	}
+	return nil
+}
+
+// Purgar elimina definitivamente un registro de la papelera
+func (s *papeleraService) Purgar(entidad string, id uint) error {
+	if err := s.papeleraRepo.Purgar(entidad, id); err != nil {
+		return err
+	}
+	s.invalidar()
+	return nil
+}
+
+// PurgarVencidos purga los registros eliminados antes de la fecha. Las entidades se recorren de
+// las dependientes a las independientes, de modo que un registro cuyos dependientes también
+// vencieron se purga en la misma pasada. Los que otros registros aún usan, según las dependencias
+// de la papelera o una clave foránea de la base de datos, se conservan y la purga continúa.
+func (s *papeleraService) PurgarVencidos(antes time.Time) (*ResultadoPurgaPapelera, error) {
+	resultado := &ResultadoPurgaPapelera{}
+	defer func() {
+		if resultado.Purgados > 0 {
+			s.invalidar()
+		}
+	}()
+	for _, e := range repositories.EntidadesPapelera {
+		ids, err := s.papeleraRepo.GetEliminadosAntes(e.Nombre, antes)
+		if err != nil {
+			return resultado, err
+		}
+		for _, id := range ids {
+			err := s.papeleraRepo.Purgar(e.Nombre, id)
+			switch {
+			case errors.Is(err, errores.ErrConflicto):
+				resultado.Omitidos++
+			case err != nil:
+				return resultado, err
+			default:
+				resultado.Purgados++
+			}
+		}
+	}
+	return resultado, nil
+}
@@ -0,0 +1,63 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"ApiEscuela/errores"
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+	"ApiEscuela/services"
+	"ApiEscuela/testutil"
+)
+
+// papeleraConClaveForanea simula la base de datos rechazando la purga de un registro por una
+// clave foránea que las dependencias de la papelera no conocen
+type papeleraConClaveForanea struct {
+	repositories.PapeleraRepository
+	entidad string
+	id      uint
+}
+
+func (p papeleraConClaveForanea) Purgar(entidad string, id uint) error {
+	if entidad == p.entidad && id == p.id {
+		return errores.Conflicto(errores.CodigoReferenciaInvalida, "El registro está relacionado con otros registros")
+	}
+	return p.PapeleraRepository.Purgar(entidad, id)
+}
+
+func TestPurgarVencidosConservaLosUsados(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+
+		// Un usuario que subió archivos, otro que la base de datos no deja purgar y uno libre
+		conMedia := f.Usuario(f.Persona(), e.TipoEstudiante, "secreto1")
+		if err := repos.Media.SaveMedia(&models.Media{Clave: "subidas/foto.jpg", UsuarioID: &conMedia.ID}); err != nil {
+			t.Fatal(err)
+		}
+		bloqueado := f.Usuario(f.Persona(), e.TipoEstudiante, "secreto1")
+		libre := f.Usuario(f.Persona(), e.TipoEstudiante, "secreto1")
+		for _, u := range []*models.Usuario{conMedia, bloqueado, libre} {
+			if err := repos.Usuario.DeleteUsuario(u.ID); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		papelera := services.NewPapeleraService(papeleraConClaveForanea{repos.Papelera, "usuarios", bloqueado.ID},
+			repos.Noticia, repos.Comunicado, nil, nil)
+		resultado, err := papelera.PurgarVencidos(time.Now().Add(time.Minute))
+		if err != nil {
+			t.Fatalf("la purga se detuvo: %v", err)
+		}
+		if resultado.Purgados != 1 || resultado.Omitidos != 2 {
+			t.Errorf("resultado = %+v, se esperaba 1 purgado y 2 conservados", resultado)
+		}
+		if err := repos.Papelera.Restaurar("usuarios", conMedia.ID); err != nil {
+			t.Errorf("el usuario con archivos no se conservó: %v", err)
+		}
+		if err := repos.Papelera.Restaurar("usuarios", libre.ID); err == nil {
+			t.Error("el usuario sin dependencias no se purgó")
+		}
+	})
+}
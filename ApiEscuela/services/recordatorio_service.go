@@ -0,0 +1,118 @@
+package services
+
+import (
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+)
+
+// recordatorioService envía por correo los recordatorios de las visitas próximas
+type recordatorioService struct {
+	programaRepo  repositories.ProgramaVisitaRepository
+	detalleRepo   repositories.DetalleAutoridadDetallesVisitaRepository
+	autoridadRepo repositories.AutoridadUTEQRepository
+	correo        ComunicadoService
+}
+
+// NewRecordatorioService crea una nueva instancia del servicio; los correos se envían con la
+// configuración SMTP de los comunicados
+func NewRecordatorioService(
+	programaRepo repositories.ProgramaVisitaRepository,
+	detalleRepo repositories.DetalleAutoridadDetallesVisitaRepository,
+	autoridadRepo repositories.AutoridadUTEQRepository,
+	correo ComunicadoService,
+) RecordatorioService {
+	return &recordatorioService{
+		programaRepo:  programaRepo,
+		detalleRepo:   detalleRepo,
+		autoridadRepo: autoridadRepo,
+		correo:        correo,
+	}
+}
+
+// ResultadoRecordatorios resume un envío de recordatorios
+type ResultadoRecordatorios struct {
+	Enviados        int      `json:"enviados"`
+	SinDestinatario int      `json:"sin_destinatario"` // programas sin correos conocidos; no se vuelven a intentar
+	Errores         []string `json:"errores"`
+}
+
+// EnviarRecordatoriosVisitas avisa a la institución y a las autoridades asignadas de cada
+// programa de visita que empieza dentro de la anticipación indicada. Cada programa se recuerda
+// una sola vez; los que fallan se reintentan en la siguiente ejecución.
+func (s *recordatorioService) EnviarRecordatoriosVisitas(anticipacion time.Duration) (*ResultadoRecordatorios, error) {
+	ahora := time.Now()
+	programas, err := s.programaRepo.GetProgramasSinRecordatorio(ahora, ahora.Add(anticipacion))
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener los programas de visita: %v", err)
+	}
+
+	result := &ResultadoRecordatorios{Errores: []string{}}
+	for _, programa := range programas {
+		correos, err := s.destinatarios(&programa)
+		if err != nil {
+			result.Errores = append(result.Errores, fmt.Sprintf("programa %d: %v", programa.ID, err))
+			continue
+		}
+		if len(correos) > 0 {
+			asunto, cuerpo := mensajeRecordatorio(&programa)
+			if err := s.correo.SendEmailWithAttachments(correos, asunto, cuerpo, nil); err != nil {
+				result.Errores = append(result.Errores, fmt.Sprintf("programa %d: %v", programa.ID, err))
+				continue
+			}
+			result.Enviados++
+		} else {
+			result.SinDestinatario++
+		}
+		if err := s.programaRepo.MarcarRecordatorioEnviado(programa.ID, time.Now()); err != nil {
+			result.Errores = append(result.Errores, fmt.Sprintf("programa %d: %v", programa.ID, err))
+		}
+	}
+	return result, nil
+}
+
+// destinatarios reúne el correo de la institución y los de las autoridades asignadas, sin repetir
+func (s *recordatorioService) destinatarios(programa *models.ProgramaVisita) ([]string, error) {
+	vistos := map[string]bool{}
+	var correos []string
+	agregar := func(correo string) {
+		correo = strings.TrimSpace(correo)
+		if correo != "" && !vistos[strings.ToLower(correo)] {
+			vistos[strings.ToLower(correo)] = true
+			correos = append(correos, correo)
+		}
+	}
+
+	agregar(programa.Institucion.Correo)
+	detalles, err := s.detalleRepo.GetDetallesByProgramaVisitaID(programa.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error al obtener las autoridades asignadas: %v", err)
+	}
+	for _, detalle := range detalles {
+		autoridad, err := s.autoridadRepo.GetAutoridadUTEQByID(detalle.AutoridadUTEQID)
+		if err != nil {
+			continue
+		}
+		if autoridad.Persona.Correo != nil {
+			agregar(*autoridad.Persona.Correo)
+		}
+	}
+	return correos, nil
+}
+
+// mensajeRecordatorio arma el asunto y el cuerpo HTML del recordatorio
+func mensajeRecordatorio(programa *models.ProgramaVisita) (string, string) {
+	fecha := programa.Fecha.Format("02/01/2006 15:04")
+	institucion := programa.Institucion.Nombre
+	if institucion == "" {
+		institucion = fmt.Sprintf("la institución %d", programa.InstitucionID)
+	}
+	asunto := fmt.Sprintf("Recordatorio: visita del %s", fecha)
+	cuerpo := fmt.Sprintf("<p>Le recordamos que la visita de <strong>%s</strong> a la UTEQ está programada para el <strong>%s</strong>.</p>"+
+		"<p>Este es un mensaje automático; por favor no responda a este correo.</p>",
+		html.EscapeString(institucion), fecha)
+	return asunto, cuerpo
+}
@@ -0,0 +1,117 @@
+package services_test
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"ApiEscuela/models"
+	"ApiEscuela/services"
+	"ApiEscuela/testutil"
+)
+
+// correoDePrueba registra los correos en lugar de enviarlos
+type correoDePrueba struct {
+	services.ComunicadoService
+	enviados [][]string
+	asuntos  []string
+	fallar   bool
+}
+
+func (c *correoDePrueba) SendEmailWithAttachments(recipients []string, subject, htmlBody string, attachments []services.Attachment) error {
+	if c.fallar {
+		return errors.New("configuración SMTP incompleta")
+	}
+	c.enviados = append(c.enviados, recipients)
+	c.asuntos = append(c.asuntos, subject)
+	return nil
+}
+
+func TestRecordatoriosVisitas(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		f := testutil.NewFixtures(t, repos)
+		e := f.Escenario()
+
+		programa := func(institucion *models.Institucion, fecha time.Time) *models.ProgramaVisita {
+			t.Helper()
+			p := &models.ProgramaVisita{Fecha: fecha, InstitucionID: institucion.ID}
+			if err := repos.ProgramaVisita.CreateProgramaVisita(p); err != nil {
+				t.Fatal(err)
+			}
+			return p
+		}
+		manana := programa(e.Institucion, time.Now().Add(20*time.Hour))
+		programa(e.Institucion, time.Now().Add(72*time.Hour)) // fuera de la anticipación
+		programa(e.Institucion, time.Now().Add(-2*time.Hour)) // ya pasó
+		sinCorreo := programa(f.Institucion(func(i *models.Institucion) { i.Correo = "" }), time.Now().Add(10*time.Hour))
+
+		// Una autoridad asignada recibe copia; el correo repetido de la institución no se duplica
+		autoridad := &models.AutoridadUTEQ{PersonaID: f.Persona().ID, Cargo: "Decano"}
+		if err := repos.AutoridadUTEQ.CreateAutoridadUTEQ(autoridad); err != nil {
+			t.Fatal(err)
+		}
+		for _, id := range []uint{autoridad.ID, autoridad.ID} {
+			if err := repos.DetalleAutoridadDetallesVisita.CreateDetalleAutoridadDetallesVisita(&models.DetalleAutoridadDetallesVisita{
+				ProgramaVisitaID: manana.ID,
+				AutoridadUTEQID:  id,
+			}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		correo := &correoDePrueba{fallar: true}
+		recordatorios := services.NewRecordatorioService(repos.ProgramaVisita, repos.DetalleAutoridadDetallesVisita, repos.AutoridadUTEQ, correo)
+
+		// Si el correo falla, el programa queda pendiente para el siguiente intento
+		result, err := recordatorios.EnviarRecordatoriosVisitas(24 * time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Enviados != 0 || result.SinDestinatario != 1 || len(result.Errores) != 1 {
+			t.Fatalf("resultado con fallo inesperado: %+v", result)
+		}
+
+		correo.fallar = false
+		result, err = recordatorios.EnviarRecordatoriosVisitas(24 * time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Enviados != 1 || result.SinDestinatario != 0 || len(result.Errores) != 0 {
+			t.Fatalf("resultado inesperado: %+v", result)
+		}
+		persona, err := repos.Persona.GetPersonaByID(autoridad.PersonaID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		destinatarios := append([]string(nil), correo.enviados[0]...)
+		sort.Strings(destinatarios)
+		esperados := []string{e.Institucion.Correo, *persona.Correo}
+		sort.Strings(esperados)
+		if strings.Join(destinatarios, ",") != strings.Join(esperados, ",") {
+			t.Errorf("destinatarios = %v, se esperaban %v", destinatarios, esperados)
+		}
+		if !strings.HasPrefix(correo.asuntos[0], "Recordatorio: visita del ") {
+			t.Errorf("asunto inesperado: %q", correo.asuntos[0])
+		}
+
+		// Cada programa se recuerda una sola vez
+		result, err = recordatorios.EnviarRecordatoriosVisitas(24 * time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.Enviados != 0 || len(correo.enviados) != 1 {
+			t.Errorf("el recordatorio se repitió: %+v", result)
+		}
+		for _, id := range []uint{manana.ID, sinCorreo.ID} {
+			p, err := repos.ProgramaVisita.GetProgramaVisitaByID(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.RecordatorioEnviadoEn == nil {
+				t.Errorf("programa %d sin recordatorio registrado", id)
+			}
+		}
+	})
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"ApiEscuela/models"
+	"ApiEscuela/storage"
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"sort"
+)
+
+// Entidades que pueden usar archivos subidos
+const (
+	EntidadNoticia    = "noticia"
+	EntidadComunicado = "comunicado"
+)
+
+// referenciaArchivo encuentra URLs /api/files/... dentro de texto o JSON
+var referenciaArchivo = regexp.MustCompile(`/api/files/[^\s"'<>()\\]+`)
+
+// ReferenciaArchivo indica que una noticia o un comunicado usa el archivo con la clave indicada
+type ReferenciaArchivo struct {
+	Entidad   string
+	EntidadID uint
+	Clave     string
+}
+
+// clavesUsadas reúne las claves de los archivos que aparecen en un registro
+type clavesUsadas map[string]bool
+
+// agregar agrega la clave de una URL /api/files/...
+func (c clavesUsadas) agregar(ref string) {
+	if clave, ok := storage.ClaveDesdeURL(ref); ok {
+		c[clave] = true
+	}
+	// El frontend puede guardar la URL codificada (espacios como %20)
+	if decodificada, err := url.PathUnescape(ref); err == nil {
+		if clave, ok := storage.ClaveDesdeURL(decodificada); ok {
+			c[clave] = true
+		}
+	}
+}
+
+// agregarDeTexto agrega las URLs que aparecen en un texto; en HTML terminan en comillas o espacios
+func (c clavesUsadas) agregarDeTexto(texto string) {
+	for _, ref := range referenciaArchivo.FindAllString(texto, -1) {
+		c.agregar(ref)
+	}
+}
+
+// ordenadas devuelve las claves en orden alfabético
+func (c clavesUsadas) ordenadas() []string {
+	claves := make([]string, 0, len(c))
+	for clave := range c {
+		claves = append(claves, clave)
+	}
+	sort.Strings(claves)
+	return claves
+}
+
+// ClavesNoticia devuelve las claves de los archivos que usa una noticia: su URL y las imágenes
+// insertadas en la descripción
+func ClavesNoticia(n *models.Noticia) []string {
+	claves := clavesUsadas{}
+	claves.agregar(n.URLNoticia)
+	claves.agregarDeTexto(n.Descripcion)
+	return claves.ordenadas()
+}
+
+// ClavesComunicado devuelve las claves de los archivos que usa un comunicado: sus adjuntos y
+// las imágenes insertadas en el mensaje
+func ClavesComunicado(c *models.Comunicado) []string {
+	claves := clavesUsadas{}
+	// Los adjuntos son un arreglo JSON de URLs cuyos nombres pueden tener espacios
+	var adjuntos []string
+	if err := json.Unmarshal([]byte(c.Adjuntos), &adjuntos); err == nil {
+		for _, adjunto := range adjuntos {
+			claves.agregar(adjunto)
+		}
+	} else {
+		claves.agregarDeTexto(c.Adjuntos)
+	}
+	claves.agregarDeTexto(c.Mensaje)
+	return claves.ordenadas()
+}
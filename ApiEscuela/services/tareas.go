@@ -0,0 +1,159 @@
+package services
+
+import (
+	"ApiEscuela/repositories"
+	"ApiEscuela/tareas"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Nombres de las tareas programadas
+const (
+	TareaExpirarCodigos       = "expirar-codigos"
+	TareaPurgarSubidas        = "purgar-subidas"
+	TareaRecolectarArchivos   = "recolectar-archivos"
+	TareaRecordatoriosVisitas = "recordatorios-visitas"
+	TareaPurgarHistorial      = "purgar-historial"
+	TareaPurgarPapelera       = "purgar-papelera"
+	TareaReintentarWebhooks   = "reintentar-webhooks"
+	TareaRefrescarAnalitica   = "refrescar-analitica"
+)
+
+// ConfigTareas contiene la programación de las tareas de mantenimiento. Una programación
+// vacía u "off" desactiva la tarea.
+type ConfigTareas struct {
+	ExpirarCodigos            string
+	PurgarSubidas             string
+	RecolectarArchivos        string
+	GraciaArchivos            time.Duration // antigüedad mínima de un archivo sin uso para eliminarlo
+	RecordatoriosVisitas      string
+	AnticipacionRecordatorios time.Duration // cuánto antes de la visita se envía el recordatorio
+	RetencionHistorial        time.Duration // 0 conserva el historial de ejecuciones
+	PurgarPapelera            string
+	RetencionPapelera         time.Duration // 0 desactiva la purga automática de la papelera
+	ReintentarWebhooks        string
+	RefrescarAnalitica        string
+}
+
+// DependenciasTareas son los repositorios y servicios que usan las tareas
+type DependenciasTareas struct {
+	Codigos       repositories.CodigoUsuarioRepository
+	Historial     repositories.EjecucionTareaRepository
+	Subidas       UploadService
+	Archivos      ArchivoService
+	Recordatorios RecordatorioService
+	Papelera      PapeleraService
+	Webhooks      WebhookService
+	Analitica     AnaliticaService
+}
+
+// TareasProgramadas devuelve las tareas de mantenimiento y notificación activas según la configuración
+func TareasProgramadas(cfg ConfigTareas, d DependenciasTareas) []tareas.Tarea {
+	todas := []tareas.Tarea{
+		{
+			Nombre:       TareaExpirarCodigos,
+			Descripcion:  "Marca como expirados los códigos de recuperación vencidos",
+			Programacion: cfg.ExpirarCodigos,
+			Ejecutar: func(context.Context) (string, error) {
+				n, err := d.Codigos.ExpirarVencidos(time.Now())
+				return fmt.Sprintf("%d códigos expirados", n), err
+			},
+		},
+		{
+			Nombre:       TareaPurgarSubidas,
+			Descripcion:  "Descarta las subidas por partes abandonadas",
+			Programacion: cfg.PurgarSubidas,
+			Ejecutar: func(context.Context) (string, error) {
+				n, err := d.Subidas.PurgeExpiredUploads()
+				return fmt.Sprintf("%d subidas vencidas eliminadas", n), err
+			},
+		},
+		{
+			Nombre:       TareaRecolectarArchivos,
+			Descripcion:  "Elimina los archivos subidos que ninguna noticia o comunicado usa",
+			Programacion: cfg.RecolectarArchivos,
+			Ejecutar: func(context.Context) (string, error) {
+				huerfanos, err := d.Archivos.FindOrphanFiles(time.Now().Add(-cfg.GraciaArchivos))
+				if err != nil || len(huerfanos) == 0 {
+					return "0 archivos huérfanos eliminados", err
+				}
+				n, err := d.Archivos.RemoveOrphanFiles(huerfanos)
+				return fmt.Sprintf("%d archivos huérfanos eliminados", n), err
+			},
+		},
+		{
+			Nombre:       TareaRecordatoriosVisitas,
+			Descripcion:  "Envía por correo el recordatorio de las visitas próximas",
+			Programacion: cfg.RecordatoriosVisitas,
+			Ejecutar: func(context.Context) (string, error) {
+				result, err := d.Recordatorios.EnviarRecordatoriosVisitas(cfg.AnticipacionRecordatorios)
+				if err != nil {
+					return "", err
+				}
+				resumen := fmt.Sprintf("%d recordatorios enviados, %d programas sin destinatarios", result.Enviados, result.SinDestinatario)
+				if len(result.Errores) > 0 {
+					return resumen, fmt.Errorf("%d recordatorios fallidos: %s", len(result.Errores), strings.Join(result.Errores, "; "))
+				}
+				return resumen, nil
+			},
+		},
+		{
+			Nombre:       TareaReintentarWebhooks,
+			Descripcion:  "Reintenta las entregas de webhooks fallidas cuyo próximo intento ya venció",
+			Programacion: cfg.ReintentarWebhooks,
+			Ejecutar: func(context.Context) (string, error) {
+				result, err := d.Webhooks.ReintentarPendientes(time.Now())
+				if result == nil {
+					return "", err
+				}
+				return fmt.Sprintf("%d entregas realizadas, %d reprogramadas, %d fallidas", result.Entregadas, result.Reprogramadas, result.Fallidas), err
+			},
+		},
+		{
+			Nombre:       TareaRefrescarAnalitica,
+			Descripcion:  "Recalcula el panel de analítica de los últimos doce meses para que el dashboard cargue desde la caché",
+			Programacion: cfg.RefrescarAnalitica,
+			Ejecutar: func(context.Context) (string, error) {
+				if err := d.Analitica.Refrescar(); err != nil {
+					return "", err
+				}
+				return "panel de analítica recalculado", nil
+			},
+		},
+	}
+	if cfg.RetencionHistorial > 0 {
+		todas = append(todas, tareas.Tarea{
+			Nombre:       TareaPurgarHistorial,
+			Descripcion:  "Elimina las ejecuciones antiguas del historial de tareas",
+			Programacion: "@daily",
+			Ejecutar: func(context.Context) (string, error) {
+				n, err := d.Historial.PurgarEjecuciones(time.Now().Add(-cfg.RetencionHistorial))
+				return fmt.Sprintf("%d ejecuciones eliminadas", n), err
+			},
+		})
+	}
+	if cfg.RetencionPapelera > 0 {
+		todas = append(todas, tareas.Tarea{
+			Nombre:       TareaPurgarPapelera,
+			Descripcion:  "Elimina definitivamente los registros que llevan más tiempo en la papelera que la retención",
+			Programacion: cfg.PurgarPapelera,
+			Ejecutar: func(context.Context) (string, error) {
+				result, err := d.Papelera.PurgarVencidos(time.Now().Add(-cfg.RetencionPapelera))
+				if result == nil {
+					return "", err
+				}
+				return fmt.Sprintf("%d registros purgados, %d conservados por dependencias", result.Purgados, result.Omitidos), err
+			},
+		})
+	}
+
+	activas := make([]tareas.Tarea, 0, len(todas))
+	for _, t := range todas {
+		if p := strings.TrimSpace(t.Programacion); p != "" && p != "off" {
+			activas = append(activas, t)
+		}
+	}
+	return activas
+}
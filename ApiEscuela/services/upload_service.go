@@ -0,0 +1,421 @@
+package services
+
+import (
+	"ApiEscuela/inspeccion"
+	"ApiEscuela/models"
+	"ApiEscuela/storage"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Límites de las subidas por partes
+const (
+	TamanoMaximoFragmento    = 2 * 1024 * 1024 // tamaño máximo de cada fragmento
+	VigenciaSubidaPorDefecto = 24 * time.Hour  // tiempo sin actividad tras el cual se descarta una subida
+)
+
+// Errores de las subidas por partes
+var (
+	ErrSubidaNoEncontrada = errors.New("subida no encontrada o vencida")
+	ErrSubidaIncompleta   = errors.New("la subida no recibió todos los bytes")
+	ErrChecksumInvalido   = errors.New("el checksum no coincide con el contenido recibido")
+	ErrFragmentoExcedido  = errors.New("el fragmento supera el tamaño declarado de la subida")
+)
+
+// ErrOffsetInvalido indica que el fragmento no empieza donde terminó el anterior
+type ErrOffsetInvalido struct {
+	Esperado int64
+}
+
+func (e *ErrOffsetInvalido) Error() string {
+	return fmt.Sprintf("el fragmento debe empezar en el byte %d", e.Esperado)
+}
+
+// NuevaSubida son los datos para iniciar una subida por partes
+type NuevaSubida struct {
+	Nombre string `json:"nombre"`
+	Tamano int64  `json:"tamano"`
+	Sha256 string `json:"sha256,omitempty"` // hex del archivo completo; se verifica al finalizar
+	// Visibilidad del archivo en la biblioteca: publico (por defecto) o privado
+	Visibilidad string `json:"visibilidad,omitempty"`
+}
+
+// UploadSession es el estado de una subida por partes
+type UploadSession struct {
+	ID          string    `json:"id"`
+	UsuarioID   uint      `json:"usuario_id"`
+	Nombre      string    `json:"nombre"`
+	ContentType string    `json:"content_type"`
+	Tipo        string    `json:"tipo"`
+	Tamano      int64     `json:"tamano"`
+	Offset      int64     `json:"offset"`
+	Sha256      string    `json:"sha256,omitempty"`
+	Visibilidad string    `json:"visibilidad"`
+	Creado      time.Time `json:"creado"`
+	ExpiraEn    time.Time `json:"expira_en"`
+}
+
+// ArchivoSubido es el resultado de finalizar una subida
+type ArchivoSubido struct {
+	Clave     string
+	Tipo      string
+	Tamano    int64
+	Sha256    string
+	Duplicado bool          // ya existía un archivo idéntico y se devolvió ese
+	Media     *models.Media // nil si no se pudo registrar en la biblioteca
+}
+
+// uploadService guarda las subidas por partes en una carpeta temporal hasta que se finalizan.
+// El estado vive en disco, así que una subida puede continuar después de reiniciar el servidor.
+type uploadService struct {
+	dir       string
+	vigencia  time.Duration
+	archivos  storage.Storage
+	inspector *inspeccion.Inspector
+	media     MediaService
+	now       func() time.Time
+
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// NewUploadService crea el servicio; dir es la carpeta temporal de las subidas en curso y
+// vigencia el tiempo sin actividad tras el cual se descartan. Al finalizar, el inspector verifica
+// el contenido de cada archivo y media lo registra en la biblioteca.
+func NewUploadService(dir string, vigencia time.Duration, archivos storage.Storage, inspector *inspeccion.Inspector, media MediaService) UploadService {
+	if vigencia <= 0 {
+		vigencia = VigenciaSubidaPorDefecto
+	}
+	return &uploadService{dir: dir, vigencia: vigencia, archivos: archivos, inspector: inspector, media: media, now: time.Now, locks: map[string]*sync.Mutex{}}
+}
+
+// CreateUpload valida el archivo e inicia una subida vacía
+func (s *uploadService) CreateUpload(usuarioID uint, datos NuevaSubida) (*UploadSession, error) {
+	if datos.Tamano <= 0 {
+		return nil, fmt.Errorf("el tamaño del archivo es requerido")
+	}
+	tipo, err := inspeccion.Validar(datos.Nombre, datos.Tamano)
+	if err != nil {
+		return nil, err
+	}
+	if datos.Sha256 != "" {
+		if b, err := hex.DecodeString(datos.Sha256); err != nil || len(b) != sha256.Size {
+			return nil, fmt.Errorf("sha256 debe ser el hash hexadecimal del archivo")
+		}
+	}
+	visibilidad, err := ValidarVisibilidad(datos.Visibilidad)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := nuevoIDSubida()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(s.rutaDatos(id), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	f.Close()
+
+	ahora := s.now()
+	estado := &UploadSession{
+		ID:          id,
+		UsuarioID:   usuarioID,
+		Nombre:      filepath.Base(datos.Nombre),
+		ContentType: tipo.ContentType,
+		Tipo:        tipo.Categoria,
+		Tamano:      datos.Tamano,
+		Sha256:      strings.ToLower(datos.Sha256),
+		Visibilidad: visibilidad,
+		Creado:      ahora,
+		ExpiraEn:    ahora.Add(s.vigencia),
+	}
+	if err := s.guardarEstado(estado); err != nil {
+		os.Remove(s.rutaDatos(id))
+		return nil, err
+	}
+	return estado, nil
+}
+
+// GetUpload devuelve el estado de una subida del usuario
+func (s *uploadService) GetUpload(usuarioID uint, id string) (*UploadSession, error) {
+	estado, err := s.leerEstado(usuarioID, id)
+	if err != nil {
+		return nil, err
+	}
+	return estado, nil
+}
+
+// WriteChunk agrega un fragmento en la posición offset. checksum es opcional, con el formato
+// "sha256 <base64>" del encabezado Upload-Checksum de tus.
+func (s *uploadService) WriteChunk(usuarioID uint, id string, offset int64, datos []byte, checksum string) (*UploadSession, error) {
+	unlock := s.bloquear(id)
+	defer unlock()
+
+	estado, err := s.leerEstado(usuarioID, id)
+	if err != nil {
+		return nil, err
+	}
+	if offset != estado.Offset {
+		return nil, &ErrOffsetInvalido{Esperado: estado.Offset}
+	}
+	if int64(len(datos)) > TamanoMaximoFragmento {
+		return nil, fmt.Errorf("el fragmento supera el máximo de %d bytes", TamanoMaximoFragmento)
+	}
+	if offset+int64(len(datos)) > estado.Tamano {
+		return nil, ErrFragmentoExcedido
+	}
+	if checksum != "" {
+		if err := verificarChecksum(checksum, datos); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := os.OpenFile(s.rutaDatos(id), os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.WriteAt(datos, offset); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+
+	estado.Offset += int64(len(datos))
+	estado.ExpiraEn = s.now().Add(s.vigencia)
+	if err := s.guardarEstado(estado); err != nil {
+		return nil, err
+	}
+	return estado, nil
+}
+
+// FinalizeUpload verifica que la subida esté completa (y su sha256, si se declaró), inspecciona
+// el contenido y mueve el archivo al almacenamiento. Si el archivo se rechaza la subida se descarta;
+// si falla el escáner se conserva para reintentar.
+func (s *uploadService) FinalizeUpload(usuarioID uint, id string) (*ArchivoSubido, error) {
+	unlock := s.bloquear(id)
+	defer unlock()
+
+	estado, err := s.leerEstado(usuarioID, id)
+	if err != nil {
+		return nil, err
+	}
+	if estado.Offset != estado.Tamano {
+		return nil, ErrSubidaIncompleta
+	}
+
+	f, err := os.Open(s.rutaDatos(id))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	suma := hex.EncodeToString(h.Sum(nil))
+	if estado.Sha256 != "" && suma != estado.Sha256 {
+		return nil, ErrChecksumInvalido
+	}
+
+	ctx := context.Background()
+	duplicado, err := s.media.BuscarDuplicado(ctx, suma, estado.Visibilidad)
+	if err != nil {
+		return nil, err
+	}
+	if duplicado != nil {
+		f.Close()
+		s.eliminar(id)
+		return &ArchivoSubido{Clave: duplicado.Clave, Tipo: duplicado.Categoria, Tamano: duplicado.Tamano, Sha256: suma, Duplicado: true, Media: duplicado}, nil
+	}
+
+	archivo, err := s.inspector.Preparar(ctx, estado.Nombre, f, estado.Tamano)
+	if err != nil {
+		if inspeccion.EsRechazo(err) {
+			f.Close()
+			s.eliminar(id)
+		}
+		return nil, err
+	}
+
+	clave := fmt.Sprintf("%s/%d_%s%s", archivo.Tipo.Carpeta, s.now().Unix(), id[:8], strings.ToLower(filepath.Ext(estado.Nombre)))
+	if err := s.archivos.Guardar(ctx, clave, archivo.Contenido, archivo.Tamano, archivo.Tipo.ContentType); err != nil {
+		return nil, err
+	}
+	f.Close()
+	s.eliminar(id)
+
+	subido := &ArchivoSubido{Clave: clave, Tipo: archivo.Tipo.Categoria, Tamano: archivo.Tamano, Sha256: suma}
+	// Sin registro el archivo se sirve igual; files index lo agrega a la biblioteca
+	subido.Media, err = s.media.RegistrarArchivo(ctx, NuevoMedia{
+		Clave:       clave,
+		Nombre:      estado.Nombre,
+		Categoria:   archivo.Tipo.Categoria,
+		ContentType: archivo.Tipo.ContentType,
+		Tamano:      archivo.Tamano,
+		Sha256:      suma,
+		UsuarioID:   estado.UsuarioID,
+		Visibilidad: estado.Visibilidad,
+		Datos:       archivo.Datos,
+	})
+	if err != nil {
+		log.Printf("Error al registrar %s en la biblioteca: %v", clave, err)
+	}
+	return subido, nil
+}
+
+// CancelUpload descarta una subida del usuario
+func (s *uploadService) CancelUpload(usuarioID uint, id string) error {
+	unlock := s.bloquear(id)
+	defer unlock()
+
+	if _, err := s.leerEstado(usuarioID, id); err != nil {
+		return err
+	}
+	s.eliminar(id)
+	return nil
+}
+
+// PurgeExpiredUploads elimina las subidas sin actividad durante la vigencia configurada
+func (s *uploadService) PurgeExpiredUploads() (int, error) {
+	entradas, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	eliminadas := 0
+	for _, e := range entradas {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || !idSubidaValido(id) {
+			continue
+		}
+		estado, err := s.leerArchivoEstado(id)
+		if err != nil || s.now().After(estado.ExpiraEn) {
+			s.eliminar(id)
+			eliminadas++
+		}
+	}
+	return eliminadas, nil
+}
+
+// bloquear serializa las operaciones sobre una misma subida
+func (s *uploadService) bloquear(id string) func() {
+	s.mu.Lock()
+	l, ok := s.locks[id]
+	if !ok {
+		l = &sync.Mutex{}
+		s.locks[id] = l
+	}
+	s.mu.Unlock()
+	l.Lock()
+	return l.Unlock
+}
+
+// leerEstado carga el estado de una subida vigente que pertenece al usuario
+func (s *uploadService) leerEstado(usuarioID uint, id string) (*UploadSession, error) {
+	if !idSubidaValido(id) {
+		return nil, ErrSubidaNoEncontrada
+	}
+	estado, err := s.leerArchivoEstado(id)
+	if err != nil {
+		return nil, ErrSubidaNoEncontrada
+	}
+	// Una subida de otro usuario se informa como inexistente para no revelar los IDs
+	if estado.UsuarioID != usuarioID || s.now().After(estado.ExpiraEn) {
+		return nil, ErrSubidaNoEncontrada
+	}
+	return estado, nil
+}
+
+func (s *uploadService) leerArchivoEstado(id string) (*UploadSession, error) {
+	datos, err := os.ReadFile(s.rutaEstado(id))
+	if err != nil {
+		return nil, err
+	}
+	var estado UploadSession
+	if err := json.Unmarshal(datos, &estado); err != nil {
+		return nil, err
+	}
+	return &estado, nil
+}
+
+// guardarEstado escribe el estado en un temporal y lo renombra para no dejarlo a medias
+func (s *uploadService) guardarEstado(estado *UploadSession) error {
+	datos, err := json.Marshal(estado)
+	if err != nil {
+		return err
+	}
+	tmp := s.rutaEstado(estado.ID) + ".tmp"
+	if err := os.WriteFile(tmp, datos, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.rutaEstado(estado.ID))
+}
+
+func (s *uploadService) eliminar(id string) {
+	os.Remove(s.rutaDatos(id))
+	os.Remove(s.rutaEstado(id))
+	s.mu.Lock()
+	delete(s.locks, id)
+	s.mu.Unlock()
+}
+
+func (s *uploadService) rutaDatos(id string) string  { return filepath.Join(s.dir, id+".part") }
+func (s *uploadService) rutaEstado(id string) string { return filepath.Join(s.dir, id+".json") }
+
+// nuevoIDSubida genera un identificador aleatorio de 32 caracteres hexadecimales
+func nuevoIDSubida() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// idSubidaValido evita que un ID manipulado se use como ruta
+func idSubidaValido(id string) bool {
+	if len(id) != 32 {
+		return false
+	}
+	_, err := hex.DecodeString(id)
+	return err == nil
+}
+
+// verificarChecksum compara el encabezado "sha256 <base64>" con el fragmento
+func verificarChecksum(checksum string, datos []byte) error {
+	algoritmo, valor, ok := strings.Cut(strings.TrimSpace(checksum), " ")
+	if !ok || !strings.EqualFold(algoritmo, "sha256") {
+		return fmt.Errorf("checksum no soportado: use \"sha256 <base64>\"")
+	}
+	esperado, err := base64.StdEncoding.DecodeString(strings.TrimSpace(valor))
+	if err != nil {
+		return fmt.Errorf("checksum inválido: %v", err)
+	}
+	suma := sha256.Sum256(datos)
+	if !bytes.Equal(esperado, suma[:]) {
+		return ErrChecksumInvalido
+	}
+	return nil
+}
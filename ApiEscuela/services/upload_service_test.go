@@ -0,0 +1,177 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"ApiEscuela/inspeccion"
+	"ApiEscuela/repositories/memory"
+	"ApiEscuela/services"
+	"ApiEscuela/storage"
+)
+
+func checksum(datos []byte) string {
+	suma := sha256.Sum256(datos)
+	return "sha256 " + base64.StdEncoding.EncodeToString(suma[:])
+}
+
+// nuevoUploadService crea el servicio sin escáner de virus y con metadatos en memoria
+func nuevoUploadService(t *testing.T, vigencia time.Duration, archivos storage.Storage) services.UploadService {
+	return services.NewUploadService(t.TempDir(), vigencia, archivos, inspeccion.New(nil), services.NewMediaService(memory.NewRepositories().Media, archivos))
+}
+
+func TestSubidaPorPartes(t *testing.T) {
+	archivos := storage.NewMemoria()
+	svc := nuevoUploadService(t, time.Hour, archivos)
+
+	// Cabecera de un MP4 seguida de datos de relleno
+	contenido := append([]byte("\x00\x00\x00\x18ftypisom\x00\x00\x02\x00isomiso2"), bytes.Repeat([]byte("0123456789"), 500)...)
+	suma := sha256.Sum256(contenido)
+	sesion, err := svc.CreateUpload(7, services.NuevaSubida{
+		Nombre: "clase.MP4", Tamano: int64(len(contenido)), Sha256: hex.EncodeToString(suma[:]),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sesion.Tipo != "video" || sesion.Offset != 0 {
+		t.Fatalf("sesión = %+v", sesion)
+	}
+
+	// Otro usuario no ve la subida
+	if _, err := svc.GetUpload(8, sesion.ID); !errors.Is(err, services.ErrSubidaNoEncontrada) {
+		t.Errorf("GetUpload de otro usuario = %v", err)
+	}
+
+	primero := contenido[:2000]
+	if _, err := svc.WriteChunk(7, sesion.ID, 0, primero, checksum(primero)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.FinalizeUpload(7, sesion.ID); !errors.Is(err, services.ErrSubidaIncompleta) {
+		t.Errorf("finalizar incompleta = %v", err)
+	}
+
+	// Un fragmento repetido (por ejemplo, tras un corte) informa desde dónde continuar
+	var offsetInvalido *services.ErrOffsetInvalido
+	if _, err := svc.WriteChunk(7, sesion.ID, 0, primero, ""); !errors.As(err, &offsetInvalido) || offsetInvalido.Esperado != 2000 {
+		t.Errorf("offset repetido = %v", err)
+	}
+	if _, err := svc.WriteChunk(7, sesion.ID, 2000, contenido[2000:4000], checksum([]byte("otro"))); !errors.Is(err, services.ErrChecksumInvalido) {
+		t.Errorf("checksum incorrecto = %v", err)
+	}
+	if _, err := svc.WriteChunk(7, sesion.ID, 2000, make([]byte, 4000), ""); !errors.Is(err, services.ErrFragmentoExcedido) {
+		t.Errorf("fragmento excedido = %v", err)
+	}
+
+	if _, err := svc.WriteChunk(7, sesion.ID, 2000, contenido[2000:], ""); err != nil {
+		t.Fatal(err)
+	}
+	estado, err := svc.GetUpload(7, sesion.ID)
+	if err != nil || estado.Offset != int64(len(contenido)) {
+		t.Fatalf("estado = %+v, %v", estado, err)
+	}
+
+	archivo, err := svc.FinalizeUpload(7, sesion.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(archivo.Clave, "videos/") || !strings.HasSuffix(archivo.Clave, ".mp4") || archivo.Tamano != int64(len(contenido)) {
+		t.Errorf("archivo = %+v", archivo)
+	}
+	r, obj, err := archivos.Abrir(context.Background(), archivo.Clave)
+	if err != nil {
+		t.Fatal(err)
+	}
+	guardado, _ := io.ReadAll(r)
+	r.Close()
+	if !bytes.Equal(guardado, contenido) || obj.ContentType != "video/mp4" {
+		t.Errorf("contenido guardado = %d bytes, %s", len(guardado), obj.ContentType)
+	}
+
+	// La sesión deja de existir al finalizar
+	if _, err := svc.GetUpload(7, sesion.ID); !errors.Is(err, services.ErrSubidaNoEncontrada) {
+		t.Errorf("sesión tras finalizar = %v", err)
+	}
+}
+
+func TestSubidaPorPartesChecksumFinal(t *testing.T) {
+	svc := nuevoUploadService(t, time.Hour, storage.NewMemoria())
+
+	otro := sha256.Sum256([]byte("otro contenido"))
+	sesion, err := svc.CreateUpload(1, services.NuevaSubida{
+		Nombre: "acta.pdf", Tamano: 8, Sha256: hex.EncodeToString(otro[:]),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.WriteChunk(1, sesion.ID, 0, []byte("%PDF-1.7"), ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.FinalizeUpload(1, sesion.ID); !errors.Is(err, services.ErrChecksumInvalido) {
+		t.Errorf("finalizar con sha256 distinto = %v", err)
+	}
+}
+
+func TestSubidaPorPartesContenidoRechazado(t *testing.T) {
+	svc := nuevoUploadService(t, time.Hour, storage.NewMemoria())
+
+	// Un ejecutable con extensión .pdf se rechaza al finalizar y la subida se descarta
+	ejecutable := []byte("MZ\x90\x00\x03\x00\x00\x00")
+	sesion, err := svc.CreateUpload(1, services.NuevaSubida{Nombre: "acta.pdf", Tamano: int64(len(ejecutable))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.WriteChunk(1, sesion.ID, 0, ejecutable, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.FinalizeUpload(1, sesion.ID); !errors.Is(err, inspeccion.ErrContenidoNoCoincide) {
+		t.Errorf("finalizar ejecutable = %v", err)
+	}
+	if _, err := svc.GetUpload(1, sesion.ID); !errors.Is(err, services.ErrSubidaNoEncontrada) {
+		t.Errorf("la subida rechazada debería descartarse: %v", err)
+	}
+}
+
+func TestSubidaPorPartesValidacion(t *testing.T) {
+	svc := nuevoUploadService(t, time.Hour, storage.NewMemoria())
+
+	casos := []services.NuevaSubida{
+		{Nombre: "script.exe", Tamano: 10},
+		{Nombre: "pelicula.mp4", Tamano: inspeccion.TamanoMaximo + 1},
+		{Nombre: "foto.jpg", Tamano: 0},
+		{Nombre: "foto.jpg", Tamano: 10, Sha256: "no-es-hex"},
+	}
+	for _, c := range casos {
+		if _, err := svc.CreateUpload(1, c); err == nil {
+			t.Errorf("CreateUpload(%+v) debería fallar", c)
+		}
+	}
+	if _, err := svc.GetUpload(1, "../../etc/passwd"); !errors.Is(err, services.ErrSubidaNoEncontrada) {
+		t.Errorf("ID manipulado = %v", err)
+	}
+}
+
+func TestSubidasVencidas(t *testing.T) {
+	svc := nuevoUploadService(t, 10*time.Millisecond, storage.NewMemoria())
+
+	sesion, err := svc.CreateUpload(1, services.NuevaSubida{Nombre: "foto.png", Tamano: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	if _, err := svc.WriteChunk(1, sesion.ID, 0, []byte("x"), ""); !errors.Is(err, services.ErrSubidaNoEncontrada) {
+		t.Errorf("escribir en subida vencida = %v", err)
+	}
+	n, err := svc.PurgeExpiredUploads()
+	if err != nil || n != 1 {
+		t.Errorf("PurgeExpiredUploads = %d, %v; se esperaba 1", n, err)
+	}
+}
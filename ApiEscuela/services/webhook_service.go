@@ -0,0 +1,480 @@
+package services
+
+import (
+	"ApiEscuela/errores"
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Eventos que se pueden suscribir con un webhook
+const (
+	EventoEstudianteCreado          = "estudiante.created"
+	EventoProgramaVisitaCreado      = "programa_visita.created"
+	EventoProgramaVisitaActualizado = "programa_visita.updated"
+	EventoDudaRespondida            = "duda.answered"
+	EventoComunicadoEnviado         = "comunicado.sent"
+	// EventoWebhookPrueba solo se envía con POST /api/webhooks/:id/prueba
+	EventoWebhookPrueba = "webhook.ping"
+)
+
+// Cabeceras de las entregas. La firma es "sha256=" seguido del HMAC-SHA256 en hexadecimal de
+// "<timestamp>.<cuerpo>" con el secreto del webhook (ver FirmaWebhook).
+const (
+	CabeceraEventoWebhook    = "X-Webhook-Evento"
+	CabeceraEntregaWebhook   = "X-Webhook-Entrega"
+	CabeceraTimestampWebhook = "X-Webhook-Timestamp"
+	CabeceraFirmaWebhook     = "X-Webhook-Firma"
+)
+
+// Valores por defecto de ConfigWebhooks
+const (
+	maxIntentosWebhook = 8
+	esperaBaseWebhook  = 30 * time.Second
+	timeoutWebhook     = 10 * time.Second
+	// esperaMaximaWebhook limita el crecimiento exponencial de la espera entre intentos
+	esperaMaximaWebhook = 24 * time.Hour
+	// reintentosPorEjecucion es cuántas entregas vencidas reintenta cada ejecución de la tarea
+	reintentosPorEjecucion = 100
+)
+
+// Errores del servicio de webhooks
+var (
+	ErrWebhookNoEncontrado = errores.NoEncontrado("webhook_no_encontrado", "El webhook no existe")
+	ErrEntregaNoEncontrada = errores.NoEncontrado("entrega_no_encontrada", "La entrega no existe")
+)
+
+// EventoWebhook describe un evento del catálogo de webhooks
+type EventoWebhook struct {
+	Nombre      string `json:"nombre"`
+	Descripcion string `json:"descripcion"`
+}
+
+// catalogoEventosWebhook son los eventos que se pueden suscribir, en el orden en que se listan
+var catalogoEventosWebhook = []EventoWebhook{
+	{EventoEstudianteCreado, "Se registró un estudiante; datos es el estudiante"},
+	{EventoProgramaVisitaCreado, "Se agendó un programa de visita; datos es el programa"},
+	{EventoProgramaVisitaActualizado, "Se modificó un programa de visita; datos es el programa actualizado"},
+	{EventoDudaRespondida, "Una autoridad respondió una duda; datos es la duda con su respuesta"},
+	{EventoComunicadoEnviado, "Se envió un comunicado; datos es el comunicado"},
+}
+
+// CargaWebhook es el cuerpo JSON de cada entrega. ID identifica la ocurrencia del evento: es el
+// mismo en todos los webhooks suscritos, en los reintentos y en los reenvíos.
+type CargaWebhook struct {
+	ID     string      `json:"id"`
+	Evento string      `json:"evento"`
+	Fecha  time.Time   `json:"fecha"`
+	Datos  interface{} `json:"datos"`
+}
+
+// WebhookCreado es un webhook recién creado junto con su secreto, que solo se muestra al crearlo
+type WebhookCreado struct {
+	models.Webhook
+	Secreto string `json:"secreto"`
+}
+
+// ResultadoReintentos resume una ejecución de ReintentarPendientes
+type ResultadoReintentos struct {
+	Entregadas    int `json:"entregadas"`
+	Reprogramadas int `json:"reprogramadas"`
+	Fallidas      int `json:"fallidas"`
+}
+
+// ConfigWebhooks configura los intentos de entrega. Los campos en cero toman el valor por defecto.
+type ConfigWebhooks struct {
+	// MaxIntentos es la cantidad de intentos antes de dar una entrega por fallida
+	MaxIntentos int
+	// EsperaBase es la espera antes del primer reintento; se duplica en cada intento siguiente
+	EsperaBase time.Duration
+	// Cliente envía las peticiones; por defecto con un timeout de 10 segundos
+	Cliente *http.Client
+}
+
+// webhookService guarda las suscripciones y entrega los eventos firmados a cada una
+type webhookService struct {
+	repo   repositories.WebhookRepository
+	config ConfigWebhooks
+	envios sync.WaitGroup
+}
+
+// NewWebhookService crea una nueva instancia del servicio
+func NewWebhookService(repo repositories.WebhookRepository, config ConfigWebhooks) WebhookService {
+	if config.MaxIntentos <= 0 {
+		config.MaxIntentos = maxIntentosWebhook
+	}
+	if config.EsperaBase <= 0 {
+		config.EsperaBase = esperaBaseWebhook
+	}
+	if config.Cliente == nil {
+		config.Cliente = &http.Client{Timeout: timeoutWebhook}
+	}
+	return &webhookService{repo: repo, config: config}
+}
+
+// FirmaWebhook calcula el valor de la cabecera X-Webhook-Firma de un cuerpo. El receptor debe
+// recalcularla con su copia del secreto y compararla en tiempo constante.
+func FirmaWebhook(secreto, timestamp string, cuerpo []byte) string {
+	mac := hmac.New(sha256.New, []byte(secreto))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(cuerpo)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// EventosDisponibles devuelve el catálogo de eventos que se pueden suscribir
+func (s *webhookService) EventosDisponibles() []EventoWebhook {
+	return catalogoEventosWebhook
+}
+
+// GetWebhooks obtiene todas las suscripciones
+func (s *webhookService) GetWebhooks() ([]models.Webhook, error) {
+	return s.repo.GetAllWebhooks()
+}
+
+// GetWebhook obtiene una suscripción por su ID
+func (s *webhookService) GetWebhook(id uint) (*models.Webhook, error) {
+	webhook, err := s.repo.GetWebhookByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrWebhookNoEncontrado
+	}
+	return webhook, err
+}
+
+// CrearWebhook valida y guarda una suscripción. Si no trae secreto se genera uno aleatorio.
+func (s *webhookService) CrearWebhook(webhook *models.Webhook) (*WebhookCreado, error) {
+	if err := validarWebhook(webhook); err != nil {
+		return nil, err
+	}
+	if webhook.Secreto == "" {
+		secreto, err := generarSecretoWebhook()
+		if err != nil {
+			return nil, err
+		}
+		webhook.Secreto = secreto
+	} else if len(webhook.Secreto) < 16 {
+		return nil, errores.Validacion("webhook_invalido", "El secreto es demasiado corto",
+			errores.Campo{Campo: "secreto", Mensaje: "Debe tener al menos 16 caracteres"})
+	}
+	if err := s.repo.CreateWebhook(webhook); err != nil {
+		return nil, err
+	}
+	return &WebhookCreado{Webhook: *webhook, Secreto: webhook.Secreto}, nil
+}
+
+// ActualizarWebhook valida y guarda los cambios de una suscripción; el secreto no cambia
+func (s *webhookService) ActualizarWebhook(webhook *models.Webhook) error {
+	actual, err := s.GetWebhook(webhook.ID)
+	if err != nil {
+		return err
+	}
+	if err := validarWebhook(webhook); err != nil {
+		return err
+	}
+	webhook.Secreto, webhook.CreatedAt = actual.Secreto, actual.CreatedAt
+	return s.repo.UpdateWebhook(webhook)
+}
+
+// EliminarWebhook elimina una suscripción; sus entregas pendientes se dan por fallidas al reintentarlas
+func (s *webhookService) EliminarWebhook(id uint) error {
+	if _, err := s.GetWebhook(id); err != nil {
+		return err
+	}
+	return s.repo.DeleteWebhook(id)
+}
+
+// GetEntregas obtiene el registro de entregas de una suscripción, de la más reciente a la más antigua
+func (s *webhookService) GetEntregas(webhookID uint, limite int) ([]models.EntregaWebhook, error) {
+	if _, err := s.GetWebhook(webhookID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetEntregasByWebhook(webhookID, limite)
+}
+
+// Publicar registra una entrega del evento para cada webhook activo suscrito y las envía en
+// segundo plano; las que fallan quedan pendientes para la tarea de reintentos
+func (s *webhookService) Publicar(evento string, datos interface{}) error {
+	webhooks, err := s.repo.GetWebhooksActivos()
+	if err != nil {
+		return fmt.Errorf("error al obtener los webhooks: %v", err)
+	}
+	var suscritos []models.Webhook
+	for _, webhook := range webhooks {
+		if suscrito(webhook, evento) {
+			suscritos = append(suscritos, webhook)
+		}
+	}
+	if len(suscritos) == 0 {
+		return nil
+	}
+	payload, err := cargaWebhook(evento, datos)
+	if err != nil {
+		return err
+	}
+	for _, webhook := range suscritos {
+		entrega, err := s.registrarEntrega(webhook.ID, evento, payload, nil)
+		if err != nil {
+			return err
+		}
+		s.envios.Add(1)
+		go func(id uint) {
+			defer s.envios.Done()
+			if _, err := s.entregar(id, time.Now()); err != nil {
+				log.Printf("Error al entregar el webhook (entrega %d): %v", id, err)
+			}
+		}(entrega.ID)
+	}
+	return nil
+}
+
+// Probar envía un evento webhook.ping a la suscripción y devuelve el resultado del intento
+func (s *webhookService) Probar(webhookID uint) (*models.EntregaWebhook, error) {
+	if _, err := s.GetWebhook(webhookID); err != nil {
+		return nil, err
+	}
+	payload, err := cargaWebhook(EventoWebhookPrueba, map[string]interface{}{"webhook_id": webhookID})
+	if err != nil {
+		return nil, err
+	}
+	entrega, err := s.registrarEntrega(webhookID, EventoWebhookPrueba, payload, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.entregar(entrega.ID, time.Now())
+}
+
+// Reenviar crea una entrega nueva con el mismo cuerpo que otra y la intenta de inmediato; si
+// falla se reintenta como cualquier otra
+func (s *webhookService) Reenviar(entregaID uint) (*models.EntregaWebhook, error) {
+	original, err := s.repo.GetEntregaByID(entregaID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrEntregaNoEncontrada
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.GetWebhook(original.WebhookID); err != nil {
+		return nil, err
+	}
+	entrega, err := s.registrarEntrega(original.WebhookID, original.Evento, original.Payload, &original.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.entregar(entrega.ID, time.Now())
+}
+
+// ReintentarPendientes intenta de nuevo las entregas pendientes cuyo próximo intento venció
+func (s *webhookService) ReintentarPendientes(ahora time.Time) (*ResultadoReintentos, error) {
+	pendientes, err := s.repo.GetEntregasPendientes(ahora, reintentosPorEjecucion)
+	if err != nil {
+		return nil, err
+	}
+	result := &ResultadoReintentos{}
+	for _, pendiente := range pendientes {
+		entrega, err := s.entregar(pendiente.ID, ahora)
+		if err != nil {
+			return result, err
+		}
+		if entrega == nil {
+			continue // otro proceso la reclamó
+		}
+		switch entrega.Estado {
+		case repositories.EntregaEntregada:
+			result.Entregadas++
+		case repositories.EntregaFallida:
+			result.Fallidas++
+		default:
+			result.Reprogramadas++
+		}
+	}
+	return result, nil
+}
+
+// Esperar bloquea hasta que terminen los envíos en segundo plano
+func (s *webhookService) Esperar() {
+	s.envios.Wait()
+}
+
+// registrarEntrega guarda una entrega pendiente que vence de inmediato
+func (s *webhookService) registrarEntrega(webhookID uint, evento, payload string, reenvioDe *uint) (*models.EntregaWebhook, error) {
+	// Postgres guarda microsegundos: truncar evita que el reclamo inmediato la vea aún sin vencer
+	ahora := time.Now().Truncate(time.Microsecond)
+	entrega := &models.EntregaWebhook{
+		WebhookID:      webhookID,
+		Evento:         evento,
+		Payload:        payload,
+		Estado:         repositories.EntregaPendiente,
+		ProximoIntento: &ahora,
+		ReenvioDe:      reenvioDe,
+	}
+	if err := s.repo.CreateEntrega(entrega); err != nil {
+		return nil, fmt.Errorf("error al registrar la entrega del webhook %d: %v", webhookID, err)
+	}
+	return entrega, nil
+}
+
+// entregar reclama una entrega vencida, la envía y guarda el resultado. Devuelve nil sin error si
+// otro proceso ya la había reclamado.
+func (s *webhookService) entregar(id uint, ahora time.Time) (*models.EntregaWebhook, error) {
+	reclamada, err := s.repo.ReclamarEntrega(id, ahora, time.Now().Add(2*s.config.Cliente.Timeout+time.Minute))
+	if err != nil || !reclamada {
+		return nil, err
+	}
+	entrega, err := s.repo.GetEntregaByID(id)
+	if err != nil {
+		return nil, err
+	}
+	entrega.Intentos++
+	entrega.UltimoEstado, entrega.UltimoError = 0, ""
+
+	webhook, err := s.repo.GetWebhookByID(entrega.WebhookID)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		entrega.UltimoError = "el webhook fue eliminado"
+		entrega.Estado, entrega.ProximoIntento = repositories.EntregaFallida, nil
+		return entrega, s.repo.UpdateEntrega(entrega)
+	case err != nil:
+		return nil, err
+	case !webhook.Activo && entrega.Evento != EventoWebhookPrueba:
+		entrega.UltimoError = "el webhook está desactivado"
+		entrega.Estado, entrega.ProximoIntento = repositories.EntregaFallida, nil
+		return entrega, s.repo.UpdateEntrega(entrega)
+	}
+
+	entrega.UltimoEstado, err = s.enviar(webhook, entrega)
+	if err == nil {
+		fin := time.Now()
+		entrega.Estado, entrega.ProximoIntento, entrega.EntregadaEn = repositories.EntregaEntregada, nil, &fin
+	} else {
+		entrega.UltimoError = err.Error()
+		if entrega.Intentos >= s.config.MaxIntentos {
+			entrega.Estado, entrega.ProximoIntento = repositories.EntregaFallida, nil
+		} else {
+			proximo := ahora.Add(s.espera(entrega.Intentos))
+			entrega.Estado, entrega.ProximoIntento = repositories.EntregaPendiente, &proximo
+		}
+	}
+	if err := s.repo.UpdateEntrega(entrega); err != nil {
+		return nil, err
+	}
+	return entrega, nil
+}
+
+// enviar hace el POST firmado de una entrega. Cualquier respuesta 2xx cuenta como entregada.
+func (s *webhookService) enviar(webhook *models.Webhook, entrega *models.EntregaWebhook) (int, error) {
+	cuerpo := []byte(entrega.Payload)
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(cuerpo))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "ApiEscuela-Webhooks/1.0")
+	req.Header.Set(CabeceraEventoWebhook, entrega.Evento)
+	req.Header.Set(CabeceraEntregaWebhook, strconv.FormatUint(uint64(entrega.ID), 10))
+	req.Header.Set(CabeceraTimestampWebhook, timestamp)
+	req.Header.Set(CabeceraFirmaWebhook, FirmaWebhook(webhook.Secreto, timestamp, cuerpo))
+
+	resp, err := s.config.Cliente.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("no se pudo conectar: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detalle, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return resp.StatusCode, fmt.Errorf("el receptor respondió %d: %s", resp.StatusCode, strings.TrimSpace(string(detalle)))
+	}
+	return resp.StatusCode, nil
+}
+
+// espera calcula cuánto esperar tras el intento fallido número intentos: la espera base se
+// duplica en cada intento, hasta un día
+func (s *webhookService) espera(intentos int) time.Duration {
+	espera := s.config.EsperaBase
+	for i := 1; i < intentos && espera < esperaMaximaWebhook; i++ {
+		espera *= 2
+	}
+	if espera > esperaMaximaWebhook {
+		espera = esperaMaximaWebhook
+	}
+	return espera
+}
+
+// suscrito indica si el webhook recibe el evento
+func suscrito(webhook models.Webhook, evento string) bool {
+	for _, e := range webhook.Eventos {
+		if e == evento {
+			return true
+		}
+	}
+	return false
+}
+
+// cargaWebhook arma el cuerpo JSON de un evento con un ID nuevo
+func cargaWebhook(evento string, datos interface{}) (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	cuerpo, err := json.Marshal(CargaWebhook{ID: hex.EncodeToString(id), Evento: evento, Fecha: time.Now(), Datos: datos})
+	if err != nil {
+		return "", fmt.Errorf("error al serializar el evento %s: %v", evento, err)
+	}
+	return string(cuerpo), nil
+}
+
+// generarSecretoWebhook genera un secreto aleatorio de 32 bytes en hexadecimal
+func generarSecretoWebhook() (string, error) {
+	secreto := make([]byte, 32)
+	if _, err := rand.Read(secreto); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secreto), nil
+}
+
+// validarWebhook comprueba la URL y que los eventos existan en el catálogo
+func validarWebhook(webhook *models.Webhook) error {
+	var campos []errores.Campo
+	webhook.URL = strings.TrimSpace(webhook.URL)
+	if u, err := url.Parse(webhook.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		campos = append(campos, errores.Campo{Campo: "url", Mensaje: "Debe ser una URL http o https", Valor: webhook.URL})
+	}
+	if len(webhook.Eventos) == 0 {
+		campos = append(campos, errores.Campo{Campo: "eventos", Mensaje: "Debe suscribir al menos un evento"})
+	}
+	for _, evento := range webhook.Eventos {
+		if !eventoDelCatalogo(evento) {
+			campos = append(campos, errores.Campo{Campo: "eventos", Mensaje: "Evento desconocido; consulte GET /api/webhooks/eventos", Valor: evento})
+		}
+	}
+	if len(campos) > 0 {
+		return errores.Validacion("webhook_invalido", "Los datos del webhook no son válidos", campos...)
+	}
+	return nil
+}
+
+func eventoDelCatalogo(evento string) bool {
+	for _, e := range catalogoEventosWebhook {
+		if e.Nombre == evento {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,185 @@
+package services_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"ApiEscuela/errores"
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+	"ApiEscuela/services"
+	"ApiEscuela/testutil"
+)
+
+// receptorWebhooks es un receptor local que verifica la firma de cada entrega
+type receptorWebhooks struct {
+	t       *testing.T
+	secreto string
+	mu      sync.Mutex
+	estado  int
+	cargas  []services.CargaWebhook
+	eventos []string
+}
+
+func nuevoReceptor(t *testing.T) (*receptorWebhooks, *httptest.Server) {
+	r := &receptorWebhooks{t: t, estado: http.StatusOK}
+	servidor := httptest.NewServer(r)
+	t.Cleanup(servidor.Close)
+	return r, servidor
+}
+
+func (r *receptorWebhooks) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	cuerpo, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	firma := services.FirmaWebhook(r.secreto, req.Header.Get(services.CabeceraTimestampWebhook), cuerpo)
+	if req.Header.Get(services.CabeceraFirmaWebhook) != firma {
+		r.t.Errorf("firma %q, se esperaba %q", req.Header.Get(services.CabeceraFirmaWebhook), firma)
+	}
+	var carga services.CargaWebhook
+	if err := json.Unmarshal(cuerpo, &carga); err != nil {
+		r.t.Errorf("cuerpo inválido %s: %v", cuerpo, err)
+	}
+	r.cargas = append(r.cargas, carga)
+	r.eventos = append(r.eventos, req.Header.Get(services.CabeceraEventoWebhook))
+	w.WriteHeader(r.estado)
+}
+
+func (r *receptorWebhooks) responder(estado int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.estado = estado
+}
+
+func (r *receptorWebhooks) recibidas() []services.CargaWebhook {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]services.CargaWebhook(nil), r.cargas...)
+}
+
+func TestWebhookReintentosConEsperaExponencial(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		receptor, servidor := nuevoReceptor(t)
+		servicio := services.NewWebhookService(repos.Webhook, services.ConfigWebhooks{EsperaBase: time.Minute, MaxIntentos: 5})
+		creado, err := servicio.CrearWebhook(&models.Webhook{URL: servidor.URL, Eventos: []string{services.EventoEstudianteCreado}, Activo: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(creado.Secreto) != 64 {
+			t.Fatalf("secreto generado %q", creado.Secreto)
+		}
+		receptor.secreto = creado.Secreto
+		// Otro receptor suscrito a otro evento no debe recibir nada
+		otro, otroServidor := nuevoReceptor(t)
+		if _, err := servicio.CrearWebhook(&models.Webhook{URL: otroServidor.URL, Eventos: []string{services.EventoComunicadoEnviado}, Activo: true}); err != nil {
+			t.Fatal(err)
+		}
+
+		receptor.responder(http.StatusServiceUnavailable)
+		if err := servicio.Publicar(services.EventoEstudianteCreado, map[string]uint{"id": 7}); err != nil {
+			t.Fatal(err)
+		}
+		servicio.Esperar()
+		entregas, _ := servicio.GetEntregas(creado.ID, 0)
+		if len(entregas) != 1 {
+			t.Fatalf("entregas = %+v, se esperaba una", entregas)
+		}
+		primera := entregas[0]
+		if primera.Estado != repositories.EntregaPendiente || primera.Intentos != 1 || primera.UltimoEstado != http.StatusServiceUnavailable || primera.ProximoIntento == nil {
+			t.Fatalf("tras el primer intento fallido: %+v", primera)
+		}
+		if espera := primera.ProximoIntento.Sub(primera.UpdatedAt); espera < 50*time.Second || espera > 70*time.Second {
+			t.Errorf("primer reintento en %v, se esperaba la espera base de un minuto", espera)
+		}
+
+		// Antes de vencer no se reintenta
+		if result, err := servicio.ReintentarPendientes(time.Now()); err != nil || result.Entregadas+result.Reprogramadas+result.Fallidas != 0 {
+			t.Errorf("reintento antes de tiempo: %+v, %v", result, err)
+		}
+		ahora := primera.ProximoIntento.Add(time.Second)
+		result, err := servicio.ReintentarPendientes(ahora)
+		if err != nil || result.Reprogramadas != 1 {
+			t.Fatalf("segundo intento: %+v, %v", result, err)
+		}
+		segunda, _ := servicio.GetEntregas(creado.ID, 0)
+		if segunda[0].Intentos != 2 || !segunda[0].ProximoIntento.Equal(ahora.Add(2*time.Minute)) {
+			t.Errorf("la espera no se duplicó: %+v", segunda[0])
+		}
+
+		receptor.responder(http.StatusNoContent)
+		result, err = servicio.ReintentarPendientes(ahora.Add(time.Hour))
+		if err != nil || result.Entregadas != 1 {
+			t.Fatalf("tercer intento: %+v, %v", result, err)
+		}
+		entregada, _ := servicio.GetEntregas(creado.ID, 0)
+		if e := entregada[0]; e.Estado != repositories.EntregaEntregada || e.Intentos != 3 || e.EntregadaEn == nil || e.ProximoIntento != nil || e.UltimoError != "" {
+			t.Errorf("entrega final: %+v", e)
+		}
+		cargas := receptor.recibidas()
+		if len(cargas) != 3 || cargas[0].ID != cargas[2].ID || cargas[0].Evento != services.EventoEstudianteCreado {
+			t.Errorf("cargas recibidas: %+v", cargas)
+		}
+		if len(otro.recibidas()) != 0 {
+			t.Errorf("un webhook no suscrito recibió %+v", otro.recibidas())
+		}
+
+		// El reenvío es una entrega nueva con el mismo cuerpo
+		reenvio, err := servicio.Reenviar(entregada[0].ID)
+		if err != nil || reenvio.Estado != repositories.EntregaEntregada || reenvio.ReenvioDe == nil || *reenvio.ReenvioDe != entregada[0].ID {
+			t.Fatalf("reenvío = %+v, %v", reenvio, err)
+		}
+		if cargas := receptor.recibidas(); len(cargas) != 4 || cargas[3].ID != cargas[0].ID {
+			t.Errorf("el reenvío cambió el cuerpo: %+v", cargas)
+		}
+	})
+}
+
+func TestWebhookFallaTrasElMaximoDeIntentos(t *testing.T) {
+	testutil.ForEachBackend(t, func(t *testing.T, repos testutil.Repos) {
+		receptor, servidor := nuevoReceptor(t)
+		receptor.responder(http.StatusInternalServerError)
+		servicio := services.NewWebhookService(repos.Webhook, services.ConfigWebhooks{EsperaBase: time.Second, MaxIntentos: 2})
+		creado, err := servicio.CrearWebhook(&models.Webhook{URL: servidor.URL, Eventos: []string{services.EventoDudaRespondida}, Activo: true, Secreto: "un-secreto-compartido"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		receptor.secreto = "un-secreto-compartido"
+		if err := servicio.Publicar(services.EventoDudaRespondida, map[string]string{"respuesta": "sí"}); err != nil {
+			t.Fatal(err)
+		}
+		servicio.Esperar()
+		result, err := servicio.ReintentarPendientes(time.Now().Add(time.Minute))
+		if err != nil || result.Fallidas != 1 {
+			t.Fatalf("segundo intento: %+v, %v", result, err)
+		}
+		entregas, _ := servicio.GetEntregas(creado.ID, 0)
+		if e := entregas[0]; e.Estado != repositories.EntregaFallida || e.Intentos != 2 || e.ProximoIntento != nil || e.UltimoEstado != http.StatusInternalServerError {
+			t.Errorf("entrega tras agotar los intentos: %+v", e)
+		}
+		if result, _ := servicio.ReintentarPendientes(time.Now().Add(48 * time.Hour)); result.Fallidas+result.Reprogramadas != 0 {
+			t.Errorf("se reintentó una entrega fallida: %+v", result)
+		}
+	})
+}
+
+func TestWebhookValidacion(t *testing.T) {
+	servicio := services.NewWebhookService(testutil.MemoryRepos().Webhook, services.ConfigWebhooks{})
+	casos := map[string]*models.Webhook{
+		"sin esquema":        {URL: "receptor.uteq.edu.ec/hook", Eventos: []string{services.EventoEstudianteCreado}},
+		"ftp":                {URL: "ftp://receptor.uteq.edu.ec", Eventos: []string{services.EventoEstudianteCreado}},
+		"sin eventos":        {URL: "https://receptor.uteq.edu.ec/hook"},
+		"evento desconocido": {URL: "https://receptor.uteq.edu.ec/hook", Eventos: []string{"estudiante.deleted"}},
+		"secreto corto":      {URL: "https://receptor.uteq.edu.ec/hook", Eventos: []string{services.EventoEstudianteCreado}, Secreto: "corto"},
+	}
+	for nombre, webhook := range casos {
+		if _, err := servicio.CrearWebhook(webhook); !errors.Is(err, errores.ErrValidacion) {
+			t.Errorf("%s: err = %v, se esperaba un error de validación", nombre, err)
+		}
+	}
+}
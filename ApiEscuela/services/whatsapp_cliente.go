@@ -0,0 +1,46 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// MensajeroWhatsApp envía mensajes de texto por WhatsApp
+type MensajeroWhatsApp interface {
+	EnviarMensaje(telefono, mensaje string) error
+}
+
+// clienteWhatsApp envía mensajes a través del servicio de WhatsApp (Node.js)
+type clienteWhatsApp struct {
+	url     string
+	cliente *http.Client
+}
+
+// NewClienteWhatsApp crea un cliente del servicio de WhatsApp en serviceURL
+func NewClienteWhatsApp(serviceURL string) MensajeroWhatsApp {
+	return &clienteWhatsApp{url: serviceURL, cliente: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// EnviarMensaje envía un mensaje con POST /send-message
+func (c *clienteWhatsApp) EnviarMensaje(telefono, mensaje string) error {
+	datos, err := json.Marshal(map[string]string{"phone": telefono, "message": mensaje})
+	if err != nil {
+		return err
+	}
+	resp, err := c.cliente.Post(c.url+"/send-message", "application/json", bytes.NewReader(datos))
+	if err != nil {
+		return fmt.Errorf("servicio de WhatsApp no disponible: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var respuesta struct {
+			Error string `json:"error"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&respuesta)
+		return fmt.Errorf("el servicio de WhatsApp respondió %d: %s", resp.StatusCode, respuesta.Error)
+	}
+	return nil
+}
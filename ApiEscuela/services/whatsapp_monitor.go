@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"time"
+
+	"ApiEscuela/eventos"
+)
+
+// EstadoWhatsAppNoDisponible es el estado que se publica cuando el servicio de WhatsApp no responde
+const EstadoWhatsAppNoDisponible = "no_disponible"
+
+// MonitorWhatsApp consulta el servicio de WhatsApp (Node.js) mientras hay clientes suscritos a
+// los eventos y publica a los administradores los cambios de conexión, de código QR y de la cola
+// de envío masivo; el QR vincula la cuenta, por eso no llega a los demás usuarios
+type MonitorWhatsApp struct {
+	url       string
+	cliente   *http.Client
+	bus       *eventos.Bus
+	intervalo time.Duration
+
+	// Último valor publicado de cada evento; solo los usa Revisar
+	estado string
+	qr     string
+	cola   map[string]interface{}
+}
+
+// NewMonitorWhatsApp crea un monitor del servicio de WhatsApp en serviceURL que revisa cada intervalo
+func NewMonitorWhatsApp(serviceURL string, bus *eventos.Bus, intervalo time.Duration) *MonitorWhatsApp {
+	return &MonitorWhatsApp{
+		url:       serviceURL,
+		cliente:   &http.Client{Timeout: 5 * time.Second},
+		bus:       bus,
+		intervalo: intervalo,
+	}
+}
+
+// Iniciar revisa el servicio cada intervalo hasta que se cancele ctx. Sin suscriptores no
+// consulta nada: el cliente que llega recibe el último estado publicado y luego los cambios.
+func (m *MonitorWhatsApp) Iniciar(ctx context.Context) {
+	if m.intervalo <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(m.intervalo)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if m.bus.Suscriptores() > 0 {
+					m.Revisar()
+				}
+			}
+		}
+	}()
+}
+
+// Revisar consulta el servicio una vez y publica lo que cambió desde la revisión anterior.
+// No es seguro llamarlo en paralelo con otra revisión.
+func (m *MonitorWhatsApp) Revisar() {
+	var status struct {
+		Status string `json:"status"`
+	}
+	if err := m.consultar("/status", &status); err != nil {
+		status.Status = EstadoWhatsAppNoDisponible
+	}
+	if status.Status != m.estado {
+		m.estado = status.Status
+		m.bus.PublicarAdministradores(eventos.WhatsAppEstado, map[string]string{"status": status.Status})
+	}
+	if status.Status == EstadoWhatsAppNoDisponible {
+		return
+	}
+
+	// El QR solo existe mientras la sesión no está autenticada
+	if status.Status != "ready" && status.Status != "authenticated" {
+		var qr QRRespuestaWhatsApp
+		if err := m.consultar("/qr", &qr); err == nil && qr.QR != "" && qr.QR != m.qr {
+			m.qr = qr.QR
+			m.bus.PublicarAdministradores(eventos.WhatsAppQR, qr)
+		}
+	} else if m.qr != "" {
+		// Ya no hay QR que mostrar; el QR vacío reemplaza al último publicado
+		m.qr = ""
+		m.bus.PublicarAdministradores(eventos.WhatsAppQR, QRRespuestaWhatsApp{Status: status.Status})
+	}
+
+	var cola struct {
+		Queue map[string]interface{} `json:"queue"`
+	}
+	if err := m.consultar("/queue/status", &cola); err == nil && cola.Queue != nil && !reflect.DeepEqual(cola.Queue, m.cola) {
+		m.cola = cola.Queue
+		m.bus.PublicarAdministradores(eventos.WhatsAppCola, cola.Queue)
+	}
+}
+
+// QRRespuestaWhatsApp es la respuesta de /qr del servicio de WhatsApp
+type QRRespuestaWhatsApp struct {
+	QR     string `json:"qr"`
+	Status string `json:"status"`
+}
+
+// consultar hace un GET al servicio y decodifica la respuesta JSON
+func (m *MonitorWhatsApp) consultar(ruta string, v interface{}) error {
+	resp, err := m.cliente.Get(m.url + ruta)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("el servicio de WhatsApp respondió %d en %s", resp.StatusCode, ruta)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
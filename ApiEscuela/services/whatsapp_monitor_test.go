@@ -0,0 +1,94 @@
+package services_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"ApiEscuela/eventos"
+	"ApiEscuela/services"
+)
+
+// whatsAppDePrueba imita las rutas de estado del servicio de WhatsApp
+type whatsAppDePrueba struct {
+	mu        sync.Mutex
+	status    string
+	qr        string
+	pendiente int
+}
+
+func (w *whatsAppDePrueba) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var respuesta interface{}
+	switch r.URL.Path {
+	case "/status":
+		respuesta = map[string]interface{}{"status": w.status}
+	case "/qr":
+		respuesta = map[string]interface{}{"success": w.qr != "", "qr": w.qr, "status": w.status}
+	case "/queue/status":
+		respuesta = map[string]interface{}{"success": true, "queue": map[string]interface{}{"pending": w.pendiente}}
+	default:
+		http.NotFound(rw, r)
+		return
+	}
+	_ = json.NewEncoder(rw).Encode(respuesta)
+}
+
+func TestMonitorWhatsAppPublicaCambios(t *testing.T) {
+	falso := &whatsAppDePrueba{status: "qr", qr: "codigo-1"}
+	servidor := httptest.NewServer(falso)
+	defer servidor.Close()
+	bus := eventos.New()
+	sub := bus.Suscribir(1, true, 0)
+	monitor := services.NewMonitorWhatsApp(servidor.URL, bus, 0)
+
+	leer := func() []string {
+		var tipos []string
+		for {
+			select {
+			case e := <-sub.C:
+				tipos = append(tipos, e.Tipo)
+			default:
+				return tipos
+			}
+		}
+	}
+	igual := func(got []string, want ...string) bool {
+		if len(got) != len(want) {
+			return false
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				return false
+			}
+		}
+		return true
+	}
+
+	monitor.Revisar()
+	if got := leer(); !igual(got, eventos.WhatsAppEstado, eventos.WhatsAppQR, eventos.WhatsAppCola) {
+		t.Fatalf("primera revisión publicó %v", got)
+	}
+	monitor.Revisar()
+	if got := leer(); len(got) != 0 {
+		t.Errorf("sin cambios se publicó %v", got)
+	}
+
+	// Al autenticarse cambia el estado, el QR se vacía y la cola avanza
+	falso.mu.Lock()
+	falso.status, falso.qr, falso.pendiente = "ready", "", 3
+	falso.mu.Unlock()
+	monitor.Revisar()
+	if got := leer(); !igual(got, eventos.WhatsAppEstado, eventos.WhatsAppQR, eventos.WhatsAppCola) {
+		t.Errorf("tras autenticarse se publicó %v", got)
+	}
+
+	servidor.Close()
+	monitor.Revisar()
+	if got := leer(); !igual(got, eventos.WhatsAppEstado) {
+		t.Errorf("con el servicio caído se publicó %v, se esperaba solo el estado", got)
+	}
+}
@@ -0,0 +1,176 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// prefijoTemporal marca los archivos que se están escribiendo; Listar los ignora
+const prefijoTemporal = ".subida-"
+
+// Local guarda los archivos en una carpeta del disco
+type Local struct {
+	dir string
+}
+
+var _ Storage = (*Local)(nil)
+
+// NewLocal crea un backend sobre la carpeta dir
+func NewLocal(dir string) *Local {
+	return &Local{dir: dir}
+}
+
+// Nombre describe el backend
+func (l *Local) Nombre() string {
+	return DriverLocal + ":" + l.dir
+}
+
+// ruta valida la clave y devuelve su ruta en disco
+func (l *Local) ruta(clave string) (string, string, error) {
+	clave, err := Clave(clave)
+	if err != nil {
+		return "", "", err
+	}
+	return clave, filepath.Join(l.dir, filepath.FromSlash(clave)), nil
+}
+
+// Guardar escribe el archivo en un temporal y lo renombra para que nunca se lea a medias
+func (l *Local) Guardar(ctx context.Context, clave string, r io.Reader, tamano int64, contentType string) error {
+	_, ruta, err := l.ruta(clave)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(ruta), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(ruta), prefijoTemporal+"*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), ruta)
+}
+
+// Abrir abre el archivo
+func (l *Local) Abrir(ctx context.Context, clave string) (io.ReadCloser, *Objeto, error) {
+	clave, ruta, err := l.ruta(clave)
+	if err != nil {
+		return nil, nil, err
+	}
+	f, err := os.Open(ruta)
+	if err != nil {
+		return nil, nil, errorLocal(err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, err
+	}
+	if !info.Mode().IsRegular() {
+		f.Close()
+		return nil, nil, ErrNoEncontrado
+	}
+	return f, objetoLocal(clave, info), nil
+}
+
+// Info devuelve los datos del archivo
+func (l *Local) Info(ctx context.Context, clave string) (*Objeto, error) {
+	clave, ruta, err := l.ruta(clave)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(ruta)
+	if err != nil {
+		return nil, errorLocal(err)
+	}
+	if !info.Mode().IsRegular() {
+		return nil, ErrNoEncontrado
+	}
+	return objetoLocal(clave, info), nil
+}
+
+// Eliminar borra el archivo y las carpetas intermedias que queden vacías
+// (por ejemplo, la carpeta con fecha de los adjuntos de un comunicado)
+func (l *Local) Eliminar(ctx context.Context, clave string) error {
+	clave, ruta, err := l.ruta(clave)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(ruta); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	// Se conservan las carpetas de primer nivel (images, documents...)
+	partes := strings.Split(clave, "/")
+	for i := len(partes) - 1; i > 1; i-- {
+		dir := filepath.Join(l.dir, filepath.FromSlash(strings.Join(partes[:i], "/")))
+		if os.Remove(dir) != nil { // solo se elimina si está vacía
+			break
+		}
+	}
+	return nil
+}
+
+// Listar recorre los archivos cuya clave empieza con prefijo
+func (l *Local) Listar(ctx context.Context, prefijo string, fn func(Objeto) error) error {
+	// Solo se recorre la carpeta más profunda que contiene al prefijo
+	base := ""
+	if i := strings.LastIndex(prefijo, "/"); i >= 0 {
+		base = prefijo[:i]
+	}
+	raiz := filepath.Join(l.dir, filepath.FromSlash(base))
+	err := filepath.WalkDir(raiz, func(ruta string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && ruta == raiz {
+				return nil
+			}
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() || strings.HasPrefix(d.Name(), prefijoTemporal) {
+			return nil
+		}
+		rel, err := filepath.Rel(l.dir, ruta)
+		if err != nil {
+			return err
+		}
+		clave := filepath.ToSlash(rel)
+		if !strings.HasPrefix(clave, prefijo) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return fn(*objetoLocal(clave, info))
+	})
+	return err
+}
+
+func objetoLocal(clave string, info fs.FileInfo) *Objeto {
+	return &Objeto{Clave: clave, Tamano: info.Size(), ContentType: ContentTypeDe(clave), Modificado: info.ModTime()}
+}
+
+// errorLocal traduce los errores de archivo inexistente
+func errorLocal(err error) error {
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNoEncontrado
+	}
+	return err
+}
@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Memoria guarda los archivos en memoria; se usa en las pruebas
+type Memoria struct {
+	mu       sync.RWMutex
+	archivos map[string]archivoMemoria
+}
+
+type archivoMemoria struct {
+	datos []byte
+	obj   Objeto
+}
+
+var _ Storage = (*Memoria)(nil)
+
+// NewMemoria crea un backend en memoria vacío
+func NewMemoria() *Memoria {
+	return &Memoria{archivos: map[string]archivoMemoria{}}
+}
+
+// Nombre describe el backend
+func (m *Memoria) Nombre() string {
+	return "memoria"
+}
+
+// Guardar guarda una copia del contenido
+func (m *Memoria) Guardar(ctx context.Context, clave string, r io.Reader, tamano int64, contentType string) error {
+	clave, err := Clave(clave)
+	if err != nil {
+		return err
+	}
+	datos, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	if contentType == "" {
+		contentType = ContentTypeDe(clave)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.archivos[clave] = archivoMemoria{
+		datos: datos,
+		obj:   Objeto{Clave: clave, Tamano: int64(len(datos)), ContentType: contentType, Modificado: time.Now()},
+	}
+	return nil
+}
+
+// Abrir devuelve un lector sobre el contenido
+func (m *Memoria) Abrir(ctx context.Context, clave string) (io.ReadCloser, *Objeto, error) {
+	a, err := m.buscar(clave)
+	if err != nil {
+		return nil, nil, err
+	}
+	obj := a.obj
+	return io.NopCloser(bytes.NewReader(a.datos)), &obj, nil
+}
+
+// Info devuelve los datos del archivo
+func (m *Memoria) Info(ctx context.Context, clave string) (*Objeto, error) {
+	a, err := m.buscar(clave)
+	if err != nil {
+		return nil, err
+	}
+	obj := a.obj
+	return &obj, nil
+}
+
+// Eliminar borra el archivo
+func (m *Memoria) Eliminar(ctx context.Context, clave string) error {
+	clave, err := Clave(clave)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.archivos, clave)
+	return nil
+}
+
+// Listar recorre en orden los archivos cuya clave empieza con prefijo
+func (m *Memoria) Listar(ctx context.Context, prefijo string, fn func(Objeto) error) error {
+	m.mu.RLock()
+	var objetos []Objeto
+	for clave, a := range m.archivos {
+		if strings.HasPrefix(clave, prefijo) {
+			objetos = append(objetos, a.obj)
+		}
+	}
+	m.mu.RUnlock()
+	sort.Slice(objetos, func(i, j int) bool { return objetos[i].Clave < objetos[j].Clave })
+	for _, obj := range objetos {
+		if err := fn(obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetModificado cambia la fecha de modificación de un archivo (para probar antigüedades)
+func (m *Memoria) SetModificado(clave string, modificado time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if a, ok := m.archivos[clave]; ok {
+		a.obj.Modificado = modificado
+		m.archivos[clave] = a
+	}
+}
+
+func (m *Memoria) buscar(clave string) (archivoMemoria, error) {
+	clave, err := Clave(clave)
+	if err != nil {
+		return archivoMemoria{}, err
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	a, ok := m.archivos[clave]
+	if !ok {
+		return archivoMemoria{}, ErrNoEncontrado
+	}
+	return a, nil
+}
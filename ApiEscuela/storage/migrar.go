@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// OpcionesMigracion configura Migrar
+type OpcionesMigracion struct {
+	Prefijo        string // solo las claves que empiezan con este prefijo
+	Reemplazar     bool   // sobrescribir los archivos que ya existen en el destino
+	Simular        bool   // solo contar lo que se copiaría
+	EliminarOrigen bool   // borrar del origen cada archivo copiado
+}
+
+// ResultadoMigracion resume una migración
+type ResultadoMigracion struct {
+	Copiados   int
+	Omitidos   int // ya existían en el destino
+	Eliminados int
+	Bytes      int64
+}
+
+// Migrar copia los archivos de origen a destino. Los archivos que ya existen en el destino se omiten
+// salvo con Reemplazar; se puede volver a ejecutar después de una interrupción.
+func Migrar(ctx context.Context, origen, destino Storage, opts OpcionesMigracion) (*ResultadoMigracion, error) {
+	if origen.Nombre() == destino.Nombre() {
+		return nil, fmt.Errorf("el origen y el destino son el mismo backend (%s)", origen.Nombre())
+	}
+	resultado := &ResultadoMigracion{}
+	err := origen.Listar(ctx, opts.Prefijo, func(obj Objeto) error {
+		if !opts.Reemplazar {
+			_, err := destino.Info(ctx, obj.Clave)
+			if err == nil {
+				resultado.Omitidos++
+				return nil
+			}
+			if !errors.Is(err, ErrNoEncontrado) {
+				return fmt.Errorf("%s: %v", obj.Clave, err)
+			}
+		}
+		if !opts.Simular {
+			if err := copiar(ctx, origen, destino, obj); err != nil {
+				return fmt.Errorf("%s: %v", obj.Clave, err)
+			}
+			if opts.EliminarOrigen {
+				if err := origen.Eliminar(ctx, obj.Clave); err != nil {
+					return fmt.Errorf("%s: %v", obj.Clave, err)
+				}
+				resultado.Eliminados++
+			}
+		}
+		resultado.Copiados++
+		resultado.Bytes += obj.Tamano
+		return nil
+	})
+	return resultado, err
+}
+
+func copiar(ctx context.Context, origen, destino Storage, obj Objeto) error {
+	r, info, err := origen.Abrir(ctx, obj.Clave)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return destino.Guardar(ctx, obj.Clave, r, info.Tamano, info.ContentType)
+}
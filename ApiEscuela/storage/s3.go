@@ -0,0 +1,174 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Config configura un bucket compatible con S3 (AWS S3, MinIO)
+type S3Config struct {
+	Endpoint  string // host[:puerto] o URL, por ejemplo "localhost:9000" o "https://s3.amazonaws.com"
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	Prefijo   string // carpeta dentro del bucket; opcional
+	UsarSSL   bool
+}
+
+// tiempoConexion limita la verificación del bucket al crear el backend
+const tiempoConexion = 10 * time.Second
+
+// S3 guarda los archivos en un bucket compatible con S3
+type S3 struct {
+	client  *minio.Client
+	bucket  string
+	prefijo string
+}
+
+var _ Storage = (*S3)(nil)
+
+// NewS3 conecta con el bucket y lo crea si no existe
+func NewS3(cfg S3Config) (*S3, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, errors.New("S3_ENDPOINT y S3_BUCKET son requeridos")
+	}
+	endpoint, usarSSL := cfg.Endpoint, cfg.UsarSSL
+	if u, err := url.Parse(cfg.Endpoint); err == nil && u.Host != "" {
+		endpoint, usarSSL = u.Host, u.Scheme == "https"
+	}
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: usarSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("configuración de S3 inválida: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), tiempoConexion)
+	defer cancel()
+	existe, err := client.BucketExists(ctx, cfg.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo acceder al bucket %s: %v", cfg.Bucket, err)
+	}
+	if !existe {
+		if err := client.MakeBucket(ctx, cfg.Bucket, minio.MakeBucketOptions{Region: cfg.Region}); err != nil {
+			return nil, fmt.Errorf("no se pudo crear el bucket %s: %v", cfg.Bucket, err)
+		}
+	}
+
+	prefijo := strings.Trim(cfg.Prefijo, "/")
+	if prefijo != "" {
+		prefijo += "/"
+	}
+	return &S3{client: client, bucket: cfg.Bucket, prefijo: prefijo}, nil
+}
+
+// Nombre describe el backend
+func (s *S3) Nombre() string {
+	return DriverS3 + ":" + s.bucket + "/" + s.prefijo
+}
+
+// objeto valida la clave y devuelve el nombre del objeto en el bucket
+func (s *S3) objeto(clave string) (string, string, error) {
+	clave, err := Clave(clave)
+	if err != nil {
+		return "", "", err
+	}
+	return clave, s.prefijo + clave, nil
+}
+
+// Guardar sube el archivo; con tamano -1 se usa una subida multiparte
+func (s *S3) Guardar(ctx context.Context, clave string, r io.Reader, tamano int64, contentType string) error {
+	_, nombre, err := s.objeto(clave)
+	if err != nil {
+		return err
+	}
+	if contentType == "" {
+		contentType = ContentTypeDe(clave)
+	}
+	_, err = s.client.PutObject(ctx, s.bucket, nombre, r, tamano, minio.PutObjectOptions{ContentType: contentType})
+	return err
+}
+
+// Abrir descarga el archivo a medida que se lee
+func (s *S3) Abrir(ctx context.Context, clave string) (io.ReadCloser, *Objeto, error) {
+	clave, nombre, err := s.objeto(clave)
+	if err != nil {
+		return nil, nil, err
+	}
+	obj, err := s.client.GetObject(ctx, s.bucket, nombre, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, nil, errorS3(err)
+	}
+	// GetObject no consulta el servidor hasta la primera lectura; Stat confirma que existe
+	info, err := obj.Stat()
+	if err != nil {
+		obj.Close()
+		return nil, nil, errorS3(err)
+	}
+	return obj, objetoS3(clave, info), nil
+}
+
+// Info devuelve los datos del archivo
+func (s *S3) Info(ctx context.Context, clave string) (*Objeto, error) {
+	clave, nombre, err := s.objeto(clave)
+	if err != nil {
+		return nil, err
+	}
+	info, err := s.client.StatObject(ctx, s.bucket, nombre, minio.StatObjectOptions{})
+	if err != nil {
+		return nil, errorS3(err)
+	}
+	return objetoS3(clave, info), nil
+}
+
+// Eliminar borra el archivo
+func (s *S3) Eliminar(ctx context.Context, clave string) error {
+	_, nombre, err := s.objeto(clave)
+	if err != nil {
+		return err
+	}
+	return s.client.RemoveObject(ctx, s.bucket, nombre, minio.RemoveObjectOptions{})
+}
+
+// Listar recorre los archivos cuya clave empieza con prefijo
+func (s *S3) Listar(ctx context.Context, prefijo string, fn func(Objeto) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel() // detiene el listado si fn devuelve un error
+	for info := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: s.prefijo + prefijo, Recursive: true}) {
+		if info.Err != nil {
+			return info.Err
+		}
+		if err := fn(*objetoS3(strings.TrimPrefix(info.Key, s.prefijo), info)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func objetoS3(clave string, info minio.ObjectInfo) *Objeto {
+	contentType := info.ContentType
+	if contentType == "" {
+		contentType = ContentTypeDe(clave)
+	}
+	return &Objeto{Clave: clave, Tamano: info.Size, ContentType: contentType, Modificado: info.LastModified}
+}
+
+// errorS3 traduce los errores de objeto inexistente
+func errorS3(err error) error {
+	switch minio.ToErrorResponse(err).Code {
+	case "NoSuchKey", "NotFound":
+		return ErrNoEncontrado
+	}
+	return err
+}
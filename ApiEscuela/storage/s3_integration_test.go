@@ -0,0 +1,43 @@
+//go:build integration
+
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+// TestS3 prueba el driver contra un MinIO local, por ejemplo:
+//
+//	docker run -d -p 9000:9000 minio/minio server /data
+//	STORAGE_TEST_S3_ENDPOINT=http://localhost:9000 go test -tags integration ./storage
+func TestS3(t *testing.T) {
+	endpoint := os.Getenv("STORAGE_TEST_S3_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("STORAGE_TEST_S3_ENDPOINT no está definida")
+	}
+	usuario, clave := os.Getenv("STORAGE_TEST_S3_ACCESS_KEY"), os.Getenv("STORAGE_TEST_S3_SECRET_KEY")
+	if usuario == "" {
+		usuario, clave = "minioadmin", "minioadmin"
+	}
+
+	s, err := NewS3(S3Config{
+		Endpoint:  endpoint,
+		Bucket:    "apiescuela-test",
+		AccessKey: usuario,
+		SecretKey: clave,
+		// Cada ejecución usa su propia carpeta para no ver archivos de ejecuciones anteriores
+		Prefijo: fmt.Sprintf("prueba-%d", time.Now().UnixNano()),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		ctx := context.Background()
+		s.Listar(ctx, "", func(o Objeto) error { return s.Eliminar(ctx, o.Clave) })
+	})
+	probarBackend(t, s)
+}
@@ -0,0 +1,136 @@
+// Package storage guarda los archivos subidos (imágenes, videos, documentos y adjuntos de
+// comunicados) en un backend intercambiable: una carpeta local o un bucket compatible con S3
+// (AWS S3, MinIO).
+//
+// Los archivos se identifican por una clave con la misma forma que la ruta que los sirve,
+// /api/files/{clave}: por ejemplo "images/1700000000_1234.jpg" o
+// "comunicados_files/2024-05-01_10-00-00/acta.pdf".
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNoEncontrado se devuelve cuando la clave no existe en el backend
+var ErrNoEncontrado = errors.New("archivo no encontrado")
+
+// ErrClaveInvalida se devuelve cuando la clave está vacía o sale de la raíz del backend
+var ErrClaveInvalida = errors.New("clave de archivo inválida")
+
+// Objeto describe un archivo guardado
+type Objeto struct {
+	Clave       string
+	Tamano      int64
+	ContentType string
+	Modificado  time.Time
+}
+
+// Storage es un backend de archivos
+type Storage interface {
+	// Guardar escribe el contenido de r en la clave; tamano puede ser -1 si se desconoce
+	Guardar(ctx context.Context, clave string, r io.Reader, tamano int64, contentType string) error
+	// Abrir devuelve el contenido de la clave; el llamador debe cerrarlo
+	Abrir(ctx context.Context, clave string) (io.ReadCloser, *Objeto, error)
+	// Info devuelve los datos de la clave sin leer su contenido
+	Info(ctx context.Context, clave string) (*Objeto, error)
+	// Eliminar borra la clave; no es un error si no existe
+	Eliminar(ctx context.Context, clave string) error
+	// Listar recorre en orden las claves que empiezan con prefijo
+	Listar(ctx context.Context, prefijo string, fn func(Objeto) error) error
+	// Nombre describe el backend para los mensajes (por ejemplo "local:assets" o "s3:uploads")
+	Nombre() string
+}
+
+// Clave une y normaliza las partes de una clave. Rechaza claves vacías, absolutas o con "..".
+func Clave(partes ...string) (string, error) {
+	clave := path.Clean(strings.ReplaceAll(path.Join(partes...), "\\", "/"))
+	if clave == "." || clave == "" || strings.HasPrefix(clave, "/") || clave == ".." || strings.HasPrefix(clave, "../") {
+		return "", ErrClaveInvalida
+	}
+	return clave, nil
+}
+
+// ClaveDesdeURL convierte una URL servida por /api/files/... (absoluta o relativa) en su clave
+func ClaveDesdeURL(url string) (string, bool) {
+	i := strings.Index(url, "/api/files/")
+	if i < 0 {
+		return "", false
+	}
+	clave, err := Clave(strings.TrimPrefix(url[i:], "/api/files/"))
+	if err != nil {
+		return "", false
+	}
+	return clave, true
+}
+
+// ContentTypeDe deduce el tipo de contenido por la extensión de la clave
+func ContentTypeDe(clave string) string {
+	if ct := mime.TypeByExtension(strings.ToLower(path.Ext(clave))); ct != "" {
+		return ct
+	}
+	return "application/octet-stream"
+}
+
+// Drivers disponibles
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+// Config selecciona y configura el backend
+type Config struct {
+	Driver   string // DriverLocal (predeterminado) o DriverS3
+	DirLocal string // carpeta del driver local; por defecto "assets"
+	S3       S3Config
+}
+
+// ConfigDesdeEntorno lee la configuración de las variables STORAGE_* y S3_*
+func ConfigDesdeEntorno() Config {
+	usarSSL := true
+	if v := os.Getenv("S3_USE_SSL"); v != "" {
+		usarSSL, _ = strconv.ParseBool(v)
+	}
+	return Config{
+		Driver:   os.Getenv("STORAGE_DRIVER"),
+		DirLocal: os.Getenv("STORAGE_LOCAL_DIR"),
+		S3: S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			Prefijo:   os.Getenv("S3_PREFIX"),
+			UsarSSL:   usarSSL,
+		},
+	}
+}
+
+// New crea el backend indicado por la configuración
+func New(cfg Config) (Storage, error) {
+	switch cfg.Driver {
+	case "", DriverLocal:
+		dir := cfg.DirLocal
+		if dir == "" {
+			dir = "assets"
+		}
+		return NewLocal(dir), nil
+	case DriverS3:
+		return NewS3(cfg.S3)
+	default:
+		return nil, fmt.Errorf("STORAGE_DRIVER desconocido: %q (use %q o %q)", cfg.Driver, DriverLocal, DriverS3)
+	}
+}
+
+// DesdeEntorno crea el backend configurado en las variables de entorno
+func DesdeEntorno() (Storage, error) {
+	return New(ConfigDesdeEntorno())
+}
@@ -0,0 +1,166 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// probarBackend verifica el contrato de Storage; lo usan todos los drivers
+func probarBackend(t *testing.T, s Storage) {
+	t.Helper()
+	ctx := context.Background()
+
+	guardar := func(clave, contenido string) {
+		t.Helper()
+		if err := s.Guardar(ctx, clave, strings.NewReader(contenido), int64(len(contenido)), ""); err != nil {
+			t.Fatalf("Guardar(%s): %v", clave, err)
+		}
+	}
+	guardar("images/logo.png", "png")
+	guardar("comunicados_files/2024-05-01_10-00-00/acta final.pdf", "pdf")
+	guardar("documents/guia.pdf", "documento")
+	guardar("documents/guia.pdf", "guía v2") // sobrescribe
+
+	r, obj, err := s.Abrir(ctx, "documents/guia.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	datos, _ := io.ReadAll(r)
+	r.Close()
+	if string(datos) != "guía v2" || obj.Tamano != int64(len("guía v2")) || obj.ContentType != "application/pdf" {
+		t.Errorf("Abrir = %q %+v", datos, obj)
+	}
+
+	if _, _, err := s.Abrir(ctx, "images/no-existe.png"); !errors.Is(err, ErrNoEncontrado) {
+		t.Errorf("Abrir inexistente = %v, se esperaba ErrNoEncontrado", err)
+	}
+	if _, err := s.Info(ctx, "images/no-existe.png"); !errors.Is(err, ErrNoEncontrado) {
+		t.Errorf("Info inexistente = %v, se esperaba ErrNoEncontrado", err)
+	}
+	if _, err := s.Info(ctx, "../config.env"); !errors.Is(err, ErrClaveInvalida) {
+		t.Errorf("Info fuera de la raíz = %v, se esperaba ErrClaveInvalida", err)
+	}
+
+	var claves []string
+	err = s.Listar(ctx, "", func(o Objeto) error {
+		claves = append(claves, o.Clave)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "comunicados_files/2024-05-01_10-00-00/acta final.pdf,documents/guia.pdf,images/logo.png"
+	if strings.Join(claves, ",") != want {
+		t.Errorf("Listar = %v", claves)
+	}
+	claves = nil
+	s.Listar(ctx, "comunicados_files/", func(o Objeto) error {
+		claves = append(claves, o.Clave)
+		return nil
+	})
+	if len(claves) != 1 {
+		t.Errorf("Listar con prefijo = %v", claves)
+	}
+
+	if err := s.Eliminar(ctx, "comunicados_files/2024-05-01_10-00-00/acta final.pdf"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Eliminar(ctx, "comunicados_files/2024-05-01_10-00-00/acta final.pdf"); err != nil {
+		t.Errorf("eliminar dos veces no debe fallar: %v", err)
+	}
+	if _, err := s.Info(ctx, "comunicados_files/2024-05-01_10-00-00/acta final.pdf"); !errors.Is(err, ErrNoEncontrado) {
+		t.Errorf("Info después de eliminar = %v", err)
+	}
+}
+
+func TestLocal(t *testing.T) {
+	dir := t.TempDir()
+	probarBackend(t, NewLocal(dir))
+
+	if _, err := os.Stat(filepath.Join(dir, "comunicados_files", "2024-05-01_10-00-00")); !os.IsNotExist(err) {
+		t.Error("la carpeta vacía del comunicado debería eliminarse")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "comunicados_files")); err != nil {
+		t.Error("las carpetas de primer nivel se conservan")
+	}
+}
+
+func TestMemoria(t *testing.T) {
+	probarBackend(t, NewMemoria())
+}
+
+func TestClave(t *testing.T) {
+	tests := []struct {
+		partes []string
+		want   string
+		err    bool
+	}{
+		{partes: []string{"images", "a.png"}, want: "images/a.png"},
+		{partes: []string{"comunicados_files", "2024", "acta final.pdf"}, want: "comunicados_files/2024/acta final.pdf"},
+		{partes: []string{"images/./b/../a.png"}, want: "images/a.png"},
+		{partes: []string{"images", "..", "..", "config.env"}, err: true},
+		{partes: []string{"/etc/passwd"}, err: true},
+		{partes: []string{`..\config.env`}, err: true},
+		{partes: []string{""}, err: true},
+	}
+	for _, tt := range tests {
+		got, err := Clave(tt.partes...)
+		if (err != nil) != tt.err || got != tt.want {
+			t.Errorf("Clave(%q) = %q, %v", tt.partes, got, err)
+		}
+	}
+
+	if clave, ok := ClaveDesdeURL("https://aplicaciones.uteq.edu.ec:9612/api/files/images/a.png"); !ok || clave != "images/a.png" {
+		t.Errorf("ClaveDesdeURL absoluta = %q, %v", clave, ok)
+	}
+	if _, ok := ClaveDesdeURL("/api/files/../../main.go"); ok {
+		t.Error("ClaveDesdeURL debe rechazar rutas fuera de la raíz")
+	}
+}
+
+func TestMigrar(t *testing.T) {
+	ctx := context.Background()
+	origen := NewLocal(t.TempDir())
+	destino := NewMemoria()
+	for _, clave := range []string{"images/a.png", "images/b.png", "videos/c.mp4"} {
+		if err := origen.Guardar(ctx, clave, strings.NewReader(clave), -1, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	destino.Guardar(ctx, "images/a.png", strings.NewReader("ya migrado"), -1, "")
+
+	simulado, err := Migrar(ctx, origen, destino, OpcionesMigracion{Simular: true})
+	if err != nil || simulado.Copiados != 2 || simulado.Omitidos != 1 {
+		t.Fatalf("simulación = %+v, %v", simulado, err)
+	}
+	if _, err := destino.Info(ctx, "videos/c.mp4"); !errors.Is(err, ErrNoEncontrado) {
+		t.Error("la simulación no debe copiar")
+	}
+
+	res, err := Migrar(ctx, origen, destino, OpcionesMigracion{EliminarOrigen: true})
+	if err != nil || res.Copiados != 2 || res.Omitidos != 1 || res.Eliminados != 2 {
+		t.Fatalf("migración = %+v, %v", res, err)
+	}
+	r, _, err := destino.Abrir(ctx, "videos/c.mp4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if datos, _ := io.ReadAll(r); string(datos) != "videos/c.mp4" {
+		t.Errorf("contenido migrado = %q", datos)
+	}
+	if _, err := origen.Info(ctx, "images/b.png"); !errors.Is(err, ErrNoEncontrado) {
+		t.Error("EliminarOrigen debe borrar los archivos copiados")
+	}
+	if _, err := origen.Info(ctx, "images/a.png"); err != nil {
+		t.Error("los archivos omitidos se conservan en el origen")
+	}
+
+	if _, err := Migrar(ctx, origen, origen, OpcionesMigracion{}); err == nil {
+		t.Error("migrar un backend sobre sí mismo debe fallar")
+	}
+}
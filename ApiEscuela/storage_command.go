@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"ApiEscuela/repositories"
+	"ApiEscuela/services"
+	"ApiEscuela/storage"
+
+	"gorm.io/gorm"
+)
+
+// migrarArchivos copia los archivos de una carpeta local al almacenamiento configurado
+// (STORAGE_DRIVER), por ejemplo para pasar los assets existentes a S3
+func migrarArchivos(db *gorm.DB, args []string) error {
+	fs := flag.NewFlagSet("files migrate", flag.ContinueOnError)
+	origenDir := fs.String("origen", "assets", "carpeta local con los archivos a migrar")
+	var opts storage.OpcionesMigracion
+	fs.StringVar(&opts.Prefijo, "prefijo", "", "migrar solo las claves que empiezan con este prefijo (por ejemplo images/)")
+	fs.BoolVar(&opts.Reemplazar, "reemplazar", false, "sobrescribir los archivos que ya existen en el destino")
+	fs.BoolVar(&opts.Simular, "simular", false, "mostrar lo que se copiaría sin copiar")
+	fs.BoolVar(&opts.EliminarOrigen, "eliminar-origen", false, "borrar cada archivo local después de copiarlo")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	destino, err := storage.DesdeEntorno()
+	if err != nil {
+		return err
+	}
+	origen := storage.NewLocal(*origenDir)
+	resultado, err := storage.Migrar(context.Background(), origen, destino, opts)
+	if resultado != nil {
+		verbo := "copiados"
+		if opts.Simular {
+			verbo = "por copiar"
+		}
+		fmt.Printf("%s -> %s: %d archivos %s (%d bytes), %d ya existían", origen.Nombre(), destino.Nombre(), resultado.Copiados, verbo, resultado.Bytes, resultado.Omitidos)
+		if opts.EliminarOrigen {
+			fmt.Printf(", %d eliminados del origen", resultado.Eliminados)
+		}
+		fmt.Println()
+	}
+	return err
+}
+
+// generarVariantes genera las variantes redimensionadas y el BlurHash de las imágenes subidas
+// antes de que existiera el procesamiento al subir
+func generarVariantes(db *gorm.DB, args []string) error {
+	fs := flag.NewFlagSet("files variants", flag.ContinueOnError)
+	var opts services.OpcionesVariantes
+	fs.StringVar(&opts.Prefijo, "prefijo", "images/", "carpeta del almacenamiento a recorrer")
+	fs.BoolVar(&opts.Reemplazar, "reemplazar", false, "volver a procesar las imágenes que ya tienen variantes")
+	fs.BoolVar(&opts.Simular, "simular", false, "mostrar cuántas imágenes se procesarían sin procesarlas")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	archivos, err := storage.DesdeEntorno()
+	if err != nil {
+		return err
+	}
+	mediaService := services.NewMediaService(repositories.NewMediaRepository(db), archivos)
+	resultado, err := mediaService.GenerarVariantes(context.Background(), opts)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range resultado.Errores {
+		fmt.Printf("Error: %s\n", e)
+	}
+	verbo := "procesadas"
+	if opts.Simular {
+		verbo = "por procesar"
+	}
+	fmt.Printf("%s, %s: %d imágenes %s (%d variantes), %d ya tenían variantes, %d con errores\n",
+		archivos.Nombre(), opts.Prefijo, resultado.Procesadas, verbo, resultado.Variantes, resultado.Omitidas, resultado.Fallidas)
+	if resultado.Fallidas > 0 {
+		return fmt.Errorf("%d imágenes no se pudieron procesar", resultado.Fallidas)
+	}
+	return nil
+}
+
+// indexarArchivos agrega a la biblioteca de archivos los subidos antes de que existiera
+func indexarArchivos(db *gorm.DB, args []string) error {
+	fs := flag.NewFlagSet("files index", flag.ContinueOnError)
+	simular := fs.Bool("simular", false, "mostrar cuántos archivos se indexarían sin indexarlos")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	archivos, err := storage.DesdeEntorno()
+	if err != nil {
+		return err
+	}
+	mediaService := services.NewMediaService(repositories.NewMediaRepository(db), archivos)
+	resultado, err := mediaService.IndexarArchivos(context.Background(), *simular)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range resultado.Errores {
+		fmt.Printf("Error: %s\n", e)
+	}
+	verbo := "indexados"
+	if *simular {
+		verbo = "por indexar"
+	}
+	fmt.Printf("%s: %d archivos %s, %d ya estaban en la biblioteca, %d con errores\n",
+		archivos.Nombre(), resultado.Indexados, verbo, resultado.Omitidos, resultado.Fallidos)
+	if resultado.Fallidos > 0 {
+		return fmt.Errorf("%d archivos no se pudieron indexar", resultado.Fallidos)
+	}
+	return nil
+}
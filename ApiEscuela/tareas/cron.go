@@ -0,0 +1,157 @@
+package tareas
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Programacion calcula cuándo corresponde ejecutar una tarea
+type Programacion interface {
+	// Siguiente devuelve la primera hora de ejecución posterior a t (cero si no hay ninguna)
+	Siguiente(t time.Time) time.Time
+}
+
+// atajos equivalentes a expresiones cron
+var atajos = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParsearProgramacion interpreta una expresión cron de cinco campos (minuto hora día-del-mes mes
+// día-de-la-semana, con *, listas, rangos y pasos), uno de los atajos @hourly, @daily, @weekly,
+// @monthly o @yearly, o "@every <duración>" (por ejemplo "@every 90m")
+func ParsearProgramacion(expresion string) (Programacion, error) {
+	expresion = strings.TrimSpace(expresion)
+	if d, ok := strings.CutPrefix(expresion, "@every "); ok {
+		intervalo, err := time.ParseDuration(strings.TrimSpace(d))
+		if err != nil || intervalo <= 0 {
+			return nil, fmt.Errorf("intervalo inválido en %q", expresion)
+		}
+		return cadaIntervalo(intervalo), nil
+	}
+	if equivalente, ok := atajos[expresion]; ok {
+		expresion = equivalente
+	}
+
+	campos := strings.Fields(expresion)
+	if len(campos) != 5 {
+		return nil, fmt.Errorf("la expresión cron %q debe tener 5 campos", expresion)
+	}
+	limites := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	var c cron
+	conjuntos := []*uint64{&c.minutos, &c.horas, &c.dias, &c.meses, &c.diasSemana}
+	for i, campo := range campos {
+		bits, err := parsearCampo(campo, limites[i][0], limites[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("campo %d de %q: %v", i+1, expresion, err)
+		}
+		*conjuntos[i] = bits
+	}
+	// 7 también es domingo
+	if c.diasSemana&(1<<7) != 0 {
+		c.diasSemana |= 1
+	}
+	c.diaLibre = strings.HasPrefix(campos[2], "*")
+	c.semanaLibre = strings.HasPrefix(campos[4], "*")
+	return c, nil
+}
+
+// parsearCampo convierte un campo cron en un conjunto de bits con los valores permitidos
+func parsearCampo(campo string, min, max int) (uint64, error) {
+	var bits uint64
+	for _, parte := range strings.Split(campo, ",") {
+		rango, paso := parte, 1
+		if r, p, ok := strings.Cut(parte, "/"); ok {
+			n, err := strconv.Atoi(p)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("paso inválido %q", p)
+			}
+			rango, paso = r, n
+		}
+
+		desde, hasta := min, max
+		switch {
+		case rango == "*":
+		case strings.Contains(rango, "-"):
+			a, b, _ := strings.Cut(rango, "-")
+			var errA, errB error
+			desde, errA = strconv.Atoi(a)
+			hasta, errB = strconv.Atoi(b)
+			if errA != nil || errB != nil {
+				return 0, fmt.Errorf("rango inválido %q", rango)
+			}
+		default:
+			n, err := strconv.Atoi(rango)
+			if err != nil {
+				return 0, fmt.Errorf("valor inválido %q", rango)
+			}
+			desde, hasta = n, n
+			// "5/15" equivale a "5-max/15"
+			if paso > 1 {
+				hasta = max
+			}
+		}
+		if desde < min || hasta > max || desde > hasta {
+			return 0, fmt.Errorf("%q fuera del rango %d-%d", parte, min, max)
+		}
+		for v := desde; v <= hasta; v += paso {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// cron es una expresión cron ya interpretada, con un bit por valor permitido de cada campo
+type cron struct {
+	minutos, horas, dias, meses, diasSemana uint64
+	// Si día del mes y día de la semana están restringidos, basta con que coincida uno (como en cron)
+	diaLibre, semanaLibre bool
+}
+
+func (c cron) Siguiente(t time.Time) time.Time {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limite := t.AddDate(5, 0, 0)
+	for t.Before(limite) {
+		if c.meses&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.coincideDia(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if c.horas&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if c.minutos&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (c cron) coincideDia(t time.Time) bool {
+	dia := c.dias&(1<<uint(t.Day())) != 0
+	semana := c.diasSemana&(1<<uint(t.Weekday())) != 0
+	if c.diaLibre || c.semanaLibre {
+		return dia && semana
+	}
+	return dia || semana
+}
+
+// cadaIntervalo se ejecuta cada cierto tiempo contado desde la revisión anterior
+type cadaIntervalo time.Duration
+
+func (d cadaIntervalo) Siguiente(t time.Time) time.Time {
+	return t.Add(time.Duration(d))
+}
@@ -0,0 +1,90 @@
+package tareas
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sync"
+)
+
+// ClaveBloqueo es la clave del bloqueo asesor de Postgres que identifica a la instancia líder
+const ClaveBloqueo int64 = 0x41504945534355 // "APIESCU"
+
+// Eleccion decide qué instancia ejecuta las tareas programadas cuando hay varias réplicas
+type Eleccion interface {
+	// EsLider confirma el liderazgo o intenta obtenerlo si nadie lo tiene
+	EsLider(ctx context.Context) bool
+	// Renunciar libera el liderazgo para que otra instancia lo tome
+	Renunciar()
+}
+
+// SiempreLider es la elección de una única instancia (y de las pruebas): siempre es líder
+func SiempreLider() Eleccion {
+	return siempreLider{}
+}
+
+type siempreLider struct{}
+
+func (siempreLider) EsLider(context.Context) bool { return true }
+func (siempreLider) Renunciar()                   {}
+
+// bloqueoAsesor elige al líder con pg_try_advisory_lock. El bloqueo pertenece a la sesión, así
+// que se reserva una conexión del pool mientras se es líder; si la conexión se pierde, Postgres
+// libera el bloqueo y otra instancia lo obtiene en su siguiente revisión.
+type bloqueoAsesor struct {
+	db    *sql.DB
+	clave int64
+
+	mu   sync.Mutex
+	conn *sql.Conn
+}
+
+// NewBloqueoAsesor crea una elección por bloqueo asesor de Postgres con la clave indicada
+func NewBloqueoAsesor(db *sql.DB, clave int64) Eleccion {
+	return &bloqueoAsesor{db: db, clave: clave}
+}
+
+func (b *bloqueoAsesor) EsLider(ctx context.Context) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.conn != nil {
+		_, err := b.conn.ExecContext(ctx, "SELECT 1")
+		if err == nil {
+			return true
+		}
+		log.Printf("Se perdió la conexión del bloqueo de tareas programadas: %v", err)
+		b.conn.Close()
+		b.conn = nil
+	}
+
+	conn, err := b.db.Conn(ctx)
+	if err != nil {
+		log.Printf("Error al obtener una conexión para el bloqueo de tareas programadas: %v", err)
+		return false
+	}
+	var obtenido bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", b.clave).Scan(&obtenido); err != nil {
+		log.Printf("Error al intentar el bloqueo de tareas programadas: %v", err)
+		obtenido = false
+	}
+	if !obtenido {
+		conn.Close()
+		return false
+	}
+	b.conn = conn
+	return true
+}
+
+func (b *bloqueoAsesor) Renunciar() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.conn == nil {
+		return
+	}
+	if _, err := b.conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", b.clave); err != nil {
+		log.Printf("Error al liberar el bloqueo de tareas programadas: %v", err)
+	}
+	b.conn.Close()
+	b.conn = nil
+}
@@ -0,0 +1,50 @@
+//go:build integration
+
+package tareas_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"ApiEscuela/tareas"
+	"ApiEscuela/testutil"
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(testutil.RunMain(m))
+}
+
+func TestBloqueoAsesor(t *testing.T) {
+	db := testutil.PostgresDB(t)
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	const clave = tareas.ClaveBloqueo + 1 // no interferir con un servidor que use la misma base
+
+	// Dos réplicas sobre la misma base: solo una puede ser líder a la vez
+	a := tareas.NewBloqueoAsesor(sqlDB, clave)
+	b := tareas.NewBloqueoAsesor(sqlDB, clave)
+	defer a.Renunciar()
+	defer b.Renunciar()
+
+	if !a.EsLider(ctx) {
+		t.Fatal("la primera instancia debía obtener el liderazgo")
+	}
+	if b.EsLider(ctx) {
+		t.Fatal("la segunda instancia no debía obtener el liderazgo")
+	}
+	if !a.EsLider(ctx) {
+		t.Fatal("el líder debía conservar el liderazgo")
+	}
+
+	a.Renunciar()
+	if !b.EsLider(ctx) {
+		t.Fatal("la segunda instancia debía tomar el relevo")
+	}
+	if a.EsLider(ctx) {
+		t.Fatal("la primera instancia ya no debía ser líder")
+	}
+}
@@ -0,0 +1,301 @@
+// Package tareas ejecuta tareas periódicas de mantenimiento y notificación dentro del servidor.
+//
+// Cada tarea tiene una programación cron. Con varias réplicas, solo la instancia líder (la que
+// tiene el bloqueo asesor de Postgres, ver NewBloqueoAsesor) ejecuta las tareas programadas;
+// las demás siguen calculando las próximas horas para tomar el relevo si el líder cae. Cada
+// ejecución, programada o manual, queda en el historial (models.EjecucionTarea).
+package tareas
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"ApiEscuela/models"
+	"ApiEscuela/repositories"
+
+	"gorm.io/gorm"
+)
+
+// Disparos de una ejecución
+const (
+	DisparoProgramado = "programado"
+	DisparoManual     = "manual"
+)
+
+// Revision es cada cuánto se revisan las tareas pendientes y el liderazgo
+const Revision = 15 * time.Second
+
+var (
+	// ErrTareaNoEncontrada se devuelve al pedir una tarea que no está registrada
+	ErrTareaNoEncontrada = errors.New("tarea no encontrada")
+	// ErrTareaEnCurso se devuelve al ejecutar manualmente una tarea que ya se está ejecutando
+	ErrTareaEnCurso = errors.New("la tarea ya se está ejecutando")
+)
+
+// Tarea es un trabajo periódico. Ejecutar devuelve un resumen de lo que hizo para el historial.
+type Tarea struct {
+	Nombre       string
+	Descripcion  string
+	Programacion string // expresión cron, ver ParsearProgramacion
+	Ejecutar     func(ctx context.Context) (string, error)
+}
+
+// Estado resume una tarea registrada para los administradores
+type Estado struct {
+	Nombre          string                 `json:"nombre"`
+	Descripcion     string                 `json:"descripcion"`
+	Programacion    string                 `json:"programacion"`
+	Proxima         time.Time              `json:"proxima"`
+	EnCurso         bool                   `json:"en_curso"`
+	UltimaEjecucion *models.EjecucionTarea `json:"ultima_ejecucion"`
+	UltimoError     *models.EjecucionTarea `json:"ultimo_error"`
+}
+
+// tareaRegistrada es una tarea con su programación interpretada y su estado en esta instancia
+type tareaRegistrada struct {
+	Tarea
+	programacion Programacion
+	proxima      time.Time
+	enCurso      bool
+}
+
+// Programador registra las tareas y las ejecuta según su programación
+type Programador struct {
+	historial repositories.EjecucionTareaRepository
+	eleccion  Eleccion
+	instancia string
+
+	mu     sync.Mutex
+	tareas map[string]*tareaRegistrada
+	lider  bool
+	ctx    context.Context // contexto de las ejecuciones; se cancela al detener el programador
+	wg     sync.WaitGroup
+}
+
+// New crea un programador que guarda el historial en el repositorio indicado
+func New(historial repositories.EjecucionTareaRepository, eleccion Eleccion) *Programador {
+	return &Programador{
+		historial: historial,
+		eleccion:  eleccion,
+		instancia: Instancia(),
+		tareas:    map[string]*tareaRegistrada{},
+		ctx:       context.Background(),
+	}
+}
+
+// Instancia identifica a este proceso en el historial (host y PID)
+func Instancia() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "desconocido"
+	}
+	return fmt.Sprintf("%s:%d", host, os.Getpid())
+}
+
+// Registrar agrega una tarea; debe llamarse antes de Iniciar
+func (p *Programador) Registrar(t Tarea) error {
+	if t.Nombre == "" || t.Ejecutar == nil {
+		return errors.New("la tarea necesita nombre y función")
+	}
+	programacion, err := ParsearProgramacion(t.Programacion)
+	if err != nil {
+		return fmt.Errorf("tarea %s: %v", t.Nombre, err)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.tareas[t.Nombre]; ok {
+		return fmt.Errorf("la tarea %s ya está registrada", t.Nombre)
+	}
+	p.tareas[t.Nombre] = &tareaRegistrada{Tarea: t, programacion: programacion, proxima: programacion.Siguiente(time.Now())}
+	return nil
+}
+
+// Iniciar revisa las tareas cada Revision hasta que se cancele ctx. Al terminar renuncia al
+// liderazgo y espera las ejecuciones en curso.
+func (p *Programador) Iniciar(ctx context.Context) {
+	p.mu.Lock()
+	p.ctx = ctx
+	p.mu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(Revision)
+		defer ticker.Stop()
+		p.revisar(ctx, time.Now())
+		for {
+			select {
+			case <-ctx.Done():
+				p.eleccion.Renunciar()
+				p.wg.Wait()
+				return
+			case ahora := <-ticker.C:
+				p.revisar(ctx, ahora)
+			}
+		}
+	}()
+}
+
+// revisar ejecuta, si esta instancia es la líder, las tareas cuya hora ya llegó
+func (p *Programador) revisar(ctx context.Context, ahora time.Time) {
+	lider := p.eleccion.EsLider(ctx)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if lider != p.lider {
+		if lider {
+			log.Printf("Esta instancia (%s) ejecutará las tareas programadas", p.instancia)
+		} else {
+			log.Printf("Esta instancia (%s) dejó de ejecutar las tareas programadas", p.instancia)
+		}
+		p.lider = lider
+	}
+	for _, t := range p.tareas {
+		if t.proxima.IsZero() || ahora.Before(t.proxima) {
+			continue
+		}
+		t.proxima = t.programacion.Siguiente(ahora)
+		if !lider {
+			continue
+		}
+		if t.enCurso {
+			log.Printf("Tarea %s omitida: la ejecución anterior no ha terminado", t.Nombre)
+			continue
+		}
+		if _, err := p.lanzar(t, DisparoProgramado); err != nil {
+			log.Printf("Error al iniciar la tarea %s: %v", t.Nombre, err)
+		}
+	}
+}
+
+// Ejecutar inicia ya una ejecución manual de la tarea en esta instancia y la devuelve en curso
+func (p *Programador) Ejecutar(nombre string) (*models.EjecucionTarea, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	t, ok := p.tareas[nombre]
+	if !ok {
+		return nil, ErrTareaNoEncontrada
+	}
+	if t.enCurso {
+		return nil, ErrTareaEnCurso
+	}
+	return p.lanzar(t, DisparoManual)
+}
+
+// lanzar registra el inicio de la ejecución y la corre en segundo plano. p.mu debe estar bloqueado.
+func (p *Programador) lanzar(t *tareaRegistrada, disparo string) (*models.EjecucionTarea, error) {
+	ejecucion := &models.EjecucionTarea{
+		Tarea:     t.Nombre,
+		Disparo:   disparo,
+		Instancia: p.instancia,
+		Inicio:    time.Now(),
+		Estado:    repositories.EjecucionEnCurso,
+	}
+	if err := p.historial.CreateEjecucion(ejecucion); err != nil {
+		return nil, fmt.Errorf("error al registrar la ejecución: %v", err)
+	}
+	t.enCurso = true
+	p.wg.Add(1)
+	copia := *ejecucion
+	go p.correr(p.ctx, t, ejecucion)
+	return &copia, nil
+}
+
+// correr ejecuta la tarea y guarda el resultado en el historial
+func (p *Programador) correr(ctx context.Context, t *tareaRegistrada, ejecucion *models.EjecucionTarea) {
+	defer p.wg.Done()
+
+	resultado, err := func() (resultado string, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("pánico: %v", r)
+			}
+		}()
+		return t.Ejecutar(ctx)
+	}()
+
+	fin := time.Now()
+	ejecucion.Fin = &fin
+	ejecucion.Resultado = resultado
+	ejecucion.Estado = repositories.EjecucionExito
+	if err != nil {
+		ejecucion.Estado = repositories.EjecucionError
+		ejecucion.Error = err.Error()
+		log.Printf("Error en la tarea %s: %v", t.Nombre, err)
+	}
+	if err := p.historial.UpdateEjecucion(ejecucion); err != nil {
+		log.Printf("Error al guardar la ejecución de la tarea %s: %v", t.Nombre, err)
+	}
+
+	p.mu.Lock()
+	t.enCurso = false
+	p.mu.Unlock()
+}
+
+// Esperar bloquea hasta que terminen las ejecuciones en curso
+func (p *Programador) Esperar() {
+	p.wg.Wait()
+}
+
+// EsLider indica si esta instancia ejecuta las tareas programadas (según la última revisión)
+func (p *Programador) EsLider() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.lider
+}
+
+// InstanciaActual identifica a esta instancia en el historial
+func (p *Programador) InstanciaActual() string {
+	return p.instancia
+}
+
+// Estados devuelve las tareas registradas, ordenadas por nombre, con su última ejecución y su último error
+func (p *Programador) Estados() ([]Estado, error) {
+	p.mu.Lock()
+	estados := make([]Estado, 0, len(p.tareas))
+	for _, t := range p.tareas {
+		estados = append(estados, Estado{
+			Nombre:       t.Nombre,
+			Descripcion:  t.Descripcion,
+			Programacion: t.Programacion,
+			Proxima:      t.proxima,
+			EnCurso:      t.enCurso,
+		})
+	}
+	p.mu.Unlock()
+	sort.Slice(estados, func(i, j int) bool { return estados[i].Nombre < estados[j].Nombre })
+
+	for i := range estados {
+		var err error
+		if estados[i].UltimaEjecucion, err = p.ultima(p.historial.GetUltimaEjecucion(estados[i].Nombre)); err != nil {
+			return nil, err
+		}
+		if estados[i].UltimoError, err = p.ultima(p.historial.GetUltimoError(estados[i].Nombre)); err != nil {
+			return nil, err
+		}
+	}
+	return estados, nil
+}
+
+// ultima trata la ausencia de ejecuciones como nil
+func (p *Programador) ultima(ejecucion *models.EjecucionTarea, err error) (*models.EjecucionTarea, error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return ejecucion, err
+}
+
+// Ejecuciones devuelve el historial de una tarea, de la ejecución más reciente a la más antigua
+func (p *Programador) Ejecuciones(nombre string, limite int) ([]models.EjecucionTarea, error) {
+	p.mu.Lock()
+	_, ok := p.tareas[nombre]
+	p.mu.Unlock()
+	if !ok {
+		return nil, ErrTareaNoEncontrada
+	}
+	return p.historial.GetEjecucionesByTarea(nombre, limite)
+}
@@ -0,0 +1,161 @@
+package tareas
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"ApiEscuela/repositories"
+	"ApiEscuela/repositories/memory"
+)
+
+func TestParsearProgramacion(t *testing.T) {
+	base := time.Date(2026, 3, 14, 10, 7, 30, 0, time.UTC) // sábado
+	casos := []struct {
+		expresion string
+		siguiente time.Time
+	}{
+		{"*/5 * * * *", time.Date(2026, 3, 14, 10, 10, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2026, 3, 14, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"30 8 * * 1-5", time.Date(2026, 3, 16, 8, 30, 0, 0, time.UTC)},
+		{"0 9 1,15 * *", time.Date(2026, 3, 15, 9, 0, 0, 0, time.UTC)},
+		{"0 0 31 * *", time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * * 7", time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)},
+		// Con día del mes y de la semana restringidos basta con que coincida uno
+		{"0 6 20 * 1", time.Date(2026, 3, 16, 6, 0, 0, 0, time.UTC)},
+		{"@every 90m", base.Add(90 * time.Minute)},
+	}
+	for _, c := range casos {
+		p, err := ParsearProgramacion(c.expresion)
+		if err != nil {
+			t.Errorf("%q: %v", c.expresion, err)
+			continue
+		}
+		if got := p.Siguiente(base); !got.Equal(c.siguiente) {
+			t.Errorf("%q: siguiente = %v, se esperaba %v", c.expresion, got, c.siguiente)
+		}
+	}
+
+	for _, invalida := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "*/0 * * * *", "5-1 * * * *", "@every -1m", "@every nunca"} {
+		if _, err := ParsearProgramacion(invalida); err == nil {
+			t.Errorf("%q debería ser inválida", invalida)
+		}
+	}
+}
+
+// eleccionFija permite cambiar el liderazgo en las pruebas
+type eleccionFija struct{ lider bool }
+
+func (e *eleccionFija) EsLider(context.Context) bool { return e.lider }
+func (e *eleccionFija) Renunciar()                   { e.lider = false }
+
+func TestProgramador(t *testing.T) {
+	historial := memory.NewRepositories().EjecucionTarea
+	eleccion := &eleccionFija{}
+	p := New(historial, eleccion)
+
+	ejecuciones := 0
+	continuar := make(chan struct{})
+	if err := p.Registrar(Tarea{Nombre: "contar", Programacion: "* * * * *", Ejecutar: func(context.Context) (string, error) {
+		ejecuciones++
+		return "listo", nil
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Registrar(Tarea{Nombre: "lenta", Programacion: "@daily", Ejecutar: func(context.Context) (string, error) {
+		<-continuar
+		return "", errors.New("sin conexión")
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Registrar(Tarea{Nombre: "contar", Programacion: "@daily", Ejecutar: func(context.Context) (string, error) { return "", nil }}); err == nil {
+		t.Error("se esperaba un error al registrar una tarea repetida")
+	}
+	if err := p.Registrar(Tarea{Nombre: "mala", Programacion: "cada tanto", Ejecutar: func(context.Context) (string, error) { return "", nil }}); err == nil {
+		t.Error("se esperaba un error por la programación inválida")
+	}
+
+	// Una instancia que no es líder no ejecuta nada, pero avanza la próxima hora
+	enUnMinuto := time.Now().Add(time.Minute)
+	p.revisar(context.Background(), enUnMinuto)
+	p.Esperar()
+	if ejecuciones != 0 || p.EsLider() {
+		t.Fatalf("sin liderazgo: %d ejecuciones, líder %v", ejecuciones, p.EsLider())
+	}
+
+	eleccion.lider = true
+	p.revisar(context.Background(), enUnMinuto)
+	p.Esperar()
+	if ejecuciones != 0 {
+		t.Fatalf("la tarea no debía repetirse en el mismo minuto: %d ejecuciones", ejecuciones)
+	}
+	p.revisar(context.Background(), enUnMinuto.Add(time.Minute))
+	p.Esperar()
+	if ejecuciones != 1 || !p.EsLider() {
+		t.Fatalf("como líder: %d ejecuciones, líder %v", ejecuciones, p.EsLider())
+	}
+	ultima, err := historial.GetUltimaEjecucion("contar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ultima.Disparo != DisparoProgramado || ultima.Estado != repositories.EjecucionExito || ultima.Resultado != "listo" || ultima.Fin == nil {
+		t.Errorf("ejecución programada inesperada: %+v", ultima)
+	}
+
+	// Ejecución manual: una sola a la vez; el error queda en el historial
+	en, err := p.Ejecutar("lenta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if en.Estado != repositories.EjecucionEnCurso || en.Disparo != DisparoManual {
+		t.Errorf("ejecución manual inesperada: %+v", en)
+	}
+	if _, err := p.Ejecutar("lenta"); !errors.Is(err, ErrTareaEnCurso) {
+		t.Errorf("se esperaba ErrTareaEnCurso, se obtuvo %v", err)
+	}
+	if _, err := p.Ejecutar("otra"); !errors.Is(err, ErrTareaNoEncontrada) {
+		t.Errorf("se esperaba ErrTareaNoEncontrada, se obtuvo %v", err)
+	}
+	close(continuar)
+	p.Esperar()
+
+	estados, err := p.Estados()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(estados) != 2 || estados[0].Nombre != "contar" || estados[1].Nombre != "lenta" {
+		t.Fatalf("estados inesperados: %+v", estados)
+	}
+	if estados[0].UltimoError != nil || estados[0].UltimaEjecucion == nil {
+		t.Errorf("contar: %+v", estados[0])
+	}
+	lenta := estados[1]
+	if lenta.EnCurso || lenta.UltimoError == nil || lenta.UltimoError.Error != "sin conexión" || lenta.UltimaEjecucion.ID != lenta.UltimoError.ID {
+		t.Errorf("lenta: %+v", lenta)
+	}
+}
+
+func TestProgramadorRecuperaPanicos(t *testing.T) {
+	historial := memory.NewRepositories().EjecucionTarea
+	p := New(historial, SiempreLider())
+	if err := p.Registrar(Tarea{Nombre: "rota", Programacion: "@hourly", Ejecutar: func(context.Context) (string, error) {
+		var m map[string]int
+		m["x"]++
+		return "", nil
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Ejecutar("rota"); err != nil {
+		t.Fatal(err)
+	}
+	p.Esperar()
+	ultimo, err := historial.GetUltimoError("rota")
+	if err != nil {
+		t.Fatalf("el pánico debía quedar como error: %v", err)
+	}
+	if ultimo.Estado != repositories.EjecucionError || ultimo.Error == "" {
+		t.Errorf("ejecución inesperada: %+v", ultimo)
+	}
+}
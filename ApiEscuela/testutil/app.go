@@ -0,0 +1,272 @@
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"ApiEscuela/eventos"
+	"ApiEscuela/handlers"
+	"ApiEscuela/inspeccion"
+	"ApiEscuela/middleware"
+	"ApiEscuela/models"
+	"ApiEscuela/repositories/cached"
+	"ApiEscuela/routers"
+	"ApiEscuela/services"
+	"ApiEscuela/tareas"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// NewApp arma la aplicación con los mismos servicios, handlers y rutas que main.go.
+// Como en main.go, los catálogos se leen a través de la caché en memoria: los datos de prueba
+// deben crearse antes de las peticiones que los listan.
+func NewApp(r Repos) *fiber.App {
+	catalogCache := cached.NewStore(5 * time.Minute)
+	r.Provincia = cached.NewProvinciaRepository(r.Provincia, catalogCache)
+	r.Ciudad = cached.NewCiudadRepository(r.Ciudad, catalogCache)
+	r.Tematica = cached.NewTematicaRepository(r.Tematica, catalogCache)
+	r.Actividad = cached.NewActividadRepository(r.Actividad, catalogCache)
+	r.VisitaDetalle = cached.NewVisitaDetalleRepository(r.VisitaDetalle, catalogCache)
+
+	authService := services.NewAuthService(r.Usuario, r.Persona, r.CodigoUsuario)
+	mediaService := services.NewMediaService(r.Media, r.Archivos)
+	comunicadoService := services.NewComunicadoService(r.Comunicado, r.Estudiante, r.Institucion, r.Archivos, mediaService)
+	if r.DirSubidas == "" {
+		r.DirSubidas = filepath.Join(os.TempDir(), "apiescuela-uploads-test")
+	}
+	inspector := inspeccion.New(r.Escaner)
+	if r.Eventos == nil {
+		r.Eventos = eventos.New()
+	}
+	uploadService := services.NewUploadService(r.DirSubidas, 0, r.Archivos, inspector, mediaService)
+	notificacionService := services.NewNotificacionService(services.DependenciasNotificaciones{
+		Notificaciones:            r.Notificacion,
+		Usuarios:                  r.Usuario,
+		Dudas:                     r.Dudas,
+		Estudiantes:               r.Estudiante,
+		EstudiantesUniversitarios: r.EstudianteUniversitario,
+		Autoridades:               r.AutoridadUTEQ,
+		Programas:                 r.ProgramaVisita,
+		Correo:                    comunicadoService,
+		WhatsApp:                  r.WhatsApp,
+		Eventos:                   r.Eventos,
+	})
+	papeleraService := services.NewPapeleraService(r.Papelera, r.Noticia, r.Comunicado, mediaService, catalogCache.InvalidateAll)
+	webhookService := services.NewWebhookService(r.Webhook, services.ConfigWebhooks{})
+	// Sin caché, para que cada petición vea los datos que el test acaba de crear
+	analiticaService := services.NewAnaliticaService(r.Analitica, 0)
+
+	// Las tareas se registran como en main.go pero el programador no se inicia: solo se
+	// ejecutan manualmente
+	programador := tareas.New(r.EjecucionTarea, tareas.SiempreLider())
+	for _, tarea := range services.TareasProgramadas(services.ConfigTareas{
+		ExpirarCodigos:            "*/5 * * * *",
+		PurgarSubidas:             "@hourly",
+		RecolectarArchivos:        "@daily",
+		GraciaArchivos:            168 * time.Hour,
+		RecordatoriosVisitas:      "@hourly",
+		AnticipacionRecordatorios: 24 * time.Hour,
+		PurgarPapelera:            "@daily",
+		RetencionPapelera:         720 * time.Hour,
+		ReintentarWebhooks:        "* * * * *",
+		RefrescarAnalitica:        "*/10 * * * *",
+	}, services.DependenciasTareas{
+		Codigos:       r.CodigoUsuario,
+		Historial:     r.EjecucionTarea,
+		Subidas:       uploadService,
+		Archivos:      services.NewArchivoService(r.Noticia, r.Comunicado, r.Archivos, mediaService),
+		Recordatorios: services.NewRecordatorioService(r.ProgramaVisita, r.DetalleAutoridadDetallesVisita, r.AutoridadUTEQ, comunicadoService),
+		Papelera:      papeleraService,
+		Webhooks:      webhookService,
+		Analitica:     analiticaService,
+	}) {
+		if err := programador.Registrar(tarea); err != nil {
+			panic(err)
+		}
+	}
+
+	estudianteHandler, errEstudiante := handlers.NewEstudianteHandler(r.Estudiante, r.Persona, r.Institucion, r.Ciudad, r.Usuario, r.TipoUsuario, authService, r.Eventos, webhookService)
+	estudianteUnivHandler, errEstudianteUniv := handlers.NewEstudianteUniversitarioHandler(r.EstudianteUniversitario, r.Persona)
+	autoridadHandler, errAutoridad := handlers.NewAutoridadUTEQHandler(r.AutoridadUTEQ, r.Persona)
+	actividadHandler, errActividad := handlers.NewActividadHandler(r.Actividad, r.Tematica)
+	programaVisitaHandler, errProgramaVisita := handlers.NewProgramaVisitaHandler(r.ProgramaVisita, r.Institucion, webhookService, r.IfMatchRequerido)
+	dudasHandler, errDudas := handlers.NewDudasHandler(r.Dudas, r.Estudiante, r.AutoridadUTEQ, notificacionService, webhookService)
+	codigoHandler, errCodigo := handlers.NewCodigoHandler(r.CodigoUsuario, r.Usuario)
+	if err := errors.Join(errEstudiante, errEstudianteUniv, errAutoridad, errActividad, errProgramaVisita, errDudas, errCodigo); err != nil {
+		panic(err)
+	}
+
+	allHandlers := routers.NewAllHandlers(
+		estudianteHandler,
+		handlers.NewPersonaHandler(r.Persona, r.IfMatchRequerido),
+		handlers.NewProvinciaHandler(r.Provincia),
+		handlers.NewCiudadHandler(r.Ciudad),
+		handlers.NewInstitucionHandler(r.Institucion),
+		handlers.NewTipoUsuarioHandler(r.TipoUsuario),
+		handlers.NewUsuarioHandler(r.Usuario),
+		estudianteUnivHandler,
+		autoridadHandler,
+		handlers.NewTematicaHandler(r.Tematica),
+		actividadHandler,
+		programaVisitaHandler,
+		handlers.NewDetalleAutoridadDetallesVisitaHandler(r.DetalleAutoridadDetallesVisita, notificacionService),
+		handlers.NewVisitaDetalleHandler(r.VisitaDetalle),
+		dudasHandler,
+		handlers.NewVisitaDetalleEstudiantesUniversitariosHandler(r.VisitaDetalleEstudiantesUniversitarios, notificacionService),
+		handlers.NewNoticiaHandler(r.Noticia, mediaService),
+		handlers.NewUploadHandler(r.Archivos, uploadService, inspector, mediaService),
+		handlers.NewAuthHandler(authService),
+		codigoHandler,
+		handlers.NewComunicadoHandler(comunicadoService, r.Archivos, r.Eventos, notificacionService, webhookService),
+		handlers.NewWhatsAppHandler(),
+		handlers.NewBackupHandler(services.NewBackupService(r.DB, r.Archivos, catalogCache.InvalidateAll)),
+		handlers.NewMediaHandler(mediaService),
+		handlers.NewTareaHandler(programador),
+		handlers.NewPapeleraHandler(papeleraService),
+		handlers.NewEventosHandler(r.Eventos),
+		handlers.NewNotificacionHandler(notificacionService),
+		handlers.NewWebhookHandler(webhookService),
+		handlers.NewBusquedaHandler(services.NewBusquedaService(r.Busqueda, r.Noticia)),
+		handlers.NewExportacionHandler(services.NewExportacionService(r.Exportacion)),
+		handlers.NewAnaliticaHandler(analiticaService),
+	)
+
+	app := fiber.New(fiber.Config{ErrorHandler: handlers.ErrorHandler})
+	routers.SetupAllRoutes(app, allHandlers)
+	return app
+}
+
+// Token genera un JWT válido para el usuario
+func Token(t testing.TB, usuario *models.Usuario) string {
+	t.Helper()
+	token, err := middleware.GenerateJWT(usuario.ID, usuario.Usuario, usuario.TipoUsuarioID)
+	if err != nil {
+		t.Fatalf("no se pudo generar el token: %v", err)
+	}
+	return token
+}
+
+// Response es la respuesta de una petición de prueba
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// JSON decodifica el cuerpo de la respuesta en v
+func (r *Response) JSON(t testing.TB, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(r.Body, v); err != nil {
+		t.Fatalf("respuesta no es JSON válido (%v): %s", err, r.Body)
+	}
+}
+
+// Map decodifica el cuerpo de la respuesta como objeto JSON
+func (r *Response) Map(t testing.TB) map[string]interface{} {
+	t.Helper()
+	var m map[string]interface{}
+	r.JSON(t, &m)
+	return m
+}
+
+// Do ejecuta una petición contra la aplicación. body se serializa como JSON si no es nil;
+// token, si no está vacío, se envía como Bearer.
+func Do(t testing.TB, app *fiber.App, method, path string, body interface{}, token string) *Response {
+	t.Helper()
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("no se pudo serializar el cuerpo: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return send(t, app, req, token)
+}
+
+// DoHeaders ejecuta una petición sin cuerpo con los encabezados indicados
+func DoHeaders(t testing.TB, app *fiber.App, method, path string, headers map[string]string, token string) *Response {
+	t.Helper()
+	req := httptest.NewRequest(method, path, nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	return send(t, app, req, token)
+}
+
+// DoRaw ejecuta una petición con el cuerpo crudo y los encabezados indicados
+func DoRaw(t testing.TB, app *fiber.App, method, path string, headers map[string]string, body []byte, token string) *Response {
+	t.Helper()
+	req := httptest.NewRequest(method, path, bytes.NewReader(body))
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	return send(t, app, req, token)
+}
+
+// DoMultipart ejecuta una petición con un formulario multipart con los campos indicados
+func DoMultipart(t testing.TB, app *fiber.App, method, path string, fields map[string]string, token string) *Response {
+	t.Helper()
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for k, v := range fields {
+		if err := w.WriteField(k, v); err != nil {
+			t.Fatalf("no se pudo escribir el campo %s: %v", k, err)
+		}
+	}
+	w.Close()
+	req := httptest.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return send(t, app, req, token)
+}
+
+// DoFile ejecuta una petición multipart con un archivo en el campo indicado
+func DoFile(t testing.TB, app *fiber.App, method, path, campo, nombre, contentType string, contenido []byte, token string) *Response {
+	t.Helper()
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name=%q; filename=%q`, campo, nombre))
+	h.Set("Content-Type", contentType)
+	part, err := w.CreatePart(h)
+	if err != nil {
+		t.Fatalf("no se pudo crear el archivo del formulario: %v", err)
+	}
+	part.Write(contenido)
+	w.Close()
+	req := httptest.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	return send(t, app, req, token)
+}
+
+func send(t testing.TB, app *fiber.App, req *http.Request, token string) *Response {
+	t.Helper()
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("%s %s: %v", req.Method, req.URL.Path, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("no se pudo leer la respuesta: %v", err)
+	}
+	return &Response{Status: resp.StatusCode, Header: resp.Header, Body: data}
+}
@@ -0,0 +1,57 @@
+package testutil
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"ApiEscuela/inspeccion"
+	"ApiEscuela/inspeccion/clamdtest"
+)
+
+// Backend crea un conjunto de repositorios vacío para una prueba
+type Backend struct {
+	Name string
+	New  func(t testing.TB) Repos
+}
+
+// backends contiene los backends disponibles; postgres.go agrega Postgres con la etiqueta "integration"
+var backends = []Backend{
+	{Name: "memory", New: func(testing.TB) Repos { return MemoryRepos() }},
+}
+
+// stopFuncs se ejecutan al terminar el binario de pruebas (ver RunMain)
+var stopFuncs []func()
+
+// Backends devuelve los backends contra los que deben ejecutarse las pruebas de flujo
+func Backends() []Backend {
+	return backends
+}
+
+// ForEachBackend ejecuta fn como subprueba con repositorios vacíos de cada backend
+func ForEachBackend(t *testing.T, fn func(t *testing.T, repos Repos)) {
+	for _, b := range backends {
+		t.Run(b.Name, func(t *testing.T) {
+			repos := b.New(t)
+			repos.DirSubidas = t.TempDir()
+			repos.Escaner = inspeccion.NewClamAV(clamdtest.New(t).Direccion(), 5*time.Second)
+			fn(t, repos)
+		})
+	}
+}
+
+// RunMain ejecuta las pruebas y libera los recursos compartidos (por ejemplo, Postgres embebido).
+// Uso en un paquete de pruebas:
+//
+//	func TestMain(m *testing.M) { os.Exit(testutil.RunMain(m)) }
+func RunMain(m *testing.M) int {
+	// Los servicios leen SMTP_* del entorno; las pruebas nunca deben enviar correos reales
+	for _, key := range []string{"SMTP_HOST", "SMTP_PORT", "SMTP_USER", "SMTP_PASS", "SMTP_FROM", "SMTP_FROM_NAME"} {
+		os.Unsetenv(key)
+	}
+	code := m.Run()
+	for _, stop := range stopFuncs {
+		stop()
+	}
+	return code
+}
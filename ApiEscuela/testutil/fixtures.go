@@ -0,0 +1,197 @@
+package testutil
+
+import (
+	"fmt"
+	"testing"
+
+	"ApiEscuela/models"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Fixtures construye y guarda datos de prueba con valores por defecto válidos.
+// Cada constructor acepta funciones opcionales para ajustar el registro antes de guardarlo.
+type Fixtures struct {
+	t   testing.TB
+	r   Repos
+	seq int
+}
+
+// NewFixtures crea un constructor de datos sobre los repositorios indicados
+func NewFixtures(t testing.TB, r Repos) *Fixtures {
+	return &Fixtures{t: t, r: r}
+}
+
+func (f *Fixtures) next() int {
+	f.seq++
+	return f.seq
+}
+
+func (f *Fixtures) must(what string, err error) {
+	f.t.Helper()
+	if err != nil {
+		f.t.Fatalf("fixture %s: %v", what, err)
+	}
+}
+
+// HashPassword encripta una clave con el costo mínimo de bcrypt (suficiente para pruebas)
+func HashPassword(t testing.TB, clave string) string {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(clave), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("no se pudo encriptar la clave: %v", err)
+	}
+	return string(hash)
+}
+
+// TipoUsuario crea un tipo de usuario con el nombre indicado
+func (f *Fixtures) TipoUsuario(nombre string, opts ...func(*models.TipoUsuario)) *models.TipoUsuario {
+	f.t.Helper()
+	tipo := &models.TipoUsuario{Nombre: nombre, Descripcion: "Tipo " + nombre}
+	for _, opt := range opts {
+		opt(tipo)
+	}
+	f.must("tipo de usuario", f.r.TipoUsuario.CreateTipoUsuario(tipo))
+	return tipo
+}
+
+// Persona crea una persona con cédula y correo únicos
+func (f *Fixtures) Persona(opts ...func(*models.Persona)) *models.Persona {
+	f.t.Helper()
+	n := f.next()
+	correo := fmt.Sprintf("persona%d@example.com", n)
+	persona := &models.Persona{
+		Nombre: fmt.Sprintf("Persona %d", n),
+		Cedula: fmt.Sprintf("09%08d", n),
+		Correo: &correo,
+	}
+	for _, opt := range opts {
+		opt(persona)
+	}
+	f.must("persona", f.r.Persona.CreatePersona(persona))
+	return persona
+}
+
+// Usuario crea un usuario para la persona con la clave encriptada.
+// El nombre de usuario por defecto es la cédula de la persona.
+func (f *Fixtures) Usuario(persona *models.Persona, tipo *models.TipoUsuario, clave string, opts ...func(*models.Usuario)) *models.Usuario {
+	f.t.Helper()
+	usuario := &models.Usuario{
+		Usuario:       persona.Cedula,
+		Contraseña:    HashPassword(f.t, clave),
+		PersonaID:     persona.ID,
+		TipoUsuarioID: tipo.ID,
+		Verificado:    true,
+	}
+	for _, opt := range opts {
+		opt(usuario)
+	}
+	f.must("usuario", f.r.Usuario.CreateUsuario(usuario))
+	return usuario
+}
+
+// Provincia crea una provincia
+func (f *Fixtures) Provincia(nombre string) *models.Provincia {
+	f.t.Helper()
+	provincia := &models.Provincia{Provincia: nombre}
+	f.must("provincia", f.r.Provincia.CreateProvincia(provincia))
+	return provincia
+}
+
+// Ciudad crea una ciudad en la provincia
+func (f *Fixtures) Ciudad(provincia *models.Provincia, nombre string) *models.Ciudad {
+	f.t.Helper()
+	ciudad := &models.Ciudad{ProvinciaID: provincia.ID, Ciudad: nombre}
+	f.must("ciudad", f.r.Ciudad.CreateCiudad(ciudad))
+	return ciudad
+}
+
+// Institucion crea una institución con correo de contacto
+func (f *Fixtures) Institucion(opts ...func(*models.Institucion)) *models.Institucion {
+	f.t.Helper()
+	n := f.next()
+	institucion := &models.Institucion{
+		Nombre:    fmt.Sprintf("Unidad Educativa %d", n),
+		Autoridad: "Rector",
+		Correo:    fmt.Sprintf("institucion%d@example.com", n),
+	}
+	for _, opt := range opts {
+		opt(institucion)
+	}
+	f.must("institución", f.r.Institucion.CreateInstitucion(institucion))
+	return institucion
+}
+
+// Estudiante crea un estudiante para la persona en la institución y ciudad indicadas
+func (f *Fixtures) Estudiante(persona *models.Persona, institucion *models.Institucion, ciudad *models.Ciudad, opts ...func(*models.Estudiante)) *models.Estudiante {
+	f.t.Helper()
+	estudiante := &models.Estudiante{
+		PersonaID:     persona.ID,
+		InstitucionID: institucion.ID,
+		CiudadID:      ciudad.ID,
+		Especialidad:  "Informática",
+	}
+	for _, opt := range opts {
+		opt(estudiante)
+	}
+	f.must("estudiante", f.r.Estudiante.CreateEstudiante(estudiante))
+	return estudiante
+}
+
+// Comunicado crea un comunicado enviado por el usuario
+func (f *Fixtures) Comunicado(usuario *models.Usuario, opts ...func(*models.Comunicado)) *models.Comunicado {
+	f.t.Helper()
+	n := f.next()
+	comunicado := &models.Comunicado{
+		Asunto:        fmt.Sprintf("Comunicado %d", n),
+		Destinatarios: `{"tipo":"todos","ids":[]}`,
+		Mensaje:       "<p>Mensaje</p>",
+		Adjuntos:      "[]",
+		UsuarioID:     usuario.ID,
+		Estado:        "enviado",
+		Canal:         "correo",
+	}
+	for _, opt := range opts {
+		opt(comunicado)
+	}
+	f.must("comunicado", f.r.Comunicado.CreateComunicado(comunicado))
+	return comunicado
+}
+
+// Codigo crea un código de verificación vigente para el usuario
+func (f *Fixtures) Codigo(usuario *models.Usuario, codigo string) *models.CodigoUsuario {
+	f.t.Helper()
+	f.must("código", f.r.CodigoUsuario.Crear(usuario.ID, codigo))
+	rec, err := f.r.CodigoUsuario.FindLatestByCodigo(codigo)
+	f.must("código", err)
+	return rec
+}
+
+// Escenario es un conjunto mínimo de datos relacionados usado por las pruebas de flujo
+type Escenario struct {
+	TipoAdmin      *models.TipoUsuario
+	TipoEstudiante *models.TipoUsuario
+	Admin          *models.Usuario
+	AdminToken     string
+	Provincia      *models.Provincia
+	Ciudad         *models.Ciudad
+	Institucion    *models.Institucion
+}
+
+// ClaveAdmin es la clave del usuario administrador del escenario
+const ClaveAdmin = "admin123"
+
+// Escenario crea tipos de usuario, un administrador con token, una provincia, una ciudad y una institución
+func (f *Fixtures) Escenario() *Escenario {
+	f.t.Helper()
+	e := &Escenario{
+		TipoAdmin:      f.TipoUsuario("Administrador"),
+		TipoEstudiante: f.TipoUsuario("Estudiante"),
+	}
+	e.Admin = f.Usuario(f.Persona(), e.TipoAdmin, ClaveAdmin, func(u *models.Usuario) { u.Usuario = "admin" })
+	e.AdminToken = Token(f.t, e.Admin)
+	e.Provincia = f.Provincia("Los Ríos")
+	e.Ciudad = f.Ciudad(e.Provincia, "Quevedo")
+	e.Institucion = f.Institucion()
+	return e
+}
@@ -0,0 +1,104 @@
+//go:build integration
+
+package testutil
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"ApiEscuela/database"
+
+	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Puerto del Postgres embebido; se puede cambiar con TEST_PG_PORT si está ocupado
+const defaultEmbeddedPort = 54329
+
+var (
+	pgOnce sync.Once
+	pgDB   *gorm.DB
+	pgErr  error
+)
+
+func init() {
+	backends = append(backends, Backend{Name: "postgres", New: func(t testing.TB) Repos {
+		return GormRepos(PostgresDB(t))
+	}})
+}
+
+// PostgresDB devuelve una base de datos migrada y vacía para la prueba.
+// Usa TEST_DATABASE_URL si está definida; si no, inicia un Postgres embebido
+// desechable que se detiene al terminar el binario de pruebas (ver RunMain).
+func PostgresDB(t testing.TB) *gorm.DB {
+	t.Helper()
+	pgOnce.Do(func() {
+		pgDB, pgErr = openPostgres()
+	})
+	if pgErr != nil {
+		t.Fatalf("no se pudo iniciar Postgres para las pruebas: %v", pgErr)
+	}
+	if err := truncateAll(pgDB); err != nil {
+		t.Fatalf("no se pudieron vaciar las tablas: %v", err)
+	}
+	return pgDB
+}
+
+func openPostgres() (*gorm.DB, error) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		port := uint32(defaultEmbeddedPort)
+		if p := os.Getenv("TEST_PG_PORT"); p != "" {
+			if _, err := fmt.Sscanf(p, "%d", &port); err != nil {
+				return nil, fmt.Errorf("TEST_PG_PORT inválido: %v", err)
+			}
+		}
+		runtime, err := os.MkdirTemp("", "apiescuela-pg-")
+		if err != nil {
+			return nil, err
+		}
+		pg := embeddedpostgres.NewDatabase(embeddedpostgres.DefaultConfig().
+			Port(port).
+			Database("apiescuela_test").
+			RuntimePath(filepath.Join(runtime, "runtime")).
+			DataPath(filepath.Join(runtime, "data")).
+			Logger(nil))
+		if err := pg.Start(); err != nil {
+			os.RemoveAll(runtime)
+			return nil, err
+		}
+		stopFuncs = append(stopFuncs, func() {
+			pg.Stop()
+			os.RemoveAll(runtime)
+		})
+		dsn = fmt.Sprintf("host=localhost port=%d user=postgres password=postgres dbname=apiescuela_test sslmode=disable", port)
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		return nil, err
+	}
+	if err := database.Migrate(db); err != nil {
+		return nil, fmt.Errorf("error al migrar: %v", err)
+	}
+	return db, nil
+}
+
+// truncateAll vacía todas las tablas de los modelos y reinicia sus secuencias
+func truncateAll(db *gorm.DB) error {
+	tables := make([]string, 0, len(database.Models))
+	for _, model := range database.Models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return err
+		}
+		tables = append(tables, `"`+stmt.Schema.Table+`"`)
+	}
+	return db.Exec("TRUNCATE " + strings.Join(tables, ", ") + " RESTART IDENTITY CASCADE").Error
+}
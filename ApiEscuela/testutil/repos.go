@@ -0,0 +1,135 @@
+// Package testutil contiene utilidades para las pruebas: conjuntos de repositorios
+// (en memoria o sobre Postgres), una aplicación Fiber armada como en main.go,
+// constructores de datos de prueba y helpers para peticiones HTTP.
+//
+// Las pruebas que usan Postgres requieren la etiqueta de compilación "integration":
+//
+//	go test -tags integration ./...
+package testutil
+
+import (
+	"ApiEscuela/eventos"
+	"ApiEscuela/inspeccion"
+	"ApiEscuela/repositories"
+	"ApiEscuela/repositories/memory"
+	"ApiEscuela/services"
+	"ApiEscuela/storage"
+
+	"gorm.io/gorm"
+)
+
+// Repos agrupa un repositorio de cada tipo
+type Repos struct {
+	Provincia                              repositories.ProvinciaRepository
+	Ciudad                                 repositories.CiudadRepository
+	Persona                                repositories.PersonaRepository
+	TipoUsuario                            repositories.TipoUsuarioRepository
+	Usuario                                repositories.UsuarioRepository
+	Institucion                            repositories.InstitucionRepository
+	Estudiante                             repositories.EstudianteRepository
+	EstudianteUniversitario                repositories.EstudianteUniversitarioRepository
+	AutoridadUTEQ                          repositories.AutoridadUTEQRepository
+	Tematica                               repositories.TematicaRepository
+	Actividad                              repositories.ActividadRepository
+	ProgramaVisita                         repositories.ProgramaVisitaRepository
+	DetalleAutoridadDetallesVisita         repositories.DetalleAutoridadDetallesVisitaRepository
+	VisitaDetalle                          repositories.VisitaDetalleRepository
+	Dudas                                  repositories.DudasRepository
+	VisitaDetalleEstudiantesUniversitarios repositories.VisitaDetalleEstudiantesUniversitariosRepository
+	CodigoUsuario                          repositories.CodigoUsuarioRepository
+	Noticia                                repositories.NoticiaRepository
+	Comunicado                             repositories.ComunicadoRepository
+	Media                                  repositories.MediaRepository
+	EjecucionTarea                         repositories.EjecucionTareaRepository
+	Notificacion                           repositories.NotificacionRepository
+	Webhook                                repositories.WebhookRepository
+	Busqueda                               repositories.BusquedaRepository
+	Exportacion                            repositories.ExportacionRepository
+	Analitica                              repositories.AnaliticaRepository
+	Papelera                               repositories.PapeleraRepository
+
+	// DB es la conexión de los repositorios GORM; nil en memoria
+	DB *gorm.DB
+	// Archivos es el almacenamiento de los archivos subidos; en memoria en ambos backends
+	Archivos *storage.Memoria
+	// DirSubidas es la carpeta temporal de las subidas por partes; ForEachBackend usa una por prueba
+	DirSubidas string
+	// Escaner analiza los archivos subidos; ForEachBackend usa un clamd de prueba que detecta EICAR
+	Escaner inspeccion.Escaner
+	// Eventos es el bus de /api/eventos; si es nil NewApp crea uno
+	Eventos *eventos.Bus
+	// WhatsApp envía las notificaciones por WhatsApp; si es nil no se envían
+	WhatsApp services.MensajeroWhatsApp
+	// IfMatchRequerido exige If-Match en las escrituras, como IF_MATCH_REQUIRED=true
+	IfMatchRequerido bool
+}
+
+// MemoryRepos crea repositorios en memoria que comparten un Store nuevo
+func MemoryRepos() Repos {
+	m := memory.NewRepositories()
+	return Repos{
+		Provincia:                              m.Provincia,
+		Ciudad:                                 m.Ciudad,
+		Persona:                                m.Persona,
+		TipoUsuario:                            m.TipoUsuario,
+		Usuario:                                m.Usuario,
+		Institucion:                            m.Institucion,
+		Estudiante:                             m.Estudiante,
+		EstudianteUniversitario:                m.EstudianteUniversitario,
+		AutoridadUTEQ:                          m.AutoridadUTEQ,
+		Tematica:                               m.Tematica,
+		Actividad:                              m.Actividad,
+		ProgramaVisita:                         m.ProgramaVisita,
+		DetalleAutoridadDetallesVisita:         m.DetalleAutoridadDetallesVisita,
+		VisitaDetalle:                          m.VisitaDetalle,
+		Dudas:                                  m.Dudas,
+		VisitaDetalleEstudiantesUniversitarios: m.VisitaDetalleEstudiantesUniversitarios,
+		CodigoUsuario:                          m.CodigoUsuario,
+		Noticia:                                m.Noticia,
+		Comunicado:                             m.Comunicado,
+		Media:                                  m.Media,
+		EjecucionTarea:                         m.EjecucionTarea,
+		Notificacion:                           m.Notificacion,
+		Webhook:                                m.Webhook,
+		Busqueda:                               m.Busqueda,
+		Exportacion:                            m.Exportacion,
+		Analitica:                              m.Analitica,
+		Papelera:                               m.Papelera,
+		Archivos:                               storage.NewMemoria(),
+	}
+}
+
+// GormRepos crea los repositorios reales sobre una conexión GORM
+func GormRepos(db *gorm.DB) Repos {
+	return Repos{
+		Provincia:                              repositories.NewProvinciaRepository(db),
+		Ciudad:                                 repositories.NewCiudadRepository(db),
+		Persona:                                repositories.NewPersonaRepository(db),
+		TipoUsuario:                            repositories.NewTipoUsuarioRepository(db),
+		Usuario:                                repositories.NewUsuarioRepository(db),
+		Institucion:                            repositories.NewInstitucionRepository(db),
+		Estudiante:                             repositories.NewEstudianteRepository(db),
+		EstudianteUniversitario:                repositories.NewEstudianteUniversitarioRepository(db),
+		AutoridadUTEQ:                          repositories.NewAutoridadUTEQRepository(db),
+		Tematica:                               repositories.NewTematicaRepository(db),
+		Actividad:                              repositories.NewActividadRepository(db),
+		ProgramaVisita:                         repositories.NewProgramaVisitaRepository(db),
+		DetalleAutoridadDetallesVisita:         repositories.NewDetalleAutoridadDetallesVisitaRepository(db),
+		VisitaDetalle:                          repositories.NewVisitaDetalleRepository(db),
+		Dudas:                                  repositories.NewDudasRepository(db),
+		VisitaDetalleEstudiantesUniversitarios: repositories.NewVisitaDetalleEstudiantesUniversitariosRepository(db),
+		CodigoUsuario:                          repositories.NewCodigoUsuarioRepository(db),
+		Noticia:                                repositories.NewNoticiaRepository(db),
+		Comunicado:                             repositories.NewComunicadoRepository(db),
+		Media:                                  repositories.NewMediaRepository(db),
+		EjecucionTarea:                         repositories.NewEjecucionTareaRepository(db),
+		Notificacion:                           repositories.NewNotificacionRepository(db),
+		Webhook:                                repositories.NewWebhookRepository(db),
+		Busqueda:                               repositories.NewBusquedaRepository(db),
+		Exportacion:                            repositories.NewExportacionRepository(db),
+		Analitica:                              repositories.NewAnaliticaRepository(db),
+		Papelera:                               repositories.NewPapeleraRepository(db),
+		DB:                                     db,
+		Archivos:                               storage.NewMemoria(),
+	}
+}
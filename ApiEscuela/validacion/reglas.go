@@ -0,0 +1,258 @@
+package validacion
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	emailRegex    = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+	simbolosRegex = regexp.MustCompile(`[<>{}[\]\\|` + "`" + `~!@#$%^&*()+=;:'"?/]`)
+	celularRegex  = regexp.MustCompile(`^09\d{8}$`)
+	fijoRegex     = regexp.MustCompile(`^0[2-7]\d{7}$`)
+)
+
+// regla es una regla de la etiqueta validate ya interpretada
+type regla struct {
+	nombre string
+	param  string
+
+	numero     float64       // min, max, len
+	opciones   []string      // oneof
+	desde      time.Duration // ventana
+	hasta      time.Duration // ventana
+	otro       []int         // required_without, despues: índice del otro campo
+	otroNombre string        // nombre json del otro campo
+}
+
+// preparar valida y convierte el parámetro de la regla; los errores son de programación y se
+// detectan al iniciar con Verificar
+func (r *regla) preparar(t reflect.Type, f reflect.StructField) error {
+	fallar := func(motivo string) error {
+		return fmt.Errorf("validacion: %s.%s: regla %q: %s", t.Name(), f.Name, r.nombre, motivo)
+	}
+	switch r.nombre {
+	case "required", "email", "numeric", "cedula", "telefono", "pasado", "futuro", "sin_simbolos", "sin_espacios":
+	case "min", "max", "len":
+		n, err := strconv.ParseFloat(r.param, 64)
+		if err != nil {
+			return fallar("se esperaba un número")
+		}
+		r.numero = n
+	case "oneof":
+		r.opciones = strings.Fields(r.param)
+		if len(r.opciones) == 0 {
+			return fallar("se esperaban opciones separadas por espacios")
+		}
+	case "ventana":
+		desde, hasta, ok := strings.Cut(r.param, "..")
+		var err1, err2 error
+		r.desde, err1 = time.ParseDuration(desde)
+		r.hasta, err2 = time.ParseDuration(hasta)
+		if !ok || err1 != nil || err2 != nil || r.desde > r.hasta {
+			return fallar("se esperaba desde..hasta, p. ej. -1h..24h")
+		}
+	case "required_without", "despues":
+		otro, ok := t.FieldByName(r.param)
+		if !ok {
+			return fallar("no existe el campo " + r.param)
+		}
+		r.otro = otro.Index
+		r.otroNombre = nombreJSON(otro)
+	case "existe":
+		if r.param == "" {
+			return fallar("falta el nombre de la referencia")
+		}
+	default:
+		return fallar("regla desconocida")
+	}
+	return nil
+}
+
+// revisar devuelve el mensaje de error de la regla para el campo, o "" si se cumple
+func (r *regla) revisar(estructura, v reflect.Value, campo string) string {
+	switch r.nombre {
+	case "required":
+		if vacio(v) {
+			return fmt.Sprintf("El campo %s es requerido", campo)
+		}
+		return ""
+	case "required_without":
+		if vacio(v) && vacio(estructura.FieldByIndex(r.otro)) {
+			return fmt.Sprintf("Debe proporcionar %s o %s", campo, r.otroNombre)
+		}
+		return ""
+	}
+	if vacio(v) {
+		return "" // campo opcional no enviado
+	}
+
+	v = indirecto(v)
+	switch r.nombre {
+	case "min", "max", "len":
+		return r.revisarTamano(v, campo)
+	case "email":
+		if !emailRegex.MatchString(strings.TrimSpace(v.String())) {
+			return fmt.Sprintf("El formato del campo %s no es un correo electrónico válido", campo)
+		}
+	case "numeric":
+		if !soloDigitos(strings.TrimSpace(v.String())) {
+			return fmt.Sprintf("El campo %s solo puede contener dígitos", campo)
+		}
+	case "oneof":
+		valor := strings.TrimSpace(fmt.Sprint(v.Interface()))
+		for _, opcion := range r.opciones {
+			if valor == opcion {
+				return ""
+			}
+		}
+		return fmt.Sprintf("El campo %s debe ser uno de: %s", campo, strings.Join(r.opciones, ", "))
+	case "cedula":
+		if !CedulaValida(v.String()) {
+			return fmt.Sprintf("El campo %s no es una cédula ecuatoriana válida", campo)
+		}
+	case "telefono":
+		if !TelefonoValido(v.String()) {
+			return fmt.Sprintf("El campo %s no es un teléfono ecuatoriano válido (celular 09XXXXXXXX o convencional 0XXXXXXXX)", campo)
+		}
+	case "sin_simbolos":
+		if simbolosRegex.MatchString(v.String()) {
+			return fmt.Sprintf("El campo %s no puede contener caracteres especiales", campo)
+		}
+	case "sin_espacios":
+		if strings.ContainsAny(v.String(), " \t\n") {
+			return fmt.Sprintf("El campo %s no puede contener espacios", campo)
+		}
+	case "pasado", "futuro", "ventana", "despues":
+		return r.revisarFecha(estructura, v, campo)
+	}
+	return ""
+}
+
+// revisarTamano aplica min, max y len: longitud en caracteres para textos, valor para números
+func (r *regla) revisarTamano(v reflect.Value, campo string) string {
+	if v.Kind() == reflect.String {
+		n := float64(utf8.RuneCountInString(strings.TrimSpace(v.String())))
+		switch {
+		case r.nombre == "min" && n < r.numero:
+			return fmt.Sprintf("El campo %s debe tener al menos %s caracteres", campo, r.param)
+		case r.nombre == "max" && n > r.numero:
+			return fmt.Sprintf("El campo %s no puede exceder %s caracteres", campo, r.param)
+		case r.nombre == "len" && n != r.numero:
+			return fmt.Sprintf("El campo %s debe tener exactamente %s caracteres", campo, r.param)
+		}
+		return ""
+	}
+
+	var n float64
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		n = v.Float()
+	case reflect.Slice, reflect.Map, reflect.Array:
+		n = float64(v.Len())
+	default:
+		return ""
+	}
+	switch {
+	case r.nombre == "min" && n < r.numero:
+		return fmt.Sprintf("El campo %s debe ser mayor o igual a %s", campo, r.param)
+	case r.nombre == "max" && n > r.numero:
+		return fmt.Sprintf("El campo %s no puede ser mayor que %s", campo, r.param)
+	case r.nombre == "len" && n != r.numero:
+		return fmt.Sprintf("El campo %s debe ser igual a %s", campo, r.param)
+	}
+	return ""
+}
+
+// revisarFecha aplica las reglas de fechas: pasado, futuro, ventana (relativa a ahora) y despues (otro campo)
+func (r *regla) revisarFecha(estructura, v reflect.Value, campo string) string {
+	t, ok := v.Interface().(time.Time)
+	if !ok {
+		return ""
+	}
+	ahora := time.Now()
+	switch r.nombre {
+	case "pasado":
+		if t.After(ahora) {
+			return fmt.Sprintf("El campo %s no puede ser una fecha futura", campo)
+		}
+	case "futuro":
+		if !t.After(ahora) {
+			return fmt.Sprintf("El campo %s debe ser una fecha futura", campo)
+		}
+	case "ventana":
+		if t.Before(ahora.Add(r.desde)) || t.After(ahora.Add(r.hasta)) {
+			return fmt.Sprintf("El campo %s debe estar entre %s y %s desde ahora", campo, r.desde, r.hasta)
+		}
+	case "despues":
+		otro := indirecto(estructura.FieldByIndex(r.otro))
+		inicio, ok := otro.Interface().(time.Time)
+		if ok && !inicio.IsZero() && !t.After(inicio) {
+			return fmt.Sprintf("El campo %s debe ser posterior a %s", campo, r.otroNombre)
+		}
+	}
+	return ""
+}
+
+func soloDigitos(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// CedulaValida comprueba una cédula ecuatoriana: 10 dígitos, código de provincia (01-24, o 30
+// para los registrados en el exterior), tercer dígito menor que 6 y dígito verificador módulo 10
+func CedulaValida(cedula string) bool {
+	cedula = strings.TrimSpace(cedula)
+	if len(cedula) != 10 || !soloDigitos(cedula) {
+		return false
+	}
+	provincia, _ := strconv.Atoi(cedula[:2])
+	if (provincia < 1 || provincia > 24) && provincia != 30 {
+		return false
+	}
+	if cedula[2] >= '6' {
+		return false
+	}
+	suma := 0
+	for i := 0; i < 9; i++ {
+		d := int(cedula[i] - '0')
+		if i%2 == 0 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		suma += d
+	}
+	verificador := (10 - suma%10) % 10
+	return verificador == int(cedula[9]-'0')
+}
+
+// TelefonoValido comprueba un teléfono ecuatoriano, celular (09XXXXXXXX) o convencional con
+// código de área (0[2-7]XXXXXXX). Acepta el prefijo internacional +593 y separadores comunes.
+func TelefonoValido(telefono string) bool {
+	limpio := strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", ".", "").Replace(strings.TrimSpace(telefono))
+	switch {
+	case strings.HasPrefix(limpio, "+593"):
+		limpio = "0" + strings.TrimPrefix(limpio, "+593")
+	case strings.HasPrefix(limpio, "593") && len(limpio) > 10:
+		limpio = "0" + strings.TrimPrefix(limpio, "593")
+	}
+	return celularRegex.MatchString(limpio) || fijoRegex.MatchString(limpio)
+}
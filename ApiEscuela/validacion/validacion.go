@@ -0,0 +1,305 @@
+// Package validacion valida los datos de entrada a partir de la etiqueta `validate` de los campos.
+//
+// Las reglas se separan por comas; las que llevan parámetro usan "regla=valor":
+//
+//	Nombre   string  `json:"nombre" validate:"required,min=2,max=100"`
+//	Cedula   string  `json:"cedula" validate:"required,cedula"`
+//	Fechafin string  `json:"fechafin" validate:"despues=Fecha"`
+//	PersonaID uint   `json:"persona_id" validate:"required,existe=persona"`
+//
+// Un campo vacío solo se revisa con required y required_without: el resto de reglas valen
+// para los campos opcionales que sí se envían. Las reglas existe se revisan al final y solo si
+// lo demás es válido, para no consultar la base de datos con datos mal formados. Los nombres de
+// los campos en los errores son los de la etiqueta json. Los handlers llaman a Verificar al
+// crearse, para que una regla mal escrita o una referencia sin registrar fallen al iniciar.
+package validacion
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"ApiEscuela/errores"
+)
+
+// CodigoValidacion es el código de error de los datos que no cumplen las reglas
+const CodigoValidacion = "validation_error"
+
+// MensajeValidacion es el mensaje de error de los datos que no cumplen las reglas
+const MensajeValidacion = "Los datos proporcionados no son válidos"
+
+// Referencia comprueba la existencia de los registros de una regla existe=<nombre>
+type Referencia struct {
+	// Codigo es el código de error si no existe, p. ej. "persona_no_existe"
+	Codigo string
+	// Mensaje es el mensaje de error si no existe
+	Mensaje string
+	// Existe consulta el registro; su error (p. ej. la base de datos no responde) no es culpa de
+	// los datos enviados y Validar lo devuelve traducido con errores.DesdeBD
+	Existe func(id uint) (bool, error)
+}
+
+// Validador valida estructuras con la etiqueta validate. El validador sin referencias
+// (New()) sirve para las estructuras que no usan la regla existe.
+type Validador struct {
+	referencias map[string]Referencia
+}
+
+// New crea un validador
+func New() *Validador {
+	return &Validador{referencias: map[string]Referencia{}}
+}
+
+// ConReferencia registra la comprobación de existencia de la regla existe=<nombre>
+func (v *Validador) ConReferencia(nombre string, r Referencia) *Validador {
+	v.referencias[nombre] = r
+	return v
+}
+
+var predeterminado = New()
+
+// Validar valida dto con el validador sin referencias
+func Validar(dto interface{}) error {
+	return predeterminado.Validar(dto)
+}
+
+// Validar revisa dto (una estructura o un puntero a ella) y devuelve nil o un *errores.Error de
+// validación con los campos que fallaron. Si solo falla una referencia, el error lleva el código
+// de la referencia. Los errores de programación (dto que no es una estructura, regla mal escrita o
+// referencia sin registrar) se devuelven como errores comunes, que el manejador de errores
+// responde con 500; Verificar los detecta al iniciar. Si una referencia no se puede consultar se
+// devuelve el error de la consulta, no uno de validación.
+func (v *Validador) Validar(dto interface{}) error {
+	valor, reglas, err := v.reglas(dto)
+	if err != nil {
+		return err
+	}
+	if campos := revisarCampos(valor, reglas); len(campos) > 0 {
+		return errores.Validacion(CodigoValidacion, MensajeValidacion, campos...)
+	}
+
+	for _, c := range reglas {
+		for _, r := range c.reglas {
+			if r.nombre != "existe" {
+				continue
+			}
+			referencia := v.referencias[r.param]
+			id, ok := identificador(valor.FieldByIndex(c.indice))
+			if !ok {
+				continue
+			}
+			existe, err := referencia.Existe(id)
+			if err != nil {
+				return errores.DesdeBD(err)
+			}
+			if existe {
+				continue
+			}
+			return errores.Validacion(referencia.Codigo, referencia.Mensaje, errores.Campo{
+				Campo:   c.nombre,
+				Mensaje: referencia.Mensaje,
+				Valor:   fmt.Sprint(id),
+			})
+		}
+	}
+	return nil
+}
+
+// Campos devuelve los campos de dto que no cumplen sus reglas, sin revisar las referencias
+func (v *Validador) Campos(dto interface{}) ([]errores.Campo, error) {
+	valor, reglas, err := v.reglas(dto)
+	if err != nil {
+		return nil, err
+	}
+	return revisarCampos(valor, reglas), nil
+}
+
+// Verificar comprueba que los dto son estructuras con reglas bien escritas y que sus reglas
+// existe tienen referencia registrada, para que esos errores fallen al iniciar y no al validar
+func (v *Validador) Verificar(dtos ...interface{}) error {
+	for _, dto := range dtos {
+		if _, _, err := v.reglas(dto); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reglas devuelve la estructura de dto y sus reglas, o el error de programación que impide validarla
+func (v *Validador) reglas(dto interface{}) (reflect.Value, []campoConReglas, error) {
+	valor := indirecto(reflect.ValueOf(dto))
+	if valor.Kind() != reflect.Struct {
+		return valor, nil, fmt.Errorf("validacion: se esperaba una estructura, se recibió %s", valor.Kind())
+	}
+	reglas, err := reglasDe(valor.Type())
+	if err != nil {
+		return valor, nil, err
+	}
+	for _, c := range reglas {
+		for _, r := range c.reglas {
+			if _, ok := v.referencias[r.param]; r.nombre == "existe" && !ok {
+				return valor, nil, fmt.Errorf("validacion: %s.%s: referencia %q sin registrar", valor.Type().Name(), c.nombre, r.param)
+			}
+		}
+	}
+	return valor, reglas, nil
+}
+
+// revisarCampos aplica las reglas, salvo existe, a los campos de la estructura
+func revisarCampos(valor reflect.Value, reglas []campoConReglas) []errores.Campo {
+	var campos []errores.Campo
+	for _, c := range reglas {
+		actual := valor.FieldByIndex(c.indice)
+		for _, r := range c.reglas {
+			if r.nombre == "existe" {
+				continue
+			}
+			mensaje := r.revisar(valor, actual, c.nombre)
+			if mensaje == "" {
+				continue
+			}
+			campo := errores.Campo{Campo: c.nombre, Mensaje: mensaje}
+			if !c.secreto {
+				campo.Valor = texto(actual)
+			}
+			campos = append(campos, campo)
+			break // un error por campo
+		}
+	}
+	return campos
+}
+
+// campoConReglas son las reglas de un campo de la estructura
+type campoConReglas struct {
+	indice  []int
+	nombre  string
+	secreto bool // no repetir el valor en el error (contraseñas)
+	reglas  []regla
+}
+
+// reglasTipo es el resultado guardado de interpretar las etiquetas de un tipo
+type reglasTipo struct {
+	campos []campoConReglas
+	err    error
+}
+
+var cache sync.Map // reflect.Type -> reglasTipo
+
+// reglasDe interpreta (una sola vez por tipo) las etiquetas validate de la estructura
+func reglasDe(t reflect.Type) ([]campoConReglas, error) {
+	if guardadas, ok := cache.Load(t); ok {
+		r := guardadas.(reglasTipo)
+		return r.campos, r.err
+	}
+	var campos []campoConReglas
+	err := recorrer(t, nil, &campos)
+	cache.Store(t, reglasTipo{campos: campos, err: err})
+	return campos, err
+}
+
+func recorrer(t reflect.Type, prefijo []int, campos *[]campoConReglas) error {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		indice := append(append([]int{}, prefijo...), i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			if err := recorrer(f.Type, indice, campos); err != nil {
+				return err
+			}
+			continue
+		}
+		etiqueta := f.Tag.Get("validate")
+		if etiqueta == "" || etiqueta == "-" || !f.IsExported() {
+			continue
+		}
+		c := campoConReglas{indice: indice, nombre: nombreJSON(f)}
+		for _, parte := range strings.Split(etiqueta, ",") {
+			nombre, param, _ := strings.Cut(strings.TrimSpace(parte), "=")
+			if nombre == "secreto" {
+				c.secreto = true
+				continue
+			}
+			r := regla{nombre: nombre, param: param}
+			if err := r.preparar(t, f); err != nil {
+				return err
+			}
+			c.reglas = append(c.reglas, r)
+		}
+		*campos = append(*campos, c)
+	}
+	return nil
+}
+
+// nombreJSON es el nombre del campo en la etiqueta json, o el nombre Go si no tiene
+func nombreJSON(f reflect.StructField) string {
+	nombre, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if nombre == "" || nombre == "-" {
+		return f.Name
+	}
+	return nombre
+}
+
+// indirecto sigue los punteros hasta el valor
+func indirecto(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return v
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+var tipoTiempo = reflect.TypeOf(time.Time{})
+
+// vacio indica si el campo no se envió: nil, cadena en blanco, cero o colección vacía
+func vacio(v reflect.Value) bool {
+	v = indirecto(v)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Invalid:
+		return true
+	case reflect.String:
+		return strings.TrimSpace(v.String()) == ""
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return v.Len() == 0
+	case reflect.Struct:
+		if v.Type() == tipoTiempo {
+			return v.Interface().(time.Time).IsZero()
+		}
+		return false
+	}
+	return v.IsZero()
+}
+
+// texto representa el valor del campo en el error
+func texto(v reflect.Value) string {
+	v = indirecto(v)
+	switch {
+	case v.Kind() == reflect.Invalid || v.Kind() == reflect.Ptr:
+		return ""
+	case v.Type() == tipoTiempo:
+		if t := v.Interface().(time.Time); !t.IsZero() {
+			return t.Format(time.RFC3339)
+		}
+		return ""
+	case v.Kind() == reflect.String:
+		return v.String()
+	case v.Kind() == reflect.Slice || v.Kind() == reflect.Map || v.Kind() == reflect.Struct:
+		return ""
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// identificador lee el ID de un campo uint (o *uint) para las reglas existe
+func identificador(v reflect.Value) (uint, bool) {
+	v = indirecto(v)
+	switch v.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if v.Uint() == 0 {
+			return 0, false
+		}
+		return uint(v.Uint()), true
+	}
+	return 0, false
+}
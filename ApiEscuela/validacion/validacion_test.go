@@ -0,0 +1,174 @@
+package validacion_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"ApiEscuela/errores"
+	"ApiEscuela/validacion"
+)
+
+type contacto struct {
+	Nombre   string  `json:"nombre" validate:"required,min=2,max=10"`
+	Cedula   string  `json:"cedula" validate:"cedula"`
+	Correo   *string `json:"correo" validate:"required_without=Telefono,email"`
+	Telefono string  `json:"telefono" validate:"telefono"`
+	Estado   string  `json:"estado" validate:"oneof=activo inactivo"`
+	Clave    string  `json:"clave" validate:"sin_espacios,secreto"`
+}
+
+type visita struct {
+	Inicio     time.Time `json:"inicio" validate:"required"`
+	Fin        time.Time `json:"fin" validate:"despues=Inicio"`
+	Expira     time.Time `json:"expira" validate:"ventana=-1h..24h"`
+	PersonaID  uint      `json:"persona_id" validate:"required,existe=persona"`
+	Secundaria *uint     `json:"secundaria_id" validate:"existe=persona"`
+}
+
+func TestCampos(t *testing.T) {
+	correo := "ana@example.com"
+	malo := "no-es-correo"
+	tests := []struct {
+		name      string
+		dto       contacto
+		wantCampo string
+	}{
+		{name: "válido", dto: contacto{Nombre: "Ana", Correo: &correo, Cedula: "1200000006", Telefono: "+593 99 123 4567"}},
+		{name: "nombre requerido", dto: contacto{Nombre: "  ", Correo: &correo}, wantCampo: "nombre"},
+		{name: "nombre largo", dto: contacto{Nombre: "Ana María José", Correo: &correo}, wantCampo: "nombre"},
+		{name: "sin correo ni teléfono", dto: contacto{Nombre: "Ana"}, wantCampo: "correo"},
+		{name: "solo teléfono", dto: contacto{Nombre: "Ana", Telefono: "02-2345678"}},
+		{name: "correo inválido", dto: contacto{Nombre: "Ana", Correo: &malo}, wantCampo: "correo"},
+		{name: "cédula inválida", dto: contacto{Nombre: "Ana", Correo: &correo, Cedula: "1200000001"}, wantCampo: "cedula"},
+		{name: "teléfono inválido", dto: contacto{Nombre: "Ana", Correo: &correo, Telefono: "0812345678"}, wantCampo: "telefono"},
+		{name: "estado desconocido", dto: contacto{Nombre: "Ana", Correo: &correo, Estado: "borrado"}, wantCampo: "estado"},
+		{name: "clave con espacios", dto: contacto{Nombre: "Ana", Correo: &correo, Clave: "con espacio"}, wantCampo: "clave"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			campos, err := validacion.New().Campos(&tt.dto)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantCampo == "" {
+				if len(campos) != 0 {
+					t.Fatalf("campos = %+v, no se esperaban errores", campos)
+				}
+				return
+			}
+			if len(campos) != 1 || campos[0].Campo != tt.wantCampo {
+				t.Fatalf("campos = %+v, se esperaba un error en %s", campos, tt.wantCampo)
+			}
+			if tt.wantCampo == "clave" && campos[0].Valor != "" {
+				t.Errorf("el valor de un campo secreto no debe aparecer en el error: %q", campos[0].Valor)
+			}
+		})
+	}
+}
+
+func TestValidarFechasYReferencias(t *testing.T) {
+	existentes := map[uint]bool{1: true}
+	v := validacion.New().ConReferencia("persona", validacion.Referencia{
+		Codigo:  "persona_no_existe",
+		Mensaje: "No se encontró la persona",
+		Existe:  func(id uint) (bool, error) { return existentes[id], nil },
+	})
+	ahora := time.Now()
+	dos := uint(2)
+
+	tests := []struct {
+		name       string
+		dto        visita
+		wantCodigo string
+	}{
+		{name: "válida", dto: visita{Inicio: ahora, Fin: ahora.Add(time.Hour), Expira: ahora.Add(time.Hour), PersonaID: 1}},
+		{name: "fin antes del inicio", dto: visita{Inicio: ahora, Fin: ahora.Add(-time.Hour), PersonaID: 1}, wantCodigo: validacion.CodigoValidacion},
+		{name: "fuera de la ventana", dto: visita{Inicio: ahora, Expira: ahora.Add(48 * time.Hour), PersonaID: 1}, wantCodigo: validacion.CodigoValidacion},
+		{name: "persona inexistente", dto: visita{Inicio: ahora, PersonaID: 9}, wantCodigo: "persona_no_existe"},
+		{name: "referencia opcional inexistente", dto: visita{Inicio: ahora, PersonaID: 1, Secundaria: &dos}, wantCodigo: "persona_no_existe"},
+		{name: "formato antes que referencias", dto: visita{PersonaID: 9}, wantCodigo: validacion.CodigoValidacion},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := v.Validar(tt.dto)
+			if tt.wantCodigo == "" {
+				if err != nil {
+					t.Fatalf("Validar = %v", err)
+				}
+				return
+			}
+			e, ok := errores.Como(err)
+			if !ok || e.Codigo != tt.wantCodigo || e.Tipo != errores.TipoValidacion {
+				t.Fatalf("Validar = %v, se esperaba el código %s", err, tt.wantCodigo)
+			}
+		})
+	}
+}
+
+func TestReferenciaQueNoSePuedeConsultar(t *testing.T) {
+	caida := errors.New("conexión rechazada")
+	v := validacion.New().ConReferencia("persona", validacion.Referencia{
+		Codigo:  "persona_no_existe",
+		Mensaje: "No se encontró la persona",
+		Existe:  func(id uint) (bool, error) { return false, caida },
+	})
+	// La base de datos caída no es un error de los datos: no debe responderse como 400
+	err := v.Validar(visita{Inicio: time.Now(), PersonaID: 1})
+	if !errors.Is(err, caida) {
+		t.Fatalf("Validar = %v, se esperaba el error de la consulta", err)
+	}
+	if e, ok := errores.Como(err); ok && e.Tipo == errores.TipoValidacion {
+		t.Errorf("Validar = %+v, no debe ser un error de validación", e)
+	}
+}
+
+func TestErroresDeProgramacion(t *testing.T) {
+	var regla struct {
+		Edad int `validate:"min=diez"`
+	}
+	sinRegistrar := visita{Inicio: time.Now(), PersonaID: 1}
+	tests := []struct {
+		name string
+		dto  interface{}
+	}{
+		{name: "regla mal escrita", dto: &regla},
+		{name: "referencia sin registrar", dto: &sinRegistrar},
+		{name: "no es una estructura", dto: "texto"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Se detecta al iniciar y, si llega a una petición, es un error común y no de validación
+			if err := validacion.New().Verificar(tt.dto); err == nil {
+				t.Error("Verificar no detectó el error")
+			}
+			err := validacion.Validar(tt.dto)
+			if _, ok := errores.Como(err); err == nil || ok {
+				t.Errorf("Validar = %v, se esperaba un error común", err)
+			}
+		})
+	}
+}
+
+func TestCedulaYTelefono(t *testing.T) {
+	for _, c := range []string{"1200000006", "1710034065", "3000000004"} {
+		if !validacion.CedulaValida(c) {
+			t.Errorf("CedulaValida(%s) = false", c)
+		}
+	}
+	for _, c := range []string{"1200000001", "2500000000", "1260000000", "120000000", "12000000AB"} {
+		if validacion.CedulaValida(c) {
+			t.Errorf("CedulaValida(%s) = true", c)
+		}
+	}
+	for _, tel := range []string{"0991234567", "+593991234567", "593 99 123 4567", "(02) 234-5678"} {
+		if !validacion.TelefonoValido(tel) {
+			t.Errorf("TelefonoValido(%s) = false", tel)
+		}
+	}
+	for _, tel := range []string{"991234567", "0812345678", "0991234", "+1 555 123 4567"} {
+		if validacion.TelefonoValido(tel) {
+			t.Errorf("TelefonoValido(%s) = true", tel)
+		}
+	}
+}